@@ -0,0 +1,83 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ReconcileRequestCompleted is the phase of a ReconcileRequest whose Secrets were triggered
+	ReconcileRequestCompleted = "Completed"
+	// ReconcileRequestFailed is the phase of a ReconcileRequest that cannot be handled
+	ReconcileRequestFailed = "Failed"
+)
+
+// ReconcileRequestSpec selects the Secrets to reconcile
+type ReconcileRequestSpec struct {
+	// SecretName is the name of the Secret to reconcile, in the namespace of the ReconcileRequest.
+	// If empty, all Secrets with operator annotations in the namespace are reconciled.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// ReconcileRequestStatus is the observed state of a ReconcileRequest
+type ReconcileRequestStatus struct {
+	// Phase is Completed once the Secrets were triggered, or Failed if the request cannot be handled
+	// +optional
+	Phase string `json:"phase,omitempty"`
+	// Secrets is the number of Secrets that were triggered
+	// +optional
+	Secrets int32 `json:"secrets,omitempty"`
+	// Message describes why the request failed
+	// +optional
+	Message string `json:"message,omitempty"`
+	// CompletionTime is the time the request was handled
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Secret",type=string,JSONPath=`.spec.secretName`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Secrets",type=integer,JSONPath=`.status.secrets`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ReconcileRequest asks the operator to reconcile a Secret, or all Secrets of its namespace, right
+// away instead of waiting for the next change or resync. Each ReconcileRequest is handled once;
+// create a new one to trigger another reconciliation.
+type ReconcileRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ReconcileRequestSpec   `json:"spec,omitempty"`
+	Status ReconcileRequestStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ReconcileRequestList contains a list of ReconcileRequests
+type ReconcileRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ReconcileRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ReconcileRequest{}, &ReconcileRequestList{})
+}
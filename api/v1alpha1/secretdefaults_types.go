@@ -0,0 +1,77 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SecretDefaultsSpec selects Secrets and the operator annotations applied to them
+type SecretDefaultsSpec struct {
+	// Selector selects the Secrets in the namespace of the SecretDefaults.
+	// An empty selector selects all Secrets.
+	// +optional
+	Selector metav1.LabelSelector `json:"selector,omitempty"`
+	// Annotations are the operator annotations (iso.gtrfc.com/...) applied to the selected
+	// Secrets. Annotations a Secret already sets itself are not overwritten.
+	Annotations map[string]string `json:"annotations"`
+}
+
+// SecretDefaultsStatus is the observed state of a SecretDefaults
+type SecretDefaultsStatus struct {
+	// ObservedGeneration is the generation of the SecretDefaults that was last applied
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// MatchedSecrets is the number of Secrets the selector matched when last applied
+	// +optional
+	MatchedSecrets int32 `json:"matchedSecrets,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Matched",type=integer,JSONPath=`.status.matchedSecrets`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// SecretDefaults applies operator annotations to the Secrets of its namespace that match a label
+// selector, e.g. to roll out rotation intervals to existing Secrets. The annotations are applied
+// with server-side apply, so removing them from the SecretDefaults removes them from the Secrets.
+type SecretDefaults struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SecretDefaultsSpec   `json:"spec"`
+	Status SecretDefaultsStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SecretDefaultsList contains a list of SecretDefaults
+type SecretDefaultsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SecretDefaults `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SecretDefaults{}, &SecretDefaultsList{})
+}
+
+// FieldManager returns the server-side apply field manager of the SecretDefaults. Each
+// SecretDefaults owns the annotations it applies, so they can be removed independently.
+func (d *SecretDefaults) FieldManager() string {
+	return "secret-defaults/" + d.Name
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SecretSourceKind is the kind of SecretSource, used as prefix in replicate-from references
+// ("SecretSource/<name>")
+const SecretSourceKind = "SecretSource"
+
+// SecretReference references a Secret in a namespace
+type SecretReference struct {
+	// Namespace of the Secret
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+	// Name of the Secret
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// SecretSourceSpec defines the backing Secret of a SecretSource and who may replicate from it
+type SecretSourceSpec struct {
+	// SecretRef references the Secret whose data is replicated
+	SecretRef SecretReference `json:"secretRef"`
+	// ReplicatableFromNamespaces lists the namespaces (glob patterns) allowed to replicate from
+	// this source, like the replicatable-from-namespaces annotation
+	// +optional
+	ReplicatableFromNamespaces []string `json:"replicatableFromNamespaces,omitempty"`
+	// ReplicatableFromSelector is a label selector for the namespaces allowed to replicate from
+	// this source, like the replicatable-from-selector annotation
+	// +optional
+	ReplicatableFromSelector string `json:"replicatableFromSelector,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Namespace",type=string,JSONPath=`.spec.secretRef.namespace`
+// +kubebuilder:printcolumn:name="Secret",type=string,JSONPath=`.spec.secretRef.name`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// SecretSource is a cluster-wide name for a Secret that other namespaces replicate from.
+// Targets reference it as "SecretSource/<name>" in replicate-from, so the backing Secret can be
+// moved without changing the annotations of every consumer.
+type SecretSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec SecretSourceSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// SecretSourceList contains a list of SecretSources
+type SecretSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SecretSource `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SecretSource{}, &SecretSourceList{})
+}
+
+// Reference returns the replicate-from reference of the SecretSource ("SecretSource/<name>")
+func (s *SecretSource) Reference() string {
+	return SecretSourceKind + "/" + s.Name
+}
+
+// SecretSourceName returns the name of the SecretSource a replicate-from reference points to.
+// It returns false for references to Secrets ("namespace/name"); namespace names are lowercase,
+// so they never collide with the kind.
+func SecretSourceName(sourceRef string) (string, bool) {
+	name, ok := strings.CutPrefix(strings.TrimSpace(sourceRef), SecretSourceKind+"/")
+	return strings.TrimSpace(name), ok
+}
@@ -17,19 +17,38 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"flag"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	webhookserver "sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	isov1alpha1 "github.com/guided-traffic/internal-secrets-operator/api/v1alpha1"
 	"github.com/guided-traffic/internal-secrets-operator/internal/controller"
+	"github.com/guided-traffic/internal-secrets-operator/internal/metricsauth"
+	"github.com/guided-traffic/internal-secrets-operator/internal/webhook"
 	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
 	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
 )
@@ -42,13 +61,49 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(corev1.AddToScheme(scheme))
+	utilruntime.Must(isov1alpha1.AddToScheme(scheme))
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == migrateAnnotationsCommand {
+		if err := runMigrateAnnotations(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == previewCommand {
+		if err := runPreview(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == statusCommand {
+		if err := runStatus(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	started := time.Now()
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
 	var configPath string
+	var webhookPort int
+	var webhookCertDir string
+	var webhookCertSecret string
+	var webhookService string
+	var webhookConfiguration string
+	var secureMetrics bool
+	var metricsCertDir string
+	var metricsAuth bool
+	var metricsClientCA string
+	var debugEndpoint bool
+	var shardIndex int
+	var controllers string
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -56,6 +111,39 @@ func main() {
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.StringVar(&configPath, "config", config.DefaultConfigPath, "Path to the configuration file.")
+	flag.IntVar(&webhookPort, "webhook-port", 9443, "The port the validating webhook server binds to.")
+	flag.StringVar(&webhookCertDir, "webhook-cert-dir", "",
+		"Directory containing the webhook serving certificate (tls.crt, tls.key). "+
+			"Defaults to the controller-runtime default directory.")
+	flag.StringVar(&webhookCertSecret, "webhook-cert-secret", "",
+		"Secret (in the POD_NAMESPACE namespace) holding a self-signed webhook serving certificate that the "+
+			"operator creates, renews and injects into the webhook configuration. If empty, the certificate "+
+			"in --webhook-cert-dir is provided externally (e.g. by cert-manager).")
+	flag.StringVar(&webhookService, "webhook-service", "",
+		"Name of the webhook Service; the self-signed certificate is issued for its DNS names.")
+	flag.StringVar(&webhookConfiguration, "webhook-configuration", "",
+		"Name of the ValidatingWebhookConfiguration that receives the CA bundle of the self-signed certificate.")
+	flag.BoolVar(&secureMetrics, "metrics-secure", false, "Serve the metrics endpoint via HTTPS.")
+	flag.StringVar(&metricsCertDir, "metrics-cert-dir", "",
+		"Directory containing the metrics serving certificate (tls.crt, tls.key). "+
+			"A self-signed certificate is generated if empty.")
+	flag.BoolVar(&metricsAuth, "metrics-auth", false,
+		"Require a bearer token authorized via TokenReview and SubjectAccessReview for the metrics endpoint. "+
+			"Requires --metrics-secure.")
+	flag.BoolVar(&debugEndpoint, "debug-endpoint", false,
+		"Serve the rotation state of tracked Secrets, the workqueue depth and the replication flows on "+
+			controller.DebugSecretsPath+" and a cluster-wide summary on "+controller.DebugStatusPath+
+			" of the metrics endpoint. Requires --metrics-auth.")
+	flag.StringVar(&metricsClientCA, "metrics-client-ca", "",
+		"PEM file with CAs that client certificates for the metrics endpoint must be signed by. "+
+			"Requires --metrics-secure.")
+
+	flag.StringVar(&controllers, "controllers", "",
+		"Comma-separated controllers to run in this process: "+controllerGenerator+", "+controllerReplicator+". "+
+			"Defaults to all controllers enabled in the configuration. Each set of controllers elects its own leader.")
+	flag.IntVar(&shardIndex, "shard-index", -1,
+		"Shard owned by this replica if sharding is enabled. Defaults to the SHARD_INDEX environment "+
+			"variable or the StatefulSet ordinal of the hostname.")
 
 	opts := zap.Options{
 		Development: true,
@@ -73,48 +161,278 @@ func main() {
 	}
 	setupLog.Info("Configuration loaded", "path", configPath, "defaults", cfg.Defaults)
 
+	metricsOpts, err := metricsServerOptions(metricsAddr, secureMetrics, metricsCertDir, metricsAuth, metricsClientCA)
+	if err != nil {
+		setupLog.Error(err, "invalid metrics endpoint configuration")
+		os.Exit(1)
+	}
+	// The debug endpoint lists Secret names and must not be served unauthenticated
+	if debugEndpoint && !metricsAuth {
+		setupLog.Error(errors.New("--debug-endpoint requires --metrics-auth"), "invalid metrics endpoint configuration")
+		os.Exit(1)
+	}
+
+	runControllers, err := parseControllers(controllers)
+	if err != nil {
+		setupLog.Error(err, "invalid --controllers")
+		os.Exit(1)
+	}
+
+	// With sharding, every replica reconciles the namespaces of its shard and is elected
+	// leader of that shard only, so the replicas of different shards run side by side
+	leaderElectionID := leaderElectionIDFor("secret-operator.guided-traffic.com", runControllers)
+	var shard *controller.Shard
+	if cfg.Sharding.Enabled() {
+		index, err := resolveShardIndex(shardIndex, cfg.Sharding.Shards)
+		if err != nil {
+			setupLog.Error(err, "invalid sharding configuration")
+			os.Exit(1)
+		}
+		shard = &controller.Shard{Config: cfg.Sharding, Index: index}
+		leaderElectionID = fmt.Sprintf("%s-shard-%d", leaderElectionID, index)
+		setupLog.Info("Sharding enabled", "shard", index, "shards", cfg.Sharding.Shards, "mode", cfg.Sharding.Mode)
+	}
+
+	if webhookCertSecret != "" {
+		if webhookService == "" || webhookConfiguration == "" || os.Getenv("POD_NAMESPACE") == "" {
+			setupLog.Error(errors.New("--webhook-cert-secret requires --webhook-service, --webhook-configuration "+
+				"and the POD_NAMESPACE environment variable"), "invalid webhook configuration")
+			os.Exit(1)
+		}
+		if webhookCertDir == "" {
+			webhookCertDir = filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs")
+		}
+	}
+
+	// With label-based opt-in, only Secrets carrying the opt-in label are cached.
+	// This reduces the watch footprint to the Secrets the operator actually manages.
+	cacheOpts := cache.Options{}
+	if cfg.Cache.ResyncPeriod > 0 {
+		cacheOpts.SyncPeriod = ptr.To(cfg.Cache.ResyncPeriod.Duration())
+		setupLog.Info("Cache resync period configured", "period", cfg.Cache.ResyncPeriod.Duration())
+	}
+	if cfg.ManagedLabel.Enabled {
+		cacheOpts.ByObject = map[client.Object]cache.ByObject{
+			&corev1.Secret{}: {Label: cfg.ManagedLabel.Selector()},
+		}
+		setupLog.Info("Label-based opt-in enabled", "label", cfg.ManagedLabel.Key, "value", cfg.ManagedLabel.Value)
+	}
+	legacyAnnotations := controller.NewLegacyAnnotations(cfg.LegacyAnnotationPrefix)
+	if legacyAnnotations != nil {
+		cacheOpts.DefaultTransform = legacyAnnotations.Transform()
+		setupLog.Info("Legacy annotation prefix enabled", "prefix", cfg.LegacyAnnotationPrefix)
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme: scheme,
-		Metrics: metricsserver.Options{
-			BindAddress: metricsAddr,
-		},
+		Scheme:    scheme,
+		Cache:     cacheOpts,
+		NewClient: legacyAnnotations.NewClient,
+		Metrics:   metricsOpts,
+		WebhookServer: webhookserver.NewServer(webhookserver.Options{
+			Port:    webhookPort,
+			CertDir: webhookCertDir,
+		}),
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "secret-operator.guided-traffic.com",
+		LeaderElectionID:       leaderElectionID,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
+	if shard != nil {
+		shard.Reader = mgr.GetAPIReader()
+	}
+
+	// eventRecorderFor returns the Event recorder of a controller. The event limits are shared
+	// by all controllers. With the activity log enabled, all Events (including those dropped by
+	// the event limits) are also written to stdout as JSON lines.
+	eventLimiter := controller.NewEventLimiter(cfg.Events.MaxPerHour, cfg.Events.MaxPerSecretPerHour)
+	eventRecorderFor := func(name string) record.EventRecorder {
+		recorder, err := controller.NewLimitedEventRecorder(mgr.GetEventRecorderFor(name), eventLimiter)
+		if err != nil {
+			setupLog.Error(err, "unable to set up event limits")
+			os.Exit(1)
+		}
+		if cfg.ActivityLog.Enabled {
+			recorder = controller.NewActivityRecorder(recorder, name, os.Stdout)
+		}
+		return recorder
+	}
+
+	// The heartbeat records completed reconciles for the liveness check
+	heartbeat := controller.NewHeartbeat()
 
-	// Create the value generator with the configured charset
+	// The generator and the replicator never reconcile the same Secret at the same time
+	secretLocks := controller.NewSecretLocks()
+
+	// Create the value generator with the configured charset and random source. The source
+	// is self-tested once, so a broken HSM or KMS connection stops the operator at startup.
+	randomSource, err := generator.NewRandomSource(cfg.Randomness.Source)
+	if err != nil {
+		setupLog.Error(err, "unable to create random source")
+		os.Exit(1)
+	}
+	if err := generator.SelfTest(randomSource); err != nil {
+		setupLog.Error(err, "random source failed the self-test")
+		os.Exit(1)
+	}
+	if err := controller.RegisterRandomSourceMetric(randomSource.Name()); err != nil {
+		setupLog.Error(err, "unable to register random source metric")
+		os.Exit(1)
+	}
+	setupLog.Info("Random source ready", "source", randomSource.Name())
 	charset := cfg.Defaults.String.BuildCharset()
-	gen := generator.NewSecretGeneratorWithCharset(charset)
+	gen := generator.NewSecretGeneratorWithSource(charset, randomSource)
+
+	// Resync checkpoints of the controllers, the startup report waits for the Secrets they defer
+	var resyncCheckpoints []*controller.ResyncCheckpoint
 
 	// Set up the Secret Generator controller (if enabled)
-	if cfg.Features.SecretGenerator {
+	if cfg.Features.SecretGenerator && runControllers[controllerGenerator] {
+		// After a failover, the new leader reconciles the Secrets that changed since the
+		// checkpoint of its predecessor first (if enabled)
+		checkpoint, err := resyncCheckpointFor(mgr, cfg, leaderElectionID+"-checkpoint")
+		if err != nil {
+			setupLog.Error(err, "unable to set up resync checkpoint")
+			os.Exit(1)
+		}
+		resyncCheckpoints = append(resyncCheckpoints, checkpoint)
+
 		if err = (&controller.SecretReconciler{
 			Client:        mgr.GetClient(),
 			Scheme:        mgr.GetScheme(),
 			Generator:     gen,
 			Config:        cfg,
-			EventRecorder: mgr.GetEventRecorderFor("secret-operator"),
+			EventRecorder: eventRecorderFor("secret-operator"),
+			RotationLimiter: controller.NewRotationLimiter(
+				cfg.Rotation.MaxConcurrent, cfg.Rotation.MaxConcurrentPerNamespace),
+			CatchUp:    controller.NewRotationCatchUp(cfg.Rotation.CatchUpWindow.Duration()),
+			Heartbeat:  heartbeat,
+			Shard:      shard,
+			Locks:      secretLocks,
+			Checkpoint: checkpoint,
 		}).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "SecretGenerator")
 			os.Exit(1)
 		}
 		setupLog.Info("Secret Generator controller enabled")
+
+		// Workloads can request a rotation of the Secrets they consume
+		if err = (&controller.RotationRequestReconciler{
+			Client:        mgr.GetClient(),
+			Scheme:        mgr.GetScheme(),
+			EventRecorder: eventRecorderFor("secret-operator"),
+			Heartbeat:     heartbeat,
+			Shard:         shard,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "RotationRequest")
+			os.Exit(1)
+		}
+
+		// Namespaces can request predefined Secrets, which the generator then fills in
+		if len(cfg.Bootstrap) > 0 {
+			if err = (&controller.BootstrapReconciler{
+				Client:        mgr.GetClient(),
+				Config:        cfg,
+				EventRecorder: eventRecorderFor("secret-operator"),
+				Heartbeat:     heartbeat,
+				Shard:         shard,
+			}).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "Bootstrap")
+				os.Exit(1)
+			}
+			setupLog.Info("Namespace bootstrap enabled", "secrets", len(cfg.Bootstrap))
+		}
 	} else {
 		setupLog.Info("Secret Generator controller disabled")
 	}
 
+	// Set up the ServiceAccount token controller (if enabled). It runs with the generator.
+	if cfg.Features.ServiceAccountTokens && runControllers[controllerGenerator] {
+		if err = (&controller.ServiceAccountTokenReconciler{
+			Client:        mgr.GetClient(),
+			Scheme:        mgr.GetScheme(),
+			Config:        cfg,
+			EventRecorder: eventRecorderFor("secret-operator"),
+			Heartbeat:     heartbeat,
+			Shard:         shard,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ServiceAccountToken")
+			os.Exit(1)
+		}
+		setupLog.Info("ServiceAccount token controller enabled")
+	}
+
+	// Set up the ConfigMap ID controller (if enabled). It runs with the generator.
+	if cfg.Features.ConfigMapIDs && runControllers[controllerGenerator] {
+		if err = (&controller.ConfigMapIDReconciler{
+			Client:        mgr.GetClient(),
+			Generator:     gen,
+			Config:        cfg,
+			EventRecorder: eventRecorderFor("secret-operator"),
+			Heartbeat:     heartbeat,
+			Shard:         shard,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ConfigMapID")
+			os.Exit(1)
+		}
+		setupLog.Info("ConfigMap ID controller enabled")
+	}
+
+	// Set up the SecretDefaults controller (if enabled). It runs with the generator.
+	if cfg.Features.SecretDefaults && runControllers[controllerGenerator] {
+		if err = (&controller.SecretDefaultsReconciler{
+			Client:        mgr.GetClient(),
+			Scheme:        mgr.GetScheme(),
+			Config:        cfg,
+			EventRecorder: eventRecorderFor("secret-operator"),
+			Heartbeat:     heartbeat,
+			Shard:         shard,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "SecretDefaults")
+			os.Exit(1)
+		}
+		setupLog.Info("SecretDefaults controller enabled")
+	}
+
+	// Set up the ReconcileRequest controller (if enabled). It runs with the generator.
+	if cfg.Features.ReconcileRequests && runControllers[controllerGenerator] {
+		if err = (&controller.ReconcileRequestReconciler{
+			Client:        mgr.GetClient(),
+			Scheme:        mgr.GetScheme(),
+			Config:        cfg,
+			EventRecorder: eventRecorderFor("secret-operator"),
+			Heartbeat:     heartbeat,
+			Shard:         shard,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ReconcileRequest")
+			os.Exit(1)
+		}
+		setupLog.Info("ReconcileRequest controller enabled")
+	}
+
 	// Set up the Secret Replicator controller (if enabled)
-	if cfg.Features.SecretReplicator {
+	var replicationFlows *controller.ReplicationFlows
+	if cfg.Features.SecretReplicator && runControllers[controllerReplicator] {
+		replicationFlows = controller.NewReplicationFlows(mgr.GetClient(), cfg.Metrics)
+		checkpoint, err := resyncCheckpointFor(mgr, cfg, leaderElectionID+"-replicator-checkpoint")
+		if err != nil {
+			setupLog.Error(err, "unable to set up resync checkpoint")
+			os.Exit(1)
+		}
+		resyncCheckpoints = append(resyncCheckpoints, checkpoint)
 		if err = (&controller.SecretReplicatorReconciler{
 			Client:        mgr.GetClient(),
 			Scheme:        mgr.GetScheme(),
 			Config:        cfg,
-			EventRecorder: mgr.GetEventRecorderFor("secret-replicator"),
+			EventRecorder: eventRecorderFor("secret-replicator"),
+			Heartbeat:     heartbeat,
+			Shard:         shard,
+			Locks:         secretLocks,
+			FanOutLimiter: controller.NewFanOutLimiter(cfg.Replication.SourceFanOutRate),
+			Flows:         replicationFlows,
+			Checkpoint:    checkpoint,
 		}).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "SecretReplicator")
 			os.Exit(1)
@@ -124,10 +442,129 @@ func main() {
 		setupLog.Info("Secret Replicator controller disabled")
 	}
 
+	// Set up the validating webhook for Secrets (if enabled)
+	if cfg.Features.ValidatingWebhook {
+		if webhookCertSecret != "" {
+			certManager := &webhook.CertificateManager{
+				Reader:                   mgr.GetAPIReader(),
+				Writer:                   mgr.GetClient(),
+				Namespace:                os.Getenv("POD_NAMESPACE"),
+				SecretName:               webhookCertSecret,
+				ServiceName:              webhookService,
+				WebhookConfigurationName: webhookConfiguration,
+				CertDir:                  webhookCertDir,
+			}
+			// The webhook server needs the certificate files when it starts
+			certCtx, cancel := context.WithTimeout(context.Background(), webhookCertificateTimeout)
+			err := certManager.WaitForCertificate(certCtx)
+			cancel()
+			if err != nil {
+				setupLog.Error(err, "unable to set up webhook serving certificate")
+				os.Exit(1)
+			}
+			if err := mgr.Add(certManager); err != nil {
+				setupLog.Error(err, "unable to set up webhook serving certificate")
+				os.Exit(1)
+			}
+			setupLog.Info("Self-managed webhook serving certificate enabled", "secret", webhookCertSecret)
+		}
+		if err = (&webhook.SecretValidator{Config: cfg, Reader: mgr.GetAPIReader()}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Secret")
+			os.Exit(1)
+		}
+		setupLog.Info("Validating webhook enabled")
+	}
+
+	// Set up the per-namespace inventory ConfigMaps (if enabled). It runs with the generator,
+	// so that split deployments do not write the inventory twice.
+	if cfg.Inventory.Enabled && runControllers[controllerGenerator] {
+		if err = (&controller.InventoryReporter{
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+			Config:    cfg,
+			Shard:     shard,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to set up inventory")
+			os.Exit(1)
+		}
+		setupLog.Info("Inventory enabled", "configMap", cfg.Inventory.ConfigMapName)
+	}
+
+	// Report deprecated, misspelled and conflicting annotations (if enabled)
+	if cfg.AnnotationHygiene.Enabled && runControllers[controllerGenerator] {
+		if err = (&controller.AnnotationHygieneReporter{
+			Client:        mgr.GetClient(),
+			Config:        cfg,
+			EventRecorder: eventRecorderFor("secret-operator"),
+			Shard:         shard,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to set up annotation hygiene reporter")
+			os.Exit(1)
+		}
+		setupLog.Info("Annotation hygiene reporter enabled", "interval", cfg.AnnotationHygiene.Interval.Duration())
+	}
+
+	// Serve the debug endpoint on the metrics server (if enabled)
+	if debugEndpoint {
+		if err := mgr.AddMetricsServerExtraHandler(controller.DebugSecretsPath, &controller.DebugHandler{
+			Reader: mgr.GetClient(),
+			Config: cfg,
+			Flows:  replicationFlows,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up debug endpoint")
+			os.Exit(1)
+		}
+		if err := mgr.AddMetricsServerExtraHandler(controller.DebugStatusPath, &controller.StatusHandler{
+			Reader: mgr.GetClient(),
+			Config: cfg,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up status endpoint")
+			os.Exit(1)
+		}
+		setupLog.Info("Debug endpoint enabled", "paths", []string{controller.DebugSecretsPath, controller.DebugStatusPath})
+	}
+
+	// Export the workqueue backpressure metrics and log when a controller falls behind
+	if err := (&controller.WorkqueueMonitor{Config: cfg}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to set up workqueue monitor")
+		os.Exit(1)
+	}
+
+	// Report the state of the Secrets at startup and the time until the initial resync was processed
+	if err := (&controller.StartupReport{
+		Reader:      mgr.GetClient(),
+		Config:      cfg,
+		Started:     started,
+		Checkpoints: resyncCheckpoints,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to set up startup report")
+		os.Exit(1)
+	}
+
+	// Check whether the API server encrypts Secrets at rest and warn if it does not
+	if cfg.EncryptionCheck.Enabled {
+		discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+		if err != nil {
+			setupLog.Error(err, "unable to create discovery client")
+			os.Exit(1)
+		}
+		if err := (&controller.EncryptionCheck{
+			Config: cfg,
+			Client: discoveryClient.RESTClient(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to set up encryption-at-rest check")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
+	if err := mgr.AddHealthzCheck("controllers", heartbeat.Checker(time.Duration(cfg.Health.StallTimeout))); err != nil {
+		setupLog.Error(err, "unable to set up controller health check")
+		os.Exit(1)
+	}
 	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
@@ -139,3 +576,129 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// metricsServerOptions builds the metrics server options from the command line flags
+func metricsServerOptions(addr string, secure bool, certDir string, auth bool, clientCA string) (metricsserver.Options, error) {
+	opts := metricsserver.Options{
+		BindAddress:   addr,
+		SecureServing: secure,
+		CertDir:       certDir,
+	}
+	if !secure {
+		if auth || clientCA != "" {
+			return opts, errors.New("--metrics-auth and --metrics-client-ca require --metrics-secure")
+		}
+		return opts, nil
+	}
+
+	if auth {
+		opts.FilterProvider = filters.WithAuthenticationAndAuthorization
+	}
+	if clientCA != "" {
+		tlsOpt, err := metricsauth.ClientCATLSOption(clientCA)
+		if err != nil {
+			return opts, err
+		}
+		opts.TLSOpts = []func(*tls.Config){tlsOpt}
+	}
+	return opts, nil
+}
+
+// webhookCertificateTimeout bounds the wait for the self-managed webhook serving certificate at startup
+const webhookCertificateTimeout = 2 * time.Minute
+
+// Controllers that can be selected with --controllers
+const (
+	controllerGenerator  = "generator"
+	controllerReplicator = "replicator"
+)
+
+// parseControllers returns the controllers selected with --controllers. An empty value selects all.
+func parseControllers(value string) (map[string]bool, error) {
+	selected := make(map[string]bool)
+	if strings.TrimSpace(value) == "" {
+		selected[controllerGenerator] = true
+		selected[controllerReplicator] = true
+		return selected, nil
+	}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		switch name {
+		case controllerGenerator, controllerReplicator:
+			selected[name] = true
+		case "":
+		default:
+			return nil, fmt.Errorf("unknown controller %q, must be %q or %q", name, controllerGenerator, controllerReplicator)
+		}
+	}
+	return selected, nil
+}
+
+// leaderElectionIDFor returns the leader election ID of a set of controllers. Processes that run
+// all controllers share the base ID; a process running a subset elects a leader of its own, so
+// split deployments neither block each other nor fail together.
+func leaderElectionIDFor(base string, controllers map[string]bool) string {
+	if controllers[controllerGenerator] && controllers[controllerReplicator] {
+		return base
+	}
+	for _, name := range []string{controllerGenerator, controllerReplicator} {
+		if controllers[name] {
+			return base + "-" + name
+		}
+	}
+	return base
+}
+
+// resyncCheckpointFor returns the resync checkpoint of a controller, kept in the named Lease next
+// to the leader election Lease. It returns nil if differential resync is disabled.
+func resyncCheckpointFor(mgr ctrl.Manager, cfg *config.Config, name string) (*controller.ResyncCheckpoint, error) {
+	if !cfg.DifferentialResync.Enabled {
+		return nil, nil
+	}
+	namespace := os.Getenv("POD_NAMESPACE")
+	if namespace == "" {
+		return nil, errors.New("differentialResync requires the POD_NAMESPACE environment variable")
+	}
+	checkpoint := &controller.ResyncCheckpoint{
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Key:       types.NamespacedName{Namespace: namespace, Name: name},
+		Interval:  cfg.DifferentialResync.CheckpointInterval.Duration(),
+		Spread:    cfg.DifferentialResync.Spread.Duration(),
+	}
+	if err := checkpoint.SetupWithManager(mgr); err != nil {
+		return nil, err
+	}
+	setupLog.Info("Differential resync enabled", "lease", checkpoint.Key.String())
+	return checkpoint, nil
+}
+
+// resolveShardIndex returns the shard of this replica from the --shard-index flag, the
+// SHARD_INDEX environment variable or the StatefulSet ordinal of the hostname
+func resolveShardIndex(flagIndex, shards int) (int, error) {
+	index := flagIndex
+	if index < 0 {
+		if value := os.Getenv("SHARD_INDEX"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				return 0, fmt.Errorf("invalid SHARD_INDEX %q: %w", value, err)
+			}
+			index = parsed
+		} else {
+			hostname, err := os.Hostname()
+			if err != nil {
+				return 0, fmt.Errorf("failed to get hostname: %w", err)
+			}
+			if index, err = controller.ShardIndexFromHostname(hostname); err != nil {
+				return 0, fmt.Errorf("set --shard-index or SHARD_INDEX: %w", err)
+			}
+		}
+	}
+	if index < 0 || index >= shards {
+		return 0, fmt.Errorf("shard index %d is out of range, must be between 0 and %d", index, shards-1)
+	}
+	return index, nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/guided-traffic/internal-secrets-operator/internal/migrate"
+)
+
+// migrateAnnotationsCommand is the subcommand that migrates third-party replicator annotations
+const migrateAnnotationsCommand = "migrate-annotations"
+
+// runMigrateAnnotations rewrites kubernetes-replicator and kubed annotations of all Secrets
+// to the annotations of this operator. By default, only the diff is printed.
+func runMigrateAnnotations(args []string) error {
+	fs := flag.NewFlagSet(migrateAnnotationsCommand, flag.ExitOnError)
+	namespace := fs.String("namespace", "", "Only migrate Secrets in this namespace (default: all namespaces).")
+	dryRun := fs.Bool("dry-run", true, "Only print the changes. Set --dry-run=false to update the Secrets.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	c, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	ctx := context.Background()
+	var secrets corev1.SecretList
+	if err := c.List(ctx, &secrets, client.InNamespace(*namespace)); err != nil {
+		return fmt.Errorf("failed to list Secrets: %w", err)
+	}
+
+	migrated, warnings := 0, 0
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		change := migrate.Translate(secret.Namespace, secret.Name, secret.Annotations)
+		if change.IsEmpty() {
+			continue
+		}
+
+		migrate.WriteDiff(os.Stdout, change)
+		fmt.Println()
+		warnings += len(change.Warnings)
+		if len(change.Added) == 0 && len(change.Removed) == 0 {
+			continue
+		}
+		migrated++
+
+		if *dryRun {
+			continue
+		}
+		original := secret.DeepCopy()
+		secret.Annotations = change.Apply(secret.Annotations)
+		if err := c.Patch(ctx, secret, client.MergeFrom(original)); err != nil {
+			return fmt.Errorf("failed to update Secret %s/%s: %w", secret.Namespace, secret.Name, err)
+		}
+	}
+
+	if *dryRun {
+		fmt.Printf("%d Secret(s) would be migrated, %d warning(s). Run with --dry-run=false to apply.\n", migrated, warnings)
+	} else {
+		fmt.Printf("%d Secret(s) migrated, %d warning(s).\n", migrated, warnings)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/guided-traffic/internal-secrets-operator/internal/preview"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+// previewCommand is the subcommand that prints Secrets as the operator would leave them
+const previewCommand = "preview"
+
+// runPreview reconciles the Secrets of a manifest file offline and prints the result
+func runPreview(args []string) error {
+	fs := flag.NewFlagSet(previewCommand, flag.ExitOnError)
+	file := fs.String("f", "", "Manifest file with Secrets and Namespaces, or \"-\" for stdin.")
+	configPath := fs.String("config", config.DefaultConfigPath, "Path to the configuration file.")
+	failOnWarning := fs.Bool("fail-on-warning", false, "Exit with an error if the operator would emit Warning events.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("-f is required")
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	var in io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return fmt.Errorf("failed to open manifest: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		in = f
+	}
+
+	objects, err := preview.Decode(in)
+	if err != nil {
+		return err
+	}
+	result, err := preview.Run(context.Background(), cfg, objects)
+	if err != nil {
+		return err
+	}
+	if err := preview.Write(os.Stdout, result); err != nil {
+		return err
+	}
+
+	if *failOnWarning && result.HasWarnings() {
+		return errors.New("the operator would emit Warning events")
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/guided-traffic/internal-secrets-operator/internal/controller"
+)
+
+// statusCommand is the subcommand that summarizes the state of the managed Secrets
+const statusCommand = "status"
+
+// runStatus fetches the summary from the status endpoint of a running operator and prints it
+func runStatus(args []string) error {
+	fs := flag.NewFlagSet(statusCommand, flag.ExitOnError)
+	url := fs.String("url", "https://localhost:8080", "Address of the operator's metrics server.")
+	token := fs.String("token", os.Getenv("ISO_TOKEN"),
+		"Bearer token authorized for the /debug/status non-resource URL (default: $ISO_TOKEN).")
+	insecure := fs.Bool("insecure-skip-tls-verify", false,
+		"Do not verify the certificate of the metrics server (e.g. the self-signed default).")
+	output := fs.String("o", "text", "Output format: text or json.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("unsupported output format %q", *output)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(*url, "/")+controller.DebugStatusPath, nil)
+	if err != nil {
+		return err
+	}
+	if *token != "" {
+		req.Header.Set("Authorization", "Bearer "+*token)
+	}
+	httpClient := &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: *insecure},
+		},
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query status endpoint: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status endpoint returned %s (is the operator running with --debug-endpoint?)", resp.Status)
+	}
+
+	var status controller.OperatorStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return fmt.Errorf("failed to decode status: %w", err)
+	}
+	if *output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(&status)
+	}
+	return controller.WriteStatus(os.Stdout, &status)
+}
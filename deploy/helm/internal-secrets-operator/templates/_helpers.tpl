@@ -60,3 +60,13 @@ Create the name of the service account to use
 {{- default "default" .Values.serviceAccount.name }}
 {{- end }}
 {{- end }}
+
+{{/*
+Whether the operator manages the webhook serving certificate itself: if requested, or if
+cert-manager is not installed in the cluster
+*/}}
+{{- define "internal-secrets-operator.webhookSelfManagedCertificate" -}}
+{{- if or .Values.webhook.selfManagedCertificate (not (.Capabilities.APIVersions.Has "cert-manager.io/v1")) -}}
+true
+{{- end }}
+{{- end }}
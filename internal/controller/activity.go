@@ -0,0 +1,148 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	// ActivityOutcomeSuccess is the outcome of decisions reported as Normal Events
+	ActivityOutcomeSuccess = "success"
+	// ActivityOutcomeFailure is the outcome of decisions reported as Warning Events
+	ActivityOutcomeFailure = "failure"
+)
+
+// ActivityObject identifies the object an activity record refers to
+type ActivityObject struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	UID       string `json:"uid,omitempty"`
+}
+
+// ActivityRecord is a single line of the structured activity stream
+type ActivityRecord struct {
+	Time      string         `json:"time"`
+	Component string         `json:"component"`
+	Decision  string         `json:"decision"`
+	Object    ActivityObject `json:"object"`
+	Reason    string         `json:"reason"`
+	Outcome   string         `json:"outcome"`
+}
+
+// activityEventRecorder is an EventRecorder that additionally writes every Event as an
+// ActivityRecord JSON line, so that security tooling can ingest operator decisions without
+// parsing free-form log messages
+type activityEventRecorder struct {
+	record.EventRecorder
+	component string
+	clock     Clock
+
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewActivityRecorder wraps an EventRecorder so that its Events are also written to out
+// as JSON lines. component identifies the controller in the records.
+func NewActivityRecorder(recorder record.EventRecorder, component string, out io.Writer) record.EventRecorder {
+	return &activityEventRecorder{
+		EventRecorder: recorder,
+		component:     component,
+		clock:         RealClock{},
+		out:           out,
+	}
+}
+
+// Event implements record.EventRecorder
+func (r *activityEventRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.write(object, eventtype, reason, message)
+	r.EventRecorder.Event(object, eventtype, reason, message)
+}
+
+// Eventf implements record.EventRecorder
+func (r *activityEventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.write(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+	r.EventRecorder.Eventf(object, eventtype, reason, messageFmt, args...)
+}
+
+// AnnotatedEventf implements record.EventRecorder
+func (r *activityEventRecorder) AnnotatedEventf(
+	object runtime.Object,
+	annotations map[string]string,
+	eventtype, reason, messageFmt string,
+	args ...interface{},
+) {
+	r.write(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+	r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+}
+
+// write writes a single activity record. Failures are ignored, the Event is still recorded.
+func (r *activityEventRecorder) write(object runtime.Object, eventtype, reason, message string) {
+	outcome := ActivityOutcomeSuccess
+	if eventtype == corev1.EventTypeWarning {
+		outcome = ActivityOutcomeFailure
+	}
+
+	line, err := json.Marshal(ActivityRecord{
+		Time:      r.clock.Now().UTC().Format(time.RFC3339),
+		Component: r.component,
+		Decision:  reason,
+		Object:    activityObject(object),
+		Reason:    message,
+		Outcome:   outcome,
+	})
+	if err != nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, _ = r.out.Write(append(line, '\n'))
+}
+
+// activityObject returns the identity of an object. Typed objects usually carry no TypeMeta,
+// so the kind falls back to the Go type name.
+func activityObject(object runtime.Object) ActivityObject {
+	var result ActivityObject
+
+	result.Kind = object.GetObjectKind().GroupVersionKind().Kind
+	if result.Kind == "" {
+		t := reflect.TypeOf(object)
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		result.Kind = t.Name()
+	}
+
+	if accessor, err := meta.Accessor(object); err == nil {
+		result.Namespace = accessor.GetNamespace()
+		result.Name = accessor.GetName()
+		result.UID = string(accessor.GetUID())
+	}
+	return result
+}
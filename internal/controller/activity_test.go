@@ -0,0 +1,83 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestActivityRecorder(t *testing.T) {
+	var out bytes.Buffer
+	fakeRecorder := record.NewFakeRecorder(10)
+	recorder := NewActivityRecorder(fakeRecorder, "secret-operator", &out).(*activityEventRecorder)
+	recorder.clock = &MockClock{currentTime: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "production", UID: "1234"}}
+	recorder.Event(secret, corev1.EventTypeNormal, EventReasonGenerationSucceeded, "Generated values for fields: password")
+	recorder.Eventf(secret, corev1.EventTypeWarning, EventReasonReplicationDenied, "Namespace %s is denied", "kube-system")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 activity records, got %d: %q", len(lines), out.String())
+	}
+
+	var records []ActivityRecord
+	for _, line := range lines {
+		var rec ActivityRecord
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("activity record is not valid JSON: %v", err)
+		}
+		records = append(records, rec)
+	}
+
+	expected := []ActivityRecord{
+		{
+			Time:      "2025-01-01T12:00:00Z",
+			Component: "secret-operator",
+			Decision:  EventReasonGenerationSucceeded,
+			Object:    ActivityObject{Kind: "Secret", Namespace: "production", Name: "db", UID: "1234"},
+			Reason:    "Generated values for fields: password",
+			Outcome:   ActivityOutcomeSuccess,
+		},
+		{
+			Time:      "2025-01-01T12:00:00Z",
+			Component: "secret-operator",
+			Decision:  EventReasonReplicationDenied,
+			Object:    ActivityObject{Kind: "Secret", Namespace: "production", Name: "db", UID: "1234"},
+			Reason:    "Namespace kube-system is denied",
+			Outcome:   ActivityOutcomeFailure,
+		},
+	}
+	for i := range expected {
+		if records[i] != expected[i] {
+			t.Errorf("record %d: expected %+v, got %+v", i, expected[i], records[i])
+		}
+	}
+
+	// The Events are still recorded
+	if len(fakeRecorder.Events) != 2 {
+		t.Errorf("expected 2 Events to be recorded, got %d", len(fakeRecorder.Events))
+	}
+}
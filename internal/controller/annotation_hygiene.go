@@ -0,0 +1,430 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	isoannotations "github.com/guided-traffic/internal-secrets-operator/pkg/annotations"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+const (
+	// MetricAnnotationProblems is the name of the gauge with the number of annotation problems
+	// found by the last annotation hygiene check
+	MetricAnnotationProblems = "secret_operator_annotation_problems"
+
+	// EventReasonAnnotationProblems is the event reason for the summary of the annotation problems
+	// of a namespace
+	EventReasonAnnotationProblems = "AnnotationProblems"
+
+	// AnnotationProblemDeprecated is an annotation with the legacy annotation prefix
+	AnnotationProblemDeprecated = "deprecated"
+	// AnnotationProblemMisspelled is an unknown annotation that is close to a known one
+	AnnotationProblemMisspelled = "misspelled"
+	// AnnotationProblemUnknown is an unknown annotation that is not close to any known one
+	AnnotationProblemUnknown = "unknown"
+	// AnnotationProblemConflicting is a combination of annotations that cannot be used together
+	AnnotationProblemConflicting = "conflicting"
+
+	// maxSuggestionDistance is the maximum edit distance between a misspelled annotation and the
+	// known annotation it is reported as a typo of
+	maxSuggestionDistance = 2
+
+	// maxEventFindings is the number of problems listed in the summary Event of a namespace
+	maxEventFindings = 10
+)
+
+// annotationProblems lists the problems in the order they are reported
+var annotationProblems = []string{
+	AnnotationProblemDeprecated,
+	AnnotationProblemMisspelled,
+	AnnotationProblemUnknown,
+	AnnotationProblemConflicting,
+}
+
+// knownAnnotations are the annotations the operator reads or writes
+var knownAnnotations = []string{
+	isoannotations.Autogenerate,
+	isoannotations.AutogenerateID,
+	isoannotations.Adopt,
+	isoannotations.Type,
+	isoannotations.Length,
+	isoannotations.Encoding,
+	isoannotations.GeneratedAt,
+	isoannotations.GeneratedKeys,
+	isoannotations.DataChecksum,
+	isoannotations.Rotate,
+	isoannotations.RotateExclude,
+	isoannotations.RotateWith,
+	isoannotations.RotationRequestedAt,
+	isoannotations.RotationRequestHandledAt,
+	isoannotations.RotationConfigError,
+	isoannotations.GenerationError,
+	isoannotations.Ready,
+	isoannotations.BackupOf,
+	isoannotations.BackupExpiresAt,
+	isoannotations.TLSDNSNames,
+	isoannotations.TLSIPAddresses,
+	isoannotations.TLSKeyUsages,
+	isoannotations.TLSDuration,
+	isoannotations.TLSRenewBefore,
+	isoannotations.TLSIssuerSecret,
+	isoannotations.TLSIssuerForNamespaces,
+	isoannotations.TLSKeystorePasswordField,
+	isoannotations.RenderEnv,
+	isoannotations.RenderJSON,
+	isoannotations.RenderYAML,
+	isoannotations.RenderMapping,
+	isoannotations.OAuthClientIDFormat,
+	isoannotations.TTL,
+	isoannotations.TTLWarnedFor,
+	isoannotations.StringUppercase,
+	isoannotations.StringLowercase,
+	isoannotations.StringNumbers,
+	isoannotations.StringSpecialChars,
+	isoannotations.StringAllowedSpecialChars,
+	isoannotations.StringData,
+	isoannotations.UniqueValues,
+	replicator.AnnotationReplicatableFromNamespaces,
+	replicator.AnnotationReplicatableFromSelector,
+	replicator.AnnotationReplicatableUntil,
+	replicator.AnnotationReplicateFrom,
+	replicator.AnnotationReplicateTo,
+	replicator.AnnotationReplicateExcludeNamespaces,
+	replicator.AnnotationReplicateAs,
+	replicator.AnnotationReplicatedFrom,
+	replicator.AnnotationLastReplicatedAt,
+	replicator.AnnotationSourceDeletedAt,
+	replicator.AnnotationConsentExpiredAt,
+	replicator.AnnotationReplicatedChecksum,
+	AnnotationReconcileRequest,
+	AnnotationTokenSecret,
+}
+
+// knownAnnotationPrefixes are the prefixes of the field-specific annotations (e.g. type.<field>)
+var knownAnnotationPrefixes = []string{
+	isoannotations.TypePrefix,
+	isoannotations.LengthPrefix,
+	isoannotations.EncodingPrefix,
+	isoannotations.HashPrefix,
+	isoannotations.RotatePrefix,
+	isoannotations.SigningKeyPrefix,
+	isoannotations.TokenDurationPrefix,
+	isoannotations.OriginPrefix,
+	isoannotations.RevisionPrefix,
+	replicator.AnnotationReplicateFromKeyPrefix,
+}
+
+// annotationFinding is a problem with the annotations of a Secret
+type annotationFinding struct {
+	secret  string
+	problem string
+	message string
+}
+
+// String returns a description of the finding for event messages
+func (f annotationFinding) String() string {
+	return fmt.Sprintf("%s: %s", f.secret, f.message)
+}
+
+// checkAnnotations returns the problems with the operator annotations of a Secret. Annotations
+// with legacyPrefix are deprecated; they are still read during the migration to the primary prefix.
+func checkAnnotations(secret *corev1.Secret, legacyPrefix string) []annotationFinding {
+	var findings []annotationFinding
+	report := func(problem, format string, args ...interface{}) {
+		findings = append(findings, annotationFinding{
+			secret:  secret.Name,
+			problem: problem,
+			message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	keys := make([]string, 0, len(secret.Annotations))
+	for key := range secret.Annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if legacyPrefix != "" && legacyPrefix != isoannotations.Prefix {
+			if name, ok := strings.CutPrefix(key, legacyPrefix); ok && name != "" {
+				report(AnnotationProblemDeprecated, "deprecated annotation %s, use %s", key, isoannotations.Prefix+name)
+				continue
+			}
+		}
+		if !strings.HasPrefix(key, isoannotations.Prefix) || isKnownAnnotation(key) {
+			continue
+		}
+		if suggestion := suggestAnnotation(key); suggestion != "" {
+			report(AnnotationProblemMisspelled, "unknown annotation %s, did you mean %s?", key, suggestion)
+		} else {
+			report(AnnotationProblemUnknown, "unknown annotation %s", key)
+		}
+	}
+
+	if replicator.HasConflictingAnnotations(secret) {
+		report(AnnotationProblemConflicting, "%s cannot be used together with %s",
+			AnnotationAutogenerate, replicator.AnnotationReplicateFrom)
+	}
+	return findings
+}
+
+// isKnownAnnotation returns true if the operator reads or writes the annotation
+func isKnownAnnotation(key string) bool {
+	if slices.Contains(knownAnnotations, key) {
+		return true
+	}
+	return slices.ContainsFunc(knownAnnotationPrefixes, func(prefix string) bool {
+		return strings.HasPrefix(key, prefix) && len(key) > len(prefix)
+	})
+}
+
+// suggestAnnotation returns the known annotation closest to an unknown one, or "" if none is
+// within maxSuggestionDistance. Field-specific annotations are compared by their prefix, so that
+// "lenght.password" suggests "length.password".
+func suggestAnnotation(key string) string {
+	name := strings.TrimPrefix(key, isoannotations.Prefix)
+
+	best, bestDistance := "", maxSuggestionDistance+1
+	consider := func(candidate, compared, known string) {
+		if distance := editDistance(compared, strings.TrimPrefix(known, isoannotations.Prefix)); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	for _, known := range knownAnnotations {
+		consider(known, name, known)
+	}
+	if prefix, field, ok := strings.Cut(name, "."); ok && field != "" {
+		for _, known := range knownAnnotationPrefixes {
+			consider(known+field, prefix+".", known)
+		}
+	}
+	// A distance as large as the name itself is no typo, e.g. "ab" for "ttl"
+	if bestDistance >= len(name) {
+		return ""
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// AnnotationHygieneReporter periodically checks the operator annotations of all Secrets for
+// deprecated, misspelled and conflicting annotations, which the controllers otherwise ignore
+// silently. It exports the number of problems and creates a summary Event per namespace.
+type AnnotationHygieneReporter struct {
+	client.Client
+	Config        *config.Config
+	EventRecorder record.EventRecorder
+	// Shard restricts the check to the namespaces of one shard. If nil, all namespaces are checked.
+	Shard *Shard
+
+	mu sync.Mutex
+	// counts holds the number of problems of the last check per problem and namespace
+	counts map[string]map[string]int
+}
+
+// SetupWithManager registers the metric and adds the reporter to the Manager. It only runs on the leader.
+func (r *AnnotationHygieneReporter) SetupWithManager(mgr ctrl.Manager) error {
+	if err := registerCollector(newAnnotationProblemsCollector(r)); err != nil {
+		return err
+	}
+	return mgr.Add(r)
+}
+
+// Start implements manager.Runnable
+func (r *AnnotationHygieneReporter) Start(ctx context.Context) error {
+	interval := r.Config.AnnotationHygiene.Interval.Duration()
+	if interval <= 0 {
+		interval = config.DefaultAnnotationHygieneInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.check(ctx); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to check annotations")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// check checks the annotations of all Secrets, updates the metric and creates the summary Events
+func (r *AnnotationHygieneReporter) check(ctx context.Context) error {
+	var secrets corev1.SecretList
+	if err := r.List(ctx, &secrets); err != nil {
+		return fmt.Errorf("failed to list Secrets: %w", err)
+	}
+
+	owned := make(map[string]bool)
+	findings := make(map[string][]annotationFinding)
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if r.Config.IsSecretTypeIgnored(string(secret.Type)) {
+			continue
+		}
+		isOwned, ok := owned[secret.Namespace]
+		if !ok {
+			var err error
+			if isOwned, err = r.Shard.Owns(ctx, secret.Namespace); err != nil {
+				log.FromContext(ctx).Error(err, "Failed to determine shard", "namespace", secret.Namespace)
+			}
+			owned[secret.Namespace] = isOwned
+		}
+		if !isOwned {
+			continue
+		}
+		findings[secret.Namespace] = append(findings[secret.Namespace], checkAnnotations(secret, r.Config.LegacyAnnotationPrefix)...)
+	}
+
+	counts := make(map[string]map[string]int, len(annotationProblems))
+	for _, problem := range annotationProblems {
+		counts[problem] = make(map[string]int)
+	}
+	for namespace, namespaceFindings := range findings {
+		for _, finding := range namespaceFindings {
+			counts[finding.problem][namespace]++
+		}
+	}
+	r.mu.Lock()
+	r.counts = counts
+	r.mu.Unlock()
+
+	namespaces := make([]string, 0, len(findings))
+	for namespace, namespaceFindings := range findings {
+		if len(namespaceFindings) > 0 {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	sort.Strings(namespaces)
+	for _, name := range namespaces {
+		r.reportNamespace(ctx, name, findings[name])
+	}
+	return nil
+}
+
+// reportNamespace creates the summary Event of the annotation problems of a namespace
+func (r *AnnotationHygieneReporter) reportNamespace(ctx context.Context, name string, findings []annotationFinding) {
+	var namespace corev1.Namespace
+	if err := r.Get(ctx, types.NamespacedName{Name: name}, &namespace); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to get namespace for the annotation problems Event", "namespace", name)
+		return
+	}
+
+	listed := findings
+	if len(listed) > maxEventFindings {
+		listed = listed[:maxEventFindings]
+	}
+	parts := make([]string, 0, len(listed))
+	for _, finding := range listed {
+		parts = append(parts, finding.String())
+	}
+	message := fmt.Sprintf("%d annotation problems: %s", len(findings), strings.Join(parts, "; "))
+	if more := len(findings) - len(listed); more > 0 {
+		message += fmt.Sprintf("; and %d more", more)
+	}
+	r.EventRecorder.Event(&namespace, corev1.EventTypeWarning, EventReasonAnnotationProblems, message)
+}
+
+// annotationProblemsCollector exports the problem counts of the last check of a reporter. Only
+// the namespaces with the most problems are reported individually; all others are aggregated into
+// OtherNamespacesLabel.
+type annotationProblemsCollector struct {
+	desc     *prometheus.Desc
+	reporter *AnnotationHygieneReporter
+	config   config.MetricsConfig
+}
+
+// newAnnotationProblemsCollector creates the collector of the problem counts of a reporter
+func newAnnotationProblemsCollector(r *AnnotationHygieneReporter) *annotationProblemsCollector {
+	return &annotationProblemsCollector{
+		desc: prometheus.NewDesc(MetricAnnotationProblems,
+			"Number of problems with the operator annotations of Secrets found by the last check (problem: deprecated, misspelled, unknown or conflicting)",
+			append([]string{"problem"}, namespaceLabels(r.Config.Metrics)...), nil),
+		reporter: r,
+		config:   r.Config.Metrics,
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *annotationProblemsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector
+func (c *annotationProblemsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.reporter.mu.Lock()
+	defer c.reporter.mu.Unlock()
+	if c.reporter.counts == nil {
+		return // Not checked yet
+	}
+
+	for _, problem := range annotationProblems {
+		counts := c.reporter.counts[problem]
+		if !c.config.PerNamespace {
+			total := 0
+			for _, count := range counts {
+				total += count
+			}
+			ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(total), problem)
+			continue
+		}
+		for namespace, count := range topNamespaces(counts, maxMetricNamespaces(c.config)) {
+			ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count), problem, namespace)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+func TestCheckAnnotations(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    map[string]string
+	}{
+		{
+			name: "known annotations",
+			annotations: map[string]string{
+				AnnotationAutogenerate:              "password",
+				AnnotationTypePrefix + "password":   "bytes",
+				AnnotationLengthPrefix + "password": "64",
+				"app.kubernetes.io/name":            "db",
+			},
+		},
+		{
+			name:        "misspelled annotation",
+			annotations: map[string]string{"iso.gtrfc.com/autogenrate": "password"},
+			expected:    map[string]string{AnnotationProblemMisspelled: "did you mean " + AnnotationAutogenerate + "?"},
+		},
+		{
+			name:        "misspelled field-specific annotation",
+			annotations: map[string]string{"iso.gtrfc.com/lenght.password": "64"},
+			expected:    map[string]string{AnnotationProblemMisspelled: "did you mean " + AnnotationLengthPrefix + "password?"},
+		},
+		{
+			name:        "unknown annotation",
+			annotations: map[string]string{"iso.gtrfc.com/something-else": "true"},
+			expected:    map[string]string{AnnotationProblemUnknown: "unknown annotation iso.gtrfc.com/something-else"},
+		},
+		{
+			name:        "deprecated annotation",
+			annotations: map[string]string{"legacy.example.com/autogenerate": "password"},
+			expected:    map[string]string{AnnotationProblemDeprecated: "use " + AnnotationAutogenerate},
+		},
+		{
+			name: "conflicting annotations",
+			annotations: map[string]string{
+				AnnotationAutogenerate:             "password",
+				replicator.AnnotationReplicateFrom: "production/db",
+			},
+			expected: map[string]string{AnnotationProblemConflicting: replicator.AnnotationReplicateFrom},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db", Annotations: tt.annotations}}
+			findings := checkAnnotations(secret, "legacy.example.com/")
+			if len(findings) != len(tt.expected) {
+				t.Fatalf("expected %d findings, got %v", len(tt.expected), findings)
+			}
+			for _, finding := range findings {
+				expected, ok := tt.expected[finding.problem]
+				if !ok {
+					t.Fatalf("unexpected finding %v", finding)
+				}
+				if !strings.Contains(finding.message, expected) {
+					t.Errorf("expected message of %s finding to contain %q, got %q", finding.problem, expected, finding.message)
+				}
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"ttl", "ttl", 0},
+		{"", "ttl", 3},
+		{"lenght", "length", 2},
+		{"autogenrate", "autogenerate", 1},
+		{"rotate", "rotates", 1},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.expected {
+			t.Errorf("editDistance(%q, %q) = %d, expected %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func TestAnnotationHygieneReporterCheck(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	objs := []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "production"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "staging"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: "db", Namespace: "production",
+			Annotations: map[string]string{"iso.gtrfc.com/autogenrate": "password"},
+		}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: "api", Namespace: "production",
+			Annotations: map[string]string{
+				AnnotationAutogenerate:             "token",
+				replicator.AnnotationReplicateFrom: "staging/api",
+			},
+		}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: "api", Namespace: "staging",
+			Annotations: map[string]string{AnnotationAutogenerate: "token"},
+		}},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	recorder := record.NewFakeRecorder(10)
+	reporter := &AnnotationHygieneReporter{
+		Client:        fakeClient,
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: recorder,
+	}
+
+	if err := reporter.check(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Only the namespace with problems gets a summary Event
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(recorder.Events))
+	}
+	event := <-recorder.Events
+	for _, expected := range []string{EventReasonAnnotationProblems, "2 annotation problems", "db: unknown annotation", "api: "} {
+		if !strings.Contains(event, expected) {
+			t.Errorf("expected event to contain %q, got %q", expected, event)
+		}
+	}
+
+	ch := make(chan prometheus.Metric, 10)
+	newAnnotationProblemsCollector(reporter).Collect(ch)
+	close(ch)
+
+	counts := make(map[string]float64)
+	for m := range ch {
+		var metric dto.Metric
+		if err := m.Write(&metric); err != nil {
+			t.Fatalf("failed to write metric: %v", err)
+		}
+		for _, label := range metric.GetLabel() {
+			if label.GetName() == "problem" {
+				counts[label.GetValue()] = metric.GetGauge().GetValue()
+			}
+		}
+	}
+	expected := map[string]float64{
+		AnnotationProblemDeprecated:  0,
+		AnnotationProblemMisspelled:  1,
+		AnnotationProblemUnknown:     0,
+		AnnotationProblemConflicting: 1,
+	}
+	for problem, count := range expected {
+		if got, ok := counts[problem]; !ok || got != count {
+			t.Errorf("expected %v %s problems, got %v", count, problem, got)
+		}
+	}
+}
+
+func TestAnnotationHygieneReporterEventLimit(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	annotations := make(map[string]string)
+	for i := 0; i < maxEventFindings+3; i++ {
+		annotations["iso.gtrfc.com/unknown-"+strings.Repeat("x", i+1)] = "true"
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "production"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "production", Annotations: annotations}},
+	).Build()
+	recorder := record.NewFakeRecorder(10)
+	reporter := &AnnotationHygieneReporter{
+		Client:        fakeClient,
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: recorder,
+	}
+
+	if err := reporter.check(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	event := <-recorder.Events
+	if !strings.Contains(event, "and 3 more") {
+		t.Errorf("expected event to mention the omitted problems, got %q", event)
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+const (
+	// AnnotationBootstrapSecrets is set on a Namespace and lists the bootstrap Secrets
+	// (comma-separated names of the bootstrap configuration) to create in it
+	AnnotationBootstrapSecrets = AnnotationPrefix + "bootstrap-secrets"
+
+	// EventReasonSecretBootstrapped is the event reason for a bootstrap Secret created in a namespace
+	EventReasonSecretBootstrapped = "SecretBootstrapped"
+
+	// EventReasonBootstrapFailed is the event reason for a bootstrap Secret that could not be created
+	EventReasonBootstrapFailed = "BootstrapFailed"
+)
+
+// BootstrapReconciler creates the configured bootstrap Secrets in namespaces that request them
+// by annotation or match their namespace patterns. The generator fills in the values afterwards.
+type BootstrapReconciler struct {
+	client.Client
+	Config        *config.Config
+	EventRecorder record.EventRecorder
+	// Heartbeat records completed reconciles for the liveness check. If nil, nothing is recorded.
+	Heartbeat *Heartbeat
+	// Shard restricts the controller to the namespaces of one shard. If nil, all namespaces are processed.
+	Shard *Shard
+}
+
+// bootstrapSecretsFor returns the bootstrap Secrets for a namespace and the names in its
+// bootstrap-secrets annotation that are not configured
+func bootstrapSecretsFor(secrets []config.BootstrapSecret, namespace *corev1.Namespace) ([]config.BootstrapSecret, []string) {
+	requested := parseFields(namespace.Annotations[AnnotationBootstrapSecrets])
+
+	var result []config.BootstrapSecret
+	for _, secret := range secrets {
+		if slices.Contains(requested, secret.Name) || matchesBootstrapNamespace(secret, namespace) {
+			result = append(result, secret)
+		}
+	}
+
+	var unknown []string
+	for _, name := range requested {
+		if !slices.ContainsFunc(secrets, func(secret config.BootstrapSecret) bool { return secret.Name == name }) {
+			unknown = append(unknown, name)
+		}
+	}
+	return result, unknown
+}
+
+// matchesBootstrapNamespace returns true if the namespace matches the namespace patterns or the
+// namespace selector of a bootstrap Secret. Secrets without either are only created by annotation.
+func matchesBootstrapNamespace(secret config.BootstrapSecret, namespace *corev1.Namespace) bool {
+	if len(secret.Namespaces) == 0 && secret.NamespaceSelector == "" {
+		return false
+	}
+	if len(secret.Namespaces) > 0 && !replicator.MatchesAnyNamespace(namespace.Name, secret.Namespaces) {
+		return false
+	}
+	if secret.NamespaceSelector == "" {
+		return true
+	}
+	// The selector is validated when the configuration is loaded
+	selector, err := labels.Parse(secret.NamespaceSelector)
+	return err == nil && selector.Matches(labels.Set(namespace.Labels))
+}
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;create
+
+// Reconcile creates the missing bootstrap Secrets of a namespace. Existing Secrets are never modified.
+func (r *BootstrapReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var namespace corev1.Namespace
+	if err := r.Get(ctx, req.NamespacedName, &namespace); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if isTerminating(&namespace) {
+		return ctrl.Result{}, nil
+	}
+	// Namespaces are cluster-scoped, so the shard is determined by the name
+	if owned, err := r.Shard.Owns(ctx, namespace.Name); err != nil || !owned {
+		return ctrl.Result{}, err
+	}
+
+	secrets, unknown := bootstrapSecretsFor(r.Config.Bootstrap, &namespace)
+	for _, name := range unknown {
+		r.EventRecorder.Event(&namespace, corev1.EventTypeWarning, EventReasonBootstrapFailed,
+			fmt.Sprintf("Bootstrap Secret %q is not configured", name))
+	}
+
+	var errs []error
+	for _, bootstrap := range secrets {
+		created, err := r.createSecret(ctx, namespace.Name, bootstrap)
+		if err != nil {
+			logger.Error(err, "Failed to create bootstrap Secret", "namespace", namespace.Name, "secret", bootstrap.Name)
+			r.EventRecorder.Event(&namespace, corev1.EventTypeWarning, EventReasonBootstrapFailed,
+				fmt.Sprintf("Cannot create bootstrap Secret %q: %v", bootstrap.Name, err))
+			errs = append(errs, err)
+			continue
+		}
+		if created {
+			logger.Info("Created bootstrap Secret", "namespace", namespace.Name, "secret", bootstrap.Name)
+			r.EventRecorder.Event(&namespace, corev1.EventTypeNormal, EventReasonSecretBootstrapped,
+				fmt.Sprintf("Created bootstrap Secret %q", bootstrap.Name))
+		}
+	}
+	return ctrl.Result{}, errors.Join(errs...)
+}
+
+// createSecret creates a bootstrap Secret unless a Secret with its name already exists.
+// It returns true if the Secret was created.
+func (r *BootstrapReconciler) createSecret(ctx context.Context, namespace string, bootstrap config.BootstrapSecret) (bool, error) {
+	var existing corev1.Secret
+	err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: bootstrap.Name}, &existing)
+	if err == nil {
+		return false, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return false, err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        bootstrap.Name,
+			Namespace:   namespace,
+			Annotations: maps.Clone(bootstrap.Annotations),
+			Labels:      maps.Clone(bootstrap.Labels),
+		},
+		Type: corev1.SecretType(bootstrap.Type),
+	}
+	// The generator ignores Secrets without the managed label
+	if r.Config.ManagedLabel.Enabled {
+		if secret.Labels == nil {
+			secret.Labels = make(map[string]string)
+		}
+		if _, ok := secret.Labels[r.Config.ManagedLabel.Key]; !ok {
+			secret.Labels[r.Config.ManagedLabel.Key] = r.Config.ManagedLabel.Value
+		}
+	}
+
+	if err := r.Create(ctx, secret); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// SetupWithManager sets up the controller with the Manager. Namespaces are reconciled when they
+// are created and when their labels or annotations change.
+func (r *BootstrapReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	recorder, err := instrumentEventRecorder(r.EventRecorder)
+	if err != nil {
+		return err
+	}
+	r.EventRecorder = recorder
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("bootstrap").
+		For(&corev1.Namespace{}, builder.WithPredicates(
+			predicate.Or(predicate.AnnotationChangedPredicate{}, predicate.LabelChangedPredicate{}))).
+		Complete(r.Heartbeat.wrap("bootstrap", reportPanics(r.EventRecorder, &corev1.Namespace{}, r)))
+}
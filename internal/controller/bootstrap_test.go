@@ -0,0 +1,151 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+func TestBootstrapSecretsFor(t *testing.T) {
+	secrets := []config.BootstrapSecret{
+		{Name: "app-db"},
+		{Name: "app-cache"},
+		{Name: "team-token", Namespaces: []string{"team-*"}},
+		{Name: "tenant-key", NamespaceSelector: "tenant=true"},
+	}
+
+	tests := []struct {
+		name            string
+		namespace       *corev1.Namespace
+		expectedSecrets []string
+		expectedUnknown []string
+	}{
+		{
+			name:      "no annotation and no match",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "apps"}},
+		},
+		{
+			name: "annotation",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "apps",
+				Annotations: map[string]string{AnnotationBootstrapSecrets: "app-db, app-queue"},
+			}},
+			expectedSecrets: []string{"app-db"},
+			expectedUnknown: []string{"app-queue"},
+		},
+		{
+			name:            "namespace pattern",
+			namespace:       &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+			expectedSecrets: []string{"team-token"},
+		},
+		{
+			name: "namespace selector",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "apps",
+				Labels: map[string]string{"tenant": "true"},
+			}},
+			expectedSecrets: []string{"tenant-key"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, unknown := bootstrapSecretsFor(secrets, tt.namespace)
+			var names []string
+			for _, secret := range result {
+				names = append(names, secret.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.expectedSecrets, ",") {
+				t.Errorf("expected secrets %v, got %v", tt.expectedSecrets, names)
+			}
+			if strings.Join(unknown, ",") != strings.Join(tt.expectedUnknown, ",") {
+				t.Errorf("expected unknown %v, got %v", tt.expectedUnknown, unknown)
+			}
+		})
+	}
+}
+
+func TestBootstrapReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "apps",
+		Annotations: map[string]string{AnnotationBootstrapSecrets: "app-db,app-cache,app-queue"},
+	}}
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-cache", Namespace: "apps"},
+		Data:       map[string][]byte{"password": []byte("manual")},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(namespace, existing).Build()
+	fakeRecorder := record.NewFakeRecorder(10)
+
+	cfg := config.NewDefaultConfig()
+	cfg.ManagedLabel = config.ManagedLabelConfig{Enabled: true, Key: "managed", Value: "true"}
+	cfg.Bootstrap = []config.BootstrapSecret{
+		{Name: "app-db", Annotations: map[string]string{AnnotationAutogenerate: "password"}},
+		{Name: "app-cache", Annotations: map[string]string{AnnotationAutogenerate: "password"}},
+	}
+	reconciler := &BootstrapReconciler{Client: fakeClient, Config: cfg, EventRecorder: fakeRecorder}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: namespace.Name}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var created corev1.Secret
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Namespace: "apps", Name: "app-db"}, &created); err != nil {
+		t.Fatalf("expected app-db to be created: %v", err)
+	}
+	if created.Annotations[AnnotationAutogenerate] != "password" {
+		t.Errorf("expected autogenerate annotation, got %v", created.Annotations)
+	}
+	if created.Labels["managed"] != "true" {
+		t.Errorf("expected managed label, got %v", created.Labels)
+	}
+
+	var cache corev1.Secret
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Namespace: "apps", Name: "app-cache"}, &cache); err != nil {
+		t.Fatalf("failed to get app-cache: %v", err)
+	}
+	if _, ok := cache.Annotations[AnnotationAutogenerate]; ok || string(cache.Data["password"]) != "manual" {
+		t.Errorf("expected existing Secret to be left untouched, got %v", cache)
+	}
+
+	var events []string
+	for len(fakeRecorder.Events) > 0 {
+		events = append(events, <-fakeRecorder.Events)
+	}
+	joined := strings.Join(events, "\n")
+	if len(events) != 2 || !strings.Contains(joined, EventReasonBootstrapFailed+` Bootstrap Secret "app-queue"`) ||
+		!strings.Contains(joined, EventReasonSecretBootstrapped+` Created bootstrap Secret "app-db"`) {
+		t.Errorf("expected BootstrapFailed for app-queue and SecretBootstrapped for app-db, got %v", events)
+	}
+}
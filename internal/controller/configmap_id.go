@@ -0,0 +1,168 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	isoannotations "github.com/guided-traffic/internal-secrets-operator/pkg/annotations"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+)
+
+// ConfigMapIDReconciler fills the keys listed in the autogenerate-id annotation of a ConfigMap
+// with random identifiers (e.g. correlation or tenant IDs). Identifiers are not secret, so they
+// are generated once and never rotated. The type, length and charset annotations of Secrets
+// apply; string values are written to data, bytes values to binaryData.
+type ConfigMapIDReconciler struct {
+	client.Client
+	Generator     generator.Generator
+	Config        *config.Config
+	EventRecorder record.EventRecorder
+	// Heartbeat records completed reconciles for the liveness check. If nil, nothing is recorded.
+	Heartbeat *Heartbeat
+	// Shard restricts the controller to the namespaces of one shard. If nil, all namespaces are processed.
+	Shard *Shard
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;update
+
+// Reconcile generates the missing identifiers of a ConfigMap
+func (r *ConfigMapIDReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var configMap corev1.ConfigMap
+	if err := r.Get(ctx, req.NamespacedName, &configMap); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	fields := isoannotations.ParseFields(configMap.Annotations[AnnotationAutogenerateID])
+	if len(fields) == 0 || configMap.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+	// Invalid annotations only change with the annotations, so they are not retried
+	if err := validateIDFields(fields); err != nil {
+		r.EventRecorder.Event(&configMap, corev1.EventTypeWarning, EventReasonGenerationFailed, err.Error())
+		return ctrl.Result{}, nil
+	}
+
+	var generated []fieldChange
+	for _, field := range fields {
+		if _, exists := configMap.Data[field]; exists {
+			continue
+		}
+		if _, exists := configMap.BinaryData[field]; exists {
+			continue
+		}
+
+		opts, err := r.generateOptions(configMap.Annotations, field)
+		if err != nil {
+			r.EventRecorder.Event(&configMap, corev1.EventTypeWarning, EventReasonGenerationFailed, err.Error())
+			return ctrl.Result{}, nil
+		}
+		value, err := r.Generator.GenerateWithOptions(opts)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to generate value for field %s: %w", field, err)
+		}
+
+		if opts.Type == config.TypeBytes {
+			if configMap.BinaryData == nil {
+				configMap.BinaryData = make(map[string][]byte)
+			}
+			configMap.BinaryData[field] = value
+		} else {
+			if configMap.Data == nil {
+				configMap.Data = make(map[string]string)
+			}
+			configMap.Data[field] = string(value)
+		}
+		generated = append(generated, fieldChange{field: field, genType: opts.Type, length: opts.Length})
+	}
+	if len(generated) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.Update(ctx, &configMap); err != nil {
+		return ctrl.Result{}, err
+	}
+	logger.Info("Generated identifiers for ConfigMap", "fields", formatFieldChanges(generated))
+	r.EventRecorder.Event(&configMap, corev1.EventTypeNormal, EventReasonGenerationSucceeded,
+		fmt.Sprintf("Generated values for fields: %s", formatFieldChanges(generated)))
+	return ctrl.Result{}, nil
+}
+
+// generateOptions returns the options to generate a field from the annotations.
+// Only the string, bytes, hex and uuid types can be generated into ConfigMaps.
+func (r *ConfigMapIDReconciler) generateOptions(annotations map[string]string, field string) (generator.GenerateOptions, error) {
+	opts := generator.GenerateOptions{
+		Type:   isoannotations.FieldType(annotations, field, r.Config.Defaults.Type),
+		Length: isoannotations.FieldLength(annotations, field, r.Config.Defaults.Length),
+	}
+	switch opts.Type {
+	case config.DefaultType:
+		charset, err := isoannotations.Charset(annotations, r.Config.Defaults.String)
+		if err != nil {
+			return opts, fmt.Errorf("invalid charset configuration for field %q: %w", field, err)
+		}
+		opts.Charset = charset
+	case config.TypeBytes, config.TypeHex, config.TypeUUID:
+	default:
+		return opts, fmt.Errorf("type %q of field %q cannot be generated into a ConfigMap, only %s, %s, %s and %s are supported",
+			opts.Type, field, config.DefaultType, config.TypeBytes, config.TypeHex, config.TypeUUID)
+	}
+	return opts, nil
+}
+
+// validateIDFields checks that the fields are valid ConfigMap keys
+func validateIDFields(fields []string) error {
+	for _, field := range fields {
+		if errs := validation.IsConfigMapKey(field); len(errs) > 0 {
+			return fmt.Errorf("invalid field %q in %s: %s", field, AnnotationAutogenerateID, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager. It watches ConfigMaps with the
+// autogenerate-id annotation.
+func (r *ConfigMapIDReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	hasAutogenerateIDAnnotation := predicate.NewPredicateFuncs(func(object client.Object) bool {
+		return object.GetAnnotations()[AnnotationAutogenerateID] != ""
+	})
+
+	recorder, err := instrumentEventRecorder(r.EventRecorder)
+	if err != nil {
+		return err
+	}
+	r.EventRecorder = recorder
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("configmap-id").
+		For(&corev1.ConfigMap{}, builder.WithPredicates(hasAutogenerateIDAnnotation)).
+		Complete(r.Heartbeat.wrap("configmap-id", r.Shard.wrap(
+			reportPanics(r.EventRecorder, &corev1.ConfigMap{}, r))))
+}
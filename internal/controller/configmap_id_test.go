@@ -0,0 +1,141 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+)
+
+func TestConfigMapIDReconciler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		data        map[string]string
+		expectError string
+		check       func(t *testing.T, configMap *corev1.ConfigMap)
+	}{
+		{
+			name: "generates missing identifiers",
+			annotations: map[string]string{
+				AnnotationAutogenerateID:                   "correlation-id,installation-id,seed,tenant-id,trace-id",
+				AnnotationLengthPrefix + "installation-id": "12",
+				AnnotationTypePrefix + "seed":              config.TypeBytes,
+				AnnotationTypePrefix + "tenant-id":         config.TypeUUID,
+				AnnotationTypePrefix + "trace-id":          config.TypeHex,
+				AnnotationLengthPrefix + "trace-id":        "16",
+				AnnotationStringUppercase:                  "false",
+			},
+			data: map[string]string{"correlation-id": "existing"},
+			check: func(t *testing.T, configMap *corev1.ConfigMap) {
+				if configMap.Data["correlation-id"] != "existing" {
+					t.Errorf("expected existing identifier to be kept, got %q", configMap.Data["correlation-id"])
+				}
+				id := configMap.Data["installation-id"]
+				if len(id) != 12 || strings.ToLower(id) != id {
+					t.Errorf("expected 12 character lowercase identifier, got %q", id)
+				}
+				if len(configMap.BinaryData["seed"]) != 32 {
+					t.Errorf("expected 32 bytes in binaryData, got %d", len(configMap.BinaryData["seed"]))
+				}
+				uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+				if !uuid.MatchString(configMap.Data["tenant-id"]) {
+					t.Errorf("expected a UUID, got %q", configMap.Data["tenant-id"])
+				}
+				if !regexp.MustCompile(`^[0-9a-f]{32}$`).MatchString(configMap.Data["trace-id"]) {
+					t.Errorf("expected 16 bytes as hex, got %q", configMap.Data["trace-id"])
+				}
+			},
+		},
+		{
+			name: "unsupported type",
+			annotations: map[string]string{
+				AnnotationAutogenerateID:    "ca",
+				AnnotationTypePrefix + "ca": config.TypeTLS,
+			},
+			expectError: "cannot be generated into a ConfigMap",
+		},
+		{
+			name:        "invalid field",
+			annotations: map[string]string{AnnotationAutogenerateID: "correlation id"},
+			expectError: `invalid field "correlation id"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "ids", Namespace: "default", Annotations: tt.annotations},
+				Data:       tt.data,
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build()
+			fakeRecorder := record.NewFakeRecorder(10)
+			reconciler := &ConfigMapIDReconciler{
+				Client:        fakeClient,
+				Generator:     generator.NewSecretGenerator(),
+				Config:        config.NewDefaultConfig(),
+				EventRecorder: fakeRecorder,
+			}
+
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: configMap.Name, Namespace: configMap.Namespace}}
+			if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var updated corev1.ConfigMap
+			if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+				t.Fatalf("failed to get ConfigMap: %v", err)
+			}
+			var event string
+			select {
+			case event = <-fakeRecorder.Events:
+			default:
+				t.Fatal("expected an event")
+			}
+			if tt.expectError != "" {
+				if !strings.Contains(event, EventReasonGenerationFailed) || !strings.Contains(event, tt.expectError) {
+					t.Errorf("expected %s event containing %q, got %q", EventReasonGenerationFailed, tt.expectError, event)
+				}
+				if updated.ResourceVersion != "999" {
+					t.Errorf("expected ConfigMap not to be updated, got resourceVersion %s", updated.ResourceVersion)
+				}
+				return
+			}
+			if !strings.Contains(event, EventReasonGenerationSucceeded) {
+				t.Errorf("expected %s event, got %q", EventReasonGenerationSucceeded, event)
+			}
+			tt.check(t, &updated)
+		})
+	}
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+const (
+	// DebugSecretsPath is the path of the debug endpoint on the metrics server
+	DebugSecretsPath = "/debug/secrets"
+
+	// workqueueDepthMetric is the controller-runtime metric holding the workqueue depth per controller
+	workqueueDepthMetric = "workqueue_depth"
+	// workqueueUnfinishedWorkMetric is the controller-runtime metric holding how long the
+	// reconciles in progress have been running per controller
+	workqueueUnfinishedWorkMetric = "workqueue_unfinished_work_seconds"
+	// activeWorkersMetric is the controller-runtime metric holding the number of running reconciles
+	// per controller. Its series are created when a controller starts.
+	activeWorkersMetric = "controller_runtime_active_workers"
+)
+
+// DebugState is the response of the debug endpoint
+type DebugState struct {
+	// Secrets lists the Secrets with generated values tracked by the operator
+	Secrets []DebugSecret `json:"secrets"`
+	// WorkqueueDepth is the number of pending reconcile requests per controller
+	WorkqueueDepth map[string]int `json:"workqueueDepth"`
+	// ReplicationFlows lists the namespace pairs Secrets are replicated across
+	ReplicationFlows []ReplicationFlow `json:"replicationFlows,omitempty"`
+}
+
+// DebugSecret describes the rotation state of a tracked Secret
+type DebugSecret struct {
+	Namespace      string   `json:"namespace"`
+	Name           string   `json:"name"`
+	Fields         []string `json:"fields"`
+	GeneratedAt    string   `json:"generatedAt,omitempty"`
+	NextRotation   string   `json:"nextRotation,omitempty"`
+	RotationDue    bool     `json:"rotationDue"`
+	RotationPaused bool     `json:"rotationPaused"`
+}
+
+// DebugHandler serves the rotation state of all tracked Secrets and the workqueue depth of the
+// controllers. It is served on the metrics server, so it is protected by the same authentication.
+type DebugHandler struct {
+	Reader client.Reader
+	Config *config.Config
+	// Gatherer provides the workqueue metrics. If nil, the controller-runtime registry is used.
+	Gatherer prometheus.Gatherer
+	// Clock is used to get the current time. If nil, time.Now() is used.
+	Clock Clock
+	// Flows provides the observed replication flows. If nil, no flows are listed.
+	Flows *ReplicationFlows
+}
+
+// ServeHTTP implements http.Handler. The optional "namespace" query parameter restricts the
+// listed Secrets to a single namespace, and the replication flows to flows from or into it.
+func (h *DebugHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	state, err := h.state(req)
+	if err != nil {
+		log.FromContext(req.Context()).Error(err, "Failed to collect debug state")
+		http.Error(w, "Failed to collect debug state", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(state)
+}
+
+// state collects the current debug state
+func (h *DebugHandler) state(req *http.Request) (*DebugState, error) {
+	ctx := req.Context()
+
+	var opts []client.ListOption
+	if namespace := req.URL.Query().Get("namespace"); namespace != "" {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+
+	var secrets corev1.SecretList
+	if err := h.Reader.List(ctx, &secrets, opts...); err != nil {
+		return nil, err
+	}
+
+	var namespaces corev1.NamespaceList
+	if err := h.Reader.List(ctx, &namespaces); err != nil {
+		return nil, err
+	}
+	paused := make(map[string]bool)
+	for i := range namespaces.Items {
+		paused[namespaces.Items[i].Name] = isRotationPaused(&namespaces.Items[i])
+	}
+
+	now := time.Now()
+	if h.Clock != nil {
+		now = h.Clock.Now()
+	}
+
+	rotation := &SecretReconciler{Config: h.Config}
+	state := &DebugState{Secrets: []DebugSecret{}}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		value, ok := secret.Annotations[AnnotationAutogenerate]
+		if !ok || h.Config.IsSecretTypeIgnored(string(secret.Type)) {
+			continue
+		}
+
+		entry := DebugSecret{
+			Namespace:      secret.Namespace,
+			Name:           secret.Name,
+			Fields:         parseFields(value),
+			GeneratedAt:    secret.Annotations[AnnotationGeneratedAt],
+			RotationPaused: paused[secret.Namespace],
+		}
+		if next := nextRotationTime(rotation, secret.Annotations, entry.Fields); next != nil {
+			entry.NextRotation = next.UTC().Format(time.RFC3339)
+			entry.RotationDue = !next.After(now)
+		}
+		state.Secrets = append(state.Secrets, entry)
+	}
+	sort.Slice(state.Secrets, func(i, j int) bool {
+		if state.Secrets[i].Namespace != state.Secrets[j].Namespace {
+			return state.Secrets[i].Namespace < state.Secrets[j].Namespace
+		}
+		return state.Secrets[i].Name < state.Secrets[j].Name
+	})
+
+	depth, err := gatherWorkqueueDepth(h.Gatherer)
+	if err != nil {
+		return nil, err
+	}
+	state.WorkqueueDepth = depth
+	state.ReplicationFlows = h.Flows.Flows(req.URL.Query().Get("namespace"))
+
+	return state, nil
+}
+
+// gatherWorkqueueDepth returns the number of pending reconcile requests per controller.
+// If gatherer is nil, the controller-runtime registry is used.
+func gatherWorkqueueDepth(gatherer prometheus.Gatherer) (map[string]int, error) {
+	families, err := gatherWorkqueueMetrics(gatherer)
+	if err != nil {
+		return nil, err
+	}
+
+	depth := make(map[string]int)
+	for name, value := range families[workqueueDepthMetric] {
+		depth[name] = int(value)
+	}
+	return depth, nil
+}
+
+// gatherWorkqueueMetrics returns the values of the controller-runtime workqueue gauges and
+// counters, and of the active workers gauge, by metric name and controller. Values of a controller with several series (e.g.
+// one per priority) are summed up. If gatherer is nil, the controller-runtime registry is used.
+func gatherWorkqueueMetrics(gatherer prometheus.Gatherer) (map[string]map[string]float64, error) {
+	if gatherer == nil {
+		gatherer = metrics.Registry
+	}
+
+	families, err := gatherer.Gather()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]map[string]float64)
+	for _, family := range families {
+		if !strings.HasPrefix(family.GetName(), "workqueue_") && family.GetName() != activeWorkersMetric {
+			continue
+		}
+		values := make(map[string]float64)
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "controller" {
+					values[label.GetValue()] += metric.GetGauge().GetValue() + metric.GetCounter().GetValue()
+				}
+			}
+		}
+		result[family.GetName()] = values
+	}
+	return result, nil
+}
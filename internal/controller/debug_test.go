@@ -0,0 +1,118 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+func TestDebugHandler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	generatedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	objs := []client.Object{
+		pausedNamespace("frozen"),
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: "due", Namespace: "frozen",
+			Annotations: map[string]string{
+				AnnotationAutogenerate: "password",
+				AnnotationRotate:       "1h",
+				AnnotationGeneratedAt:  generatedAt.Format(time.RFC3339),
+			},
+		}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: "pending", Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate: "password",
+				AnnotationRotate:       "7d",
+				AnnotationGeneratedAt:  generatedAt.Format(time.RFC3339),
+			},
+		}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "manual", Namespace: "default"}},
+	}
+
+	registry := prometheus.NewRegistry()
+	depth := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: workqueueDepthMetric},
+		[]string{"name", "controller", "priority"})
+	registry.MustRegister(depth)
+	depth.WithLabelValues("secret-generator", "secret-generator", "").Set(3)
+
+	handler := &DebugHandler{
+		Reader:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		Config:   config.NewDefaultConfig(),
+		Gatherer: registry,
+		Clock:    &MockClock{currentTime: generatedAt.Add(2 * time.Hour)},
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, DebugSecretsPath, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	var state DebugState
+	if err := json.Unmarshal(rec.Body.Bytes(), &state); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(state.Secrets) != 2 {
+		t.Fatalf("expected 2 tracked Secrets, got %+v", state.Secrets)
+	}
+	pending, due := state.Secrets[0], state.Secrets[1]
+	if pending.Name != "pending" || pending.RotationDue || pending.RotationPaused ||
+		pending.NextRotation != generatedAt.Add(7*24*time.Hour).Format(time.RFC3339) {
+		t.Errorf("unexpected state for pending Secret: %+v", pending)
+	}
+	if due.Name != "due" || !due.RotationDue || !due.RotationPaused {
+		t.Errorf("expected due Secret to be due and paused, got %+v", due)
+	}
+	if state.WorkqueueDepth["secret-generator"] != 3 {
+		t.Errorf("expected workqueue depth 3, got %v", state.WorkqueueDepth)
+	}
+
+	// Filter by namespace
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, DebugSecretsPath+"?namespace=default", nil))
+	state = DebugState{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &state); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(state.Secrets) != 1 || state.Secrets[0].Name != "pending" {
+		t.Errorf("expected only the Secret in the default namespace, got %+v", state.Secrets)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, DebugSecretsPath, nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405 for POST, got %d", rec.Code)
+	}
+}
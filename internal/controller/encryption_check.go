@@ -0,0 +1,183 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+const (
+	// MetricEncryptionAtRest is the name of the gauge with the result of the encryption-at-rest
+	// posture check; the series of the current state is 1, all others are 0
+	MetricEncryptionAtRest = "secret_operator_encryption_at_rest"
+
+	// EncryptionAtRestEnabled means the API server stores Secrets through an encryption provider
+	EncryptionAtRestEnabled = "enabled"
+	// EncryptionAtRestDisabled means the API server stores Secrets unencrypted (identity provider)
+	EncryptionAtRestDisabled = "disabled"
+	// EncryptionAtRestUnknown means the check could not determine the state, e.g. because the
+	// API server metrics are not accessible, do not report the resource or no Secret has been
+	// written yet
+	EncryptionAtRestUnknown = "unknown"
+
+	// storageTransformationMetric is the API server counter of storage transformations, labelled
+	// with the prefix of the transformer (provider) that was used
+	storageTransformationMetric = "apiserver_storage_transformation_operations_total"
+
+	// identityTransformerPrefix is the transformer prefix of unencrypted storage
+	identityTransformerPrefix = "identity"
+)
+
+var encryptionAtRest = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: MetricEncryptionAtRest,
+	Help: "Whether the API server appears to encrypt Secrets at rest (state: enabled, disabled or unknown)",
+}, []string{"state"})
+
+// EncryptionCheck periodically checks whether the API server encrypts Secrets at rest, exports
+// the result and logs a warning if it does not, since generated credentials would then be stored
+// in etcd in plain text. The check is best-effort: it reads the storage transformation metrics of
+// the API server, which report the encryption providers used to write Secrets since that API
+// server instance started. It is disabled by default.
+type EncryptionCheck struct {
+	Config *config.Config
+	// Client requests the API server metrics
+	Client rest.Interface
+
+	state string
+}
+
+// SetupWithManager registers the metric and adds the check to the Manager
+func (c *EncryptionCheck) SetupWithManager(mgr ctrl.Manager) error {
+	if err := registerCollector(encryptionAtRest); err != nil {
+		return err
+	}
+	return mgr.Add(c)
+}
+
+// NeedLeaderElection returns false, so that every replica exports the result
+func (c *EncryptionCheck) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements manager.Runnable
+func (c *EncryptionCheck) Start(ctx context.Context) error {
+	ticker := time.NewTicker(c.Config.EncryptionCheck.Interval.Duration())
+	defer ticker.Stop()
+
+	for {
+		c.check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// check determines the encryption-at-rest state, updates the metric and logs when the state changes
+func (c *EncryptionCheck) check(ctx context.Context) {
+	logger := log.FromContext(ctx)
+
+	state, err := c.probe(ctx)
+	if err != nil {
+		logger.V(1).Info("Failed to read API server metrics for the encryption-at-rest check", "error", err.Error())
+	}
+	for _, s := range []string{EncryptionAtRestEnabled, EncryptionAtRestDisabled, EncryptionAtRestUnknown} {
+		value := 0.0
+		if s == state {
+			value = 1
+		}
+		encryptionAtRest.WithLabelValues(s).Set(value)
+	}
+
+	if state == c.state {
+		return
+	}
+	c.state = state
+	switch state {
+	case EncryptionAtRestDisabled:
+		logger.Info("Encryption at rest: Secrets are not encrypted; generated credentials are stored in etcd in plain text. " +
+			"Configure an EncryptionConfiguration for secrets on the API server.")
+	case EncryptionAtRestUnknown:
+		logger.Info("Encryption at rest: could not determine whether Secrets are encrypted")
+	default:
+		logger.Info("Encryption at rest: Secrets are encrypted")
+	}
+}
+
+// probe reads the API server metrics and returns the encryption-at-rest state
+func (c *EncryptionCheck) probe(ctx context.Context) (string, error) {
+	data, err := c.Client.Get().AbsPath("/metrics").DoRaw(ctx)
+	if err != nil {
+		return EncryptionAtRestUnknown, err
+	}
+	return encryptionStateFromMetrics(data)
+}
+
+// encryptionStateFromMetrics derives the encryption-at-rest state from the storage transformation
+// metrics of the API server. Secrets count as unencrypted if any successful write of a Secret used
+// the identity transformer, and as encrypted if all of them used another transformer. The counters
+// cover the time since the API server started, so a provider change is only reflected after its
+// restart. API servers that do not label the metric with the resource report unknown, since the
+// writes of Secrets cannot be told apart from those of other resources.
+func encryptionStateFromMetrics(data []byte) (string, error) {
+	parser := expfmt.NewTextParser(model.UTF8Validation)
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(data))
+	if err != nil {
+		return EncryptionAtRestUnknown, fmt.Errorf("failed to parse API server metrics: %w", err)
+	}
+
+	family, ok := families[storageTransformationMetric]
+	if !ok {
+		return EncryptionAtRestUnknown, nil
+	}
+
+	state := EncryptionAtRestUnknown
+	for _, metric := range family.GetMetric() {
+		labels := make(map[string]string)
+		for _, label := range metric.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		if labels["transformation_type"] != "to_storage" || metric.GetCounter().GetValue() == 0 {
+			continue
+		}
+		if status, ok := labels["status"]; ok && status != "OK" {
+			continue
+		}
+		if labels["resource"] != "secrets" {
+			continue
+		}
+		if labels["transformer_prefix"] == identityTransformerPrefix {
+			return EncryptionAtRestDisabled, nil
+		}
+		state = EncryptionAtRestEnabled
+	}
+	return state, nil
+}
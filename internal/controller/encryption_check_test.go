@@ -0,0 +1,146 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+const storageTransformationHeader = `# HELP apiserver_storage_transformation_operations_total [ALPHA] Total number of transformations.
+# TYPE apiserver_storage_transformation_operations_total counter
+`
+
+func TestEncryptionStateFromMetrics(t *testing.T) {
+	tests := []struct {
+		name     string
+		metrics  string
+		expected string
+	}{
+		{
+			name: "encrypted",
+			metrics: storageTransformationHeader +
+				`apiserver_storage_transformation_operations_total{resource="secrets",status="OK",transformation_type="to_storage",transformer_prefix="k8s:enc:aescbc:v1:"} 3
+apiserver_storage_transformation_operations_total{resource="configmaps",status="OK",transformation_type="to_storage",transformer_prefix="identity"} 5
+`,
+			expected: EncryptionAtRestEnabled,
+		},
+		{
+			name: "only other resources encrypted",
+			metrics: storageTransformationHeader +
+				`apiserver_storage_transformation_operations_total{resource="configmaps",status="OK",transformation_type="to_storage",transformer_prefix="k8s:enc:aescbc:v1:"} 3
+apiserver_storage_transformation_operations_total{resource="secrets",status="OK",transformation_type="to_storage",transformer_prefix="identity"} 5
+`,
+			expected: EncryptionAtRestDisabled,
+		},
+		{
+			name: "some Secrets written unencrypted",
+			metrics: storageTransformationHeader +
+				`apiserver_storage_transformation_operations_total{resource="secrets",status="OK",transformation_type="to_storage",transformer_prefix="k8s:enc:aescbc:v1:"} 3
+apiserver_storage_transformation_operations_total{resource="secrets",status="OK",transformation_type="to_storage",transformer_prefix="identity"} 1
+`,
+			expected: EncryptionAtRestDisabled,
+		},
+		{
+			name: "failed writes",
+			metrics: storageTransformationHeader +
+				`apiserver_storage_transformation_operations_total{resource="secrets",status="OK",transformation_type="to_storage",transformer_prefix="k8s:enc:aescbc:v1:"} 3
+apiserver_storage_transformation_operations_total{resource="secrets",status="Unknown",transformation_type="to_storage",transformer_prefix="identity"} 1
+`,
+			expected: EncryptionAtRestEnabled,
+		},
+		{
+			name: "without resource label",
+			metrics: storageTransformationHeader +
+				`apiserver_storage_transformation_operations_total{status="OK",transformation_type="to_storage",transformer_prefix="identity"} 5
+apiserver_storage_transformation_operations_total{status="OK",transformation_type="to_storage",transformer_prefix="k8s:enc:aescbc:v1:"} 1
+`,
+			expected: EncryptionAtRestUnknown,
+		},
+		{
+			name: "no writes",
+			metrics: storageTransformationHeader +
+				`apiserver_storage_transformation_operations_total{resource="secrets",status="OK",transformation_type="to_storage",transformer_prefix="identity"} 0
+`,
+			expected: EncryptionAtRestUnknown,
+		},
+		{
+			name:     "metric missing",
+			metrics:  "# TYPE apiserver_request_total counter\napiserver_request_total 1\n",
+			expected: EncryptionAtRestUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, err := encryptionStateFromMetrics([]byte(tt.metrics))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if state != tt.expected {
+				t.Errorf("expected state %s, got %s", tt.expected, state)
+			}
+		})
+	}
+}
+
+func TestEncryptionCheck(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metrics" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(storageTransformationHeader +
+			`apiserver_storage_transformation_operations_total{resource="secrets",status="OK",transformation_type="to_storage",transformer_prefix="identity"} 2
+`))
+	}))
+	defer server.Close()
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create discovery client: %v", err)
+	}
+	check := &EncryptionCheck{Config: config.NewDefaultConfig(), Client: discoveryClient.RESTClient()}
+	ctx := context.Background()
+
+	check.check(ctx)
+	if value := gaugeValue(t, encryptionAtRest, EncryptionAtRestDisabled); value != 1 {
+		t.Errorf("expected disabled state to be 1, got %v", value)
+	}
+	if value := gaugeValue(t, encryptionAtRest, EncryptionAtRestEnabled); value != 0 {
+		t.Errorf("expected enabled state to be 0, got %v", value)
+	}
+
+	// Without access to the API server metrics, the state is unknown
+	status = http.StatusForbidden
+	check.check(ctx)
+	if value := gaugeValue(t, encryptionAtRest, EncryptionAtRestUnknown); value != 1 {
+		t.Errorf("expected unknown state to be 1, got %v", value)
+	}
+	if value := gaugeValue(t, encryptionAtRest, EncryptionAtRestDisabled); value != 0 {
+		t.Errorf("expected disabled state to be 0, got %v", value)
+	}
+}
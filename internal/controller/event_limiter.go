@@ -0,0 +1,174 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	// MetricEventsDroppedTotal is the name of the counter of Events dropped by the event limits
+	MetricEventsDroppedTotal = "secret_operator_events_dropped_total"
+
+	// eventLimiterWindow is the sliding window used by the EventLimiter
+	eventLimiterWindow = time.Hour
+)
+
+// eventsDroppedTotal counts the Events that were not emitted because of the event limits
+var eventsDroppedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: MetricEventsDroppedTotal,
+	Help: "Number of Kubernetes Events dropped by the per-Secret or cluster-wide event limits, by type and reason",
+}, []string{"type", "reason"})
+
+// EventLimiter limits how many Events may be emitted per hour, both cluster-wide and per
+// object. This keeps a Secret that fails on every reconcile from exhausting the Event budget
+// of its namespace. It is shared by the Event recorders of all controllers.
+// A nil EventLimiter allows all Events.
+type EventLimiter struct {
+	mu           sync.Mutex
+	clock        Clock
+	maxPerWindow int
+	maxPerObject int
+	cluster      []time.Time
+	objects      map[string][]time.Time
+	lastSweep    time.Time
+}
+
+// NewEventLimiter creates an EventLimiter allowing at most maxPerHour Events cluster-wide and
+// maxPerObjectPerHour Events per object (0 = unlimited). It returns nil if both limits are disabled.
+func NewEventLimiter(maxPerHour, maxPerObjectPerHour int) *EventLimiter {
+	if maxPerHour <= 0 && maxPerObjectPerHour <= 0 {
+		return nil
+	}
+	return &EventLimiter{
+		clock:        RealClock{},
+		maxPerWindow: maxPerHour,
+		maxPerObject: maxPerObjectPerHour,
+		objects:      make(map[string][]time.Time),
+	}
+}
+
+// Allow records an Event for the given object if the limits allow it
+func (l *EventLimiter) Allow(object runtime.Object) bool {
+	if l == nil {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	l.sweep(now)
+
+	l.cluster = pruneWindow(l.cluster, now, eventLimiterWindow)
+	if l.maxPerWindow > 0 && len(l.cluster) >= l.maxPerWindow {
+		return false
+	}
+
+	key := eventObjectKey(object)
+	objectEvents := pruneWindow(l.objects[key], now, eventLimiterWindow)
+	if l.maxPerObject > 0 && len(objectEvents) >= l.maxPerObject {
+		l.objects[key] = objectEvents
+		return false
+	}
+
+	l.cluster = append(l.cluster, now)
+	l.objects[key] = append(objectEvents, now)
+	return true
+}
+
+// sweep removes objects without Events in the window, so that deleted objects do not
+// accumulate. It runs at most once per window.
+func (l *EventLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < eventLimiterWindow {
+		return
+	}
+	l.lastSweep = now
+	for key, events := range l.objects {
+		if events = pruneWindow(events, now, eventLimiterWindow); events == nil {
+			delete(l.objects, key)
+		} else {
+			l.objects[key] = events
+		}
+	}
+}
+
+// eventObjectKey identifies the object an Event refers to
+func eventObjectKey(object runtime.Object) string {
+	obj := activityObject(object)
+	return fmt.Sprintf("%s/%s/%s", obj.Kind, obj.Namespace, obj.Name)
+}
+
+// limitedEventRecorder is an EventRecorder that drops Events exceeding the limits of an
+// EventLimiter and counts them in eventsDroppedTotal
+type limitedEventRecorder struct {
+	record.EventRecorder
+	limiter *EventLimiter
+}
+
+// NewLimitedEventRecorder wraps an EventRecorder so that its Events are subject to the limiter.
+// The recorder is returned unchanged if the limiter is nil.
+func NewLimitedEventRecorder(recorder record.EventRecorder, limiter *EventLimiter) (record.EventRecorder, error) {
+	if limiter == nil {
+		return recorder, nil
+	}
+	if err := registerCollector(eventsDroppedTotal); err != nil {
+		return nil, err
+	}
+	return &limitedEventRecorder{EventRecorder: recorder, limiter: limiter}, nil
+}
+
+// allow returns true if the Event may be emitted, and counts it as dropped otherwise
+func (r *limitedEventRecorder) allow(object runtime.Object, eventtype, reason string) bool {
+	if r.limiter.Allow(object) {
+		return true
+	}
+	eventsDroppedTotal.WithLabelValues(eventtype, reason).Inc()
+	return false
+}
+
+// Event implements record.EventRecorder
+func (r *limitedEventRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if r.allow(object, eventtype, reason) {
+		r.EventRecorder.Event(object, eventtype, reason, message)
+	}
+}
+
+// Eventf implements record.EventRecorder
+func (r *limitedEventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	if r.allow(object, eventtype, reason) {
+		r.EventRecorder.Eventf(object, eventtype, reason, messageFmt, args...)
+	}
+}
+
+// AnnotatedEventf implements record.EventRecorder
+func (r *limitedEventRecorder) AnnotatedEventf(
+	object runtime.Object,
+	annotations map[string]string,
+	eventtype, reason, messageFmt string,
+	args ...interface{},
+) {
+	if r.allow(object, eventtype, reason) {
+		r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestNewEventLimiterDisabled(t *testing.T) {
+	if l := NewEventLimiter(0, 0); l != nil {
+		t.Error("expected nil limiter when both limits are disabled")
+	}
+
+	// A nil limiter allows everything
+	var l *EventLimiter
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "s", Namespace: "ns"}}
+	for i := 0; i < 100; i++ {
+		if !l.Allow(secret) {
+			t.Fatal("expected nil limiter to allow all Events")
+		}
+	}
+
+	recorder := record.NewFakeRecorder(10)
+	wrapped, err := NewLimitedEventRecorder(recorder, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if wrapped != recorder {
+		t.Error("expected recorder to be returned unchanged without limiter")
+	}
+}
+
+func TestEventLimiter(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &MockClock{currentTime: start}
+	l := NewEventLimiter(3, 2)
+	l.clock = clock
+
+	noisy := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "noisy", Namespace: "ns"}}
+	other := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "ns"}}
+	sameName := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "noisy", Namespace: "ns"}}
+
+	// The per-object limit only affects the noisy Secret
+	for i := 0; i < 2; i++ {
+		if !l.Allow(noisy) {
+			t.Fatalf("expected Event %d of the noisy Secret to be allowed", i+1)
+		}
+	}
+	if l.Allow(noisy) {
+		t.Error("expected Event beyond the per-object limit to be dropped")
+	}
+	if !l.Allow(sameName) {
+		t.Error("expected Event of another kind with the same name to be allowed")
+	}
+
+	// The cluster-wide limit is reached
+	if l.Allow(other) {
+		t.Error("expected Event beyond the cluster-wide limit to be dropped")
+	}
+
+	// Events are allowed again once the earlier ones left the window
+	clock.currentTime = start.Add(time.Hour + time.Second)
+	if !l.Allow(noisy) || !l.Allow(other) {
+		t.Error("expected Events to be allowed after the window passed")
+	}
+	if _, ok := l.objects["ServiceAccount/ns/noisy"]; ok {
+		t.Error("expected objects without Events in the window to be removed")
+	}
+}
+
+func TestLimitedEventRecorder(t *testing.T) {
+	fakeRecorder := record.NewFakeRecorder(10)
+	recorder, err := NewLimitedEventRecorder(fakeRecorder, NewEventLimiter(0, 1))
+	if err != nil {
+		t.Fatalf("failed to create recorder: %v", err)
+	}
+
+	counter := eventsDroppedTotal.WithLabelValues(corev1.EventTypeWarning, EventReasonGenerationFailed)
+	var before dto.Metric
+	if err := counter.Write(&before); err != nil {
+		t.Fatalf("failed to write metric: %v", err)
+	}
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "s", Namespace: "ns"}}
+	recorder.Event(secret, corev1.EventTypeWarning, EventReasonGenerationFailed, "failed")
+	recorder.Eventf(secret, corev1.EventTypeWarning, EventReasonGenerationFailed, "failed %d", 2)
+	recorder.AnnotatedEventf(secret, nil, corev1.EventTypeWarning, EventReasonGenerationFailed, "failed %d", 3)
+
+	if len(fakeRecorder.Events) != 1 {
+		t.Errorf("expected 1 Event to be recorded, got %d", len(fakeRecorder.Events))
+	}
+
+	var after dto.Metric
+	if err := counter.Write(&after); err != nil {
+		t.Fatalf("failed to write metric: %v", err)
+	}
+	if got := after.GetCounter().GetValue() - before.GetCounter().GetValue(); got != 2 {
+		t.Errorf("expected 2 dropped Events to be counted, got %v", got)
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// FanOutLimiter spreads the reconciles triggered by a single replication source over time.
+// Each source has its own token bucket holding one second worth of requests: the first targets
+// are enqueued immediately, the remaining ones are delayed to the configured rate. Targets of
+// other sources are not affected, so one source fanning out to many namespaces cannot starve
+// the sync of other sources. A nil FanOutLimiter enqueues all targets immediately.
+type FanOutLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	// sources holds the theoretical arrival time of the next request of each source; sources
+	// whose time has passed have a full bucket and are removed
+	sources map[string]time.Time
+}
+
+// NewFanOutLimiter creates a FanOutLimiter enqueuing at most perSecond targets of each source
+// per second. It returns nil if perSecond is not positive.
+func NewFanOutLimiter(perSecond int) *FanOutLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &FanOutLimiter{
+		interval: time.Second / time.Duration(perSecond),
+		sources:  make(map[string]time.Time),
+	}
+}
+
+// Delays returns how long each of n targets of the source must wait before it is enqueued
+func (l *FanOutLimiter) Delays(source string, n int, now time.Time) []time.Duration {
+	delays := make([]time.Duration, n)
+	if l == nil {
+		return delays
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for key, next := range l.sources {
+		if !next.After(now) {
+			delete(l.sources, key)
+		}
+	}
+
+	// The bucket holds one second worth of requests
+	tolerance := time.Second - l.interval
+	next, ok := l.sources[source]
+	if !ok {
+		next = now
+	}
+	for i := range delays {
+		if delay := next.Sub(now) - tolerance; delay > 0 {
+			delays[i] = delay
+		}
+		next = next.Add(l.interval)
+	}
+	l.sources[source] = next
+	return delays
+}
+
+// fanOutHandler enqueues the targets of a source, delayed by the FanOutLimiter
+type fanOutHandler struct {
+	limiter *FanOutLimiter
+	// targets returns the targets of the source
+	targets handler.MapFunc
+	now     func() time.Time
+	// checkpoint skips sources of the initial list that did not change since the checkpoint of
+	// the previous leader, since their targets are in the initial list themselves. May be nil.
+	checkpoint *ResyncCheckpoint
+}
+
+// Create implements handler.EventHandler
+func (h *fanOutHandler) Create(ctx context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	if e.IsInInitialList && h.checkpoint.unchangedSinceCheckpoint(ctx, e.Object) {
+		return
+	}
+	h.enqueue(ctx, q, e.Object)
+}
+
+// Update implements handler.EventHandler
+func (h *fanOutHandler) Update(ctx context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.enqueue(ctx, q, e.ObjectOld, e.ObjectNew)
+}
+
+// Delete implements handler.EventHandler
+func (h *fanOutHandler) Delete(ctx context.Context, e event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.enqueue(ctx, q, e.Object)
+}
+
+// Generic implements handler.EventHandler
+func (h *fanOutHandler) Generic(ctx context.Context, e event.GenericEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.enqueue(ctx, q, e.Object)
+}
+
+// enqueue adds the targets of the objects (old and new version of the same source) to the queue,
+// delaying those beyond the source's rate
+func (h *fanOutHandler) enqueue(
+	ctx context.Context,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+	objs ...client.Object,
+) {
+	var requests []reconcile.Request
+	seen := make(map[reconcile.Request]bool)
+	for _, obj := range objs {
+		for _, req := range h.targets(ctx, obj) {
+			if !seen[req] {
+				seen[req] = true
+				requests = append(requests, req)
+			}
+		}
+	}
+	if len(requests) == 0 {
+		return
+	}
+	source := objs[len(objs)-1]
+	key := fmt.Sprintf("%T %s/%s", source, source.GetNamespace(), source.GetName())
+	delays := h.limiter.Delays(key, len(requests), h.now())
+	for i, req := range requests {
+		if delays[i] > 0 {
+			q.AddAfter(req, delays[i])
+		} else {
+			q.Add(req)
+		}
+	}
+}
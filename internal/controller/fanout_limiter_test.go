@@ -0,0 +1,101 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestNewFanOutLimiterDisabled(t *testing.T) {
+	if l := NewFanOutLimiter(0); l != nil {
+		t.Error("expected nil limiter when the rate is disabled")
+	}
+
+	var l *FanOutLimiter
+	for _, delay := range l.Delays("source", 100, time.Now()) {
+		if delay != 0 {
+			t.Fatal("expected nil limiter not to delay targets")
+		}
+	}
+}
+
+func TestFanOutLimiterDelays(t *testing.T) {
+	l := NewFanOutLimiter(10)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// One second worth of targets is enqueued immediately, the rest at 10 per second
+	delays := l.Delays("a", 30, now)
+	for i, delay := range delays {
+		expected := time.Duration(0)
+		if i >= 10 {
+			expected = time.Duration(i-9) * 100 * time.Millisecond
+		}
+		if delay != expected {
+			t.Errorf("expected delay %v for target %d, got %v", expected, i, delay)
+		}
+	}
+
+	// Other sources are not delayed by source a
+	if delay := l.Delays("b", 1, now)[0]; delay != 0 {
+		t.Errorf("expected other source not to be delayed, got %v", delay)
+	}
+
+	// A second change of source a queues up behind its first fan-out
+	if delay := l.Delays("a", 1, now)[0]; delay != 2100*time.Millisecond {
+		t.Errorf("expected delay %v, got %v", 2100*time.Millisecond, delay)
+	}
+
+	// Once the backlog has drained, the bucket is full again
+	if delay := l.Delays("a", 10, now.Add(time.Minute))[9]; delay != 0 {
+		t.Errorf("expected full bucket after the backlog drained, got %v", delay)
+	}
+}
+
+func TestFanOutHandlerDelaysTargets(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	targets := func(_ context.Context, obj client.Object) []reconcile.Request {
+		var requests []reconcile.Request
+		for _, ns := range []string{"ns-1", "ns-2", "ns-3"} {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: obj.GetName()}})
+		}
+		return requests
+	}
+	h := &fanOutHandler{limiter: NewFanOutLimiter(2), targets: targets, now: func() time.Time { return now }}
+
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+
+	source := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default", ResourceVersion: "2"}}
+	old := source.DeepCopy()
+	old.ResourceVersion = "1"
+	h.Update(context.Background(), event.UpdateEvent{ObjectOld: old, ObjectNew: source}, q)
+
+	// The old and new version map to the same targets, only the third one is delayed
+	if q.Len() != 2 {
+		t.Errorf("expected 2 targets to be enqueued immediately, got %d", q.Len())
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Heartbeat records the last completed reconcile of each controller, so that the liveness
+// check can detect controllers that stopped processing their workqueue. Failed reconciles count
+// as well: a controller that keeps failing, e.g. while the API server is unavailable, still
+// processes its queue and is not fixed by a restart.
+type Heartbeat struct {
+	// Gatherer provides the workqueue metrics. If nil, the controller-runtime registry is used.
+	Gatherer prometheus.Gatherer
+	// Clock is used to get the current time. If nil, time.Now() is used.
+	Clock Clock
+
+	mu sync.Mutex
+	// last holds the time of the last completed reconcile per controller while its queue is
+	// non-empty, or the time the queue was first seen non-empty if no reconcile completed since.
+	// It is reset when the queue drains, so a reconcile before an idle period does not count.
+	last map[string]time.Time
+	// controllers holds the names of the controllers reporting to the heartbeat
+	controllers map[string]bool
+}
+
+// NewHeartbeat returns an empty Heartbeat
+func NewHeartbeat() *Heartbeat {
+	return &Heartbeat{
+		last:        make(map[string]time.Time),
+		controllers: make(map[string]bool),
+	}
+}
+
+// now returns the current time using the configured clock
+func (h *Heartbeat) now() time.Time {
+	if h.Clock == nil {
+		return time.Now()
+	}
+	return h.Clock.Now()
+}
+
+// wrap returns a Reconciler that reports completed reconciles of the named controller.
+// A nil Heartbeat returns the reconciler unchanged.
+func (h *Heartbeat) wrap(name string, reconciler reconcile.Reconciler) reconcile.Reconciler {
+	if h == nil {
+		return reconciler
+	}
+
+	h.mu.Lock()
+	h.controllers[name] = true
+	h.mu.Unlock()
+
+	return reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		// Deferred, so that a reconcile that panics and is recovered by the controller counts as well
+		defer h.beat(name)
+		return reconciler.Reconcile(ctx, req)
+	})
+}
+
+// beat records a completed reconcile of the named controller
+func (h *Heartbeat) beat(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.last[name] = h.now()
+}
+
+// Checker returns a health check that fails if a controller has pending reconcile requests
+// but has not completed a reconcile for longer than timeout
+func (h *Heartbeat) Checker(timeout time.Duration) healthz.Checker {
+	return func(_ *http.Request) error {
+		depth, err := gatherWorkqueueDepth(h.Gatherer)
+		if err != nil {
+			return fmt.Errorf("failed to gather workqueue depth: %w", err)
+		}
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		now := h.now()
+		var stalled []string
+		for name := range h.controllers {
+			if depth[name] == 0 {
+				delete(h.last, name)
+				continue
+			}
+			last, ok := h.last[name]
+			if !ok {
+				// Start measuring when the queue of a controller is first seen non-empty
+				h.last[name] = now
+				continue
+			}
+			if since := now.Sub(last); since > timeout {
+				stalled = append(stalled, fmt.Sprintf("%s (%d pending, last reconcile %s ago)",
+					name, depth[name], since.Truncate(time.Second)))
+			}
+		}
+
+		if len(stalled) > 0 {
+			sort.Strings(stalled)
+			return fmt.Errorf("controllers stalled: %s", strings.Join(stalled, ", "))
+		}
+		return nil
+	}
+}
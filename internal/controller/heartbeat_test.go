@@ -0,0 +1,104 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestHeartbeatChecker(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	depth := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: workqueueDepthMetric},
+		[]string{"name", "controller", "priority"})
+	registry.MustRegister(depth)
+
+	clock := &MockClock{currentTime: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	heartbeat := NewHeartbeat()
+	heartbeat.Gatherer = registry
+	heartbeat.Clock = clock
+
+	fail := false
+	reconciler := heartbeat.wrap("secret-generator", reconcile.Func(func(context.Context, ctrl.Request) (ctrl.Result, error) {
+		if fail {
+			return ctrl.Result{}, errors.New("reconcile failed")
+		}
+		return ctrl.Result{}, nil
+	}))
+	check := heartbeat.Checker(10 * time.Minute)
+
+	// An empty queue is healthy, even without any reconcile
+	clock.currentTime = clock.currentTime.Add(time.Hour)
+	if err := check(nil); err != nil {
+		t.Errorf("expected empty queue to be healthy, got %v", err)
+	}
+
+	// A non-empty queue starts the timeout
+	depth.WithLabelValues("secret-generator", "secret-generator", "").Set(5)
+	if err := check(nil); err != nil {
+		t.Errorf("expected controller to be healthy when its queue is first seen non-empty, got %v", err)
+	}
+	clock.currentTime = clock.currentTime.Add(5 * time.Minute)
+	if _, err := reconciler.Reconcile(context.Background(), ctrl.Request{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Failed reconciles are recorded, the controller still processes its queue
+	fail = true
+	clock.currentTime = clock.currentTime.Add(9 * time.Minute)
+	_, _ = reconciler.Reconcile(context.Background(), ctrl.Request{})
+	clock.currentTime = clock.currentTime.Add(9 * time.Minute)
+	if err := check(nil); err != nil {
+		t.Errorf("expected failing controller to be healthy within the timeout, got %v", err)
+	}
+
+	clock.currentTime = clock.currentTime.Add(2 * time.Minute)
+	err := check(nil)
+	if err == nil || !strings.Contains(err.Error(), "secret-generator (5 pending") {
+		t.Errorf("expected stalled controller to fail the check, got %v", err)
+	}
+
+	// A drained queue resets the timeout, the last reconcile before an idle period does not count
+	depth.WithLabelValues("secret-generator", "secret-generator", "").Set(0)
+	if err := check(nil); err != nil {
+		t.Errorf("expected empty queue to be healthy, got %v", err)
+	}
+	clock.currentTime = clock.currentTime.Add(time.Hour)
+	depth.WithLabelValues("secret-generator", "secret-generator", "").Set(1)
+	if err := check(nil); err != nil {
+		t.Errorf("expected controller to be healthy when its queue fills again after an idle period, got %v", err)
+	}
+}
+
+func TestHeartbeatNil(t *testing.T) {
+	var heartbeat *Heartbeat
+	called := false
+	reconciler := heartbeat.wrap("secret-generator", reconcile.Func(func(context.Context, ctrl.Request) (ctrl.Result, error) {
+		called = true
+		return ctrl.Result{}, nil
+	}))
+	if _, err := reconciler.Reconcile(context.Background(), ctrl.Request{}); err != nil || !called {
+		t.Errorf("expected nil heartbeat to call the reconciler, got called=%v err=%v", called, err)
+	}
+}
@@ -0,0 +1,311 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+const (
+	// LabelInventory marks the inventory ConfigMaps maintained by the operator
+	LabelInventory = AnnotationPrefix + "inventory"
+
+	// AnnotationInventoryUpdatedAt records when the content of an inventory ConfigMap last changed
+	AnnotationInventoryUpdatedAt = AnnotationPrefix + "inventory-updated-at"
+
+	// InventoryDataKey is the ConfigMap key holding the JSON inventory
+	InventoryDataKey = "inventory.json"
+)
+
+// Inventory summarizes the operator-managed Secrets of a namespace
+type Inventory struct {
+	Counts  InventoryCounts   `json:"counts"`
+	Secrets []InventorySecret `json:"secrets"`
+}
+
+// InventoryCounts holds the number of operator-managed Secrets of a namespace by kind of management
+type InventoryCounts struct {
+	// Generated is the number of Secrets with generated values
+	Generated int `json:"generated"`
+	// Rotating is the number of Secrets with generated values that are rotated
+	Rotating int `json:"rotating"`
+	// Replicated is the number of Secrets replicated from another Secret
+	Replicated int `json:"replicated"`
+	// PushSources is the number of Secrets pushed to other namespaces
+	PushSources int `json:"pushSources"`
+}
+
+// InventorySecret summarizes a single operator-managed Secret
+type InventorySecret struct {
+	Name           string   `json:"name"`
+	Fields         []string `json:"fields,omitempty"`
+	GeneratedAt    string   `json:"generatedAt,omitempty"`
+	NextRotation   string   `json:"nextRotation,omitempty"`
+	ReplicatedFrom string   `json:"replicatedFrom,omitempty"`
+	ReplicateTo    string   `json:"replicateTo,omitempty"`
+}
+
+// InventoryReporter periodically writes a ConfigMap per namespace that summarizes the
+// operator-managed Secrets, so that dashboards do not need cluster-wide read access to Secrets
+type InventoryReporter struct {
+	client.Client
+	// APIReader reads the inventory ConfigMaps directly from the API server,
+	// so that the operator does not cache all ConfigMaps of the cluster
+	APIReader client.Reader
+	Config    *config.Config
+	// Clock is used to get the current time. If nil, time.Now() is used.
+	Clock Clock
+	// Shard restricts the inventory to the namespaces of one shard. If nil, all namespaces are processed.
+	Shard *Shard
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;create;update;delete
+
+// SetupWithManager adds the reporter to the Manager. It only runs on the leader.
+func (r *InventoryReporter) SetupWithManager(mgr ctrl.Manager) error {
+	return mgr.Add(r)
+}
+
+// Start implements manager.Runnable
+func (r *InventoryReporter) Start(ctx context.Context) error {
+	interval := time.Duration(r.Config.Inventory.Interval)
+	if interval <= 0 {
+		interval = config.DefaultInventoryInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.update(ctx); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to update inventory")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// now returns the current time using the configured clock
+func (r *InventoryReporter) now() time.Time {
+	if r.Clock == nil {
+		return time.Now()
+	}
+	return r.Clock.Now()
+}
+
+// update writes the inventory ConfigMaps of all namespaces with operator-managed Secrets
+// and removes the inventory ConfigMaps of namespaces without
+func (r *InventoryReporter) update(ctx context.Context) error {
+	var secrets corev1.SecretList
+	if err := r.List(ctx, &secrets); err != nil {
+		return fmt.Errorf("failed to list Secrets: %w", err)
+	}
+	inventories := r.buildInventories(secrets.Items)
+
+	// The inventories of other shards are written by their replicas
+	owned := make(map[string]bool)
+	owns := func(namespace string) bool {
+		if _, ok := owned[namespace]; !ok {
+			isOwned, err := r.Shard.Owns(ctx, namespace)
+			if err != nil {
+				log.FromContext(ctx).Error(err, "Failed to determine shard", "namespace", namespace)
+			}
+			owned[namespace] = isOwned
+		}
+		return owned[namespace]
+	}
+	for namespace := range inventories {
+		if !owns(namespace) {
+			delete(inventories, namespace)
+		}
+	}
+
+	// A failing namespace must not block the inventory of the others
+	for namespace, inventory := range inventories {
+		if err := r.writeInventory(ctx, namespace, inventory); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to write inventory", "namespace", namespace)
+		}
+	}
+
+	var configMaps corev1.ConfigMapList
+	if err := r.APIReader.List(ctx, &configMaps, client.MatchingLabels{LabelInventory: "true"}); err != nil {
+		return fmt.Errorf("failed to list inventory ConfigMaps: %w", err)
+	}
+	for i := range configMaps.Items {
+		configMap := &configMaps.Items[i]
+		if _, ok := inventories[configMap.Namespace]; ok || configMap.Name != r.Config.Inventory.ConfigMapName ||
+			!owns(configMap.Namespace) {
+			continue
+		}
+		if err := r.Delete(ctx, configMap); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete inventory ConfigMap %s/%s: %w", configMap.Namespace, configMap.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// buildInventories returns the inventory of every namespace with operator-managed Secrets
+func (r *InventoryReporter) buildInventories(secrets []corev1.Secret) map[string]*Inventory {
+	// The rotation helpers of the generator resolve field types and intervals with the config defaults
+	rotation := &SecretReconciler{Config: r.Config}
+
+	inventories := make(map[string]*Inventory)
+	for i := range secrets {
+		secret := &secrets[i]
+		if r.Config.IsSecretTypeIgnored(string(secret.Type)) {
+			continue
+		}
+
+		entry := InventorySecret{
+			Name:           secret.Name,
+			ReplicatedFrom: replicator.GetReplicatedFromAnnotation(secret),
+			ReplicateTo:    secret.Annotations[replicator.AnnotationReplicateTo],
+		}
+
+		var counts InventoryCounts
+		if value, ok := secret.Annotations[AnnotationAutogenerate]; ok {
+			counts.Generated = 1
+			entry.Fields = parseFields(value)
+			entry.GeneratedAt = secret.Annotations[AnnotationGeneratedAt]
+			if next := nextRotationTime(rotation, secret.Annotations, entry.Fields); next != nil {
+				counts.Rotating = 1
+				entry.NextRotation = next.UTC().Format(time.RFC3339)
+			}
+		}
+		if entry.ReplicatedFrom != "" {
+			counts.Replicated = 1
+		}
+		if entry.ReplicateTo != "" {
+			counts.PushSources = 1
+		}
+		if counts == (InventoryCounts{}) {
+			continue
+		}
+
+		inventory, ok := inventories[secret.Namespace]
+		if !ok {
+			inventory = &Inventory{Secrets: []InventorySecret{}}
+			inventories[secret.Namespace] = inventory
+		}
+		inventory.Counts.Generated += counts.Generated
+		inventory.Counts.Rotating += counts.Rotating
+		inventory.Counts.Replicated += counts.Replicated
+		inventory.Counts.PushSources += counts.PushSources
+		inventory.Secrets = append(inventory.Secrets, entry)
+	}
+
+	for _, inventory := range inventories {
+		sort.Slice(inventory.Secrets, func(i, j int) bool {
+			return inventory.Secrets[i].Name < inventory.Secrets[j].Name
+		})
+	}
+	return inventories
+}
+
+// nextRotationTime returns the earliest time a field of the Secret is due for rotation,
+// or nil if no field is rotated or the Secret has not been generated yet
+func nextRotationTime(rotation *SecretReconciler, annotations map[string]string, fields []string) *time.Time {
+	generatedAt := rotation.getGeneratedAtTime(annotations)
+	if generatedAt == nil {
+		return nil
+	}
+
+	var next *time.Time
+	for _, field := range fields {
+		// Certificates and signed tokens are renewed based on their lifetime instead of a rotation interval
+		if isExpiringType(rotation.getFieldType(annotations, field)) {
+			continue
+		}
+		interval := rotation.getFieldRotationInterval(annotations, field)
+		if interval <= 0 {
+			continue
+		}
+		due := generatedAt.Add(interval)
+		if next == nil || due.Before(*next) {
+			next = &due
+		}
+	}
+	return next
+}
+
+// writeInventory creates or updates the inventory ConfigMap of a namespace.
+// The ConfigMap is only written if its content changed.
+func (r *InventoryReporter) writeInventory(ctx context.Context, namespace string, inventory *Inventory) error {
+	data, err := json.MarshalIndent(inventory, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode inventory: %w", err)
+	}
+
+	key := types.NamespacedName{Namespace: namespace, Name: r.Config.Inventory.ConfigMapName}
+	var configMap corev1.ConfigMap
+	err = r.APIReader.Get(ctx, key, &configMap)
+	if apierrors.IsNotFound(err) {
+		configMap = corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        key.Name,
+				Namespace:   key.Namespace,
+				Labels:      map[string]string{LabelInventory: "true"},
+				Annotations: map[string]string{AnnotationInventoryUpdatedAt: r.now().UTC().Format(time.RFC3339)},
+			},
+			Data: map[string]string{InventoryDataKey: string(data)},
+		}
+		if err := r.Create(ctx, &configMap); err != nil {
+			return fmt.Errorf("failed to create inventory ConfigMap %s: %w", key, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get inventory ConfigMap %s: %w", key, err)
+	}
+
+	if configMap.Labels[LabelInventory] != "true" {
+		return fmt.Errorf("ConfigMap %s exists and is not an inventory ConfigMap", key)
+	}
+	if configMap.Data[InventoryDataKey] == string(data) {
+		return nil
+	}
+
+	if configMap.Annotations == nil {
+		configMap.Annotations = make(map[string]string)
+	}
+	configMap.Annotations[AnnotationInventoryUpdatedAt] = r.now().UTC().Format(time.RFC3339)
+	configMap.Data = map[string]string{InventoryDataKey: string(data)}
+	if err := r.Update(ctx, &configMap); err != nil {
+		return fmt.Errorf("failed to update inventory ConfigMap %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+func TestInventoryReporterUpdate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	generatedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	objs := []client.Object{
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: "db", Namespace: "production",
+			Annotations: map[string]string{
+				AnnotationAutogenerate:             "password,api-key",
+				AnnotationRotate:                   "30d",
+				AnnotationRotatePrefix + "api-key": "7d",
+				AnnotationGeneratedAt:              generatedAt.Format(time.RFC3339),
+				replicator.AnnotationReplicateTo:   "staging",
+			},
+		}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: "db", Namespace: "staging",
+			Annotations: map[string]string{replicator.AnnotationReplicatedFrom: "production/db"},
+		}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "manual", Namespace: "production"}},
+		// The inventory of a namespace without managed Secrets is removed
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name: config.DefaultInventoryConfigMapName, Namespace: "old",
+			Labels: map[string]string{LabelInventory: "true"},
+		}},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	reporter := &InventoryReporter{
+		Client:    fakeClient,
+		APIReader: fakeClient,
+		Config:    config.NewDefaultConfig(),
+		Clock:     &MockClock{currentTime: generatedAt.Add(time.Hour)},
+	}
+
+	if err := reporter.update(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	production := getInventory(t, fakeClient, "production")
+	expected := Inventory{
+		Counts: InventoryCounts{Generated: 1, Rotating: 1, PushSources: 1},
+		Secrets: []InventorySecret{{
+			Name:         "db",
+			Fields:       []string{"password", "api-key"},
+			GeneratedAt:  generatedAt.Format(time.RFC3339),
+			NextRotation: generatedAt.Add(7 * 24 * time.Hour).Format(time.RFC3339),
+			ReplicateTo:  "staging",
+		}},
+	}
+	if !reflect.DeepEqual(production, expected) {
+		t.Errorf("expected inventory %+v, got %+v", expected, production)
+	}
+
+	staging := getInventory(t, fakeClient, "staging")
+	if staging.Counts.Replicated != 1 || staging.Secrets[0].ReplicatedFrom != "production/db" {
+		t.Errorf("expected replicated Secret in staging inventory, got %+v", staging)
+	}
+
+	var stale corev1.ConfigMap
+	err := fakeClient.Get(context.Background(),
+		types.NamespacedName{Namespace: "old", Name: config.DefaultInventoryConfigMapName}, &stale)
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected stale inventory ConfigMap to be deleted, got %v", err)
+	}
+}
+
+func TestInventoryReporterSkipsForeignConfigMap(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	foreign := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: config.DefaultInventoryConfigMapName, Namespace: "production"},
+		Data:       map[string]string{"owner": "someone-else"},
+	}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name: "db", Namespace: "production", Annotations: map[string]string{AnnotationAutogenerate: "password"},
+	}}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(foreign, secret).Build()
+	reporter := &InventoryReporter{Client: fakeClient, APIReader: fakeClient, Config: config.NewDefaultConfig()}
+
+	if err := reporter.update(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var configMap corev1.ConfigMap
+	if err := fakeClient.Get(context.Background(), client.ObjectKeyFromObject(foreign), &configMap); err != nil {
+		t.Fatalf("failed to get ConfigMap: %v", err)
+	}
+	if configMap.Data["owner"] != "someone-else" || configMap.Data[InventoryDataKey] != "" {
+		t.Error("expected a ConfigMap not created by the operator to be left untouched")
+	}
+}
+
+func getInventory(t *testing.T, c client.Client, namespace string) Inventory {
+	t.Helper()
+
+	var configMap corev1.ConfigMap
+	key := types.NamespacedName{Namespace: namespace, Name: config.DefaultInventoryConfigMapName}
+	if err := c.Get(context.Background(), key, &configMap); err != nil {
+		t.Fatalf("failed to get inventory ConfigMap in %s: %v", namespace, err)
+	}
+
+	var inventory Inventory
+	if err := json.Unmarshal([]byte(configMap.Data[InventoryDataKey]), &inventory); err != nil {
+		t.Fatalf("failed to decode inventory: %v", err)
+	}
+	return inventory
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	isoannotations "github.com/guided-traffic/internal-secrets-operator/pkg/annotations"
+)
+
+// LegacyAnnotations translates the annotations with the legacy prefix to the primary prefix
+// before objects are stored in the informer cache, so that all controllers read legacy
+// annotations without further changes. The translations are removed again before objects are
+// written, so only the annotations the operator changes are stored with the primary prefix and
+// later edits of the legacy annotations still apply.
+type LegacyAnnotations struct {
+	prefix string
+	// translated holds the annotations translated for each object, by UID. Entries of deleted
+	// objects are kept until the operator restarts; the legacy prefix is only used for migrations.
+	translated sync.Map
+}
+
+// NewLegacyAnnotations returns the translation of the legacy prefix, or nil if prefix is empty
+func NewLegacyAnnotations(prefix string) *LegacyAnnotations {
+	if prefix == "" {
+		return nil
+	}
+	return &LegacyAnnotations{prefix: prefix}
+}
+
+// Transform returns the cache transform that translates the legacy annotations.
+// Returns nil if l is nil.
+func (l *LegacyAnnotations) Transform() toolscache.TransformFunc {
+	if l == nil {
+		return nil
+	}
+	return func(obj interface{}) (interface{}, error) {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			// Tombstones and other non-objects are stored unchanged
+			return obj, nil
+		}
+		l.translate(accessor)
+		return obj, nil
+	}
+}
+
+// NewClient creates the client of the manager. Objects written by it are stored without the
+// translated annotations. If l is nil, the default client is returned.
+func (l *LegacyAnnotations) NewClient(config *rest.Config, options client.Options) (client.Client, error) {
+	if l == nil {
+		return client.New(config, options)
+	}
+	c, err := client.NewWithWatch(config, options)
+	if err != nil {
+		return nil, err
+	}
+	return l.wrap(c), nil
+}
+
+// wrap returns a client that removes the translated annotations before objects are updated or
+// patched, and translates the annotations of the returned objects again
+func (l *LegacyAnnotations) wrap(c client.WithWatch) client.WithWatch {
+	return interceptor.NewClient(c, interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			l.untranslate(obj)
+			err := c.Update(ctx, obj, opts...)
+			l.translate(obj)
+			return err
+		},
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			l.untranslate(obj)
+			err := c.Patch(ctx, obj, patch, opts...)
+			l.translate(obj)
+			return err
+		},
+	})
+}
+
+// translate translates the legacy annotations of an object and records the added annotations
+func (l *LegacyAnnotations) translate(obj metav1.Object) {
+	translated := isoannotations.TranslateLegacy(obj.GetAnnotations(), l.prefix)
+	if len(translated) == 0 {
+		l.translated.Delete(obj.GetUID())
+		return
+	}
+	l.translated.Store(obj.GetUID(), translated)
+}
+
+// untranslate removes the translated annotations the operator left unchanged. Translated
+// annotations the operator removed are removed with the legacy prefix as well.
+func (l *LegacyAnnotations) untranslate(obj metav1.Object) {
+	value, ok := l.translated.Load(obj.GetUID())
+	annotations := obj.GetAnnotations()
+	if !ok || annotations == nil {
+		return
+	}
+	for _, key := range value.([]string) {
+		legacyKey := l.prefix + strings.TrimPrefix(key, isoannotations.Prefix)
+		legacyValue, hasLegacy := annotations[legacyKey]
+		primary, ok := annotations[key]
+		switch {
+		case !ok:
+			delete(annotations, legacyKey)
+		case hasLegacy && primary == legacyValue:
+			delete(annotations, key)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestLegacyAnnotationsTransform(t *testing.T) {
+	if NewLegacyAnnotations("").Transform() != nil {
+		t.Fatal("expected no transform without a legacy prefix")
+	}
+
+	transform := NewLegacyAnnotations("legacy.example.com/").Transform()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-secret",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"legacy.example.com/autogenerate": "password",
+				"legacy.example.com/length":       "16",
+				AnnotationLength:                  "32",
+			},
+		},
+	}
+	obj, err := transform(secret)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	annotations := obj.(*corev1.Secret).Annotations
+	if annotations[AnnotationAutogenerate] != "password" {
+		t.Errorf("expected translated autogenerate annotation, got %q", annotations[AnnotationAutogenerate])
+	}
+	if annotations[AnnotationLength] != "32" {
+		t.Errorf("expected primary length annotation to take precedence, got %q", annotations[AnnotationLength])
+	}
+
+	tombstone := toolscache.DeletedFinalStateUnknown{Key: "default/test-secret"}
+	if obj, err := transform(tombstone); err != nil || obj != tombstone {
+		t.Errorf("expected tombstone to be returned unchanged, got %v, %v", obj, err)
+	}
+}
+
+func TestLegacyAnnotationsWrite(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-secret",
+			Namespace: "default",
+			UID:       "uid",
+			Annotations: map[string]string{
+				"legacy.example.com/autogenerate": "password",
+				"legacy.example.com/rotate":       "24h",
+				"legacy.example.com/adopt":        "token",
+			},
+		},
+	}
+	legacy := NewLegacyAnnotations("legacy.example.com/")
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	c := legacy.wrap(fakeClient)
+	ctx := context.Background()
+
+	var cached corev1.Secret
+	if err := c.Get(ctx, client.ObjectKeyFromObject(secret), &cached); err != nil {
+		t.Fatalf("failed to get Secret: %v", err)
+	}
+	if _, err := legacy.Transform()(&cached); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The operator changes one translated annotation and removes another one
+	cached.Annotations[AnnotationRotate] = "48h"
+	delete(cached.Annotations, AnnotationAdopt)
+	if err := c.Update(ctx, &cached); err != nil {
+		t.Fatalf("failed to update Secret: %v", err)
+	}
+	if cached.Annotations[AnnotationAutogenerate] != "password" {
+		t.Errorf("expected the returned object to be translated again, got %v", cached.Annotations)
+	}
+
+	var stored corev1.Secret
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(secret), &stored); err != nil {
+		t.Fatalf("failed to get Secret: %v", err)
+	}
+	expected := map[string]string{
+		"legacy.example.com/autogenerate": "password",
+		"legacy.example.com/rotate":       "24h",
+		AnnotationRotate:                  "48h",
+	}
+	if len(stored.Annotations) != len(expected) {
+		t.Fatalf("expected annotations %v to be stored, got %v", expected, stored.Annotations)
+	}
+	for key, value := range expected {
+		if stored.Annotations[key] != value {
+			t.Errorf("expected annotations %v to be stored, got %v", expected, stored.Annotations)
+		}
+	}
+}
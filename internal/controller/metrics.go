@@ -0,0 +1,528 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	isoannotations "github.com/guided-traffic/internal-secrets-operator/pkg/annotations"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+const (
+	// MetricRotationPausedNamespaces is the name of the gauge with the number of paused namespaces
+	MetricRotationPausedNamespaces = "secret_operator_rotation_paused_namespaces"
+
+	// MetricStaleReplicationTargets is the name of the gauge with the number of snapshot targets
+	// whose source was deleted or whose consent expired
+	MetricStaleReplicationTargets = "secret_operator_stale_replication_targets"
+
+	// MetricInvalidRotationConfig is the name of the gauge with the number of Secrets with
+	// invalid rotate annotations
+	MetricInvalidRotationConfig = "secret_operator_invalid_rotation_config"
+
+	// MetricManagedSecrets is the name of the gauge with the number of Secrets with generated values
+	MetricManagedSecrets = "secret_operator_managed_secrets"
+
+	// MetricReplicationTargets is the name of the gauge with the number of replicated Secrets
+	MetricReplicationTargets = "secret_operator_replication_targets"
+
+	// MetricRotationsTotal is the name of the counter of Secret rotations (including certificate renewals)
+	MetricRotationsTotal = "secret_operator_rotations_total"
+
+	// MetricEventsTotal is the name of the counter of emitted Kubernetes Events by type and reason
+	MetricEventsTotal = "secret_operator_events_total"
+
+	// MetricGeneratedFieldAge is the name of the histogram of the time since generated fields
+	// were last generated or rotated
+	MetricGeneratedFieldAge = "secret_operator_generated_field_age_seconds"
+
+	// MetricRotationOverdueFields is the name of the gauge with the number of fields whose rotation
+	// or certificate renewal is overdue by more than metrics.rotationOverdueThreshold
+	MetricRotationOverdueFields = "secret_operator_rotation_overdue_fields"
+
+	// MetricRandomSourceInfo is the name of the gauge that reports the active random source
+	MetricRandomSourceInfo = "secret_operator_random_source_info"
+
+	// OtherNamespacesLabel is the namespace label value that aggregates all namespaces
+	// beyond the configured metrics.maxNamespaces
+	OtherNamespacesLabel = "_other"
+)
+
+// metricsLog logs the errors of metrics that are computed on every scrape
+var metricsLog = ctrl.Log.WithName("metrics")
+
+// registerCollector registers a collector with the controller-runtime metrics registry.
+// Registering the same collector more than once (e.g. in tests) is not an error.
+func registerCollector(collector prometheus.Collector) error {
+	err := metrics.Registry.Register(collector)
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if errors.As(err, &alreadyRegistered) {
+		return nil
+	}
+	return err
+}
+
+// RegisterRandomSourceMetric registers a gauge with the value 1 and the name of the active
+// random source as its source label
+func RegisterRandomSourceMetric(source string) error {
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:        MetricRandomSourceInfo,
+		Help:        "Random source used for generated values (always 1)",
+		ConstLabels: prometheus.Labels{"source": source},
+	})
+	gauge.Set(1)
+	return registerCollector(gauge)
+}
+
+// newPausedNamespacesGauge returns a gauge that counts the namespaces with rotation paused.
+// The value is computed from the (cached) reader on every scrape.
+func newPausedNamespacesGauge(reader client.Reader) prometheus.GaugeFunc {
+	return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: MetricRotationPausedNamespaces,
+		Help: "Number of namespaces with secret rotation paused",
+	}, func() float64 {
+		var namespaces corev1.NamespaceList
+		if err := reader.List(context.Background(), &namespaces); err != nil {
+			return 0
+		}
+		paused := 0
+		for i := range namespaces.Items {
+			if isRotationPaused(&namespaces.Items[i]) {
+				paused++
+			}
+		}
+		return float64(paused)
+	})
+}
+
+// listOwnedSecrets lists the Secrets for a metric that is computed on every scrape. Like in the
+// controllers, Secrets without the managed label and Secrets in namespaces of other shards are
+// skipped, so that shards do not report each other's Secrets. Errors are logged and return false.
+func listOwnedSecrets(reader client.Reader, cfg *config.Config, shard *Shard, metric string) ([]corev1.Secret, bool) {
+	ctx := context.Background()
+	var secrets corev1.SecretList
+	if err := reader.List(ctx, &secrets); err != nil {
+		metricsLog.Error(err, "Failed to list Secrets", "metric", metric)
+		return nil, false
+	}
+
+	owned := make(map[string]bool)
+	result := secrets.Items[:0]
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if !cfg.ManagedLabel.Matches(secret.Labels) {
+			continue
+		}
+		ok, known := owned[secret.Namespace]
+		if !known {
+			var err error
+			if ok, err = shard.Owns(ctx, secret.Namespace); err != nil {
+				metricsLog.Error(err, "Failed to check the shard of a namespace", "metric", metric)
+				return nil, false
+			}
+			owned[secret.Namespace] = ok
+		}
+		if ok {
+			result = append(result, *secret)
+		}
+	}
+	return result, true
+}
+
+// newStaleTargetsGauge returns a gauge that counts the stale replication targets.
+// The value is computed from the (cached) reader on every scrape.
+func newStaleTargetsGauge(reader client.Reader) prometheus.GaugeFunc {
+	return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: MetricStaleReplicationTargets,
+		Help: "Number of replicated Secrets whose source was deleted or whose consent expired, and that no longer receive updates",
+	}, func() float64 {
+		var secrets corev1.SecretList
+		if err := reader.List(context.Background(), &secrets); err != nil {
+			return 0
+		}
+		stale := 0
+		for i := range secrets.Items {
+			if replicator.IsStale(&secrets.Items[i]) {
+				stale++
+			}
+		}
+		return float64(stale)
+	})
+}
+
+// newInvalidRotationConfigGauge returns a gauge that counts the Secrets with invalid rotate
+// annotations, as recorded by the generator. The value is computed from the (cached) reader on every scrape.
+func newInvalidRotationConfigGauge(reader client.Reader, cfg *config.Config, shard *Shard) prometheus.GaugeFunc {
+	return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: MetricInvalidRotationConfig,
+		Help: "Number of Secrets with invalid rotate annotations",
+	}, func() float64 {
+		secrets, ok := listOwnedSecrets(reader, cfg, shard, MetricInvalidRotationConfig)
+		if !ok {
+			return 0
+		}
+		invalid := 0
+		for i := range secrets {
+			if secrets[i].Annotations[AnnotationRotationConfigError] != "" {
+				invalid++
+			}
+		}
+		return float64(invalid)
+	})
+}
+
+// fieldAgeBuckets are the upper bounds in seconds of the generated field age histogram:
+// 1h, 6h, 1d, 7d, 30d, 90d, 180d and 365d
+var fieldAgeBuckets = []float64{3600, 21600, 86400, 604800, 2592000, 7776000, 15552000, 31536000}
+
+// rotationAgeCollector reports the age of all generated fields and the number of fields whose
+// rotation is overdue. A rising overdue count means the operator has stopped rotating, e.g.
+// because it is stuck or lacks permissions. The values are computed from the (cached) reader on every scrape.
+type rotationAgeCollector struct {
+	ageDesc     *prometheus.Desc
+	overdueDesc *prometheus.Desc
+	reader      client.Reader
+	reconciler  *SecretReconciler
+}
+
+// newRotationAgeCollector returns a rotationAgeCollector that evaluates rotation intervals like the given reconciler
+func newRotationAgeCollector(reader client.Reader, reconciler *SecretReconciler) *rotationAgeCollector {
+	return &rotationAgeCollector{
+		ageDesc: prometheus.NewDesc(MetricGeneratedFieldAge,
+			"Time since generated fields were last generated or rotated", nil, nil),
+		overdueDesc: prometheus.NewDesc(MetricRotationOverdueFields,
+			"Number of generated fields whose rotation or certificate renewal is overdue", nil, nil),
+		reader:     reader,
+		reconciler: reconciler,
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *rotationAgeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.ageDesc
+	ch <- c.overdueDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *rotationAgeCollector) Collect(ch chan<- prometheus.Metric) {
+	r := c.reconciler
+	secrets, ok := listOwnedSecrets(c.reader, r.Config, r.Shard, MetricGeneratedFieldAge)
+	if !ok {
+		return
+	}
+
+	threshold := r.Config.Metrics.RotationOverdueThreshold.Duration()
+	buckets := make(map[float64]uint64, len(fieldAgeBuckets))
+	var count uint64
+	var sum float64
+	overdue := 0
+	for i := range secrets {
+		secret := &secrets[i]
+		if r.Config.IsSecretTypeIgnored(string(secret.Type)) {
+			continue
+		}
+		generatedAt := r.getGeneratedAtTime(secret.Annotations)
+		for _, field := range parseSecretAnnotations(secret.Annotations) {
+			if _, ok := secret.Data[field]; !ok || generatedAt == nil {
+				continue
+			}
+
+			age := r.since(*generatedAt).Seconds()
+			count++
+			sum += age
+			for _, bound := range fieldAgeBuckets {
+				if age <= bound {
+					buckets[bound]++
+				}
+			}
+
+			if dueAt, ok := c.rotationDue(secret, field, *generatedAt); ok && r.since(dueAt) > threshold {
+				overdue++
+			}
+		}
+	}
+
+	ch <- prometheus.MustNewConstHistogram(c.ageDesc, count, sum, buckets)
+	ch <- prometheus.MustNewConstMetric(c.overdueDesc, prometheus.GaugeValue, float64(overdue))
+}
+
+// rotationDue returns when the field is due for rotation, certificate renewal or signed token
+// refresh. It returns false if the field is not rotated.
+func (c *rotationAgeCollector) rotationDue(secret *corev1.Secret, field string, generatedAt time.Time) (time.Time, bool) {
+	r := c.reconciler
+	switch r.getFieldType(secret.Annotations, field) {
+	case config.TypeSignedToken:
+		key, _ := signingKey(secret, field)
+		claims, err := generator.ParseSignedToken(secret.Data[field], key)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return r.signedTokenRenewalTime(claims), true
+	case config.TypeTLS:
+		cert, err := generator.ParseCertificatePEM(secret.Data[field])
+		if err != nil {
+			return time.Time{}, false
+		}
+		spec, err := isoannotations.ParseCertificate(secret.Annotations)
+		if err != nil {
+			return time.Time{}, false
+		}
+		renewAt, err := r.certificateRenewalTime(spec, cert)
+		return renewAt, err == nil
+	}
+	interval := r.getFieldRotationInterval(secret.Annotations, field)
+	if interval <= 0 {
+		return time.Time{}, false
+	}
+	return generatedAt.Add(interval), true
+}
+
+// eventsTotal counts the Events emitted by the operator's controllers
+var eventsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: MetricEventsTotal,
+	Help: "Number of Kubernetes Events emitted by the operator, by type and reason",
+}, []string{"type", "reason"})
+
+// metricsEventRecorder is an EventRecorder that counts all recorded Events in eventsTotal,
+// so that alerts can use the rate of specific failure reasons instead of matching Event text.
+// The Events of the last hour are also recorded for the status endpoint.
+type metricsEventRecorder struct {
+	record.EventRecorder
+}
+
+// instrumentEventRecorder wraps an EventRecorder so that its Events are counted
+func instrumentEventRecorder(recorder record.EventRecorder) (record.EventRecorder, error) {
+	if err := registerCollector(eventsTotal); err != nil {
+		return nil, err
+	}
+	if _, ok := recorder.(*metricsEventRecorder); ok {
+		return recorder, nil
+	}
+	return &metricsEventRecorder{EventRecorder: recorder}, nil
+}
+
+// Event implements record.EventRecorder
+func (r *metricsEventRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	eventsTotal.WithLabelValues(eventtype, reason).Inc()
+	recentEvents.record(reason, time.Now())
+	r.EventRecorder.Event(object, eventtype, reason, message)
+}
+
+// Eventf implements record.EventRecorder
+func (r *metricsEventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	eventsTotal.WithLabelValues(eventtype, reason).Inc()
+	recentEvents.record(reason, time.Now())
+	r.EventRecorder.Eventf(object, eventtype, reason, messageFmt, args...)
+}
+
+// AnnotatedEventf implements record.EventRecorder
+func (r *metricsEventRecorder) AnnotatedEventf(
+	object runtime.Object,
+	annotations map[string]string,
+	eventtype, reason, messageFmt string,
+	args ...interface{},
+) {
+	eventsTotal.WithLabelValues(eventtype, reason).Inc()
+	recentEvents.record(reason, time.Now())
+	r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+}
+
+// namespaceLabels returns the variable labels of metrics that can be reported per namespace
+func namespaceLabels(cfg config.MetricsConfig) []string {
+	if cfg.PerNamespace {
+		return []string{"namespace"}
+	}
+	return nil
+}
+
+// maxMetricNamespaces returns the number of namespaces reported individually in per-namespace metrics
+func maxMetricNamespaces(cfg config.MetricsConfig) int {
+	if cfg.MaxNamespaces > 0 {
+		return cfg.MaxNamespaces
+	}
+	return config.DefaultMetricsMaxNamespaces
+}
+
+// secretCountCollector is a gauge that counts the Secrets matching a filter, optionally per namespace.
+// The value is computed from the (cached) reader on every scrape, over the Secrets of the shard.
+// Only the namespaces with the most matching Secrets are reported individually; all others are
+// aggregated into OtherNamespacesLabel.
+type secretCountCollector struct {
+	name    string
+	desc    *prometheus.Desc
+	reader  client.Reader
+	config  *config.Config
+	shard   *Shard
+	matches func(secret *corev1.Secret) bool
+}
+
+// newSecretCountCollector returns a secretCountCollector for the given metric
+func newSecretCountCollector(
+	name, help string,
+	reader client.Reader,
+	cfg *config.Config,
+	shard *Shard,
+	matches func(secret *corev1.Secret) bool,
+) *secretCountCollector {
+	return &secretCountCollector{
+		name:    name,
+		desc:    prometheus.NewDesc(name, help, namespaceLabels(cfg.Metrics), nil),
+		reader:  reader,
+		config:  cfg,
+		shard:   shard,
+		matches: matches,
+	}
+}
+
+// newManagedSecretsCollector returns a gauge that counts the Secrets with generated values
+func newManagedSecretsCollector(reader client.Reader, cfg *config.Config, shard *Shard) *secretCountCollector {
+	return newSecretCountCollector(MetricManagedSecrets, "Number of Secrets with generated values",
+		reader, cfg, shard, func(secret *corev1.Secret) bool {
+			_, ok := secret.Annotations[AnnotationAutogenerate]
+			return ok && !cfg.IsSecretTypeIgnored(string(secret.Type))
+		})
+}
+
+// newReplicationTargetsCollector returns a gauge that counts the replicated Secrets
+func newReplicationTargetsCollector(reader client.Reader, cfg *config.Config, shard *Shard) *secretCountCollector {
+	return newSecretCountCollector(MetricReplicationTargets, "Number of Secrets replicated from another Secret",
+		reader, cfg, shard, func(secret *corev1.Secret) bool {
+			return replicator.GetReplicatedFromAnnotation(secret) != ""
+		})
+}
+
+// Describe implements prometheus.Collector
+func (c *secretCountCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector
+func (c *secretCountCollector) Collect(ch chan<- prometheus.Metric) {
+	secrets, ok := listOwnedSecrets(c.reader, c.config, c.shard, c.name)
+	if !ok {
+		return
+	}
+
+	total := 0
+	counts := make(map[string]int)
+	for i := range secrets {
+		if c.matches(&secrets[i]) {
+			counts[secrets[i].Namespace]++
+			total++
+		}
+	}
+
+	if !c.config.Metrics.PerNamespace {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(total))
+		return
+	}
+	for namespace, count := range topNamespaces(counts, maxMetricNamespaces(c.config.Metrics)) {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count), namespace)
+	}
+}
+
+// topNamespaces keeps the limit namespaces with the highest counts and aggregates all other
+// namespaces into OtherNamespacesLabel
+func topNamespaces(counts map[string]int, limit int) map[string]int {
+	if len(counts) <= limit {
+		return counts
+	}
+
+	namespaces := make([]string, 0, len(counts))
+	for namespace := range counts {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Slice(namespaces, func(i, j int) bool {
+		if counts[namespaces[i]] != counts[namespaces[j]] {
+			return counts[namespaces[i]] > counts[namespaces[j]]
+		}
+		return namespaces[i] < namespaces[j]
+	})
+
+	result := make(map[string]int, limit+1)
+	for i, namespace := range namespaces {
+		if i < limit {
+			result[namespace] = counts[namespace]
+		} else {
+			result[OtherNamespacesLabel] += counts[namespace]
+		}
+	}
+	return result
+}
+
+// rotationCounter counts Secret rotations, optionally per namespace. To keep the counter monotonic,
+// the first namespaces that rotate (up to the configured limit) are reported individually and all
+// later namespaces are aggregated into OtherNamespacesLabel.
+type rotationCounter struct {
+	counter      *prometheus.CounterVec
+	perNamespace bool
+	limit        int
+
+	mu         sync.Mutex
+	namespaces map[string]struct{}
+}
+
+// newRotationCounter returns a rotationCounter for the given metrics configuration
+func newRotationCounter(cfg config.MetricsConfig) *rotationCounter {
+	return &rotationCounter{
+		counter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: MetricRotationsTotal,
+			Help: "Number of Secret rotations, including certificate renewals",
+		}, namespaceLabels(cfg)),
+		perNamespace: cfg.PerNamespace,
+		limit:        maxMetricNamespaces(cfg),
+		namespaces:   make(map[string]struct{}),
+	}
+}
+
+// inc counts a rotation in the given namespace. It is a no-op on a nil counter.
+func (c *rotationCounter) inc(namespace string) {
+	if c == nil {
+		return
+	}
+	if !c.perNamespace {
+		c.counter.WithLabelValues().Inc()
+		return
+	}
+
+	c.mu.Lock()
+	if _, ok := c.namespaces[namespace]; !ok {
+		if len(c.namespaces) < c.limit {
+			c.namespaces[namespace] = struct{}{}
+		} else {
+			namespace = OtherNamespacesLabel
+		}
+	}
+	c.mu.Unlock()
+
+	c.counter.WithLabelValues(namespace).Inc()
+}
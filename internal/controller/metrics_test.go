@@ -0,0 +1,369 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+func TestPausedNamespacesGauge(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		pausedNamespace("a"),
+		pausedNamespace("b"),
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "c"}},
+	).Build()
+
+	var metric dto.Metric
+	if err := newPausedNamespacesGauge(fakeClient).Write(&metric); err != nil {
+		t.Fatalf("failed to write metric: %v", err)
+	}
+	if got := metric.GetGauge().GetValue(); got != 2 {
+		t.Errorf("expected 2 paused namespaces, got %v", got)
+	}
+}
+
+func TestStaleTargetsGauge(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:        "stale",
+			Namespace:   "app",
+			Annotations: map[string]string{replicator.AnnotationSourceDeletedAt: "2025-01-01T00:00:00Z"},
+		}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "fresh", Namespace: "app"}},
+	).Build()
+
+	var metric dto.Metric
+	if err := newStaleTargetsGauge(fakeClient).Write(&metric); err != nil {
+		t.Fatalf("failed to write metric: %v", err)
+	}
+	if got := metric.GetGauge().GetValue(); got != 1 {
+		t.Errorf("expected 1 stale target, got %v", got)
+	}
+}
+
+func TestInvalidRotationConfigGauge(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:        "invalid",
+			Namespace:   "app",
+			Annotations: map[string]string{AnnotationRotationConfigError: "invalid iso.gtrfc.com/rotate \"weekly\""},
+		}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "valid", Namespace: "app"}},
+	).Build()
+
+	var metric dto.Metric
+	if err := newInvalidRotationConfigGauge(fakeClient, config.NewDefaultConfig(), nil).Write(&metric); err != nil {
+		t.Fatalf("failed to write metric: %v", err)
+	}
+	if got := metric.GetGauge().GetValue(); got != 1 {
+		t.Errorf("expected 1 Secret with invalid rotation config, got %v", got)
+	}
+}
+
+func TestRegisterCollectorTwice(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	for i := 0; i < 2; i++ {
+		if err := registerCollector(newStaleTargetsGauge(fakeClient)); err != nil {
+			t.Fatalf("registration %d failed: %v", i+1, err)
+		}
+	}
+}
+
+// collectByNamespace collects all metrics of a collector, keyed by their namespace label
+// ("" for metrics without namespace label)
+func collectByNamespace(t *testing.T, collector prometheus.Collector) map[string]float64 {
+	t.Helper()
+
+	ch := make(chan prometheus.Metric, 100)
+	collector.Collect(ch)
+	close(ch)
+
+	values := make(map[string]float64)
+	for m := range ch {
+		var metric dto.Metric
+		if err := m.Write(&metric); err != nil {
+			t.Fatalf("failed to write metric: %v", err)
+		}
+		namespace := ""
+		for _, label := range metric.GetLabel() {
+			if label.GetName() == "namespace" {
+				namespace = label.GetValue()
+			}
+		}
+		value := metric.GetGauge().GetValue()
+		if metric.GetCounter() != nil {
+			value = metric.GetCounter().GetValue()
+		}
+		values[namespace] = value
+	}
+	return values
+}
+
+func TestTopNamespaces(t *testing.T) {
+	counts := map[string]int{"a": 5, "b": 1, "c": 3, "d": 3}
+
+	result := topNamespaces(counts, 2)
+	expected := map[string]int{"a": 5, "c": 3, OtherNamespacesLabel: 4}
+	if len(result) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, result)
+	}
+	for namespace, count := range expected {
+		if result[namespace] != count {
+			t.Errorf("expected %s=%d, got %d", namespace, count, result[namespace])
+		}
+	}
+
+	if result := topNamespaces(counts, 10); len(result) != len(counts) {
+		t.Errorf("expected all namespaces below the limit, got %v", result)
+	}
+}
+
+func TestManagedSecretsCollector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	managed := func(name, namespace string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: namespace, Annotations: map[string]string{AnnotationAutogenerate: "password"},
+		}}
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		managed("a1", "a"), managed("a2", "a"), managed("b1", "b"), managed("c1", "c"),
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "manual", Namespace: "a"}},
+	).Build()
+
+	cfg := config.NewDefaultConfig()
+	if got := collectByNamespace(t, newManagedSecretsCollector(fakeClient, cfg, nil)); got[""] != 4 || len(got) != 1 {
+		t.Errorf("expected a single total of 4, got %v", got)
+	}
+
+	cfg.Metrics.PerNamespace = true
+	cfg.Metrics.MaxNamespaces = 1
+	got := collectByNamespace(t, newManagedSecretsCollector(fakeClient, cfg, nil))
+	if got["a"] != 2 || got[OtherNamespacesLabel] != 2 || len(got) != 2 {
+		t.Errorf("expected a=2 and %s=2, got %v", OtherNamespacesLabel, got)
+	}
+
+	// Only the Secrets of the shard are counted
+	cfg.Metrics.MaxNamespaces = 10
+	shard := &Shard{Config: config.ShardingConfig{Shards: 2, Mode: config.ShardingModeHash}}
+	shard.Index = ShardFor(shard.Config, "a", nil)
+	got = collectByNamespace(t, newManagedSecretsCollector(fakeClient, cfg, shard))
+	for namespace, count := range got {
+		if ShardFor(shard.Config, namespace, nil) != shard.Index {
+			t.Errorf("expected only namespaces of shard %d, got %s=%v", shard.Index, namespace, count)
+		}
+	}
+	if got["a"] != 2 {
+		t.Errorf("expected a=2, got %v", got)
+	}
+
+	// Without the managed label, Secrets are not counted
+	cfg.ManagedLabel = config.ManagedLabelConfig{Enabled: true, Key: "managed", Value: "true"}
+	if got := collectByNamespace(t, newManagedSecretsCollector(fakeClient, cfg, nil)); len(got) != 0 {
+		t.Errorf("expected no Secrets with the managed label, got %v", got)
+	}
+}
+
+func TestReplicationTargetsCollector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: "target", Namespace: "app",
+			Annotations: map[string]string{replicator.AnnotationReplicatedFrom: "infra/ca"},
+		}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "source", Namespace: "infra"}},
+	).Build()
+
+	cfg := config.NewDefaultConfig()
+	cfg.Metrics.PerNamespace = true
+	got := collectByNamespace(t, newReplicationTargetsCollector(fakeClient, cfg, nil))
+	if got["app"] != 1 || len(got) != 1 {
+		t.Errorf("expected app=1, got %v", got)
+	}
+}
+
+func TestRotationCounter(t *testing.T) {
+	counter := newRotationCounter(config.MetricsConfig{PerNamespace: true, MaxNamespaces: 2})
+	for _, namespace := range []string{"a", "b", "c", "a", "d"} {
+		counter.inc(namespace)
+	}
+
+	got := collectByNamespace(t, counter.counter)
+	expected := map[string]float64{"a": 2, "b": 1, OtherNamespacesLabel: 2}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	for namespace, value := range expected {
+		if got[namespace] != value {
+			t.Errorf("expected %s=%v, got %v", namespace, value, got[namespace])
+		}
+	}
+
+	aggregated := newRotationCounter(config.MetricsConfig{})
+	aggregated.inc("a")
+	aggregated.inc("b")
+	if got := collectByNamespace(t, aggregated.counter); got[""] != 2 {
+		t.Errorf("expected total of 2, got %v", got)
+	}
+
+	// A nil counter (reconciler not set up with a manager) is a no-op
+	var nilCounter *rotationCounter
+	nilCounter.inc("a")
+}
+
+func TestMetricsEventRecorder(t *testing.T) {
+	fakeRecorder := record.NewFakeRecorder(10)
+	recorder, err := instrumentEventRecorder(fakeRecorder)
+	if err != nil {
+		t.Fatalf("failed to instrument recorder: %v", err)
+	}
+
+	// Instrumenting twice does not count Events twice
+	recorder, err = instrumentEventRecorder(recorder)
+	if err != nil {
+		t.Fatalf("failed to instrument recorder: %v", err)
+	}
+
+	counter := eventsTotal.WithLabelValues(corev1.EventTypeWarning, EventReasonPushFailed)
+	var before dto.Metric
+	if err := counter.Write(&before); err != nil {
+		t.Fatalf("failed to write metric: %v", err)
+	}
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "s", Namespace: "ns"}}
+	recorder.Event(secret, corev1.EventTypeWarning, EventReasonPushFailed, "failed")
+	recorder.Eventf(secret, corev1.EventTypeWarning, EventReasonPushFailed, "failed %d", 2)
+	recorder.AnnotatedEventf(secret, nil, corev1.EventTypeWarning, EventReasonPushFailed, "failed %d", 3)
+
+	var after dto.Metric
+	if err := counter.Write(&after); err != nil {
+		t.Fatalf("failed to write metric: %v", err)
+	}
+	if got := after.GetCounter().GetValue() - before.GetCounter().GetValue(); got != 3 {
+		t.Errorf("expected 3 counted events, got %v", got)
+	}
+	if len(fakeRecorder.Events) != 3 {
+		t.Errorf("expected events to be forwarded, got %d", len(fakeRecorder.Events))
+	}
+}
+
+func TestRotationAgeCollector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	now := time.Date(2025, 12, 6, 12, 0, 0, 0, time.UTC)
+	generated := func(name string, age time.Duration, annotations map[string]string, data map[string][]byte) *corev1.Secret {
+		annotations[AnnotationGeneratedAt] = now.Add(-age).Format(time.RFC3339)
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "app", Annotations: annotations},
+			Data:       data,
+		}
+	}
+	// A certificate whose renewal was due 10 days ago
+	cert, err := generator.GenerateSelfSignedCertificate(generator.CertificateOptions{
+		CommonName: "example.com",
+		Duration:   30 * 24 * time.Hour,
+		NotBefore:  now.Add(-30 * 24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("failed to generate certificate: %v", err)
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		generated("fresh", 2*time.Hour,
+			map[string]string{AnnotationAutogenerate: "password,username", AnnotationRotate: "24h"},
+			map[string][]byte{"password": []byte("a"), "username": []byte("b")}),
+		generated("overdue", 3*time.Hour,
+			map[string]string{AnnotationAutogenerate: "token", AnnotationRotate: "1h"},
+			map[string][]byte{"token": []byte("c")}),
+		generated("within-threshold", 90*time.Minute,
+			map[string]string{AnnotationAutogenerate: "token", AnnotationRotate: "1h"},
+			map[string][]byte{"token": []byte("d")}),
+		generated("certificate", 20*24*time.Hour,
+			map[string]string{AnnotationAutogenerate: "tls.crt", AnnotationTypePrefix + "tls.crt": config.TypeTLS},
+			map[string][]byte{"tls.crt": cert.CertPEM, "tls.key": cert.KeyPEM}),
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "app",
+			Annotations: map[string]string{AnnotationAutogenerate: "password"}}},
+	).Build()
+
+	reconciler := &SecretReconciler{
+		Config: config.NewDefaultConfig(),
+		Clock:  &MockClock{currentTime: now},
+	}
+	ch := make(chan prometheus.Metric, 10)
+	newRotationAgeCollector(fakeClient, reconciler).Collect(ch)
+	close(ch)
+
+	var histogram *dto.Histogram
+	overdue := -1.0
+	for m := range ch {
+		var metric dto.Metric
+		if err := m.Write(&metric); err != nil {
+			t.Fatalf("failed to write metric: %v", err)
+		}
+		if metric.GetHistogram() != nil {
+			histogram = metric.GetHistogram()
+		} else {
+			overdue = metric.GetGauge().GetValue()
+		}
+	}
+
+	if overdue != 2 {
+		t.Errorf("expected 2 overdue fields, got %v", overdue)
+	}
+	if histogram == nil {
+		t.Fatal("expected field age histogram")
+	}
+	if histogram.GetSampleCount() != 5 {
+		t.Errorf("expected 5 generated fields, got %d", histogram.GetSampleCount())
+	}
+	for _, bucket := range histogram.GetBucket() {
+		expected := map[float64]uint64{3600: 0, 21600: 4, 86400: 4, 604800: 4, 2592000: 5}
+		if want, ok := expected[bucket.GetUpperBound()]; ok && bucket.GetCumulativeCount() != want {
+			t.Errorf("expected %d fields up to %vs, got %d", want, bucket.GetUpperBound(), bucket.GetCumulativeCount())
+		}
+	}
+}
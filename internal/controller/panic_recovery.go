@@ -0,0 +1,55 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// EventReasonReconcilePanic is the reason of the Event emitted when a reconcile panicked
+const EventReasonReconcilePanic = "ReconcilePanic"
+
+// reportPanics wraps a reconciler so that a panic is reported as a Warning Event on the
+// reconciled object, whose kind is given by object. The panic is passed on to the controller,
+// which recovers it (RecoverPanic defaults to true), logs it with its stack trace, counts it in
+// controller_runtime_reconcile_panics_total and retries the request with backoff like any other error.
+func reportPanics(recorder record.EventRecorder, object client.Object, reconciler reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			obj, ok := object.DeepCopyObject().(client.Object)
+			if recorder != nil && ok {
+				obj.SetNamespace(req.Namespace)
+				obj.SetName(req.Name)
+				recorder.Event(obj, corev1.EventTypeWarning, EventReasonReconcilePanic,
+					fmt.Sprintf("Reconcile failed unexpectedly: %v", recovered))
+			}
+			panic(recovered)
+		}()
+		return reconciler.Reconcile(ctx, req)
+	})
+}
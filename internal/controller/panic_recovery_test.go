@@ -0,0 +1,72 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReportPanics(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	reconciler := reportPanics(recorder, &corev1.Secret{},
+		reconcile.Func(func(context.Context, ctrl.Request) (ctrl.Result, error) {
+			var annotations map[string]string
+			annotations["malformed"] = "value" // panics: assignment to entry in nil map
+			return ctrl.Result{}, nil
+		}))
+
+	// The panic is passed on to the controller, which recovers and counts it
+	func() {
+		defer func() {
+			if recovered := recover(); recovered == nil {
+				t.Error("expected the panic to be passed on")
+			}
+		}()
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "broken"}}
+		_, _ = reconciler.Reconcile(context.Background(), req)
+	}()
+
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, EventReasonReconcilePanic) {
+			t.Errorf("expected %s event, got %q", EventReasonReconcilePanic, event)
+		}
+	default:
+		t.Error("expected an event on the Secret")
+	}
+}
+
+func TestReportPanicsPassesThroughResults(t *testing.T) {
+	reconciler := reportPanics(nil, &corev1.Secret{},
+		reconcile.Func(func(context.Context, ctrl.Request) (ctrl.Result, error) {
+			return ctrl.Result{RequeueAfter: time.Minute}, nil
+		}))
+
+	result, err := reconciler.Reconcile(context.Background(), ctrl.Request{})
+	if err != nil || result.RequeueAfter != time.Minute {
+		t.Errorf("expected the result to be passed through, got %v, %v", result, err)
+	}
+}
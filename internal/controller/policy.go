@@ -0,0 +1,82 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/policy"
+)
+
+const (
+	// EventReasonPolicyViolation is emitted when a Secret uses a feature denied by a policy
+	EventReasonPolicyViolation = "PolicyViolation"
+)
+
+// namespaceLabelsForPolicies returns the labels of the namespace if a policy selects namespaces
+// by label, nil otherwise. A namespace that cannot be found has no labels.
+func namespaceLabelsForPolicies(ctx context.Context, c client.Reader, rules []config.PolicyRule, name string) (map[string]string, error) {
+	if !policy.NeedsNamespaceLabels(rules) {
+		return nil, nil
+	}
+	var namespace corev1.Namespace
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, &namespace); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	if namespace.Labels == nil {
+		return map[string]string{}, nil
+	}
+	return namespace.Labels, nil
+}
+
+// enforcePolicies evaluates the policies for the given features of a Secret and emits a Warning
+// event if it violates any of them. It returns true if the Secret may be processed.
+func enforcePolicies(
+	ctx context.Context,
+	c client.Reader,
+	recorder record.EventRecorder,
+	rules []config.PolicyRule,
+	secret *corev1.Secret,
+	features []string,
+) (bool, error) {
+	if len(rules) == 0 {
+		return true, nil
+	}
+	namespaceLabels, err := namespaceLabelsForPolicies(ctx, c, rules, secret.Namespace)
+	if err != nil {
+		return false, err
+	}
+	violations := policy.Evaluate(rules, secret, namespaceLabels, features)
+	if len(violations) == 0 {
+		return true, nil
+	}
+
+	messages := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		messages = append(messages, violation.String())
+	}
+	recorder.Event(secret, corev1.EventTypeWarning, EventReasonPolicyViolation,
+		"Secret is not processed: "+strings.Join(messages, "; "))
+	return false, nil
+}
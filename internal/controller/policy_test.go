@@ -0,0 +1,211 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+func expectPolicyViolationEvent(t *testing.T, recorder *record.FakeRecorder, expected string) {
+	t.Helper()
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, EventReasonPolicyViolation) || !strings.Contains(event, expected) {
+			t.Errorf("expected PolicyViolation event mentioning %q, got %q", expected, event)
+		}
+	default:
+		t.Error("expected PolicyViolation event")
+	}
+}
+
+func TestReconcileSkipsSecretViolatingPolicy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	tenant := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"tenant": "true"}}}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "team-a",
+			Annotations: map[string]string{
+				AnnotationAutogenerate: "password",
+				AnnotationRotate:       "1h",
+			},
+		},
+	}
+
+	cfg := config.NewDefaultConfig()
+	cfg.Policies = []config.PolicyRule{{
+		Name:                "slow-rotation-for-tenants",
+		NamespaceSelector:   "tenant=true",
+		MinRotationInterval: config.Duration(24 * time.Hour),
+	}}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tenant, secret).Build()
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        cfg,
+		EventRecorder: recorder,
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "team-a"}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get Secret: %v", err)
+	}
+	if _, ok := updated.Data["password"]; ok {
+		t.Error("expected no value to be generated for a Secret violating a policy")
+	}
+	expectPolicyViolationEvent(t, recorder, "slow-rotation-for-tenants")
+
+	// The same Secret is processed once it carries a compliant interval
+	updated.Annotations[AnnotationRotate] = "7d"
+	if err := fakeClient.Update(context.Background(), &updated); err != nil {
+		t.Fatalf("failed to update Secret: %v", err)
+	}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get Secret: %v", err)
+	}
+	if _, ok := updated.Data["password"]; !ok {
+		t.Error("expected password to be generated for a compliant Secret")
+	}
+}
+
+func TestSecretReplicatorReconciler_PushDeniedByPolicy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "registry",
+			Namespace:   "team-a",
+			Annotations: map[string]string{replicator.AnnotationReplicateTo: "staging"},
+		},
+		Data: map[string][]byte{"token": []byte("value")},
+	}
+	staging := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "staging"}}
+
+	cfg := config.NewDefaultConfig()
+	cfg.Policies = []config.PolicyRule{{
+		Name:              "push-from-infra-only",
+		ExcludeNamespaces: []string{"infra-*"},
+		DeniedFeatures:    []string{config.PolicyFeatureReplicateTo},
+	}}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(source, staging).Build()
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &SecretReplicatorReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        cfg,
+		EventRecorder: recorder,
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "registry", Namespace: "team-a"}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	var pushed corev1.Secret
+	err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "registry", Namespace: "staging"}, &pushed)
+	if err == nil {
+		t.Error("expected no Secret to be pushed from a namespace denied by policy")
+	}
+	expectPolicyViolationEvent(t, recorder, "replicate-to")
+}
+
+func TestSecretReplicatorReconciler_PullFromSourceDeniedByPolicy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "db",
+			Namespace:   "team-a",
+			Annotations: map[string]string{replicator.AnnotationReplicatableFromNamespaces: "*"},
+		},
+		Data: map[string][]byte{"password": []byte("secret")},
+	}
+	target := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "db",
+			Namespace:   "app",
+			Annotations: map[string]string{replicator.AnnotationReplicateFrom: "team-a/db"},
+		},
+	}
+
+	cfg := config.NewDefaultConfig()
+	cfg.Policies = []config.PolicyRule{{
+		Name:           "no-sharing-from-teams",
+		Namespaces:     []string{"team-*"},
+		DeniedFeatures: []string{config.PolicyFeatureReplicatableFrom},
+	}}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(source, target).Build()
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &SecretReplicatorReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        cfg,
+		EventRecorder: recorder,
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "db", Namespace: "app"}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	updated := &corev1.Secret{}
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, updated); err != nil {
+		t.Fatalf("Failed to get target: %v", err)
+	}
+	if len(updated.Data) != 0 {
+		t.Errorf("Expected no data to be pulled from a source denied by policy, got %v", updated.Data)
+	}
+
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, EventReasonReplicationDenied) || !strings.Contains(event, "no-sharing-from-teams") {
+			t.Errorf("Expected ReplicationDenied event for policy, got %q", event)
+		}
+	default:
+		t.Error("Expected ReplicationDenied event")
+	}
+}
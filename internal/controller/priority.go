@@ -0,0 +1,110 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// RotationDuePriority is the workqueue priority of Secrets with an overdue or requested rotation.
+// They are processed before regular events (priority 0) and before the no-op reconciles of the
+// initial list and resyncs (handler.LowPriority), so that a backed-up queue after a restart does
+// not delay overdue rotations.
+const RotationDuePriority = 100
+
+// secretEventHandler enqueues Secrets with a priority depending on their rotation state
+func (r *SecretReconciler) secretEventHandler() handler.EventHandler {
+	return handler.Funcs{
+		CreateFunc: func(ctx context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			r.enqueueWithPriority(ctx, q, e.Object, e.IsInInitialList, e.IsInInitialList)
+		},
+		UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			r.enqueueWithPriority(ctx, q, e.ObjectNew, e.ObjectOld.GetResourceVersion() == e.ObjectNew.GetResourceVersion(), false)
+		},
+		DeleteFunc: func(_ context.Context, e event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			q.Add(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(e.Object)})
+		},
+		GenericFunc: func(ctx context.Context, e event.GenericEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			r.enqueueWithPriority(ctx, q, e.Object, false, false)
+		},
+	}
+}
+
+// enqueueWithPriority adds the Secret to the queue. Without a priority queue, it is added as usual.
+// Secrets of the initial list are prioritized by the checkpoint of the previous leader (see
+// ResyncCheckpoint), if there is one.
+func (r *SecretReconciler) enqueueWithPriority(
+	ctx context.Context,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+	obj client.Object,
+	unchanged, initialList bool,
+) {
+	r.Checkpoint.observe(obj)
+
+	var priority *int
+	var after time.Duration
+	switch {
+	case r.rotationPriority(obj):
+		priority = ptr.To(RotationDuePriority)
+	case initialList:
+		var initial int
+		initial, after = r.Checkpoint.initialListPriority(ctx, obj)
+		priority = &initial
+	case unchanged:
+		priority = ptr.To(handler.LowPriority)
+	}
+	addWithPriority(q, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)}, priority, after)
+}
+
+// addWithPriority adds the request to the queue after the delay. Without a priority queue, the
+// priority is ignored.
+func addWithPriority(
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+	req reconcile.Request,
+	priority *int,
+	after time.Duration,
+) {
+	priorityQueue, ok := q.(priorityqueue.PriorityQueue[reconcile.Request])
+	if !ok {
+		q.AddAfter(req, after)
+		return
+	}
+	priorityQueue.AddWithOpts(priorityqueue.AddOpts{Priority: priority, After: after}, req)
+}
+
+// rotationPriority returns true if a rotation of the Secret is overdue or was requested
+func (r *SecretReconciler) rotationPriority(obj client.Object) bool {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return false
+	}
+	generatedAt := r.getGeneratedAtTime(secret.Annotations)
+	if r.isRotationRequested(secret.Annotations, generatedAt) {
+		return true
+	}
+	return r.isRotationDue(secret, parseSecretAnnotations(secret.Annotations), generatedAt)
+}
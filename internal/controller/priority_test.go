@@ -0,0 +1,77 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+func TestSecretEventHandlerPriority(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	r := &SecretReconciler{Config: config.NewDefaultConfig(), Clock: &MockClock{currentTime: now}}
+
+	newSecret := func(name string, generatedAt time.Time) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name, Namespace: "default",
+				Annotations: map[string]string{
+					AnnotationAutogenerate: "password",
+					AnnotationRotate:       "1h",
+					AnnotationGeneratedAt:  generatedAt.Format(time.RFC3339),
+				},
+			},
+			Data: map[string][]byte{"password": []byte("value")},
+		}
+	}
+
+	queue := priorityqueue.New[reconcile.Request]("test")
+	defer queue.ShutDown()
+	eventHandler := r.secretEventHandler()
+	ctx := context.Background()
+
+	// The initial list is added in this order; the overdue Secret must come first
+	eventHandler.Create(ctx, event.CreateEvent{Object: newSecret("fresh", now), IsInInitialList: true}, queue)
+	eventHandler.Create(ctx, event.CreateEvent{Object: newSecret("overdue", now.Add(-2*time.Hour)), IsInInitialList: true}, queue)
+	eventHandler.Create(ctx, event.CreateEvent{Object: newSecret("new", now)}, queue)
+
+	expected := []struct {
+		name     string
+		priority int
+	}{
+		{"overdue", RotationDuePriority},
+		{"new", 0},
+		{"fresh", handler.LowPriority},
+	}
+	for _, e := range expected {
+		item, priority, _ := queue.GetWithPriority()
+		if item.Name != e.name || priority != e.priority {
+			t.Errorf("expected %s with priority %d, got %s with priority %d", e.name, e.priority, item.Name, priority)
+		}
+		queue.Done(item)
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// MetricPushFailedTargets is the name of the gauge with the number of target namespaces that
+	// push replication failed for and that are waiting for a retry
+	MetricPushFailedTargets = "secret_operator_replication_push_failed_targets"
+
+	// pushRetryBaseDelay is the delay before the first retry of a failed push
+	pushRetryBaseDelay = 5 * time.Second
+	// pushRetryMaxDelay caps the exponential backoff of failed pushes
+	pushRetryMaxDelay = 10 * time.Minute
+)
+
+// pushFailures tracks the target namespaces each push source failed to replicate into, so that
+// the source is requeued with exponential backoff until all of them succeed. The zero value is
+// ready to use.
+type pushFailures struct {
+	mu sync.Mutex
+	// attempts holds the number of consecutive failures per source and target namespace
+	attempts map[types.NamespacedName]map[string]int
+}
+
+// record records the target namespaces that failed in the last push of the source; all other
+// namespaces are considered successful. It returns the delay before the next retry, or 0 if all
+// namespaces succeeded.
+func (p *pushFailures) record(source types.NamespacedName, failed []string) time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(failed) == 0 {
+		delete(p.attempts, source)
+		return 0
+	}
+	if p.attempts == nil {
+		p.attempts = make(map[types.NamespacedName]map[string]int)
+	}
+
+	previous := p.attempts[source]
+	current := make(map[string]int, len(failed))
+	maxAttempts := 0
+	for _, namespace := range failed {
+		current[namespace] = previous[namespace] + 1
+		maxAttempts = max(maxAttempts, current[namespace])
+	}
+	p.attempts[source] = current
+	return pushRetryDelay(maxAttempts)
+}
+
+// forget removes the failures of a source that no longer pushes
+func (p *pushFailures) forget(source types.NamespacedName) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.attempts, source)
+}
+
+// count returns the number of failed targets of all sources
+func (p *pushFailures) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	total := 0
+	for _, namespaces := range p.attempts {
+		total += len(namespaces)
+	}
+	return total
+}
+
+// pushRetryDelay returns the exponential backoff after the given number of consecutive failures
+func pushRetryDelay(attempts int) time.Duration {
+	delay := pushRetryBaseDelay
+	for i := 1; i < attempts && delay < pushRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, pushRetryMaxDelay)
+}
+
+// newPushFailedTargetsGauge returns a gauge with the number of failed push targets
+func newPushFailedTargetsGauge(failures *pushFailures) prometheus.GaugeFunc {
+	return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: MetricPushFailedTargets,
+		Help: "Number of target namespaces that push replication failed for and that are waiting for a retry",
+	}, func() float64 {
+		return float64(failures.count())
+	})
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestPushFailuresBackoff(t *testing.T) {
+	var p pushFailures
+	source := types.NamespacedName{Namespace: "production", Name: "db"}
+
+	expected := []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 40 * time.Second}
+	for i, delay := range expected {
+		if got := p.record(source, []string{"staging"}); got != delay {
+			t.Errorf("expected delay %v after %d failures, got %v", delay, i+1, got)
+		}
+	}
+	if p.count() != 1 {
+		t.Errorf("expected 1 failed target, got %d", p.count())
+	}
+
+	// A newly failing namespace does not reset the backoff of the others
+	if got := p.record(source, []string{"staging", "dev"}); got != 80*time.Second {
+		t.Errorf("expected delay of the most failed namespace, got %v", got)
+	}
+	if p.count() != 2 {
+		t.Errorf("expected 2 failed targets, got %d", p.count())
+	}
+
+	// Succeeded namespaces are cleared and their attempts reset
+	if got := p.record(source, []string{"dev"}); got != 10*time.Second {
+		t.Errorf("expected delay of dev only, got %v", got)
+	}
+	if got := p.record(source, nil); got != 0 {
+		t.Errorf("expected no retry after all namespaces succeeded, got %v", got)
+	}
+	if p.count() != 0 {
+		t.Errorf("expected no failed targets, got %d", p.count())
+	}
+}
+
+func TestPushFailuresForget(t *testing.T) {
+	var p pushFailures
+	a := types.NamespacedName{Namespace: "production", Name: "a"}
+	b := types.NamespacedName{Namespace: "production", Name: "b"}
+	p.record(a, []string{"staging", "dev"})
+	p.record(b, []string{"staging"})
+	if p.count() != 3 {
+		t.Fatalf("expected 3 failed targets, got %d", p.count())
+	}
+
+	p.forget(a)
+	if p.count() != 1 {
+		t.Errorf("expected 1 failed target after forgetting a, got %d", p.count())
+	}
+	if _, ok := p.attempts[a]; ok {
+		t.Errorf("expected no failed namespaces for a, got %v", p.attempts[a])
+	}
+}
+
+func TestPushRetryDelayCapped(t *testing.T) {
+	if got := pushRetryDelay(100); got != pushRetryMaxDelay {
+		t.Errorf("expected delay to be capped at %v, got %v", pushRetryMaxDelay, got)
+	}
+}
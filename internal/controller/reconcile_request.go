@@ -0,0 +1,198 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	isov1alpha1 "github.com/guided-traffic/internal-secrets-operator/api/v1alpha1"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+const (
+	// AnnotationReconcileRequest records the UID of the last ReconcileRequest that triggered a
+	// Secret. Changing it makes the generator and the replicator reconcile the Secret.
+	AnnotationReconcileRequest = AnnotationPrefix + "reconcile-request"
+
+	// EventReasonReconcileRequested is the event reason for a handled ReconcileRequest
+	EventReasonReconcileRequested = "ReconcileRequested"
+
+	// EventReasonReconcileRequestFailed is the event reason for a ReconcileRequest that cannot be handled
+	EventReasonReconcileRequestFailed = "ReconcileRequestFailed"
+)
+
+// ReconcileRequestReconciler handles ReconcileRequests by setting the reconcile-request
+// annotation on the requested Secrets. The changed annotation makes all controllers watching
+// the Secrets reconcile them, in whichever replica or shard runs them.
+type ReconcileRequestReconciler struct {
+	client.Client
+	Scheme        *runtime.Scheme
+	Config        *config.Config
+	EventRecorder record.EventRecorder
+	// Clock is used to get the current time. If nil, time.Now() is used.
+	Clock Clock
+	// Heartbeat records completed reconciles for the liveness check. If nil, nothing is recorded.
+	Heartbeat *Heartbeat
+	// Shard restricts the controller to the namespaces of one shard. If nil, all namespaces are processed.
+	Shard *Shard
+}
+
+// now returns the current time using the Clock if set, otherwise time.Now()
+func (r *ReconcileRequestReconciler) now() time.Time {
+	if r.Clock != nil {
+		return r.Clock.Now()
+	}
+	return time.Now()
+}
+
+// +kubebuilder:rbac:groups=iso.gtrfc.com,resources=reconcilerequests,verbs=get;list;watch
+// +kubebuilder:rbac:groups=iso.gtrfc.com,resources=reconcilerequests/status,verbs=get;update;patch
+
+// Reconcile triggers the Secrets of a ReconcileRequest once and records the outcome in its status
+func (r *ReconcileRequestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var request isov1alpha1.ReconcileRequest
+	if err := r.Get(ctx, req.NamespacedName, &request); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if request.Status.Phase != "" {
+		return ctrl.Result{}, nil
+	}
+
+	secrets, problem, err := r.requestedSecrets(ctx, &request)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if problem != "" {
+		logger.Info("Cannot handle ReconcileRequest", "reason", problem)
+		r.EventRecorder.Eventf(&request, corev1.EventTypeWarning, EventReasonReconcileRequestFailed,
+			"Cannot reconcile: %s", problem)
+		return ctrl.Result{}, r.complete(ctx, &request, isov1alpha1.ReconcileRequestFailed, 0, problem)
+	}
+
+	for i := range secrets {
+		secret := &secrets[i]
+		if secret.Annotations[AnnotationReconcileRequest] == string(request.UID) {
+			continue
+		}
+		original := secret.DeepCopy()
+		secret.Annotations[AnnotationReconcileRequest] = string(request.UID)
+		if err := r.Patch(ctx, secret, client.MergeFrom(original)); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to trigger Secret %s: %w", secret.Name, err)
+		}
+		logger.V(1).Info("Triggered reconciliation", "secret", secret.Name)
+	}
+
+	logger.Info("Handled ReconcileRequest", "secrets", len(secrets))
+	r.EventRecorder.Eventf(&request, corev1.EventTypeNormal, EventReasonReconcileRequested,
+		"Triggered reconciliation of %d Secret(s)", len(secrets))
+	return ctrl.Result{}, r.complete(ctx, &request, isov1alpha1.ReconcileRequestCompleted, int32(len(secrets)), "")
+}
+
+// requestedSecrets returns the Secrets a ReconcileRequest selects. If the request cannot be
+// handled, it returns a description of the problem instead.
+func (r *ReconcileRequestReconciler) requestedSecrets(
+	ctx context.Context,
+	request *isov1alpha1.ReconcileRequest,
+) ([]corev1.Secret, string, error) {
+	if request.Spec.SecretName == "" {
+		var list corev1.SecretList
+		if err := r.List(ctx, &list, client.InNamespace(request.Namespace)); err != nil {
+			return nil, "", fmt.Errorf("failed to list Secrets: %w", err)
+		}
+		var secrets []corev1.Secret
+		for _, secret := range list.Items {
+			if r.reconcilable(&secret) {
+				secrets = append(secrets, secret)
+			}
+		}
+		return secrets, "", nil
+	}
+
+	var secret corev1.Secret
+	key := types.NamespacedName{Namespace: request.Namespace, Name: request.Spec.SecretName}
+	if err := r.Get(ctx, key, &secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Sprintf("Secret %q not found", request.Spec.SecretName), nil
+		}
+		return nil, "", fmt.Errorf("failed to get Secret %s: %w", request.Spec.SecretName, err)
+	}
+	if !r.reconcilable(&secret) {
+		return nil, fmt.Sprintf("Secret %q is not managed by the operator", request.Spec.SecretName), nil
+	}
+	return []corev1.Secret{secret}, "", nil
+}
+
+// reconcilable returns true if the Secret carries operator annotations and is not of an ignored type
+func (r *ReconcileRequestReconciler) reconcilable(secret *corev1.Secret) bool {
+	if r.Config.IsSecretTypeIgnored(string(secret.Type)) {
+		return false
+	}
+	for key := range secret.Annotations {
+		if strings.HasPrefix(key, AnnotationPrefix) && key != AnnotationReconcileRequest {
+			return true
+		}
+	}
+	return false
+}
+
+// complete records the outcome of a ReconcileRequest in its status
+func (r *ReconcileRequestReconciler) complete(
+	ctx context.Context,
+	request *isov1alpha1.ReconcileRequest,
+	phase string,
+	secrets int32,
+	message string,
+) error {
+	request.Status = isov1alpha1.ReconcileRequestStatus{
+		Phase:          phase,
+		Secrets:        secrets,
+		Message:        message,
+		CompletionTime: &metav1.Time{Time: r.now()},
+	}
+	return client.IgnoreNotFound(r.Status().Update(ctx, request))
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *ReconcileRequestReconciler) SetupWithManager(mgr ctrl.Manager) error {
+
+	recorder, err := instrumentEventRecorder(r.EventRecorder)
+	if err != nil {
+		return err
+	}
+	r.EventRecorder = recorder
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("reconcile-request").
+		For(&isov1alpha1.ReconcileRequest{}).
+		Complete(r.Heartbeat.wrap("reconcile-request", r.Shard.wrap(
+			reportPanics(r.EventRecorder, &isov1alpha1.ReconcileRequest{}, r))))
+}
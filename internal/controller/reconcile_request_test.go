@@ -0,0 +1,205 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	isov1alpha1 "github.com/guided-traffic/internal-secrets-operator/api/v1alpha1"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+func newReconcileRequestReconciler(t *testing.T, objects ...client.Object) (*ReconcileRequestReconciler, client.Client) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = isov1alpha1.AddToScheme(scheme)
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&isov1alpha1.ReconcileRequest{}).
+		Build()
+	return &ReconcileRequestReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+		Clock:         &MockClock{currentTime: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)},
+	}, fakeClient
+}
+
+func TestReconcileRequestReconcilerNamespace(t *testing.T) {
+	request := &isov1alpha1.ReconcileRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "sync-now", Namespace: "apps", UID: "request-1"},
+	}
+	generated := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "db",
+			Namespace:   "apps",
+			Annotations: map[string]string{AnnotationAutogenerate: "password"},
+		},
+	}
+	replicated := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ca",
+			Namespace:   "apps",
+			Annotations: map[string]string{AnnotationPrefix + "replicate-from": "pki/ca"},
+		},
+	}
+	unmanaged := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "manual", Namespace: "apps"},
+	}
+	ignored := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "release",
+			Namespace:   "apps",
+			Annotations: map[string]string{AnnotationAutogenerate: "password"},
+		},
+		Type: "helm.sh/release.v1",
+	}
+	otherNamespace := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "db",
+			Namespace:   "other",
+			Annotations: map[string]string{AnnotationAutogenerate: "password"},
+		},
+	}
+	r, fakeClient := newReconcileRequestReconciler(t, request, generated, replicated, unmanaged, ignored, otherNamespace)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "sync-now", Namespace: "apps"}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, key := range []types.NamespacedName{
+		{Namespace: "apps", Name: "db"},
+		{Namespace: "apps", Name: "ca"},
+		{Namespace: "apps", Name: "manual"},
+		{Namespace: "apps", Name: "release"},
+		{Namespace: "other", Name: "db"},
+	} {
+		var secret corev1.Secret
+		if err := fakeClient.Get(ctx, key, &secret); err != nil {
+			t.Fatalf("failed to get Secret %s: %v", key, err)
+		}
+		triggered := secret.Annotations[AnnotationReconcileRequest] == "request-1"
+		expected := key.Namespace == "apps" && (key.Name == "db" || key.Name == "ca")
+		if triggered != expected {
+			t.Errorf("Secret %s: expected triggered=%v, got annotations %v", key, expected, secret.Annotations)
+		}
+	}
+
+	var updated isov1alpha1.ReconcileRequest
+	if err := fakeClient.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get ReconcileRequest: %v", err)
+	}
+	if updated.Status.Phase != isov1alpha1.ReconcileRequestCompleted || updated.Status.Secrets != 2 {
+		t.Errorf("expected Completed with 2 Secrets, got %+v", updated.Status)
+	}
+	if updated.Status.CompletionTime == nil || !updated.Status.CompletionTime.Time.Equal(r.now()) {
+		t.Errorf("expected completion time %v, got %v", r.now(), updated.Status.CompletionTime)
+	}
+
+	// A handled request is not applied again
+	var secret corev1.Secret
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "apps", Name: "db"}, &secret); err != nil {
+		t.Fatalf("failed to get Secret: %v", err)
+	}
+	delete(secret.Annotations, AnnotationReconcileRequest)
+	if err := fakeClient.Update(ctx, &secret); err != nil {
+		t.Fatalf("failed to update Secret: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "apps", Name: "db"}, &secret); err != nil {
+		t.Fatalf("failed to get Secret: %v", err)
+	}
+	if _, ok := secret.Annotations[AnnotationReconcileRequest]; ok {
+		t.Error("expected a handled ReconcileRequest not to trigger again")
+	}
+}
+
+func TestReconcileRequestReconcilerSecret(t *testing.T) {
+	tests := []struct {
+		name          string
+		secretName    string
+		expectPhase   string
+		expectMessage string
+	}{
+		{name: "managed Secret", secretName: "db", expectPhase: isov1alpha1.ReconcileRequestCompleted},
+		{name: "missing Secret", secretName: "missing", expectPhase: isov1alpha1.ReconcileRequestFailed, expectMessage: "not found"},
+		{name: "unmanaged Secret", secretName: "manual", expectPhase: isov1alpha1.ReconcileRequestFailed, expectMessage: "not managed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &isov1alpha1.ReconcileRequest{
+				ObjectMeta: metav1.ObjectMeta{Name: "sync-now", Namespace: "apps", UID: "request-1"},
+				Spec:       isov1alpha1.ReconcileRequestSpec{SecretName: tt.secretName},
+			}
+			managed := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "db",
+					Namespace:   "apps",
+					Annotations: map[string]string{AnnotationAutogenerate: "password"},
+				},
+			}
+			unmanaged := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "manual", Namespace: "apps"},
+			}
+			r, fakeClient := newReconcileRequestReconciler(t, request, managed, unmanaged)
+			ctx := context.Background()
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "sync-now", Namespace: "apps"}}
+
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var updated isov1alpha1.ReconcileRequest
+			if err := fakeClient.Get(ctx, req.NamespacedName, &updated); err != nil {
+				t.Fatalf("failed to get ReconcileRequest: %v", err)
+			}
+			if updated.Status.Phase != tt.expectPhase || !strings.Contains(updated.Status.Message, tt.expectMessage) {
+				t.Errorf("expected phase %s with message %q, got %+v", tt.expectPhase, tt.expectMessage, updated.Status)
+			}
+
+			var secret corev1.Secret
+			if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "apps", Name: "db"}, &secret); err != nil {
+				t.Fatalf("failed to get Secret: %v", err)
+			}
+			triggered := secret.Annotations[AnnotationReconcileRequest] == "request-1"
+			if triggered != (tt.secretName == "db") {
+				t.Errorf("unexpected annotations %v", secret.Annotations)
+			}
+		})
+	}
+}
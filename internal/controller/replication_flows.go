@@ -0,0 +1,233 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+const (
+	// MetricReplicationFlows is the name of the gauge with the number of replicated Secrets per
+	// source namespace, target namespace and replication mode
+	MetricReplicationFlows = "secret_operator_replication_flows"
+
+	// ReplicationModePull marks flows of Secrets pulling from a source (replicate-from)
+	ReplicationModePull = "pull"
+	// ReplicationModePush marks flows of Secrets pushed by a source (replicate-to)
+	ReplicationModePush = "push"
+)
+
+// ReplicationFlows records the namespace pairs that Secrets are replicated across, as observed by
+// the replicator. It answers which namespaces receive data from which other namespaces without
+// scanning the annotations of all Secrets. Flows are kept in memory, so after a restart they are
+// rebuilt as the replicator syncs the targets. Targets that were deleted, are no longer replicated
+// or became stale snapshots are forgotten when the flows are read. A nil ReplicationFlows records
+// nothing.
+type ReplicationFlows struct {
+	mu      sync.Mutex
+	targets map[types.NamespacedName]replicationTarget
+	desc    *prometheus.Desc
+	// reader looks up recorded targets to forget stale ones. If nil, targets are only forgotten
+	// when the replicator observes their deletion.
+	reader client.Reader
+	config config.MetricsConfig
+}
+
+// replicationTarget is the last observed sync of a target Secret
+type replicationTarget struct {
+	mode             string
+	sourceNamespaces []string
+	syncedAt         time.Time
+}
+
+// ReplicationFlow is the aggregated replication from one namespace into another
+type ReplicationFlow struct {
+	SourceNamespace string `json:"sourceNamespace"`
+	TargetNamespace string `json:"targetNamespace"`
+	Mode            string `json:"mode"`
+	// Targets is the number of Secrets in the target namespace replicated from the source namespace
+	Targets int `json:"targets"`
+	// LastSync is the time of the most recent sync of any of the targets
+	LastSync string `json:"lastSync"`
+}
+
+// NewReplicationFlows creates an empty ReplicationFlows. Recorded targets are looked up with
+// the (cached) reader to forget stale ones; the metric follows the per-namespace settings of cfg.
+func NewReplicationFlows(reader client.Reader, cfg config.MetricsConfig) *ReplicationFlows {
+	labels := []string{"mode"}
+	if cfg.PerNamespace {
+		labels = []string{"source_namespace", "target_namespace", "mode"}
+	}
+	return &ReplicationFlows{
+		targets: make(map[types.NamespacedName]replicationTarget),
+		desc: prometheus.NewDesc(MetricReplicationFlows,
+			"Number of Secrets replicated from the source namespace into the target namespace", labels, nil),
+		reader: reader,
+		config: cfg,
+	}
+}
+
+// record records a successful sync of the target from the given source namespaces
+func (f *ReplicationFlows) record(target types.NamespacedName, mode string, sourceNamespaces []string, now time.Time) {
+	if f == nil {
+		return
+	}
+	namespaces := slices.Clone(sourceNamespaces)
+	slices.Sort(namespaces)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.targets[target] = replicationTarget{mode: mode, sourceNamespaces: slices.Compact(namespaces), syncedAt: now}
+}
+
+// forget removes a target that no longer exists
+func (f *ReplicationFlows) forget(target types.NamespacedName) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.targets, target)
+}
+
+// forgetStale forgets the targets that no longer exist, are no longer replicated or became stale
+// snapshots. Targets synced again while they are looked up are kept.
+func (f *ReplicationFlows) forgetStale() {
+	if f.reader == nil {
+		return
+	}
+	f.mu.Lock()
+	synced := make(map[types.NamespacedName]time.Time, len(f.targets))
+	for key, target := range f.targets {
+		synced[key] = target.syncedAt
+	}
+	f.mu.Unlock()
+
+	for key, syncedAt := range synced {
+		var secret corev1.Secret
+		if err := f.reader.Get(context.Background(), key, &secret); client.IgnoreNotFound(err) != nil {
+			metricsLog.Error(err, "Failed to get replication target", "metric", MetricReplicationFlows,
+				"namespace", key.Namespace, "name", key.Name)
+			continue
+		} else if err == nil && replicator.GetReplicatedFromAnnotation(&secret) != "" && !replicator.IsStale(&secret) {
+			continue
+		}
+		f.mu.Lock()
+		if target, ok := f.targets[key]; ok && target.syncedAt.Equal(syncedAt) {
+			delete(f.targets, key)
+		}
+		f.mu.Unlock()
+	}
+}
+
+// Flows returns the observed flows sorted by source namespace, target namespace and mode.
+// If namespace is not empty, only flows from or into that namespace are returned.
+func (f *ReplicationFlows) Flows(namespace string) []ReplicationFlow {
+	if f == nil {
+		return nil
+	}
+	f.forgetStale()
+
+	type flowKey struct{ source, target, mode string }
+	counts := make(map[flowKey]int)
+	lastSync := make(map[flowKey]time.Time)
+
+	f.mu.Lock()
+	for key, target := range f.targets {
+		for _, source := range target.sourceNamespaces {
+			if namespace != "" && source != namespace && key.Namespace != namespace {
+				continue
+			}
+			flow := flowKey{source: source, target: key.Namespace, mode: target.mode}
+			counts[flow]++
+			if target.syncedAt.After(lastSync[flow]) {
+				lastSync[flow] = target.syncedAt
+			}
+		}
+	}
+	f.mu.Unlock()
+
+	flows := make([]ReplicationFlow, 0, len(counts))
+	for key, count := range counts {
+		flows = append(flows, ReplicationFlow{
+			SourceNamespace: key.source,
+			TargetNamespace: key.target,
+			Mode:            key.mode,
+			Targets:         count,
+			LastSync:        lastSync[key].UTC().Format(time.RFC3339),
+		})
+	}
+	sort.Slice(flows, func(i, j int) bool {
+		if flows[i].SourceNamespace != flows[j].SourceNamespace {
+			return flows[i].SourceNamespace < flows[j].SourceNamespace
+		}
+		if flows[i].TargetNamespace != flows[j].TargetNamespace {
+			return flows[i].TargetNamespace < flows[j].TargetNamespace
+		}
+		return flows[i].Mode < flows[j].Mode
+	})
+	return flows
+}
+
+// Describe implements prometheus.Collector
+func (f *ReplicationFlows) Describe(ch chan<- *prometheus.Desc) {
+	ch <- f.desc
+}
+
+// Collect implements prometheus.Collector. Without metrics.perNamespace, the targets are only
+// counted by mode. Otherwise, the metrics.maxNamespaces flows with the most targets are reported
+// individually and all others are aggregated into OtherNamespacesLabel for both namespaces.
+func (f *ReplicationFlows) Collect(ch chan<- prometheus.Metric) {
+	flows := f.Flows("")
+	if !f.config.PerNamespace {
+		byMode := make(map[string]int)
+		for _, flow := range flows {
+			byMode[flow.Mode] += flow.Targets
+		}
+		for mode, targets := range byMode {
+			ch <- prometheus.MustNewConstMetric(f.desc, prometheus.GaugeValue, float64(targets), mode)
+		}
+		return
+	}
+
+	sort.SliceStable(flows, func(i, j int) bool { return flows[i].Targets > flows[j].Targets })
+	others := make(map[string]int)
+	for i, flow := range flows {
+		if i >= maxMetricNamespaces(f.config) {
+			others[flow.Mode] += flow.Targets
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(f.desc, prometheus.GaugeValue, float64(flow.Targets),
+			flow.SourceNamespace, flow.TargetNamespace, flow.Mode)
+	}
+	for mode, targets := range others {
+		ch <- prometheus.MustNewConstMetric(f.desc, prometheus.GaugeValue, float64(targets),
+			OtherNamespacesLabel, OtherNamespacesLabel, mode)
+	}
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+func TestReplicationFlows(t *testing.T) {
+	f := NewReplicationFlows(nil, config.MetricsConfig{})
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	f.record(types.NamespacedName{Namespace: "tenant-a", Name: "ca"}, ReplicationModePull, []string{"infra"}, now)
+	f.record(types.NamespacedName{Namespace: "tenant-a", Name: "proxy"}, ReplicationModePull, []string{"infra", "infra"}, now.Add(time.Hour))
+	f.record(types.NamespacedName{Namespace: "tenant-b", Name: "registry"}, ReplicationModePush, []string{"infra"}, now)
+	f.record(types.NamespacedName{Namespace: "infra", Name: "token"}, ReplicationModePull, []string{"tenant-b"}, now)
+
+	expected := []ReplicationFlow{
+		{SourceNamespace: "infra", TargetNamespace: "tenant-a", Mode: ReplicationModePull, Targets: 2, LastSync: "2025-01-01T01:00:00Z"},
+		{SourceNamespace: "infra", TargetNamespace: "tenant-b", Mode: ReplicationModePush, Targets: 1, LastSync: "2025-01-01T00:00:00Z"},
+		{SourceNamespace: "tenant-b", TargetNamespace: "infra", Mode: ReplicationModePull, Targets: 1, LastSync: "2025-01-01T00:00:00Z"},
+	}
+	if flows := f.Flows(""); !reflect.DeepEqual(flows, expected) {
+		t.Errorf("expected flows %+v, got %+v", expected, flows)
+	}
+
+	// The namespace filter matches flows from and into the namespace
+	if flows := f.Flows("tenant-b"); !reflect.DeepEqual(flows, expected[1:]) {
+		t.Errorf("expected flows %+v, got %+v", expected[1:], flows)
+	}
+
+	// A target moving to another source only counts for its new source
+	f.record(types.NamespacedName{Namespace: "infra", Name: "token"}, ReplicationModePull, []string{"tenant-a"}, now)
+	f.forget(types.NamespacedName{Namespace: "tenant-a", Name: "ca"})
+	f.forget(types.NamespacedName{Namespace: "tenant-a", Name: "proxy"})
+	flows := f.Flows("infra")
+	if len(flows) != 2 || flows[1].SourceNamespace != "tenant-a" {
+		t.Errorf("unexpected flows after update: %+v", flows)
+	}
+
+	// A nil ReplicationFlows records nothing
+	var disabled *ReplicationFlows
+	disabled.record(types.NamespacedName{Namespace: "a", Name: "b"}, ReplicationModePull, []string{"c"}, now)
+	if flows := disabled.Flows(""); flows != nil {
+		t.Errorf("expected no flows, got %+v", flows)
+	}
+}
+
+func TestSecretReplicatorReconciler_RecordsFlows(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ca-bundle",
+			Namespace: "infra",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicatableFromNamespaces: "tenant-a",
+				replicator.AnnotationReplicateTo:                "tenant-b",
+			},
+		},
+		Data: map[string][]byte{"ca.crt": []byte("ca")},
+	}
+	target := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ca",
+			Namespace:   "tenant-a",
+			Annotations: map[string]string{replicator.AnnotationReplicateFrom: "infra/ca-bundle"},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(source, target).Build()
+	flows := NewReplicationFlows(fakeClient, config.MetricsConfig{})
+	reconciler := &SecretReplicatorReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+		Flows:         flows,
+	}
+
+	for _, key := range []types.NamespacedName{
+		{Namespace: "infra", Name: "ca-bundle"},
+		{Namespace: "tenant-a", Name: "ca"},
+	} {
+		if _, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key}); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+
+	var observed []string
+	for _, flow := range flows.Flows("") {
+		observed = append(observed, flow.SourceNamespace+" -> "+flow.TargetNamespace+" ("+flow.Mode+")")
+	}
+	expected := []string{"infra -> tenant-a (pull)", "infra -> tenant-b (push)"}
+	if !reflect.DeepEqual(observed, expected) {
+		t.Errorf("expected flows %v, got %v", expected, observed)
+	}
+
+	// Deleted targets are forgotten
+	if err := fakeClient.Delete(context.Background(), target); err != nil {
+		t.Fatalf("failed to delete target: %v", err)
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "tenant-a", Name: "ca"}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if got := flows.Flows("tenant-a"); len(got) != 0 {
+		t.Errorf("expected flow of deleted target to be forgotten, got %+v", got)
+	}
+}
+
+func TestReplicationFlowsForgetsStaleTargets(t *testing.T) {
+	replica := func(name string, annotations map[string]string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "tenant-a", Annotations: annotations}}
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(
+		replica("replicated", map[string]string{replicator.AnnotationReplicatedFrom: "infra/ca"}),
+		replica("unannotated", nil),
+		replica("stale", map[string]string{
+			replicator.AnnotationReplicatedFrom:  "infra/ca",
+			replicator.AnnotationSourceDeletedAt: "2025-01-01T00:00:00Z",
+		}),
+	).Build()
+
+	f := NewReplicationFlows(fakeClient, config.MetricsConfig{})
+	now := time.Now()
+	for _, name := range []string{"replicated", "unannotated", "stale", "deleted"} {
+		f.record(types.NamespacedName{Namespace: "tenant-a", Name: name}, ReplicationModePush, []string{"infra"}, now)
+	}
+
+	flows := f.Flows("")
+	if len(flows) != 1 || flows[0].Targets != 1 {
+		t.Errorf("expected only the replicated target to be counted, got %+v", flows)
+	}
+	if len(f.targets) != 1 {
+		t.Errorf("expected stale targets to be forgotten, got %d targets", len(f.targets))
+	}
+}
+
+func TestReplicationFlowsCardinality(t *testing.T) {
+	now := time.Now()
+	record := func(f *ReplicationFlows) {
+		for i, target := range []string{"a", "a", "a", "b", "b", "c"} {
+			key := types.NamespacedName{Namespace: target, Name: fmt.Sprintf("replica-%d", i)}
+			f.record(key, ReplicationModePull, []string{"infra"}, now)
+		}
+		f.record(types.NamespacedName{Namespace: "d", Name: "pushed"}, ReplicationModePush, []string{"infra"}, now)
+	}
+
+	// Without per-namespace metrics, only the modes are reported
+	f := NewReplicationFlows(nil, config.MetricsConfig{})
+	record(f)
+	if got := collectFlows(t, f); !reflect.DeepEqual(got, map[string]float64{"pull": 6, "push": 1}) {
+		t.Errorf("unexpected flows by mode: %v", got)
+	}
+
+	// Flows beyond maxNamespaces are aggregated
+	f = NewReplicationFlows(nil, config.MetricsConfig{PerNamespace: true, MaxNamespaces: 2})
+	record(f)
+	expected := map[string]float64{
+		"infra/a/pull":       3,
+		"infra/b/pull":       2,
+		"_other/_other/pull": 1,
+		"_other/_other/push": 1,
+	}
+	if got := collectFlows(t, f); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+// collectFlows returns the values of the flows metric by its labels joined with "/"
+func collectFlows(t *testing.T, f *ReplicationFlows) map[string]float64 {
+	t.Helper()
+	ch := make(chan prometheus.Metric, 10)
+	f.Collect(ch)
+	close(ch)
+	result := make(map[string]float64)
+	for metric := range ch {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("failed to write metric: %v", err)
+		}
+		labels := make(map[string]string)
+		for _, label := range m.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		key := labels["mode"]
+		if source, ok := labels["source_namespace"]; ok {
+			key = source + "/" + labels["target_namespace"] + "/" + key
+		}
+		result[key] = m.GetGauge().GetValue()
+	}
+	return result
+}
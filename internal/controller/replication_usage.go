@@ -0,0 +1,309 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+const (
+	// MetricReplicatedBytes is the name of the gauge with the size of the data replicated from
+	// each source namespace, summed over all targets
+	MetricReplicatedBytes = "secret_operator_replicated_bytes"
+
+	// EventReasonReplicationLimitExceeded is the event reason for replications refused by
+	// replication.maxBytesPerSourceNamespace
+	EventReasonReplicationLimitExceeded = "ReplicationLimitExceeded"
+)
+
+// dataSize returns the size of Secret data (keys and values) in bytes
+func dataSize(data map[string][]byte) int64 {
+	var size int64
+	for key, value := range data {
+		size += int64(len(key) + len(value))
+	}
+	return size
+}
+
+// replicatedSourceNamespaces returns the namespaces of the sources a Secret was replicated from
+func replicatedSourceNamespaces(secret *corev1.Secret) []string {
+	var namespaces []string
+	for _, ref := range strings.Split(replicator.GetReplicatedFromAnnotation(secret), ",") {
+		namespace, _, ok := strings.Cut(strings.TrimSpace(ref), "/")
+		if ok && namespace != "" && !slices.Contains(namespaces, namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
+
+// replicatedBytes returns the size of the replicated data per source namespace. A Secret
+// replicated from several namespaces counts toward each of them.
+func replicatedBytes(secrets []corev1.Secret) map[string]int64 {
+	result := make(map[string]int64)
+	for i := range secrets {
+		size := dataSize(secrets[i].Data)
+		for _, namespace := range replicatedSourceNamespaces(&secrets[i]) {
+			result[namespace] += size
+		}
+	}
+	return result
+}
+
+// replicatedBytesTracker keeps the size of the replicated data per source namespace up to date
+// from the events of the Secret informer, so that neither the replication limit nor the metric
+// lists all Secrets. It is safe for concurrent use.
+type replicatedBytesTracker struct {
+	mu sync.RWMutex
+	// secrets holds the source namespaces and data size of each replicated Secret
+	secrets map[types.NamespacedName]replicatedContribution
+	// totals holds the size of the replicated data per source namespace
+	totals map[string]int64
+	// hasSynced reports whether the initial list was delivered. If nil, the tracker is synced.
+	hasSynced func() bool
+}
+
+// replicatedContribution is the contribution of a replicated Secret to the totals
+type replicatedContribution struct {
+	namespaces []string
+	size       int64
+}
+
+// newReplicatedBytesTracker returns an empty replicatedBytesTracker
+func newReplicatedBytesTracker() *replicatedBytesTracker {
+	return &replicatedBytesTracker{
+		secrets: make(map[types.NamespacedName]replicatedContribution),
+		totals:  make(map[string]int64),
+	}
+}
+
+// setupWithManager feeds the tracker from the Secret informer of the Manager's cache
+func (t *replicatedBytesTracker) setupWithManager(mgr ctrl.Manager) error {
+	informer, err := mgr.GetCache().GetInformer(context.Background(), &corev1.Secret{})
+	if err != nil {
+		return fmt.Errorf("failed to get Secret informer: %w", err)
+	}
+	registration, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if secret, ok := obj.(*corev1.Secret); ok {
+				t.set(secret)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if secret, ok := obj.(*corev1.Secret); ok {
+				t.set(secret)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if secret, ok := obj.(*corev1.Secret); ok {
+				t.remove(client.ObjectKeyFromObject(secret))
+			}
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch Secrets for replicated bytes: %w", err)
+	}
+	t.hasSynced = registration.HasSynced
+	return nil
+}
+
+// set records the replicated data of the Secret, replacing its previous state
+func (t *replicatedBytesTracker) set(secret *corev1.Secret) {
+	key := client.ObjectKeyFromObject(secret)
+	namespaces := replicatedSourceNamespaces(secret)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.removeLocked(key)
+	if len(namespaces) == 0 {
+		return
+	}
+	entry := replicatedContribution{namespaces: namespaces, size: dataSize(secret.Data)}
+	t.secrets[key] = entry
+	for _, namespace := range namespaces {
+		t.totals[namespace] += entry.size
+	}
+}
+
+// remove forgets the replicated data of the Secret
+func (t *replicatedBytesTracker) remove(key types.NamespacedName) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.removeLocked(key)
+}
+
+// removeLocked forgets the replicated data of the Secret. The caller must hold mu.
+func (t *replicatedBytesTracker) removeLocked(key types.NamespacedName) {
+	entry, ok := t.secrets[key]
+	if !ok {
+		return
+	}
+	delete(t.secrets, key)
+	for _, namespace := range entry.namespaces {
+		if t.totals[namespace] -= entry.size; t.totals[namespace] <= 0 {
+			delete(t.totals, namespace)
+		}
+	}
+}
+
+// snapshot returns a copy of the totals, and false if the initial list was not delivered yet
+func (t *replicatedBytesTracker) snapshot() (map[string]int64, bool) {
+	if t.hasSynced != nil && !t.hasSynced() {
+		return nil, false
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	totals := make(map[string]int64, len(t.totals))
+	for namespace, size := range t.totals {
+		totals[namespace] = size
+	}
+	return totals, true
+}
+
+// replicationUsage tracks the replicated bytes per source namespace during a reconcile to enforce
+// replication.maxBytesPerSourceNamespace. A nil replicationUsage allows all replications.
+type replicationUsage struct {
+	limit int64
+	bytes map[string]int64
+	// refused is set once a replication exceeded the limit
+	refused bool
+}
+
+// loadReplicationUsage returns the current replication usage, or nil if no limit is configured.
+// The usage is taken from the tracker; the Secrets are only listed if there is none or it did not
+// sync yet.
+func (r *SecretReplicatorReconciler) loadReplicationUsage(ctx context.Context) (*replicationUsage, error) {
+	limit := r.Config.Replication.MaxBytesPerSourceNamespace
+	if limit <= 0 {
+		return nil, nil
+	}
+	if r.replicatedBytes != nil {
+		if totals, ok := r.replicatedBytes.snapshot(); ok {
+			return &replicationUsage{limit: limit, bytes: totals}, nil
+		}
+	}
+	var list corev1.SecretList
+	if err := r.List(ctx, &list); err != nil {
+		return nil, fmt.Errorf("failed to list Secrets for replication limit: %w", err)
+	}
+	return &replicationUsage{limit: limit, bytes: replicatedBytes(list.Items)}, nil
+}
+
+// snapshot returns a copy of a target before replication, or nil if no limit is enforced
+func (u *replicationUsage) snapshot(target *corev1.Secret) *corev1.Secret {
+	if u == nil {
+		return nil
+	}
+	return target.DeepCopy()
+}
+
+// reserve records the replicated data of target, which replaces previous (nil for new targets).
+// If the data replicated from one of its source namespaces would exceed the limit, nothing is
+// recorded and a message is returned. Replications that do not grow the data replicated from a
+// namespace are always allowed, so lowering the limit never blocks updates of existing targets.
+func (u *replicationUsage) reserve(previous, target *corev1.Secret) string {
+	if u == nil {
+		return ""
+	}
+	var previousNamespaces []string
+	var previousSize int64
+	if previous != nil {
+		previousNamespaces = replicatedSourceNamespaces(previous)
+		previousSize = dataSize(previous.Data)
+	}
+
+	size := dataSize(target.Data)
+	deltas := make(map[string]int64)
+	for _, namespace := range replicatedSourceNamespaces(target) {
+		delta := size
+		if slices.Contains(previousNamespaces, namespace) {
+			delta -= previousSize
+		}
+		if delta > 0 && u.bytes[namespace]+delta > u.limit {
+			u.refused = true
+			return fmt.Sprintf("Replicating %d bytes would exceed the limit of %d bytes replicated from namespace %s (%d bytes in use)",
+				size, u.limit, namespace, u.bytes[namespace])
+		}
+		deltas[namespace] = delta
+	}
+	for _, namespace := range previousNamespaces {
+		if _, ok := deltas[namespace]; !ok {
+			deltas[namespace] = -previousSize
+		}
+	}
+	for namespace, delta := range deltas {
+		u.bytes[namespace] += delta
+	}
+	return ""
+}
+
+// replicatedBytesCollector is a gauge with the size of the data replicated from each source
+// namespace, taken from the tracker on every scrape. Only the namespaces with the most replicated
+// bytes are reported individually; all others are aggregated into OtherNamespacesLabel.
+type replicatedBytesCollector struct {
+	desc    *prometheus.Desc
+	tracker *replicatedBytesTracker
+	limit   int
+}
+
+// newReplicatedBytesCollector returns a replicatedBytesCollector
+func newReplicatedBytesCollector(tracker *replicatedBytesTracker, cfg config.MetricsConfig) *replicatedBytesCollector {
+	return &replicatedBytesCollector{
+		desc: prometheus.NewDesc(MetricReplicatedBytes,
+			"Size in bytes of the data replicated from the source namespace, summed over all targets",
+			[]string{"source_namespace"}, nil),
+		tracker: tracker,
+		limit:   maxMetricNamespaces(cfg),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *replicatedBytesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector
+func (c *replicatedBytesCollector) Collect(ch chan<- prometheus.Metric) {
+	totals, ok := c.tracker.snapshot()
+	if !ok {
+		return
+	}
+
+	counts := make(map[string]int)
+	for namespace, size := range totals {
+		counts[namespace] = int(size)
+	}
+	for namespace, size := range topNamespaces(counts, c.limit) {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(size), namespace)
+	}
+}
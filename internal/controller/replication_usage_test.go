@@ -0,0 +1,227 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"maps"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+func replicatedSecret(namespace, replicatedFrom string, size int) corev1.Secret {
+	return corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "replica",
+			Namespace:   namespace,
+			Annotations: map[string]string{replicator.AnnotationReplicatedFrom: replicatedFrom},
+		},
+		Data: map[string][]byte{"k": make([]byte, size-1)},
+	}
+}
+
+func TestReplicatedBytes(t *testing.T) {
+	secrets := []corev1.Secret{
+		replicatedSecret("a", "infra/ca", 100),
+		replicatedSecret("b", "infra/ca", 100),
+		replicatedSecret("c", "infra/ca,tenant/db", 50),
+		{ObjectMeta: metav1.ObjectMeta{Name: "plain", Namespace: "infra"}, Data: map[string][]byte{"k": []byte("v")}},
+	}
+	expected := map[string]int64{"infra": 250, "tenant": 50}
+	if got := replicatedBytes(secrets); !maps.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestReplicatedBytesTracker(t *testing.T) {
+	tracker := newReplicatedBytesTracker()
+	a := replicatedSecret("a", "infra/ca", 100)
+	c := replicatedSecret("c", "infra/ca,tenant/db", 50)
+	tracker.set(&a)
+	tracker.set(&c)
+	tracker.set(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "plain", Namespace: "infra"}})
+	if got, _ := tracker.snapshot(); !maps.Equal(got, map[string]int64{"infra": 150, "tenant": 50}) {
+		t.Errorf("unexpected totals %v", got)
+	}
+
+	// An update replaces the previous contribution of the Secret
+	c = replicatedSecret("c", "tenant/db", 30)
+	tracker.set(&c)
+	if got, _ := tracker.snapshot(); !maps.Equal(got, map[string]int64{"infra": 100, "tenant": 30}) {
+		t.Errorf("unexpected totals after update %v", got)
+	}
+
+	// Namespaces without replicated data are forgotten
+	tracker.remove(types.NamespacedName{Name: "replica", Namespace: "c"})
+	if got, _ := tracker.snapshot(); !maps.Equal(got, map[string]int64{"infra": 100}) {
+		t.Errorf("unexpected totals after delete %v", got)
+	}
+
+	// Before the initial list was delivered, the totals are not used
+	tracker.hasSynced = func() bool { return false }
+	if _, ok := tracker.snapshot(); ok {
+		t.Error("expected an unsynced tracker not to report totals")
+	}
+	reconciler := &SecretReplicatorReconciler{
+		Client:          fake.NewClientBuilder().WithObjects(&c).Build(),
+		Config:          config.NewDefaultConfig(),
+		replicatedBytes: tracker,
+	}
+	reconciler.Config.Replication.MaxBytesPerSourceNamespace = 1000
+	usage, err := reconciler.loadReplicationUsage(context.Background())
+	if err != nil || !maps.Equal(usage.bytes, map[string]int64{"tenant": 30}) {
+		t.Errorf("expected the usage to be listed before the tracker synced, got %v, %v", usage, err)
+	}
+	tracker.hasSynced = nil
+	usage, err = reconciler.loadReplicationUsage(context.Background())
+	if err != nil || !maps.Equal(usage.bytes, map[string]int64{"infra": 100}) {
+		t.Errorf("expected the usage of the tracker, got %v, %v", usage, err)
+	}
+}
+
+func TestReplicationUsageReserve(t *testing.T) {
+	usage := &replicationUsage{limit: 300, bytes: map[string]int64{"infra": 200}}
+
+	existing := replicatedSecret("a", "infra/ca", 100)
+	grown := replicatedSecret("a", "infra/ca", 150)
+	if msg := usage.reserve(&existing, &grown); msg != "" {
+		t.Errorf("expected growth within the limit to be allowed, got %q", msg)
+	}
+	if usage.bytes["infra"] != 250 {
+		t.Errorf("expected 250 bytes in use, got %d", usage.bytes["infra"])
+	}
+
+	added := replicatedSecret("b", "infra/ca", 100)
+	if msg := usage.reserve(nil, &added); !strings.Contains(msg, "namespace infra") {
+		t.Errorf("expected new target to exceed the limit, got %q", msg)
+	}
+	if !usage.refused || usage.bytes["infra"] != 250 {
+		t.Errorf("expected refused replication not to be recorded, got %+v", usage)
+	}
+
+	// Moving a target to another source namespace releases its bytes
+	moved := replicatedSecret("a", "tenant/db", 150)
+	if msg := usage.reserve(&grown, &moved); msg != "" {
+		t.Errorf("unexpected refusal: %q", msg)
+	}
+	if usage.bytes["infra"] != 100 || usage.bytes["tenant"] != 150 {
+		t.Errorf("expected bytes to move to the new source namespace, got %v", usage.bytes)
+	}
+
+	// Targets that do not grow are never refused, even above the limit
+	usage.bytes["infra"] = 1000
+	shrunk := replicatedSecret("c", "infra/ca", 10)
+	previous := replicatedSecret("c", "infra/ca", 20)
+	if msg := usage.reserve(&previous, &shrunk); msg != "" {
+		t.Errorf("expected shrinking target to be allowed, got %q", msg)
+	}
+
+	var unlimited *replicationUsage
+	if msg := unlimited.reserve(nil, &added); msg != "" || unlimited.snapshot(&added) != nil {
+		t.Error("expected nil replicationUsage to allow everything")
+	}
+}
+
+func TestSecretReplicatorReconciler_ReplicationLimit(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	// 45 bytes per replica: the limit allows two of them
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ca",
+			Namespace: "infra",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicateTo:                "a,b,c",
+				replicator.AnnotationReplicatableFromNamespaces: "d",
+			},
+		},
+		Data: map[string][]byte{"token": []byte(strings.Repeat("x", 40))},
+	}
+	target := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ca",
+			Namespace:   "d",
+			Annotations: map[string]string{replicator.AnnotationReplicateFrom: "infra/ca"},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(source, target).Build()
+	cfg := config.NewDefaultConfig()
+	cfg.Replication.MaxBytesPerSourceNamespace = 100
+	recorder := record.NewFakeRecorder(20)
+	reconciler := &SecretReplicatorReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        cfg,
+		EventRecorder: recorder,
+	}
+	ctx := context.Background()
+
+	result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "ca", Namespace: "infra"}})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if result.RequeueAfter != quotaRetryInterval {
+		t.Errorf("expected retry after %v, got %v", quotaRetryInterval, result.RequeueAfter)
+	}
+	for namespace, expected := range map[string]bool{"a": true, "b": true, "c": false} {
+		err := fakeClient.Get(ctx, types.NamespacedName{Name: "ca", Namespace: namespace}, &corev1.Secret{})
+		if exists := err == nil; exists != expected {
+			t.Errorf("namespace %s: expected pushed=%v, got error %v", namespace, expected, err)
+		} else if !exists && !apierrors.IsNotFound(err) {
+			t.Errorf("namespace %s: unexpected error %v", namespace, err)
+		}
+	}
+
+	// Pulling from the same namespace is limited as well
+	result, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "ca", Namespace: "d"}})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if result.RequeueAfter != quotaRetryInterval {
+		t.Errorf("expected retry after %v, got %v", quotaRetryInterval, result.RequeueAfter)
+	}
+	var pulled corev1.Secret
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: "ca", Namespace: "d"}, &pulled); err != nil {
+		t.Fatalf("failed to get pull target: %v", err)
+	}
+	if len(pulled.Data) != 0 {
+		t.Errorf("expected pull target not to be replicated, got %d keys", len(pulled.Data))
+	}
+
+	exceeded := 0
+	for len(recorder.Events) > 0 {
+		if strings.Contains(<-recorder.Events, EventReasonReplicationLimitExceeded) {
+			exceeded++
+		}
+	}
+	if exceeded != 2 {
+		t.Errorf("expected a %s event for the push and the pull, got %d", EventReasonReplicationLimitExceeded, exceeded)
+	}
+}
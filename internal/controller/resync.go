@@ -0,0 +1,73 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// resyncSource returns a source that enqueues all Secrets passing the predicate every period.
+// It resyncs a single controller independently of the informer resync, which applies to all
+// controllers sharing the cache.
+func resyncSource(reader client.Reader, period time.Duration, filter predicate.Predicate) source.Source {
+	return source.Func(func(ctx context.Context, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) error {
+		go func() {
+			ticker := time.NewTicker(period)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					resync(ctx, reader, filter, queue)
+				}
+			}
+		}()
+		return nil
+	})
+}
+
+// resync enqueues all Secrets passing the predicate
+func resync(
+	ctx context.Context,
+	reader client.Reader,
+	filter predicate.Predicate,
+	queue workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	secrets := &corev1.SecretList{}
+	if err := reader.List(ctx, secrets); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list Secrets for resync")
+		return
+	}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if filter.Generic(event.GenericEvent{Object: secret}) {
+			queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}})
+		}
+	}
+}
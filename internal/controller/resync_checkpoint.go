@@ -0,0 +1,381 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"hash/fnv"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// AnnotationResyncCheckpoint records the checkpoint on the checkpoint Lease: the resource
+	// version up to which the leader reconciled all events of Secrets
+	AnnotationResyncCheckpoint = AnnotationPrefix + "resync-checkpoint"
+
+	// MetricDifferentialResyncDeferred is the name of the counter of Secrets of the initial list
+	// that were deferred because they did not change since the checkpoint of the previous leader
+	MetricDifferentialResyncDeferred = "secret_operator_differential_resync_deferred_total"
+
+	// checkpointWriteTimeout bounds the write of the final checkpoint when the leader stops
+	checkpointWriteTimeout = 5 * time.Second
+)
+
+var differentialResyncDeferred = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: MetricDifferentialResyncDeferred,
+	Help: "Secrets of the initial list that were deferred because they did not change since the checkpoint of the previous leader",
+})
+
+// pendingVersions are the resource versions of a Secret that was enqueued but not reconciled yet
+type pendingVersions struct {
+	// oldest is the oldest resource version that was not reconciled
+	oldest uint64
+	// latest is the latest resource version that was enqueued
+	latest uint64
+}
+
+// ResyncCheckpoint records in a Lease the resource version up to which a controller reconciled
+// all Secret events (a low-water mark). Each controller uses its own checkpoint and Lease. After a
+// leader failover or restart, the new leader reconciles the Secrets of its initial list that
+// changed since the checkpoint of its predecessor first and spreads all others over a window,
+// instead of reconciling the whole cluster at once.
+// Overdue rotations are never deferred.
+//
+// Resource versions are compared as integers, which holds for etcd-backed API servers. Secrets
+// with a resource version that is not an integer are treated as changed. A nil ResyncCheckpoint
+// records nothing and defers nothing.
+type ResyncCheckpoint struct {
+	// Client writes the Lease
+	Client client.Client
+	// APIReader reads the Lease, so that the Leases of the cluster are not cached
+	APIReader client.Reader
+	// Key is the namespace and name of the Lease
+	Key types.NamespacedName
+	// Interval is how often the checkpoint is written
+	Interval time.Duration
+	// Spread is the window over which the Secrets that did not change since the checkpoint are reconciled
+	Spread time.Duration
+
+	mu sync.Mutex
+	// previous is the checkpoint of the previous leader (0 if there is none). It is loaded on first use.
+	previous uint64
+	loaded   bool
+	// highWater is the highest resource version that was enqueued
+	highWater uint64
+	// pending holds the resource versions of the Secrets that were enqueued but not reconciled yet
+	pending map[types.NamespacedName]pendingVersions
+	// written is the last checkpoint written to the Lease
+	written uint64
+	// deferredUntil is when the last deferred Secret of the initial list becomes due
+	deferredUntil time.Time
+}
+
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;create;update
+
+// SetupWithManager registers the metric and adds the checkpoint writer to the Manager. It only runs on the leader.
+func (c *ResyncCheckpoint) SetupWithManager(mgr ctrl.Manager) error {
+	if err := registerCollector(differentialResyncDeferred); err != nil {
+		return err
+	}
+	return mgr.Add(c)
+}
+
+// Start implements manager.Runnable
+func (c *ResyncCheckpoint) Start(ctx context.Context) error {
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Runnables stop before the leader lease is released, so the final checkpoint is
+			// written while this process is still the leader
+			writeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), checkpointWriteTimeout)
+			c.record(writeCtx)
+			cancel()
+			return nil
+		case <-ticker.C:
+			c.record(ctx)
+		}
+	}
+}
+
+// record writes the current checkpoint to the Lease if it changed
+func (c *ResyncCheckpoint) record(ctx context.Context) {
+	c.mu.Lock()
+	checkpoint := c.checkpoint()
+	unchanged := checkpoint == 0 || checkpoint == c.written
+	c.mu.Unlock()
+	if unchanged {
+		return
+	}
+
+	if err := c.write(ctx, checkpoint); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to write resync checkpoint", "lease", c.Key)
+		return
+	}
+	c.mu.Lock()
+	c.written = checkpoint
+	c.mu.Unlock()
+}
+
+// checkpoint returns the resource version up to which all enqueued Secrets were reconciled.
+// The caller must hold the lock.
+func (c *ResyncCheckpoint) checkpoint() uint64 {
+	checkpoint := c.highWater
+	for _, versions := range c.pending {
+		checkpoint = min(checkpoint, versions.oldest-1)
+	}
+	return checkpoint
+}
+
+// write stores the checkpoint on the Lease, creating it if needed
+func (c *ResyncCheckpoint) write(ctx context.Context, checkpoint uint64) error {
+	value := strconv.FormatUint(checkpoint, 10)
+
+	var lease coordinationv1.Lease
+	err := c.APIReader.Get(ctx, c.Key, &lease)
+	if apierrors.IsNotFound(err) {
+		lease = coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{
+			Name:        c.Key.Name,
+			Namespace:   c.Key.Namespace,
+			Annotations: map[string]string{AnnotationResyncCheckpoint: value},
+		}}
+		return c.Client.Create(ctx, &lease)
+	}
+	if err != nil {
+		return err
+	}
+
+	if lease.Annotations == nil {
+		lease.Annotations = make(map[string]string)
+	}
+	lease.Annotations[AnnotationResyncCheckpoint] = value
+	return c.Client.Update(ctx, &lease)
+}
+
+// load reads the checkpoint of the previous leader. It returns 0 if there is none.
+func (c *ResyncCheckpoint) load(ctx context.Context) uint64 {
+	var lease coordinationv1.Lease
+	if err := c.APIReader.Get(ctx, c.Key, &lease); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.FromContext(ctx).Error(err, "Failed to read resync checkpoint, reconciling all Secrets", "lease", c.Key)
+		}
+		return 0
+	}
+	checkpoint, _ := strconv.ParseUint(lease.Annotations[AnnotationResyncCheckpoint], 10, 64)
+	return checkpoint
+}
+
+// sinceCheckpoint returns whether the Secret changed since the checkpoint of the previous leader.
+// ok is false if there is no checkpoint.
+func (c *ResyncCheckpoint) sinceCheckpoint(ctx context.Context, obj client.Object) (changed, ok bool) {
+	if c == nil {
+		return false, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.loaded {
+		c.previous = c.load(ctx)
+		c.loaded = true
+	}
+	if c.previous == 0 {
+		return false, false
+	}
+	version, valid := parseResourceVersion(obj.GetResourceVersion())
+	return !valid || version > c.previous, true
+}
+
+// initialListPriority returns the priority and delay of a Secret of the initial list. Secrets that
+// changed since the checkpoint of the previous leader get the regular priority; it is explicit,
+// since handler.Funcs defaults the initial list to handler.LowPriority. All others have low
+// priority and, if there is a checkpoint, are deferred over the spread window.
+func (c *ResyncCheckpoint) initialListPriority(ctx context.Context, obj client.Object) (int, time.Duration) {
+	changed, ok := c.sinceCheckpoint(ctx, obj)
+	switch {
+	case !ok:
+		return handler.LowPriority, 0
+	case changed:
+		return 0, 0
+	}
+	differentialResyncDeferred.Inc()
+	deferral := c.deferral(client.ObjectKeyFromObject(obj))
+	c.mu.Lock()
+	if until := time.Now().Add(deferral); until.After(c.deferredUntil) {
+		c.deferredUntil = until
+	}
+	c.mu.Unlock()
+	return handler.LowPriority, deferral
+}
+
+// pendingDeferral returns when the last deferred Secret of the initial list becomes due. Deferred
+// Secrets are not counted in the workqueue depth until then. A nil ResyncCheckpoint returns the
+// zero time.
+func (c *ResyncCheckpoint) pendingDeferral() time.Time {
+	if c == nil {
+		return time.Time{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.deferredUntil
+}
+
+// unchangedSinceCheckpoint returns true if there is a checkpoint of the previous leader and the
+// object did not change since. A nil ResyncCheckpoint returns false.
+func (c *ResyncCheckpoint) unchangedSinceCheckpoint(ctx context.Context, obj client.Object) bool {
+	changed, ok := c.sinceCheckpoint(ctx, obj)
+	return ok && !changed
+}
+
+// eventHandler returns an event handler that enqueues Secrets like handler.EnqueueRequestForObject,
+// records them with observe and prioritizes the initial list by the checkpoint
+func (c *ResyncCheckpoint) eventHandler() handler.EventHandler {
+	return handler.Funcs{
+		CreateFunc: func(ctx context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			var priority *int
+			var after time.Duration
+			if e.IsInInitialList {
+				var initial int
+				initial, after = c.initialListPriority(ctx, e.Object)
+				priority = &initial
+			}
+			c.enqueue(q, e.Object, priority, after)
+		},
+		UpdateFunc: func(_ context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			var priority *int
+			if e.ObjectOld.GetResourceVersion() == e.ObjectNew.GetResourceVersion() {
+				priority = ptr.To(handler.LowPriority)
+			}
+			c.enqueue(q, e.ObjectNew, priority, 0)
+		},
+		DeleteFunc: func(_ context.Context, e event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			q.Add(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(e.Object)})
+		},
+		GenericFunc: func(_ context.Context, e event.GenericEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			c.enqueue(q, e.Object, nil, 0)
+		},
+	}
+}
+
+// enqueue records the Secret with observe and adds it to the queue
+func (c *ResyncCheckpoint) enqueue(
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+	obj client.Object,
+	priority *int,
+	after time.Duration,
+) {
+	c.observe(obj)
+	addWithPriority(q, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)}, priority, after)
+}
+
+// deferral returns how long a Secret that did not change since the checkpoint is deferred.
+// The delay is derived from the name, so that the Secrets are spread evenly over the window.
+func (c *ResyncCheckpoint) deferral(key types.NamespacedName) time.Duration {
+	if c.Spread <= 0 {
+		return 0
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key.String()))
+	return time.Duration(h.Sum64() % uint64(c.Spread))
+}
+
+// observe records that the Secret is enqueued. It must be called before the Secret is added to
+// the queue: a worker may reconcile the Secret right away, and a version observed after its
+// reconcile would stay pending and hold back the checkpoint. The checkpoint does not pass a
+// version that is observed but not reconciled yet.
+func (c *ResyncCheckpoint) observe(obj client.Object) {
+	if c == nil {
+		return
+	}
+	version, ok := parseResourceVersion(obj.GetResourceVersion())
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.highWater = max(c.highWater, version)
+	if c.pending == nil {
+		c.pending = make(map[types.NamespacedName]pendingVersions)
+	}
+	key := client.ObjectKeyFromObject(obj)
+	versions, ok := c.pending[key]
+	if !ok {
+		versions.oldest = version
+	}
+	versions.oldest = min(versions.oldest, version)
+	versions.latest = max(versions.latest, version)
+	c.pending[key] = versions
+}
+
+// wrap returns a Reconciler that marks the enqueued versions of a Secret as reconciled after a
+// successful reconcile. A nil ResyncCheckpoint returns the reconciler unchanged.
+func (c *ResyncCheckpoint) wrap(reconciler reconcile.Reconciler) reconcile.Reconciler {
+	if c == nil {
+		return reconciler
+	}
+	return reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		c.mu.Lock()
+		latest := c.pending[req.NamespacedName].latest
+		c.mu.Unlock()
+
+		result, err := reconciler.Reconcile(ctx, req)
+		if err == nil {
+			c.reconciled(req.NamespacedName, latest)
+		}
+		return result, err
+	})
+}
+
+// reconciled marks the versions of a Secret up to latest as reconciled. Versions enqueued during
+// the reconcile stay pending, since the Secret is in the queue again.
+func (c *ResyncCheckpoint) reconciled(key types.NamespacedName, latest uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	versions, ok := c.pending[key]
+	switch {
+	case !ok:
+	case versions.latest <= latest:
+		delete(c.pending, key)
+	default:
+		versions.oldest = versions.latest
+		c.pending[key] = versions
+	}
+}
+
+// parseResourceVersion parses a resource version as an integer
+func parseResourceVersion(resourceVersion string) (uint64, bool) {
+	version, err := strconv.ParseUint(resourceVersion, 10, 64)
+	return version, err == nil && version > 0
+}
@@ -0,0 +1,297 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+var checkpointKey = types.NamespacedName{Namespace: "operator", Name: "secret-operator-checkpoint"}
+
+// newCheckpointClient returns a fake client with the checkpoint Lease (if checkpoint is not empty)
+func newCheckpointClient(checkpoint string) client.Client {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	if checkpoint != "" {
+		builder = builder.WithObjects(&coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{
+			Name: checkpointKey.Name, Namespace: checkpointKey.Namespace,
+			Annotations: map[string]string{AnnotationResyncCheckpoint: checkpoint},
+		}})
+	}
+	return builder.Build()
+}
+
+// managedSecret returns a Secret with a generated field and the given resource version
+func managedSecret(name, resourceVersion string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: "default", ResourceVersion: resourceVersion,
+			Annotations: map[string]string{AnnotationAutogenerate: "password"},
+		},
+		Data: map[string][]byte{"password": []byte("value")},
+	}
+}
+
+func TestSecretEventHandlerCheckpoint(t *testing.T) {
+	fakeClient := newCheckpointClient("100")
+	r := &SecretReconciler{
+		Config: config.NewDefaultConfig(),
+		Checkpoint: &ResyncCheckpoint{
+			Client: fakeClient, APIReader: fakeClient, Key: checkpointKey, Spread: time.Hour,
+		},
+	}
+
+	queue := priorityqueue.New[reconcile.Request]("test")
+	defer queue.ShutDown()
+	eventHandler := r.secretEventHandler()
+	ctx := context.Background()
+
+	eventHandler.Create(ctx, event.CreateEvent{Object: managedSecret("unchanged", "50"), IsInInitialList: true}, queue)
+	eventHandler.Create(ctx, event.CreateEvent{Object: managedSecret("changed", "150"), IsInInitialList: true}, queue)
+
+	// The Secret that changed since the checkpoint is processed with regular priority, the
+	// unchanged one is deferred within the spread
+	item, priority, _ := queue.GetWithPriority()
+	if item.Name != "changed" || priority != 0 {
+		t.Errorf("expected changed with priority 0, got %s with priority %d", item.Name, priority)
+	}
+	queue.Done(item)
+	if queue.Len() != 0 {
+		t.Errorf("expected the unchanged Secret to be deferred, got %d ready items", queue.Len())
+	}
+	if r.Checkpoint.pendingDeferral().IsZero() {
+		t.Error("expected the deferral to be recorded")
+	}
+
+	// Both Secrets are pending, so the checkpoint stays before the unchanged one
+	if checkpoint := r.Checkpoint.checkpoint(); checkpoint != 49 {
+		t.Errorf("expected checkpoint 49, got %d", checkpoint)
+	}
+}
+
+func TestSecretEventHandlerWithoutCheckpoint(t *testing.T) {
+	fakeClient := newCheckpointClient("")
+	r := &SecretReconciler{
+		Config: config.NewDefaultConfig(),
+		Checkpoint: &ResyncCheckpoint{
+			Client: fakeClient, APIReader: fakeClient, Key: checkpointKey, Spread: time.Hour,
+		},
+	}
+
+	queue := priorityqueue.New[reconcile.Request]("test")
+	defer queue.ShutDown()
+	r.secretEventHandler().Create(context.Background(),
+		event.CreateEvent{Object: managedSecret("unchanged", "50"), IsInInitialList: true}, queue)
+
+	// Without a checkpoint of a previous leader, the initial list is not deferred
+	item, priority, _ := queue.GetWithPriority()
+	if item.Name != "unchanged" || priority != handler.LowPriority {
+		t.Errorf("expected unchanged with low priority, got %s with priority %d", item.Name, priority)
+	}
+	queue.Done(item)
+}
+
+func TestResyncCheckpointRecord(t *testing.T) {
+	fakeClient := newCheckpointClient("")
+	c := &ResyncCheckpoint{Client: fakeClient, APIReader: fakeClient, Key: checkpointKey}
+	ctx := context.Background()
+
+	failing := true
+	reconciler := c.wrap(reconcile.Func(func(context.Context, ctrl.Request) (ctrl.Result, error) {
+		if failing {
+			return ctrl.Result{}, errors.New("failed")
+		}
+		return ctrl.Result{}, nil
+	}))
+	reconcileSecret := func(name string) {
+		_, _ = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: name}})
+	}
+	leaseCheckpoint := func() string {
+		var lease coordinationv1.Lease
+		if err := fakeClient.Get(ctx, checkpointKey, &lease); err != nil {
+			return ""
+		}
+		return lease.Annotations[AnnotationResyncCheckpoint]
+	}
+
+	c.observe(managedSecret("a", "10"))
+	c.observe(managedSecret("b", "20"))
+	c.record(ctx)
+	if got := leaseCheckpoint(); got != "9" {
+		t.Errorf("expected checkpoint 9 before any reconcile, got %q", got)
+	}
+
+	// A failed reconcile keeps the Secret pending
+	reconcileSecret("a")
+	reconcileSecret("b")
+	if checkpoint := c.checkpoint(); checkpoint != 9 {
+		t.Errorf("expected checkpoint 9 after failed reconciles, got %d", checkpoint)
+	}
+
+	failing = false
+	reconcileSecret("a")
+	c.record(ctx)
+	if got := leaseCheckpoint(); got != "19" {
+		t.Errorf("expected checkpoint 19, got %q", got)
+	}
+	reconcileSecret("b")
+	c.record(ctx)
+	if got := leaseCheckpoint(); got != "20" {
+		t.Errorf("expected checkpoint 20 after all Secrets were reconciled, got %q", got)
+	}
+
+	// The next leader reads the checkpoint
+	next := &ResyncCheckpoint{Client: fakeClient, APIReader: fakeClient, Key: checkpointKey}
+	if changed, ok := next.sinceCheckpoint(ctx, managedSecret("b", "20")); !ok || changed {
+		t.Errorf("expected Secret at the checkpoint to be unchanged, got changed=%v ok=%v", changed, ok)
+	}
+	if changed, ok := next.sinceCheckpoint(ctx, managedSecret("c", "21")); !ok || !changed {
+		t.Errorf("expected newer Secret to be changed, got changed=%v ok=%v", changed, ok)
+	}
+}
+
+func TestResyncCheckpointEventDuringReconcile(t *testing.T) {
+	c := &ResyncCheckpoint{}
+	c.observe(managedSecret("a", "10"))
+
+	reconciler := c.wrap(reconcile.Func(func(context.Context, ctrl.Request) (ctrl.Result, error) {
+		// The Secret changes while it is reconciled and is enqueued again
+		c.observe(managedSecret("a", "15"))
+		return ctrl.Result{}, nil
+	}))
+	if _, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "a"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if checkpoint := c.checkpoint(); checkpoint != 14 {
+		t.Errorf("expected the new version to stay pending (checkpoint 14), got %d", checkpoint)
+	}
+}
+
+func TestResyncCheckpointDeferral(t *testing.T) {
+	c := &ResyncCheckpoint{Spread: 10 * time.Minute}
+	key := types.NamespacedName{Namespace: "default", Name: "db"}
+	delay := c.deferral(key)
+	if delay < 0 || delay >= c.Spread {
+		t.Errorf("expected a delay within the spread, got %v", delay)
+	}
+	if again := c.deferral(key); again != delay {
+		t.Errorf("expected a stable delay, got %v and %v", delay, again)
+	}
+	if delay := (&ResyncCheckpoint{}).deferral(key); delay != 0 {
+		t.Errorf("expected no delay without spread, got %v", delay)
+	}
+}
+
+// observingQueue records whether the checkpoint observed a Secret before it was added to the queue
+type observingQueue struct {
+	priorityqueue.PriorityQueue[reconcile.Request]
+	checkpoint *ResyncCheckpoint
+	observed   map[string]bool
+}
+
+func (q *observingQueue) AddWithOpts(o priorityqueue.AddOpts, items ...reconcile.Request) {
+	q.checkpoint.mu.Lock()
+	for _, item := range items {
+		_, ok := q.checkpoint.pending[item.NamespacedName]
+		q.observed[item.Name] = ok
+	}
+	q.checkpoint.mu.Unlock()
+	q.PriorityQueue.AddWithOpts(o, items...)
+}
+
+func TestSecretEventHandlerObservesBeforeEnqueue(t *testing.T) {
+	r := &SecretReconciler{Config: config.NewDefaultConfig(), Checkpoint: &ResyncCheckpoint{}}
+	queue := &observingQueue{
+		PriorityQueue: priorityqueue.New[reconcile.Request]("test"),
+		checkpoint:    r.Checkpoint,
+		observed:      make(map[string]bool),
+	}
+	defer queue.ShutDown()
+
+	r.secretEventHandler().Create(context.Background(), event.CreateEvent{Object: managedSecret("db", "10")}, queue)
+
+	// A worker may reconcile the Secret as soon as it is in the queue
+	if !queue.observed["db"] {
+		t.Error("expected the Secret to be observed before it was enqueued")
+	}
+}
+
+func TestReplicatorCheckpointEventHandler(t *testing.T) {
+	fakeClient := newCheckpointClient("100")
+	checkpoint := &ResyncCheckpoint{Client: fakeClient, APIReader: fakeClient, Key: checkpointKey, Spread: time.Hour}
+	queue := &observingQueue{
+		PriorityQueue: priorityqueue.New[reconcile.Request]("test"),
+		checkpoint:    checkpoint,
+		observed:      make(map[string]bool),
+	}
+	defer queue.ShutDown()
+	eventHandler := checkpoint.eventHandler()
+	ctx := context.Background()
+
+	eventHandler.Create(ctx, event.CreateEvent{Object: managedSecret("unchanged", "50"), IsInInitialList: true}, queue)
+	eventHandler.Create(ctx, event.CreateEvent{Object: managedSecret("changed", "150"), IsInInitialList: true}, queue)
+
+	item, priority, _ := queue.GetWithPriority()
+	if item.Name != "changed" || priority != 0 {
+		t.Errorf("expected changed with priority 0, got %s with priority %d", item.Name, priority)
+	}
+	queue.Done(item)
+	if queue.Len() != 0 {
+		t.Errorf("expected the unchanged Secret to be deferred, got %d ready items", queue.Len())
+	}
+	if !queue.observed["unchanged"] || !queue.observed["changed"] {
+		t.Errorf("expected both Secrets to be observed before they were enqueued, got %v", queue.observed)
+	}
+
+	// Source Secrets of the initial list that did not change do not enqueue their targets
+	targets := func(context.Context, client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "other", Name: "target"}}}
+	}
+	fanOut := &fanOutHandler{
+		limiter: NewFanOutLimiter(0), targets: targets, now: time.Now, checkpoint: checkpoint,
+	}
+	fanOut.Create(ctx, event.CreateEvent{Object: managedSecret("unchanged", "50"), IsInInitialList: true}, queue)
+	if queue.Len() != 0 {
+		t.Errorf("expected no targets of an unchanged source, got %d ready items", queue.Len())
+	}
+	fanOut.Create(ctx, event.CreateEvent{Object: managedSecret("changed", "150"), IsInInitialList: true}, queue)
+	if queue.Len() != 1 {
+		t.Errorf("expected the target of a changed source, got %d ready items", queue.Len())
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestResyncEnqueuesMatchingSecrets(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	managed := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name: "managed", Namespace: "default", Annotations: map[string]string{AnnotationAutogenerate: "password"},
+	}}
+	unmanaged := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "unmanaged", Namespace: "default"}}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(managed, unmanaged).Build()
+
+	queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer queue.ShutDown()
+
+	filter := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		_, ok := obj.GetAnnotations()[AnnotationAutogenerate]
+		return ok
+	})
+	resync(context.Background(), fakeClient, filter, queue)
+
+	if queue.Len() != 1 {
+		t.Fatalf("expected 1 request to be enqueued, got %d", queue.Len())
+	}
+	req, _ := queue.Get()
+	if req.NamespacedName != client.ObjectKeyFromObject(managed) {
+		t.Errorf("expected request for %s, got %s", client.ObjectKeyFromObject(managed), req.NamespacedName)
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// RotationCatchUp spreads the rotations that are overdue when the operator starts over a window,
+// oldest first, so that a restart after a long downtime does not rotate all of them at once.
+// Rotations that become due after the start are not deferred. A nil RotationCatchUp defers nothing.
+type RotationCatchUp struct {
+	window time.Duration
+	// ready is closed once the slots were assigned
+	ready chan struct{}
+
+	mu sync.Mutex
+	// slots holds the time from which the rotation of each overdue Secret may start. It is nil
+	// once all slots passed, which disables the deferral.
+	slots map[types.NamespacedName]time.Time
+	// end is the end of the window
+	end time.Time
+}
+
+// NewRotationCatchUp creates a RotationCatchUp for the given window.
+// It returns nil if the window is not positive.
+func NewRotationCatchUp(window time.Duration) *RotationCatchUp {
+	if window <= 0 {
+		return nil
+	}
+	return &RotationCatchUp{window: window, ready: make(chan struct{})}
+}
+
+// start assigns evenly spaced slots in the window to the overdue Secrets, oldest first.
+// It must be called once.
+func (c *RotationCatchUp) start(now time.Time, overdue []types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(overdue) > 0 {
+		c.slots = make(map[types.NamespacedName]time.Time, len(overdue))
+		for i, key := range overdue {
+			c.slots[key] = now.Add(c.window / time.Duration(len(overdue)) * time.Duration(i))
+		}
+		c.end = now.Add(c.window)
+	}
+	close(c.ready)
+}
+
+// delay returns how long the overdue rotation of the Secret is deferred. It waits until the
+// slots were assigned.
+func (c *RotationCatchUp) delay(ctx context.Context, key types.NamespacedName, now time.Time) (time.Duration, error) {
+	if c == nil {
+		return 0, nil
+	}
+	select {
+	case <-c.ready:
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.slots == nil {
+		return 0, nil
+	}
+	if !now.Before(c.end) {
+		c.slots = nil
+		return 0, nil
+	}
+
+	slot, ok := c.slots[key]
+	if !ok {
+		return 0, nil
+	}
+	if !slot.After(now) {
+		delete(c.slots, key)
+		if len(c.slots) == 0 {
+			c.slots = nil
+		}
+		return 0, nil
+	}
+	return slot.Sub(now), nil
+}
+
+// setupCatchUp adds a Runnable that assigns the catch-up slots once the cache is synced.
+// It only runs on the leader.
+func (r *SecretReconciler) setupCatchUp(mgr ctrl.Manager) error {
+	if r.CatchUp == nil {
+		return nil
+	}
+	return mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		if !mgr.GetCache().WaitForCacheSync(ctx) {
+			return nil
+		}
+		r.startCatchUp(ctx)
+		return nil
+	}))
+}
+
+// startCatchUp lists the overdue rotations and assigns their slots. If the Secrets cannot be
+// listed, overdue rotations are not deferred.
+func (r *SecretReconciler) startCatchUp(ctx context.Context) {
+	now := r.now()
+	overdue, err := r.overdueRotations(ctx, now)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed to list overdue rotations, they are not deferred")
+	}
+	r.CatchUp.start(now, overdue)
+}
+
+// catchUpDelay returns how long the overdue rotation of the Secret is deferred by the catch-up window
+func (r *SecretReconciler) catchUpDelay(ctx context.Context, key types.NamespacedName) (time.Duration, error) {
+	return r.CatchUp.delay(ctx, key, r.now())
+}
+
+// overdueRotations lists the Secrets whose rotation interval has elapsed, oldest first.
+// Certificates and signed tokens are renewed based on their lifetime and are not included.
+func (r *SecretReconciler) overdueRotations(ctx context.Context, now time.Time) ([]types.NamespacedName, error) {
+	var secrets corev1.SecretList
+	if err := r.List(ctx, &secrets); err != nil {
+		return nil, err
+	}
+
+	type overdueSecret struct {
+		key types.NamespacedName
+		due time.Time
+	}
+	var overdue []overdueSecret
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		fields := parseSecretAnnotations(secret.Annotations)
+		if len(fields) == 0 || r.Config.IsSecretTypeIgnored(string(secret.Type)) {
+			continue
+		}
+		if due := nextRotationTime(r, secret.Annotations, fields); due != nil && !due.After(now) {
+			overdue = append(overdue, overdueSecret{key: types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}, due: *due})
+		}
+	}
+	slices.SortFunc(overdue, func(a, b overdueSecret) int {
+		if c := a.due.Compare(b.due); c != 0 {
+			return c
+		}
+		return strings.Compare(a.key.String(), b.key.String())
+	})
+
+	keys := make([]types.NamespacedName, 0, len(overdue))
+	for _, o := range overdue {
+		keys = append(keys, o.key)
+	}
+	return keys, nil
+}
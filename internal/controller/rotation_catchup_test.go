@@ -0,0 +1,162 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+)
+
+func TestRotationCatchUp(t *testing.T) {
+	if NewRotationCatchUp(0) != nil {
+		t.Error("expected nil RotationCatchUp for a zero window")
+	}
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	oldest := types.NamespacedName{Namespace: "default", Name: "oldest"}
+	middle := types.NamespacedName{Namespace: "default", Name: "middle"}
+	newest := types.NamespacedName{Namespace: "default", Name: "newest"}
+	catchUp := NewRotationCatchUp(3 * time.Hour)
+	catchUp.start(start, []types.NamespacedName{oldest, middle, newest})
+	tests := []struct {
+		key      types.NamespacedName
+		now      time.Time
+		expected time.Duration
+	}{
+		{key: newest, now: start, expected: 2 * time.Hour},
+		{key: oldest, now: start, expected: 0},
+		{key: middle, now: start.Add(30 * time.Minute), expected: 30 * time.Minute},
+		{key: middle, now: start.Add(time.Hour), expected: 0},
+		// Rotations that became due after the start are not deferred
+		{key: types.NamespacedName{Namespace: "default", Name: "later"}, now: start.Add(time.Hour), expected: 0},
+		// The deferral is disabled once the window passed
+		{key: newest, now: start.Add(3 * time.Hour), expected: 0},
+		{key: newest, now: start.Add(time.Hour), expected: 0},
+	}
+	for _, tt := range tests {
+		delay, err := catchUp.delay(context.Background(), tt.key, tt.now)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if delay != tt.expected {
+			t.Errorf("expected delay %v for %s at %v, got %v", tt.expected, tt.key.Name, tt.now, delay)
+		}
+	}
+	if catchUp.slots != nil {
+		t.Error("expected the slots to be released after the window")
+	}
+}
+
+func TestRotationCatchUpWaitsForSlots(t *testing.T) {
+	catchUp := NewRotationCatchUp(time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Reconciles before the slots were assigned wait for them
+	if _, err := catchUp.delay(ctx, types.NamespacedName{Namespace: "default", Name: "db"}, time.Now()); err == nil {
+		t.Error("expected an error while the slots are not assigned")
+	}
+}
+
+func TestReconcileSpreadsOverdueRotations(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	now := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	newSecret := func(name string, generatedAt time.Time) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Annotations: map[string]string{
+					AnnotationAutogenerate: "password",
+					AnnotationRotate:       "1d",
+					AnnotationGeneratedAt:  generatedAt.Format(time.RFC3339),
+				},
+			},
+			Data: map[string][]byte{"password": []byte("old")},
+		}
+	}
+	objs := []client.Object{
+		newSecret("oldest", now.Add(-7*24*time.Hour)),
+		newSecret("newest", now.Add(-2*24*time.Hour)),
+		// Not overdue, so it does not take a slot
+		newSecret("current", now.Add(-time.Hour)),
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+		Clock:         &MockClock{currentTime: now},
+		CatchUp:       NewRotationCatchUp(time.Hour),
+	}
+
+	ctx := context.Background()
+	reconciler.startCatchUp(ctx)
+	rotated := func(name string) bool {
+		t.Helper()
+		var secret corev1.Secret
+		if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: name}, &secret); err != nil {
+			t.Fatalf("failed to get Secret: %v", err)
+		}
+		return string(secret.Data["password"]) != "old"
+	}
+
+	// The newest overdue Secret gets the second half of the window
+	result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "newest"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rotated("newest") {
+		t.Error("expected the rotation of the newest overdue Secret to be deferred")
+	}
+	if result.RequeueAfter != 30*time.Minute {
+		t.Errorf("expected requeue after the slot in 30m, got %v", result.RequeueAfter)
+	}
+
+	if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "oldest"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !rotated("oldest") {
+		t.Error("expected the oldest overdue Secret to be rotated immediately")
+	}
+
+	reconciler.Clock = &MockClock{currentTime: now.Add(30 * time.Minute)}
+	if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "newest"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !rotated("newest") {
+		t.Error("expected the newest overdue Secret to be rotated in its slot")
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+	"time"
+)
+
+// rotationLimiterWindow is the sliding window used by the RotationLimiter
+const rotationLimiterWindow = time.Minute
+
+// RotationLimiter limits how many Secret rotations may execute per minute,
+// both cluster-wide and per namespace. This prevents a thundering herd of
+// dependent workload restarts when many Secrets become due at the same time.
+// A nil RotationLimiter allows all rotations.
+type RotationLimiter struct {
+	mu           sync.Mutex
+	maxPerWindow int
+	maxPerNS     int
+	cluster      []time.Time
+	namespaces   map[string][]time.Time
+}
+
+// NewRotationLimiter creates a RotationLimiter allowing at most maxPerMinute rotations
+// cluster-wide and maxPerMinutePerNamespace rotations per namespace (0 = unlimited).
+// It returns nil if both limits are disabled.
+func NewRotationLimiter(maxPerMinute, maxPerMinutePerNamespace int) *RotationLimiter {
+	if maxPerMinute <= 0 && maxPerMinutePerNamespace <= 0 {
+		return nil
+	}
+	return &RotationLimiter{
+		maxPerWindow: maxPerMinute,
+		maxPerNS:     maxPerMinutePerNamespace,
+		namespaces:   make(map[string][]time.Time),
+	}
+}
+
+// TryAcquire records a rotation in the given namespace if the limits allow it.
+// If the rotation is not allowed, it returns false and the time to wait before retrying.
+func (l *RotationLimiter) TryAcquire(namespace string, now time.Time) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.cluster = pruneWindow(l.cluster, now, rotationLimiterWindow)
+	nsEvents := pruneWindow(l.namespaces[namespace], now, rotationLimiterWindow)
+
+	var wait time.Duration
+	if l.maxPerWindow > 0 && len(l.cluster) >= l.maxPerWindow {
+		wait = l.cluster[0].Add(rotationLimiterWindow).Sub(now)
+	}
+	if l.maxPerNS > 0 && len(nsEvents) >= l.maxPerNS {
+		if nsWait := nsEvents[0].Add(rotationLimiterWindow).Sub(now); nsWait > wait {
+			wait = nsWait
+		}
+	}
+
+	if wait > 0 {
+		l.namespaces[namespace] = nsEvents
+		return false, wait
+	}
+
+	l.cluster = append(l.cluster, now)
+	l.namespaces[namespace] = append(nsEvents, now)
+	return true, 0
+}
+
+// pruneWindow removes all timestamps that are outside the sliding window
+func pruneWindow(events []time.Time, now time.Time, window time.Duration) []time.Time {
+	cutoff := now.Add(-window)
+	i := 0
+	for i < len(events) && !events[i].After(cutoff) {
+		i++
+	}
+	if i == len(events) {
+		return nil
+	}
+	return events[i:]
+}
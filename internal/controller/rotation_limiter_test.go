@@ -0,0 +1,157 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+)
+
+func TestNewRotationLimiterDisabled(t *testing.T) {
+	if l := NewRotationLimiter(0, 0); l != nil {
+		t.Error("expected nil limiter when both limits are disabled")
+	}
+
+	// A nil limiter allows everything
+	var l *RotationLimiter
+	for i := 0; i < 100; i++ {
+		if ok, _ := l.TryAcquire("default", time.Now()); !ok {
+			t.Fatal("expected nil limiter to allow all rotations")
+		}
+	}
+}
+
+func TestRotationLimiterClusterWide(t *testing.T) {
+	l := NewRotationLimiter(2, 0)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if ok, _ := l.TryAcquire("ns-a", start); !ok {
+		t.Fatal("expected first rotation to be allowed")
+	}
+	if ok, _ := l.TryAcquire("ns-b", start.Add(10*time.Second)); !ok {
+		t.Fatal("expected second rotation to be allowed")
+	}
+
+	ok, wait := l.TryAcquire("ns-c", start.Add(20*time.Second))
+	if ok {
+		t.Fatal("expected third rotation to be denied")
+	}
+	if wait != 40*time.Second {
+		t.Errorf("expected wait %v, got %v", 40*time.Second, wait)
+	}
+
+	// After the first rotation leaves the window, a new rotation is allowed
+	if ok, _ := l.TryAcquire("ns-c", start.Add(61*time.Second)); !ok {
+		t.Error("expected rotation to be allowed after window has passed")
+	}
+}
+
+func TestRotationLimiterPerNamespace(t *testing.T) {
+	l := NewRotationLimiter(0, 1)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if ok, _ := l.TryAcquire("ns-a", now); !ok {
+		t.Fatal("expected first rotation in ns-a to be allowed")
+	}
+	if ok, _ := l.TryAcquire("ns-a", now); ok {
+		t.Error("expected second rotation in ns-a to be denied")
+	}
+	if ok, _ := l.TryAcquire("ns-b", now); !ok {
+		t.Error("expected rotation in ns-b to be allowed")
+	}
+}
+
+func TestReconcileRotationDeferredByLimiter(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-secret",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate: "password",
+				AnnotationRotate:       "1h",
+				AnnotationGeneratedAt:  now.Add(-2 * time.Hour).Format(time.RFC3339),
+			},
+		},
+		Data: map[string][]byte{
+			"password": []byte("old-password"),
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+
+	limiter := NewRotationLimiter(1, 0)
+	// Exhaust the limit with a rotation of another Secret
+	if ok, _ := limiter.TryAcquire("other", now.Add(-30*time.Second)); !ok {
+		t.Fatal("expected limiter to allow first rotation")
+	}
+
+	reconciler := &SecretReconciler{
+		Client:          fakeClient,
+		Scheme:          scheme,
+		Generator:       generator.NewSecretGenerator(),
+		Config:          config.NewDefaultConfig(),
+		EventRecorder:   record.NewFakeRecorder(10),
+		Clock:           &MockClock{currentTime: now},
+		RotationLimiter: limiter,
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+	result, err := reconciler.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if string(updated.Data["password"]) != "old-password" {
+		t.Error("expected rotation to be deferred")
+	}
+	if result.RequeueAfter != 30*time.Second {
+		t.Errorf("expected RequeueAfter %v, got %v", 30*time.Second, result.RequeueAfter)
+	}
+
+	// Once the window has passed, the rotation executes
+	reconciler.Clock = &MockClock{currentTime: now.Add(31 * time.Second)}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if string(updated.Data["password"]) == "old-password" {
+		t.Error("expected rotation to execute after the limit window")
+	}
+}
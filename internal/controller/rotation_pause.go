@@ -0,0 +1,83 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// AnnotationRotationPaused is set on a Namespace to pause rotation of all Secrets in it.
+	// Initial generation of missing fields is not affected.
+	AnnotationRotationPaused = AnnotationPrefix + "rotation-paused"
+)
+
+// isRotationPaused returns true if the namespace has rotation paused
+func isRotationPaused(namespace *corev1.Namespace) bool {
+	paused, ok := parseBoolAnnotation(namespace.Annotations, AnnotationRotationPaused)
+	return ok && paused
+}
+
+// isNamespaceRotationPaused returns true if rotation is paused for the given namespace.
+// A namespace that cannot be found is treated as not paused.
+func (r *SecretReconciler) isNamespaceRotationPaused(ctx context.Context, name string) (bool, error) {
+	var namespace corev1.Namespace
+	if err := r.Get(ctx, types.NamespacedName{Name: name}, &namespace); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return isRotationPaused(&namespace), nil
+}
+
+// secretsInNamespace returns reconcile requests for all generated Secrets in a namespace.
+// It is used to resume deferred rotations once a namespace is unpaused.
+func (r *SecretReconciler) secretsInNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	var secrets corev1.SecretList
+	if err := r.List(ctx, &secrets, client.InNamespace(obj.GetName())); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list Secrets for namespace", "namespace", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, secret := range secrets.Items {
+		if _, ok := secret.Annotations[AnnotationAutogenerate]; !ok {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace},
+		})
+	}
+	return requests
+}
+
+// rotationPausedChanged only passes Namespace updates that change the rotation-paused annotation
+var rotationPausedChanged = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return e.ObjectOld.GetAnnotations()[AnnotationRotationPaused] !=
+			e.ObjectNew.GetAnnotations()[AnnotationRotationPaused]
+	},
+}
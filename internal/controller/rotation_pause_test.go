@@ -0,0 +1,156 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+)
+
+func pausedNamespace(name string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{AnnotationRotationPaused: "true"},
+		},
+	}
+}
+
+func TestReconcileRotationPausedNamespace(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	generatedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := generatedAt.Add(2 * time.Hour)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-secret",
+			Namespace: "frozen",
+			Annotations: map[string]string{
+				AnnotationAutogenerate: "password,api-key",
+				AnnotationRotate:       "1h",
+				AnnotationGeneratedAt:  generatedAt.Format(time.RFC3339),
+			},
+		},
+		Data: map[string][]byte{"password": []byte("old-password")},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pausedNamespace("frozen"), secret).Build()
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+		Clock:         &MockClock{currentTime: now},
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-secret", Namespace: "frozen"}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+
+	if string(updated.Data["password"]) != "old-password" {
+		t.Error("expected password not to be rotated while the namespace is paused")
+	}
+	if len(updated.Data["api-key"]) == 0 {
+		t.Error("expected missing field to be generated while the namespace is paused")
+	}
+}
+
+func TestIsRotationPaused(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    bool
+	}{
+		{name: "no annotation", annotations: nil, expected: false},
+		{name: "paused", annotations: map[string]string{AnnotationRotationPaused: "true"}, expected: true},
+		{name: "not paused", annotations: map[string]string{AnnotationRotationPaused: "false"}, expected: false},
+		{name: "invalid value", annotations: map[string]string{AnnotationRotationPaused: "yes"}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns", Annotations: tt.annotations}}
+			if got := isRotationPaused(ns); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRotationPausedChanged(t *testing.T) {
+	unpaused := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns"}}
+	paused := pausedNamespace("ns")
+
+	if !rotationPausedChanged.Update(event.UpdateEvent{ObjectOld: paused, ObjectNew: unpaused}) {
+		t.Error("expected unpausing a namespace to pass the predicate")
+	}
+	if rotationPausedChanged.Update(event.UpdateEvent{ObjectOld: paused, ObjectNew: paused.DeepCopy()}) {
+		t.Error("expected unrelated namespace updates to be filtered")
+	}
+	if rotationPausedChanged.Create(event.CreateEvent{Object: paused}) {
+		t.Error("expected namespace creation to be filtered")
+	}
+}
+
+func TestSecretsInNamespace(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	objs := []client.Object{
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: "generated", Namespace: "ns", Annotations: map[string]string{AnnotationAutogenerate: "password"},
+		}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "manual", Namespace: "ns"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: "other", Namespace: "other-ns", Annotations: map[string]string{AnnotationAutogenerate: "password"},
+		}},
+	}
+
+	reconciler := &SecretReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		Config: config.NewDefaultConfig(),
+	}
+
+	requests := reconciler.secretsInNamespace(context.Background(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns"}})
+	if len(requests) != 1 || requests[0].Name != "generated" {
+		t.Errorf("expected only the generated Secret to be enqueued, got %v", requests)
+	}
+}
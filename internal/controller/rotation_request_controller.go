@@ -0,0 +1,195 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// AnnotationRequestRotation is set on a Deployment or StatefulSet and lists the Secrets
+	// (comma-separated, in the workload's namespace) whose rotation the workload requests.
+	// Changing the value triggers a rotation of all listed Secrets.
+	AnnotationRequestRotation = AnnotationPrefix + "request-rotation"
+
+	// AnnotationRotationRequestHandled records the last request-rotation value the operator acted on
+	AnnotationRotationRequestHandled = AnnotationPrefix + "rotation-request-handled"
+
+	// EventReasonRotationRequested is the event reason for a rotation requested by a workload
+	EventReasonRotationRequested = "RotationRequested"
+
+	// EventReasonRotationRequestFailed is the event reason for a rotation request that could not be applied
+	EventReasonRotationRequestFailed = "RotationRequestFailed"
+)
+
+// RotationRequestReconciler watches Deployments and StatefulSets for the request-rotation
+// annotation and forwards changed requests to the referenced Secrets
+type RotationRequestReconciler struct {
+	client.Client
+	Scheme        *runtime.Scheme
+	EventRecorder record.EventRecorder
+	// Clock is used to get the current time. If nil, time.Now() is used.
+	Clock Clock
+	// Heartbeat records completed reconciles for the liveness check. If nil, nothing is recorded.
+	Heartbeat *Heartbeat
+	// Shard restricts the controller to the namespaces of one shard. If nil, all namespaces are processed.
+	Shard *Shard
+}
+
+// now returns the current time using the Clock if set, otherwise time.Now()
+func (r *RotationRequestReconciler) now() time.Time {
+	if r.Clock != nil {
+		return r.Clock.Now()
+	}
+	return time.Now()
+}
+
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;list;watch;patch
+
+// reconcileWorkload handles the request-rotation annotation of a single workload.
+// The first observed value is only recorded as baseline; every later change of the value
+// requests a rotation of all listed Secrets.
+func (r *RotationRequestReconciler) reconcileWorkload(
+	ctx context.Context,
+	req ctrl.Request,
+	workload client.Object,
+) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if err := r.Get(ctx, req.NamespacedName, workload); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	annotations := workload.GetAnnotations()
+	requested := annotations[AnnotationRequestRotation]
+	if requested == "" {
+		return ctrl.Result{}, nil
+	}
+
+	handled, seen := annotations[AnnotationRotationRequestHandled]
+	if seen && handled == requested {
+		return ctrl.Result{}, nil
+	}
+
+	if seen {
+		requestedAt := r.now().UTC().Format(time.RFC3339)
+		for _, secretName := range parseRotationRequest(requested) {
+			if err := r.requestSecretRotation(ctx, workload.GetNamespace(), secretName, requestedAt); err != nil {
+				logger.Error(err, "Failed to request rotation", "secret", secretName)
+				r.EventRecorder.Event(workload, corev1.EventTypeWarning, EventReasonRotationRequestFailed,
+					fmt.Sprintf("Cannot request rotation of Secret %q: %v", secretName, err))
+				continue
+			}
+			logger.Info("Requested rotation", "secret", secretName)
+			r.EventRecorder.Event(workload, corev1.EventTypeNormal, EventReasonRotationRequested,
+				fmt.Sprintf("Requested rotation of Secret %q", secretName))
+		}
+	} else {
+		logger.V(1).Info("Recording initial rotation request as baseline", "value", requested)
+	}
+
+	// Record the handled value on the workload so the same request is not applied twice
+	original := workload.DeepCopyObject().(client.Object)
+	annotations[AnnotationRotationRequestHandled] = requested
+	workload.SetAnnotations(annotations)
+	if err := r.Patch(ctx, workload, client.MergeFrom(original)); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// parseRotationRequest returns the Secret names of a request-rotation value.
+// Each entry may carry a suffix after ':' (e.g. a date or counter) which is ignored.
+func parseRotationRequest(value string) []string {
+	var names []string
+	for _, entry := range parseFields(value) {
+		name, _, _ := strings.Cut(entry, ":")
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// requestSecretRotation marks a Secret for rotation by setting the rotation-requested-at annotation.
+// Only Secrets managed by the secret generator can be rotated.
+func (r *RotationRequestReconciler) requestSecretRotation(
+	ctx context.Context,
+	namespace, name, requestedAt string,
+) error {
+	var secret corev1.Secret
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &secret); err != nil {
+		return err
+	}
+
+	if _, ok := secret.Annotations[AnnotationAutogenerate]; !ok {
+		return fmt.Errorf("secret has no %s annotation", AnnotationAutogenerate)
+	}
+
+	original := secret.DeepCopy()
+	secret.Annotations[AnnotationRotationRequestedAt] = requestedAt
+	return r.Patch(ctx, &secret, client.MergeFrom(original))
+}
+
+// SetupWithManager sets up one controller per supported workload kind with the Manager
+func (r *RotationRequestReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	hasRequestRotationAnnotation := predicate.NewPredicateFuncs(func(object client.Object) bool {
+		return object.GetAnnotations()[AnnotationRequestRotation] != ""
+	})
+
+	recorder, err := instrumentEventRecorder(r.EventRecorder)
+	if err != nil {
+		return err
+	}
+	r.EventRecorder = recorder
+
+	workloads := map[string]func() client.Object{
+		"rotation-request-deployment":  func() client.Object { return &appsv1.Deployment{} },
+		"rotation-request-statefulset": func() client.Object { return &appsv1.StatefulSet{} },
+	}
+
+	for name, newWorkload := range workloads {
+		err := ctrl.NewControllerManagedBy(mgr).
+			Named(name).
+			For(newWorkload(), builder.WithPredicates(hasRequestRotationAnnotation)).
+			Complete(r.Heartbeat.wrap(name, r.Shard.wrap(reportPanics(r.EventRecorder, newWorkload(),
+				reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+					return r.reconcileWorkload(ctx, req, newWorkload())
+				})))))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
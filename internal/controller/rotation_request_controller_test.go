@@ -0,0 +1,182 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newRotationRequestReconciler(t *testing.T, now time.Time, objs ...client.Object) (*RotationRequestReconciler, *record.FakeRecorder) {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	fakeRecorder := record.NewFakeRecorder(10)
+	return &RotationRequestReconciler{
+		Client:        fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		Scheme:        scheme,
+		EventRecorder: fakeRecorder,
+		Clock:         &MockClock{currentTime: now},
+	}, fakeRecorder
+}
+
+func TestParseRotationRequest(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{input: "db", expected: []string{"db"}},
+		{input: "db, api-keys", expected: []string{"db", "api-keys"}},
+		{input: "db:2025-06-01,api-keys:3", expected: []string{"db", "api-keys"}},
+		{input: ":token,", expected: nil},
+	}
+
+	for _, tt := range tests {
+		result := parseRotationRequest(tt.input)
+		if strings.Join(result, ",") != strings.Join(tt.expected, ",") {
+			t.Errorf("parseRotationRequest(%q) = %v, expected %v", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestRotationRequestReconcilerRecordsBaseline(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "db",
+			Namespace:   "default",
+			Annotations: map[string]string{AnnotationAutogenerate: "password"},
+		},
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "default",
+			Annotations: map[string]string{AnnotationRequestRotation: "db"},
+		},
+	}
+
+	r, fakeRecorder := newRotationRequestReconciler(t, now, secret, deployment)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+	if _, err := r.reconcileWorkload(ctx, req, &appsv1.Deployment{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated appsv1.Deployment
+	if err := r.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get deployment: %v", err)
+	}
+	if updated.Annotations[AnnotationRotationRequestHandled] != "db" {
+		t.Errorf("expected handled annotation %q, got %q", "db", updated.Annotations[AnnotationRotationRequestHandled])
+	}
+
+	var updatedSecret corev1.Secret
+	if err := r.Get(ctx, types.NamespacedName{Name: "db", Namespace: "default"}, &updatedSecret); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if _, ok := updatedSecret.Annotations[AnnotationRotationRequestedAt]; ok {
+		t.Error("expected no rotation request for the initial value")
+	}
+
+	select {
+	case event := <-fakeRecorder.Events:
+		t.Errorf("expected no event, got %q", event)
+	default:
+	}
+}
+
+func TestRotationRequestReconcilerRequestsRotationOnChange(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "db",
+			Namespace:   "default",
+			Annotations: map[string]string{AnnotationAutogenerate: "password"},
+		},
+	}
+	unmanaged := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "manual",
+			Namespace: "default",
+		},
+	}
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "db-server",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationRequestRotation:        "db:2,manual:2",
+				AnnotationRotationRequestHandled: "db:1,manual:1",
+			},
+		},
+	}
+
+	r, fakeRecorder := newRotationRequestReconciler(t, now, secret, unmanaged, statefulSet)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "db-server", Namespace: "default"}}
+
+	if _, err := r.reconcileWorkload(ctx, req, &appsv1.StatefulSet{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updatedSecret corev1.Secret
+	if err := r.Get(ctx, types.NamespacedName{Name: "db", Namespace: "default"}, &updatedSecret); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if got := updatedSecret.Annotations[AnnotationRotationRequestedAt]; got != now.Format(time.RFC3339) {
+		t.Errorf("expected rotation-requested-at %q, got %q", now.Format(time.RFC3339), got)
+	}
+
+	var updated appsv1.StatefulSet
+	if err := r.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get statefulset: %v", err)
+	}
+	if updated.Annotations[AnnotationRotationRequestHandled] != "db:2,manual:2" {
+		t.Errorf("expected handled annotation to be updated, got %q", updated.Annotations[AnnotationRotationRequestHandled])
+	}
+
+	var events []string
+	for len(fakeRecorder.Events) > 0 {
+		events = append(events, <-fakeRecorder.Events)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d: %v", len(events), events)
+	}
+	if !strings.Contains(events[0], EventReasonRotationRequested) || !strings.Contains(events[0], `"db"`) {
+		t.Errorf("expected RotationRequested event for db, got %q", events[0])
+	}
+	if !strings.Contains(events[1], EventReasonRotationRequestFailed) || !strings.Contains(events[1], `"manual"`) {
+		t.Errorf("expected RotationRequestFailed event for manual, got %q", events[1])
+	}
+}
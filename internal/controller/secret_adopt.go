@@ -0,0 +1,84 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	isoannotations "github.com/guided-traffic/internal-secrets-operator/pkg/annotations"
+)
+
+// adoptFields takes over the fields listed in the adopt annotation. Their current values are
+// kept, but they are moved to the autogenerate annotation and recorded as generated keys, and
+// generated-at is set to now if it is absent, so that rotation applies from then on. An existing
+// generated-at is kept, so the fields the operator already generated keep their rotation schedule.
+// Adopted fields without a value are generated like any other autogenerate field.
+func (r *SecretReconciler) adoptFields(ctx context.Context, secret *corev1.Secret, logger logr.Logger) error {
+	value, ok := secret.Annotations[AnnotationAdopt]
+	if !ok {
+		return nil
+	}
+	adopted := parseFields(value)
+	if err := isoannotations.ValidateFields(adopted); err != nil {
+		r.EventRecorder.Event(secret, corev1.EventTypeWarning, EventReasonGenerationFailed,
+			fmt.Sprintf("Invalid %s annotation: %v", AnnotationAdopt, err))
+		logger.Info("Invalid adopt annotation", "error", err)
+		return nil // Don't requeue - the annotation needs to be fixed
+	}
+
+	fields := parseSecretAnnotations(secret.Annotations)
+	var existing []string
+	for _, field := range adopted {
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+		if _, ok := secret.Data[field]; ok {
+			existing = append(existing, field)
+		}
+	}
+
+	original := secret.DeepCopy()
+	secret.Annotations[AnnotationAutogenerate] = strings.Join(fields, ",")
+	delete(secret.Annotations, AnnotationAdopt)
+	if len(existing) > 0 {
+		if _, ok := secret.Annotations[AnnotationGeneratedAt]; !ok {
+			secret.Annotations[AnnotationGeneratedAt] = isoannotations.FormatTimestamp(r.now(), r.Config.Rotation.TimestampFormat)
+		}
+		recordGeneratedKeys(secret, existing)
+		for _, field := range existing {
+			isoannotations.SetOrigin(secret.Annotations, field, isoannotations.OriginAdopted)
+		}
+		recordDataChecksum(secret)
+	}
+	if err := r.Update(ctx, secret); err != nil {
+		original.DeepCopyInto(secret)
+		return fmt.Errorf("failed to adopt fields: %w", err)
+	}
+
+	if len(existing) > 0 {
+		r.EventRecorder.Event(secret, corev1.EventTypeNormal, EventReasonFieldsAdopted,
+			fmt.Sprintf("Adopted existing values for fields: %s", strings.Join(existing, ", ")))
+		logger.Info("Adopted existing fields", "fields", existing)
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	isoannotations "github.com/guided-traffic/internal-secrets-operator/pkg/annotations"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+)
+
+func TestReconcileAdoptsExistingFields(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "legacy",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAdopt:  "password,api-key",
+				AnnotationRotate: "30d",
+			},
+		},
+		Data: map[string][]byte{
+			"password": []byte("hand-made"),
+			"username": []byte("admin"),
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	fakeRecorder := record.NewFakeRecorder(10)
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: fakeRecorder,
+		Clock:         &MockClock{currentTime: now},
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+	result, err := reconciler.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+
+	// The adopted value is kept, the missing adopted field is generated
+	if string(updated.Data["password"]) != "hand-made" {
+		t.Errorf("expected the adopted value to be kept, got %q", updated.Data["password"])
+	}
+	if len(updated.Data["api-key"]) == 0 {
+		t.Error("expected the missing adopted field to be generated")
+	}
+
+	if _, ok := updated.Annotations[AnnotationAdopt]; ok {
+		t.Error("expected the adopt annotation to be removed")
+	}
+	if updated.Annotations[AnnotationAutogenerate] != "password,api-key" {
+		t.Errorf("expected adopted fields in autogenerate, got %q", updated.Annotations[AnnotationAutogenerate])
+	}
+	if updated.Annotations[AnnotationGeneratedKeys] != "api-key,password" {
+		t.Errorf("expected adopted fields in generated-keys, got %q", updated.Annotations[AnnotationGeneratedKeys])
+	}
+	if updated.Annotations[AnnotationGeneratedAt] != now.Format(time.RFC3339) {
+		t.Errorf("expected generated-at to be set to now, got %q", updated.Annotations[AnnotationGeneratedAt])
+	}
+	if got := updated.Annotations[AnnotationOriginPrefix+"password"]; got != isoannotations.OriginAdopted {
+		t.Errorf("expected origin %q for the adopted field, got %q", isoannotations.OriginAdopted, got)
+	}
+	if got := updated.Annotations[AnnotationOriginPrefix+"api-key"]; got != isoannotations.OriginGenerated {
+		t.Errorf("expected origin %q for the generated field, got %q", isoannotations.OriginGenerated, got)
+	}
+
+	// Rotation applies from the adoption on
+	if result.RequeueAfter != 30*24*time.Hour {
+		t.Errorf("expected RequeueAfter %v, got %v", 30*24*time.Hour, result.RequeueAfter)
+	}
+
+	event := <-fakeRecorder.Events
+	if !strings.Contains(event, EventReasonFieldsAdopted) || !strings.Contains(event, "password") {
+		t.Errorf("expected %s event for password, got %q", EventReasonFieldsAdopted, event)
+	}
+}
+
+func TestReconcileAdoptInvalidField(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "legacy",
+			Namespace:   "default",
+			Annotations: map[string]string{AnnotationAdopt: "pass word"},
+		},
+		Data: map[string][]byte{"pass word": []byte("hand-made")},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	fakeRecorder := record.NewFakeRecorder(10)
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: fakeRecorder,
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if _, ok := updated.Annotations[AnnotationAutogenerate]; ok {
+		t.Error("expected invalid fields not to be adopted")
+	}
+
+	event := <-fakeRecorder.Events
+	if !strings.Contains(event, EventReasonGenerationFailed) {
+		t.Errorf("expected %s event, got %q", EventReasonGenerationFailed, event)
+	}
+}
+
+func TestReconcileAdoptKeepsGeneratedAt(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	generatedAt := now.Add(-10 * 24 * time.Hour)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mixed",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate:  "api-key",
+				AnnotationAdopt:         "password",
+				AnnotationRotate:        "30d",
+				AnnotationGeneratedAt:   generatedAt.Format(time.RFC3339),
+				AnnotationGeneratedKeys: "api-key",
+			},
+		},
+		Data: map[string][]byte{
+			"api-key":  []byte("generated"),
+			"password": []byte("hand-made"),
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+		Clock:         &MockClock{currentTime: now},
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+	result, err := reconciler.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if updated.Annotations[AnnotationGeneratedAt] != generatedAt.Format(time.RFC3339) {
+		t.Errorf("expected generated-at to be kept, got %q", updated.Annotations[AnnotationGeneratedAt])
+	}
+	if string(updated.Data["api-key"]) != "generated" || string(updated.Data["password"]) != "hand-made" {
+		t.Errorf("expected values to be kept, got %v", updated.Data)
+	}
+	// The rotation schedule of the generated field is not restarted
+	if result.RequeueAfter != 20*24*time.Hour {
+		t.Errorf("expected RequeueAfter %v, got %v", 20*24*time.Hour, result.RequeueAfter)
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	isoannotations "github.com/guided-traffic/internal-secrets-operator/pkg/annotations"
+)
+
+// EventReasonRotationBackupFailed is the event reason for rotations deferred because the
+// previous values could not be backed up
+const EventReasonRotationBackupFailed = "RotationBackupFailed"
+
+// rotationBackupSuffix is appended to the name of a Secret to name its rotation backup
+const rotationBackupSuffix = "-rotation-backup"
+
+// rotationBackupName returns the name of the rotation backup Secret of the given Secret
+func rotationBackupName(name string) string {
+	return name + rotationBackupSuffix
+}
+
+// backupRotatedValues copies the previous values of the given keys into the rotation backup
+// Secret before they are overwritten. Keys without a previous value (initial generation) are
+// skipped. Values of keys rotated earlier are kept, and the retention starts anew.
+func (r *SecretReconciler) backupRotatedValues(
+	ctx context.Context,
+	secret *corev1.Secret,
+	previous map[string][]byte,
+	keys []string,
+) error {
+	data := make(map[string][]byte)
+	for _, key := range keys {
+		if value, ok := previous[key]; ok {
+			data[key] = value
+		}
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	expiresAt := isoannotations.FormatTimestamp(r.now().Add(r.Config.Rotation.BackupRetention.Duration()),
+		r.Config.Rotation.TimestampFormat)
+
+	var backup corev1.Secret
+	key := client.ObjectKey{Namespace: secret.Namespace, Name: rotationBackupName(secret.Name)}
+	err := r.Get(ctx, key, &backup)
+	if apierrors.IsNotFound(err) {
+		backup = corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				Annotations: map[string]string{
+					AnnotationBackupOf:        secret.Name,
+					AnnotationBackupExpiresAt: expiresAt,
+				},
+				// The backup is deleted together with the Secret
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(secret, corev1.SchemeGroupVersion.WithKind("Secret")),
+				},
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: data,
+		}
+		// The operator's Secret informer only sees Secrets with the managed label
+		if r.Config.ManagedLabel.Enabled {
+			backup.Labels = map[string]string{r.Config.ManagedLabel.Key: r.Config.ManagedLabel.Value}
+		}
+		if err := r.Create(ctx, &backup); err != nil {
+			return fmt.Errorf("failed to create rotation backup Secret %s: %w", key.Name, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get rotation backup Secret %s: %w", key.Name, err)
+	}
+
+	// Never overwrite a Secret that happens to have the backup name
+	if backup.Annotations[AnnotationBackupOf] != secret.Name {
+		return fmt.Errorf("secret %s exists and is not a rotation backup of %s", key.Name, secret.Name)
+	}
+	if backup.Data == nil {
+		backup.Data = make(map[string][]byte)
+	}
+	for k, value := range data {
+		backup.Data[k] = value
+	}
+	backup.Annotations[AnnotationBackupExpiresAt] = expiresAt
+	if err := r.Update(ctx, &backup); err != nil {
+		return fmt.Errorf("failed to update rotation backup Secret %s: %w", key.Name, err)
+	}
+	return nil
+}
+
+// expireRotationBackup deletes the rotation backup Secret of the given Secret once its retention
+// has elapsed. It returns the time until the backup expires, or nil if there is no backup.
+func (r *SecretReconciler) expireRotationBackup(ctx context.Context, secret *corev1.Secret) (*time.Duration, error) {
+	var backup corev1.Secret
+	key := client.ObjectKey{Namespace: secret.Namespace, Name: rotationBackupName(secret.Name)}
+	if err := r.Get(ctx, key, &backup); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	if backup.Annotations[AnnotationBackupOf] != secret.Name {
+		return nil, nil
+	}
+	expiresAt := isoannotations.ParseTimestamp(backup.Annotations, AnnotationBackupExpiresAt)
+	if expiresAt == nil {
+		return nil, nil
+	}
+	if remaining := expiresAt.Sub(r.now()); remaining > 0 {
+		return &remaining, nil
+	}
+
+	uid := backup.UID
+	if err := r.Delete(ctx, &backup, client.Preconditions{UID: &uid}); client.IgnoreNotFound(err) != nil {
+		return nil, fmt.Errorf("failed to delete expired rotation backup Secret %s: %w", key.Name, err)
+	}
+	log.FromContext(ctx).Info("Deleted expired rotation backup", "backup", key.Name)
+	return nil, nil
+}
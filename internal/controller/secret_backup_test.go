@@ -0,0 +1,244 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+)
+
+// newBackupTestReconciler returns a reconciler with rotation backups retained for a day
+func newBackupTestReconciler(objects ...client.Object) (*SecretReconciler, client.Client, *MockClock) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	cfg := config.NewDefaultConfig()
+	cfg.Rotation.MinInterval = config.Duration(time.Minute)
+	cfg.Rotation.BackupRetention = config.Duration(24 * time.Hour)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	mockClock := &MockClock{currentTime: time.Date(2025, 12, 6, 12, 0, 0, 0, time.UTC)}
+	return &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        cfg,
+		EventRecorder: record.NewFakeRecorder(10),
+		Clock:         mockClock,
+	}, fakeClient, mockClock
+}
+
+func TestReconcileBacksUpRotatedValues(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "db",
+			Namespace: "default",
+			UID:       "db-uid",
+			Annotations: map[string]string{
+				AnnotationAutogenerate:              "password,username",
+				AnnotationRotatePrefix + "password": "1h",
+				AnnotationGeneratedAt:               "2025-12-06T10:00:00Z",
+			},
+		},
+		Data: map[string][]byte{
+			"password": []byte("old-password"),
+			"username": []byte("admin"),
+		},
+	}
+	reconciler, fakeClient, mockClock := newBackupTestReconciler(secret)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "db", Namespace: "default"}}
+
+	result, err := reconciler.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.RequeueAfter > 24*time.Hour {
+		t.Errorf("expected requeue before the backup expires, got %s", result.RequeueAfter)
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get Secret: %v", err)
+	}
+	if string(updated.Data["password"]) == "old-password" {
+		t.Fatal("expected password to be rotated")
+	}
+
+	var backup corev1.Secret
+	backupKey := types.NamespacedName{Name: "db-rotation-backup", Namespace: "default"}
+	if err := fakeClient.Get(ctx, backupKey, &backup); err != nil {
+		t.Fatalf("failed to get backup Secret: %v", err)
+	}
+	if string(backup.Data["password"]) != "old-password" {
+		t.Errorf("expected backup of the previous password, got %q", backup.Data["password"])
+	}
+	if _, ok := backup.Data["username"]; ok {
+		t.Error("expected fields that were not rotated not to be backed up")
+	}
+	if backup.Annotations[AnnotationBackupOf] != "db" {
+		t.Errorf("expected backup-of annotation %q, got %q", "db", backup.Annotations[AnnotationBackupOf])
+	}
+	if got := backup.Annotations[AnnotationBackupExpiresAt]; got != "2025-12-07T12:00:00Z" {
+		t.Errorf("expected backup to expire at 2025-12-07T12:00:00Z, got %q", got)
+	}
+	if len(backup.OwnerReferences) != 1 || backup.OwnerReferences[0].UID != "db-uid" {
+		t.Errorf("expected backup to be owned by the Secret, got %v", backup.OwnerReferences)
+	}
+
+	// A later rotation replaces the backup and extends its retention
+	rotated := string(updated.Data["password"])
+	mockClock.currentTime = mockClock.currentTime.Add(2 * time.Hour)
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := fakeClient.Get(ctx, backupKey, &backup); err != nil {
+		t.Fatalf("failed to get backup Secret: %v", err)
+	}
+	if string(backup.Data["password"]) != rotated {
+		t.Errorf("expected backup of the previously rotated password, got %q", backup.Data["password"])
+	}
+	if got := backup.Annotations[AnnotationBackupExpiresAt]; got != "2025-12-07T14:00:00Z" {
+		t.Errorf("expected backup to expire at 2025-12-07T14:00:00Z, got %q", got)
+	}
+
+	// The backup is deleted once the retention has elapsed
+	reconciler.Config.Rotation.BackupRetention = 0
+	mockClock.currentTime = mockClock.currentTime.Add(25 * time.Hour)
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := fakeClient.Get(ctx, backupKey, &backup); !apierrors.IsNotFound(err) {
+		t.Errorf("expected expired backup to be deleted, got %v", err)
+	}
+}
+
+func TestReconcileDefersRotationWithoutBackup(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "db",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate: "password",
+				AnnotationRotate:       "1h",
+				AnnotationGeneratedAt:  "2025-12-06T10:00:00Z",
+			},
+		},
+		Data: map[string][]byte{"password": []byte("old-password")},
+	}
+	// A Secret that happens to have the backup name must not be overwritten
+	foreign := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-rotation-backup", Namespace: "default"},
+		Data:       map[string][]byte{"other": []byte("value")},
+	}
+	reconciler, fakeClient, _ := newBackupTestReconciler(secret, foreign)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "db", Namespace: "default"}}
+
+	if _, err := reconciler.Reconcile(ctx, req); err == nil {
+		t.Fatal("expected error when the backup cannot be written")
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get Secret: %v", err)
+	}
+	if string(updated.Data["password"]) != "old-password" {
+		t.Error("expected rotation to be deferred")
+	}
+	var backup corev1.Secret
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(foreign), &backup); err != nil {
+		t.Fatalf("failed to get Secret: %v", err)
+	}
+	if _, ok := backup.Data["password"]; ok {
+		t.Error("expected foreign Secret not to be modified")
+	}
+}
+
+func TestReconcileSkipsBackupOnInitialGeneration(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "db",
+			Namespace:   "default",
+			Annotations: map[string]string{AnnotationAutogenerate: "password", AnnotationRotate: "1h"},
+		},
+	}
+	reconciler, fakeClient, _ := newBackupTestReconciler(secret)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "db", Namespace: "default"}}
+
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var backup corev1.Secret
+	err := fakeClient.Get(ctx, types.NamespacedName{Name: "db-rotation-backup", Namespace: "default"}, &backup)
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected no backup for initial generation, got %v", err)
+	}
+}
+
+func TestReconcileExpiresBackupWithoutFields(t *testing.T) {
+	// The Secret no longer generates any field, but its backup still expires
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+		Data:       map[string][]byte{"password": []byte("kept")},
+	}
+	backup := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "db-rotation-backup",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationBackupOf:        "db",
+				AnnotationBackupExpiresAt: "2025-12-06T18:00:00Z",
+			},
+		},
+		Data: map[string][]byte{"password": []byte("old-password")},
+	}
+	reconciler, fakeClient, mockClock := newBackupTestReconciler(secret, backup)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "db", Namespace: "default"}}
+
+	result, err := reconciler.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.RequeueAfter != 6*time.Hour {
+		t.Errorf("expected requeue when the backup expires in 6h, got %s", result.RequeueAfter)
+	}
+
+	mockClock.currentTime = mockClock.currentTime.Add(6 * time.Hour)
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(backup), &corev1.Secret{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected expired backup to be deleted, got %v", err)
+	}
+}
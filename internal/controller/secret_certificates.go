@@ -0,0 +1,289 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	isoannotations "github.com/guided-traffic/internal-secrets-operator/pkg/annotations"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+// certificateDuration returns the configured validity period for generated certificates
+func (r *SecretReconciler) certificateDuration() time.Duration {
+	if d := r.Config.Certificates.Duration.Duration(); d > 0 {
+		return d
+	}
+	return config.DefaultCertificateDuration
+}
+
+// certificateRenewalFraction returns the configured fraction of a certificate's lifetime
+// after which it is renewed
+func (r *SecretReconciler) certificateRenewalFraction() float64 {
+	if f := r.Config.Certificates.RenewalFraction; f > 0 {
+		return f
+	}
+	return config.DefaultCertificateRenewalFraction
+}
+
+// checkCertificateRenewal checks if the certificate stored in a field needs renewal.
+// A certificate is renewed once the configured fraction of its lifetime has elapsed (or
+// tls.renew-before before it expires), and when its subject alternative names no longer match
+// the annotations. If the field has no value, no renewal is needed (the certificate will be generated).
+func (r *SecretReconciler) checkCertificateRenewal(secret *corev1.Secret, field string) rotationCheckResult {
+	result := rotationCheckResult{}
+
+	certData, ok := secret.Data[field]
+	if !ok {
+		return result
+	}
+
+	cert, err := generator.ParseCertificatePEM(certData)
+	if err != nil {
+		result.err = fmt.Errorf("cannot parse certificate in field %q: %w", field, err)
+		result.errMsg = fmt.Sprintf("Cannot parse certificate in field %q, renewal disabled: %v", field, err)
+		return result
+	}
+	spec, err := isoannotations.ParseCertificate(secret.Annotations)
+	var renewAt time.Time
+	if err == nil {
+		renewAt, err = r.certificateRenewalTime(spec, cert)
+	}
+	if err != nil {
+		result.err = fmt.Errorf("cannot schedule renewal of certificate in field %q: %w", field, err)
+		result.errMsg = fmt.Sprintf("Cannot schedule renewal of certificate in field %q, renewal disabled: %v", field, err)
+		return result
+	}
+	result.rotationInterval = cert.NotAfter.Sub(cert.NotBefore)
+
+	if !r.now().Before(renewAt) || !certificateNamesMatch(spec, cert) {
+		result.needsRotation = true
+	} else {
+		timeUntilRenewal := renewAt.Sub(r.now())
+		result.timeUntilRotation = &timeUntilRenewal
+	}
+
+	return result
+}
+
+// certificateRenewalTime returns when the certificate is due for renewal
+func (r *SecretReconciler) certificateRenewalTime(spec isoannotations.Certificate, cert *x509.Certificate) (time.Time, error) {
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	if spec.RenewBefore > 0 {
+		// Never renew on every reconciliation, e.g. when the issuer capped the lifetime
+		if spec.RenewBefore >= lifetime {
+			return time.Time{}, fmt.Errorf("%s %s is not shorter than the certificate lifetime %s",
+				AnnotationTLSRenewBefore, spec.RenewBefore, lifetime)
+		}
+		return cert.NotAfter.Add(-spec.RenewBefore), nil
+	}
+	return cert.NotBefore.Add(time.Duration(float64(lifetime) * r.certificateRenewalFraction())), nil
+}
+
+// certificateNamesMatch returns true if the subject alternative names of the certificate are
+// the ones requested by the annotations
+func certificateNamesMatch(spec isoannotations.Certificate, cert *x509.Certificate) bool {
+	ips := func(addresses []net.IP) []string {
+		out := make([]string, 0, len(addresses))
+		for _, ip := range addresses {
+			out = append(out, ip.String())
+		}
+		slices.Sort(out)
+		return out
+	}
+	return slices.Equal(slices.Sorted(slices.Values(spec.DNSNames)), slices.Sorted(slices.Values(cert.DNSNames))) &&
+		slices.Equal(ips(spec.IPAddresses), ips(cert.IPAddresses))
+}
+
+// generateCertificateField generates (or renews) a self-signed certificate for a field.
+// The PEM-encoded certificate is stored in the field itself and the PEM-encoded private key
+// in the corresponding key field (see generator.CertificateKeyField).
+func (r *SecretReconciler) generateCertificateField(
+	ctx context.Context,
+	secret *corev1.Secret,
+	field string,
+	rotationOpts rotationOptions,
+	logger logr.Logger,
+) fieldGenerationResult {
+	result := fieldGenerationResult{field: field}
+	keyField := generator.CertificateKeyField(field)
+
+	_, certExists := secret.Data[field]
+	_, keyExists := secret.Data[keyField]
+	exists := certExists && keyExists
+
+	renewalCheck := r.checkCertificateRenewal(secret, field)
+	if renewalCheck.err != nil {
+		logger.Error(renewalCheck.err, "Cannot check certificate renewal", "field", field)
+		r.EventRecorder.Event(secret, corev1.EventTypeWarning, EventReasonRotationFailed, renewalCheck.errMsg)
+		if exists && !rotationOpts.force {
+			return result
+		}
+	}
+
+	// An explicitly requested rotation renews the certificate regardless of its lifetime
+	if rotationOpts.force && exists {
+		renewalCheck.needsRotation = true
+	}
+
+	if exists && (!renewalCheck.needsRotation || !rotationOpts.allow) {
+		logger.V(1).Info("Certificate is still valid, skipping", "field", field)
+		return result
+	}
+
+	issuer, err := r.resolveIssuer(ctx, secret)
+	if err != nil {
+		result.err = fmt.Errorf("cannot use issuer for field %s: %w", field, err)
+		result.errMsg = fmt.Sprintf("Cannot use issuer %q for field %q: %v",
+			secret.Annotations[AnnotationTLSIssuerSecret], field, err)
+		result.invalid = true
+		logger.Error(err, "Cannot use issuer", "field", field)
+		r.EventRecorder.Event(secret, corev1.EventTypeWarning, EventReasonGenerationFailed, result.errMsg)
+		return result
+	}
+
+	spec, err := isoannotations.ParseCertificate(secret.Annotations)
+	duration := r.certificateDuration()
+	if spec.Duration > 0 {
+		duration = spec.Duration
+	}
+	if err == nil && spec.RenewBefore >= duration {
+		err = fmt.Errorf("%s %s must be shorter than the certificate duration %s", AnnotationTLSRenewBefore, spec.RenewBefore, duration)
+	}
+	if err != nil {
+		result.err = fmt.Errorf("invalid certificate configuration for field %s: %w", field, err)
+		result.errMsg = fmt.Sprintf("Invalid certificate configuration for field %q: %v", field, err)
+		result.invalid = true
+		logger.Error(err, "Invalid certificate configuration", "field", field)
+		r.EventRecorder.Event(secret, corev1.EventTypeWarning, EventReasonGenerationFailed, result.errMsg)
+		return result
+	}
+
+	cert, err := generator.GenerateSelfSignedCertificate(generator.CertificateOptions{
+		CommonName:  secret.Name,
+		Duration:    duration,
+		NotBefore:   r.now(),
+		DNSNames:    spec.DNSNames,
+		IPAddresses: spec.IPAddresses,
+		KeyUsages:   spec.KeyUsages,
+		Issuer:      issuer,
+	})
+	if err != nil {
+		result.err = fmt.Errorf("failed to generate certificate for field %s: %w", field, err)
+		result.errMsg = fmt.Sprintf("Failed to generate certificate for field %q: %v", field, err)
+		result.skipRest = true
+		logger.Error(err, "Failed to generate certificate", "field", field)
+		r.EventRecorder.Event(secret, corev1.EventTypeWarning, EventReasonGenerationFailed, result.errMsg)
+		return result
+	}
+
+	result.value = cert.CertPEM
+	result.extraData = map[string][]byte{keyField: cert.KeyPEM}
+	result.genType = config.TypeTLS
+	result.notAfter = cert.NotAfter
+	result.rotated = renewalCheck.needsRotation
+
+	if renewalCheck.needsRotation {
+		logger.Info("Renewed certificate for field", "field", field, "notAfter", cert.NotAfter)
+	} else {
+		logger.Info("Generated certificate for field", "field", field, "notAfter", cert.NotAfter)
+	}
+
+	return result
+}
+
+// resolveIssuer returns the CA referenced by the tls.issuer-secret annotation, or nil if the
+// certificate is self-signed. CA Secrets in other namespaces must list the namespace of the
+// Secret in their tls.issuer-for-namespaces annotation, so that tenants cannot sign certificates
+// with a CA they have no access to.
+func (r *SecretReconciler) resolveIssuer(ctx context.Context, secret *corev1.Secret) (*generator.CA, error) {
+	ref := strings.TrimSpace(secret.Annotations[AnnotationTLSIssuerSecret])
+	if ref == "" {
+		return nil, nil
+	}
+	key := client.ObjectKey{Namespace: secret.Namespace, Name: ref}
+	if namespace, name, found := strings.Cut(ref, "/"); found {
+		key = client.ObjectKey{Namespace: strings.TrimSpace(namespace), Name: strings.TrimSpace(name)}
+	}
+
+	var caSecret corev1.Secret
+	if err := r.Get(ctx, key, &caSecret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("CA Secret %s not found", key)
+		}
+		return nil, fmt.Errorf("failed to get CA Secret %s: %w", key, err)
+	}
+	if caSecret.Namespace != secret.Namespace {
+		patterns := replicator.ParseTargetNamespaces(caSecret.Annotations[AnnotationTLSIssuerForNamespaces])
+		if !replicator.MatchesAnyNamespace(secret.Namespace, patterns) {
+			return nil, fmt.Errorf("CA Secret %s does not allow namespace %q in %s",
+				key, secret.Namespace, AnnotationTLSIssuerForNamespaces)
+		}
+	}
+	ca, err := generator.ParseCA(caSecret.Data[corev1.TLSCertKey], caSecret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("CA Secret %s: %w", key, err)
+	}
+	return ca, nil
+}
+
+// calculateNextRenewal returns the minimum time until the next renewal across all certificate
+// and signed-token fields, or nil if there are no such fields.
+func (r *SecretReconciler) calculateNextRenewal(secret *corev1.Secret, fields []string) *time.Duration {
+	var nextRenewal *time.Duration
+
+	for _, field := range fields {
+		renewalCheck, expiring := r.checkRenewal(secret, field)
+		if !expiring || renewalCheck.err != nil || renewalCheck.timeUntilRotation == nil {
+			continue
+		}
+
+		nextRenewal = minDuration(nextRenewal, renewalCheck.timeUntilRotation)
+	}
+
+	return nextRenewal
+}
+
+// EventReasonInvalidTLSPair is the Event reason for kubernetes.io/tls Secrets whose certificate
+// and private key do not match
+const EventReasonInvalidTLSPair = "InvalidTLSPair"
+
+// validateTLSPair checks that the certificate chain of a kubernetes.io/tls Secret parses and
+// matches its private key. Other Secret types and incomplete pairs are not checked.
+func validateTLSPair(secret *corev1.Secret) error {
+	if secret.Type != corev1.SecretTypeTLS {
+		return nil
+	}
+	certPEM, keyPEM := secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]
+	if len(certPEM) == 0 || len(keyPEM) == 0 {
+		return nil
+	}
+	return generator.ValidateKeyPair(certPEM, keyPEM)
+}
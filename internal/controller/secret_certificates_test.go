@@ -0,0 +1,546 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+)
+
+func newTLSSecret(data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-tls",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate: "tls.crt",
+				AnnotationType:         config.TypeTLS,
+			},
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: data,
+	}
+}
+
+func TestReconcileGeneratesCertificate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	secret := newTLSSecret(nil)
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	fakeRecorder := record.NewFakeRecorder(10)
+
+	cfg := config.NewDefaultConfig()
+	cfg.Certificates.Duration = config.Duration(90 * time.Hour)
+
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        cfg,
+		EventRecorder: fakeRecorder,
+		Clock:         &MockClock{currentTime: now},
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+	result, err := reconciler.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+
+	if _, err := tls.X509KeyPair(updated.Data["tls.crt"], updated.Data["tls.key"]); err != nil {
+		t.Fatalf("expected valid certificate/key pair, got: %v", err)
+	}
+
+	// Renewal is scheduled after 2/3 of the 90h lifetime
+	if result.RequeueAfter != 60*time.Hour {
+		t.Errorf("expected RequeueAfter %v, got %v", 60*time.Hour, result.RequeueAfter)
+	}
+
+	select {
+	case event := <-fakeRecorder.Events:
+		if !strings.Contains(event, EventReasonGenerationSucceeded) || !strings.Contains(event, "tls.crt (tls, expires") {
+			t.Errorf("unexpected event %q", event)
+		}
+	default:
+		t.Error("expected a generation event")
+	}
+}
+
+func TestReconcileRenewsCertificate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	issuedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	existing, err := generator.GenerateSelfSignedCertificate(generator.CertificateOptions{
+		CommonName: "test-tls",
+		Duration:   90 * time.Hour,
+		NotBefore:  issuedAt,
+	})
+	if err != nil {
+		t.Fatalf("failed to generate certificate: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		now           time.Time
+		fraction      float64
+		expectRenewal bool
+		expectRequeue time.Duration
+	}{
+		{
+			name:          "before renewal point",
+			now:           issuedAt.Add(30 * time.Hour),
+			fraction:      2.0 / 3.0,
+			expectRenewal: false,
+			expectRequeue: 30 * time.Hour,
+		},
+		{
+			name:          "after renewal point",
+			now:           issuedAt.Add(61 * time.Hour),
+			fraction:      2.0 / 3.0,
+			expectRenewal: true,
+		},
+		{
+			name:          "custom fraction",
+			now:           issuedAt.Add(46 * time.Hour),
+			fraction:      0.5,
+			expectRenewal: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := newTLSSecret(map[string][]byte{
+				"tls.crt": existing.CertPEM,
+				"tls.key": existing.KeyPEM,
+			})
+			// The generic rotation interval must not affect certificate fields
+			secret.Annotations[AnnotationRotate] = "1h"
+
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+			fakeRecorder := record.NewFakeRecorder(10)
+
+			cfg := config.NewDefaultConfig()
+			cfg.Certificates.RenewalFraction = tt.fraction
+
+			reconciler := &SecretReconciler{
+				Client:        fakeClient,
+				Scheme:        scheme,
+				Generator:     generator.NewSecretGenerator(),
+				Config:        cfg,
+				EventRecorder: fakeRecorder,
+				Clock:         &MockClock{currentTime: tt.now},
+			}
+
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+			result, err := reconciler.Reconcile(context.Background(), req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var updated corev1.Secret
+			if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+				t.Fatalf("failed to get secret: %v", err)
+			}
+
+			renewed := string(updated.Data["tls.crt"]) != string(existing.CertPEM)
+			if renewed != tt.expectRenewal {
+				t.Errorf("expected renewal %v, got %v", tt.expectRenewal, renewed)
+			}
+
+			if !tt.expectRenewal {
+				if result.RequeueAfter != tt.expectRequeue {
+					t.Errorf("expected RequeueAfter %v, got %v", tt.expectRequeue, result.RequeueAfter)
+				}
+				return
+			}
+
+			if _, err := tls.X509KeyPair(updated.Data["tls.crt"], updated.Data["tls.key"]); err != nil {
+				t.Errorf("expected renewed certificate and key to match: %v", err)
+			}
+
+			select {
+			case event := <-fakeRecorder.Events:
+				if !strings.Contains(event, EventReasonCertificateRenewed) {
+					t.Errorf("expected %s event, got %q", EventReasonCertificateRenewed, event)
+				}
+			default:
+				t.Error("expected a CertificateRenewed event")
+			}
+		})
+	}
+}
+
+func TestReconcileInvalidCertificateIsPreserved(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	secret := newTLSSecret(map[string][]byte{
+		"tls.crt": []byte("user-provided"),
+		"tls.key": []byte("user-provided"),
+	})
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	fakeRecorder := record.NewFakeRecorder(10)
+
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: fakeRecorder,
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if string(updated.Data["tls.crt"]) != "user-provided" {
+		t.Error("expected unparseable certificate to be preserved")
+	}
+
+	select {
+	case event := <-fakeRecorder.Events:
+		if !strings.Contains(event, EventReasonRotationFailed) {
+			t.Errorf("expected %s event, got %q", EventReasonRotationFailed, event)
+		}
+	default:
+		t.Error("expected a warning event")
+	}
+}
+
+func TestReconcileRejectsInvalidTLSPair(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	cert, err := generator.GenerateSelfSignedCertificate(generator.CertificateOptions{CommonName: "server", Duration: time.Hour})
+	if err != nil {
+		t.Fatalf("failed to generate certificate: %v", err)
+	}
+	// A string generated into tls.key never matches the provided certificate
+	secret := newTLSSecret(map[string][]byte{"tls.crt": cert.CertPEM})
+	secret.Annotations = map[string]string{AnnotationAutogenerate: "tls.key"}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	fakeRecorder := record.NewFakeRecorder(10)
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: fakeRecorder,
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if _, ok := updated.Data["tls.key"]; ok {
+		t.Error("expected invalid TLS pair not to be written")
+	}
+
+	select {
+	case event := <-fakeRecorder.Events:
+		if !strings.Contains(event, EventReasonInvalidTLSPair) {
+			t.Errorf("expected InvalidTLSPair event, got %q", event)
+		}
+	default:
+		t.Error("expected an InvalidTLSPair event")
+	}
+}
+
+func TestReconcileSignsCertificateWithIssuer(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	caCert, err := generator.GenerateSelfSignedCertificate(generator.CertificateOptions{
+		CommonName: "internal-ca",
+		Duration:   365 * 24 * time.Hour,
+		NotBefore:  now,
+		IsCA:       true,
+	})
+	if err != nil {
+		t.Fatalf("failed to generate CA: %v", err)
+	}
+	caSecret := func(namespace string, annotations map[string]string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "internal-ca", Namespace: namespace, Annotations: annotations},
+			Type:       corev1.SecretTypeTLS,
+			Data:       map[string][]byte{"tls.crt": caCert.CertPEM, "tls.key": caCert.KeyPEM},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		issuer      string
+		caSecret    *corev1.Secret
+		expectError string
+	}{
+		{
+			name:     "same namespace",
+			issuer:   "internal-ca",
+			caSecret: caSecret("default", nil),
+		},
+		{
+			name:   "other namespace allowed",
+			issuer: "pki/internal-ca",
+			caSecret: caSecret("pki", map[string]string{
+				AnnotationTLSIssuerForNamespaces: "staging,def*",
+			}),
+		},
+		{
+			name:        "other namespace not allowed",
+			issuer:      "pki/internal-ca",
+			caSecret:    caSecret("pki", map[string]string{AnnotationTLSIssuerForNamespaces: "staging"}),
+			expectError: "does not allow namespace",
+		},
+		{
+			name:        "missing CA Secret",
+			issuer:      "pki/missing",
+			caSecret:    caSecret("pki", nil),
+			expectError: "not found",
+		},
+		{
+			name:   "not a CA",
+			issuer: "internal-ca",
+			caSecret: func() *corev1.Secret {
+				leaf, _ := generator.GenerateSelfSignedCertificate(generator.CertificateOptions{
+					CommonName: "leaf", Duration: time.Hour, NotBefore: now,
+				})
+				secret := caSecret("default", nil)
+				secret.Data = map[string][]byte{"tls.crt": leaf.CertPEM, "tls.key": leaf.KeyPEM}
+				return secret
+			}(),
+			expectError: "not a CA certificate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = clientgoscheme.AddToScheme(scheme)
+
+			secret := newTLSSecret(nil)
+			secret.Annotations[AnnotationTLSIssuerSecret] = tt.issuer
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret, tt.caSecret).Build()
+			fakeRecorder := record.NewFakeRecorder(10)
+			reconciler := &SecretReconciler{
+				Client:        fakeClient,
+				Scheme:        scheme,
+				Generator:     generator.NewSecretGenerator(),
+				Config:        config.NewDefaultConfig(),
+				EventRecorder: fakeRecorder,
+				Clock:         &MockClock{currentTime: now},
+			}
+
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+			if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var updated corev1.Secret
+			if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+				t.Fatalf("failed to get secret: %v", err)
+			}
+
+			if tt.expectError != "" {
+				if _, ok := updated.Data["tls.crt"]; ok {
+					t.Error("expected no certificate to be generated")
+				}
+				select {
+				case event := <-fakeRecorder.Events:
+					if !strings.Contains(event, EventReasonGenerationFailed) || !strings.Contains(event, tt.expectError) {
+						t.Errorf("expected %s event containing %q, got %q", EventReasonGenerationFailed, tt.expectError, event)
+					}
+				default:
+					t.Error("expected a GenerationFailed event")
+				}
+				return
+			}
+
+			leaf, err := generator.ParseCertificatePEM(updated.Data["tls.crt"])
+			if err != nil {
+				t.Fatalf("failed to parse generated certificate: %v", err)
+			}
+			ca, err := generator.ParseCA(caCert.CertPEM, caCert.KeyPEM)
+			if err != nil {
+				t.Fatalf("failed to parse CA: %v", err)
+			}
+			roots := x509.NewCertPool()
+			roots.AddCert(ca.Certificate)
+			if _, err := leaf.Verify(x509.VerifyOptions{Roots: roots, CurrentTime: now.Add(time.Hour)}); err != nil {
+				t.Errorf("expected certificate to be signed by the CA: %v", err)
+			}
+			if _, err := tls.X509KeyPair(updated.Data["tls.crt"], updated.Data["tls.key"]); err != nil {
+				t.Errorf("expected valid certificate/key pair, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestReconcileCertificateAnnotations(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	secret := newTLSSecret(nil)
+	secret.Annotations[AnnotationTLSDNSNames] = "app.example.com,app.default.svc"
+	secret.Annotations[AnnotationTLSIPAddresses] = "10.0.0.1"
+	secret.Annotations[AnnotationTLSKeyUsages] = "digital-signature,server-auth,client-auth"
+	secret.Annotations[AnnotationTLSDuration] = "10d"
+	secret.Annotations[AnnotationTLSRenewBefore] = "2d"
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	fakeRecorder := record.NewFakeRecorder(10)
+	mockClock := &MockClock{currentTime: now}
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: fakeRecorder,
+		Clock:         mockClock,
+	}
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+
+	result, err := reconciler.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Renewal is scheduled 2 days before the expiry after 10 days
+	if result.RequeueAfter != 8*24*time.Hour {
+		t.Errorf("expected RequeueAfter %v, got %v", 8*24*time.Hour, result.RequeueAfter)
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	cert, err := generator.ParseCertificatePEM(updated.Data["tls.crt"])
+	if err != nil {
+		t.Fatalf("failed to parse generated certificate: %v", err)
+	}
+	if !cert.NotAfter.Equal(now.Add(10 * 24 * time.Hour)) {
+		t.Errorf("expected certificate to expire after 10 days, got %s", cert.NotAfter)
+	}
+	if strings.Join(cert.DNSNames, ",") != "app.example.com,app.default.svc" {
+		t.Errorf("unexpected DNS names %v", cert.DNSNames)
+	}
+	if len(cert.IPAddresses) != 1 || cert.IPAddresses[0].String() != "10.0.0.1" {
+		t.Errorf("unexpected IP addresses %v", cert.IPAddresses)
+	}
+	if len(cert.ExtKeyUsage) != 2 || cert.KeyUsage != x509.KeyUsageDigitalSignature {
+		t.Errorf("unexpected key usages %v %v", cert.KeyUsage, cert.ExtKeyUsage)
+	}
+	<-fakeRecorder.Events
+
+	// Changing the subject alternative names reissues the certificate
+	updated.Annotations[AnnotationTLSDNSNames] = "app.example.com"
+	if err := fakeClient.Update(ctx, &updated); err != nil {
+		t.Fatalf("failed to update secret: %v", err)
+	}
+	mockClock.currentTime = now.Add(time.Hour)
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := fakeClient.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if cert, err = generator.ParseCertificatePEM(updated.Data["tls.crt"]); err != nil {
+		t.Fatalf("failed to parse renewed certificate: %v", err)
+	}
+	if strings.Join(cert.DNSNames, ",") != "app.example.com" {
+		t.Errorf("expected certificate to be reissued for the new DNS names, got %v", cert.DNSNames)
+	}
+	select {
+	case event := <-fakeRecorder.Events:
+		if !strings.Contains(event, EventReasonCertificateRenewed) {
+			t.Errorf("expected %s event, got %q", EventReasonCertificateRenewed, event)
+		}
+	default:
+		t.Error("expected a CertificateRenewed event")
+	}
+}
+
+func TestReconcileInvalidCertificateAnnotations(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	secret := newTLSSecret(nil)
+	secret.Annotations[AnnotationTLSKeyUsages] = "code-signing"
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	fakeRecorder := record.NewFakeRecorder(10)
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: fakeRecorder,
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var updated corev1.Secret
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if _, ok := updated.Data["tls.crt"]; ok {
+		t.Error("expected no certificate to be generated")
+	}
+	select {
+	case event := <-fakeRecorder.Events:
+		if !strings.Contains(event, EventReasonGenerationFailed) || !strings.Contains(event, "unknown key usage") {
+			t.Errorf("unexpected event %q", event)
+		}
+	default:
+		t.Error("expected a GenerationFailed event")
+	}
+}
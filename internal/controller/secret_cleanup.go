@@ -0,0 +1,206 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	isoannotations "github.com/guided-traffic/internal-secrets-operator/pkg/annotations"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+// EventReasonGeneratedFieldsRemoved is the event reason for the cleanup of fields removed from autogenerate
+const EventReasonGeneratedFieldsRemoved = "GeneratedFieldsRemoved"
+
+// bookkeepingAnnotations are the annotations the generator maintains on a Secret. They are removed
+// together with the autogenerate annotation.
+var bookkeepingAnnotations = []string{
+	AnnotationGeneratedAt,
+	AnnotationGeneratedKeys,
+	AnnotationGenerationError,
+	AnnotationReady,
+	AnnotationRotationConfigError,
+	AnnotationRotationRequestedAt,
+	AnnotationRotationRequestHandledAt,
+}
+
+// recordGeneratedKeys adds the given data keys to the generated-keys annotation
+func recordGeneratedKeys(secret *corev1.Secret, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	recorded := isoannotations.ParseFields(secret.Annotations[AnnotationGeneratedKeys])
+	for _, key := range keys {
+		if !slices.Contains(recorded, key) {
+			recorded = append(recorded, key)
+		}
+	}
+	slices.Sort(recorded)
+	secret.Annotations[AnnotationGeneratedKeys] = strings.Join(recorded, ",")
+}
+
+// setOrigins records generated as the origin of the keys generated in this reconciliation. Fields
+// with a value but without a recorded origin were generated before origins were recorded if they
+// are listed in generated-keys, and were set by someone else otherwise. It returns true if an
+// origin changed.
+func setOrigins(secret *corev1.Secret, fields, generated []string) bool {
+	changed := false
+	for _, key := range generated {
+		if isoannotations.SetOrigin(secret.Annotations, key, isoannotations.OriginGenerated) {
+			changed = true
+		}
+	}
+	recorded := isoannotations.ParseFields(secret.Annotations[AnnotationGeneratedKeys])
+	for _, field := range fields {
+		if _, ok := secret.Data[field]; !ok {
+			continue
+		}
+		if _, ok := secret.Annotations[AnnotationOriginPrefix+field]; ok {
+			continue
+		}
+		origin := isoannotations.OriginExternal
+		if slices.Contains(recorded, field) {
+			origin = isoannotations.OriginGenerated
+		}
+		if isoannotations.SetOrigin(secret.Annotations, field, origin) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// incrementRevision increments the revision counter of a field whose value was generated, rotated
+// or renewed. A counter that is not a number starts over. Fields that cannot form a valid
+// annotation name have no counter.
+func incrementRevision(secret *corev1.Secret, field string) {
+	name, ok := isoannotations.FieldAnnotation(AnnotationRevisionPrefix, field)
+	if !ok {
+		return
+	}
+	revision, err := strconv.ParseUint(secret.Annotations[name], 10, 64)
+	if err != nil {
+		revision = 0
+	}
+	secret.Annotations[name] = strconv.FormatUint(revision+1, 10)
+}
+
+// removeStaleFieldAnnotations removes the origins and revisions the generator recorded for keys
+// that no longer belong to a field. Origins recorded by the replicator are kept. It returns true
+// if an annotation was removed.
+func removeStaleFieldAnnotations(secret *corev1.Secret, belongsToField func(key string) bool) bool {
+	changed := false
+	for annotation, value := range secret.Annotations {
+		key, ok := strings.CutPrefix(annotation, AnnotationOriginPrefix)
+		if ok && value == isoannotations.OriginReplicated {
+			continue
+		}
+		if !ok {
+			key, ok = strings.CutPrefix(annotation, AnnotationRevisionPrefix)
+		}
+		if !ok || belongsToField(key) {
+			continue
+		}
+		delete(secret.Annotations, annotation)
+		changed = true
+	}
+	return changed
+}
+
+// recordDataChecksum sets the data-checksum annotation to the checksum of the current data
+func recordDataChecksum(secret *corev1.Secret) {
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[AnnotationDataChecksum] = replicator.DataChecksum(secret.Data)
+}
+
+// cleanupRemovedFields removes the bookkeeping of fields that are no longer listed in the
+// autogenerate annotation. If the annotation was removed entirely, all bookkeeping annotations are
+// removed. With cleanup.deleteRemovedFields, the values generated for removed fields are deleted
+// as well; values the operator did not generate are never deleted.
+func (r *SecretReconciler) cleanupRemovedFields(ctx context.Context, secret *corev1.Secret, fields []string) error {
+	recorded := isoannotations.ParseFields(secret.Annotations[AnnotationGeneratedKeys])
+	belongsToField := func(key string) bool {
+		return slices.ContainsFunc(fields, func(field string) bool {
+			return key == field || key == generator.CertificateKeyField(field) || isKeystoreKey(secret, field, key) ||
+				isHashKey(secret, field, key) || r.isOAuthClientIDKey(secret, field, key)
+		})
+	}
+
+	var kept, removed []string
+	for _, key := range recorded {
+		if belongsToField(key) {
+			kept = append(kept, key)
+		} else {
+			removed = append(removed, key)
+		}
+	}
+
+	changed := false
+	if len(fields) == 0 {
+		for _, key := range bookkeepingAnnotations {
+			if _, ok := secret.Annotations[key]; ok {
+				delete(secret.Annotations, key)
+				changed = true
+			}
+		}
+	} else if len(removed) > 0 {
+		if len(kept) == 0 {
+			delete(secret.Annotations, AnnotationGeneratedKeys)
+		} else {
+			secret.Annotations[AnnotationGeneratedKeys] = strings.Join(kept, ",")
+		}
+		changed = true
+	}
+	if removeStaleFieldAnnotations(secret, belongsToField) {
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	var deleted []string
+	if r.Config.Cleanup.DeleteRemovedFields {
+		for _, key := range removed {
+			if _, ok := secret.Data[key]; ok {
+				delete(secret.Data, key)
+				deleted = append(deleted, key)
+			}
+		}
+		if len(deleted) > 0 {
+			recordDataChecksum(secret)
+		}
+	}
+
+	if err := r.Update(ctx, secret); err != nil {
+		return fmt.Errorf("failed to clean up removed fields: %w", err)
+	}
+	log.FromContext(ctx).Info("Cleaned up removed fields", "removed", removed, "deleted", deleted)
+	if len(deleted) > 0 {
+		r.EventRecorder.Event(secret, corev1.EventTypeNormal, EventReasonGeneratedFieldsRemoved,
+			fmt.Sprintf("Deleted generated values of removed fields: %s", strings.Join(deleted, ", ")))
+	}
+	return nil
+}
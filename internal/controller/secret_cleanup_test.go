@@ -0,0 +1,251 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	isoannotations "github.com/guided-traffic/internal-secrets-operator/pkg/annotations"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+func TestReconcileRecordsGeneratedKeys(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-secret",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate: "password,username",
+			},
+		},
+		Data: map[string][]byte{"username": []byte("admin")},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	// The user-provided username was not generated by the operator
+	if got := updated.Annotations[AnnotationGeneratedKeys]; got != "password" {
+		t.Errorf("expected generated keys %q, got %q", "password", got)
+	}
+	if got := updated.Annotations[AnnotationDataChecksum]; got != replicator.DataChecksum(updated.Data) {
+		t.Errorf("expected data checksum of the generated data, got %q", got)
+	}
+	if got := updated.Annotations[AnnotationOriginPrefix+"password"]; got != isoannotations.OriginGenerated {
+		t.Errorf("expected origin %q for password, got %q", isoannotations.OriginGenerated, got)
+	}
+	if got := updated.Annotations[AnnotationOriginPrefix+"username"]; got != isoannotations.OriginExternal {
+		t.Errorf("expected origin %q for username, got %q", isoannotations.OriginExternal, got)
+	}
+}
+
+func TestReconcileSkipsFieldAnnotationsOfLongKeys(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	longField := strings.Repeat("k", 60)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-secret",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate: longField + ",key_,password",
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	for _, key := range []string{longField, "key_", "password"} {
+		if len(updated.Data[key]) == 0 {
+			t.Errorf("expected %q to be generated", key)
+		}
+	}
+	if errs := apivalidation.ValidateAnnotations(updated.Annotations, field.NewPath("metadata", "annotations")); len(errs) > 0 {
+		t.Errorf("invalid annotations: %v", errs.ToAggregate())
+	}
+	if got := updated.Annotations[AnnotationOriginPrefix+"password"]; got != isoannotations.OriginGenerated {
+		t.Errorf("expected origin %q for password, got %q", isoannotations.OriginGenerated, got)
+	}
+	if got := updated.Annotations[AnnotationRevisionPrefix+"password"]; got != "1" {
+		t.Errorf("expected revision 1 for password, got %q", got)
+	}
+}
+
+func TestReconcileCleansUpRemovedFields(t *testing.T) {
+	tests := []struct {
+		name                string
+		autogenerate        string
+		deleteRemovedFields bool
+		expectedKeys        []string
+		expectedAnnotations map[string]string
+	}{
+		{
+			name:         "autogenerate removed keeps values",
+			expectedKeys: []string{"api-key", "password", "tls.crt", "tls.key", "username"},
+			expectedAnnotations: map[string]string{
+				AnnotationTTL: "720h",
+			},
+		},
+		{
+			name:                "autogenerate removed deletes generated values",
+			deleteRemovedFields: true,
+			expectedKeys:        []string{"username"},
+			expectedAnnotations: map[string]string{
+				AnnotationTTL: "720h",
+			},
+		},
+		{
+			name:                "field dropped deletes its generated values",
+			autogenerate:        "password",
+			deleteRemovedFields: true,
+			expectedKeys:        []string{"password", "username"},
+			expectedAnnotations: map[string]string{
+				AnnotationTTL:                         "720h",
+				AnnotationAutogenerate:                "password",
+				AnnotationGeneratedAt:                 "2025-01-01T00:00:00Z",
+				AnnotationGeneratedKeys:               "password",
+				AnnotationReady:                       "true",
+				AnnotationOriginPrefix + "password":   isoannotations.OriginGenerated,
+				AnnotationRevisionPrefix + "password": "2",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = clientgoscheme.AddToScheme(scheme)
+
+			annotations := map[string]string{
+				AnnotationTTL:                         "720h",
+				AnnotationGeneratedAt:                 "2025-01-01T00:00:00Z",
+				AnnotationGeneratedKeys:               "api-key,password,tls.crt,tls.key",
+				AnnotationRotationConfigError:         "invalid iso.gtrfc.com/rotate \"weekly\"",
+				AnnotationReady:                       "true",
+				AnnotationOriginPrefix + "api-key":    isoannotations.OriginGenerated,
+				AnnotationOriginPrefix + "password":   isoannotations.OriginGenerated,
+				AnnotationOriginPrefix + "tls.crt":    isoannotations.OriginGenerated,
+				AnnotationOriginPrefix + "tls.key":    isoannotations.OriginGenerated,
+				AnnotationRevisionPrefix + "api-key":  "1",
+				AnnotationRevisionPrefix + "password": "2",
+			}
+			if tt.autogenerate != "" {
+				annotations[AnnotationAutogenerate] = tt.autogenerate
+				delete(annotations, AnnotationRotationConfigError)
+			}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "test-secret",
+					Namespace:         "default",
+					Annotations:       annotations,
+					CreationTimestamp: metav1.Now(),
+				},
+				Data: map[string][]byte{
+					"api-key":  []byte("generated"),
+					"password": []byte("generated"),
+					"tls.crt":  []byte("generated"),
+					"tls.key":  []byte("generated"),
+					"username": []byte("admin"),
+				},
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+			cfg := config.NewDefaultConfig()
+			cfg.Cleanup.DeleteRemovedFields = tt.deleteRemovedFields
+			reconciler := &SecretReconciler{
+				Client:        fakeClient,
+				Scheme:        scheme,
+				Generator:     generator.NewSecretGenerator(),
+				Config:        cfg,
+				EventRecorder: record.NewFakeRecorder(10),
+			}
+
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+			if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var updated corev1.Secret
+			if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+				t.Fatalf("failed to get secret: %v", err)
+			}
+			if keys := slices.Sorted(maps.Keys(updated.Data)); !reflect.DeepEqual(keys, tt.expectedKeys) {
+				t.Errorf("expected data keys %v, got %v", tt.expectedKeys, keys)
+			}
+			// Deleting values updates the checksum of the data
+			if tt.deleteRemovedFields {
+				if checksum := updated.Annotations[AnnotationDataChecksum]; checksum != replicator.DataChecksum(updated.Data) {
+					t.Errorf("expected data checksum of the remaining data, got %q", checksum)
+				}
+				delete(updated.Annotations, AnnotationDataChecksum)
+			}
+			if !reflect.DeepEqual(updated.Annotations, tt.expectedAnnotations) {
+				t.Errorf("expected annotations %v, got %v", tt.expectedAnnotations, updated.Annotations)
+			}
+		})
+	}
+}
@@ -19,6 +19,8 @@ package controller
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -27,63 +29,85 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
+	isoannotations "github.com/guided-traffic/internal-secrets-operator/pkg/annotations"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/clock"
 	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
 	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/policy"
 )
 
 const (
-	// AnnotationPrefix is the prefix for all secret operator annotations
-	AnnotationPrefix = "iso.gtrfc.com/"
-
-	// AnnotationAutogenerate specifies which fields to auto-generate
-	AnnotationAutogenerate = AnnotationPrefix + "autogenerate"
-
-	// AnnotationType specifies the default type of generated value (string, bytes)
-	AnnotationType = AnnotationPrefix + "type"
-
-	// AnnotationLength specifies the default length of the generated value
-	AnnotationLength = AnnotationPrefix + "length"
-
-	// AnnotationTypePrefix is the prefix for field-specific type annotations (type.<field>)
-	AnnotationTypePrefix = AnnotationPrefix + "type."
-
-	// AnnotationLengthPrefix is the prefix for field-specific length annotations (length.<field>)
-	AnnotationLengthPrefix = AnnotationPrefix + "length."
-
-	// AnnotationGeneratedAt indicates when the value was generated
-	AnnotationGeneratedAt = AnnotationPrefix + "generated-at"
-
-	// AnnotationRotate specifies the default rotation interval for all fields
-	AnnotationRotate = AnnotationPrefix + "rotate"
-
-	// AnnotationRotatePrefix is the prefix for field-specific rotation annotations (rotate.<field>)
-	AnnotationRotatePrefix = AnnotationPrefix + "rotate."
-
-	// AnnotationStringUppercase specifies whether to include uppercase letters
-	AnnotationStringUppercase = AnnotationPrefix + "string.uppercase"
-
-	// AnnotationStringLowercase specifies whether to include lowercase letters
-	AnnotationStringLowercase = AnnotationPrefix + "string.lowercase"
-
-	// AnnotationStringNumbers specifies whether to include numbers
-	AnnotationStringNumbers = AnnotationPrefix + "string.numbers"
-
-	// AnnotationStringSpecialChars specifies whether to include special characters
-	AnnotationStringSpecialChars = AnnotationPrefix + "string.specialChars"
-
-	// AnnotationStringAllowedSpecialChars specifies which special characters to use
-	AnnotationStringAllowedSpecialChars = AnnotationPrefix + "string.allowedSpecialChars"
+	// Annotation keys, defined in pkg/annotations
+	AnnotationPrefix                    = isoannotations.Prefix
+	AnnotationAutogenerate              = isoannotations.Autogenerate
+	AnnotationAutogenerateID            = isoannotations.AutogenerateID
+	AnnotationAdopt                     = isoannotations.Adopt
+	AnnotationType                      = isoannotations.Type
+	AnnotationLength                    = isoannotations.Length
+	AnnotationTypePrefix                = isoannotations.TypePrefix
+	AnnotationLengthPrefix              = isoannotations.LengthPrefix
+	AnnotationEncoding                  = isoannotations.Encoding
+	AnnotationEncodingPrefix            = isoannotations.EncodingPrefix
+	AnnotationHashPrefix                = isoannotations.HashPrefix
+	AnnotationGeneratedAt               = isoannotations.GeneratedAt
+	AnnotationGeneratedKeys             = isoannotations.GeneratedKeys
+	AnnotationDataChecksum              = isoannotations.DataChecksum
+	AnnotationRotationConfigError       = isoannotations.RotationConfigError
+	AnnotationGenerationError           = isoannotations.GenerationError
+	AnnotationReady                     = isoannotations.Ready
+	AnnotationOriginPrefix              = isoannotations.OriginPrefix
+	AnnotationRevisionPrefix            = isoannotations.RevisionPrefix
+	AnnotationRotate                    = isoannotations.Rotate
+	AnnotationRotatePrefix              = isoannotations.RotatePrefix
+	AnnotationRotateExclude             = isoannotations.RotateExclude
+	AnnotationRotateWith                = isoannotations.RotateWith
+	AnnotationRotationRequestedAt       = isoannotations.RotationRequestedAt
+	AnnotationRotationRequestHandledAt  = isoannotations.RotationRequestHandledAt
+	AnnotationBackupOf                  = isoannotations.BackupOf
+	AnnotationBackupExpiresAt           = isoannotations.BackupExpiresAt
+	AnnotationTLSDNSNames               = isoannotations.TLSDNSNames
+	AnnotationTLSIPAddresses            = isoannotations.TLSIPAddresses
+	AnnotationTLSKeyUsages              = isoannotations.TLSKeyUsages
+	AnnotationTLSDuration               = isoannotations.TLSDuration
+	AnnotationTLSRenewBefore            = isoannotations.TLSRenewBefore
+	AnnotationTLSIssuerSecret           = isoannotations.TLSIssuerSecret
+	AnnotationTLSIssuerForNamespaces    = isoannotations.TLSIssuerForNamespaces
+	AnnotationTLSKeystorePasswordField  = isoannotations.TLSKeystorePasswordField
+	AnnotationSigningKeyPrefix          = isoannotations.SigningKeyPrefix
+	AnnotationTokenDurationPrefix       = isoannotations.TokenDurationPrefix
+	AnnotationOAuthClientIDFormat       = isoannotations.OAuthClientIDFormat
+	AnnotationRenderEnv                 = isoannotations.RenderEnv
+	AnnotationRenderJSON                = isoannotations.RenderJSON
+	AnnotationRenderYAML                = isoannotations.RenderYAML
+	AnnotationRenderMapping             = isoannotations.RenderMapping
+	AnnotationTTL                       = isoannotations.TTL
+	AnnotationTTLWarnedFor              = isoannotations.TTLWarnedFor
+	AnnotationStringUppercase           = isoannotations.StringUppercase
+	AnnotationStringLowercase           = isoannotations.StringLowercase
+	AnnotationStringNumbers             = isoannotations.StringNumbers
+	AnnotationStringSpecialChars        = isoannotations.StringSpecialChars
+	AnnotationStringAllowedSpecialChars = isoannotations.StringAllowedSpecialChars
+	AnnotationStringData                = isoannotations.StringData
+	AnnotationUniqueValues              = isoannotations.UniqueValues
 
 	// Event reasons
 	EventReasonGenerationFailed    = "GenerationFailed"
 	EventReasonGenerationSucceeded = "GenerationSucceeded"
+	EventReasonFieldsAdopted       = "FieldsAdopted"
 	EventReasonRotationSucceeded   = "RotationSucceeded"
 	EventReasonRotationFailed      = "RotationFailed"
+	EventReasonCertificateRenewed  = "CertificateRenewed"
+	EventReasonTTLExpiring         = "TTLExpiring"
+	EventReasonTTLInvalid          = "TTLInvalid"
 )
 
 // SecretReconciler reconciles a Secret object
@@ -96,21 +120,30 @@ type SecretReconciler struct {
 	// Clock is used to get the current time. If nil, time.Now() is used.
 	// This allows for time mocking in tests.
 	Clock Clock
+	// RotationLimiter limits the rate of rotations. If nil, rotations are not limited.
+	RotationLimiter *RotationLimiter
+	// CatchUp spreads the rotations that are overdue at startup. If nil, they are not deferred.
+	CatchUp *RotationCatchUp
+	// Heartbeat records completed reconciles for the liveness check. If nil, nothing is recorded.
+	Heartbeat *Heartbeat
+	// Shard restricts the controller to the namespaces of one shard. If nil, all namespaces are processed.
+	Shard *Shard
+	// Locks serializes reconciles of the same Secret with the other controllers. If nil, nothing is locked.
+	Locks *SecretLocks
+	// Checkpoint prioritizes the initial list by the checkpoint of the previous leader and records
+	// the checkpoint of this one. If nil, the initial list is not prioritized.
+	Checkpoint *ResyncCheckpoint
+
+	// rotations counts rotations for the metrics endpoint (set up in SetupWithManager)
+	rotations *rotationCounter
 }
 
-// Clock is an interface for getting the current time.
+// Clock is an interface for getting the current time (see pkg/clock).
 // This allows for time mocking in tests.
-type Clock interface {
-	Now() time.Time
-}
+type Clock = clock.Clock
 
 // RealClock implements Clock using the real time.
-type RealClock struct{}
-
-// Now returns the current time.
-func (RealClock) Now() time.Time {
-	return time.Now()
-}
+type RealClock = clock.RealClock
 
 // now returns the current time using the Clock if set, otherwise time.Now()
 func (r *SecretReconciler) now() time.Time {
@@ -125,8 +158,10 @@ func (r *SecretReconciler) since(t time.Time) time.Duration {
 	return r.now().Sub(t)
 }
 
-// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=iso.gtrfc.com,resources=secretsources,verbs=get;list;watch
 
 // Reconcile handles the reconciliation of Secrets with autogenerate annotations
 func (r *SecretReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -138,15 +173,82 @@ func (r *SecretReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		// Secret was deleted, nothing to do
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	NormalizeStringData(&secret)
+
+	// Never touch Secrets owned by other components (e.g. Helm release state)
+	if r.Config.IsSecretTypeIgnored(string(secret.Type)) {
+		logger.V(1).Info("Skipping Secret of ignored type", "type", secret.Type)
+		return ctrl.Result{}, nil
+	}
+
+	// Never process Secrets that use features denied by a policy
+	allowed, err := enforcePolicies(ctx, r.Client, r.EventRecorder, r.Config.Policies, &secret, policy.GenerationFeatures)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !allowed {
+		logger.Info("Skipping Secret that violates a policy")
+		return ctrl.Result{}, nil
+	}
+
+	// Handle secret-wide TTL before anything else
+	ttlResult, err := r.handleTTL(ctx, &secret, logger)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if ttlResult.deleted {
+		return ctrl.Result{}, nil
+	}
+
+	// Take over existing fields listed in the adopt annotation
+	if err := r.adoptFields(ctx, &secret, logger); err != nil {
+		return ctrl.Result{}, err
+	}
 
 	// Parse the autogenerate annotation
 	fields := parseSecretAnnotations(secret.Annotations)
+	if err := r.cleanupRemovedFields(ctx, &secret, fields); err != nil {
+		return ctrl.Result{}, err
+	}
+	// Backups expire even if the Secret no longer generates any field
+	backupExpiresIn, err := r.expireRotationBackup(ctx, &secret)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
 	if len(fields) == 0 {
-		return ctrl.Result{}, nil
+		if err := r.syncAggregateKeys(ctx, &secret); err != nil {
+			return ctrl.Result{}, err
+		}
+		return requeueAfter(minDuration(ttlResult.requeueAfter, backupExpiresIn)), nil
+	}
+	if err := isoannotations.ValidateFields(fields); err != nil {
+		// The API server would reject the generated data; fields only change with the annotation
+		r.EventRecorder.Event(&secret, corev1.EventTypeWarning, EventReasonGenerationFailed, err.Error())
+		logger.Info("Invalid autogenerate annotation", "error", err)
+		return requeueAfter(minDuration(ttlResult.requeueAfter, backupExpiresIn)), nil
 	}
 
 	logger.Info("Reconciling Secret", "name", secret.Name, "namespace", secret.Namespace)
 
+	if err := r.syncRotationConfigError(ctx, &secret, fields); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Never generate more Secrets or fields than the namespace quota allows
+	exceeded, err := r.checkQuota(ctx, &secret, fields)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if exceeded != "" {
+		r.EventRecorder.Event(&secret, corev1.EventTypeWarning, EventReasonQuotaExceeded, exceeded)
+		logger.Info("Namespace quota exceeded", "reason", exceeded)
+		if err := r.syncReady(ctx, &secret, fields); err != nil {
+			return ctrl.Result{}, err
+		}
+		retry := quotaRetryInterval
+		return requeueAfter(minDuration(&retry, ttlResult.requeueAfter)), nil
+	}
+
 	// Initialize data map if nil
 	if secret.Data == nil {
 		secret.Data = make(map[string][]byte)
@@ -155,43 +257,206 @@ func (r *SecretReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	// Get the generated-at timestamp for rotation checks
 	generatedAt := r.getGeneratedAtTime(secret.Annotations)
 
+	// Check whether a rotation was explicitly requested (e.g. by a consuming workload)
+	rotationRequested := r.isRotationRequested(secret.Annotations, generatedAt)
+	rotationOpts := rotationOptions{
+		allow: true,
+		force: rotationRequested,
+	}
+
+	// Follow the rotations of the Secret referenced by rotate-with
+	coupled, err := r.isCoupledRotationDue(ctx, &secret, generatedAt)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if coupled {
+		logger.Info("Rotating with coupled Secret", "secret", secret.Annotations[AnnotationRotateWith])
+		rotationOpts.force = true
+	}
+
+	// Check the namespace pause and the rotation rate limit before rotating any field.
+	// Initial generation is never paused or limited.
+	var rotationDeferredFor *time.Duration
+	if rotationOpts.force || r.isRotationDue(&secret, fields, generatedAt) {
+		paused, err := r.isNamespaceRotationPaused(ctx, secret.Namespace)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		var catchUpWait time.Duration
+		if !paused && !rotationOpts.force {
+			if catchUpWait, err = r.catchUpDelay(ctx, req.NamespacedName); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		if paused {
+			// Rotation resumes once the namespace is unpaused (see secretsInNamespace)
+			logger.Info("Rotation paused for namespace")
+			rotationOpts.allow = false
+		} else if catchUpWait > 0 {
+			// Requested and coupled rotations are forced and never deferred
+			logger.Info("Rotation deferred by catch-up window", "retryAfter", catchUpWait)
+			rotationOpts.allow = false
+			rotationDeferredFor = &catchUpWait
+		} else if !coupled {
+			// Coupled rotations are not rate-limited, the other Secret was already rotated
+			var wait time.Duration
+			rotationOpts.allow, wait = r.RotationLimiter.TryAcquire(secret.Namespace, r.now())
+			if !rotationOpts.allow {
+				logger.Info("Rotation deferred by rate limit", "retryAfter", wait)
+				rotationDeferredFor = &wait
+			}
+		}
+	}
+
 	// Process all fields
-	updateResult := r.processSecretFields(&secret, fields, generatedAt, logger)
+	previous := maps.Clone(secret.Data)
+	updateResult := r.processSecretFields(ctx, &secret, fields, generatedAt, rotationOpts, logger)
 	if updateResult.skipRest {
 		// An error occurred during field processing. The error has already been logged
-		// and a Warning event has been created. We don't modify the secret and don't
-		// return an error (which would cause unnecessary retries).
+		// and a Warning event has been created. We don't modify the data of the secret and
+		// don't return an error (which would cause unnecessary retries).
+		if err := r.syncReady(ctx, &secret, fields); err != nil {
+			return ctrl.Result{}, err
+		}
 		return ctrl.Result{}, nil
 	}
+	// Fields with invalid configuration were skipped, their errors are written along with the other fields
+	generationErrorChanged := setGenerationError(&secret, updateResult.failed)
+	readyChanged := setReady(&secret, fields)
+	originsChanged := setOrigins(&secret, fields, updateResult.keys)
+	// A request is handled once its rotation was allowed, even if all fields are excluded
+	requestHandled := rotationRequested && rotationOpts.allow && setRotationRequestHandled(&secret)
 
 	// If changes were made, update the secret
 	if updateResult.changed {
-		if err := r.updateSecretAndEmitEvents(ctx, &secret, updateResult.rotated, logger); err != nil {
+		// Never write a TLS pair that ingress controllers would reject
+		if err := validateTLSPair(&secret); err != nil {
+			r.EventRecorder.Event(&secret, corev1.EventTypeWarning, EventReasonInvalidTLSPair,
+				fmt.Sprintf("Generated data does not form a valid TLS pair: %v", err))
+			logger.Info("Generated data does not form a valid TLS pair", "error", err)
+			return ctrl.Result{}, nil
+		}
+		// Never overwrite values that could not be backed up
+		if retention := r.Config.Rotation.BackupRetention.Duration(); retention > 0 &&
+			(len(updateResult.rotated) > 0 || len(updateResult.renewed) > 0) {
+			if err := r.backupRotatedValues(ctx, &secret, previous, updateResult.keys); err != nil {
+				r.EventRecorder.Event(&secret, corev1.EventTypeWarning, EventReasonRotationBackupFailed,
+					fmt.Sprintf("Rotation deferred, previous values could not be backed up: %v", err))
+				return ctrl.Result{}, err
+			}
+			backupExpiresIn = &retention
+		}
+		if err := r.updateSecretAndEmitEvents(ctx, &secret, updateResult, logger); err != nil {
 			return ctrl.Result{}, err
 		}
 		// Update generatedAt for next rotation calculation
 		generatedAt = r.getGeneratedAtTime(secret.Annotations)
+	} else if generationErrorChanged || readyChanged || originsChanged || requestHandled {
+		if err := r.Update(ctx, &secret); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to update generation status: %w", err)
+		}
 	}
 
-	// Calculate next rotation time and schedule requeue if needed
-	if nextRotation := r.calculateNextRotation(secret.Annotations, fields, generatedAt); nextRotation != nil {
+	// Calculate next rotation time (including certificate renewals) and schedule requeue if needed
+	nextRotation := minDuration(
+		r.calculateNextRotation(secret.Annotations, fields, generatedAt),
+		r.calculateNextRenewal(&secret, fields),
+	)
+	nextRotation = minDuration(nextRotation, rotationDeferredFor)
+	nextRotation = minDuration(nextRotation, backupExpiresIn)
+	if nextRotation != nil {
 		logger.Info("Scheduling next reconciliation for rotation", "requeueAfter", *nextRotation)
-		return ctrl.Result{RequeueAfter: *nextRotation}, nil
 	}
 
-	return ctrl.Result{}, nil
+	return requeueAfter(minDuration(nextRotation, ttlResult.requeueAfter)), nil
 }
 
-// parseFields parses a comma-separated list of field names
-func parseFields(value string) []string {
-	var fields []string
-	for _, field := range strings.Split(value, ",") {
-		field = strings.TrimSpace(field)
-		if field != "" {
-			fields = append(fields, field)
+// requeueAfter returns a result that requeues after d, or an empty result if d is nil
+func requeueAfter(d *time.Duration) ctrl.Result {
+	if d == nil {
+		return ctrl.Result{}
+	}
+	return ctrl.Result{RequeueAfter: *d}
+}
+
+// minDuration returns the smaller of two optional durations
+func minDuration(a, b *time.Duration) *time.Duration {
+	if a == nil {
+		return b
+	}
+	if b == nil || *a < *b {
+		return a
+	}
+	return b
+}
+
+// ttlResult contains the result of handling the secret-wide TTL
+type ttlResult struct {
+	deleted      bool
+	requeueAfter *time.Duration
+}
+
+// handleTTL deletes the Secret if its TTL has expired and emits a Warning event once the
+// Secret is within the configured warning window before expiry (0 disables the warning).
+// The warning is emitted once per expiry. The TTL is measured from the Secret's creation timestamp.
+func (r *SecretReconciler) handleTTL(ctx context.Context, secret *corev1.Secret, logger logr.Logger) (ttlResult, error) {
+	result := ttlResult{}
+
+	value, ok := secret.Annotations[AnnotationTTL]
+	if !ok || value == "" {
+		return result, nil
+	}
+
+	ttl, err := config.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		msg := fmt.Sprintf("Invalid TTL %q: must be a positive duration", value)
+		logger.Error(err, "Invalid TTL annotation", "ttl", value)
+		r.EventRecorder.Event(secret, corev1.EventTypeWarning, EventReasonTTLInvalid, msg)
+		return result, nil
+	}
+
+	expiresAt := secret.CreationTimestamp.Add(ttl)
+	remaining := expiresAt.Sub(r.now())
+
+	if remaining <= 0 {
+		logger.Info("TTL expired, deleting Secret", "ttl", ttl, "expiredAt", expiresAt.Format(time.RFC3339))
+		if err := r.Delete(ctx, secret); err != nil {
+			if client.IgnoreNotFound(err) == nil {
+				result.deleted = true
+				return result, nil
+			}
+			logger.Error(err, "Failed to delete expired Secret")
+			return result, err
+		}
+		result.deleted = true
+		return result, nil
+	}
+
+	warningBefore := r.Config.TTL.WarningBefore.Duration()
+	if remaining <= warningBefore {
+		expiry := expiresAt.UTC().Format(time.RFC3339)
+		if secret.Annotations[AnnotationTTLWarnedFor] != expiry {
+			original := secret.DeepCopy()
+			secret.Annotations[AnnotationTTLWarnedFor] = expiry
+			if err := r.Patch(ctx, secret, client.MergeFrom(original)); err != nil {
+				return result, fmt.Errorf("failed to record TTL warning: %w", err)
+			}
+			r.EventRecorder.Event(secret, corev1.EventTypeWarning, EventReasonTTLExpiring,
+				fmt.Sprintf("Secret will be deleted at %s (TTL %s)", expiry, value))
 		}
+		result.requeueAfter = &remaining
+		return result, nil
 	}
-	return fields
+
+	// Requeue when the warning window starts
+	untilWarning := remaining - warningBefore
+	result.requeueAfter = &untilWarning
+	return result, nil
+}
+
+// parseFields parses a comma-separated list of field names
+func parseFields(value string) []string {
+	return isoannotations.ParseFields(value)
 }
 
 // getAnnotationOrDefault returns the annotation value or a default
@@ -204,213 +469,213 @@ func (r *SecretReconciler) getAnnotationOrDefault(annotations map[string]string,
 
 // getLengthAnnotation returns the length annotation value or the default from config
 func (r *SecretReconciler) getLengthAnnotation(annotations map[string]string) int {
-	if value, ok := annotations[AnnotationLength]; ok && value != "" {
-		if length, err := strconv.Atoi(value); err == nil && length > 0 {
-			return length
-		}
-	}
-	return r.Config.Defaults.Length
+	return isoannotations.DefaultLength(annotations, r.Config.Defaults.Length)
 }
 
 // getFieldType returns the type for a specific field.
 // Priority: type.<field> annotation > type annotation > default type from config
 func (r *SecretReconciler) getFieldType(annotations map[string]string, field string) string {
-	// Check for field-specific type annotation
-	fieldTypeKey := AnnotationTypePrefix + field
-	if value, ok := annotations[fieldTypeKey]; ok && value != "" {
-		return value
-	}
-	// Fall back to default type annotation
-	return r.getAnnotationOrDefault(annotations, AnnotationType, r.Config.Defaults.Type)
+	return isoannotations.FieldType(annotations, field, r.Config.Defaults.Type)
 }
 
 // getFieldLength returns the length for a specific field.
-// Priority: length.<field> annotation > length annotation > default length
-func (r *SecretReconciler) getFieldLength(annotations map[string]string, field string) int {
-	// Check for field-specific length annotation
-	fieldLengthKey := AnnotationLengthPrefix + field
-	if value, ok := annotations[fieldLengthKey]; ok && value != "" {
-		if length, err := strconv.Atoi(value); err == nil && length > 0 {
-			return length
-		}
-	}
-	// Fall back to default length annotation
-	return r.getLengthAnnotation(annotations)
+// Priority: length.<field> annotation > length annotation > type profile > default length
+func (r *SecretReconciler) getFieldLength(secret *corev1.Secret, field string) int {
+	length, _ := r.Config.Defaults.FieldDefaults(string(secret.Type), field)
+	return isoannotations.FieldLength(secret.Annotations, field, length)
 }
 
 // getFieldRotationInterval returns the rotation interval for a specific field.
-// Priority: rotate.<field> annotation > rotate annotation > 0 (no rotation)
+// Priority: rotate.<field> annotation > rotate annotation > rotation.defaultInterval (0 = no rotation)
 func (r *SecretReconciler) getFieldRotationInterval(annotations map[string]string, field string) time.Duration {
-	// Check for field-specific rotation annotation
-	fieldRotateKey := AnnotationRotatePrefix + field
-	if value, ok := annotations[fieldRotateKey]; ok && value != "" {
-		if duration, err := config.ParseDuration(value); err == nil {
-			return duration
-		}
-	}
-	// Check for default rotation annotation
-	if value, ok := annotations[AnnotationRotate]; ok && value != "" {
-		if duration, err := config.ParseDuration(value); err == nil {
-			return duration
-		}
-	}
-	// No rotation configured
-	return 0
+	return isoannotations.FieldRotationInterval(annotations, field, r.Config.Rotation.DefaultInterval.Duration())
 }
 
-// getGeneratedAtTime parses the generated-at annotation and returns the time
+// getGeneratedAtTime parses the generated-at annotation and returns the time.
+// Timestamps in the future by no more than the clock skew tolerance are treated as now.
 func (r *SecretReconciler) getGeneratedAtTime(annotations map[string]string) *time.Time {
-	if value, ok := annotations[AnnotationGeneratedAt]; ok && value != "" {
-		if t, err := time.Parse(time.RFC3339, value); err == nil {
-			return &t
-		}
+	generatedAt := isoannotations.ParseTimestamp(annotations, AnnotationGeneratedAt)
+	if generatedAt == nil {
+		return nil
 	}
-	return nil
+	now := r.now()
+	if generatedAt.After(now) && generatedAt.Sub(now) <= r.Config.Rotation.ClockSkewTolerance.Duration() {
+		return &now
+	}
+	return generatedAt
 }
 
 // parseBoolAnnotation parses a boolean annotation value.
 // Returns the parsed value and true if the annotation exists and is valid.
 // Valid values are "true", "false", "1", "0" (case-insensitive).
 func parseBoolAnnotation(annotations map[string]string, key string) (bool, bool) {
-	value, ok := annotations[key]
-	if !ok {
-		return false, false
-	}
-	value = strings.ToLower(strings.TrimSpace(value))
-	switch value {
-	case "true", "1":
-		return true, true
-	case "false", "0":
-		return false, true
-	default:
-		return false, false
-	}
+	return isoannotations.ParseBool(annotations, key)
 }
 
-// charsetOptions holds the resolved charset configuration
-type charsetOptions struct {
-	uppercase           bool
-	lowercase           bool
-	numbers             bool
-	specialChars        bool
-	allowedSpecialChars string
-}
-
-// resolveCharsetOptions resolves charset options from annotations and config defaults.
-// Priority: annotations > config defaults
-func (r *SecretReconciler) resolveCharsetOptions(annotations map[string]string) charsetOptions {
-	opts := charsetOptions{
-		uppercase:           r.Config.Defaults.String.Uppercase,
-		lowercase:           r.Config.Defaults.String.Lowercase,
-		numbers:             r.Config.Defaults.String.Numbers,
-		specialChars:        r.Config.Defaults.String.SpecialChars,
-		allowedSpecialChars: r.Config.Defaults.String.AllowedSpecialChars,
-	}
-
-	// Override with annotations if present
-	if val, ok := parseBoolAnnotation(annotations, AnnotationStringUppercase); ok {
-		opts.uppercase = val
-	}
-	if val, ok := parseBoolAnnotation(annotations, AnnotationStringLowercase); ok {
-		opts.lowercase = val
-	}
-	if val, ok := parseBoolAnnotation(annotations, AnnotationStringNumbers); ok {
-		opts.numbers = val
-	}
-	if val, ok := parseBoolAnnotation(annotations, AnnotationStringSpecialChars); ok {
-		opts.specialChars = val
-	}
-	// Note: We check for the annotation's existence, not just non-empty value
-	// This allows users to explicitly set it to empty if they want to override the config
-	if val, ok := annotations[AnnotationStringAllowedSpecialChars]; ok {
-		opts.allowedSpecialChars = val
-	}
-
-	return opts
+// getCharsetFromAnnotations builds the charset of a field based on annotations.
+// Priority: annotations > type profile > config defaults
+// Returns the charset and an error if the configuration is invalid.
+func (r *SecretReconciler) getCharsetFromAnnotations(secret *corev1.Secret, field string) (string, error) {
+	_, defaults := r.Config.Defaults.FieldDefaults(string(secret.Type), field)
+	return isoannotations.Charset(secret.Annotations, defaults)
 }
 
-// validateCharsetOptions validates charset options.
-func validateCharsetOptions(opts charsetOptions) error {
-	// Validate that at least one charset option is enabled
-	if !opts.uppercase && !opts.lowercase && !opts.numbers && !opts.specialChars {
-		return fmt.Errorf("at least one charset option must be enabled (uppercase, lowercase, numbers, or specialChars)")
-	}
-
-	// Validate that if specialChars is enabled, allowedSpecialChars is not empty
-	if opts.specialChars && opts.allowedSpecialChars == "" {
-		return fmt.Errorf("allowedSpecialChars must not be empty when specialChars is enabled")
-	}
-
-	return nil
+// fieldChange describes a generated or rotated field (never its value)
+type fieldChange struct {
+	field    string
+	genType  string
+	length   int
+	notAfter time.Time
 }
 
-// buildCharsetString builds a charset string from charset options.
-func buildCharsetString(opts charsetOptions) string {
-	var charset string
-	if opts.lowercase {
-		charset += "abcdefghijklmnopqrstuvwxyz"
-	}
-	if opts.uppercase {
-		charset += "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
-	}
-	if opts.numbers {
-		charset += "0123456789"
-	}
-	if opts.specialChars {
-		charset += opts.allowedSpecialChars
+// String returns a human-readable description of the change, e.g. "password (string, 32)"
+// or "tls.crt (tls, expires 2025-01-01T00:00:00Z)" for certificates
+func (c fieldChange) String() string {
+	if !c.notAfter.IsZero() {
+		return fmt.Sprintf("%s (%s, expires %s)", c.field, c.genType, c.notAfter.UTC().Format(time.RFC3339))
 	}
-	return charset
+	return fmt.Sprintf("%s (%s, %d)", c.field, c.genType, c.length)
 }
 
-// getCharsetFromAnnotations builds a charset based on annotations.
-// Priority: annotations > config defaults
-// Returns the charset and an error if the configuration is invalid.
-func (r *SecretReconciler) getCharsetFromAnnotations(annotations map[string]string) (string, error) {
-	opts := r.resolveCharsetOptions(annotations)
-
-	if err := validateCharsetOptions(opts); err != nil {
-		return "", err
+// formatFieldChanges formats a list of field changes for event messages
+func formatFieldChanges(changes []fieldChange) string {
+	parts := make([]string, 0, len(changes))
+	for _, c := range changes {
+		parts = append(parts, c.String())
 	}
-
-	return buildCharsetString(opts), nil
+	return strings.Join(parts, ", ")
 }
 
 // secretUpdateResult contains the result of updating a secret
 type secretUpdateResult struct {
-	changed  bool
-	rotated  bool
+	changed bool
+	// keys lists the data keys written, including extra keys such as certificate private keys
+	keys      []string
+	generated []fieldChange
+	rotated   []fieldChange
+	renewed   []fieldChange
+	// failed maps fields that could not be generated due to their configuration to the error
+	failed   map[string]string
 	err      error
 	skipRest bool
 }
 
+// fail records that a field could not be generated due to its configuration
+func (r *secretUpdateResult) fail(field, errMsg string) {
+	if r.failed == nil {
+		r.failed = make(map[string]string)
+	}
+	r.failed[field] = errMsg
+}
+
+// rotationOptions controls how due rotations are handled while processing fields
+type rotationOptions struct {
+	// allow is false if rotations are deferred (e.g. by the rotation rate limit)
+	allow bool
+	// force rotates all existing fields regardless of their rotation interval
+	force bool
+}
+
 // processSecretFields processes all fields that need generation or rotation.
+// If rotation is not allowed, only missing fields are generated and due rotations are skipped.
+// Fields are generated all or nothing: if any field fails, the data of the Secret is rolled back
+// so that consumers never observe a Secret with only some of its fields generated.
 // It returns the update result indicating what changes were made.
 func (r *SecretReconciler) processSecretFields(
+	ctx context.Context,
 	secret *corev1.Secret,
 	fields []string,
 	generatedAt *time.Time,
+	rotationOpts rotationOptions,
 	logger logr.Logger,
 ) secretUpdateResult {
 	result := secretUpdateResult{}
+	original := maps.Clone(secret.Data)
 
-	for _, field := range fields {
-		fieldResult := r.generateFieldValue(secret, field, generatedAt, logger)
+	for _, field := range r.orderFields(secret.Annotations, fields) {
+		fieldResult := r.generateFieldValue(ctx, secret, field, generatedAt, rotationOpts, logger)
 
+		if fieldResult.invalid {
+			result.fail(field, fieldResult.errMsg)
+			continue
+		}
 		if fieldResult.skipRest {
-			result.err = fieldResult.err
-			result.skipRest = true
-			return result
+			if result.changed {
+				logger.Info("Discarding values generated in this reconciliation", "fields", result.keys)
+			}
+			secret.Data = original
+			return secretUpdateResult{err: fieldResult.err, skipRest: true}
+		}
+		if fieldResult.completed {
+			for key, value := range fieldResult.extraData {
+				secret.Data[key] = value
+				result.keys = append(result.keys, key)
+			}
+			result.changed = true
+			continue
 		}
 
 		if fieldResult.value != nil {
+			// A value that cannot be hashed is not written, so that the value and its hash never diverge
+			hashKey, hash, err := fieldHash(secret.Annotations, field, fieldResult.value)
+			if err != nil {
+				result.fail(field, r.reportHashFailure(secret, field, err, logger))
+				continue
+			}
 			secret.Data[field] = fieldResult.value
+			result.keys = append(result.keys, field)
+			for key, value := range fieldResult.extraData {
+				secret.Data[key] = value
+				result.keys = append(result.keys, key)
+			}
+			if hashKey != "" {
+				secret.Data[hashKey] = hash
+				result.keys = append(result.keys, hashKey)
+			}
 			result.changed = true
-			if fieldResult.rotated {
-				result.rotated = true
+			change := fieldChange{
+				field:    field,
+				genType:  fieldResult.genType,
+				length:   fieldResult.length,
+				notAfter: fieldResult.notAfter,
+			}
+			switch {
+			case fieldResult.rotated && fieldResult.genType == config.TypeTLS:
+				result.renewed = append(result.renewed, change)
+			case fieldResult.rotated:
+				result.rotated = append(result.rotated, change)
+			default:
+				result.generated = append(result.generated, change)
 			}
 		}
 	}
 
+	keys, err := r.renderKeystores(secret, fields, result.keys)
+	if err != nil {
+		logger.Error(err, "Failed to render keystores")
+		r.EventRecorder.Event(secret, corev1.EventTypeWarning, EventReasonGenerationFailed,
+			fmt.Sprintf("Failed to render keystores: %v", err))
+		secret.Data = original
+		return secretUpdateResult{err: err, skipRest: true}
+	}
+	if len(keys) > 0 {
+		result.keys = append(result.keys, keys...)
+		result.changed = true
+	}
+	r.renderMissingHashes(secret, fields, &result, logger)
+	// Aggregate keys (e.g. .env) are derived from the other keys, so they are rendered last and
+	// never recorded as generated keys
+	rendered, err := renderAggregateKeys(secret)
+	if err != nil {
+		logger.Error(err, "Failed to render aggregate keys")
+		r.EventRecorder.Event(secret, corev1.EventTypeWarning, EventReasonGenerationFailed, err.Error())
+		secret.Data = original
+		return secretUpdateResult{err: err, skipRest: true}
+	}
+	if rendered {
+		result.changed = true
+	}
+
 	return result
 }
 
@@ -419,39 +684,66 @@ func (r *SecretReconciler) processSecretFields(
 func (r *SecretReconciler) updateSecretAndEmitEvents(
 	ctx context.Context,
 	secret *corev1.Secret,
-	rotated bool,
+	updateResult secretUpdateResult,
 	logger logr.Logger,
 ) error {
 	// Update metadata annotations
 	if secret.Annotations == nil {
 		secret.Annotations = make(map[string]string)
 	}
-	secret.Annotations[AnnotationGeneratedAt] = r.now().Format(time.RFC3339)
+	// Rendering missing keystores alone must not reset the rotation schedule
+	if len(updateResult.generated) > 0 || len(updateResult.rotated) > 0 || len(updateResult.renewed) > 0 {
+		secret.Annotations[AnnotationGeneratedAt] = isoannotations.FormatTimestamp(r.now(), r.Config.Rotation.TimestampFormat)
+	}
+	recordGeneratedKeys(secret, updateResult.keys)
+	for _, changes := range [][]fieldChange{updateResult.generated, updateResult.rotated, updateResult.renewed} {
+		for _, change := range changes {
+			incrementRevision(secret, change.field)
+		}
+	}
+	recordDataChecksum(secret)
+	if r.useStringData(secret.Annotations) {
+		r.moveToStringData(secret, updateResult.keys)
+	}
 
-	// Update the secret
+	// Update all fields with a single write; on failure the values are discarded and
+	// regenerated by the next reconciliation
 	if err := r.Update(ctx, secret); err != nil {
 		logger.Error(err, "Failed to update Secret")
 		return err
 	}
+	NormalizeStringData(secret)
+
+	if len(updateResult.rotated) > 0 || len(updateResult.renewed) > 0 {
+		r.rotations.inc(secret.Namespace)
+	}
 
-	// Emit success event
-	r.emitSuccessEvent(secret, rotated, logger)
+	// Emit success events
+	r.emitSuccessEvents(secret, updateResult, logger)
 
 	return nil
 }
 
-// emitSuccessEvent emits the appropriate success event based on whether rotation occurred.
-func (r *SecretReconciler) emitSuccessEvent(secret *corev1.Secret, rotated bool, logger logr.Logger) {
-	if rotated {
+// emitSuccessEvents emits a GenerationSucceeded event for newly generated fields and a
+// RotationSucceeded event for rotated fields. Event messages list the affected fields
+// with their type and length, but never their values.
+func (r *SecretReconciler) emitSuccessEvents(secret *corev1.Secret, updateResult secretUpdateResult, logger logr.Logger) {
+	if len(updateResult.generated) > 0 {
+		r.EventRecorder.Event(secret, corev1.EventTypeNormal, EventReasonGenerationSucceeded,
+			fmt.Sprintf("Generated values for fields: %s", formatFieldChanges(updateResult.generated)))
+		logger.Info("Successfully updated Secret with generated values", "fields", formatFieldChanges(updateResult.generated))
+	}
+	if len(updateResult.rotated) > 0 {
 		if r.Config.Rotation.CreateEvents {
 			r.EventRecorder.Event(secret, corev1.EventTypeNormal, EventReasonRotationSucceeded,
-				"Successfully rotated values for secret fields")
+				fmt.Sprintf("Rotated values for fields: %s", formatFieldChanges(updateResult.rotated)))
 		}
-		logger.Info("Successfully rotated Secret values")
-	} else {
-		r.EventRecorder.Event(secret, corev1.EventTypeNormal, EventReasonGenerationSucceeded,
-			"Successfully generated values for secret fields")
-		logger.Info("Successfully updated Secret with generated values")
+		logger.Info("Successfully rotated Secret values", "fields", formatFieldChanges(updateResult.rotated))
+	}
+	if len(updateResult.renewed) > 0 {
+		r.EventRecorder.Event(secret, corev1.EventTypeNormal, EventReasonCertificateRenewed,
+			fmt.Sprintf("Renewed certificates for fields: %s", formatFieldChanges(updateResult.renewed)))
+		logger.Info("Successfully renewed certificates", "fields", formatFieldChanges(updateResult.renewed))
 	}
 }
 
@@ -459,10 +751,21 @@ func (r *SecretReconciler) emitSuccessEvent(secret *corev1.Secret, rotated bool,
 type fieldGenerationResult struct {
 	field    string
 	value    []byte
-	rotated  bool
-	err      error
-	errMsg   string
-	skipRest bool // if true, skip remaining fields and return error
+	genType  string
+	length   int
+	notAfter time.Time
+	// extraData holds additional keys written alongside the field (e.g. the private key of a certificate)
+	extraData map[string][]byte
+	rotated   bool
+	err       error
+	errMsg    string
+	skipRest  bool // if true, skip remaining fields and return error
+	// invalid is true if the field's configuration prevents its generation. Other fields are
+	// still generated, since the error persists until the annotations are fixed.
+	invalid bool
+	// completed is true if only extraData is written to complete an existing field (e.g. the
+	// client ID of an oauth-client field), while the field itself is unchanged
+	completed bool
 }
 
 // rotationCheckResult contains the result of checking if a field needs rotation
@@ -477,11 +780,81 @@ type rotationCheckResult struct {
 // parseSecretAnnotations parses the autogenerate annotation and returns the list of fields to generate.
 // Returns nil if the annotation is not present or empty.
 func parseSecretAnnotations(annotations map[string]string) []string {
-	autogenerate, ok := annotations[AnnotationAutogenerate]
-	if !ok || autogenerate == "" {
+	return isoannotations.Fields(annotations)
+}
+
+// syncRotationConfigError records invalid rotate annotations in the rotation-config-error
+// annotation, so that misconfigured Secrets stay discoverable after the Event expired.
+// The Warning Event is only emitted when the error changes.
+func (r *SecretReconciler) syncRotationConfigError(ctx context.Context, secret *corev1.Secret, fields []string) error {
+	var message string
+	if err := isoannotations.ValidateRotation(secret.Annotations, fields); err != nil {
+		message = err.Error()
+	}
+	if secret.Annotations[AnnotationRotationConfigError] == message {
 		return nil
 	}
-	return parseFields(autogenerate)
+
+	if message == "" {
+		delete(secret.Annotations, AnnotationRotationConfigError)
+	} else {
+		secret.Annotations[AnnotationRotationConfigError] = message
+		r.EventRecorder.Event(secret, corev1.EventTypeWarning, EventReasonRotationFailed, message)
+	}
+	if err := r.Update(ctx, secret); err != nil {
+		return fmt.Errorf("failed to update rotation config error: %w", err)
+	}
+	return nil
+}
+
+// setGenerationError records the errors of fields that could not be generated in the
+// generation-error annotation, ordered by field name, and removes the annotation once all
+// fields are generated. It returns true if the annotation changed.
+func setGenerationError(secret *corev1.Secret, failed map[string]string) bool {
+	messages := make([]string, 0, len(failed))
+	for _, field := range slices.Sorted(maps.Keys(failed)) {
+		messages = append(messages, failed[field])
+	}
+	message := strings.Join(messages, "; ")
+	if secret.Annotations[AnnotationGenerationError] == message {
+		return false
+	}
+
+	if message == "" {
+		delete(secret.Annotations, AnnotationGenerationError)
+	} else {
+		secret.Annotations[AnnotationGenerationError] = message
+	}
+	return true
+}
+
+// setReady sets the ready annotation to whether all fields and all rendered keys exist in the
+// Secret. It returns true if the annotation changed.
+func setReady(secret *corev1.Secret, fields []string) bool {
+	complete := !slices.ContainsFunc(fields, func(field string) bool {
+		_, ok := secret.Data[field]
+		return !ok
+	}) && hasAggregateKeys(secret)
+
+	ready := strconv.FormatBool(complete)
+	if secret.Annotations[AnnotationReady] == ready {
+		return false
+	}
+	secret.Annotations[AnnotationReady] = ready
+	return true
+}
+
+// syncReady updates the ready annotation of a Secret whose data is not written in this
+// reconciliation, e.g. because a field could not be generated. A Secret without the annotation
+// is not ready for consumers either, so the annotation is only added along with the data.
+func (r *SecretReconciler) syncReady(ctx context.Context, secret *corev1.Secret, fields []string) error {
+	if _, ok := secret.Annotations[AnnotationReady]; !ok || !setReady(secret, fields) {
+		return nil
+	}
+	if err := r.Update(ctx, secret); err != nil {
+		return fmt.Errorf("failed to update ready annotation: %w", err)
+	}
+	return nil
 }
 
 // checkFieldRotation checks if a field needs rotation based on annotations and timestamps.
@@ -506,7 +879,8 @@ func (r *SecretReconciler) checkFieldRotation(annotations map[string]string, fie
 	}
 
 	if generatedAt != nil {
-		timeSinceGeneration := r.since(*generatedAt)
+		// Rotations are due slightly early rather than late when clocks are skewed
+		timeSinceGeneration := r.since(*generatedAt) + r.Config.Rotation.ClockSkewTolerance.Duration()
 		if timeSinceGeneration >= rotationInterval {
 			result.needsRotation = true
 		} else {
@@ -525,13 +899,27 @@ func (r *SecretReconciler) checkFieldRotation(annotations map[string]string, fie
 // generateFieldValue generates a value for a single field based on its configuration.
 // It handles existing values, rotation checks, and value generation.
 func (r *SecretReconciler) generateFieldValue(
+	ctx context.Context,
 	secret *corev1.Secret,
 	field string,
 	generatedAt *time.Time,
+	rotationOpts rotationOptions,
 	logger logr.Logger,
 ) fieldGenerationResult {
 	result := fieldGenerationResult{field: field}
 
+	// Get field-specific generation parameters
+	genType := r.getFieldType(secret.Annotations, field)
+
+	// TLS certificates use lifetime-based renewal instead of the generic rotation interval
+	if genType == config.TypeTLS {
+		return r.generateCertificateField(ctx, secret, field, rotationOpts, logger)
+	}
+	// Signed tokens are refreshed before they expire
+	if genType == config.TypeSignedToken {
+		return r.generateSignedTokenField(secret, field, rotationOpts, logger)
+	}
+
 	// Check if field already has a value
 	_, fieldExists := secret.Data[field]
 
@@ -543,45 +931,72 @@ func (r *SecretReconciler) generateFieldValue(
 	if rotationCheck.err != nil {
 		logger.Error(nil, rotationCheck.errMsg, "field", field)
 		r.EventRecorder.Event(secret, corev1.EventTypeWarning, EventReasonRotationFailed, rotationCheck.errMsg)
-		// If field exists, skip it (invalid rotation config prevents rotation)
+		// If field exists, skip it (invalid rotation config prevents rotation, unless explicitly requested)
 		// If field doesn't exist, we still generate the initial value
-		if fieldExists {
+		if fieldExists && !rotationOpts.force {
+			if genType == config.TypeOAuthClient {
+				return r.completeOAuthClient(secret, field, logger)
+			}
 			return result
 		}
 		// Continue to generate initial value, but rotation won't work
 	}
 
-	// Skip if field already has a value and doesn't need rotation
-	if fieldExists && !rotationCheck.needsRotation {
+	// An explicitly requested rotation applies to all existing fields that are not excluded
+	if rotationOpts.force && fieldExists && !isoannotations.RotationExcluded(secret.Annotations, field) {
+		rotationCheck.needsRotation = true
+	}
+
+	// Skip if field already has a value and doesn't need rotation (or rotation is deferred)
+	if fieldExists && (!rotationCheck.needsRotation || !rotationOpts.allow) {
 		logger.V(1).Info("Field already has value, skipping", "field", field)
+		if genType == config.TypeOAuthClient {
+			return r.completeOAuthClient(secret, field, logger)
+		}
 		return result
 	}
 
-	// Get field-specific generation parameters
-	genType := r.getFieldType(secret.Annotations, field)
-	length := r.getFieldLength(secret.Annotations, field)
+	length := r.getFieldLength(secret, field)
+	if genType == config.TypeUUID {
+		length = generator.UUIDLength
+	}
 
-	// Generate the value
-	var value string
-	var err error
+	// The client secret of an OAuth client is a string
+	valueType := genType
+	if genType == config.TypeOAuthClient {
+		valueType = config.DefaultType
+	}
+	opts := generator.GenerateOptions{Type: valueType, Length: length}
 
 	// For string type, build charset from annotations
-	if genType == "string" || genType == "" {
-		charset, charsetErr := r.getCharsetFromAnnotations(secret.Annotations)
+	if valueType == config.DefaultType || valueType == "" {
+		charset, charsetErr := r.getCharsetFromAnnotations(secret, field)
 		if charsetErr != nil {
 			result.err = fmt.Errorf("invalid charset configuration for field %s: %w", field, charsetErr)
 			result.errMsg = fmt.Sprintf("Invalid charset configuration for field %q: %v", field, charsetErr)
-			result.skipRest = true
+			result.invalid = true
 			logger.Error(charsetErr, "Invalid charset configuration", "field", field)
 			r.EventRecorder.Event(secret, corev1.EventTypeWarning, EventReasonGenerationFailed, result.errMsg)
 			return result
 		}
-		value, err = r.Generator.GenerateWithCharset(genType, length, charset)
-	} else {
-		// For bytes type, use default Generate method
-		value, err = r.Generator.Generate(genType, length)
+		opts.Charset = charset
+	}
+
+	// Bytes values can be stored in a text encoding for consumers that expect strings
+	if valueType == config.TypeBytes {
+		encoding := isoannotations.FieldEncoding(secret.Annotations, field)
+		if encodingErr := generator.ValidateEncoding(encoding); encodingErr != nil {
+			result.err = fmt.Errorf("invalid encoding for field %s: %w", field, encodingErr)
+			result.errMsg = fmt.Sprintf("Invalid encoding for field %q: %v", field, encodingErr)
+			result.invalid = true
+			logger.Error(encodingErr, "Invalid encoding", "field", field)
+			r.EventRecorder.Event(secret, corev1.EventTypeWarning, EventReasonGenerationFailed, result.errMsg)
+			return result
+		}
+		opts.Encoding = encoding
 	}
 
+	value, err := r.generateDistinct(opts, r.valuesToAvoid(secret, field, rotationCheck.needsRotation))
 	if err != nil {
 		result.err = fmt.Errorf("failed to generate value for field %s: %w", field, err)
 		result.errMsg = fmt.Sprintf("Failed to generate value for field %q: %v", field, err)
@@ -591,9 +1006,15 @@ func (r *SecretReconciler) generateFieldValue(
 		return result
 	}
 
-	result.value = []byte(value)
+	result.value = value
+	result.genType = genType
+	result.length = length
 	result.rotated = rotationCheck.needsRotation
 
+	if genType == config.TypeOAuthClient && !r.addOAuthClientID(secret, &result, logger) {
+		return result
+	}
+
 	if rotationCheck.needsRotation {
 		logger.Info("Rotated value for field", "field", field, "type", genType, "length", length)
 	} else {
@@ -603,12 +1024,77 @@ func (r *SecretReconciler) generateFieldValue(
 	return result
 }
 
+// isRotationRequested returns true if the rotation-requested-at annotation was not handled yet
+// and is not older than the last generation. Invalid timestamps are ignored.
+func (r *SecretReconciler) isRotationRequested(annotations map[string]string, generatedAt *time.Time) bool {
+	requestedAt := isoannotations.ParseTimestamp(annotations, AnnotationRotationRequestedAt)
+	if requestedAt == nil || annotations[AnnotationRotationRequestHandledAt] == annotations[AnnotationRotationRequestedAt] {
+		return false
+	}
+	// Timestamps have a precision of seconds, so a request in the second of the last
+	// generation still counts
+	return generatedAt == nil || !requestedAt.Before(*generatedAt)
+}
+
+// setRotationRequestHandled records that the rotation request of the Secret was handled.
+// It returns true if the annotation changed.
+func setRotationRequestHandled(secret *corev1.Secret) bool {
+	requestedAt := secret.Annotations[AnnotationRotationRequestedAt]
+	if secret.Annotations[AnnotationRotationRequestHandledAt] == requestedAt {
+		return false
+	}
+	secret.Annotations[AnnotationRotationRequestHandledAt] = requestedAt
+	return true
+}
+
+// isExpiringType returns true for generation types whose values expire and are renewed based on
+// their lifetime instead of a rotation interval
+func isExpiringType(genType string) bool {
+	return genType == config.TypeTLS || genType == config.TypeSignedToken
+}
+
+// checkRenewal checks the renewal of a field whose value expires (certificates and signed
+// tokens). It returns false for other fields, which are rotated by interval.
+func (r *SecretReconciler) checkRenewal(secret *corev1.Secret, field string) (rotationCheckResult, bool) {
+	switch r.getFieldType(secret.Annotations, field) {
+	case config.TypeTLS:
+		return r.checkCertificateRenewal(secret, field), true
+	case config.TypeSignedToken:
+		return r.checkSignedTokenRenewal(secret, field), true
+	}
+	return rotationCheckResult{}, false
+}
+
+// isRotationDue returns true if at least one existing field is due for rotation or renewal
+func (r *SecretReconciler) isRotationDue(secret *corev1.Secret, fields []string, generatedAt *time.Time) bool {
+	for _, field := range fields {
+		if _, exists := secret.Data[field]; !exists {
+			continue
+		}
+
+		check, expiring := r.checkRenewal(secret, field)
+		if !expiring {
+			check = r.checkFieldRotation(secret.Annotations, field, generatedAt)
+		}
+
+		if check.err == nil && check.needsRotation {
+			return true
+		}
+	}
+	return false
+}
+
 // calculateNextRotation calculates the next rotation time based on all fields with rotation configured.
 // It returns the minimum time until the next rotation across all fields.
 func (r *SecretReconciler) calculateNextRotation(annotations map[string]string, fields []string, generatedAt *time.Time) *time.Duration {
 	var nextRotation *time.Duration
 
 	for _, field := range fields {
+		// Certificates and signed tokens are renewed based on their lifetime, see calculateNextRenewal
+		if isExpiringType(r.getFieldType(annotations, field)) {
+			continue
+		}
+
 		rotationCheck := r.checkFieldRotation(annotations, field, generatedAt)
 
 		// Skip fields with validation errors
@@ -633,19 +1119,73 @@ func (r *SecretReconciler) calculateNextRotation(annotations map[string]string,
 
 // SetupWithManager sets up the controller with the Manager
 func (r *SecretReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	// Create a predicate that filters secrets with the autogenerate annotation
+	// Create a predicate that filters secrets with the autogenerate, ttl or render annotations
+	// (and the opt-in label, if label-based opt-in is enabled); ignored Secret types are skipped
 	hasAutogenerateAnnotation := predicate.NewPredicateFuncs(func(object client.Object) bool {
+		if !r.Config.ManagedLabel.Matches(object.GetLabels()) {
+			return false
+		}
+		if secret, ok := object.(*corev1.Secret); ok && r.Config.IsSecretTypeIgnored(string(secret.Type)) {
+			return false
+		}
 		annotations := object.GetAnnotations()
 		if annotations == nil {
 			return false
 		}
-		_, ok := annotations[AnnotationAutogenerate]
-		return ok
+		_, hasAutogenerate := annotations[AnnotationAutogenerate]
+		_, hasAdopt := annotations[AnnotationAdopt]
+		_, hasTTL := annotations[AnnotationTTL]
+		// Secrets whose autogenerate annotation was removed still need their bookkeeping cleaned up
+		_, hasGeneratedAt := annotations[AnnotationGeneratedAt]
+		_, hasGeneratedKeys := annotations[AnnotationGeneratedKeys]
+		return hasAutogenerate || hasAdopt || hasTTL || hasGeneratedAt || hasGeneratedKeys || hasRenderAnnotation(annotations)
 	})
 
-	return ctrl.NewControllerManagedBy(mgr).
+	if err := registerCollector(newPausedNamespacesGauge(mgr.GetClient())); err != nil {
+		return err
+	}
+	if err := registerCollector(newManagedSecretsCollector(mgr.GetClient(), r.Config, r.Shard)); err != nil {
+		return err
+	}
+	if err := registerCollector(newRotationAgeCollector(mgr.GetClient(), r)); err != nil {
+		return err
+	}
+	if err := registerCollector(newInvalidRotationConfigGauge(mgr.GetClient(), r.Config, r.Shard)); err != nil {
+		return err
+	}
+	r.rotations = newRotationCounter(r.Config.Metrics)
+	if err := registerCollector(r.rotations.counter); err != nil {
+		return err
+	}
+	recorder, err := instrumentEventRecorder(r.EventRecorder)
+	if err != nil {
+		return err
+	}
+	r.EventRecorder = recorder
+	if err := r.setupCatchUp(mgr); err != nil {
+		return err
+	}
+	// coupledSecrets looks up the Secrets referencing a rotated Secret in this index
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &corev1.Secret{}, rotateWithIndexField, indexRotateWith); err != nil {
+		return err
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
 		Named("secret-generator").
-		For(&corev1.Secret{}).
-		WithEventFilter(hasAutogenerateAnnotation).
-		Complete(r)
+		// Overdue rotations are processed first when the queue backs up, see RotationDuePriority
+		Watches(&corev1.Secret{}, r.secretEventHandler(), builder.WithPredicates(hasAutogenerateAnnotation)).
+		WithOptions(ctrlcontroller.Options{UsePriorityQueue: ptr.To(true)}).
+		// Resume paused rotations when the rotation-paused annotation of a namespace changes
+		Watches(&corev1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.secretsInNamespace),
+			builder.WithPredicates(rotationPausedChanged)).
+		// Rotate Secrets coupled by rotate-with when the Secret they reference is rotated
+		Watches(&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.coupledSecrets),
+			builder.WithPredicates(generatedAtChanged))
+	if period := r.Config.Cache.ResyncPeriodFor(config.ControllerSecretGenerator); period > 0 {
+		b = b.WatchesRawSource(resyncSource(mgr.GetClient(), period, hasAutogenerateAnnotation))
+	}
+	return b.Complete(r.Heartbeat.wrap("secret-generator", r.Checkpoint.wrap(r.Shard.wrap(r.Locks.wrap(
+		reportPanics(r.EventRecorder, &corev1.Secret{}, r))))))
 }
@@ -19,11 +19,16 @@ package controller
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -352,7 +357,7 @@ func TestGetFieldLength(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := r.getFieldLength(tt.annotations, tt.field)
+			result := r.getFieldLength(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}, tt.field)
 			if result != tt.expected {
 				t.Errorf("expected %d, got %d", tt.expected, result)
 			}
@@ -360,6 +365,78 @@ func TestGetFieldLength(t *testing.T) {
 	}
 }
 
+// alphanumericCharset is the charset of the default string options
+const alphanumericCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+func TestTypeProfiles(t *testing.T) {
+	r := &SecretReconciler{
+		Config: config.NewDefaultConfig(),
+	}
+	r.Config.Defaults.BuiltinTypeProfiles = true
+
+	tests := []struct {
+		name            string
+		secretType      corev1.SecretType
+		annotations     map[string]string
+		field           string
+		expectedLength  int
+		expectedCharset string
+	}{
+		{
+			name:            "basic-auth password",
+			secretType:      corev1.SecretTypeBasicAuth,
+			field:           "password",
+			expectedLength:  32,
+			expectedCharset: alphanumericCharset + config.DefaultAllowedSpecialChars,
+		},
+		{
+			name:            "basic-auth field without profile",
+			secretType:      corev1.SecretTypeBasicAuth,
+			field:           "token",
+			expectedLength:  32,
+			expectedCharset: alphanumericCharset,
+		},
+		{
+			name:            "dockerconfigjson",
+			secretType:      corev1.SecretTypeDockerConfigJson,
+			field:           "password",
+			expectedLength:  48,
+			expectedCharset: alphanumericCharset,
+		},
+		{
+			name:            "annotations take precedence",
+			secretType:      corev1.SecretTypeDockerConfigJson,
+			annotations:     map[string]string{AnnotationLength: "20", AnnotationStringNumbers: "false"},
+			field:           "password",
+			expectedLength:  20,
+			expectedCharset: "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ",
+		},
+		{
+			name:            "opaque uses defaults",
+			secretType:      corev1.SecretTypeOpaque,
+			field:           "password",
+			expectedLength:  32,
+			expectedCharset: alphanumericCharset,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}, Type: tt.secretType}
+			if length := r.getFieldLength(secret, tt.field); length != tt.expectedLength {
+				t.Errorf("expected length %d, got %d", tt.expectedLength, length)
+			}
+			charset, err := r.getCharsetFromAnnotations(secret, tt.field)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if charset != tt.expectedCharset {
+				t.Errorf("expected charset %q, got %q", tt.expectedCharset, charset)
+			}
+		})
+	}
+}
+
 func TestReconcile(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = clientgoscheme.AddToScheme(scheme)
@@ -531,6 +608,50 @@ func TestReconcileSecretNotFound(t *testing.T) {
 	}
 }
 
+func TestReconcileSkipsIgnoredSecretTypes(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "sh.helm.release.v1.app.v1",
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-48 * time.Hour)),
+			Annotations: map[string]string{
+				AnnotationAutogenerate: "release",
+				AnnotationTTL:          "1h",
+			},
+		},
+		Type: "helm.sh/release.v1",
+		Data: map[string][]byte{"release": []byte("helm-state")},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: "default"}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+		t.Fatalf("expected Helm release Secret not to be deleted: %v", err)
+	}
+	if string(updated.Data["release"]) != "helm-state" {
+		t.Error("expected Helm release Secret not to be modified")
+	}
+	if _, ok := updated.Annotations[AnnotationGeneratedAt]; ok {
+		t.Error("expected no generated-at annotation on ignored Secret")
+	}
+}
+
 func TestReconcileEmitsSuccessEvent(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = clientgoscheme.AddToScheme(scheme)
@@ -1443,7 +1564,8 @@ func TestGetCharsetFromAnnotations(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			charset, err := r.getCharsetFromAnnotations(tt.annotations)
+			charset, err := r.getCharsetFromAnnotations(
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}, "password")
 
 			if tt.expectError {
 				if err == nil {
@@ -2179,3 +2301,1185 @@ func TestSinceMethod(t *testing.T) {
 		t.Errorf("expected since to return %v, got %v", expected, elapsed)
 	}
 }
+
+func TestReconcileWithTTL(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		ttl           string
+		now           time.Time
+		expectDeleted bool
+		expectRequeue time.Duration
+		expectEvent   string
+	}{
+		{
+			name:          "TTL expired deletes secret",
+			ttl:           "24h",
+			now:           createdAt.Add(25 * time.Hour),
+			expectDeleted: true,
+		},
+		{
+			name:          "TTL in warning window emits event",
+			ttl:           "24h",
+			now:           createdAt.Add(23*time.Hour + 30*time.Minute),
+			expectRequeue: 30 * time.Minute,
+			expectEvent:   EventReasonTTLExpiring,
+		},
+		{
+			name:          "TTL not yet in warning window requeues at warning start",
+			ttl:           "1d",
+			now:           createdAt.Add(10 * time.Hour),
+			expectRequeue: 13 * time.Hour,
+		},
+		{
+			name:        "invalid TTL emits warning",
+			ttl:         "forever",
+			now:         createdAt.Add(time.Hour),
+			expectEvent: EventReasonTTLInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "test-secret",
+					Namespace:         "default",
+					CreationTimestamp: metav1.NewTime(createdAt),
+					Annotations: map[string]string{
+						AnnotationTTL: tt.ttl,
+					},
+				},
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(secret).
+				Build()
+
+			fakeRecorder := record.NewFakeRecorder(10)
+
+			reconciler := &SecretReconciler{
+				Client:        fakeClient,
+				Scheme:        scheme,
+				Generator:     generator.NewSecretGenerator(),
+				Config:        config.NewDefaultConfig(),
+				EventRecorder: fakeRecorder,
+				Clock:         &MockClock{currentTime: tt.now},
+			}
+
+			req := ctrl.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      secret.Name,
+					Namespace: secret.Namespace,
+				},
+			}
+
+			result, err := reconciler.Reconcile(context.Background(), req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var updatedSecret corev1.Secret
+			err = fakeClient.Get(context.Background(), req.NamespacedName, &updatedSecret)
+			if tt.expectDeleted {
+				if !apierrors.IsNotFound(err) {
+					t.Errorf("expected secret to be deleted, got err %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("expected secret to still exist, got err %v", err)
+			}
+
+			if result.RequeueAfter != tt.expectRequeue {
+				t.Errorf("expected RequeueAfter %v, got %v", tt.expectRequeue, result.RequeueAfter)
+			}
+
+			select {
+			case event := <-fakeRecorder.Events:
+				if tt.expectEvent == "" {
+					t.Errorf("expected no event, got %q", event)
+				} else if !strings.Contains(event, tt.expectEvent) {
+					t.Errorf("expected event %q, got %q", tt.expectEvent, event)
+				}
+			default:
+				if tt.expectEvent != "" {
+					t.Errorf("expected event %q to be emitted", tt.expectEvent)
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileTTLWarning(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	newReconciler := func(warningBefore time.Duration) (*SecretReconciler, *record.FakeRecorder, *MockClock) {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "test-secret",
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(createdAt),
+				Annotations:       map[string]string{AnnotationTTL: "24h"},
+			},
+		}
+		cfg := config.NewDefaultConfig()
+		cfg.TTL.WarningBefore = config.Duration(warningBefore)
+		fakeRecorder := record.NewFakeRecorder(10)
+		clock := &MockClock{currentTime: createdAt.Add(23 * time.Hour)}
+		return &SecretReconciler{
+			Client:        fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build(),
+			Scheme:        scheme,
+			Generator:     generator.NewSecretGenerator(),
+			Config:        cfg,
+			EventRecorder: fakeRecorder,
+			Clock:         clock,
+		}, fakeRecorder, clock
+	}
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-secret", Namespace: "default"}}
+
+	// The warning is emitted once per expiry, not on every reconcile in the window
+	reconciler, fakeRecorder, clock := newReconciler(2 * time.Hour)
+	for range 2 {
+		if _, err := reconciler.Reconcile(ctx, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		clock.currentTime = clock.currentTime.Add(10 * time.Minute)
+	}
+	if len(fakeRecorder.Events) != 1 {
+		t.Errorf("expected one %s event, got %d", EventReasonTTLExpiring, len(fakeRecorder.Events))
+	}
+	var secret corev1.Secret
+	if err := reconciler.Get(ctx, req.NamespacedName, &secret); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if got := secret.Annotations[AnnotationTTLWarnedFor]; got != "2025-01-02T00:00:00Z" {
+		t.Errorf("expected the warned expiry to be recorded, got %q", got)
+	}
+
+	// A longer TTL is a new expiry and warned about again
+	secret.Annotations[AnnotationTTL] = "25h"
+	if err := reconciler.Update(ctx, &secret); err != nil {
+		t.Fatalf("failed to update secret: %v", err)
+	}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fakeRecorder.Events) != 2 {
+		t.Errorf("expected a %s event for the new expiry, got %d events", EventReasonTTLExpiring, len(fakeRecorder.Events))
+	}
+
+	// 0 disables the warning
+	reconciler, fakeRecorder, _ = newReconciler(0)
+	result, err := reconciler.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fakeRecorder.Events) != 0 {
+		t.Errorf("expected no event with the warning disabled, got %q", <-fakeRecorder.Events)
+	}
+	if result.RequeueAfter != time.Hour {
+		t.Errorf("expected requeue at expiry, got %v", result.RequeueAfter)
+	}
+}
+
+func TestReconcileTTLCombinedWithRotation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "test-secret",
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(now),
+			Annotations: map[string]string{
+				AnnotationAutogenerate: "password",
+				AnnotationRotate:       "24h",
+				AnnotationTTL:          "3h",
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(secret).
+		Build()
+
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+		Clock:         &MockClock{currentTime: now},
+	}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      secret.Name,
+			Namespace: secret.Namespace,
+		},
+	}
+
+	result, err := reconciler.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// TTL warning (in 2h) comes before the next rotation (in 24h)
+	if result.RequeueAfter != 2*time.Hour {
+		t.Errorf("expected RequeueAfter %v, got %v", 2*time.Hour, result.RequeueAfter)
+	}
+}
+
+func TestReconcileEventsIncludeFieldDetails(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	oldTime := time.Now().Add(-2 * time.Hour)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-secret",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate:                    "password,encryption-key",
+				AnnotationRotatePrefix + "password":       "1h",
+				AnnotationTypePrefix + "encryption-key":   "bytes",
+				AnnotationLengthPrefix + "encryption-key": "16",
+				AnnotationGeneratedAt:                     oldTime.Format(time.RFC3339),
+			},
+		},
+		Data: map[string][]byte{
+			"password": []byte("old-password"),
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(secret).
+		Build()
+
+	fakeRecorder := record.NewFakeRecorder(10)
+	cfg := config.NewDefaultConfig()
+	cfg.Rotation.CreateEvents = true
+
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        cfg,
+		EventRecorder: fakeRecorder,
+	}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      secret.Name,
+			Namespace: secret.Namespace,
+		},
+	}
+
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedEvents := []string{
+		fmt.Sprintf("%s %s Generated values for fields: encryption-key (bytes, 16)", corev1.EventTypeNormal, EventReasonGenerationSucceeded),
+		fmt.Sprintf("%s %s Rotated values for fields: password (string, 32)", corev1.EventTypeNormal, EventReasonRotationSucceeded),
+	}
+
+	for _, expected := range expectedEvents {
+		select {
+		case event := <-fakeRecorder.Events:
+			if event != expected {
+				t.Errorf("expected event %q, got %q", expected, event)
+			}
+		default:
+			t.Errorf("expected event %q to be emitted", expected)
+		}
+	}
+}
+
+func TestReconcileRequestedRotation(t *testing.T) {
+	generatedAt := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	now := generatedAt.Add(2 * time.Hour)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "db",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate:        "password",
+				AnnotationGeneratedAt:         generatedAt.Format(time.RFC3339),
+				AnnotationRotationRequestedAt: generatedAt.Add(time.Hour).Format(time.RFC3339),
+			},
+		},
+		Data: map[string][]byte{"password": []byte("old-password")},
+	}
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	r := &SecretReconciler{
+		Client:        fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build(),
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+		Clock:         &MockClock{currentTime: now},
+	}
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "db", Namespace: "default"}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated corev1.Secret
+	if err := r.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if string(updated.Data["password"]) == "old-password" {
+		t.Error("expected password to be rotated on request")
+	}
+	if updated.Annotations[AnnotationGeneratedAt] != now.Format(time.RFC3339) {
+		t.Errorf("expected generated-at to be updated, got %q", updated.Annotations[AnnotationGeneratedAt])
+	}
+
+	if got := updated.Annotations[AnnotationRotationRequestHandledAt]; got != secret.Annotations[AnnotationRotationRequestedAt] {
+		t.Errorf("expected the request to be recorded as handled, got %q", got)
+	}
+
+	// A second reconcile must not rotate again, the request was handled
+	rotated := string(updated.Data["password"])
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if string(updated.Data["password"]) != rotated {
+		t.Error("expected handled rotation request not to rotate again")
+	}
+
+	// A request in the same second as the last generation still rotates, but only once
+	updated.Annotations[AnnotationRotationRequestedAt] = now.Format(time.RFC3339)
+	if err := r.Update(ctx, &updated); err != nil {
+		t.Fatalf("failed to update secret: %v", err)
+	}
+	for i := range 2 {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := r.Get(ctx, req.NamespacedName, &updated); err != nil {
+			t.Fatalf("failed to get secret: %v", err)
+		}
+		if changed := string(updated.Data["password"]) != rotated; changed != (i == 0) {
+			t.Errorf("reconcile %d: expected rotation %v, got %v", i+1, i == 0, changed)
+		}
+		rotated = string(updated.Data["password"])
+	}
+}
+
+func TestReconcileRotateExclude(t *testing.T) {
+	generatedAt := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	for name, annotation := range map[string]string{
+		"interval":  AnnotationRotate,
+		"requested": AnnotationRotationRequestedAt,
+	} {
+		t.Run(name, func(t *testing.T) {
+			annotations := map[string]string{
+				AnnotationAutogenerate:  "username,password",
+				AnnotationGeneratedAt:   generatedAt.Format(time.RFC3339),
+				AnnotationRotateExclude: "username",
+			}
+			if annotation == AnnotationRotate {
+				annotations[AnnotationRotate] = "1h"
+			} else {
+				annotations[AnnotationRotationRequestedAt] = generatedAt.Add(time.Hour).Format(time.RFC3339)
+			}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default", Annotations: annotations},
+				Data: map[string][]byte{
+					"username": []byte("old-username"),
+					"password": []byte("old-password"),
+				},
+			}
+
+			scheme := runtime.NewScheme()
+			_ = clientgoscheme.AddToScheme(scheme)
+			r := &SecretReconciler{
+				Client:        fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build(),
+				Scheme:        scheme,
+				Generator:     generator.NewSecretGenerator(),
+				Config:        config.NewDefaultConfig(),
+				EventRecorder: record.NewFakeRecorder(10),
+				Clock:         &MockClock{currentTime: generatedAt.Add(2 * time.Hour)},
+			}
+			ctx := context.Background()
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "db", Namespace: "default"}}
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var updated corev1.Secret
+			if err := r.Get(ctx, req.NamespacedName, &updated); err != nil {
+				t.Fatalf("failed to get secret: %v", err)
+			}
+			if string(updated.Data["password"]) == "old-password" {
+				t.Error("expected password to be rotated")
+			}
+			if string(updated.Data["username"]) != "old-username" {
+				t.Errorf("expected excluded username to be kept, got %q", updated.Data["username"])
+			}
+		})
+	}
+}
+
+func TestReconcileWithDefaultRotationInterval(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	oldTime := time.Now().Add(-48 * time.Hour)
+	newSecret := func(name string, annotations map[string]string) *corev1.Secret {
+		annotations[AnnotationAutogenerate] = "password"
+		annotations[AnnotationGeneratedAt] = oldTime.Format(time.RFC3339)
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+			Data:       map[string][]byte{"password": []byte("old-password")},
+		}
+	}
+	tests := []struct {
+		name          string
+		secret        *corev1.Secret
+		expectRotated bool
+	}{
+		{name: "without rotate annotation", secret: newSecret("default-interval", map[string]string{}), expectRotated: true},
+		{name: "opted out", secret: newSecret("opted-out", map[string]string{AnnotationRotate: "0"})},
+		{name: "explicit longer interval", secret: newSecret("explicit", map[string]string{AnnotationRotate: "7d"})},
+	}
+
+	cfg := config.NewDefaultConfig()
+	cfg.Rotation.DefaultInterval = config.Duration(24 * time.Hour)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.secret).Build()
+			reconciler := &SecretReconciler{
+				Client:        fakeClient,
+				Scheme:        scheme,
+				Generator:     generator.NewSecretGenerator(),
+				Config:        cfg,
+				EventRecorder: record.NewFakeRecorder(10),
+			}
+
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: tt.secret.Name, Namespace: "default"}}
+			result, err := reconciler.Reconcile(context.Background(), req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var updated corev1.Secret
+			if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+				t.Fatalf("failed to get secret: %v", err)
+			}
+			rotated := string(updated.Data["password"]) != "old-password"
+			if rotated != tt.expectRotated {
+				t.Errorf("expected rotated=%v, got %v", tt.expectRotated, rotated)
+			}
+			if tt.expectRotated && (result.RequeueAfter <= 23*time.Hour || result.RequeueAfter > 24*time.Hour) {
+				t.Errorf("expected requeue after the default interval, got %v", result.RequeueAfter)
+			}
+		})
+	}
+}
+
+func TestReconcileRotationWithClockSkew(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		generatedAt   string
+		expectRotated bool
+	}{
+		// Written by a replica whose clock is 20s ahead; without tolerance the rotation would be delayed
+		{name: "future timestamp within tolerance", generatedAt: now.Add(-time.Hour + 20*time.Second).Format(time.RFC3339), expectRotated: true},
+		{name: "Unix timestamp due", generatedAt: strconv.FormatInt(now.Add(-2*time.Hour).Unix(), 10), expectRotated: true},
+		{name: "not yet due", generatedAt: now.Add(-30 * time.Minute).Format(time.RFC3339)},
+	}
+
+	cfg := config.NewDefaultConfig()
+	cfg.Rotation.ClockSkewTolerance = config.Duration(30 * time.Second)
+	cfg.Rotation.TimestampFormat = config.TimestampFormatUnix
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "skewed",
+					Namespace: "default",
+					Annotations: map[string]string{
+						AnnotationAutogenerate: "password",
+						AnnotationRotate:       "1h",
+						AnnotationGeneratedAt:  tt.generatedAt,
+					},
+				},
+				Data: map[string][]byte{"password": []byte("old-password")},
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+			reconciler := &SecretReconciler{
+				Client:        fakeClient,
+				Scheme:        scheme,
+				Generator:     generator.NewSecretGenerator(),
+				Config:        cfg,
+				EventRecorder: record.NewFakeRecorder(10),
+				Clock:         &MockClock{currentTime: now},
+			}
+
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "skewed", Namespace: "default"}}
+			if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var updated corev1.Secret
+			if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+				t.Fatalf("failed to get secret: %v", err)
+			}
+			rotated := string(updated.Data["password"]) != "old-password"
+			if rotated != tt.expectRotated {
+				t.Errorf("expected rotated=%v, got %v", tt.expectRotated, rotated)
+			}
+			if rotated && updated.Annotations[AnnotationGeneratedAt] != strconv.FormatInt(now.Unix(), 10) {
+				t.Errorf("expected generated-at as Unix timestamp, got %q", updated.Annotations[AnnotationGeneratedAt])
+			}
+		})
+	}
+}
+
+func TestReconcileRejectsInvalidFieldNames(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "invalid-fields",
+			Namespace:   "default",
+			Annotations: map[string]string{AnnotationAutogenerate: "password,db password"},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	fakeRecorder := record.NewFakeRecorder(10)
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: fakeRecorder,
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if len(updated.Data) != 0 {
+		t.Errorf("expected no fields to be generated, got %v", updated.Data)
+	}
+
+	select {
+	case event := <-fakeRecorder.Events:
+		if !strings.Contains(event, EventReasonGenerationFailed) || !strings.Contains(event, `"db password"`) {
+			t.Errorf("expected GenerationFailed event naming the invalid field, got %q", event)
+		}
+	default:
+		t.Error("expected a GenerationFailed event")
+	}
+}
+
+func TestReconcileRecordsInvalidRotationConfig(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "invalid-rotation",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate: "password",
+				AnnotationRotate:       "weekly",
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	fakeRecorder := record.NewFakeRecorder(10)
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: fakeRecorder,
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+	for i := 0; i < 2; i++ {
+		if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if msg := updated.Annotations[AnnotationRotationConfigError]; !strings.Contains(msg, `"weekly"`) {
+		t.Errorf("expected rotation config error naming the invalid value, got %q", msg)
+	}
+	if len(updated.Data["password"]) == 0 {
+		t.Error("expected the initial value to be generated despite the invalid rotation config")
+	}
+
+	// The Warning Event is only emitted when the error first appears
+	rotationFailed := 0
+	for len(fakeRecorder.Events) > 0 {
+		if strings.Contains(<-fakeRecorder.Events, EventReasonRotationFailed) {
+			rotationFailed++
+		}
+	}
+	if rotationFailed != 1 {
+		t.Errorf("expected 1 RotationFailed event, got %d", rotationFailed)
+	}
+
+	updated.Annotations[AnnotationRotate] = "7d"
+	if err := fakeClient.Update(context.Background(), &updated); err != nil {
+		t.Fatalf("failed to update secret: %v", err)
+	}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if _, ok := updated.Annotations[AnnotationRotationConfigError]; ok {
+		t.Error("expected rotation config error to be removed once the annotation is fixed")
+	}
+}
+
+// failingGenerator fails every call to GenerateWithOptions after the first succeeded calls
+type failingGenerator struct {
+	generator.Generator
+	succeed int
+}
+
+func (g *failingGenerator) GenerateWithOptions(opts generator.GenerateOptions) ([]byte, error) {
+	if g.succeed == 0 {
+		return nil, fmt.Errorf("simulated generation error")
+	}
+	g.succeed--
+	return g.Generator.GenerateWithOptions(opts)
+}
+
+func TestReconcilePartialGenerationFailure(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	fixedTime := time.Date(2025, 12, 6, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		data         map[string][]byte
+		expectedData map[string][]byte
+	}{
+		{
+			name: "initial generation",
+			annotations: map[string]string{
+				AnnotationAutogenerate: "username,password",
+			},
+			data:         map[string][]byte{"other": []byte("kept")},
+			expectedData: map[string][]byte{"other": []byte("kept")},
+		},
+		{
+			name: "rotation",
+			annotations: map[string]string{
+				AnnotationAutogenerate: "username,password",
+				AnnotationRotate:       "1h",
+				AnnotationGeneratedAt:  fixedTime.Add(-2 * time.Hour).Format(time.RFC3339),
+			},
+			data: map[string][]byte{
+				"username": []byte("old-username"),
+				"password": []byte("old-password"),
+			},
+			expectedData: map[string][]byte{
+				"username": []byte("old-username"),
+				"password": []byte("old-password"),
+			},
+		},
+		{
+			// The ready annotation is written without the data, so the discarded username
+			// must not make the Secret look complete
+			name: "missing field with ready annotation",
+			annotations: map[string]string{
+				AnnotationAutogenerate: "username,password",
+				AnnotationRotate:       "1h",
+				AnnotationGeneratedAt:  fixedTime.Add(-2 * time.Hour).Format(time.RFC3339),
+				AnnotationReady:        "false",
+			},
+			data:         map[string][]byte{"password": []byte("old-password")},
+			expectedData: map[string][]byte{"password": []byte("old-password")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "partial",
+					Namespace:   "default",
+					Annotations: tt.annotations,
+				},
+				Data: tt.data,
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+			fakeRecorder := record.NewFakeRecorder(10)
+			reconciler := &SecretReconciler{
+				Client:        fakeClient,
+				Scheme:        scheme,
+				Generator:     &failingGenerator{Generator: generator.NewSecretGenerator(), succeed: 1},
+				Config:        config.NewDefaultConfig(),
+				EventRecorder: fakeRecorder,
+				Clock:         &MockClock{currentTime: fixedTime},
+			}
+
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+			if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var updated corev1.Secret
+			if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+				t.Fatalf("failed to get Secret: %v", err)
+			}
+			if !reflect.DeepEqual(updated.Data, tt.expectedData) {
+				t.Errorf("expected data %v to be left untouched, got %v", tt.expectedData, updated.Data)
+			}
+			if updated.ResourceVersion != "999" {
+				t.Errorf("expected Secret not to be updated, got resourceVersion %s", updated.ResourceVersion)
+			}
+
+			select {
+			case event := <-fakeRecorder.Events:
+				if !strings.Contains(event, EventReasonGenerationFailed) {
+					t.Errorf("expected %s event, got %q", EventReasonGenerationFailed, event)
+				}
+			default:
+				t.Error("expected a GenerationFailed event")
+			}
+		})
+	}
+}
+
+func TestProcessSecretFieldsRollsBackOnFailure(t *testing.T) {
+	reconciler := &SecretReconciler{
+		Generator:     &failingGenerator{Generator: generator.NewSecretGenerator(), succeed: 1},
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{AnnotationAutogenerate: "username,password"},
+		},
+		Data: map[string][]byte{"other": []byte("kept")},
+	}
+
+	result := reconciler.processSecretFields(context.Background(), secret, []string{"username", "password"}, nil,
+		rotationOptions{allow: true}, logr.Discard())
+	if !result.skipRest || result.err == nil {
+		t.Fatalf("expected generation to fail, got %+v", result)
+	}
+	if result.changed || len(result.generated) > 0 || len(result.keys) > 0 {
+		t.Errorf("expected no changes to be reported, got %+v", result)
+	}
+	expected := map[string][]byte{"other": []byte("kept")}
+	if !reflect.DeepEqual(secret.Data, expected) {
+		t.Errorf("expected data to be rolled back to %v, got %v", expected, secret.Data)
+	}
+}
+
+func TestReconcileIsolatesInvalidFields(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	// The charset annotations disable all character classes, so only the bytes field can be generated
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "invalid-charset",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate:                  "password,encryption-key,api-key",
+				AnnotationTypePrefix + "encryption-key": "bytes",
+				AnnotationStringUppercase:               "false",
+				AnnotationStringLowercase:               "false",
+				AnnotationStringNumbers:                 "false",
+				AnnotationStringSpecialChars:            "false",
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	fakeRecorder := record.NewFakeRecorder(10)
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: fakeRecorder,
+	}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if len(updated.Data["encryption-key"]) == 0 {
+		t.Error("expected the valid field to be generated")
+	}
+	if _, ok := updated.Data["password"]; ok {
+		t.Error("expected the invalid field not to be generated")
+	}
+	// Errors are ordered by field name, independent of the autogenerate order
+	generationError := updated.Annotations[AnnotationGenerationError]
+	apiKey, password := strings.Index(generationError, `"api-key"`), strings.Index(generationError, `"password"`)
+	if apiKey < 0 || password < apiKey {
+		t.Errorf("expected errors for api-key and password in field order, got %q", generationError)
+	}
+	if ready := updated.Annotations[AnnotationReady]; ready != "false" {
+		t.Errorf("expected Secret with missing fields not to be ready, got %q", ready)
+	}
+
+	failed := 0
+	for len(fakeRecorder.Events) > 0 {
+		if strings.Contains(<-fakeRecorder.Events, EventReasonGenerationFailed) {
+			failed++
+		}
+	}
+	if failed != 2 {
+		t.Errorf("expected a GenerationFailed event per invalid field, got %d", failed)
+	}
+
+	// Fixing the charset generates the remaining fields and removes the error
+	delete(updated.Annotations, AnnotationStringLowercase)
+	if err := fakeClient.Update(ctx, &updated); err != nil {
+		t.Fatalf("failed to update secret: %v", err)
+	}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := fakeClient.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if len(updated.Data["password"]) == 0 || len(updated.Data["api-key"]) == 0 {
+		t.Errorf("expected the fixed fields to be generated, got %d keys", len(updated.Data))
+	}
+	if _, ok := updated.Annotations[AnnotationGenerationError]; ok {
+		t.Error("expected generation error to be removed once all fields are generated")
+	}
+	if ready := updated.Annotations[AnnotationReady]; ready != "true" {
+		t.Errorf("expected Secret to be ready once all fields are generated, got %q", ready)
+	}
+}
+
+func TestReconcileReadyAnnotation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		data        map[string][]byte
+		failing     bool
+		expected    string
+	}{
+		{
+			name: "all fields and rendered keys exist",
+			annotations: map[string]string{
+				AnnotationAutogenerate: "username,password",
+				AnnotationRenderEnv:    "true",
+			},
+			expected: "true",
+		},
+		{
+			name: "field added to a ready Secret cannot be generated",
+			annotations: map[string]string{
+				AnnotationAutogenerate: "username,password",
+				AnnotationReady:        "true",
+			},
+			data:     map[string][]byte{"username": []byte("admin")},
+			failing:  true,
+			expected: "false",
+		},
+		{
+			name: "initial generation fails",
+			annotations: map[string]string{
+				AnnotationAutogenerate: "username,password",
+			},
+			failing:  true,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "ready",
+					Namespace:   "default",
+					Annotations: tt.annotations,
+				},
+				Data: tt.data,
+			}
+			var gen generator.Generator = generator.NewSecretGenerator()
+			if tt.failing {
+				gen = &failingGenerator{Generator: gen}
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+			reconciler := &SecretReconciler{
+				Client:        fakeClient,
+				Scheme:        scheme,
+				Generator:     gen,
+				Config:        config.NewDefaultConfig(),
+				EventRecorder: record.NewFakeRecorder(10),
+			}
+
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+			if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var updated corev1.Secret
+			if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+				t.Fatalf("failed to get Secret: %v", err)
+			}
+			if ready := updated.Annotations[AnnotationReady]; ready != tt.expected {
+				t.Errorf("expected ready %q, got %q", tt.expected, ready)
+			}
+		})
+	}
+}
+
+func TestReconcileRevisionCounter(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	generatedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "revisions",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate:                "password,api-key,token",
+				AnnotationGeneratedAt:                 generatedAt.Format(time.RFC3339),
+				AnnotationRotationRequestedAt:         generatedAt.Add(time.Hour).Format(time.RFC3339),
+				AnnotationRevisionPrefix + "password": "4",
+				AnnotationRevisionPrefix + "api-key":  "invalid",
+				AnnotationRotateExclude:               "api-key",
+			},
+		},
+		Data: map[string][]byte{
+			"password": []byte("old-password"),
+			"api-key":  []byte("old-api-key"),
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+		Clock:         &MockClock{currentTime: generatedAt.Add(2 * time.Hour)},
+	}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	// The rotated field counts on, the new field starts at 1 and the excluded field is unchanged
+	expected := map[string]string{"password": "5", "token": "1", "api-key": "invalid"}
+	for field, revision := range expected {
+		if got := updated.Annotations[AnnotationRevisionPrefix+field]; got != revision {
+			t.Errorf("expected revision %q for %s, got %q", revision, field, got)
+		}
+	}
+}
+
+func TestReconcileUUIDType(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "instance",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate:            "instance-id,password",
+				AnnotationType:                    "uuid",
+				AnnotationTypePrefix + "password": "string",
+				AnnotationLength:                  "16",
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	fakeRecorder := record.NewFakeRecorder(10)
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: fakeRecorder,
+	}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	// The length annotation does not apply to UUIDs
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	if id := string(updated.Data["instance-id"]); !uuid.MatchString(id) {
+		t.Errorf("expected a version 4 UUID, got %q", id)
+	}
+	if password := updated.Data["password"]; len(password) != 16 || uuid.Match(password) {
+		t.Errorf("expected a string of length 16 for the overridden field, got %q", password)
+	}
+
+	event := <-fakeRecorder.Events
+	if !strings.Contains(event, "instance-id (uuid, 36)") {
+		t.Errorf("expected the UUID field with its length in the event, got %q", event)
+	}
+}
+
+func TestReconcileHexType(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "hmac",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate:              "hmac-key",
+				AnnotationType:                      "hex",
+				AnnotationLengthPrefix + "hmac-key": "32",
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+	}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	// The length is the number of random bytes, each encoded as two hex characters
+	if key := string(updated.Data["hmac-key"]); !regexp.MustCompile(`^[0-9a-f]{64}$`).MatchString(key) {
+		t.Errorf("expected 64 hex characters, got %q", key)
+	}
+}
+
+func TestReconcileBytesEncoding(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "keys",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate:                   "secret-key,jwt-key,raw-key,invalid-key",
+				AnnotationType:                           "bytes",
+				AnnotationLength:                         "32",
+				AnnotationEncoding:                       "base32",
+				AnnotationEncodingPrefix + "jwt-key":     "base64url",
+				AnnotationEncodingPrefix + "raw-key":     "raw",
+				AnnotationEncodingPrefix + "invalid-key": "rot13",
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	fakeRecorder := record.NewFakeRecorder(10)
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: fakeRecorder,
+	}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if key := string(updated.Data["secret-key"]); !regexp.MustCompile(`^[A-Z2-7]{52}={4}$`).MatchString(key) {
+		t.Errorf("expected base32 of 32 bytes, got %q", key)
+	}
+	if key := string(updated.Data["jwt-key"]); !regexp.MustCompile(`^[A-Za-z0-9_-]{43}$`).MatchString(key) {
+		t.Errorf("expected unpadded base64url of 32 bytes, got %q", key)
+	}
+	if key := updated.Data["raw-key"]; len(key) != 32 {
+		t.Errorf("expected 32 raw bytes, got %d", len(key))
+	}
+	if _, ok := updated.Data["invalid-key"]; ok {
+		t.Error("expected the field with an invalid encoding not to be generated")
+	}
+	if !strings.Contains(updated.Annotations[AnnotationGenerationError], `unknown encoding: rot13`) {
+		t.Errorf("expected the invalid encoding in the generation error, got %q", updated.Annotations[AnnotationGenerationError])
+	}
+}
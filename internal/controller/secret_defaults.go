@@ -0,0 +1,325 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	isov1alpha1 "github.com/guided-traffic/internal-secrets-operator/api/v1alpha1"
+	isoannotations "github.com/guided-traffic/internal-secrets-operator/pkg/annotations"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+const (
+	// FinalizerSecretDefaults is set on SecretDefaults to remove the applied annotations from
+	// the Secrets when the SecretDefaults is deleted
+	FinalizerSecretDefaults = AnnotationPrefix + "secret-defaults"
+
+	// EventReasonSecretDefaultsInvalid is the event reason for a SecretDefaults that cannot be applied
+	EventReasonSecretDefaultsInvalid = "SecretDefaultsInvalid"
+
+	// EventReasonSecretDefaultsFailed is the event reason for a Secret the annotations of a
+	// SecretDefaults cannot be applied to
+	EventReasonSecretDefaultsFailed = "SecretDefaultsFailed"
+)
+
+// SecretDefaultsReconciler applies the annotations of SecretDefaults to the matching Secrets of
+// their namespace with server-side apply. Each SecretDefaults applies with its own field manager,
+// so annotations it no longer sets, or sets on Secrets that no longer match, are removed again.
+// Annotations a Secret already sets itself are never overwritten.
+type SecretDefaultsReconciler struct {
+	client.Client
+	Scheme        *runtime.Scheme
+	Config        *config.Config
+	EventRecorder record.EventRecorder
+	// Heartbeat records completed reconciles for the liveness check. If nil, nothing is recorded.
+	Heartbeat *Heartbeat
+	// Shard restricts the controller to the namespaces of one shard. If nil, all namespaces are processed.
+	Shard *Shard
+}
+
+// defaultableAnnotations are the generation and rotation annotations a SecretDefaults may apply.
+// Replication, consent and bookkeeping annotations are left out: a SecretDefaults must not grant
+// more than the generation settings to users who may not edit the Secrets themselves.
+var defaultableAnnotations = []string{
+	isoannotations.Autogenerate,
+	isoannotations.Type,
+	isoannotations.Length,
+	isoannotations.Encoding,
+	isoannotations.Rotate,
+	isoannotations.RotateExclude,
+	isoannotations.TLSDNSNames,
+	isoannotations.TLSIPAddresses,
+	isoannotations.TLSKeyUsages,
+	isoannotations.TLSDuration,
+	isoannotations.TLSRenewBefore,
+	isoannotations.TLSKeystorePasswordField,
+	isoannotations.OAuthClientIDFormat,
+	isoannotations.StringUppercase,
+	isoannotations.StringLowercase,
+	isoannotations.StringNumbers,
+	isoannotations.StringSpecialChars,
+	isoannotations.StringAllowedSpecialChars,
+	isoannotations.StringData,
+	isoannotations.UniqueValues,
+}
+
+// defaultableAnnotationPrefixes are the prefixes of the field-specific annotations a SecretDefaults may apply
+var defaultableAnnotationPrefixes = []string{
+	isoannotations.TypePrefix,
+	isoannotations.LengthPrefix,
+	isoannotations.EncodingPrefix,
+	isoannotations.HashPrefix,
+	isoannotations.RotatePrefix,
+	isoannotations.TokenDurationPrefix,
+}
+
+// isDefaultableAnnotation returns true if a SecretDefaults may apply the annotation
+func isDefaultableAnnotation(key string) bool {
+	if slices.Contains(defaultableAnnotations, key) {
+		return true
+	}
+	return slices.ContainsFunc(defaultableAnnotationPrefixes, func(prefix string) bool {
+		return len(key) > len(prefix) && strings.HasPrefix(key, prefix)
+	})
+}
+
+// validateSecretDefaults returns the label selector of a SecretDefaults, or an error if the
+// selector or one of its annotations is invalid
+func validateSecretDefaults(defaults *isov1alpha1.SecretDefaults) (labels.Selector, error) {
+	for _, key := range slices.Sorted(maps.Keys(defaults.Spec.Annotations)) {
+		if !strings.HasPrefix(key, AnnotationPrefix) {
+			return nil, fmt.Errorf("annotation %s is not an operator annotation (%s...)", key, AnnotationPrefix)
+		}
+		if !isDefaultableAnnotation(key) {
+			return nil, fmt.Errorf("annotation %s cannot be applied by SecretDefaults, only generation and rotation annotations are allowed", key)
+		}
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&defaults.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector: %w", err)
+	}
+	return selector, nil
+}
+
+// appliedAnnotations returns the annotation keys the field manager owns on a Secret through
+// server-side apply
+func appliedAnnotations(secret *corev1.Secret, manager string) map[string]bool {
+	owned := make(map[string]bool)
+	for _, entry := range secret.ManagedFields {
+		if entry.Manager != manager || entry.Operation != metav1.ManagedFieldsOperationApply || entry.FieldsV1 == nil {
+			continue
+		}
+		var fields struct {
+			Metadata struct {
+				Annotations map[string]json.RawMessage `json:"f:annotations"`
+			} `json:"f:metadata"`
+		}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+		for key := range fields.Metadata.Annotations {
+			owned[strings.TrimPrefix(key, "f:")] = true
+		}
+	}
+	return owned
+}
+
+// desiredAnnotations returns the annotations of a SecretDefaults to apply to a Secret. Annotations
+// the Secret sets to a different value without the SecretDefaults owning them are left out.
+func desiredAnnotations(defaults *isov1alpha1.SecretDefaults, secret *corev1.Secret, owned map[string]bool) map[string]string {
+	desired := make(map[string]string)
+	for key, value := range defaults.Spec.Annotations {
+		if existing, ok := secret.Annotations[key]; ok && existing != value && !owned[key] {
+			continue
+		}
+		desired[key] = value
+	}
+	return desired
+}
+
+// +kubebuilder:rbac:groups=iso.gtrfc.com,resources=secretdefaults,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=iso.gtrfc.com,resources=secretdefaults/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=iso.gtrfc.com,resources=secretdefaults/finalizers,verbs=update
+
+// Reconcile applies the annotations of a SecretDefaults to the matching Secrets of its namespace
+// and removes them from Secrets that no longer match
+func (r *SecretDefaultsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var defaults isov1alpha1.SecretDefaults
+	if err := r.Get(ctx, req.NamespacedName, &defaults); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !defaults.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(&defaults, FinalizerSecretDefaults) {
+			return ctrl.Result{}, nil
+		}
+		if _, err := r.applyDefaults(ctx, &defaults, labels.Nothing()); err != nil {
+			return ctrl.Result{}, err
+		}
+		controllerutil.RemoveFinalizer(&defaults, FinalizerSecretDefaults)
+		return ctrl.Result{}, r.Update(ctx, &defaults)
+	}
+
+	if controllerutil.AddFinalizer(&defaults, FinalizerSecretDefaults) {
+		if err := r.Update(ctx, &defaults); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	selector, err := validateSecretDefaults(&defaults)
+	if err != nil {
+		// Keep what was applied before; a corrected SecretDefaults is reconciled again
+		logger.Info("Invalid SecretDefaults", "error", err.Error())
+		r.EventRecorder.Eventf(&defaults, corev1.EventTypeWarning, EventReasonSecretDefaultsInvalid,
+			"SecretDefaults cannot be applied: %v", err)
+		return ctrl.Result{}, nil
+	}
+
+	matched, err := r.applyDefaults(ctx, &defaults, selector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if defaults.Status.MatchedSecrets != matched || defaults.Status.ObservedGeneration != defaults.Generation {
+		defaults.Status.MatchedSecrets = matched
+		defaults.Status.ObservedGeneration = defaults.Generation
+		if err := r.Status().Update(ctx, &defaults); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// applyDefaults applies the annotations of a SecretDefaults to the Secrets of its namespace that
+// match the selector and removes them from all other Secrets. Secrets of ignored types never match.
+// It returns the number of matching Secrets.
+func (r *SecretDefaultsReconciler) applyDefaults(
+	ctx context.Context,
+	defaults *isov1alpha1.SecretDefaults,
+	selector labels.Selector,
+) (int32, error) {
+	logger := log.FromContext(ctx)
+	manager := defaults.FieldManager()
+
+	var secrets corev1.SecretList
+	if err := r.List(ctx, &secrets, client.InNamespace(defaults.Namespace)); err != nil {
+		return 0, fmt.Errorf("failed to list Secrets: %w", err)
+	}
+
+	var matched int32
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		owned := appliedAnnotations(secret, manager)
+
+		desired := map[string]string{}
+		if selector.Matches(labels.Set(secret.Labels)) && !r.Config.IsSecretTypeIgnored(string(secret.Type)) {
+			matched++
+			desired = desiredAnnotations(defaults, secret, owned)
+		}
+		if isApplied(secret, owned, desired) {
+			continue
+		}
+
+		apply := corev1ac.Secret(secret.Name, secret.Namespace)
+		if len(desired) > 0 {
+			apply.WithAnnotations(desired)
+		}
+		if err := r.Apply(ctx, apply, client.FieldOwner(manager)); err != nil {
+			r.EventRecorder.Eventf(secret, corev1.EventTypeWarning, EventReasonSecretDefaultsFailed,
+				"Failed to apply SecretDefaults %s: %v", defaults.Name, err)
+			return 0, fmt.Errorf("failed to apply SecretDefaults to Secret %s: %w", secret.Name, err)
+		}
+		logger.V(1).Info("Applied SecretDefaults", "secret", secret.Name, "annotations", len(desired))
+	}
+	return matched, nil
+}
+
+// isApplied returns true if the field manager owns exactly the desired annotations and the
+// Secret has the desired values
+func isApplied(secret *corev1.Secret, owned map[string]bool, desired map[string]string) bool {
+	if len(owned) != len(desired) {
+		return false
+	}
+	for key, value := range desired {
+		if !owned[key] || secret.Annotations[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// findSecretDefaultsForSecret maps a Secret to the SecretDefaults of its namespace
+func (r *SecretDefaultsReconciler) findSecretDefaultsForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	var list isov1alpha1.SecretDefaultsList
+	if err := r.List(ctx, &list, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list SecretDefaults", "namespace", obj.GetNamespace())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(list.Items))
+	for _, defaults := range list.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: defaults.Namespace, Name: defaults.Name},
+		})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager. It watches SecretDefaults and the
+// labels and annotations of Secrets, which decide what is applied to them.
+func (r *SecretDefaultsReconciler) SetupWithManager(mgr ctrl.Manager) error {
+
+	recorder, err := instrumentEventRecorder(r.EventRecorder)
+	if err != nil {
+		return err
+	}
+	r.EventRecorder = recorder
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("secret-defaults").
+		For(&isov1alpha1.SecretDefaults{}).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.findSecretDefaultsForSecret),
+			builder.WithPredicates(predicate.Or(predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{})),
+		).
+		Complete(r.Heartbeat.wrap("secret-defaults", r.Shard.wrap(
+			reportPanics(r.EventRecorder, &isov1alpha1.SecretDefaults{}, r))))
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	isov1alpha1 "github.com/guided-traffic/internal-secrets-operator/api/v1alpha1"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+func TestSecretDefaultsReconciler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = isov1alpha1.AddToScheme(scheme)
+
+	defaults := &isov1alpha1.SecretDefaults{
+		ObjectMeta: metav1.ObjectMeta{Name: "rotation", Namespace: "apps"},
+		Spec: isov1alpha1.SecretDefaultsSpec{
+			Selector: metav1.LabelSelector{MatchLabels: map[string]string{"tier": "db"}},
+			Annotations: map[string]string{
+				AnnotationRotate:             "720h",
+				AnnotationStringSpecialChars: "false",
+			},
+		},
+	}
+	matching := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "apps", Labels: map[string]string{"tier": "db"}},
+	}
+	// The Secret sets the rotation interval itself, which is not overwritten
+	custom := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "custom",
+			Namespace:   "apps",
+			Labels:      map[string]string{"tier": "db"},
+			Annotations: map[string]string{AnnotationRotate: "24h"},
+		},
+	}
+	other := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "apps", Labels: map[string]string{"tier": "web"}},
+	}
+	// Secrets of ignored types never match
+	release := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "release", Namespace: "apps", Labels: map[string]string{"tier": "db"}},
+		Type:       "helm.sh/release.v1",
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(defaults, matching, custom, other, release).
+		WithStatusSubresource(&isov1alpha1.SecretDefaults{}).
+		WithReturnManagedFields().
+		Build()
+	r := &SecretDefaultsReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+	}
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "rotation", Namespace: "apps"}}
+	getSecret := func(name string) *corev1.Secret {
+		var secret corev1.Secret
+		if err := fakeClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "apps"}, &secret); err != nil {
+			t.Fatalf("failed to get Secret %s: %v", name, err)
+		}
+		return &secret
+	}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := getSecret("db").Annotations; got[AnnotationRotate] != "720h" || got[AnnotationStringSpecialChars] != "false" {
+		t.Errorf("expected annotations to be applied, got %v", got)
+	}
+	if got := getSecret("custom").Annotations; got[AnnotationRotate] != "24h" || got[AnnotationStringSpecialChars] != "false" {
+		t.Errorf("expected own rotation interval to be kept, got %v", got)
+	}
+	if got := getSecret("web").Annotations; len(got) != 0 {
+		t.Errorf("expected non-matching Secret to be untouched, got %v", got)
+	}
+	if got := getSecret("release").Annotations; len(got) != 0 {
+		t.Errorf("expected Secret of ignored type to be untouched, got %v", got)
+	}
+
+	var updated isov1alpha1.SecretDefaults
+	if err := fakeClient.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get SecretDefaults: %v", err)
+	}
+	if updated.Status.MatchedSecrets != 2 {
+		t.Errorf("expected 2 matched Secrets, got %d", updated.Status.MatchedSecrets)
+	}
+
+	// A Secret that no longer matches loses the applied annotations
+	secret := getSecret("db")
+	secret.Labels = map[string]string{"tier": "cache"}
+	if err := fakeClient.Update(ctx, secret); err != nil {
+		t.Fatalf("failed to update Secret: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := getSecret("db").Annotations; len(got) != 0 {
+		t.Errorf("expected annotations to be removed, got %v", got)
+	}
+
+	// Deleting the SecretDefaults removes the annotations from all Secrets
+	if err := fakeClient.Delete(ctx, &updated); err != nil {
+		t.Fatalf("failed to delete SecretDefaults: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := getSecret("custom").Annotations; len(got) != 1 || got[AnnotationRotate] != "24h" {
+		t.Errorf("expected only the own annotation to be kept, got %v", got)
+	}
+	if err := fakeClient.Get(ctx, req.NamespacedName, &updated); client.IgnoreNotFound(err) != nil || err == nil {
+		t.Errorf("expected SecretDefaults to be deleted, got %v", err)
+	}
+}
+
+func TestSecretDefaultsReconciler_InvalidAnnotation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = isov1alpha1.AddToScheme(scheme)
+
+	defaults := &isov1alpha1.SecretDefaults{
+		ObjectMeta: metav1.ObjectMeta{Name: "invalid", Namespace: "apps"},
+		Spec: isov1alpha1.SecretDefaultsSpec{
+			Annotations: map[string]string{"example.com/owner": "team-a"},
+		},
+	}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "apps"}}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(defaults, secret).
+		WithStatusSubresource(&isov1alpha1.SecretDefaults{}).
+		Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &SecretDefaultsReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: recorder,
+	}
+
+	ctx := context.Background()
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(defaults)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got corev1.Secret
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(secret), &got); err != nil {
+		t.Fatalf("failed to get Secret: %v", err)
+	}
+	if len(got.Annotations) != 0 {
+		t.Errorf("expected no annotations to be applied, got %v", got.Annotations)
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, EventReasonSecretDefaultsInvalid) || !strings.Contains(event, "example.com/owner") {
+			t.Errorf("expected SecretDefaultsInvalid event mentioning the annotation, got %q", event)
+		}
+	default:
+		t.Error("expected SecretDefaultsInvalid event")
+	}
+}
+
+func TestValidateSecretDefaults(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		wantErr    bool
+	}{
+		{name: "generation", annotation: AnnotationAutogenerate},
+		{name: "field type", annotation: AnnotationTypePrefix + "password"},
+		{name: "field rotation", annotation: AnnotationRotatePrefix + "password"},
+		{name: "foreign annotation", annotation: "example.com/owner", wantErr: true},
+		{name: "bare prefix", annotation: AnnotationTypePrefix, wantErr: true},
+		{name: "replicate-to", annotation: replicator.AnnotationReplicateTo, wantErr: true},
+		{name: "replication allowlist", annotation: replicator.AnnotationReplicatableFromNamespaces, wantErr: true},
+		{name: "bookkeeping", annotation: AnnotationGeneratedAt, wantErr: true},
+		{name: "ttl", annotation: AnnotationTTL, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaults := &isov1alpha1.SecretDefaults{
+				Spec: isov1alpha1.SecretDefaultsSpec{Annotations: map[string]string{tt.annotation: "value"}},
+			}
+			_, err := validateSecretDefaults(defaults)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateSecretDefaults() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+)
+
+// fieldHash returns the companion key (e.g. password-bcrypt) and the hash of a value of a field
+// with a hash.<field> annotation, for consumers such as htpasswd files that verify the value
+// instead of reading it. hashKey is empty if the field has no hash annotation.
+func fieldHash(annotations map[string]string, field string, value []byte) (hashKey string, hash []byte, err error) {
+	algorithm := strings.TrimSpace(annotations[AnnotationHashPrefix+field])
+	if algorithm == "" {
+		return "", nil, nil
+	}
+	hash, err = generator.GenerateHash(algorithm, value)
+	if err != nil {
+		return "", nil, err
+	}
+	return generator.HashField(field, algorithm), hash, nil
+}
+
+// renderMissingHashes writes the hashes of existing fields whose companion key is missing, e.g.
+// because the hash annotation was added after the field was generated. Hashes are salted, so
+// existing hashes are only replaced when the field is generated or rotated. Fields whose hash
+// cannot be rendered are recorded as failed without affecting the other fields.
+func (r *SecretReconciler) renderMissingHashes(secret *corev1.Secret, fields []string, result *secretUpdateResult, logger logr.Logger) {
+	for _, field := range fields {
+		value, ok := secret.Data[field]
+		if !ok || result.failed[field] != "" {
+			continue
+		}
+		algorithm := strings.TrimSpace(secret.Annotations[AnnotationHashPrefix+field])
+		if algorithm == "" {
+			continue
+		}
+		if _, ok := secret.Data[generator.HashField(field, algorithm)]; ok {
+			continue
+		}
+
+		hashKey, hash, err := fieldHash(secret.Annotations, field, value)
+		if err != nil {
+			result.fail(field, r.reportHashFailure(secret, field, err, logger))
+			continue
+		}
+		secret.Data[hashKey] = hash
+		result.keys = append(result.keys, hashKey)
+		result.changed = true
+	}
+}
+
+// reportHashFailure logs and creates a Warning event for a field whose value cannot be hashed
+// and returns the message for the generation-error annotation
+func (r *SecretReconciler) reportHashFailure(secret *corev1.Secret, field string, err error, logger logr.Logger) string {
+	errMsg := fmt.Sprintf("Failed to hash field %q: %v", field, err)
+	logger.Error(err, "Failed to hash field", "field", field)
+	r.EventRecorder.Event(secret, corev1.EventTypeWarning, EventReasonGenerationFailed, errMsg)
+	return errMsg
+}
+
+// isHashKey returns true if key is the companion key holding the hash of a field
+func isHashKey(secret *corev1.Secret, field, key string) bool {
+	algorithm := strings.TrimSpace(secret.Annotations[AnnotationHashPrefix+field])
+	return algorithm != "" && key == generator.HashField(field, algorithm)
+}
@@ -0,0 +1,265 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+)
+
+var bcryptHashPattern = regexp.MustCompile(`^\$2a\$10\$[./A-Za-z0-9]{53}$`)
+
+func newHashSecret(algorithm string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-hash",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate:            "password",
+				AnnotationHashPrefix + "password": algorithm,
+			},
+		},
+	}
+}
+
+func TestReconcileRendersHashes(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	secret := newHashSecret(generator.HashBcrypt)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+	}
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var updated corev1.Secret
+	if err := fakeClient.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if hash := updated.Data["password-bcrypt"]; !bcryptHashPattern.Match(hash) {
+		t.Errorf("expected a bcrypt hash, got %q", hash)
+	}
+	if keys := updated.Annotations[AnnotationGeneratedKeys]; keys != "password,password-bcrypt" {
+		t.Errorf("unexpected generated keys %q", keys)
+	}
+
+	// Rendered hashes are left untouched by further reconciliations
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var unchanged corev1.Secret
+	if err := fakeClient.Get(ctx, req.NamespacedName, &unchanged); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if unchanged.ResourceVersion != updated.ResourceVersion {
+		t.Error("expected the Secret not to be updated again")
+	}
+
+	// Rotating the field hashes the new value
+	unchanged.Annotations[AnnotationRotate] = "1h"
+	unchanged.Annotations[AnnotationGeneratedAt] = "2000-01-01T00:00:00Z"
+	if err := fakeClient.Update(ctx, &unchanged); err != nil {
+		t.Fatalf("failed to update secret: %v", err)
+	}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var rotated corev1.Secret
+	if err := fakeClient.Get(ctx, req.NamespacedName, &rotated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if string(rotated.Data["password"]) == string(updated.Data["password"]) {
+		t.Fatal("expected the password to be rotated")
+	}
+	if hash := rotated.Data["password-bcrypt"]; !bcryptHashPattern.Match(hash) || string(hash) == string(updated.Data["password-bcrypt"]) {
+		t.Errorf("expected the rotated password to be hashed again, got %q", hash)
+	}
+}
+
+func TestReconcileHashesExistingField(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	secret := newHashSecret(generator.HashBcrypt)
+	secret.Annotations[AnnotationGeneratedAt] = "2000-01-01T00:00:00Z"
+	secret.Data = map[string][]byte{"password": []byte("existing")}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+	}
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var updated corev1.Secret
+	if err := fakeClient.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if string(updated.Data["password"]) != "existing" {
+		t.Errorf("expected the existing value to be kept, got %q", updated.Data["password"])
+	}
+	if hash := updated.Data["password-bcrypt"]; !bcryptHashPattern.Match(hash) {
+		t.Errorf("expected a bcrypt hash, got %q", hash)
+	}
+	if updated.Annotations[AnnotationGeneratedAt] != "2000-01-01T00:00:00Z" {
+		t.Error("expected generated-at to be kept")
+	}
+}
+
+func TestReconcileRemovesHashWithAnnotation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	secret := newHashSecret(generator.HashBcrypt)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	cfg := config.NewDefaultConfig()
+	cfg.Cleanup.DeleteRemovedFields = true
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        cfg,
+		EventRecorder: record.NewFakeRecorder(10),
+	}
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var updated corev1.Secret
+	if err := fakeClient.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	delete(updated.Annotations, AnnotationHashPrefix+"password")
+	if err := fakeClient.Update(ctx, &updated); err != nil {
+		t.Fatalf("failed to update secret: %v", err)
+	}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var cleaned corev1.Secret
+	if err := fakeClient.Get(ctx, req.NamespacedName, &cleaned); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if _, ok := cleaned.Data["password-bcrypt"]; ok {
+		t.Error("expected the hash to be deleted with its annotation")
+	}
+	if string(cleaned.Data["password"]) != string(updated.Data["password"]) {
+		t.Error("expected the password to be kept")
+	}
+	if keys := cleaned.Annotations[AnnotationGeneratedKeys]; keys != "password" {
+		t.Errorf("unexpected generated keys %q", keys)
+	}
+}
+
+func TestReconcileHashFailuresArePerField(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-hash",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate:                   "password,long-password,md5-password,api-key",
+				AnnotationHashPrefix + "password":        generator.HashBcrypt,
+				AnnotationHashPrefix + "long-password":   generator.HashBcrypt,
+				AnnotationLengthPrefix + "long-password": "100",
+				AnnotationHashPrefix + "md5-password":    "md5",
+				AnnotationHashPrefix + "api-key":         "md5",
+			},
+		},
+		// An existing value whose hash cannot be rendered must not block the other fields
+		Data: map[string][]byte{"api-key": []byte("existing")},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	fakeRecorder := record.NewFakeRecorder(10)
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: fakeRecorder,
+	}
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+
+	for range 2 {
+		if _, err := reconciler.Reconcile(ctx, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	var updated corev1.Secret
+	if err := fakeClient.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if hash := updated.Data["password-bcrypt"]; !bcryptHashPattern.Match(hash) {
+		t.Errorf("expected a bcrypt hash of password, got %q", hash)
+	}
+	for _, key := range []string{"long-password", "long-password-bcrypt", "md5-password", "md5-password-md5", "api-key-md5"} {
+		if _, ok := updated.Data[key]; ok {
+			t.Errorf("expected %s not to be written", key)
+		}
+	}
+	if string(updated.Data["api-key"]) != "existing" {
+		t.Errorf("expected the existing api-key to be kept, got %q", updated.Data["api-key"])
+	}
+	generationError := updated.Annotations[AnnotationGenerationError]
+	for _, expected := range []string{`"long-password"`, `"md5-password"`, `"api-key"`, "unknown hash algorithm: md5"} {
+		if !strings.Contains(generationError, expected) {
+			t.Errorf("expected %s in the generation error, got %q", expected, generationError)
+		}
+	}
+	select {
+	case event := <-fakeRecorder.Events:
+		if !strings.Contains(event, EventReasonGenerationFailed) {
+			t.Errorf("unexpected event %q", event)
+		}
+	default:
+		t.Error("expected a GenerationFailed event")
+	}
+}
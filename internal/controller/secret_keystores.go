@@ -0,0 +1,90 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/pem"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+)
+
+// renderKeystores writes a PKCS#12 keystore and truststore alongside each certificate field of a
+// Secret with the tls.keystore-password-field annotation, for workloads (e.g. Java) that cannot
+// consume PEM. Keystores are rendered when they are missing and whenever the certificate or the
+// password was written in this reconciliation. It returns the data keys written.
+func (r *SecretReconciler) renderKeystores(secret *corev1.Secret, fields, written []string) ([]string, error) {
+	passwordField := strings.TrimSpace(secret.Annotations[AnnotationTLSKeystorePasswordField])
+	if passwordField == "" {
+		return nil, nil
+	}
+
+	var keys []string
+	for _, field := range fields {
+		if r.getFieldType(secret.Annotations, field) != config.TypeTLS {
+			continue
+		}
+		keystoreKey, truststoreKey := generator.KeystoreFields(field)
+		_, hasKeystore := secret.Data[keystoreKey]
+		_, hasTruststore := secret.Data[truststoreKey]
+		if hasKeystore && hasTruststore && !slices.Contains(written, field) && !slices.Contains(written, passwordField) {
+			continue
+		}
+
+		password := string(secret.Data[passwordField])
+		if password == "" {
+			return nil, fmt.Errorf("keystore password field %q is missing or empty", passwordField)
+		}
+		certPEM := secret.Data[field]
+		keystore, err := generator.EncodeKeystore(certPEM, secret.Data[generator.CertificateKeyField(field)], secret.Name, password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render keystore for field %q: %w", field, err)
+		}
+		truststore, err := generator.EncodeTruststore(trustAnchorsPEM(certPEM), secret.Name, password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render truststore for field %q: %w", field, err)
+		}
+		secret.Data[keystoreKey] = keystore
+		secret.Data[truststoreKey] = truststore
+		keys = append(keys, keystoreKey, truststoreKey)
+	}
+	return keys, nil
+}
+
+// trustAnchorsPEM returns the certificates that verify a PEM-encoded certificate chain: the
+// issuer chain of certificates signed by a CA, or the certificate itself if it is self-signed
+func trustAnchorsPEM(chainPEM []byte) []byte {
+	leaf, rest := pem.Decode(chainPEM)
+	if leaf == nil || len(strings.TrimSpace(string(rest))) == 0 {
+		return chainPEM
+	}
+	return rest
+}
+
+// isKeystoreKey returns true if key is a keystore or truststore rendered for a certificate field
+func isKeystoreKey(secret *corev1.Secret, field, key string) bool {
+	if secret.Annotations[AnnotationTLSKeystorePasswordField] == "" {
+		return false
+	}
+	keystoreKey, truststoreKey := generator.KeystoreFields(field)
+	return key == keystoreKey || key == truststoreKey
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+)
+
+func newKeystoreSecret(fields string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-keystore",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate:             fields,
+				AnnotationTypePrefix + "tls.crt":   config.TypeTLS,
+				AnnotationTLSKeystorePasswordField: "keystore-password",
+			},
+		},
+		Type: corev1.SecretTypeTLS,
+	}
+}
+
+func TestReconcileRendersKeystores(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	secret := newKeystoreSecret("tls.crt,keystore-password")
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+	}
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var updated corev1.Secret
+	if err := fakeClient.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	for _, key := range []string{"keystore.p12", "truststore.p12"} {
+		if len(updated.Data[key]) == 0 {
+			t.Errorf("expected %s to be rendered", key)
+		}
+	}
+	if keys := updated.Annotations[AnnotationGeneratedKeys]; keys != "keystore-password,keystore.p12,tls.crt,tls.key,truststore.p12" {
+		t.Errorf("unexpected generated keys %q", keys)
+	}
+
+	// Rendered keystores are left untouched by further reconciliations
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var unchanged corev1.Secret
+	if err := fakeClient.Get(ctx, req.NamespacedName, &unchanged); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if unchanged.ResourceVersion != updated.ResourceVersion {
+		t.Error("expected the Secret not to be updated again")
+	}
+
+	// Missing keystores are rendered again without resetting the rotation schedule
+	delete(unchanged.Data, "truststore.p12")
+	if err := fakeClient.Update(ctx, &unchanged); err != nil {
+		t.Fatalf("failed to update secret: %v", err)
+	}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var rerendered corev1.Secret
+	if err := fakeClient.Get(ctx, req.NamespacedName, &rerendered); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if len(rerendered.Data["truststore.p12"]) == 0 {
+		t.Error("expected the truststore to be rendered again")
+	}
+	if rerendered.Annotations[AnnotationGeneratedAt] != updated.Annotations[AnnotationGeneratedAt] {
+		t.Error("expected generated-at to be kept")
+	}
+}
+
+func TestReconcileKeystoreWithoutPassword(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	secret := newKeystoreSecret("tls.crt")
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	fakeRecorder := record.NewFakeRecorder(10)
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: fakeRecorder,
+	}
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var updated corev1.Secret
+	if err := fakeClient.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if len(updated.Data) != 0 {
+		t.Errorf("expected no data to be written, got keys %v", updated.Data)
+	}
+	select {
+	case event := <-fakeRecorder.Events:
+		if !strings.Contains(event, EventReasonGenerationFailed) || !strings.Contains(event, "keystore-password") {
+			t.Errorf("unexpected event %q", event)
+		}
+	default:
+		t.Error("expected a GenerationFailed event")
+	}
+}
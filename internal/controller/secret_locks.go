@@ -0,0 +1,94 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// SecretLocks serializes the reconciles of different controllers on the same Secret, so that
+// e.g. a push replication never reads a Secret while the generator is updating it
+type SecretLocks struct {
+	mu    sync.Mutex
+	locks map[types.NamespacedName]*secretLock
+}
+
+// secretLock is the lock of a single Secret. It is removed once no reconcile holds or waits for it.
+type secretLock struct {
+	ch   chan struct{}
+	refs int
+}
+
+// NewSecretLocks returns an empty SecretLocks
+func NewSecretLocks() *SecretLocks {
+	return &SecretLocks{locks: make(map[types.NamespacedName]*secretLock)}
+}
+
+// Lock blocks until the lock of the Secret is acquired or the context is done.
+// The returned function releases the lock.
+func (l *SecretLocks) Lock(ctx context.Context, key types.NamespacedName) (func(), error) {
+	l.mu.Lock()
+	lock, ok := l.locks[key]
+	if !ok {
+		lock = &secretLock{ch: make(chan struct{}, 1)}
+		l.locks[key] = lock
+	}
+	lock.refs++
+	l.mu.Unlock()
+
+	select {
+	case lock.ch <- struct{}{}:
+		return func() {
+			<-lock.ch
+			l.release(key, lock)
+		}, nil
+	case <-ctx.Done():
+		l.release(key, lock)
+		return nil, ctx.Err()
+	}
+}
+
+// release drops a reference to the lock of a Secret
+func (l *SecretLocks) release(key types.NamespacedName, lock *secretLock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lock.refs--
+	if lock.refs == 0 {
+		delete(l.locks, key)
+	}
+}
+
+// wrap returns a Reconciler that holds the lock of the requested Secret while reconciling.
+// A nil SecretLocks returns the reconciler unchanged.
+func (l *SecretLocks) wrap(reconciler reconcile.Reconciler) reconcile.Reconciler {
+	if l == nil {
+		return reconciler
+	}
+	return reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		unlock, err := l.Lock(ctx, req.NamespacedName)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		defer unlock()
+		return reconciler.Reconcile(ctx, req)
+	})
+}
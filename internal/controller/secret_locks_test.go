@@ -0,0 +1,93 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestSecretLocksSerializeReconciles(t *testing.T) {
+	locks := NewSecretLocks()
+
+	var active, maxActive int32
+	reconciler := locks.wrap(reconcile.Func(func(context.Context, ctrl.Request) (ctrl.Result, error) {
+		current := atomic.AddInt32(&active, 1)
+		for {
+			previous := atomic.LoadInt32(&maxActive)
+			if current <= previous || atomic.CompareAndSwapInt32(&maxActive, previous, current) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&active, -1)
+		return ctrl.Result{}, nil
+	}))
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "db"}}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = reconciler.Reconcile(context.Background(), req)
+		}()
+	}
+	wg.Wait()
+
+	if maxActive != 1 {
+		t.Errorf("expected reconciles of the same Secret to be serialized, got %d in parallel", maxActive)
+	}
+	if len(locks.locks) != 0 {
+		t.Errorf("expected released locks to be removed, got %d", len(locks.locks))
+	}
+}
+
+func TestSecretLocksContextCanceled(t *testing.T) {
+	locks := NewSecretLocks()
+	key := types.NamespacedName{Namespace: "default", Name: "db"}
+
+	unlock, err := locks.Lock(context.Background(), key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Other Secrets are not blocked
+	unlockOther, err := locks.Lock(context.Background(), types.NamespacedName{Namespace: "default", Name: "other"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	unlockOther()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := locks.Lock(ctx, key); err == nil {
+		t.Error("expected error when the context is done before the lock is acquired")
+	}
+
+	unlock()
+	if len(locks.locks) != 0 {
+		t.Errorf("expected released locks to be removed, got %d", len(locks.locks))
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+)
+
+// clientIDFormat returns the client ID format of oauth-client fields, falling back to the
+// configured default
+func (r *SecretReconciler) clientIDFormat(annotations map[string]string) string {
+	if format, ok := annotations[AnnotationOAuthClientIDFormat]; ok && format != "" {
+		return format
+	}
+	return r.Config.OAuthClients.ClientIDFormat
+}
+
+// addOAuthClientID generates the client ID of an oauth-client field if it does not exist yet.
+// The client ID is never rotated, only the client secret in the field itself.
+// It returns false if the client ID could not be generated; the result holds the error then. An
+// unknown client ID format only fails the field, since it persists until the annotation is fixed.
+func (r *SecretReconciler) addOAuthClientID(
+	secret *corev1.Secret,
+	result *fieldGenerationResult,
+	logger logr.Logger,
+) bool {
+	idField := generator.OAuthClientIDField(result.field)
+	if _, exists := secret.Data[idField]; exists {
+		return true
+	}
+
+	format := r.clientIDFormat(secret.Annotations)
+	if !slices.Contains(config.ClientIDFormats, format) {
+		result.value = nil
+		result.err = fmt.Errorf("invalid client ID format for field %s: %q, must be one of %v",
+			result.field, format, config.ClientIDFormats)
+		result.errMsg = fmt.Sprintf("Invalid client ID format for field %q: %q, must be one of %v",
+			result.field, format, config.ClientIDFormats)
+		result.invalid = true
+		logger.Error(result.err, "Invalid client ID format", "field", result.field)
+		r.EventRecorder.Event(secret, corev1.EventTypeWarning, EventReasonGenerationFailed, result.errMsg)
+		return false
+	}
+
+	id, err := generator.GenerateClientID(r.Generator, format)
+	if err != nil {
+		result.value = nil
+		result.err = fmt.Errorf("failed to generate client ID for field %s: %w", result.field, err)
+		result.errMsg = fmt.Sprintf("Failed to generate client ID for field %q: %v", result.field, err)
+		result.skipRest = true
+		logger.Error(err, "Failed to generate client ID", "field", result.field)
+		r.EventRecorder.Event(secret, corev1.EventTypeWarning, EventReasonGenerationFailed, result.errMsg)
+		return false
+	}
+
+	result.extraData = map[string][]byte{idField: []byte(id)}
+	logger.Info("Generated client ID for field", "field", result.field, "clientIDField", idField)
+	return true
+}
+
+// isOAuthClientIDKey returns true if key holds the client ID of an oauth-client field
+func (r *SecretReconciler) isOAuthClientIDKey(secret *corev1.Secret, field, key string) bool {
+	return key == generator.OAuthClientIDField(field) && r.getFieldType(secret.Annotations, field) == config.TypeOAuthClient
+}
+
+// completeOAuthClient generates a missing client ID of an existing oauth-client field. Only the
+// client ID is written: the client secret keeps its generation time, revision, origin and hash.
+func (r *SecretReconciler) completeOAuthClient(secret *corev1.Secret, field string, logger logr.Logger) fieldGenerationResult {
+	result := fieldGenerationResult{field: field}
+	if _, exists := secret.Data[generator.OAuthClientIDField(field)]; exists {
+		return result
+	}
+	if r.addOAuthClientID(secret, &result, logger) {
+		result.completed = true
+	}
+	return result
+}
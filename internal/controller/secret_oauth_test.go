@@ -0,0 +1,214 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	isoannotations "github.com/guided-traffic/internal-secrets-operator/pkg/annotations"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+)
+
+func newOAuthSecret(data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-oauth",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate:                 "client_secret",
+				AnnotationTypePrefix + "client_secret": config.TypeOAuthClient,
+			},
+		},
+		Data: data,
+	}
+}
+
+func reconcileOAuthSecret(t *testing.T, secret *corev1.Secret, now time.Time) *corev1.Secret {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+		Clock:         &MockClock{currentTime: now},
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	return &updated
+}
+
+func TestReconcileGeneratesOAuthClient(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	updated := reconcileOAuthSecret(t, newOAuthSecret(nil), now)
+
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	if !uuid.Match(updated.Data["client_id"]) {
+		t.Errorf("expected client_id to be a UUID, got %q", updated.Data["client_id"])
+	}
+	if len(updated.Data["client_secret"]) != config.DefaultLength {
+		t.Errorf("expected client_secret of length %d, got %q", config.DefaultLength, updated.Data["client_secret"])
+	}
+}
+
+func TestReconcileRotatesOAuthClientSecret(t *testing.T) {
+	generatedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	secret := newOAuthSecret(map[string][]byte{
+		"client_id":     []byte("existing-id"),
+		"client_secret": []byte("existing-secret"),
+	})
+	secret.Annotations[AnnotationGeneratedAt] = generatedAt.Format(time.RFC3339)
+	secret.Annotations[AnnotationRotatePrefix+"client_secret"] = "24h"
+
+	updated := reconcileOAuthSecret(t, secret, generatedAt.Add(25*time.Hour))
+
+	if string(updated.Data["client_secret"]) == "existing-secret" {
+		t.Error("expected client_secret to be rotated")
+	}
+	if string(updated.Data["client_id"]) != "existing-id" {
+		t.Errorf("expected client_id to stay stable, got %q", updated.Data["client_id"])
+	}
+}
+
+func TestReconcileKeepsOAuthClientID(t *testing.T) {
+	for _, deleteRemovedFields := range []bool{false, true} {
+		scheme := runtime.NewScheme()
+		_ = clientgoscheme.AddToScheme(scheme)
+
+		secret := newOAuthSecret(nil)
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+		cfg := config.NewDefaultConfig()
+		cfg.Cleanup.DeleteRemovedFields = deleteRemovedFields
+		reconciler := &SecretReconciler{
+			Client:        fakeClient,
+			Scheme:        scheme,
+			Generator:     generator.NewSecretGenerator(),
+			Config:        cfg,
+			EventRecorder: record.NewFakeRecorder(10),
+		}
+		ctx := context.Background()
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+
+		var first corev1.Secret
+		for i := range 2 {
+			if _, err := reconciler.Reconcile(ctx, req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var updated corev1.Secret
+			if err := fakeClient.Get(ctx, req.NamespacedName, &updated); err != nil {
+				t.Fatalf("failed to get secret: %v", err)
+			}
+			if i == 0 {
+				first = updated
+				continue
+			}
+			if string(updated.Data["client_id"]) != string(first.Data["client_id"]) {
+				t.Errorf("deleteRemovedFields=%v: expected client_id to stay stable, got %q and %q",
+					deleteRemovedFields, first.Data["client_id"], updated.Data["client_id"])
+			}
+			if keys := updated.Annotations[AnnotationGeneratedKeys]; keys != "client_id,client_secret" {
+				t.Errorf("deleteRemovedFields=%v: unexpected generated keys %q", deleteRemovedFields, keys)
+			}
+			if updated.ResourceVersion != first.ResourceVersion {
+				t.Errorf("deleteRemovedFields=%v: expected the Secret not to be updated again", deleteRemovedFields)
+			}
+		}
+	}
+}
+
+func TestReconcileCompletesOAuthClientID(t *testing.T) {
+	const generatedAt = "2024-12-01T00:00:00Z"
+	secret := newOAuthSecret(map[string][]byte{
+		"client_secret":        []byte("existing-secret"),
+		"client_secret-bcrypt": []byte("existing-hash"),
+	})
+	secret.Annotations[AnnotationOAuthClientIDFormat] = config.ClientIDFormatHex
+	secret.Annotations[AnnotationHashPrefix+"client_secret"] = generator.HashBcrypt
+	secret.Annotations[AnnotationGeneratedAt] = generatedAt
+	secret.Annotations[AnnotationRevisionPrefix+"client_secret"] = "3"
+	secret.Annotations[AnnotationOriginPrefix+"client_secret"] = isoannotations.OriginExternal
+
+	updated := reconcileOAuthSecret(t, secret, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	if string(updated.Data["client_secret"]) != "existing-secret" {
+		t.Errorf("expected client_secret to be kept, got %q", updated.Data["client_secret"])
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{32}$`).Match(updated.Data["client_id"]) {
+		t.Errorf("expected hex client_id, got %q", updated.Data["client_id"])
+	}
+	if got := string(updated.Data["client_secret-bcrypt"]); got != "existing-hash" {
+		t.Errorf("expected the hash of client_secret to be kept, got %q", got)
+	}
+	if got := updated.Annotations[AnnotationGeneratedAt]; got != generatedAt {
+		t.Errorf("expected generated-at to be kept, got %q", got)
+	}
+	if got := updated.Annotations[AnnotationRevisionPrefix+"client_secret"]; got != "3" {
+		t.Errorf("expected the revision of client_secret to be kept, got %q", got)
+	}
+	if got := updated.Annotations[AnnotationOriginPrefix+"client_secret"]; got != isoannotations.OriginExternal {
+		t.Errorf("expected the origin of client_secret to be kept, got %q", got)
+	}
+	if got := updated.Annotations[AnnotationGeneratedKeys]; got != "client_id" {
+		t.Errorf("expected only client_id to be recorded as generated, got %q", got)
+	}
+}
+
+func TestReconcileInvalidOAuthClientIDFormatFailsOnlyTheField(t *testing.T) {
+	secret := newOAuthSecret(nil)
+	secret.Annotations[AnnotationAutogenerate] = "client_secret,password"
+	secret.Annotations[AnnotationOAuthClientIDFormat] = "base32"
+
+	updated := reconcileOAuthSecret(t, secret, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	if len(updated.Data["password"]) == 0 {
+		t.Error("expected the other field to be generated")
+	}
+	for _, key := range []string{"client_secret", "client_id"} {
+		if _, ok := updated.Data[key]; ok {
+			t.Errorf("expected %s not to be generated", key)
+		}
+	}
+	if generationError := updated.Annotations[AnnotationGenerationError]; !strings.Contains(generationError, `"client_secret"`) {
+		t.Errorf("expected generation-error to name client_secret, got %q", generationError)
+	}
+}
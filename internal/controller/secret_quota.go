@@ -0,0 +1,88 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	isoannotations "github.com/guided-traffic/internal-secrets-operator/pkg/annotations"
+)
+
+// EventReasonQuotaExceeded is the event reason for Secrets rejected by a namespace quota
+const EventReasonQuotaExceeded = "QuotaExceeded"
+
+// quotaRetryInterval is how often Secrets rejected by a quota are checked again
+const quotaRetryInterval = 5 * time.Minute
+
+// hasMissingFields returns true if any of the fields has no value yet
+func hasMissingFields(secret *corev1.Secret, fields []string) bool {
+	for _, field := range fields {
+		if _, ok := secret.Data[field]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// checkQuota returns a message if generating the fields of the Secret would exceed the
+// per-namespace quota. Only Secrets with missing fields are checked, so Secrets that are
+// already generated keep being rotated when the quota is lowered.
+func (r *SecretReconciler) checkQuota(ctx context.Context, secret *corev1.Secret, fields []string) (string, error) {
+	quota := r.Config.Quota
+	if quota.MaxSecretsPerNamespace == 0 && quota.MaxFieldsPerNamespace == 0 {
+		return "", nil
+	}
+	if !hasMissingFields(secret, fields) {
+		return "", nil
+	}
+
+	var list corev1.SecretList
+	if err := r.List(ctx, &list, client.InNamespace(secret.Namespace)); err != nil {
+		return "", fmt.Errorf("failed to list Secrets for quota check: %w", err)
+	}
+
+	// Count the Secrets with generated values, including this one
+	secrets, generatedFields := 1, len(fields)
+	for i := range list.Items {
+		other := &list.Items[i]
+		if other.Name == secret.Name {
+			continue
+		}
+		if _, ok := other.Annotations[AnnotationGeneratedAt]; !ok {
+			continue
+		}
+		secrets++
+		generatedFields += len(isoannotations.Fields(other.Annotations))
+	}
+
+	if quota.MaxSecretsPerNamespace > 0 && secrets > quota.MaxSecretsPerNamespace {
+		if _, generated := secret.Annotations[AnnotationGeneratedAt]; !generated {
+			return fmt.Sprintf("Namespace %s already has %d generated Secrets, the maximum is %d",
+				secret.Namespace, secrets-1, quota.MaxSecretsPerNamespace), nil
+		}
+	}
+	if quota.MaxFieldsPerNamespace > 0 && generatedFields > quota.MaxFieldsPerNamespace {
+		return fmt.Sprintf("Generating %d fields would exceed the maximum of %d generated fields in namespace %s",
+			len(fields), quota.MaxFieldsPerNamespace, secret.Namespace), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+)
+
+func TestReconcileEnforcesQuota(t *testing.T) {
+	// generatedSecret is a Secret in the namespace that was already generated
+	generatedSecret := func(name, fields string) *corev1.Secret {
+		data := make(map[string][]byte)
+		for _, field := range strings.Split(fields, ",") {
+			data[field] = []byte("value")
+		}
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Annotations: map[string]string{
+					AnnotationAutogenerate: fields,
+					AnnotationGeneratedAt:  "2025-01-01T00:00:00Z",
+				},
+			},
+			Data: data,
+		}
+	}
+
+	tests := []struct {
+		name          string
+		quota         config.QuotaConfig
+		existing      []client.Object
+		secret        *corev1.Secret
+		expectBlocked bool
+	}{
+		{
+			name:     "no quota",
+			existing: []client.Object{generatedSecret("a", "password"), generatedSecret("b", "password")},
+			secret: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "default",
+				Annotations: map[string]string{AnnotationAutogenerate: "password"}}},
+		},
+		{
+			name:     "within secrets quota",
+			quota:    config.QuotaConfig{MaxSecretsPerNamespace: 2},
+			existing: []client.Object{generatedSecret("a", "password")},
+			secret: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "default",
+				Annotations: map[string]string{AnnotationAutogenerate: "password"}}},
+		},
+		{
+			name:     "secrets quota exceeded",
+			quota:    config.QuotaConfig{MaxSecretsPerNamespace: 2},
+			existing: []client.Object{generatedSecret("a", "password"), generatedSecret("b", "password")},
+			secret: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "default",
+				Annotations: map[string]string{AnnotationAutogenerate: "password"}}},
+			expectBlocked: true,
+		},
+		{
+			name:  "secrets in other namespaces are not counted",
+			quota: config.QuotaConfig{MaxSecretsPerNamespace: 1},
+			existing: []client.Object{&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "other",
+				Annotations: map[string]string{AnnotationAutogenerate: "password", AnnotationGeneratedAt: "2025-01-01T00:00:00Z"}}}},
+			secret: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "default",
+				Annotations: map[string]string{AnnotationAutogenerate: "password"}}},
+		},
+		{
+			name:     "fields quota exceeded",
+			quota:    config.QuotaConfig{MaxFieldsPerNamespace: 4},
+			existing: []client.Object{generatedSecret("a", "username,password")},
+			secret: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "default",
+				Annotations: map[string]string{AnnotationAutogenerate: "a,b,c"}}},
+			expectBlocked: true,
+		},
+		{
+			name:     "new field on generated secret exceeds fields quota",
+			quota:    config.QuotaConfig{MaxSecretsPerNamespace: 2, MaxFieldsPerNamespace: 3},
+			existing: []client.Object{generatedSecret("a", "username,password")},
+			secret: func() *corev1.Secret {
+				secret := generatedSecret("new", "password")
+				secret.Annotations[AnnotationAutogenerate] = "password,token"
+				return secret
+			}(),
+			expectBlocked: true,
+		},
+		{
+			name:     "generated secret is not blocked by lowered quota",
+			quota:    config.QuotaConfig{MaxSecretsPerNamespace: 1, MaxFieldsPerNamespace: 1},
+			existing: []client.Object{generatedSecret("a", "username,password")},
+			secret:   generatedSecret("new", "password"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = clientgoscheme.AddToScheme(scheme)
+
+			cfg := config.NewDefaultConfig()
+			cfg.Quota = tt.quota
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(append(tt.existing, tt.secret)...).
+				Build()
+			fakeRecorder := record.NewFakeRecorder(10)
+			reconciler := &SecretReconciler{
+				Client:        fakeClient,
+				Scheme:        scheme,
+				Generator:     generator.NewSecretGenerator(),
+				Config:        cfg,
+				EventRecorder: fakeRecorder,
+			}
+
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: tt.secret.Name, Namespace: tt.secret.Namespace}}
+			result, err := reconciler.Reconcile(context.Background(), req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var updated corev1.Secret
+			if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+				t.Fatalf("failed to get Secret: %v", err)
+			}
+			blocked := false
+			for _, field := range strings.Split(updated.Annotations[AnnotationAutogenerate], ",") {
+				if _, ok := updated.Data[field]; !ok {
+					blocked = true
+				}
+			}
+			if blocked != tt.expectBlocked {
+				t.Errorf("expected blocked=%v, got data %v", tt.expectBlocked, updated.Data)
+			}
+
+			quotaEvent := false
+			for len(fakeRecorder.Events) > 0 {
+				if strings.Contains(<-fakeRecorder.Events, EventReasonQuotaExceeded) {
+					quotaEvent = true
+				}
+			}
+			if quotaEvent != tt.expectBlocked {
+				t.Errorf("expected QuotaExceeded event=%v, got %v", tt.expectBlocked, quotaEvent)
+			}
+			if tt.expectBlocked && result.RequeueAfter != quotaRetryInterval {
+				t.Errorf("expected requeue after %s, got %s", quotaRetryInterval, result.RequeueAfter)
+			}
+		})
+	}
+}
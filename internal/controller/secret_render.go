@@ -0,0 +1,224 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+
+	isoannotations "github.com/guided-traffic/internal-secrets-operator/pkg/annotations"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+const (
+	// EnvFileKey is the data key the render-env annotation renders the Secret data into
+	EnvFileKey = ".env"
+	// JSONConfigKey is the data key the render-json annotation renders the Secret data into
+	JSONConfigKey = "config.json"
+	// YAMLConfigKey is the data key the render-yaml annotation renders the Secret data into
+	YAMLConfigKey = "config.yaml"
+)
+
+// renderEntry is a data value rendered into an aggregate key under the given name
+type renderEntry struct {
+	name  string
+	value string
+}
+
+// aggregateKey is a data key that holds other data keys of the Secret in a single file
+type aggregateKey struct {
+	annotation string
+	key        string
+	encode     func(entries []renderEntry) ([]byte, error)
+}
+
+// aggregateKeys are the aggregate keys in the order they are rendered
+var aggregateKeys = []aggregateKey{
+	{annotation: AnnotationRenderEnv, key: EnvFileKey, encode: encodeEnvFile},
+	{annotation: AnnotationRenderJSON, key: JSONConfigKey, encode: encodeJSONConfig},
+	{annotation: AnnotationRenderYAML, key: YAMLConfigKey, encode: encodeYAMLConfig},
+}
+
+// plainEnvValue matches values that are written to the .env file without quotes
+var plainEnvValue = regexp.MustCompile(`^[A-Za-z0-9_./:@+=,-]*$`)
+
+// renderAggregateKeys renders the data keys selected by the render-env, render-json and
+// render-yaml annotations into the .env, config.json and config.yaml keys. Keys are renamed by
+// the render-mapping annotation; values that are not UTF-8 (e.g. keystores) are skipped.
+// It returns true if any aggregate key changed.
+func renderAggregateKeys(secret *corev1.Secret) (bool, error) {
+	changed := false
+	for _, aggregate := range aggregateKeys {
+		value, ok := aggregate.enabled(secret.Annotations)
+		if !ok {
+			continue
+		}
+
+		data, err := aggregate.encode(selectRenderEntries(secret, value))
+		if err != nil {
+			return false, fmt.Errorf("failed to render %s: %w", aggregate.key, err)
+		}
+		if current, ok := secret.Data[aggregate.key]; ok && bytes.Equal(current, data) {
+			continue
+		}
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+		secret.Data[aggregate.key] = data
+		changed = true
+	}
+	return changed, nil
+}
+
+// enabled returns the value of the render annotation of the aggregate key, and false if the key
+// is not rendered
+func (a aggregateKey) enabled(annotations map[string]string) (string, bool) {
+	value := strings.TrimSpace(annotations[a.annotation])
+	return value, value != "" && !strings.EqualFold(value, "false")
+}
+
+// hasAggregateKeys returns true if all aggregate keys rendered for the Secret exist
+func hasAggregateKeys(secret *corev1.Secret) bool {
+	for _, aggregate := range aggregateKeys {
+		if _, ok := aggregate.enabled(secret.Annotations); !ok {
+			continue
+		}
+		if _, ok := secret.Data[aggregate.key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// selectRenderEntries returns the entries for a render annotation value: all keys in
+// alphabetical order for "true", otherwise the listed keys in the given order. Missing keys and
+// aggregate keys are skipped.
+func selectRenderEntries(secret *corev1.Secret, value string) []renderEntry {
+	keys := slices.Sorted(maps.Keys(secret.Data))
+	if !strings.EqualFold(value, "true") {
+		keys = isoannotations.ParseFields(value)
+	}
+	mapping := isoannotations.ParseRenderMapping(secret.Annotations)
+
+	var entries []renderEntry
+	for _, key := range keys {
+		data, ok := secret.Data[key]
+		if !ok || isAggregateKey(key) || !utf8.Valid(data) {
+			continue
+		}
+		name := key
+		if mapped, ok := mapping[key]; ok {
+			name = mapped
+		}
+		entries = append(entries, renderEntry{name: name, value: string(data)})
+	}
+	return entries
+}
+
+// isAggregateKey returns true if key is rendered from the other data keys
+func isAggregateKey(key string) bool {
+	return slices.ContainsFunc(aggregateKeys, func(aggregate aggregateKey) bool { return aggregate.key == key })
+}
+
+// hasRenderAnnotation returns true if any aggregate key is rendered for the annotations
+func hasRenderAnnotation(annotations map[string]string) bool {
+	return slices.ContainsFunc(aggregateKeys, func(aggregate aggregateKey) bool {
+		_, ok := annotations[aggregate.annotation]
+		return ok
+	})
+}
+
+// encodeEnvFile renders the entries as KEY=value lines. Names that are not valid environment
+// variable names are skipped. Values with other than plain characters are double-quoted, with
+// backslashes, double quotes and line breaks escaped.
+func encodeEnvFile(entries []renderEntry) ([]byte, error) {
+	var out bytes.Buffer
+	for _, entry := range entries {
+		if len(validation.IsEnvVarName(entry.name)) > 0 {
+			continue
+		}
+		value := entry.value
+		if !plainEnvValue.MatchString(value) {
+			value = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`).Replace(value) + `"`
+		}
+		fmt.Fprintf(&out, "%s=%s\n", entry.name, value)
+	}
+	return out.Bytes(), nil
+}
+
+// encodeJSONConfig renders the entries as an indented JSON object with sorted keys
+func encodeJSONConfig(entries []renderEntry) ([]byte, error) {
+	data, err := json.MarshalIndent(renderEntryMap(entries), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// encodeYAMLConfig renders the entries as a YAML mapping with sorted keys
+func encodeYAMLConfig(entries []renderEntry) ([]byte, error) {
+	return yaml.Marshal(renderEntryMap(entries))
+}
+
+// renderEntryMap returns the entries as a map; of entries with the same name, the last one wins
+func renderEntryMap(entries []renderEntry) map[string]string {
+	out := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		out[entry.name] = entry.value
+	}
+	return out
+}
+
+// syncAggregateKeys updates the aggregate keys of a Secret that has no fields to generate.
+// Replication targets are rendered by the replicator, which records the checksum of the rendered
+// data for drift detection; rendering them here would make them look modified.
+func (r *SecretReconciler) syncAggregateKeys(ctx context.Context, secret *corev1.Secret) error {
+	if secret.Annotations[replicator.AnnotationReplicatedChecksum] != "" {
+		return nil
+	}
+	changed, err := renderAggregateKeys(secret)
+	if err != nil || !changed {
+		return err
+	}
+	recordDataChecksum(secret)
+	if err := r.Update(ctx, secret); err != nil {
+		return fmt.Errorf("failed to update rendered keys: %w", err)
+	}
+	return nil
+}
+
+// renderReplicatedAggregateKeys renders the aggregate keys of a replication target after its data
+// was replicated and records the checksums again, so that the rendered keys are not taken for drift
+func renderReplicatedAggregateKeys(target *corev1.Secret) error {
+	changed, err := renderAggregateKeys(target)
+	if err != nil || !changed {
+		return err
+	}
+	replicator.RecordChecksums(target)
+	return nil
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+func TestRenderAggregateKeys(t *testing.T) {
+	data := map[string][]byte{
+		"DB_USER":      []byte("admin"),
+		"DB_PASSWORD":  []byte(`p"a$s\s`),
+		"ca.crt":       []byte("line1\nline2"),
+		"1INVALID":     []byte("skipped"),
+		"keystore.p12": {0xff, 0xfe},
+	}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		data        map[string][]byte
+		expected    map[string]string
+		changed     bool
+	}{
+		{
+			name:        "disabled",
+			annotations: map[string]string{AnnotationRenderEnv: "false"},
+			data:        data,
+			expected:    map[string]string{},
+		},
+		{
+			name:        "all keys",
+			annotations: map[string]string{AnnotationRenderEnv: "true"},
+			data:        data,
+			expected: map[string]string{
+				EnvFileKey: "DB_PASSWORD=\"p\\\"a$s\\\\s\"\nDB_USER=admin\nca.crt=\"line1\\nline2\"\n",
+			},
+			changed: true,
+		},
+		{
+			name:        "selected keys in order",
+			annotations: map[string]string{AnnotationRenderEnv: "DB_USER, DB_PASSWORD, missing"},
+			data:        data,
+			expected:    map[string]string{EnvFileKey: "DB_USER=admin\nDB_PASSWORD=\"p\\\"a$s\\\\s\"\n"},
+			changed:     true,
+		},
+		{
+			name: "json and yaml with mapping",
+			annotations: map[string]string{
+				AnnotationRenderJSON:    "DB_USER,ca.crt",
+				AnnotationRenderYAML:    "DB_USER,ca.crt",
+				AnnotationRenderMapping: "DB_USER=username, ca.crt=caCert",
+			},
+			data: data,
+			expected: map[string]string{
+				JSONConfigKey: "{\n  \"caCert\": \"line1\\nline2\",\n  \"username\": \"admin\"\n}\n",
+				YAMLConfigKey: "caCert: |-\n  line1\n  line2\nusername: admin\n",
+			},
+			changed: true,
+		},
+		{
+			name:        "unchanged",
+			annotations: map[string]string{AnnotationRenderEnv: "true"},
+			data:        map[string][]byte{"A": []byte("b"), EnvFileKey: []byte("A=b\n")},
+			expected:    map[string]string{EnvFileKey: "A=b\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+				Data:       make(map[string][]byte),
+			}
+			for key, value := range tt.data {
+				secret.Data[key] = value
+			}
+
+			changed, err := renderAggregateKeys(secret)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if changed != tt.changed {
+				t.Errorf("expected changed %v, got %v", tt.changed, changed)
+			}
+			for _, key := range []string{EnvFileKey, JSONConfigKey, YAMLConfigKey} {
+				if got := string(secret.Data[key]); got != tt.expected[key] {
+					t.Errorf("expected %s:\n%s\ngot:\n%s", key, tt.expected[key], got)
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileRendersAggregateKeys(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	generated := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "generated",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate: "PASSWORD",
+				AnnotationRenderEnv:    "true",
+			},
+		},
+		Data: map[string][]byte{"USERNAME": []byte("admin")},
+	}
+	// A replication target has no fields to generate
+	target := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "target",
+			Namespace:   "default",
+			Annotations: map[string]string{AnnotationRenderEnv: "TOKEN"},
+		},
+		Data: map[string][]byte{"TOKEN": []byte("abc"), "OTHER": []byte("x")},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(generated, target).Build()
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+	}
+	ctx := context.Background()
+
+	for _, name := range []string{"generated", "target"} {
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: "default"}}
+		if _, err := reconciler.Reconcile(ctx, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: "generated", Namespace: "default"}, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	env := string(updated.Data[EnvFileKey])
+	if !strings.HasPrefix(env, "PASSWORD=") || !strings.HasSuffix(env, "\nUSERNAME=admin\n") {
+		t.Errorf("expected .env with the generated password and the username, got:\n%s", env)
+	}
+	if strings.Contains(updated.Annotations[AnnotationGeneratedKeys], EnvFileKey) {
+		t.Errorf("expected %s not to be recorded as generated key", EnvFileKey)
+	}
+
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: "target", Namespace: "default"}, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if env := string(updated.Data[EnvFileKey]); env != "TOKEN=abc\n" {
+		t.Errorf("expected .env with the selected key, got:\n%s", env)
+	}
+}
+
+func TestReplicationTargetRendersWithoutDrift(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "db",
+			Namespace:   "production",
+			Annotations: map[string]string{replicator.AnnotationReplicatableFromNamespaces: "staging"},
+		},
+		Data: map[string][]byte{"PASSWORD": []byte("secret")},
+	}
+	target := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "db",
+			Namespace: "staging",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicateFrom: "production/db",
+				AnnotationRenderEnv:                "true",
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(source, target).Build()
+	cfg := config.NewDefaultConfig()
+	cfg.Replication.DriftPolicy = config.DriftPolicyWarn
+	recorder := record.NewFakeRecorder(10)
+	replicatorReconciler := &SecretReplicatorReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        cfg,
+		EventRecorder: recorder,
+	}
+	generatorReconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        cfg,
+		EventRecorder: recorder,
+	}
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "db", Namespace: "staging"}}
+
+	// Both controllers see the target on every cycle
+	for range 2 {
+		if _, err := replicatorReconciler.Reconcile(ctx, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := generatorReconciler.Reconcile(ctx, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(ctx, req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if env := string(updated.Data[EnvFileKey]); env != "PASSWORD=secret\n" {
+		t.Errorf("expected .env with the replicated key, got:\n%s", env)
+	}
+	if replicator.HasDrifted(&updated) {
+		t.Error("expected the rendered target not to be drifted")
+	}
+	for len(recorder.Events) > 0 {
+		if event := <-recorder.Events; strings.Contains(event, EventReasonDriftDetected) {
+			t.Errorf("unexpected event %q", event)
+		}
+	}
+}
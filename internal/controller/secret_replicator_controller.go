@@ -19,21 +19,32 @@ package controller
 import (
 	"context"
 	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	isov1alpha1 "github.com/guided-traffic/internal-secrets-operator/api/v1alpha1"
+	isoannotations "github.com/guided-traffic/internal-secrets-operator/pkg/annotations"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/clock"
 	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/policy"
 	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
 )
 
@@ -41,9 +52,11 @@ const (
 	// Event reasons for replication
 	EventReasonReplicationSucceeded = "ReplicationSucceeded"
 	EventReasonReplicationFailed    = "ReplicationFailed"
+	EventReasonReplicationDenied    = "ReplicationDenied"
 	EventReasonPushFailed           = "PushFailed"
 	EventReasonSourceDeleted        = "SourceDeleted"
 	EventReasonConflictingFeatures  = "ConflictingFeatures"
+	EventReasonDriftDetected        = "DriftDetected"
 )
 
 // SecretReplicatorReconciler reconciles Secrets for replication
@@ -52,6 +65,33 @@ type SecretReplicatorReconciler struct {
 	Scheme        *runtime.Scheme
 	Config        *config.Config
 	EventRecorder record.EventRecorder
+	// Clock is used for replication timestamps. If nil, time.Now() is used.
+	Clock Clock
+	// Heartbeat records completed reconciles for the liveness check. If nil, nothing is recorded.
+	Heartbeat *Heartbeat
+	// Shard restricts the controller to the namespaces of one shard. If nil, all namespaces are processed.
+	Shard *Shard
+	// Locks serializes reconciles of the same Secret with the other controllers. If nil, nothing is locked.
+	Locks *SecretLocks
+	// Checkpoint defers the initial list after a leader change to Secrets that changed since the
+	// previous leader. If nil, all Secrets are reconciled on start.
+	Checkpoint *ResyncCheckpoint
+	// FanOutLimiter spreads the target reconciles of a changed source over time. If nil, all
+	// targets are enqueued immediately.
+	FanOutLimiter *FanOutLimiter
+	// Flows records the namespace pairs Secrets are replicated across. If nil, nothing is recorded.
+	Flows *ReplicationFlows
+
+	// pushFailures tracks failed push targets, which are retried with backoff
+	pushFailures pushFailures
+	// replicatedBytes tracks the replicated data per source namespace. If nil, the Secrets are
+	// listed to enforce the replication limit.
+	replicatedBytes *replicatedBytesTracker
+}
+
+// now returns the current time using the Clock if set, otherwise time.Now()
+func (r *SecretReplicatorReconciler) now() time.Time {
+	return clock.Now(r.Clock)
 }
 
 // Reconcile handles Secret replication (both pull and push)
@@ -63,27 +103,50 @@ func (r *SecretReplicatorReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	if err := r.Get(ctx, req.NamespacedName, secret); err != nil {
 		if apierrors.IsNotFound(err) {
 			// Secret deleted - handled by finalizer
+			r.Flows.forget(req.NamespacedName)
+			r.pushFailures.forget(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		log.Error(err, "failed to get Secret")
 		return ctrl.Result{}, err
 	}
+	NormalizeStringData(secret)
 
 	// Handle deletion (for push-based replication cleanup)
 	if replicator.IsBeingDeleted(secret) {
 		return r.handleDeletion(ctx, secret)
 	}
 
+	// Never touch Secrets owned by other components (e.g. Helm release state)
+	if r.Config.IsSecretTypeIgnored(string(secret.Type)) {
+		log.V(1).Info("Skipping Secret of ignored type", "type", secret.Type)
+		r.pushFailures.forget(req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+
 	// Check for conflicting annotations (autogenerate + replicate-from)
 	if replicator.HasConflictingAnnotations(secret) {
 		r.EventRecorder.Event(secret, corev1.EventTypeWarning, EventReasonConflictingFeatures,
 			"Secret has both 'autogenerate' and 'replicate-from' annotations. These features cannot be used together.")
 		log.Info("Skipping Secret with conflicting annotations", "namespace", secret.Namespace, "name", secret.Name)
+		r.pushFailures.forget(req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+
+	// Never replicate Secrets that use features denied by a policy
+	allowed, err := enforcePolicies(ctx, r.Client, r.EventRecorder, r.Config.Policies, secret, policy.ReplicationFeatures)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !allowed {
+		log.Info("Skipping Secret that violates a policy", "namespace", secret.Namespace, "name", secret.Name)
+		r.pushFailures.forget(req.NamespacedName)
 		return ctrl.Result{}, nil
 	}
 
 	// Handle pull-based replication
-	if secret.Annotations[replicator.AnnotationReplicateFrom] != "" {
+	if replicator.IsPullTarget(secret) {
+		r.pushFailures.forget(req.NamespacedName)
 		return r.handlePullReplication(ctx, secret)
 	}
 
@@ -92,86 +155,434 @@ func (r *SecretReplicatorReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return r.handlePushReplication(ctx, secret)
 	}
 
+	// The Secret no longer pushes (e.g. replicate-to was removed), stop retrying its failed targets
+	r.pushFailures.forget(req.NamespacedName)
 	return ctrl.Result{}, nil
 }
 
-// handlePullReplication implements pull-based replication (target pulls from source)
+// pendingGeneratedFields returns the autogenerate fields of a Secret that the generator has not
+// generated yet. Such Secrets are not replicated until the generator has updated them.
+func (r *SecretReplicatorReconciler) pendingGeneratedFields(secret *corev1.Secret) []string {
+	if !r.Config.Features.SecretGenerator || !r.Config.ManagedLabel.Matches(secret.Labels) {
+		return nil
+	}
+	fields := isoannotations.Fields(secret.Annotations)
+	if isoannotations.ValidateFields(fields) != nil {
+		return nil // The generator rejects the annotation
+	}
+	var pending []string
+	for _, field := range fields {
+		if _, ok := secret.Data[field]; !ok {
+			pending = append(pending, field)
+		}
+	}
+	return pending
+}
+
+// handlePullReplication implements pull-based replication (target pulls from one or more sources)
 func (r *SecretReplicatorReconciler) handlePullReplication(ctx context.Context, targetSecret *corev1.Secret) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 
-	// Parse source reference
-	sourceRef := targetSecret.Annotations[replicator.AnnotationReplicateFrom]
-	sourceNamespace, sourceName, err := replicator.ParseSourceReference(sourceRef)
+	// Parse source references (whole Secrets and individual keys)
+	var sourceRefs []string
+	sourceRefsValue := targetSecret.Annotations[replicator.AnnotationReplicateFrom]
+	if sourceRefsValue != "" {
+		var err error
+		sourceRefs, err = replicator.ParseSourceReferences(sourceRefsValue)
+		if err != nil {
+			r.EventRecorder.Event(targetSecret, corev1.EventTypeWarning, EventReasonReplicationFailed,
+				fmt.Sprintf("Invalid source reference: %v", err))
+			log.Error(err, "invalid source reference", "sourceRef", sourceRefsValue)
+			return ctrl.Result{}, nil // Don't requeue - user needs to fix annotation
+		}
+	}
+	keyRefs, err := replicator.ParseKeyReferences(targetSecret.Annotations)
 	if err != nil {
 		r.EventRecorder.Event(targetSecret, corev1.EventTypeWarning, EventReasonReplicationFailed,
 			fmt.Sprintf("Invalid source reference: %v", err))
-		log.Error(err, "invalid source reference", "sourceRef", sourceRef)
+		log.Error(err, "invalid key reference")
 		return ctrl.Result{}, nil // Don't requeue - user needs to fix annotation
 	}
 
+	// Fetch and validate all sources before touching the target, so the target is
+	// only updated if every source is available and allows the replication
+	allSourceRefs := slices.Clone(sourceRefs)
+	for _, ref := range keyRefs {
+		if !slices.Contains(allSourceRefs, ref.Source) {
+			allSourceRefs = append(allSourceRefs, ref.Source)
+		}
+	}
+	slices.Sort(allSourceRefs[len(sourceRefs):])
+
+	sources := make(map[string]*corev1.Secret, len(allSourceRefs))
+	for _, sourceRef := range allSourceRefs {
+		sourceSecret, err := r.getPullSource(ctx, targetSecret, sourceRef)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if sourceSecret == nil {
+			return ctrl.Result{}, nil
+		}
+		if pending := r.pendingGeneratedFields(sourceSecret); len(pending) > 0 {
+			log.Info("Waiting for the generator", "source", sourceRef, "fields", pending)
+			return ctrl.Result{}, nil // The update of the generator triggers a new reconciliation
+		}
+		sources[sourceRef] = sourceSecret
+	}
+
+	if !r.handleDrift(ctx, targetSecret) {
+		return ctrl.Result{}, nil // Don't requeue - the target was modified on purpose
+	}
+
+	usage, err := r.loadReplicationUsage(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	previous := usage.snapshot(targetSecret)
+
+	// Replicate data from whole sources to target (later sources win on key conflicts)
+	sourceSecrets := make([]*corev1.Secret, 0, len(sourceRefs))
+	for _, sourceRef := range sourceRefs {
+		sourceSecrets = append(sourceSecrets, sources[sourceRef])
+	}
+	replicator.ReplicateSecrets(sourceSecrets, targetSecret, r.now())
+
+	// Individually referenced keys take precedence over whole sources
+	if err := replicator.ReplicateKeys(sources, keyRefs, targetSecret, r.now()); err != nil {
+		r.EventRecorder.Event(targetSecret, corev1.EventTypeWarning, EventReasonReplicationFailed,
+			fmt.Sprintf("Cannot replicate keys: %v", err))
+		log.Info("Cannot replicate keys", "error", err)
+		return ctrl.Result{}, nil // Don't requeue - source changes trigger a new reconciliation
+	}
+	r.applyOwnerLabels(targetSecret, allSourceRefs)
+	if err := renderReplicatedAggregateKeys(targetSecret); err != nil {
+		r.EventRecorder.Event(targetSecret, corev1.EventTypeWarning, EventReasonReplicationFailed,
+			fmt.Sprintf("Cannot render replicated data: %v", err))
+		log.Info("Cannot render replicated data", "error", err)
+		return ctrl.Result{}, nil // Don't requeue - source changes trigger a new reconciliation
+	}
+
+	// The target may have a different type than its sources; ensure the keys required by its type exist
+	if err := replicator.ValidateTypeKeys(targetSecret); err != nil {
+		r.EventRecorder.Event(targetSecret, corev1.EventTypeWarning, EventReasonReplicationFailed,
+			fmt.Sprintf("Cannot project replicated data into target type: %v", err))
+		log.Info("Replicated data does not match target type", "type", targetSecret.Type, "error", err)
+		return ctrl.Result{}, nil // Don't requeue - source changes trigger a new reconciliation
+	}
+	if err := validateTLSPair(targetSecret); err != nil {
+		r.EventRecorder.Event(targetSecret, corev1.EventTypeWarning, EventReasonInvalidTLSPair,
+			fmt.Sprintf("Replicated data does not form a valid TLS pair: %v", err))
+		log.Info("Replicated data does not form a valid TLS pair", "error", err)
+		return ctrl.Result{}, nil // Don't requeue - source changes trigger a new reconciliation
+	}
+	if exceeded := usage.reserve(previous, targetSecret); exceeded != "" {
+		r.EventRecorder.Event(targetSecret, corev1.EventTypeWarning, EventReasonReplicationLimitExceeded, exceeded)
+		log.Info("Replication limit exceeded", "reason", exceeded)
+		return ctrl.Result{RequeueAfter: quotaRetryInterval}, nil
+	}
+
+	// Update target Secret
+	if err := r.Update(ctx, targetSecret); err != nil {
+		r.EventRecorder.Event(targetSecret, corev1.EventTypeWarning, EventReasonReplicationFailed,
+			fmt.Sprintf("Failed to update target Secret: %v", err))
+		log.Error(err, "failed to update target Secret")
+		return ctrl.Result{}, err
+	}
+
+	sourceNamespaces := make([]string, 0, len(sources))
+	for _, source := range sources {
+		sourceNamespaces = append(sourceNamespaces, source.Namespace)
+	}
+	r.Flows.record(client.ObjectKeyFromObject(targetSecret), ReplicationModePull, sourceNamespaces, r.now())
+
+	joinedRefs := strings.Join(allSourceRefs, ", ")
+	r.EventRecorder.Event(targetSecret, corev1.EventTypeNormal, EventReasonReplicationSucceeded,
+		fmt.Sprintf("Successfully replicated from %s", joinedRefs))
+	log.Info("Pull replication succeeded", "target", fmt.Sprintf("%s/%s", targetSecret.Namespace, targetSecret.Name), "source", joinedRefs)
+
+	return r.requeueAtConsentExpiry(slices.Collect(maps.Values(sources))...), nil
+}
+
+// getPullSource fetches a source Secret for pull replication and checks that the target may replicate from it.
+// It returns nil (and no error) if the source cannot be used; a Warning event has been created in that case.
+func (r *SecretReplicatorReconciler) getPullSource(
+	ctx context.Context,
+	targetSecret *corev1.Secret,
+	sourceRef string,
+) (*corev1.Secret, error) {
+	log := log.FromContext(ctx)
+
+	sourceNamespace, sourceName, err := replicator.ParseSourceReference(sourceRef)
+	if err != nil {
+		return nil, err
+	}
+
+	// A SecretSource grants the consent in place of the annotations of its backing Secret
+	var secretSource *isov1alpha1.SecretSource
+	if name, ok := isov1alpha1.SecretSourceName(sourceRef); ok {
+		secretSource, err = r.getSecretSource(ctx, targetSecret, name)
+		if secretSource == nil {
+			return nil, err
+		}
+		sourceNamespace, sourceName = secretSource.Spec.SecretRef.Namespace, secretSource.Spec.SecretRef.Name
+	}
+	replicatedFrom := replicator.GetReplicatedFromAnnotation(targetSecret)
+	replicatedBefore := replicator.ReferencesSource(replicatedFrom, sourceRef) ||
+		(secretSource != nil && replicator.ReferencesSource(replicatedFrom, sourceNamespace+"/"+sourceName))
+
+	// Never pull from namespaces on the cluster denylist
+	if decision := replicator.DecideNamespace(sourceNamespace, r.Config.Replication.DeniedNamespaces); !decision.Allowed {
+		r.EventRecorder.Event(targetSecret, corev1.EventTypeWarning, EventReasonReplicationDenied,
+			fmt.Sprintf("Replication from %s not allowed: %s", sourceRef, decision.Reason))
+		log.Info("Source namespace is denied", "source", sourceRef)
+		return nil, nil // Don't requeue - the denylist is cluster configuration
+	}
+
 	// Fetch source Secret
 	sourceSecret := &corev1.Secret{}
 	sourceKey := types.NamespacedName{Namespace: sourceNamespace, Name: sourceName}
 	if err := r.Get(ctx, sourceKey, sourceSecret); err != nil {
+		if apierrors.IsNotFound(err) && replicatedBefore {
+			// The target was replicated from this source before, so it keeps its snapshot
+			marked, err := r.markStale(ctx, targetSecret, replicator.AnnotationSourceDeletedAt)
+			if marked {
+				r.EventRecorder.Event(targetSecret, corev1.EventTypeWarning, EventReasonSourceDeleted,
+					fmt.Sprintf("Source Secret %s was deleted. Target keeps its last known data and no longer receives updates.", sourceRef))
+				log.Info("Source Secret deleted - marked target as stale", "source", sourceRef)
+			}
+			return nil, err
+		}
 		if apierrors.IsNotFound(err) {
 			r.EventRecorder.Event(targetSecret, corev1.EventTypeWarning, EventReasonReplicationFailed,
 				fmt.Sprintf("Source Secret %s not found", sourceRef))
 			log.Info("Source Secret not found", "source", sourceRef)
-			return ctrl.Result{}, nil
+			return nil, nil
 		}
 		log.Error(err, "failed to get source Secret", "source", sourceRef)
-		return ctrl.Result{}, err
+		return nil, err
 	}
+	NormalizeStringData(sourceSecret)
 
 	// Check if source Secret was deleted
 	if replicator.IsBeingDeleted(sourceSecret) {
 		r.EventRecorder.Event(targetSecret, corev1.EventTypeWarning, EventReasonSourceDeleted,
 			fmt.Sprintf("Source Secret %s is being deleted. Target will keep last known data.", sourceRef))
 		log.Info("Source Secret being deleted - keeping snapshot", "source", sourceRef)
-		return ctrl.Result{}, nil
+		if replicatedBefore {
+			_, err := r.markStale(ctx, targetSecret, replicator.AnnotationSourceDeletedAt)
+			return nil, err
+		}
+		return nil, nil
 	}
 
 	// Validate replication is allowed (mutual consent)
-	sourceAllowlist := sourceSecret.Annotations[replicator.AnnotationReplicatableFromNamespaces]
-	allowed, err := replicator.ValidateReplication(sourceNamespace, sourceAllowlist, targetSecret.Namespace)
-	if err != nil || !allowed {
-		r.EventRecorder.Event(targetSecret, corev1.EventTypeWarning, EventReasonReplicationFailed,
-			fmt.Sprintf("Replication not allowed: %v", err))
-		log.Info("Replication not allowed", "source", sourceRef, "error", err)
-		return ctrl.Result{}, nil // Don't requeue - mutual consent required
+	consentSource := sourceSecret
+	if secretSource != nil {
+		consentSource = secretSourceConsent(secretSource)
+	}
+	if decision := r.validatePullConsent(ctx, consentSource, targetSecret.Namespace); !decision.Allowed {
+		r.EventRecorder.Event(targetSecret, corev1.EventTypeWarning, EventReasonReplicationDenied,
+			fmt.Sprintf("Replication from %s not allowed: %s", sourceRef, decision.Reason))
+		log.Info("Replication not allowed", "source", sourceRef, "reason", decision.Reason)
+		return nil, nil // Don't requeue - mutual consent required
 	}
 
-	// Replicate data from source to target
-	replicator.ReplicateSecret(sourceSecret, targetSecret)
+	// Time-boxed consent ends with replicatable-until; earlier syncs are kept as a snapshot
+	if decision := replicator.DecideConsentExpiry(consentSource, r.now()); !decision.Allowed {
+		r.EventRecorder.Event(targetSecret, corev1.EventTypeWarning, EventReasonReplicationDenied,
+			fmt.Sprintf("Replication from %s not allowed: %s", sourceRef, decision.Reason))
+		log.Info("Replication consent expired", "source", sourceRef, "reason", decision.Reason)
+		if replicatedBefore {
+			_, err := r.markStale(ctx, targetSecret, replicator.AnnotationConsentExpiredAt)
+			return nil, err
+		}
+		return nil, nil // Don't requeue - extending the consent triggers a new reconciliation
+	}
 
-	// Update target Secret
-	if err := r.Update(ctx, targetSecret); err != nil {
+	// Never pull across a replication boundary, regardless of the consent of the source
+	decision, err := r.decideBoundary(ctx, sourceNamespace, targetSecret.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if !decision.Allowed {
+		r.EventRecorder.Event(targetSecret, corev1.EventTypeWarning, EventReasonReplicationDenied,
+			fmt.Sprintf("Replication from %s not allowed: %s", sourceRef, decision.Reason))
+		log.Info("Replication crosses a boundary", "source", sourceRef, "reason", decision.Reason)
+		return nil, nil // Don't requeue - boundaries are cluster configuration
+	}
+
+	// The consent of the source only counts if policies allow it in the source namespace.
+	// The consent of a SecretSource is cluster configuration and not subject to policies.
+	if len(r.Config.Policies) > 0 && secretSource == nil {
+		namespaceLabels, err := namespaceLabelsForPolicies(ctx, r.Client, r.Config.Policies, sourceSecret.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		violations := policy.Evaluate(r.Config.Policies, sourceSecret, namespaceLabels,
+			[]string{config.PolicyFeatureReplicatableFrom})
+		if len(violations) > 0 {
+			r.EventRecorder.Event(targetSecret, corev1.EventTypeWarning, EventReasonReplicationDenied,
+				fmt.Sprintf("Replication from %s not allowed: %s", sourceRef, violations[0]))
+			log.Info("Replication denied by policy", "source", sourceRef, "rule", violations[0].Rule)
+			return nil, nil // Don't requeue - policies are cluster configuration
+		}
+	}
+
+	return sourceSecret, nil
+}
+
+// getSecretSource fetches a SecretSource referenced by a pull target.
+// It returns nil (and no error) if the SecretSource cannot be used; a Warning event has been created in that case.
+func (r *SecretReplicatorReconciler) getSecretSource(
+	ctx context.Context,
+	targetSecret *corev1.Secret,
+	name string,
+) (*isov1alpha1.SecretSource, error) {
+	log := log.FromContext(ctx)
+
+	if !r.Config.Features.SecretSources {
 		r.EventRecorder.Event(targetSecret, corev1.EventTypeWarning, EventReasonReplicationFailed,
-			fmt.Sprintf("Failed to update target Secret: %v", err))
-		log.Error(err, "failed to update target Secret")
-		return ctrl.Result{}, err
+			fmt.Sprintf("Cannot replicate from SecretSource %s: the secretSources feature is disabled", name))
+		log.Info("SecretSource referenced but feature disabled", "secretSource", name)
+		return nil, nil // Don't requeue - the feature is cluster configuration
 	}
 
-	r.EventRecorder.Event(targetSecret, corev1.EventTypeNormal, EventReasonReplicationSucceeded,
-		fmt.Sprintf("Successfully replicated from %s", sourceRef))
-	log.Info("Pull replication succeeded", "target", fmt.Sprintf("%s/%s", targetSecret.Namespace, targetSecret.Name), "source", sourceRef)
+	secretSource := &isov1alpha1.SecretSource{}
+	if err := r.Get(ctx, types.NamespacedName{Name: name}, secretSource); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.EventRecorder.Event(targetSecret, corev1.EventTypeWarning, EventReasonReplicationFailed,
+				fmt.Sprintf("SecretSource %s not found", name))
+			log.Info("SecretSource not found", "secretSource", name)
+			return nil, nil // Don't requeue - creating the SecretSource triggers a new reconciliation
+		}
+		log.Error(err, "failed to get SecretSource", "secretSource", name)
+		return nil, err
+	}
+	return secretSource, nil
+}
 
-	return ctrl.Result{}, nil
+// secretSourceConsent returns a Secret that carries the consent of a SecretSource as annotations,
+// so it can be checked like the consent of the backing Secret
+func secretSourceConsent(secretSource *isov1alpha1.SecretSource) *corev1.Secret {
+	annotations := make(map[string]string)
+	if len(secretSource.Spec.ReplicatableFromNamespaces) > 0 {
+		annotations[replicator.AnnotationReplicatableFromNamespaces] = strings.Join(secretSource.Spec.ReplicatableFromNamespaces, ",")
+	}
+	if secretSource.Spec.ReplicatableFromSelector != "" {
+		annotations[replicator.AnnotationReplicatableFromSelector] = secretSource.Spec.ReplicatableFromSelector
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   secretSource.Spec.SecretRef.Namespace,
+			Name:        secretSource.Spec.SecretRef.Name,
+			Annotations: annotations,
+		},
+	}
+}
+
+// markStale marks a snapshot target as stale with the given annotation (source-deleted-at or
+// consent-expired-at). It returns true if the target was not marked before.
+func (r *SecretReplicatorReconciler) markStale(ctx context.Context, targetSecret *corev1.Secret, annotation string) (bool, error) {
+	if replicator.IsStale(targetSecret) {
+		return false, nil
+	}
+
+	original := targetSecret.DeepCopy()
+	if targetSecret.Annotations == nil {
+		targetSecret.Annotations = make(map[string]string)
+	}
+	targetSecret.Annotations[annotation] = r.now().Format(time.RFC3339)
+	if err := r.Patch(ctx, targetSecret, client.MergeFrom(original)); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return true, nil
+}
+
+// validatePullConsent checks if the source Secret allows replication into the target namespace.
+// The target namespace is only fetched if the source selects namespaces by label.
+func (r *SecretReplicatorReconciler) validatePullConsent(
+	ctx context.Context,
+	sourceSecret *corev1.Secret,
+	targetNamespace string,
+) replicator.Decision {
+	decision := replicator.DecidePullConsent(sourceSecret, targetNamespace, nil)
+	if decision.Allowed || sourceSecret.Annotations[replicator.AnnotationReplicatableFromSelector] == "" {
+		return decision
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: targetNamespace}, namespace); err != nil {
+		return replicator.Deny("cannot get target namespace %q: %v", targetNamespace, err)
+	}
+	return replicator.DecidePullConsent(sourceSecret, targetNamespace, namespace.Labels)
+}
+
+// decideBoundary checks the replication boundaries between two namespaces.
+// The namespaces are only fetched if boundaries are configured.
+func (r *SecretReplicatorReconciler) decideBoundary(ctx context.Context, sourceNamespace, targetNamespace string) (replicator.Decision, error) {
+	boundaries := r.Config.Replication.Boundaries
+	if len(boundaries) == 0 {
+		return replicator.Allow(), nil
+	}
+
+	namespaceLabels := make([]map[string]string, 0, 2)
+	for _, name := range []string{sourceNamespace, targetNamespace} {
+		namespace := &corev1.Namespace{}
+		if err := r.Get(ctx, types.NamespacedName{Name: name}, namespace); client.IgnoreNotFound(err) != nil {
+			return replicator.Decision{}, fmt.Errorf("failed to get namespace %s: %w", name, err)
+		}
+		namespaceLabels = append(namespaceLabels, namespace.Labels)
+	}
+	return replicator.DecideBoundary(sourceNamespace, namespaceLabels[0], targetNamespace, namespaceLabels[1], boundaries), nil
+}
+
+// isNamespaceTerminating returns true if the namespace is being deleted
+func (r *SecretReplicatorReconciler) isNamespaceTerminating(ctx context.Context, name string) (bool, error) {
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: name}, namespace); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return isTerminating(namespace), nil
+}
+
+// isTerminating returns true if the namespace is being deleted
+func isTerminating(namespace *corev1.Namespace) bool {
+	return namespace.DeletionTimestamp != nil || namespace.Status.Phase == corev1.NamespaceTerminating
 }
 
 // handlePushReplication implements push-based replication (source pushes to targets)
 func (r *SecretReplicatorReconciler) handlePushReplication(ctx context.Context, sourceSecret *corev1.Secret) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 
-	// Parse target namespaces
+	// Resolve target namespaces
 	targetNSList := sourceSecret.Annotations[replicator.AnnotationReplicateTo]
-	targetNamespaces := replicator.ParseTargetNamespaces(targetNSList)
+	targetNamespaces, err := r.resolvePushTargets(ctx, sourceSecret)
+	if err != nil {
+		log.Error(err, "failed to resolve target namespaces")
+		return ctrl.Result{}, err
+	}
 
 	if len(targetNamespaces) == 0 {
 		log.Info("No target namespaces specified", "annotation", targetNSList)
+		r.pushFailures.forget(client.ObjectKeyFromObject(sourceSecret))
 		return ctrl.Result{}, nil
 	}
 
+	// Never push a Secret whose generated fields are incomplete
+	if pending := r.pendingGeneratedFields(sourceSecret); len(pending) > 0 {
+		log.Info("Waiting for the generator", "fields", pending)
+		return ctrl.Result{}, nil // The update of the generator triggers a new reconciliation
+	}
+
+	// Stop pushing once the time-boxed consent has ended; pushed targets keep their data
+	if decision := replicator.DecideConsentExpiry(sourceSecret, r.now()); !decision.Allowed {
+		r.EventRecorder.Event(sourceSecret, corev1.EventTypeWarning, EventReasonReplicationDenied,
+			fmt.Sprintf("Push replication stopped: %s", decision.Reason))
+		log.Info("Replication consent expired", "reason", decision.Reason)
+		r.pushFailures.forget(client.ObjectKeyFromObject(sourceSecret))
+		return ctrl.Result{}, r.markPushTargetsStale(ctx, sourceSecret, targetNamespaces)
+	}
+
 	// Add finalizer to source Secret for cleanup
 	if !replicator.HasFinalizer(sourceSecret) {
 		replicator.AddFinalizer(sourceSecret)
@@ -184,61 +595,273 @@ func (r *SecretReplicatorReconciler) handlePushReplication(ctx context.Context,
 
 	sourceRef := fmt.Sprintf("%s/%s", sourceSecret.Namespace, sourceSecret.Name)
 
+	usage, err := r.loadReplicationUsage(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Push to each target namespace
+	var failed []string
 	for _, targetNS := range targetNamespaces {
-		if err := r.pushToNamespace(ctx, sourceSecret, targetNS, sourceRef); err != nil {
+		if err := r.pushToNamespace(ctx, sourceSecret, targetNS, sourceRef, usage); err != nil {
 			log.Error(err, "failed to push to namespace", "targetNamespace", targetNS)
 			// Continue with other namespaces even if one fails
+			failed = append(failed, targetNS)
 		}
 	}
 
-	return ctrl.Result{}, nil
+	// Retry failed namespaces with backoff instead of waiting for an unrelated change
+	if retryAfter := r.pushFailures.record(client.ObjectKeyFromObject(sourceSecret), failed); retryAfter > 0 {
+		log.Info("Retrying failed push targets", "targetNamespaces", failed, "retryAfter", retryAfter)
+		return ctrl.Result{RequeueAfter: retryAfter}, nil
+	}
+
+	result := r.requeueAtConsentExpiry(sourceSecret)
+	// Targets refused by the replication limit are pushed once other targets free up space
+	if usage != nil && usage.refused && (result.RequeueAfter == 0 || result.RequeueAfter > quotaRetryInterval) {
+		result.RequeueAfter = quotaRetryInterval
+	}
+	return result, nil
+}
+
+// markPushTargetsStale marks the existing targets pushed from a source Secret as stale
+func (r *SecretReplicatorReconciler) markPushTargetsStale(ctx context.Context, sourceSecret *corev1.Secret, targetNamespaces []string) error {
+	sourceRef := fmt.Sprintf("%s/%s", sourceSecret.Namespace, sourceSecret.Name)
+	for _, targetNS := range targetNamespaces {
+		targetName, err := replicator.RenderTargetName(sourceSecret, targetNS)
+		if err != nil {
+			continue
+		}
+		targetSecret := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: targetNS, Name: targetName}, targetSecret); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get target Secret: %w", err)
+		}
+		if !replicator.IsOwnedByUs(targetSecret, sourceRef) {
+			continue
+		}
+		marked, err := r.markStale(ctx, targetSecret, replicator.AnnotationConsentExpiredAt)
+		if err != nil {
+			return err
+		}
+		if marked {
+			r.EventRecorder.Event(targetSecret, corev1.EventTypeWarning, EventReasonReplicationDenied,
+				fmt.Sprintf("Consent of source Secret %s expired. Target keeps its last known data and no longer receives updates.", sourceRef))
+		}
+	}
+	return nil
+}
+
+// requeueAtConsentExpiry returns a result that requeues when the earliest replicatable-until
+// consent of the sources ends, so that targets are marked stale on time
+func (r *SecretReplicatorReconciler) requeueAtConsentExpiry(sources ...*corev1.Secret) ctrl.Result {
+	var remaining *time.Duration
+	for _, source := range sources {
+		until, err := replicator.ConsentExpiry(source)
+		if err != nil || until == nil {
+			continue
+		}
+		untilExpiry := until.Sub(r.now())
+		remaining = minDuration(remaining, &untilExpiry)
+	}
+	if remaining == nil {
+		return ctrl.Result{}
+	}
+	return ctrl.Result{RequeueAfter: max(*remaining, time.Second)}
+}
+
+// resolvePushTargets returns the namespaces a source Secret is pushed to.
+// The wildcard "*" expands to all namespaces except the source namespace. Namespaces matching the
+// replicate-exclude-namespaces annotation or the cluster denylist are skipped. Namespaces across a
+// replication boundary are skipped with a Warning event, unless they were matched by the wildcard.
+func (r *SecretReplicatorReconciler) resolvePushTargets(ctx context.Context, sourceSecret *corev1.Secret) ([]string, error) {
+	log := log.FromContext(ctx)
+
+	targetNamespaces := replicator.ParseTargetNamespaces(sourceSecret.Annotations[replicator.AnnotationReplicateTo])
+	wildcard := slices.Contains(targetNamespaces, replicator.ReplicateToAllNamespaces)
+	if wildcard {
+		namespaceList := &corev1.NamespaceList{}
+		if err := r.List(ctx, namespaceList); err != nil {
+			return nil, fmt.Errorf("failed to list namespaces: %w", err)
+		}
+		targetNamespaces = make([]string, 0, len(namespaceList.Items))
+		for _, namespace := range namespaceList.Items {
+			if namespace.Name != sourceSecret.Namespace {
+				targetNamespaces = append(targetNamespaces, namespace.Name)
+			}
+		}
+	}
+
+	result := make([]string, 0, len(targetNamespaces))
+	for _, targetNS := range targetNamespaces {
+		if decision := replicator.DecidePushTarget(sourceSecret, targetNS, r.Config.Replication.DeniedNamespaces); !decision.Allowed {
+			log.V(1).Info("Skipping namespace", "targetNamespace", targetNS, "reason", decision.Reason)
+			continue
+		}
+		decision, err := r.decideBoundary(ctx, sourceSecret.Namespace, targetNS)
+		if err != nil {
+			return nil, err
+		}
+		if !decision.Allowed {
+			if !wildcard {
+				r.EventRecorder.Event(sourceSecret, corev1.EventTypeWarning, EventReasonReplicationDenied,
+					fmt.Sprintf("Push to namespace %s not allowed: %s", targetNS, decision.Reason))
+			}
+			log.V(1).Info("Skipping namespace", "targetNamespace", targetNS, "reason", decision.Reason)
+			continue
+		}
+		result = append(result, targetNS)
+	}
+
+	return result, nil
 }
 
 // pushToNamespace pushes a Secret to a target namespace
-func (r *SecretReplicatorReconciler) pushToNamespace(ctx context.Context, sourceSecret *corev1.Secret, targetNS string, sourceRef string) error {
+func (r *SecretReplicatorReconciler) pushToNamespace(
+	ctx context.Context,
+	sourceSecret *corev1.Secret,
+	targetNS, sourceRef string,
+	usage *replicationUsage,
+) error {
 	log := log.FromContext(ctx)
 
+	// Determine the target name (optionally templated per namespace)
+	targetName, err := replicator.RenderTargetName(sourceSecret, targetNS)
+	if err != nil {
+		r.EventRecorder.Event(sourceSecret, corev1.EventTypeWarning, EventReasonPushFailed,
+			fmt.Sprintf("Cannot determine target name for namespace %s: %v", targetNS, err))
+		log.Info("Cannot determine target name", "targetNamespace", targetNS, "error", err)
+		return nil // Don't return error - retrying does not fix the template, source changes trigger a new reconciliation
+	}
+
 	// Check if target Secret already exists
 	targetSecret := &corev1.Secret{}
-	targetKey := types.NamespacedName{Namespace: targetNS, Name: sourceSecret.Name}
-	err := r.Get(ctx, targetKey, targetSecret)
+	targetKey := types.NamespacedName{Namespace: targetNS, Name: targetName}
+	err = r.Get(ctx, targetKey, targetSecret)
 
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			// Target doesn't exist - create it
-			targetSecret = replicator.CreateReplicatedSecret(sourceSecret, targetNS)
+			targetSecret = replicator.CreateReplicatedSecret(sourceSecret, targetNS, r.now())
+			targetSecret.Name = targetName
+			targetSecret.Type = replicator.PushedType(sourceSecret.Type, r.Config.Replication.PushedType)
+			replicator.ApplyPushedLabels(targetSecret, r.Config.Replication.PushedLabels)
+			r.applyOwnerLabels(targetSecret, []string{sourceRef})
+			if err := validateTLSPair(targetSecret); err != nil {
+				r.EventRecorder.Event(sourceSecret, corev1.EventTypeWarning, EventReasonInvalidTLSPair,
+					fmt.Sprintf("Not pushing to namespace %s, data does not form a valid TLS pair: %v", targetNS, err))
+				log.Info("Pushed data does not form a valid TLS pair", "targetNamespace", targetNS, "error", err)
+				return nil // Don't return error - source changes trigger a new reconciliation
+			}
+			if exceeded := usage.reserve(nil, targetSecret); exceeded != "" {
+				r.refusePush(ctx, sourceSecret, targetNS, exceeded)
+				return nil
+			}
 			if err := r.Create(ctx, targetSecret); err != nil {
 				r.EventRecorder.Event(sourceSecret, corev1.EventTypeWarning, EventReasonPushFailed,
 					fmt.Sprintf("Failed to create Secret in namespace %s: %v", targetNS, err))
 				return fmt.Errorf("failed to create target Secret: %w", err)
 			}
+			r.Flows.record(targetKey, ReplicationModePush, []string{sourceSecret.Namespace}, r.now())
+			r.EventRecorder.Event(targetSecret, corev1.EventTypeNormal, EventReasonReplicationSucceeded,
+				fmt.Sprintf("Created by push replication from %s", sourceRef))
 			log.Info("Created replicated Secret", "targetNamespace", targetNS, "name", targetSecret.Name)
 			return nil
 		}
 		return fmt.Errorf("failed to get target Secret: %w", err)
 	}
 
-	// Target exists - check if we own it
-	if !replicator.IsOwnedByUs(targetSecret, sourceRef) {
-		r.EventRecorder.Event(sourceSecret, corev1.EventTypeWarning, EventReasonPushFailed,
-			fmt.Sprintf("Secret %s/%s already exists and is not owned by this replication (no replicated-from annotation)", targetNS, sourceSecret.Name))
-		log.Info("Target Secret exists but is not owned by us", "targetNamespace", targetNS, "name", sourceSecret.Name)
+	// Target exists - never overwrite Secrets owned by other components
+	typeIgnored := r.Config.IsSecretTypeIgnored(string(targetSecret.Type))
+	if decision := replicator.DecideOverwrite(targetSecret, sourceRef, typeIgnored); !decision.Allowed {
+		r.EventRecorder.Event(sourceSecret, corev1.EventTypeWarning, EventReasonPushFailed, decision.Reason)
+		log.Info("Target Secret is not overwritten", "targetNamespace", targetNS, "name", targetName, "reason", decision.Reason)
 		return nil // Don't return error - just skip this target
 	}
 
+	if !r.handleDrift(ctx, targetSecret) {
+		return nil
+	}
+
 	// We own it - update it
-	replicator.ReplicateSecret(sourceSecret, targetSecret)
+	previous := usage.snapshot(targetSecret)
+	previousData := maps.Clone(targetSecret.Data)
+	replicator.ReplicateSecret(sourceSecret, targetSecret, r.now())
+	replicator.ApplyPushedLabels(targetSecret, r.Config.Replication.PushedLabels)
+	r.applyOwnerLabels(targetSecret, []string{sourceRef})
+	if err := renderReplicatedAggregateKeys(targetSecret); err != nil {
+		r.EventRecorder.Event(sourceSecret, corev1.EventTypeWarning, EventReasonPushFailed,
+			fmt.Sprintf("Not pushing to namespace %s, cannot render replicated data: %v", targetNS, err))
+		log.Info("Cannot render pushed data", "targetNamespace", targetNS, "error", err)
+		return nil // Don't return error - source changes trigger a new reconciliation
+	}
+	if err := validateTLSPair(targetSecret); err != nil {
+		r.EventRecorder.Event(sourceSecret, corev1.EventTypeWarning, EventReasonInvalidTLSPair,
+			fmt.Sprintf("Not pushing to namespace %s, data does not form a valid TLS pair: %v", targetNS, err))
+		log.Info("Pushed data does not form a valid TLS pair", "targetNamespace", targetNS, "error", err)
+		return nil // Don't return error - source changes trigger a new reconciliation
+	}
+	if exceeded := usage.reserve(previous, targetSecret); exceeded != "" {
+		r.refusePush(ctx, sourceSecret, targetNS, exceeded)
+		return nil
+	}
 	if err := r.Update(ctx, targetSecret); err != nil {
 		r.EventRecorder.Event(sourceSecret, corev1.EventTypeWarning, EventReasonPushFailed,
 			fmt.Sprintf("Failed to update Secret in namespace %s: %v", targetNS, err))
 		return fmt.Errorf("failed to update target Secret: %w", err)
 	}
+	r.Flows.record(targetKey, ReplicationModePush, []string{sourceSecret.Namespace}, r.now())
+
+	// Only announce actual data changes on the target to avoid an event on every resync
+	if !reflect.DeepEqual(previousData, targetSecret.Data) {
+		r.EventRecorder.Event(targetSecret, corev1.EventTypeNormal, EventReasonReplicationSucceeded,
+			fmt.Sprintf("Updated by push replication from %s", sourceRef))
+	}
 
 	log.Info("Updated replicated Secret", "targetNamespace", targetNS, "name", targetSecret.Name)
 	return nil
 }
 
+// refusePush reports a push target refused by the replication limit on the source
+func (r *SecretReplicatorReconciler) refusePush(ctx context.Context, sourceSecret *corev1.Secret, targetNS, reason string) {
+	r.EventRecorder.Event(sourceSecret, corev1.EventTypeWarning, EventReasonReplicationLimitExceeded,
+		fmt.Sprintf("Not pushing to namespace %s: %s", targetNS, reason))
+	log.FromContext(ctx).Info("Replication limit exceeded", "targetNamespace", targetNS, "reason", reason)
+}
+
+// applyOwnerLabels sets the configured owner labels on a replicated Secret. Owner labels that no
+// longer apply (e.g. the source name after a second source was added) are removed.
+func (r *SecretReplicatorReconciler) applyOwnerLabels(target *corev1.Secret, sourceRefs []string) {
+	for _, key := range r.Config.Replication.OwnerLabels.Keys() {
+		delete(target.Labels, key)
+	}
+	replicator.ApplyPushedLabels(target, r.Config.Replication.OwnerLabels.Labels(sourceRefs))
+}
+
+// handleDrift detects manual modifications of a replicated target and applies the configured
+// drift policy. It returns false if the target must not be overwritten.
+func (r *SecretReplicatorReconciler) handleDrift(ctx context.Context, targetSecret *corev1.Secret) bool {
+	if !replicator.HasDrifted(targetSecret) {
+		return true
+	}
+
+	if r.Config.Replication.DriftPolicy == config.DriftPolicyWarn {
+		r.EventRecorder.Event(targetSecret, corev1.EventTypeWarning, EventReasonDriftDetected,
+			"Replicated data was modified manually; replication is suspended until the modification is reverted")
+		log.FromContext(ctx).Info("Replicated Secret was modified manually, not overwriting",
+			"namespace", targetSecret.Namespace, "name", targetSecret.Name)
+		return false
+	}
+
+	r.EventRecorder.Event(targetSecret, corev1.EventTypeWarning, EventReasonDriftDetected,
+		"Replicated data was modified manually; the modification is overwritten")
+	log.FromContext(ctx).Info("Replicated Secret was modified manually, overwriting",
+		"namespace", targetSecret.Namespace, "name", targetSecret.Name)
+	return true
+}
+
 // handleDeletion handles cleanup when a source Secret with replicate-to is deleted
 func (r *SecretReplicatorReconciler) handleDeletion(ctx context.Context, sourceSecret *corev1.Secret) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
@@ -268,14 +891,33 @@ func (r *SecretReplicatorReconciler) handleDeletion(ctx context.Context, sourceS
 		return ctrl.Result{}, err
 	}
 
-	// Delete all pushed Secrets
+	// Delete all pushed Secrets. Deleting in a terminating namespace fails until the namespace
+	// is gone, so those copies are left to the namespace deletion.
+	terminating := make(map[string]bool)
 	for i := range secretList.Items {
 		secret := &secretList.Items[i]
 		if replicator.GetReplicatedFromAnnotation(secret) == sourceRef {
+			skip, checked := terminating[secret.Namespace]
+			if !checked {
+				var err error
+				if skip, err = r.isNamespaceTerminating(ctx, secret.Namespace); err != nil {
+					log.Error(err, "failed to get namespace for cleanup", "namespace", secret.Namespace)
+					return ctrl.Result{}, err
+				}
+				terminating[secret.Namespace] = skip
+				if skip {
+					log.Info("Namespace is terminating, leaving replicated Secrets to the namespace deletion",
+						"namespace", secret.Namespace)
+				}
+			}
+			if skip {
+				continue
+			}
 			if err := r.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
 				log.Error(err, "failed to delete replicated Secret", "namespace", secret.Namespace, "name", secret.Name)
 				return ctrl.Result{}, err
 			}
+			r.Flows.forget(client.ObjectKeyFromObject(secret))
 			log.Info("Deleted replicated Secret", "namespace", secret.Namespace, "name", secret.Name)
 		}
 	}
@@ -287,6 +929,7 @@ func (r *SecretReplicatorReconciler) handleDeletion(ctx context.Context, sourceS
 		return ctrl.Result{}, err
 	}
 
+	r.pushFailures.forget(client.ObjectKeyFromObject(sourceSecret))
 	log.Info("Cleaned up all replicated Secrets", "source", sourceRef)
 	return ctrl.Result{}, nil
 }
@@ -310,11 +953,20 @@ func (r *SecretReplicatorReconciler) SetupWithManagerAndName(mgr ctrl.Manager, n
 			return false
 		}
 
+		// Skip Secrets without the opt-in label (if label-based opt-in is enabled)
+		if !r.Config.ManagedLabel.Matches(secret.Labels) {
+			return false
+		}
+
+		// Skip Secrets owned by other components
+		if r.Config.IsSecretTypeIgnored(string(secret.Type)) {
+			return false
+		}
+
 		// Watch Secrets with replication annotations
-		hasReplicateFrom := secret.Annotations[replicator.AnnotationReplicateFrom] != ""
 		hasReplicateTo := secret.Annotations[replicator.AnnotationReplicateTo] != ""
 
-		return hasReplicateFrom || hasReplicateTo
+		return replicator.IsPullTarget(secret) || hasReplicateTo
 	})
 
 	// Predicate for source Secrets: trigger target reconciliation when source changes
@@ -323,22 +975,112 @@ func (r *SecretReplicatorReconciler) SetupWithManagerAndName(mgr ctrl.Manager, n
 		if !ok {
 			return false
 		}
-		// Only watch Secrets that could be sources (have replicatable-from-namespaces)
-		return secret.Annotations != nil &&
-			secret.Annotations[replicator.AnnotationReplicatableFromNamespaces] != ""
+		if !r.Config.ManagedLabel.Matches(secret.Labels) || r.Config.IsSecretTypeIgnored(string(secret.Type)) {
+			return false
+		}
+		// Only watch Secrets that could be sources (have replicatable-from-namespaces or -selector,
+		// or back a SecretSource)
+		return replicator.AllowsReplication(secret) || len(r.secretSourcesFor(context.Background(), secret)) > 0
 	})
 
-	return ctrl.NewControllerManagedBy(mgr).
+	if err := registerCollector(newStaleTargetsGauge(mgr.GetClient())); err != nil {
+		return err
+	}
+	r.replicatedBytes = newReplicatedBytesTracker()
+	if err := r.replicatedBytes.setupWithManager(mgr); err != nil {
+		return err
+	}
+	if err := registerCollector(newReplicatedBytesCollector(r.replicatedBytes, r.Config.Metrics)); err != nil {
+		return err
+	}
+	if err := registerCollector(newReplicationTargetsCollector(mgr.GetClient(), r.Config, r.Shard)); err != nil {
+		return err
+	}
+	if err := registerCollector(newPushFailedTargetsGauge(&r.pushFailures)); err != nil {
+		return err
+	}
+	if r.Flows != nil {
+		if err := registerCollector(r.Flows); err != nil {
+			return err
+		}
+	}
+	recorder, err := instrumentEventRecorder(r.EventRecorder)
+	if err != nil {
+		return err
+	}
+	r.EventRecorder = recorder
+
+	var secretHandler handler.EventHandler = &handler.EnqueueRequestForObject{}
+	if r.Checkpoint != nil {
+		secretHandler = r.Checkpoint.eventHandler()
+	}
+	sourceHandler := r.fanOutHandler(r.findTargetsForSource)
+	sourceHandler.checkpoint = r.Checkpoint
+
+	b := ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		// Watch Secrets with replicate-from or replicate-to annotations
-		For(&corev1.Secret{}, builder.WithPredicates(mainPredicate)).
+		Watches(&corev1.Secret{}, secretHandler, builder.WithPredicates(mainPredicate)).
 		// Watch source Secrets to trigger reconciliation of target Secrets when source changes
+		Watches(&corev1.Secret{}, sourceHandler, builder.WithPredicates(sourcePredicate)).
+		// Watch new namespaces to push Secrets with replicate-to "*" into them
 		Watches(
-			&corev1.Secret{},
-			handler.EnqueueRequestsFromMapFunc(r.findTargetsForSource),
-			builder.WithPredicates(sourcePredicate),
-		).
-		Complete(r)
+			&corev1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.findWildcardPushSources),
+			builder.WithPredicates(namespaceCreated),
+		)
+	if r.Config.Features.SecretSources {
+		// Watch SecretSources to reconcile their targets when the backing Secret is relocated
+		b = b.Watches(&isov1alpha1.SecretSource{}, r.fanOutHandler(r.findTargetsForSecretSource))
+	}
+	if period := r.Config.Cache.ResyncPeriodFor(config.ControllerSecretReplicator); period > 0 {
+		b = b.WatchesRawSource(resyncSource(mgr.GetClient(), period, mainPredicate))
+	}
+	return b.Complete(r.Heartbeat.wrap(name, r.Checkpoint.wrap(r.Shard.wrap(r.Locks.wrap(
+		reportPanics(r.EventRecorder, &corev1.Secret{}, r))))))
+}
+
+// fanOutHandler enqueues the targets found by the map function, rate-limited per source
+func (r *SecretReplicatorReconciler) fanOutHandler(targets handler.MapFunc) *fanOutHandler {
+	return &fanOutHandler{
+		limiter: r.FanOutLimiter,
+		targets: targets,
+		now:     r.now,
+	}
+}
+
+// namespaceCreated only passes Namespace creation events
+var namespaceCreated = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return true },
+	UpdateFunc:  func(event.UpdateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+}
+
+// findWildcardPushSources finds all source Secrets that push to all namespaces ("*")
+// This enables replication into namespaces created after the source
+func (r *SecretReplicatorReconciler) findWildcardPushSources(ctx context.Context, obj client.Object) []reconcile.Request {
+	log := log.FromContext(ctx)
+
+	secretList := &corev1.SecretList{}
+	if err := r.List(ctx, secretList); err != nil {
+		log.Error(err, "failed to list Secrets for new namespace", "namespace", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range secretList.Items {
+		source := &secretList.Items[i]
+		targets := replicator.ParseTargetNamespaces(source.Annotations[replicator.AnnotationReplicateTo])
+		if !slices.Contains(targets, replicator.ReplicateToAllNamespaces) || !r.Config.ManagedLabel.Matches(source.Labels) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: source.Namespace, Name: source.Name},
+		})
+	}
+
+	return requests
 }
 
 // findTargetsForSource finds all target Secrets that replicate from a given source Secret
@@ -349,8 +1091,46 @@ func (r *SecretReplicatorReconciler) findTargetsForSource(ctx context.Context, o
 		return nil
 	}
 
+	// Targets may reference the Secret directly or through a SecretSource
+	sourceRefs := append([]string{fmt.Sprintf("%s/%s", secret.Namespace, secret.Name)}, r.secretSourcesFor(ctx, secret)...)
+	return r.findTargets(ctx, sourceRefs)
+}
+
+// findTargetsForSecretSource finds all target Secrets that replicate from a given SecretSource
+func (r *SecretReplicatorReconciler) findTargetsForSecretSource(ctx context.Context, obj client.Object) []reconcile.Request {
+	secretSource, ok := obj.(*isov1alpha1.SecretSource)
+	if !ok {
+		return nil
+	}
+	return r.findTargets(ctx, []string{secretSource.Reference()})
+}
+
+// secretSourcesFor returns the references of all SecretSources backed by the given Secret
+func (r *SecretReplicatorReconciler) secretSourcesFor(ctx context.Context, secret *corev1.Secret) []string {
+	if !r.Config.Features.SecretSources {
+		return nil
+	}
+
+	secretSources := &isov1alpha1.SecretSourceList{}
+	if err := r.List(ctx, secretSources); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list SecretSources")
+		return nil
+	}
+
+	var refs []string
+	for i := range secretSources.Items {
+		secretRef := secretSources.Items[i].Spec.SecretRef
+		if secretRef.Namespace == secret.Namespace && secretRef.Name == secret.Name {
+			refs = append(refs, secretSources.Items[i].Reference())
+		}
+	}
+	return refs
+}
+
+// findTargets finds all target Secrets that replicate from any of the given source references
+func (r *SecretReplicatorReconciler) findTargets(ctx context.Context, sourceRefs []string) []reconcile.Request {
 	log := log.FromContext(ctx)
-	sourceRef := fmt.Sprintf("%s/%s", secret.Namespace, secret.Name)
+	sourceRef := strings.Join(sourceRefs, ", ")
 
 	// Find all Secrets with replicate-from annotation pointing to this source
 	secretList := &corev1.SecretList{}
@@ -366,9 +1146,8 @@ func (r *SecretReplicatorReconciler) findTargetsForSource(ctx context.Context, o
 			continue
 		}
 
-		// Check if this target pulls from our source
-		targetSourceRef := target.Annotations[replicator.AnnotationReplicateFrom]
-		if targetSourceRef == sourceRef {
+		// Check if this target pulls from our source (possibly among other sources or for single keys)
+		if slices.ContainsFunc(sourceRefs, func(ref string) bool { return replicator.PullsFrom(target, ref) }) {
 			requests = append(requests, reconcile.Request{
 				NamespacedName: types.NamespacedName{
 					Namespace: target.Namespace,
@@ -19,10 +19,13 @@ package controller
 import (
 	"context"
 	"fmt"
+	"maps"
 	"strings"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -32,7 +35,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
+	isov1alpha1 "github.com/guided-traffic/internal-secrets-operator/api/v1alpha1"
 	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
 	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
 )
 
@@ -226,6 +231,7 @@ func TestSecretReplicatorReconciler_PushReplication(t *testing.T) {
 		expectCreated  bool
 		expectUpdated  bool
 		expectSkipped  bool
+		expectMissing  bool
 	}{
 		{
 			name: "push creates new secret",
@@ -300,6 +306,24 @@ func TestSecretReplicatorReconciler_PushReplication(t *testing.T) {
 			targetNS:      "staging",
 			expectSkipped: true,
 		},
+		{
+			name: "push waits for generated fields",
+			sourceSecret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app-secret",
+					Namespace: "production",
+					Annotations: map[string]string{
+						replicator.AnnotationReplicateTo: "staging",
+						AnnotationAutogenerate:           "key,password",
+					},
+				},
+				Data: map[string][]byte{
+					"key": []byte("value"),
+				},
+			},
+			targetNS:      "staging",
+			expectMissing: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -373,6 +397,10 @@ func TestSecretReplicatorReconciler_PushReplication(t *testing.T) {
 					t.Errorf("Unowned secret was modified")
 				}
 			}
+
+			if tt.expectMissing && !apierrors.IsNotFound(err) {
+				t.Errorf("Expected secret not to be created, got error: %v", err)
+			}
 		})
 	}
 }
@@ -504,114 +532,1034 @@ func TestSecretReplicatorReconciler_FindTargetsForSource(t *testing.T) {
 		}
 	}
 
-	if !foundStaging {
-		t.Error("Did not find reconcile request for staging/db-credentials")
+	if !foundStaging {
+		t.Error("Did not find reconcile request for staging/db-credentials")
+	}
+	if !foundDevelopment {
+		t.Error("Did not find reconcile request for development/db-credentials")
+	}
+}
+
+func TestSecretReplicatorReconciler_PullFromMultipleSources(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	caBundle := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ca-bundle",
+			Namespace: "infra",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicatableFromNamespaces: "app",
+			},
+		},
+		Data: map[string][]byte{
+			"ca.crt": []byte("ca"),
+			"shared": []byte("from-ca-bundle"),
+		},
+	}
+	proxyCreds := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "proxy-creds",
+			Namespace: "infra",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicatableFromNamespaces: "app",
+			},
+		},
+		Data: map[string][]byte{
+			"proxy-password": []byte("secret"),
+			"shared":         []byte("from-proxy-creds"),
+		},
+	}
+	target := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "combined",
+			Namespace: "app",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicateFrom: "infra/ca-bundle,infra/proxy-creds",
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(caBundle, proxyCreds, target).
+		Build()
+
+	reconciler := &SecretReplicatorReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "combined", Namespace: "app"}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	updated := &corev1.Secret{}
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, updated); err != nil {
+		t.Fatalf("Failed to get target Secret: %v", err)
+	}
+
+	expected := map[string]string{
+		"ca.crt":         "ca",
+		"proxy-password": "secret",
+		"shared":         "from-proxy-creds", // later source wins
+	}
+	for key, value := range expected {
+		if string(updated.Data[key]) != value {
+			t.Errorf("Data[%q] = %q, want %q", key, updated.Data[key], value)
+		}
+	}
+
+	// Both sources trigger reconciliation of the combined target
+	for _, source := range []*corev1.Secret{caBundle, proxyCreds} {
+		requests := reconciler.findTargetsForSource(context.Background(), source)
+		if len(requests) != 1 || requests[0].NamespacedName != req.NamespacedName {
+			t.Errorf("Expected source %s to map to the combined target, got %v", source.Name, requests)
+		}
+	}
+}
+
+func TestSecretReplicatorReconciler_PullFromMultipleSourcesRequiresAllConsents(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	allowed := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ca-bundle",
+			Namespace: "infra",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicatableFromNamespaces: "app",
+			},
+		},
+		Data: map[string][]byte{"ca.crt": []byte("ca")},
+	}
+	denied := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "proxy-creds",
+			Namespace: "infra",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicatableFromNamespaces: "other",
+			},
+		},
+		Data: map[string][]byte{"proxy-password": []byte("secret")},
+	}
+	target := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "combined",
+			Namespace: "app",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicateFrom: "infra/ca-bundle,infra/proxy-creds",
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(allowed, denied, target).
+		Build()
+
+	fakeRecorder := record.NewFakeRecorder(10)
+	reconciler := &SecretReplicatorReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: fakeRecorder,
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "combined", Namespace: "app"}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	updated := &corev1.Secret{}
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, updated); err != nil {
+		t.Fatalf("Failed to get target Secret: %v", err)
+	}
+	if len(updated.Data) != 0 {
+		t.Errorf("Expected target to stay empty if one source denies replication, got %v", updated.Data)
+	}
+
+	select {
+	case event := <-fakeRecorder.Events:
+		if !strings.Contains(event, EventReasonReplicationDenied) || !strings.Contains(event, "infra/proxy-creds") {
+			t.Errorf("Expected ReplicationDenied event for infra/proxy-creds, got %q", event)
+		}
+	default:
+		t.Error("Expected a ReplicationDenied event")
+	}
+}
+
+func TestSecretReplicatorReconciler_PullPerKeyReferences(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	db := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "db",
+			Namespace: "infra",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicatableFromNamespaces: "app",
+			},
+		},
+		Data: map[string][]byte{
+			"password": []byte("dbpass"),
+			"username": []byte("dbuser"),
+		},
+	}
+	api := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "api",
+			Namespace: "infra",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicatableFromNamespaces: "app",
+			},
+		},
+		Data: map[string][]byte{"token": []byte("apitoken")},
+	}
+	target := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-config",
+			Namespace: "app",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicateFromKeyPrefix + "db-password": "infra/db#password",
+				replicator.AnnotationReplicateFromKeyPrefix + "token":       "infra/api",
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(db, api, target).
+		Build()
+
+	reconciler := &SecretReplicatorReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app-config", Namespace: "app"}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	updated := &corev1.Secret{}
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, updated); err != nil {
+		t.Fatalf("Failed to get target Secret: %v", err)
+	}
+
+	expected := map[string]string{
+		"db-password": "dbpass",
+		"token":       "apitoken",
+	}
+	if len(updated.Data) != len(expected) {
+		t.Errorf("Expected only referenced keys, got %v", updated.Data)
+	}
+	for key, value := range expected {
+		if string(updated.Data[key]) != value {
+			t.Errorf("Data[%q] = %q, want %q", key, updated.Data[key], value)
+		}
+	}
+
+	if requests := reconciler.findTargetsForSource(context.Background(), db); len(requests) != 1 {
+		t.Errorf("Expected per-key source to map to the target, got %v", requests)
+	}
+}
+
+func TestSecretReplicatorReconciler_PullTypeProjection(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	opts := generator.CertificateOptions{CommonName: "server", Duration: time.Hour}
+	cert, err := generator.GenerateSelfSignedCertificate(opts)
+	if err != nil {
+		t.Fatalf("Failed to generate certificate: %v", err)
+	}
+	other, err := generator.GenerateSelfSignedCertificate(opts)
+	if err != nil {
+		t.Fatalf("Failed to generate certificate: %v", err)
+	}
+
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "certs",
+			Namespace: "infra",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicatableFromNamespaces: "app",
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"server.crt": cert.CertPEM,
+			"server.key": cert.KeyPEM,
+			"other.key":  other.KeyPEM,
+			"ca.crt":     []byte("ca"),
+		},
+	}
+	newTarget := func(name string, keyAnnotations map[string]string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "app",
+				Annotations: keyAnnotations,
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: map[string][]byte{"tls.crt": {}, "tls.key": {}},
+		}
+	}
+	projected := newTarget("projected", map[string]string{
+		replicator.AnnotationReplicateFromKeyPrefix + "tls.crt": "infra/certs#server.crt",
+		replicator.AnnotationReplicateFromKeyPrefix + "tls.key": "infra/certs#server.key",
+		replicator.AnnotationReplicateFromKeyPrefix + "ca.crt":  "infra/certs",
+	})
+	incomplete := newTarget("incomplete", map[string]string{
+		replicator.AnnotationReplicateFromKeyPrefix + "tls.crt": "infra/certs#server.crt",
+	})
+	mismatched := newTarget("mismatched", map[string]string{
+		replicator.AnnotationReplicateFromKeyPrefix + "tls.crt": "infra/certs#server.crt",
+		replicator.AnnotationReplicateFromKeyPrefix + "tls.key": "infra/certs#other.key",
+	})
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(source, projected, incomplete, mismatched).
+		Build()
+
+	fakeRecorder := record.NewFakeRecorder(10)
+	reconciler := &SecretReplicatorReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: fakeRecorder,
+	}
+
+	// All required keys are projected into the TLS target
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "projected", Namespace: "app"}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	updated := &corev1.Secret{}
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, updated); err != nil {
+		t.Fatalf("Failed to get target Secret: %v", err)
+	}
+	if string(updated.Data["tls.crt"]) != string(cert.CertPEM) || string(updated.Data["tls.key"]) != string(cert.KeyPEM) ||
+		string(updated.Data["ca.crt"]) != "ca" {
+		t.Errorf("Unexpected projected data: %v", updated.Data)
+	}
+	<-fakeRecorder.Events // ReplicationSucceeded
+
+	// The private key is missing, so the TLS target is not updated
+	req = ctrl.Request{NamespacedName: types.NamespacedName{Name: "incomplete", Namespace: "app"}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, updated); err != nil {
+		t.Fatalf("Failed to get target Secret: %v", err)
+	}
+	if len(updated.Data["tls.crt"]) != 0 {
+		t.Error("Expected incomplete target not to be updated")
+	}
+	select {
+	case event := <-fakeRecorder.Events:
+		if !strings.Contains(event, EventReasonReplicationFailed) || !strings.Contains(event, "tls.key") {
+			t.Errorf("Expected ReplicationFailed event mentioning tls.key, got %q", event)
+		}
+	default:
+		t.Error("Expected a ReplicationFailed event")
+	}
+
+	// The private key does not belong to the certificate, so the TLS target is not updated
+	req = ctrl.Request{NamespacedName: types.NamespacedName{Name: "mismatched", Namespace: "app"}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, updated); err != nil {
+		t.Fatalf("Failed to get target Secret: %v", err)
+	}
+	if len(updated.Data["tls.crt"]) != 0 {
+		t.Error("Expected mismatched target not to be updated")
+	}
+	select {
+	case event := <-fakeRecorder.Events:
+		if !strings.Contains(event, EventReasonInvalidTLSPair) {
+			t.Errorf("Expected InvalidTLSPair event, got %q", event)
+		}
+	default:
+		t.Error("Expected an InvalidTLSPair event")
+	}
+}
+
+func TestSecretReplicatorReconciler_PullWithNamespaceSelector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "db-credentials",
+			Namespace: "production",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicatableFromSelector: "env=staging",
+			},
+		},
+		Data: map[string][]byte{"password": []byte("prodpass")},
+	}
+	newNamespace := func(name, env string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"env": env}}}
+	}
+	newTarget := func(namespace string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "db-credentials",
+				Namespace: namespace,
+				Annotations: map[string]string{
+					replicator.AnnotationReplicateFrom: "production/db-credentials",
+				},
+			},
+		}
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			source,
+			newNamespace("team-a", "staging"),
+			newNamespace("team-b", "development"),
+			newTarget("team-a"),
+			newTarget("team-b"),
+		).
+		Build()
+
+	reconciler := &SecretReplicatorReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+	}
+
+	tests := []struct {
+		namespace   string
+		expectData  bool
+		description string
+	}{
+		{namespace: "team-a", expectData: true, description: "namespace labels match the selector"},
+		{namespace: "team-b", expectData: false, description: "namespace labels do not match the selector"},
+	}
+
+	for _, tt := range tests {
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "db-credentials", Namespace: tt.namespace}}
+		if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+
+		updated := &corev1.Secret{}
+		if err := fakeClient.Get(context.Background(), req.NamespacedName, updated); err != nil {
+			t.Fatalf("Failed to get target Secret: %v", err)
+		}
+		if hasData := string(updated.Data["password"]) == "prodpass"; hasData != tt.expectData {
+			t.Errorf("%s: expected replicated data = %v, got %v", tt.description, tt.expectData, hasData)
+		}
+	}
+}
+
+func TestSecretReplicatorReconciler_MarksStaleTargetWhenSourceDeleted(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "db-credentials",
+			Namespace: "production",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicatableFromNamespaces: "staging",
+			},
+		},
+		Data: map[string][]byte{"password": []byte("prodpass")},
+	}
+	target := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "db-credentials",
+			Namespace: "staging",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicateFrom: "production/db-credentials",
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(source, target).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &SecretReplicatorReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: recorder,
+	}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "db-credentials", Namespace: "staging"}}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	<-recorder.Events // ReplicationSucceeded
+
+	// Delete the source; the target keeps its snapshot and is marked as stale
+	if err := fakeClient.Delete(ctx, source); err != nil {
+		t.Fatalf("Failed to delete source: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := reconciler.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+
+	updated := &corev1.Secret{}
+	if err := fakeClient.Get(ctx, req.NamespacedName, updated); err != nil {
+		t.Fatalf("Failed to get target Secret: %v", err)
+	}
+	if string(updated.Data["password"]) != "prodpass" {
+		t.Error("Expected target to keep its snapshot")
+	}
+	if !replicator.IsStale(updated) {
+		t.Error("Expected target to be marked with source-deleted-at")
+	}
+
+	// The warning is only emitted once
+	if len(recorder.Events) != 1 {
+		t.Fatalf("Expected exactly one event, got %d", len(recorder.Events))
+	}
+	if event := <-recorder.Events; !strings.Contains(event, EventReasonSourceDeleted) {
+		t.Errorf("Expected SourceDeleted event, got %q", event)
+	}
+
+	// Once the source is recreated, the target is no longer stale
+	source.ResourceVersion = ""
+	if err := fakeClient.Create(ctx, source); err != nil {
+		t.Fatalf("Failed to recreate source: %v", err)
+	}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := fakeClient.Get(ctx, req.NamespacedName, updated); err != nil {
+		t.Fatalf("Failed to get target Secret: %v", err)
+	}
+	if replicator.IsStale(updated) {
+		t.Error("Expected source-deleted-at to be removed after successful replication")
+	}
+}
+
+func TestSecretReplicatorReconciler_PullConsentExpiry(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	until := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "db-credentials",
+			Namespace: "production",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicatableFromNamespaces: "staging",
+				replicator.AnnotationReplicatableUntil:          until.Format(time.RFC3339),
+			},
+		},
+		Data: map[string][]byte{"password": []byte("prodpass")},
+	}
+	target := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "db-credentials",
+			Namespace: "staging",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicateFrom: "production/db-credentials",
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(source, target).Build()
+	clock := &MockClock{currentTime: until.Add(-time.Hour)}
+	reconciler := &SecretReplicatorReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+		Clock:         clock,
+	}
+
+	// Before the consent ends, the target is synced and requeued for the expiry
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "db-credentials", Namespace: "staging"}}
+	result, err := reconciler.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if result.RequeueAfter != time.Hour {
+		t.Errorf("Expected requeue at consent expiry after 1h, got %v", result.RequeueAfter)
+	}
+
+	// Afterwards, the target keeps its snapshot and is marked as stale
+	source.Data["password"] = []byte("newpass")
+	if err := fakeClient.Update(ctx, source); err != nil {
+		t.Fatalf("Failed to update source: %v", err)
+	}
+	clock.currentTime = until
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	updated := &corev1.Secret{}
+	if err := fakeClient.Get(ctx, req.NamespacedName, updated); err != nil {
+		t.Fatalf("Failed to get target Secret: %v", err)
+	}
+	if string(updated.Data["password"]) != "prodpass" {
+		t.Errorf("Expected target to keep its snapshot, got %q", updated.Data["password"])
+	}
+	if updated.Annotations[replicator.AnnotationConsentExpiredAt] == "" {
+		t.Error("Expected target to be marked with consent-expired-at")
+	}
+
+	// Extending the consent resumes the replication
+	source.Annotations[replicator.AnnotationReplicatableUntil] = until.AddDate(1, 0, 0).Format(time.RFC3339)
+	if err := fakeClient.Update(ctx, source); err != nil {
+		t.Fatalf("Failed to update source: %v", err)
+	}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := fakeClient.Get(ctx, req.NamespacedName, updated); err != nil {
+		t.Fatalf("Failed to get target Secret: %v", err)
+	}
+	if string(updated.Data["password"]) != "newpass" || replicator.IsStale(updated) {
+		t.Errorf("Expected target to be synced again, got %q (stale: %v)", updated.Data["password"], replicator.IsStale(updated))
+	}
+}
+
+func TestSecretReplicatorReconciler_PushConsentExpiry(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	until := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "shared",
+			Namespace:  "team-a",
+			Finalizers: []string{replicator.FinalizerReplicateToCleanup},
+			Annotations: map[string]string{
+				replicator.AnnotationReplicateTo:       "team-b",
+				replicator.AnnotationReplicatableUntil: until.Format(time.RFC3339),
+			},
+		},
+		Data: map[string][]byte{"token": []byte("new")},
+	}
+	target := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "shared",
+			Namespace:   "team-b",
+			Annotations: map[string]string{replicator.AnnotationReplicatedFrom: "team-a/shared"},
+		},
+		Data: map[string][]byte{"token": []byte("old")},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(source, target).Build()
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &SecretReplicatorReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: recorder,
+		Clock:         &MockClock{currentTime: until.Add(time.Minute)},
+	}
+
+	ctx := context.Background()
+	if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(source)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	updated := &corev1.Secret{}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(target), updated); err != nil {
+		t.Fatalf("Failed to get target Secret: %v", err)
+	}
+	if string(updated.Data["token"]) != "old" {
+		t.Errorf("Expected target not to be updated after consent expiry, got %q", updated.Data["token"])
+	}
+	if updated.Annotations[replicator.AnnotationConsentExpiredAt] == "" {
+		t.Error("Expected target to be marked with consent-expired-at")
+	}
+
+	denied := 0
+	for len(recorder.Events) > 0 {
+		if strings.Contains(<-recorder.Events, EventReasonReplicationDenied) {
+			denied++
+		}
+	}
+	if denied != 2 {
+		t.Errorf("Expected ReplicationDenied events on source and target, got %d", denied)
+	}
+}
+
+func TestSecretReplicatorReconciler_SourceWithoutAllowlist(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	// Source without replicatable-from-namespaces annotation
+	sourceSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "db-credentials",
+			Namespace: "production",
+			// No replicatable-from-namespaces annotation
+		},
+		Data: map[string][]byte{
+			"password": []byte("secret"),
+		},
+	}
+
+	targetSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "db-credentials",
+			Namespace: "staging",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicateFrom: "production/db-credentials",
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(sourceSecret, targetSecret).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+
+	reconciler := &SecretReplicatorReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: recorder,
+	}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: targetSecret.Namespace,
+			Name:      targetSecret.Name,
+		},
+	}
+
+	_, err := reconciler.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Errorf("Reconcile() error = %v", err)
+	}
+
+	// Check that target secret was NOT updated (no data replicated)
+	updatedSecret := &corev1.Secret{}
+	err = fakeClient.Get(context.Background(), types.NamespacedName{
+		Namespace: targetSecret.Namespace,
+		Name:      targetSecret.Name,
+	}, updatedSecret)
+	if err != nil {
+		t.Fatalf("Failed to get target secret: %v", err)
+	}
+
+	// Data should still be empty (replication denied)
+	if len(updatedSecret.Data) > 0 {
+		t.Error("Expected target secret to remain empty when source has no allowlist")
+	}
+
+	// Check for warning event
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, "Warning") {
+			t.Errorf("Expected warning event, got: %s", event)
+		}
+	default:
+		t.Error("Expected a warning event for denied replication")
+	}
+}
+
+func TestSecretReplicatorReconciler_PushToMultipleNamespaces(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	sourceSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "shared-secret",
+			Namespace: "production",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicateTo: "staging,development,qa",
+			},
+		},
+		Data: map[string][]byte{
+			"api-key": []byte("secret-key"),
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(sourceSecret).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+
+	reconciler := &SecretReplicatorReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: recorder,
+	}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: sourceSecret.Namespace,
+			Name:      sourceSecret.Name,
+		},
+	}
+
+	_, err := reconciler.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Errorf("Reconcile() error = %v", err)
+	}
+
+	// Check that secrets were created in all target namespaces
+	targetNamespaces := []string{"staging", "development", "qa"}
+	for _, ns := range targetNamespaces {
+		targetSecret := &corev1.Secret{}
+		err = fakeClient.Get(context.Background(), types.NamespacedName{
+			Namespace: ns,
+			Name:      sourceSecret.Name,
+		}, targetSecret)
+		if err != nil {
+			t.Errorf("Expected secret to be created in %s, got error: %v", ns, err)
+			continue
+		}
+
+		// Verify data was replicated
+		if string(targetSecret.Data["api-key"]) != "secret-key" {
+			t.Errorf("Secret in %s has wrong data", ns)
+		}
+
+		// Verify replicated-from annotation
+		expectedSource := "production/shared-secret"
+		if targetSecret.Annotations[replicator.AnnotationReplicatedFrom] != expectedSource {
+			t.Errorf("Secret in %s has wrong replicated-from annotation", ns)
+		}
+	}
+}
+
+func TestSecretReplicatorReconciler_PushToAllNamespaces(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ca-bundle",
+			Namespace: "infra",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicateTo:                "*",
+				replicator.AnnotationReplicateExcludeNamespaces: "sandbox-*",
+			},
+		},
+		Data: map[string][]byte{"ca.crt": []byte("ca")},
+	}
+
+	var objs []client.Object
+	for _, name := range []string{"infra", "app-a", "app-b", "sandbox-1", "kube-system"} {
+		objs = append(objs, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	objs = append(objs, source)
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		Build()
+
+	cfg := config.NewDefaultConfig()
+	cfg.Replication.DeniedNamespaces = []string{"kube-*"}
+
+	reconciler := &SecretReplicatorReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        cfg,
+		EventRecorder: record.NewFakeRecorder(10),
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "ca-bundle", Namespace: "infra"}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	expected := map[string]bool{
+		"app-a":       true,
+		"app-b":       true,
+		"sandbox-1":   false, // excluded by annotation
+		"kube-system": false, // denied by cluster config
+	}
+	for namespace, expectPushed := range expected {
+		target := &corev1.Secret{}
+		err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "ca-bundle", Namespace: namespace}, target)
+		if pushed := err == nil; pushed != expectPushed {
+			t.Errorf("namespace %s: expected pushed = %v, got %v (err: %v)", namespace, expectPushed, pushed, err)
+		}
+	}
+
+	// New namespaces trigger reconciliation of wildcard sources
+	requests := reconciler.findWildcardPushSources(context.Background(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "app-c"}})
+	if len(requests) != 1 || requests[0].NamespacedName != req.NamespacedName {
+		t.Errorf("Expected wildcard source to be enqueued for new namespace, got %v", requests)
+	}
+}
+
+func TestSecretReplicatorReconciler_PullFromDeniedNamespace(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ca",
+			Namespace: "kube-system",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicatableFromNamespaces: "*",
+			},
+		},
+		Data: map[string][]byte{"ca.crt": []byte("ca")},
+	}
+	target := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ca",
+			Namespace: "app",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicateFrom: "kube-system/ca",
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(source, target).
+		Build()
+	recorder := record.NewFakeRecorder(10)
+
+	cfg := config.NewDefaultConfig()
+	cfg.Replication.DeniedNamespaces = []string{"kube-*"}
+	reconciler := &SecretReplicatorReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        cfg,
+		EventRecorder: recorder,
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "ca", Namespace: "app"}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
 	}
-	if !foundDevelopment {
-		t.Error("Did not find reconcile request for development/db-credentials")
+
+	updated := &corev1.Secret{}
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, updated); err != nil {
+		t.Fatalf("Failed to get target: %v", err)
+	}
+	if len(updated.Data) != 0 {
+		t.Errorf("Expected no data to be pulled from a denied namespace, got %v", updated.Data)
+	}
+
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, EventReasonReplicationDenied) || !strings.Contains(event, "denylist") {
+			t.Errorf("Expected ReplicationDenied event for denylist, got %q", event)
+		}
+	default:
+		t.Error("Expected ReplicationDenied event")
 	}
 }
 
-func TestSecretReplicatorReconciler_SourceWithoutAllowlist(t *testing.T) {
+func TestSecretReplicatorReconciler_PushWithTemplatedName(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = corev1.AddToScheme(scheme)
 
-	// Source without replicatable-from-namespaces annotation
-	sourceSecret := &corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "db-credentials",
-			Namespace: "production",
-			// No replicatable-from-namespaces annotation
-		},
-		Data: map[string][]byte{
-			"password": []byte("secret"),
-		},
-	}
-
-	targetSecret := &corev1.Secret{
+	source := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "db-credentials",
-			Namespace: "staging",
+			Name:      "registry",
+			Namespace: "infra",
 			Annotations: map[string]string{
-				replicator.AnnotationReplicateFrom: "production/db-credentials",
+				replicator.AnnotationReplicateTo: "team-a,team-b",
+				replicator.AnnotationReplicateAs: "{{ .Namespace }}-registry-creds",
 			},
 		},
+		Data: map[string][]byte{".dockerconfigjson": []byte("{}")},
 	}
 
 	fakeClient := fake.NewClientBuilder().
 		WithScheme(scheme).
-		WithObjects(sourceSecret, targetSecret).
+		WithObjects(source).
 		Build()
 
-	recorder := record.NewFakeRecorder(10)
-
 	reconciler := &SecretReplicatorReconciler{
 		Client:        fakeClient,
 		Scheme:        scheme,
 		Config:        config.NewDefaultConfig(),
-		EventRecorder: recorder,
-	}
-
-	req := ctrl.Request{
-		NamespacedName: types.NamespacedName{
-			Namespace: targetSecret.Namespace,
-			Name:      targetSecret.Name,
-		},
+		EventRecorder: record.NewFakeRecorder(10),
 	}
 
-	_, err := reconciler.Reconcile(context.Background(), req)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "registry", Namespace: "infra"}}
+	result, err := reconciler.Reconcile(context.Background(), req)
 	if err != nil {
-		t.Errorf("Reconcile() error = %v", err)
+		t.Fatalf("Reconcile() error = %v", err)
 	}
-
-	// Check that target secret was NOT updated (no data replicated)
-	updatedSecret := &corev1.Secret{}
-	err = fakeClient.Get(context.Background(), types.NamespacedName{
-		Namespace: targetSecret.Namespace,
-		Name:      targetSecret.Name,
-	}, updatedSecret)
-	if err != nil {
-		t.Fatalf("Failed to get target secret: %v", err)
+	// An invalid template is not fixed by retrying
+	if result.RequeueAfter != 0 || reconciler.pushFailures.count() != 0 {
+		t.Errorf("Expected no retry, got RequeueAfter %v and %d failed targets", result.RequeueAfter, reconciler.pushFailures.count())
 	}
 
-	// Data should still be empty (replication denied)
-	if len(updatedSecret.Data) > 0 {
-		t.Error("Expected target secret to remain empty when source has no allowlist")
+	for _, namespace := range []string{"team-a", "team-b"} {
+		target := &corev1.Secret{}
+		key := types.NamespacedName{Name: namespace + "-registry-creds", Namespace: namespace}
+		if err := fakeClient.Get(context.Background(), key, target); err != nil {
+			t.Fatalf("Expected templated target %s: %v", key, err)
+		}
+		if target.Annotations[replicator.AnnotationReplicatedFrom] != "infra/registry" {
+			t.Errorf("Expected replicated-from infra/registry, got %q", target.Annotations[replicator.AnnotationReplicatedFrom])
+		}
 	}
 
-	// Check for warning event
-	select {
-	case event := <-recorder.Events:
-		if !strings.Contains(event, "Warning") {
-			t.Errorf("Expected warning event, got: %s", event)
+	// Deleting the source cleans up the templated targets
+	var updated corev1.Secret
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+		t.Fatalf("Failed to get source: %v", err)
+	}
+	if err := fakeClient.Delete(context.Background(), &updated); err != nil {
+		t.Fatalf("Failed to delete source: %v", err)
+	}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	for _, namespace := range []string{"team-a", "team-b"} {
+		key := types.NamespacedName{Name: namespace + "-registry-creds", Namespace: namespace}
+		if err := fakeClient.Get(context.Background(), key, &corev1.Secret{}); !apierrors.IsNotFound(err) {
+			t.Errorf("Expected target %s to be deleted, got %v", key, err)
 		}
-	default:
-		t.Error("Expected a warning event for denied replication")
 	}
 }
 
-func TestSecretReplicatorReconciler_PushToMultipleNamespaces(t *testing.T) {
+func TestSecretReplicatorReconciler_PushWithInvalidNameTemplate(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = corev1.AddToScheme(scheme)
 
-	sourceSecret := &corev1.Secret{
+	source := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "shared-secret",
-			Namespace: "production",
+			Name:      "registry",
+			Namespace: "infra",
 			Annotations: map[string]string{
-				replicator.AnnotationReplicateTo: "staging,development,qa",
+				replicator.AnnotationReplicateTo: "team-a",
+				replicator.AnnotationReplicateAs: "{{ .Namespace }}_creds",
 			},
 		},
-		Data: map[string][]byte{
-			"api-key": []byte("secret-key"),
-		},
 	}
 
 	fakeClient := fake.NewClientBuilder().
 		WithScheme(scheme).
-		WithObjects(sourceSecret).
+		WithObjects(source).
 		Build()
-
 	recorder := record.NewFakeRecorder(10)
 
 	reconciler := &SecretReplicatorReconciler{
@@ -621,41 +1569,26 @@ func TestSecretReplicatorReconciler_PushToMultipleNamespaces(t *testing.T) {
 		EventRecorder: recorder,
 	}
 
-	req := ctrl.Request{
-		NamespacedName: types.NamespacedName{
-			Namespace: sourceSecret.Namespace,
-			Name:      sourceSecret.Name,
-		},
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "registry", Namespace: "infra"}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
 	}
 
-	_, err := reconciler.Reconcile(context.Background(), req)
-	if err != nil {
-		t.Errorf("Reconcile() error = %v", err)
+	var secrets corev1.SecretList
+	if err := fakeClient.List(context.Background(), &secrets, client.InNamespace("team-a")); err != nil {
+		t.Fatalf("Failed to list secrets: %v", err)
+	}
+	if len(secrets.Items) != 0 {
+		t.Errorf("Expected no target to be created, got %d", len(secrets.Items))
 	}
 
-	// Check that secrets were created in all target namespaces
-	targetNamespaces := []string{"staging", "development", "qa"}
-	for _, ns := range targetNamespaces {
-		targetSecret := &corev1.Secret{}
-		err = fakeClient.Get(context.Background(), types.NamespacedName{
-			Namespace: ns,
-			Name:      sourceSecret.Name,
-		}, targetSecret)
-		if err != nil {
-			t.Errorf("Expected secret to be created in %s, got error: %v", ns, err)
-			continue
-		}
-
-		// Verify data was replicated
-		if string(targetSecret.Data["api-key"]) != "secret-key" {
-			t.Errorf("Secret in %s has wrong data", ns)
-		}
-
-		// Verify replicated-from annotation
-		expectedSource := "production/shared-secret"
-		if targetSecret.Annotations[replicator.AnnotationReplicatedFrom] != expectedSource {
-			t.Errorf("Secret in %s has wrong replicated-from annotation", ns)
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, EventReasonPushFailed) {
+			t.Errorf("Expected PushFailed event, got %q", event)
 		}
+	default:
+		t.Error("Expected PushFailed event")
 	}
 }
 
@@ -911,6 +1844,81 @@ func TestSecretReplicatorReconciler_HandleDeletion(t *testing.T) {
 	}
 }
 
+func TestSecretReplicatorReconciler_HandleDeletionSkipsTerminatingNamespace(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "push-secret",
+			Namespace:         "production",
+			DeletionTimestamp: &metav1.Time{Time: metav1.Now().Time},
+			Finalizers:        []string{replicator.FinalizerReplicateToCleanup},
+			Annotations: map[string]string{
+				replicator.AnnotationReplicateTo: "staging,development",
+			},
+		},
+	}
+	terminating := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "staging"},
+		Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating},
+	}
+	active := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "development"}}
+	replicated := func(namespace string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "push-secret",
+				Namespace: namespace,
+				Annotations: map[string]string{
+					replicator.AnnotationReplicatedFrom: "production/push-secret",
+				},
+			},
+		}
+	}
+
+	// Deletes in the terminating namespace fail, like the API server rejects them while the
+	// namespace is being finalized
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(source, terminating, active, replicated("staging"), replicated("development")).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				if obj.GetNamespace() == "staging" {
+					return fmt.Errorf("namespace staging is terminating")
+				}
+				return c.Delete(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	reconciler := &SecretReplicatorReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: source.Namespace, Name: source.Name}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	// The copy in the terminating namespace is left to the namespace deletion
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Namespace: "staging", Name: "push-secret"}, &corev1.Secret{}); err != nil {
+		t.Errorf("expected Secret in terminating namespace to be left alone, got %v", err)
+	}
+	err := fakeClient.Get(context.Background(), types.NamespacedName{Namespace: "development", Name: "push-secret"}, &corev1.Secret{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected Secret in active namespace to be deleted, got %v", err)
+	}
+
+	// The source is no longer blocked by its finalizer
+	updatedSource := &corev1.Secret{}
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, updatedSource); err == nil && replicator.HasFinalizer(updatedSource) {
+		t.Error("expected finalizer to be removed from source secret")
+	}
+}
+
 func TestSecretReplicatorReconciler_HandleDeletionWithoutFinalizer(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = corev1.AddToScheme(scheme)
@@ -1382,9 +2390,160 @@ func TestSecretReplicatorReconciler_PushUpdateExistingOwnedSecret(t *testing.T)
 
 	fakeClient := fake.NewClientBuilder().
 		WithScheme(scheme).
-		WithObjects(sourceSecret, targetSecret).
+		WithObjects(sourceSecret, targetSecret).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+
+	reconciler := &SecretReplicatorReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: recorder,
+	}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: sourceSecret.Namespace,
+			Name:      sourceSecret.Name,
+		},
+	}
+
+	_, err := reconciler.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Errorf("Reconcile() error = %v", err)
+	}
+
+	// Verify target was updated with new value
+	updatedTarget := &corev1.Secret{}
+	err = fakeClient.Get(context.Background(), types.NamespacedName{
+		Namespace: "staging",
+		Name:      sourceSecret.Name,
+	}, updatedTarget)
+	if err != nil {
+		t.Fatalf("Failed to get target secret: %v", err)
+	}
+
+	if string(updatedTarget.Data["key"]) != "new-value" {
+		t.Errorf("Expected target secret data to be updated to 'new-value', got '%s'", string(updatedTarget.Data["key"]))
+	}
+}
+
+func TestSecretReplicatorReconciler_PushEmitsEventsOnTarget(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	sourceSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "push-secret",
+			Namespace: "production",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicateTo: "staging",
+			},
+		},
+		Data: map[string][]byte{"key": []byte("v1")},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(sourceSecret).
+		Build()
+	recorder := record.NewFakeRecorder(10)
+
+	reconciler := &SecretReplicatorReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: recorder,
+	}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "production", Name: "push-secret"}}
+	expectEvent := func(substr string) {
+		t.Helper()
+		select {
+		case event := <-recorder.Events:
+			if !strings.Contains(event, EventReasonReplicationSucceeded) || !strings.Contains(event, substr) {
+				t.Errorf("Expected ReplicationSucceeded event containing %q, got %q", substr, event)
+			}
+		default:
+			t.Errorf("Expected event containing %q", substr)
+		}
+	}
+
+	// Creating the target emits an event
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	expectEvent("Created by push replication from production/push-secret")
+
+	// A resync without data changes emits no event
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if len(recorder.Events) != 0 {
+		t.Errorf("Expected no event for unchanged data, got %q", <-recorder.Events)
+	}
+
+	// Changing the source data emits an update event
+	var source corev1.Secret
+	if err := fakeClient.Get(ctx, req.NamespacedName, &source); err != nil {
+		t.Fatalf("Failed to get source: %v", err)
+	}
+	source.Data["key"] = []byte("v2")
+	if err := fakeClient.Update(ctx, &source); err != nil {
+		t.Fatalf("Failed to update source: %v", err)
+	}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	expectEvent("Updated by push replication from production/push-secret")
+}
+
+func TestSecretReplicatorReconciler_SkipsIgnoredSecretTypes(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "production",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicatableFromNamespaces: "staging",
+				replicator.AnnotationReplicateTo:                "staging",
+			},
+		},
+		Data: map[string][]byte{"release": []byte("replicated")},
+	}
+	// A Helm release Secret with a misplaced replicate-from annotation
+	helmRelease := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sh.helm.release.v1.app.v1",
+			Namespace: "staging",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicateFrom: "production/app",
+			},
+		},
+		Type: "helm.sh/release.v1",
+		Data: map[string][]byte{"release": []byte("helm-state")},
+	}
+	// An existing push target of an ignored type, even with a replicated-from annotation
+	tokenTarget := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "staging",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicatedFrom: "production/app",
+			},
+		},
+		Type: corev1.SecretTypeServiceAccountToken,
+		Data: map[string][]byte{"token": []byte("sa-token")},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(source, helmRelease, tokenTarget).
 		Build()
-
 	recorder := record.NewFakeRecorder(10)
 
 	reconciler := &SecretReplicatorReconciler{
@@ -1394,30 +2553,118 @@ func TestSecretReplicatorReconciler_PushUpdateExistingOwnedSecret(t *testing.T)
 		EventRecorder: recorder,
 	}
 
-	req := ctrl.Request{
-		NamespacedName: types.NamespacedName{
-			Namespace: sourceSecret.Namespace,
-			Name:      sourceSecret.Name,
-		},
+	for _, key := range []types.NamespacedName{
+		{Name: "sh.helm.release.v1.app.v1", Namespace: "staging"},
+		{Name: "app", Namespace: "production"},
+	} {
+		if _, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key}); err != nil {
+			t.Fatalf("Reconcile(%s) error = %v", key, err)
+		}
 	}
 
-	_, err := reconciler.Reconcile(context.Background(), req)
-	if err != nil {
-		t.Errorf("Reconcile() error = %v", err)
+	updated := &corev1.Secret{}
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "sh.helm.release.v1.app.v1", Namespace: "staging"}, updated); err != nil {
+		t.Fatalf("Failed to get Helm release Secret: %v", err)
+	}
+	if string(updated.Data["release"]) != "helm-state" {
+		t.Errorf("Expected Helm release Secret to be untouched, got %q", string(updated.Data["release"]))
 	}
 
-	// Verify target was updated with new value
-	updatedTarget := &corev1.Secret{}
-	err = fakeClient.Get(context.Background(), types.NamespacedName{
-		Namespace: "staging",
-		Name:      sourceSecret.Name,
-	}, updatedTarget)
-	if err != nil {
-		t.Fatalf("Failed to get target secret: %v", err)
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "app", Namespace: "staging"}, updated); err != nil {
+		t.Fatalf("Failed to get token Secret: %v", err)
+	}
+	if _, ok := updated.Data["release"]; ok || string(updated.Data["token"]) != "sa-token" {
+		t.Errorf("Expected service account token Secret to be untouched, got %v", updated.Data)
 	}
 
-	if string(updatedTarget.Data["key"]) != "new-value" {
-		t.Errorf("Expected target secret data to be updated to 'new-value', got '%s'", string(updatedTarget.Data["key"]))
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, EventReasonPushFailed) || !strings.Contains(event, "ignored type") {
+			t.Errorf("Expected PushFailed event for ignored type, got %q", event)
+		}
+	default:
+		t.Error("Expected PushFailed event for ignored target type")
+	}
+}
+
+func TestSecretReplicatorReconciler_DriftPolicy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	// A target whose data was modified after replication ("old" was replicated, "local" was set manually)
+	driftedTarget := func(annotations map[string]string) *corev1.Secret {
+		annotations[replicator.AnnotationReplicatedChecksum] = replicator.DataChecksum(map[string][]byte{"key": []byte("old")})
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-secret", Namespace: "staging", Annotations: annotations},
+			Data:       map[string][]byte{"key": []byte("local")},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		policy        string
+		pull          bool
+		expectedValue string
+	}{
+		{name: "pull overwrite", policy: config.DriftPolicyOverwrite, pull: true, expectedValue: "new"},
+		{name: "pull warn", policy: config.DriftPolicyWarn, pull: true, expectedValue: "local"},
+		{name: "push overwrite", policy: config.DriftPolicyOverwrite, expectedValue: "new"},
+		{name: "push warn", policy: config.DriftPolicyWarn, expectedValue: "local"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "app-secret", Namespace: "production", Annotations: map[string]string{}},
+				Data:       map[string][]byte{"key": []byte("new")},
+			}
+			var target *corev1.Secret
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app-secret", Namespace: "staging"}}
+			if tt.pull {
+				source.Annotations[replicator.AnnotationReplicatableFromNamespaces] = "staging"
+				target = driftedTarget(map[string]string{replicator.AnnotationReplicateFrom: "production/app-secret"})
+			} else {
+				source.Annotations[replicator.AnnotationReplicateTo] = "staging"
+				target = driftedTarget(map[string]string{replicator.AnnotationReplicatedFrom: "production/app-secret"})
+				req.Namespace = "production"
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(source, target).
+				Build()
+			recorder := record.NewFakeRecorder(10)
+
+			cfg := config.NewDefaultConfig()
+			cfg.Replication.DriftPolicy = tt.policy
+			reconciler := &SecretReplicatorReconciler{
+				Client:        fakeClient,
+				Scheme:        scheme,
+				Config:        cfg,
+				EventRecorder: recorder,
+			}
+
+			if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			updated := &corev1.Secret{}
+			if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "app-secret", Namespace: "staging"}, updated); err != nil {
+				t.Fatalf("Failed to get target: %v", err)
+			}
+			if string(updated.Data["key"]) != tt.expectedValue {
+				t.Errorf("Expected target value %q, got %q", tt.expectedValue, string(updated.Data["key"]))
+			}
+
+			select {
+			case event := <-recorder.Events:
+				if !strings.Contains(event, EventReasonDriftDetected) {
+					t.Errorf("Expected DriftDetected event, got %q", event)
+				}
+			default:
+				t.Error("Expected DriftDetected event")
+			}
+		})
 	}
 }
 
@@ -1557,6 +2804,25 @@ func TestSecretReplicatorReconciler_PushCreateError(t *testing.T) {
 	default:
 		t.Error("Expected a warning event for failed create")
 	}
+	if reconciler.pushFailures.count() != 1 {
+		t.Fatalf("Expected 1 failed target, got %d", reconciler.pushFailures.count())
+	}
+
+	// Removing replicate-to stops the retries of the failed target
+	var updated corev1.Secret
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+		t.Fatalf("Failed to get source Secret: %v", err)
+	}
+	delete(updated.Annotations, replicator.AnnotationReplicateTo)
+	if err := fakeClient.Update(context.Background(), &updated); err != nil {
+		t.Fatalf("Failed to update source Secret: %v", err)
+	}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if reconciler.pushFailures.count() != 0 {
+		t.Errorf("Expected no failed targets after removing replicate-to, got %d", reconciler.pushFailures.count())
+	}
 }
 
 func TestSecretReplicatorReconciler_PushUpdateOwnedSecretError(t *testing.T) {
@@ -2088,3 +3354,417 @@ func TestSecretReplicatorReconciler_PullReplicationGetSourceError(t *testing.T)
 		t.Error("Expected error from Reconcile when getting source secret fails (not NotFound)")
 	}
 }
+
+func TestSecretReplicatorReconciler_ReplicationBoundaries(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	namespaces := []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "production", Labels: map[string]string{"env": "prod"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "production-eu", Labels: map[string]string{"env": "prod"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "development", Labels: map[string]string{"env": "dev"}}},
+	}
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "db",
+			Namespace: "production",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicateTo:                "production-eu,development",
+				replicator.AnnotationReplicatableFromNamespaces: "*",
+			},
+		},
+		Data: map[string][]byte{"password": []byte("secret")},
+	}
+	pullTarget := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "db-pulled",
+			Namespace:   "development",
+			Annotations: map[string]string{replicator.AnnotationReplicateFrom: "production/db"},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(append(namespaces, source, pullTarget)...).
+		Build()
+	recorder := record.NewFakeRecorder(10)
+
+	cfg := config.NewDefaultConfig()
+	cfg.Replication.Boundaries = []config.ReplicationBoundary{{Name: "prod-stays-prod", From: "env=prod", To: "env!=prod"}}
+	reconciler := &SecretReplicatorReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        cfg,
+		EventRecorder: recorder,
+	}
+
+	// Push: only the target within the boundary receives the Secret
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "db", Namespace: "production"}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	pushed := &corev1.Secret{}
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "db", Namespace: "production-eu"}, pushed); err != nil {
+		t.Errorf("Expected Secret to be pushed to production-eu: %v", err)
+	}
+	err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "db", Namespace: "development"}, pushed)
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("Expected no Secret to be pushed across the boundary, got %v", err)
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, EventReasonReplicationDenied) || !strings.Contains(event, "prod-stays-prod") {
+			t.Errorf("Expected ReplicationDenied event for boundary, got %q", event)
+		}
+	default:
+		t.Error("Expected ReplicationDenied event for push")
+	}
+
+	// Pull: the consent of the source does not override the boundary
+	req = ctrl.Request{NamespacedName: types.NamespacedName{Name: "db-pulled", Namespace: "development"}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	pulled := &corev1.Secret{}
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, pulled); err != nil {
+		t.Fatalf("Failed to get pull target: %v", err)
+	}
+	if len(pulled.Data) != 0 {
+		t.Errorf("Expected no data to be pulled across the boundary, got %v", pulled.Data)
+	}
+}
+
+func TestSecretReplicatorReconciler_PushWithConfiguredLabelsAndType(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "registry",
+			Namespace:   "production",
+			Labels:      map[string]string{"app": "registry"},
+			Annotations: map[string]string{replicator.AnnotationReplicateTo: "staging"},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{"token": []byte("value")},
+	}
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "registry",
+			Namespace:   "development",
+			Annotations: map[string]string{replicator.AnnotationReplicatedFrom: "production/registry"},
+		},
+		Type: corev1.SecretTypeOpaque,
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(source, existing).Build()
+	cfg := config.NewDefaultConfig()
+	cfg.Replication.PushedLabels = map[string]string{"managed-by": "iso-operator"}
+	cfg.Replication.PushedType = "example.com/replicated"
+	reconciler := &SecretReplicatorReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        cfg,
+		EventRecorder: record.NewFakeRecorder(10),
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "registry", Namespace: "production"}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	created := &corev1.Secret{}
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "registry", Namespace: "staging"}, created); err != nil {
+		t.Fatalf("Expected Secret to be pushed: %v", err)
+	}
+	if created.Type != "example.com/replicated" {
+		t.Errorf("Expected pushed type override, got %q", created.Type)
+	}
+	if created.Labels["managed-by"] != "iso-operator" || created.Labels["app"] != "registry" {
+		t.Errorf("Expected configured and source labels, got %v", created.Labels)
+	}
+
+	// Existing targets receive the labels, but keep their immutable type
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, source); err != nil {
+		t.Fatalf("Failed to get source: %v", err)
+	}
+	source.Annotations[replicator.AnnotationReplicateTo] = "development"
+	if err := fakeClient.Update(context.Background(), source); err != nil {
+		t.Fatalf("Failed to update source: %v", err)
+	}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	updated := &corev1.Secret{}
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "registry", Namespace: "development"}, updated); err != nil {
+		t.Fatalf("Failed to get existing target: %v", err)
+	}
+	if updated.Type != corev1.SecretTypeOpaque {
+		t.Errorf("Expected existing target to keep its type, got %q", updated.Type)
+	}
+	if updated.Labels["managed-by"] != "iso-operator" {
+		t.Errorf("Expected configured label on existing target, got %v", updated.Labels)
+	}
+}
+
+func TestSecretReplicatorReconciler_OwnerLabels(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	newSource := func(name string, annotations map[string]string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "production", Annotations: annotations},
+			Data:       map[string][]byte{name: []byte("value")},
+		}
+	}
+	db := newSource("db", map[string]string{
+		replicator.AnnotationReplicatableFromNamespaces: "staging",
+		replicator.AnnotationReplicateTo:                "development",
+	})
+	cache := newSource("cache", map[string]string{replicator.AnnotationReplicatableFromNamespaces: "staging"})
+	target := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "staging",
+			Annotations: map[string]string{replicator.AnnotationReplicateFrom: "production/db"},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(db, cache, target).Build()
+	cfg := config.NewDefaultConfig()
+	cfg.Replication.OwnerLabels = config.OwnerLabelsConfig{
+		SourceNamespace: "iso.gtrfc.com/source-namespace",
+		SourceName:      "iso.gtrfc.com/source-name",
+		ManagedBy:       "app.kubernetes.io/managed-by",
+	}
+	reconciler := &SecretReplicatorReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        cfg,
+		EventRecorder: record.NewFakeRecorder(10),
+	}
+	ctx := context.Background()
+	reconcileAndGet := func(key types.NamespacedName, result types.NamespacedName) *corev1.Secret {
+		t.Helper()
+		if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		secret := &corev1.Secret{}
+		if err := fakeClient.Get(ctx, result, secret); err != nil {
+			t.Fatalf("Failed to get Secret %s: %v", result, err)
+		}
+		return secret
+	}
+
+	targetKey := types.NamespacedName{Name: "app", Namespace: "staging"}
+	pulled := reconcileAndGet(targetKey, targetKey)
+	expected := map[string]string{
+		"iso.gtrfc.com/source-namespace": "production",
+		"iso.gtrfc.com/source-name":      "db",
+		"app.kubernetes.io/managed-by":   config.OwnerLabelManagedBy,
+	}
+	if !maps.Equal(pulled.Labels, expected) {
+		t.Errorf("Expected owner labels %v on pull target, got %v", expected, pulled.Labels)
+	}
+
+	// With a second source, the source name no longer applies
+	pulled.Annotations[replicator.AnnotationReplicateFrom] = "production/db,production/cache"
+	if err := fakeClient.Update(ctx, pulled); err != nil {
+		t.Fatalf("Failed to update target: %v", err)
+	}
+	pulled = reconcileAndGet(targetKey, targetKey)
+	delete(expected, "iso.gtrfc.com/source-name")
+	if !maps.Equal(pulled.Labels, expected) {
+		t.Errorf("Expected owner labels %v on multi-source target, got %v", expected, pulled.Labels)
+	}
+
+	pushed := reconcileAndGet(types.NamespacedName{Name: "db", Namespace: "production"},
+		types.NamespacedName{Name: "db", Namespace: "development"})
+	if pushed.Labels["iso.gtrfc.com/source-name"] != "db" || pushed.Labels["app.kubernetes.io/managed-by"] != config.OwnerLabelManagedBy {
+		t.Errorf("Expected owner labels on pushed Secret, got %v", pushed.Labels)
+	}
+}
+
+func TestSecretReplicatorReconciler_PullFromSecretSource(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = isov1alpha1.AddToScheme(scheme)
+
+	tests := []struct {
+		name           string
+		disabled       bool
+		allowed        []string
+		expectedData   map[string]string
+		expectEventMsg string
+	}{
+		{
+			name:         "pull through SecretSource",
+			allowed:      []string{"team-*"},
+			expectedData: map[string]string{"ca.crt": "ca"},
+		},
+		{
+			name:           "SecretSource does not allow the target namespace",
+			allowed:        []string{"staging"},
+			expectEventMsg: EventReasonReplicationDenied,
+		},
+		{
+			name:           "feature disabled",
+			disabled:       true,
+			allowed:        []string{"team-*"},
+			expectEventMsg: "secretSources feature is disabled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secretSource := &isov1alpha1.SecretSource{
+				ObjectMeta: metav1.ObjectMeta{Name: "company-ca"},
+				Spec: isov1alpha1.SecretSourceSpec{
+					SecretRef:                  isov1alpha1.SecretReference{Namespace: "pki", Name: "company-ca"},
+					ReplicatableFromNamespaces: tt.allowed,
+				},
+			}
+			// The SecretSource grants the consent, the backing Secret has no annotations
+			source := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "company-ca", Namespace: "pki"},
+				Data:       map[string][]byte{"ca.crt": []byte("ca")},
+			}
+			target := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "company-ca",
+					Namespace: "team-a",
+					Annotations: map[string]string{
+						replicator.AnnotationReplicateFrom: "SecretSource/company-ca",
+					},
+				},
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(secretSource, source, target).
+				Build()
+			recorder := record.NewFakeRecorder(10)
+
+			cfg := config.NewDefaultConfig()
+			cfg.Features.SecretSources = !tt.disabled
+			reconciler := &SecretReplicatorReconciler{
+				Client:        fakeClient,
+				Scheme:        scheme,
+				Config:        cfg,
+				EventRecorder: recorder,
+			}
+
+			req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(target)}
+			if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			updated := &corev1.Secret{}
+			if err := fakeClient.Get(context.Background(), req.NamespacedName, updated); err != nil {
+				t.Fatalf("Failed to get target: %v", err)
+			}
+			if len(updated.Data) != len(tt.expectedData) {
+				t.Errorf("Expected data %v, got %v", tt.expectedData, updated.Data)
+			}
+			for key, value := range tt.expectedData {
+				if string(updated.Data[key]) != value {
+					t.Errorf("Expected %s=%q, got %q", key, value, updated.Data[key])
+				}
+			}
+			if tt.expectedData != nil && updated.Annotations[replicator.AnnotationReplicatedFrom] != "pki/company-ca" {
+				t.Errorf("Expected replicated-from to name the backing Secret, got %q",
+					updated.Annotations[replicator.AnnotationReplicatedFrom])
+			}
+
+			if tt.expectEventMsg != "" {
+				select {
+				case event := <-recorder.Events:
+					if !strings.Contains(event, tt.expectEventMsg) {
+						t.Errorf("Expected event containing %q, got %q", tt.expectEventMsg, event)
+					}
+				default:
+					t.Errorf("Expected event containing %q", tt.expectEventMsg)
+				}
+			}
+		})
+	}
+}
+
+func TestSecretReplicatorReconciler_RelocateSecretSource(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = isov1alpha1.AddToScheme(scheme)
+
+	secretSource := &isov1alpha1.SecretSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "company-ca"},
+		Spec: isov1alpha1.SecretSourceSpec{
+			SecretRef:                  isov1alpha1.SecretReference{Namespace: "pki", Name: "company-ca"},
+			ReplicatableFromNamespaces: []string{"*"},
+		},
+	}
+	oldSource := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "company-ca", Namespace: "pki"},
+		Data:       map[string][]byte{"ca.crt": []byte("old")},
+	}
+	newSource := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "root-ca", Namespace: "security"},
+		Data:       map[string][]byte{"ca.crt": []byte("new")},
+	}
+	target := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ca",
+			Namespace: "app",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicateFrom + ".ca.crt": "SecretSource/company-ca",
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(secretSource, oldSource, newSource, target).
+		Build()
+
+	cfg := config.NewDefaultConfig()
+	cfg.Features.SecretSources = true
+	reconciler := &SecretReplicatorReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        cfg,
+		EventRecorder: record.NewFakeRecorder(10),
+	}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(target)}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	// Changes of the backing Secret reach the targets of the SecretSource
+	if requests := reconciler.findTargetsForSource(ctx, oldSource); len(requests) != 1 || requests[0].NamespacedName != req.NamespacedName {
+		t.Errorf("Expected the target to be enqueued for the backing Secret, got %v", requests)
+	}
+
+	// Relocate the backing Secret
+	secretSource.Spec.SecretRef = isov1alpha1.SecretReference{Namespace: "security", Name: "root-ca"}
+	if err := fakeClient.Update(ctx, secretSource); err != nil {
+		t.Fatalf("Failed to update SecretSource: %v", err)
+	}
+	if requests := reconciler.findTargetsForSecretSource(ctx, secretSource); len(requests) != 1 || requests[0].NamespacedName != req.NamespacedName {
+		t.Fatalf("Expected the target to be enqueued for the SecretSource, got %v", requests)
+	}
+	if requests := reconciler.findTargetsForSource(ctx, oldSource); len(requests) != 0 {
+		t.Errorf("Expected no targets for the previous backing Secret, got %v", requests)
+	}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	updated := &corev1.Secret{}
+	if err := fakeClient.Get(ctx, req.NamespacedName, updated); err != nil {
+		t.Fatalf("Failed to get target: %v", err)
+	}
+	if string(updated.Data["ca.crt"]) != "new" {
+		t.Errorf("Expected data of the relocated Secret, got %q", updated.Data["ca.crt"])
+	}
+}
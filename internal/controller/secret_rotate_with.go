@@ -0,0 +1,179 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+// rotateWithIndexField is the cache index of Secrets by the Secret their rotate-with annotation references
+const rotateWithIndexField = "metadata.annotations.rotate-with"
+
+// indexRotateWith returns the Secret referenced by the rotate-with annotation for the cache index
+func indexRotateWith(obj client.Object) []string {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return nil
+	}
+	if key, ok := rotateWithKey(secret); ok {
+		return []string{key.String()}
+	}
+	return nil
+}
+
+// rotateWithKey returns the Secret referenced by the rotate-with annotation
+func rotateWithKey(secret *corev1.Secret) (client.ObjectKey, bool) {
+	ref := strings.TrimSpace(secret.Annotations[AnnotationRotateWith])
+	if ref == "" {
+		return client.ObjectKey{}, false
+	}
+	if namespace, name, found := strings.Cut(ref, "/"); found {
+		return client.ObjectKey{Namespace: strings.TrimSpace(namespace), Name: strings.TrimSpace(name)}, true
+	}
+	return client.ObjectKey{Namespace: secret.Namespace, Name: ref}, true
+}
+
+// isCoupledRotationDue returns true if the Secret referenced by the rotate-with annotation was
+// generated or rotated after this Secret. Secrets whose rotate-with references lead back to them
+// are not coupled, since each rotation would trigger the next one. A Secret in another namespace
+// must allow the namespace with the same consent annotations as for replication.
+func (r *SecretReconciler) isCoupledRotationDue(ctx context.Context, secret *corev1.Secret, generatedAt *time.Time) (bool, error) {
+	key, ok := rotateWithKey(secret)
+	if !ok || generatedAt == nil {
+		return false, nil
+	}
+
+	var other corev1.Secret
+	if err := r.Get(ctx, key, &other); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.FromContext(ctx).V(1).Info("Secret referenced by rotate-with not found", "secret", key)
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get Secret %s referenced by rotate-with: %w", key, err)
+	}
+	if other.Namespace != secret.Namespace {
+		if decision := r.decideRotateWithConsent(ctx, &other, secret.Namespace); !decision.Allowed {
+			r.EventRecorder.Event(secret, corev1.EventTypeWarning, EventReasonRotationFailed,
+				fmt.Sprintf("Secret %s referenced in %s does not allow this namespace, rotation coupling is ignored: %s",
+					key, AnnotationRotateWith, decision.Reason))
+			return false, nil
+		}
+	}
+	cycle, err := r.isRotateWithCycle(ctx, secret, &other)
+	if err != nil {
+		return false, err
+	}
+	if cycle {
+		r.EventRecorder.Event(secret, corev1.EventTypeWarning, EventReasonRotationFailed,
+			fmt.Sprintf("The %s references starting at Secret %s lead back to this Secret, rotation coupling is ignored",
+				AnnotationRotateWith, key))
+		return false, nil
+	}
+
+	otherGeneratedAt := r.getGeneratedAtTime(other.Annotations)
+	return otherGeneratedAt != nil && otherGeneratedAt.After(*generatedAt), nil
+}
+
+// isRotateWithCycle follows the rotate-with references from the referenced Secret and returns
+// true if they lead back to the Secret. Cycles that do not include the Secret are left to the
+// Secrets on the cycle.
+func (r *SecretReconciler) isRotateWithCycle(ctx context.Context, secret, referenced *corev1.Secret) (bool, error) {
+	self := client.ObjectKeyFromObject(secret)
+	visited := map[client.ObjectKey]bool{self: true}
+	current := referenced
+	for {
+		visited[client.ObjectKeyFromObject(current)] = true
+		next, ok := rotateWithKey(current)
+		if !ok {
+			return false, nil
+		}
+		if next == self {
+			return true, nil
+		}
+		if visited[next] {
+			return false, nil
+		}
+		current = &corev1.Secret{}
+		if err := r.Get(ctx, next, current); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to get Secret %s referenced by rotate-with: %w", next, err)
+		}
+	}
+}
+
+// decideRotateWithConsent checks if a Secret in another namespace allows coupling from the
+// namespace, with its replicatable-from-namespaces or replicatable-from-selector annotation
+// and its replicatable-until annotation
+func (r *SecretReconciler) decideRotateWithConsent(ctx context.Context, referenced *corev1.Secret, namespace string) replicator.Decision {
+	if decision := replicator.DecideConsentExpiry(referenced, r.now()); !decision.Allowed {
+		return decision
+	}
+	decision := replicator.DecidePullConsent(referenced, namespace, nil)
+	if decision.Allowed || referenced.Annotations[replicator.AnnotationReplicatableFromSelector] == "" {
+		return decision
+	}
+
+	var ns corev1.Namespace
+	if err := r.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
+		return replicator.Deny("cannot get namespace %q: %v", namespace, err)
+	}
+	return replicator.DecidePullConsent(referenced, namespace, ns.Labels)
+}
+
+// coupledSecrets returns requests for all Secrets whose rotate-with annotation references the Secret
+func (r *SecretReconciler) coupledSecrets(ctx context.Context, obj client.Object) []reconcile.Request {
+	key := client.ObjectKeyFromObject(obj)
+	var secrets corev1.SecretList
+	if err := r.List(ctx, &secrets, client.MatchingFields{rotateWithIndexField: key.String()}); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list Secrets for rotate-with")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(secrets.Items))
+	for i := range secrets.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&secrets.Items[i])})
+	}
+	return requests
+}
+
+// generatedAtChanged only passes Secret updates that change the generated-at annotation, i.e.
+// generations and rotations
+var generatedAtChanged = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return e.ObjectOld.GetAnnotations()[AnnotationGeneratedAt] !=
+			e.ObjectNew.GetAnnotations()[AnnotationGeneratedAt]
+	},
+}
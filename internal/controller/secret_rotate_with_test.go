@@ -0,0 +1,174 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+func TestReconcileRotateWith(t *testing.T) {
+	generatedAt := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		serverGeneratedAt time.Time
+		serverRotateWith  string
+		withoutConsent    bool
+		expectRotation    bool
+		expectWarning     bool
+	}{
+		{
+			name:              "referenced Secret rotated later",
+			serverGeneratedAt: generatedAt.Add(time.Hour),
+			expectRotation:    true,
+		},
+		{
+			name:              "referenced Secret not rotated since",
+			serverGeneratedAt: generatedAt.Add(-time.Hour),
+		},
+		{
+			name:              "Secrets referencing each other",
+			serverGeneratedAt: generatedAt.Add(time.Hour),
+			serverRotateWith:  "default/truststore",
+			expectWarning:     true,
+		},
+		{
+			name:              "Secrets referencing each other through a third Secret",
+			serverGeneratedAt: generatedAt.Add(time.Hour),
+			serverRotateWith:  "default/proxy",
+			expectWarning:     true,
+		},
+		{
+			name:              "referenced Secret in another namespace without consent",
+			serverGeneratedAt: generatedAt.Add(time.Hour),
+			withoutConsent:    true,
+			expectWarning:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "server", Namespace: "production", Annotations: map[string]string{
+					AnnotationAutogenerate: "password",
+					AnnotationGeneratedAt:  tt.serverGeneratedAt.Format(time.RFC3339),
+				}},
+				Data: map[string][]byte{"password": []byte("server")},
+			}
+			if tt.serverRotateWith != "" {
+				server.Annotations[AnnotationRotateWith] = tt.serverRotateWith
+			}
+			if !tt.withoutConsent {
+				server.Annotations[replicator.AnnotationReplicatableFromNamespaces] = "default"
+			}
+			proxy := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "proxy", Namespace: "default", Annotations: map[string]string{
+					AnnotationRotateWith: "truststore",
+				}},
+			}
+			truststore := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "truststore", Namespace: "default", Annotations: map[string]string{
+					AnnotationAutogenerate: "password",
+					AnnotationGeneratedAt:  generatedAt.Format(time.RFC3339),
+					AnnotationRotateWith:   "production/server",
+				}},
+				Data: map[string][]byte{"password": []byte("old-password")},
+			}
+
+			scheme := runtime.NewScheme()
+			_ = clientgoscheme.AddToScheme(scheme)
+			recorder := record.NewFakeRecorder(10)
+			r := &SecretReconciler{
+				Client:        fake.NewClientBuilder().WithScheme(scheme).WithObjects(server, truststore, proxy).Build(),
+				Scheme:        scheme,
+				Generator:     generator.NewSecretGenerator(),
+				Config:        config.NewDefaultConfig(),
+				EventRecorder: recorder,
+				Clock:         &MockClock{currentTime: generatedAt.Add(2 * time.Hour)},
+			}
+			ctx := context.Background()
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "truststore", Namespace: "default"}}
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var updated corev1.Secret
+			if err := r.Get(ctx, req.NamespacedName, &updated); err != nil {
+				t.Fatalf("failed to get secret: %v", err)
+			}
+			if rotated := string(updated.Data["password"]) != "old-password"; rotated != tt.expectRotation {
+				t.Errorf("expected rotation %v, got %v", tt.expectRotation, rotated)
+			}
+
+			warned := false
+			for len(recorder.Events) > 0 {
+				if event := <-recorder.Events; strings.Contains(event, AnnotationRotateWith) {
+					warned = true
+				}
+			}
+			if warned != tt.expectWarning {
+				t.Errorf("expected warning %v, got %v", tt.expectWarning, warned)
+			}
+		})
+	}
+}
+
+func TestCoupledSecrets(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	secret := func(namespace, name, rotateWith string) *corev1.Secret {
+		s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		if rotateWith != "" {
+			s.Annotations = map[string]string{AnnotationRotateWith: rotateWith}
+		}
+		return s
+	}
+	server := secret("production", "server", "")
+	r := &SecretReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).
+		WithIndex(&corev1.Secret{}, rotateWithIndexField, indexRotateWith).WithObjects(
+		server,
+		secret("production", "same-namespace", "server"),
+		secret("default", "other-namespace", "production/server"),
+		secret("default", "unrelated", "server"),
+		secret("default", "uncoupled", ""),
+	).Build()}
+
+	requests := r.coupledSecrets(context.Background(), server)
+	got := make(map[string]bool)
+	for _, req := range requests {
+		got[req.String()] = true
+	}
+	if len(got) != 2 || !got["production/same-namespace"] || !got["default/other-namespace"] {
+		t.Errorf("expected both coupled Secrets to be enqueued, got %v", requests)
+	}
+}
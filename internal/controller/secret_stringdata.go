@@ -0,0 +1,65 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+// NormalizeStringData merges stringData into data the way the API server does on writes, so
+// that Secrets read from sources that keep stringData (manifests, fake clients) are processed
+// like Secrets read from the API server
+func NormalizeStringData(secret *corev1.Secret) {
+	if len(secret.StringData) == 0 {
+		return
+	}
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte, len(secret.StringData))
+	}
+	for key, value := range secret.StringData {
+		secret.Data[key] = []byte(value)
+	}
+	secret.StringData = nil
+}
+
+// useStringData returns true if generated string values of the Secret are written via stringData
+func (r *SecretReconciler) useStringData(annotations map[string]string) bool {
+	if value, ok := parseBoolAnnotation(annotations, AnnotationStringData); ok {
+		return value
+	}
+	return r.Config.Defaults.StringData
+}
+
+// moveToStringData moves the given generated keys from data to stringData, leaving the encoding
+// to the API server. Keys of bytes fields and values that are not valid UTF-8 stay in data.
+func (r *SecretReconciler) moveToStringData(secret *corev1.Secret, keys []string) {
+	for _, key := range keys {
+		value, ok := secret.Data[key]
+		if !ok || !utf8.Valid(value) || r.getFieldType(secret.Annotations, key) == config.TypeBytes {
+			continue
+		}
+		if secret.StringData == nil {
+			secret.StringData = make(map[string]string)
+		}
+		secret.StringData[key] = string(value)
+		delete(secret.Data, key)
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+)
+
+func TestNormalizeStringData(t *testing.T) {
+	secret := &corev1.Secret{
+		Data:       map[string][]byte{"username": []byte("old"), "host": []byte("db")},
+		StringData: map[string]string{"username": "admin"},
+	}
+	NormalizeStringData(secret)
+
+	if secret.StringData != nil {
+		t.Errorf("expected stringData to be cleared, got %v", secret.StringData)
+	}
+	if string(secret.Data["username"]) != "admin" || string(secret.Data["host"]) != "db" {
+		t.Errorf("expected stringData to take precedence over data, got %v", secret.Data)
+	}
+}
+
+func TestReconcileWritesStringData(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "db",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate:       "password,key",
+				AnnotationTypePrefix + "key": config.TypeBytes,
+				AnnotationStringData:         "true",
+			},
+		},
+		StringData: map[string]string{"username": "admin"},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	r := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: record.NewFakeRecorder(10),
+	}
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "db", Namespace: "default"}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The fake client stores what the operator sent, i.e. before the API server merges stringData
+	var stored corev1.Secret
+	if err := fakeClient.Get(ctx, req.NamespacedName, &stored); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	password, ok := stored.StringData["password"]
+	if !ok || len(password) != config.DefaultLength {
+		t.Errorf("expected generated password in stringData, got %v", stored.StringData)
+	}
+	if _, ok := stored.Data["password"]; ok {
+		t.Error("expected password not to be written to data")
+	}
+	if _, ok := stored.StringData["key"]; ok {
+		t.Error("expected bytes field not to be written to stringData")
+	}
+	if string(stored.Data["username"]) != "admin" {
+		t.Errorf("expected stringData of the input to be merged into data, got %q", stored.Data["username"])
+	}
+
+	// Reads are normalized, so the password is not generated again
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := fakeClient.Get(ctx, req.NamespacedName, &stored); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	NormalizeStringData(&stored)
+	if string(stored.Data["password"]) != password {
+		t.Error("expected password to be kept on the second reconcile")
+	}
+}
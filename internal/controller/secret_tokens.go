@@ -0,0 +1,181 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+)
+
+// tokenDuration returns the validity period of a signed-token field: the token-duration.<field>
+// annotation, or the configured default
+func (r *SecretReconciler) tokenDuration(annotations map[string]string, field string) (time.Duration, error) {
+	if value, ok := annotations[AnnotationTokenDurationPrefix+field]; ok {
+		d, err := config.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return 0, fmt.Errorf("invalid %s%s %q", AnnotationTokenDurationPrefix, field, value)
+		}
+		return d, nil
+	}
+	if d := r.Config.Tokens.Duration.Duration(); d > 0 {
+		return d, nil
+	}
+	return config.DefaultTokenDuration, nil
+}
+
+// tokenRenewalFraction returns the configured fraction of a signed token's lifetime after which
+// it is refreshed
+func (r *SecretReconciler) tokenRenewalFraction() float64 {
+	if f := r.Config.Tokens.RenewalFraction; f > 0 {
+		return f
+	}
+	return config.DefaultTokenRenewalFraction
+}
+
+// signingKey returns the value of the field that signs a signed-token field
+func signingKey(secret *corev1.Secret, field string) ([]byte, error) {
+	keyField := strings.TrimSpace(secret.Annotations[AnnotationSigningKeyPrefix+field])
+	if keyField == "" {
+		return nil, fmt.Errorf("no signing key configured, set %s%s", AnnotationSigningKeyPrefix, field)
+	}
+	key := secret.Data[keyField]
+	if len(key) == 0 {
+		return nil, fmt.Errorf("signing key field %q is missing or empty", keyField)
+	}
+	return key, nil
+}
+
+// signedTokenRenewalTime returns when a signed token is due for refresh
+func (r *SecretReconciler) signedTokenRenewalTime(claims generator.TokenClaims) time.Time {
+	issuedAt := time.Unix(claims.IssuedAt, 0)
+	return issuedAt.Add(time.Duration(float64(claims.Lifetime()) * r.tokenRenewalFraction()))
+}
+
+// checkSignedTokenRenewal checks if the signed token stored in a field needs to be refreshed.
+// A token is refreshed once the configured fraction of its lifetime has elapsed, and when it was
+// not signed with the current signing key (e.g. because the key was rotated).
+// If the field has no value, no refresh is needed (the token will be generated).
+func (r *SecretReconciler) checkSignedTokenRenewal(secret *corev1.Secret, field string) rotationCheckResult {
+	result := rotationCheckResult{}
+
+	token, ok := secret.Data[field]
+	if !ok {
+		return result
+	}
+
+	key, _ := signingKey(secret, field)
+	claims, err := generator.ParseSignedToken(token, key)
+	if errors.Is(err, generator.ErrTokenSignature) {
+		result.needsRotation = true
+		return result
+	}
+	if err != nil {
+		result.err = fmt.Errorf("cannot parse signed token in field %q: %w", field, err)
+		result.errMsg = fmt.Sprintf("Cannot parse signed token in field %q, refresh disabled: %v", field, err)
+		return result
+	}
+	result.rotationInterval = claims.Lifetime()
+
+	renewAt := r.signedTokenRenewalTime(claims)
+	if !r.now().Before(renewAt) {
+		result.needsRotation = true
+	} else {
+		timeUntilRenewal := renewAt.Sub(r.now())
+		result.timeUntilRotation = &timeUntilRenewal
+	}
+	return result
+}
+
+// generateSignedTokenField generates (or refreshes) a signed token for a field
+func (r *SecretReconciler) generateSignedTokenField(
+	secret *corev1.Secret,
+	field string,
+	rotationOpts rotationOptions,
+	logger logr.Logger,
+) fieldGenerationResult {
+	result := fieldGenerationResult{field: field}
+	_, exists := secret.Data[field]
+
+	renewalCheck := r.checkSignedTokenRenewal(secret, field)
+	if renewalCheck.err != nil {
+		logger.Error(renewalCheck.err, "Cannot check signed token refresh", "field", field)
+		r.EventRecorder.Event(secret, corev1.EventTypeWarning, EventReasonRotationFailed, renewalCheck.errMsg)
+		if exists && !rotationOpts.force {
+			return result
+		}
+	}
+
+	// An explicitly requested rotation refreshes the token regardless of its lifetime
+	if rotationOpts.force && exists {
+		renewalCheck.needsRotation = true
+	}
+
+	if exists && (!renewalCheck.needsRotation || !rotationOpts.allow) {
+		logger.V(1).Info("Signed token is still valid, skipping", "field", field)
+		return result
+	}
+
+	key, err := signingKey(secret, field)
+	var duration time.Duration
+	if err == nil {
+		duration, err = r.tokenDuration(secret.Annotations, field)
+	}
+	var token []byte
+	if err == nil {
+		token, err = generator.GenerateSignedToken(key, r.now(), duration)
+	}
+	if err != nil {
+		result.err = fmt.Errorf("failed to generate signed token for field %s: %w", field, err)
+		result.errMsg = fmt.Sprintf("Failed to generate signed token for field %q: %v", field, err)
+		result.invalid = true
+		logger.Error(err, "Failed to generate signed token", "field", field)
+		r.EventRecorder.Event(secret, corev1.EventTypeWarning, EventReasonGenerationFailed, result.errMsg)
+		return result
+	}
+
+	result.value = token
+	result.genType = config.TypeSignedToken
+	result.notAfter = time.Unix(r.now().Add(duration).Unix(), 0)
+	result.rotated = renewalCheck.needsRotation
+	logger.Info("Generated signed token for field", "field", field, "notAfter", result.notAfter)
+	return result
+}
+
+// orderFields returns the fields in generation order: signed tokens are generated last, so that
+// they are signed with the signing key generated or rotated in the same reconciliation
+func (r *SecretReconciler) orderFields(annotations map[string]string, fields []string) []string {
+	ordered := slices.Clone(fields)
+	slices.SortStableFunc(ordered, func(a, b string) int {
+		isToken := func(field string) int {
+			if r.getFieldType(annotations, field) == config.TypeSignedToken {
+				return 1
+			}
+			return 0
+		}
+		return isToken(a) - isToken(b)
+	})
+	return ordered
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+)
+
+func newTokenSecret(data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-token",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate:                  "signing-key,token",
+				AnnotationTypePrefix + "token":          config.TypeSignedToken,
+				AnnotationSigningKeyPrefix + "token":    "signing-key",
+				AnnotationTokenDurationPrefix + "token": "90h",
+			},
+		},
+		Data: data,
+	}
+}
+
+func TestReconcileGeneratesSignedToken(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	secret := newTokenSecret(nil)
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	fakeRecorder := record.NewFakeRecorder(10)
+
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: fakeRecorder,
+		Clock:         &MockClock{currentTime: now},
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+	result, err := reconciler.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+
+	// The token is signed with the signing key generated in the same reconciliation
+	claims, err := generator.ParseSignedToken(updated.Data["token"], updated.Data["signing-key"])
+	if err != nil {
+		t.Fatalf("expected token signed with the signing key, got: %v", err)
+	}
+	if claims.IssuedAt != now.Unix() || claims.Lifetime() != 90*time.Hour {
+		t.Errorf("unexpected claims %+v", claims)
+	}
+
+	// Refresh is scheduled after 2/3 of the 90h lifetime
+	if result.RequeueAfter != 60*time.Hour {
+		t.Errorf("expected RequeueAfter %v, got %v", 60*time.Hour, result.RequeueAfter)
+	}
+}
+
+func TestReconcileRefreshesSignedToken(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	issuedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	key := []byte("signing-key-value")
+	existing, err := generator.GenerateSignedToken(key, issuedAt, 90*time.Hour)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		now           time.Time
+		key           []byte
+		expectRefresh bool
+		expectRequeue time.Duration
+	}{
+		{
+			name:          "before refresh point",
+			now:           issuedAt.Add(30 * time.Hour),
+			key:           key,
+			expectRequeue: 30 * time.Hour,
+		},
+		{
+			name:          "after refresh point",
+			now:           issuedAt.Add(61 * time.Hour),
+			key:           key,
+			expectRefresh: true,
+		},
+		{
+			name:          "signing key changed",
+			now:           issuedAt.Add(time.Hour),
+			key:           []byte("rotated-signing-key"),
+			expectRefresh: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := newTokenSecret(map[string][]byte{"signing-key": tt.key, "token": existing})
+			secret.Annotations[AnnotationGeneratedAt] = issuedAt.Format(time.RFC3339)
+
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+			reconciler := &SecretReconciler{
+				Client:        fakeClient,
+				Scheme:        scheme,
+				Generator:     generator.NewSecretGenerator(),
+				Config:        config.NewDefaultConfig(),
+				EventRecorder: record.NewFakeRecorder(10),
+				Clock:         &MockClock{currentTime: tt.now},
+			}
+
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+			result, err := reconciler.Reconcile(context.Background(), req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var updated corev1.Secret
+			if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+				t.Fatalf("failed to get secret: %v", err)
+			}
+
+			refreshed := string(updated.Data["token"]) != string(existing)
+			if refreshed != tt.expectRefresh {
+				t.Fatalf("expected refresh %v, got %v", tt.expectRefresh, refreshed)
+			}
+			if refreshed {
+				claims, err := generator.ParseSignedToken(updated.Data["token"], tt.key)
+				if err != nil {
+					t.Fatalf("expected refreshed token signed with the current key, got: %v", err)
+				}
+				if claims.IssuedAt != tt.now.Unix() {
+					t.Errorf("expected refreshed token issued at %v, got %d", tt.now, claims.IssuedAt)
+				}
+			}
+			if tt.expectRequeue > 0 && result.RequeueAfter != tt.expectRequeue {
+				t.Errorf("expected RequeueAfter %v, got %v", tt.expectRequeue, result.RequeueAfter)
+			}
+		})
+	}
+}
+
+func TestReconcileSignedTokenWithoutSigningKey(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	secret := newTokenSecret(nil)
+	delete(secret.Annotations, AnnotationSigningKeyPrefix+"token")
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	fakeRecorder := record.NewFakeRecorder(10)
+	reconciler := &SecretReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Generator:     generator.NewSecretGenerator(),
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: fakeRecorder,
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated corev1.Secret
+	if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if _, ok := updated.Data["token"]; ok || len(updated.Data["signing-key"]) == 0 {
+		t.Errorf("expected only the signing key to be written, got keys %v", updated.Data)
+	}
+	if !strings.Contains(updated.Annotations[AnnotationGenerationError], `"token"`) {
+		t.Errorf("expected generation error for the token field, got %q", updated.Annotations[AnnotationGenerationError])
+	}
+
+	select {
+	case event := <-fakeRecorder.Events:
+		if !strings.Contains(event, EventReasonGenerationFailed) || !strings.Contains(event, AnnotationSigningKeyPrefix+"token") {
+			t.Errorf("unexpected event %q", event)
+		}
+	default:
+		t.Error("expected a generation failure event")
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+)
+
+// maxDistinctAttempts is the number of attempts to generate a value that differs from the
+// values to avoid. Collisions are only likely for very short values or tiny charsets.
+const maxDistinctAttempts = 10
+
+// valuesToAvoid returns the values a newly generated value of the field must differ from: the
+// values of all other fields if unique values are enabled, and the previous value of the field
+// on rotation if rotations must change the value
+func (r *SecretReconciler) valuesToAvoid(secret *corev1.Secret, field string, rotation bool) [][]byte {
+	var avoid [][]byte
+	if r.uniqueValues(secret.Annotations) {
+		for key, value := range secret.Data {
+			if key != field {
+				avoid = append(avoid, value)
+			}
+		}
+	}
+	if rotation && r.Config.Rotation.RequireChange {
+		if previous, ok := secret.Data[field]; ok {
+			avoid = append(avoid, previous)
+		}
+	}
+	return avoid
+}
+
+// uniqueValues returns true if the generated values of the Secret must be distinct
+func (r *SecretReconciler) uniqueValues(annotations map[string]string) bool {
+	if value, ok := parseBoolAnnotation(annotations, AnnotationUniqueValues); ok {
+		return value
+	}
+	return r.Config.Defaults.UniqueValues
+}
+
+// generateDistinct generates a value with the given options that differs from all values to
+// avoid, retrying on collisions
+func (r *SecretReconciler) generateDistinct(opts generator.GenerateOptions, avoid [][]byte) ([]byte, error) {
+	for range maxDistinctAttempts {
+		value, err := r.Generator.GenerateWithOptions(opts)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.ContainsFunc(avoid, func(other []byte) bool { return bytes.Equal(value, other) }) {
+			return value, nil
+		}
+	}
+	return nil, fmt.Errorf("no distinct value after %d attempts, increase the length or charset", maxDistinctAttempts)
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+)
+
+// sequenceGenerator returns the given values in order from GenerateWithOptions
+type sequenceGenerator struct {
+	generator.Generator
+	values []string
+}
+
+func (g *sequenceGenerator) GenerateWithOptions(generator.GenerateOptions) ([]byte, error) {
+	value := g.values[0]
+	g.values = g.values[1:]
+	return []byte(value), nil
+}
+
+func TestReconcileUniqueValues(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	generatedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		data          map[string][]byte
+		requireChange bool
+		values        []string
+		expected      map[string]string
+		expectFailure bool
+	}{
+		{
+			name:        "fields are distinct",
+			annotations: map[string]string{AnnotationAutogenerate: "a,b", AnnotationUniqueValues: "true"},
+			values:      []string{"same", "same", "other"},
+			expected:    map[string]string{"a": "same", "b": "other"},
+		},
+		{
+			name:        "collisions are allowed by default",
+			annotations: map[string]string{AnnotationAutogenerate: "a,b"},
+			values:      []string{"same", "same"},
+			expected:    map[string]string{"a": "same", "b": "same"},
+		},
+		{
+			name: "rotation changes the value",
+			annotations: map[string]string{
+				AnnotationAutogenerate: "a",
+				AnnotationRotate:       "1h",
+				AnnotationGeneratedAt:  generatedAt.Format(time.RFC3339),
+			},
+			data:          map[string][]byte{"a": []byte("old")},
+			requireChange: true,
+			values:        []string{"old", "new"},
+			expected:      map[string]string{"a": "new"},
+		},
+		{
+			name:        "no distinct value",
+			annotations: map[string]string{AnnotationAutogenerate: "a,b", AnnotationUniqueValues: "true"},
+			values: []string{"same", "same", "same", "same", "same", "same",
+				"same", "same", "same", "same", "same", "same"},
+			expected:      map[string]string{},
+			expectFailure: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default", Annotations: tt.annotations},
+				Data:       tt.data,
+			}
+			cfg := config.NewDefaultConfig()
+			cfg.Rotation.RequireChange = tt.requireChange
+
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+			recorder := record.NewFakeRecorder(10)
+			r := &SecretReconciler{
+				Client:        fakeClient,
+				Scheme:        scheme,
+				Generator:     &sequenceGenerator{values: tt.values},
+				Config:        cfg,
+				EventRecorder: recorder,
+				Clock:         &MockClock{currentTime: generatedAt.Add(2 * time.Hour)},
+			}
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "db", Namespace: "default"}}
+			if _, err := r.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var updated corev1.Secret
+			if err := fakeClient.Get(context.Background(), req.NamespacedName, &updated); err != nil {
+				t.Fatalf("failed to get secret: %v", err)
+			}
+			if len(updated.Data) != len(tt.expected) {
+				t.Errorf("expected data %v, got %v", tt.expected, updated.Data)
+			}
+			for key, value := range tt.expected {
+				if string(updated.Data[key]) != value {
+					t.Errorf("expected %s=%q, got %q", key, value, updated.Data[key])
+				}
+			}
+
+			if tt.expectFailure {
+				select {
+				case event := <-recorder.Events:
+					if !strings.Contains(event, EventReasonGenerationFailed) || !strings.Contains(event, "no distinct value") {
+						t.Errorf("expected %s event, got %q", EventReasonGenerationFailed, event)
+					}
+				default:
+					t.Error("expected a GenerationFailed event")
+				}
+			}
+		})
+	}
+}
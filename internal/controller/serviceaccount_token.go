@@ -0,0 +1,232 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	isoannotations "github.com/guided-traffic/internal-secrets-operator/pkg/annotations"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+const (
+	// AnnotationTokenSecret is set on a ServiceAccount and names the long-lived token Secret
+	// the operator creates and rotates for it
+	AnnotationTokenSecret = AnnotationPrefix + "token-secret"
+
+	// EventReasonTokenSecretCreated is the event reason for a created token Secret
+	EventReasonTokenSecretCreated = "TokenSecretCreated"
+
+	// EventReasonTokenSecretRotated is the event reason for a rotated token Secret
+	EventReasonTokenSecretRotated = "TokenSecretRotated"
+
+	// EventReasonTokenSecretFailed is the event reason for a token Secret that cannot be managed
+	EventReasonTokenSecretFailed = "TokenSecretFailed"
+)
+
+// ServiceAccountTokenReconciler creates long-lived kubernetes.io/service-account-token Secrets
+// for ServiceAccounts with the token-secret annotation and rotates them according to the
+// rotate annotation of the ServiceAccount. The token itself is issued by the Kubernetes token
+// controller; a rotation deletes the Secret, which invalidates the old token, and creates it again.
+type ServiceAccountTokenReconciler struct {
+	client.Client
+	Scheme        *runtime.Scheme
+	Config        *config.Config
+	EventRecorder record.EventRecorder
+	// Clock is used to get the current time. If nil, time.Now() is used.
+	Clock Clock
+	// Heartbeat records completed reconciles for the liveness check. If nil, nothing is recorded.
+	Heartbeat *Heartbeat
+	// Shard restricts the controller to the namespaces of one shard. If nil, all namespaces are processed.
+	Shard *Shard
+}
+
+// now returns the current time using the Clock if set, otherwise time.Now()
+func (r *ServiceAccountTokenReconciler) now() time.Time {
+	if r.Clock != nil {
+		return r.Clock.Now()
+	}
+	return time.Now()
+}
+
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;delete
+
+// Reconcile creates the token Secret of a ServiceAccount and rotates it when it is due
+func (r *ServiceAccountTokenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var sa corev1.ServiceAccount
+	if err := r.Get(ctx, req.NamespacedName, &sa); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	name := sa.Annotations[AnnotationTokenSecret]
+	if name == "" || sa.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		r.EventRecorder.Event(&sa, corev1.EventTypeWarning, EventReasonTokenSecretFailed,
+			fmt.Sprintf("Invalid token Secret name %q: %s", name, errs[0]))
+		return ctrl.Result{}, nil
+	}
+
+	var secret corev1.Secret
+	err := r.Get(ctx, types.NamespacedName{Namespace: sa.Namespace, Name: name}, &secret)
+	if apierrors.IsNotFound(err) {
+		if err := r.createTokenSecret(ctx, &sa, name); err != nil {
+			return ctrl.Result{}, err
+		}
+		logger.Info("Created token Secret", "serviceAccount", sa.Name, "secret", name)
+		r.EventRecorder.Event(&sa, corev1.EventTypeNormal, EventReasonTokenSecretCreated,
+			fmt.Sprintf("Created token Secret %q", name))
+		// The created Secret triggers another reconcile, which schedules the rotation
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Never take over Secrets that belong to another ServiceAccount or are no token Secrets
+	if secret.Type != corev1.SecretTypeServiceAccountToken ||
+		secret.Annotations[corev1.ServiceAccountNameKey] != sa.Name {
+		r.EventRecorder.Event(&sa, corev1.EventTypeWarning, EventReasonTokenSecretFailed,
+			fmt.Sprintf("Secret %q exists and is not a token Secret of this ServiceAccount", name))
+		return ctrl.Result{}, nil
+	}
+
+	interval, err := r.rotationInterval(&sa)
+	if err != nil {
+		r.EventRecorder.Event(&sa, corev1.EventTypeWarning, EventReasonTokenSecretFailed, err.Error())
+		return ctrl.Result{}, nil
+	}
+	if interval <= 0 {
+		return ctrl.Result{}, nil
+	}
+
+	generatedAt := secret.CreationTimestamp.Time
+	if parsed := isoannotations.ParseTimestamp(secret.Annotations, AnnotationGeneratedAt); parsed != nil {
+		generatedAt = *parsed
+	}
+	elapsed := r.now().Sub(generatedAt) + r.Config.Rotation.ClockSkewTolerance.Duration()
+	if elapsed < interval {
+		return ctrl.Result{RequeueAfter: interval - elapsed}, nil
+	}
+
+	var namespace corev1.Namespace
+	if err := r.Get(ctx, types.NamespacedName{Name: sa.Namespace}, &namespace); client.IgnoreNotFound(err) != nil {
+		return ctrl.Result{}, err
+	}
+	if isRotationPaused(&namespace) {
+		logger.V(1).Info("Token rotation paused", "serviceAccount", sa.Name, "secret", name)
+		return ctrl.Result{}, nil
+	}
+
+	// Deleting the Secret invalidates the old token; the UID precondition ensures that
+	// a Secret replaced in the meantime is not deleted
+	if err := r.Delete(ctx, &secret, client.Preconditions{UID: &secret.UID}); client.IgnoreNotFound(err) != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.createTokenSecret(ctx, &sa, name); err != nil {
+		return ctrl.Result{}, err
+	}
+	logger.Info("Rotated token Secret", "serviceAccount", sa.Name, "secret", name)
+	r.EventRecorder.Event(&sa, corev1.EventTypeNormal, EventReasonTokenSecretRotated,
+		fmt.Sprintf("Rotated token Secret %q", name))
+	return ctrl.Result{}, nil
+}
+
+// rotationInterval returns the rotation interval of the token Secret from the rotate annotation
+// of the ServiceAccount or rotation.defaultInterval. 0 disables rotation.
+func (r *ServiceAccountTokenReconciler) rotationInterval(sa *corev1.ServiceAccount) (time.Duration, error) {
+	interval := r.Config.Rotation.DefaultInterval.Duration()
+	if value, ok := sa.Annotations[AnnotationRotate]; ok && value != "" {
+		parsed, err := config.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			return 0, fmt.Errorf("invalid %s %q", AnnotationRotate, value)
+		}
+		interval = parsed
+	}
+	if interval > 0 && interval < r.Config.Rotation.MinInterval.Duration() {
+		return 0, fmt.Errorf("rotation interval %s is below minimum %s",
+			interval, r.Config.Rotation.MinInterval.Duration())
+	}
+	return interval, nil
+}
+
+// createTokenSecret creates a token Secret for the ServiceAccount. The Secret is owned by the
+// ServiceAccount, so it is deleted together with it.
+func (r *ServiceAccountTokenReconciler) createTokenSecret(ctx context.Context, sa *corev1.ServiceAccount, name string) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: sa.Namespace,
+			Annotations: map[string]string{
+				corev1.ServiceAccountNameKey: sa.Name,
+				AnnotationGeneratedAt:        isoannotations.FormatTimestamp(r.now().UTC(), r.Config.Rotation.TimestampFormat),
+			},
+		},
+		Type: corev1.SecretTypeServiceAccountToken,
+	}
+	// The operator's Secret informer only sees Secrets with the managed label
+	if r.Config.ManagedLabel.Enabled {
+		secret.Labels = map[string]string{r.Config.ManagedLabel.Key: r.Config.ManagedLabel.Value}
+	}
+	if err := controllerutil.SetControllerReference(sa, secret, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, secret); err != nil {
+		return fmt.Errorf("failed to create token Secret %s: %w", name, err)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager. It watches ServiceAccounts with the
+// token-secret annotation and the token Secrets they own.
+func (r *ServiceAccountTokenReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	hasTokenSecretAnnotation := predicate.NewPredicateFuncs(func(object client.Object) bool {
+		return object.GetAnnotations()[AnnotationTokenSecret] != ""
+	})
+
+	recorder, err := instrumentEventRecorder(r.EventRecorder)
+	if err != nil {
+		return err
+	}
+	r.EventRecorder = recorder
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("serviceaccount-token").
+		For(&corev1.ServiceAccount{}, builder.WithPredicates(hasTokenSecretAnnotation)).
+		Owns(&corev1.Secret{}).
+		Complete(r.Heartbeat.wrap("serviceaccount-token", r.Shard.wrap(
+			reportPanics(r.EventRecorder, &corev1.ServiceAccount{}, r))))
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+func TestServiceAccountTokenReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+		Name:      "legacy-client",
+		Namespace: "apps",
+		UID:       "sa-uid",
+		Annotations: map[string]string{
+			AnnotationTokenSecret: "legacy-client-token",
+			AnnotationRotate:      "30d",
+		},
+	}}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sa).Build()
+	fakeRecorder := record.NewFakeRecorder(10)
+	clock := &MockClock{currentTime: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	reconciler := &ServiceAccountTokenReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: fakeRecorder,
+		Clock:         clock,
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: sa.Namespace, Name: sa.Name}}
+	secretKey := types.NamespacedName{Namespace: sa.Namespace, Name: "legacy-client-token"}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var secret corev1.Secret
+	if err := fakeClient.Get(context.Background(), secretKey, &secret); err != nil {
+		t.Fatalf("expected token Secret to be created: %v", err)
+	}
+	if secret.Type != corev1.SecretTypeServiceAccountToken || secret.Annotations[corev1.ServiceAccountNameKey] != sa.Name {
+		t.Errorf("expected token Secret of %s, got type %s and annotations %v", sa.Name, secret.Type, secret.Annotations)
+	}
+	if len(secret.OwnerReferences) != 1 || secret.OwnerReferences[0].UID != sa.UID {
+		t.Errorf("expected token Secret to be owned by the ServiceAccount, got %v", secret.OwnerReferences)
+	}
+	if event := <-fakeRecorder.Events; !strings.Contains(event, EventReasonTokenSecretCreated) {
+		t.Errorf("expected %s event, got %q", EventReasonTokenSecretCreated, event)
+	}
+
+	// Not yet due
+	clock.currentTime = clock.currentTime.Add(29 * 24 * time.Hour)
+	result, err := reconciler.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.RequeueAfter != 24*time.Hour {
+		t.Errorf("expected requeue after 24h, got %v", result.RequeueAfter)
+	}
+
+	// Due: the Secret is replaced, which invalidates the old token
+	clock.currentTime = clock.currentTime.Add(24 * time.Hour)
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var rotated corev1.Secret
+	if err := fakeClient.Get(context.Background(), secretKey, &rotated); err != nil {
+		t.Fatalf("expected token Secret to be recreated: %v", err)
+	}
+	if rotated.Annotations[AnnotationGeneratedAt] != clock.currentTime.Format(time.RFC3339) {
+		t.Errorf("expected generated-at %s, got %s", clock.currentTime.Format(time.RFC3339), rotated.Annotations[AnnotationGeneratedAt])
+	}
+	if event := <-fakeRecorder.Events; !strings.Contains(event, EventReasonTokenSecretRotated) {
+		t.Errorf("expected %s event, got %q", EventReasonTokenSecretRotated, event)
+	}
+}
+
+func TestServiceAccountTokenReconcileUnixTimestamp(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+		Name:      "legacy-client",
+		Namespace: "apps",
+		Annotations: map[string]string{
+			AnnotationTokenSecret: "legacy-client-token",
+			AnnotationRotate:      "30d",
+		},
+	}}
+	cfg := config.NewDefaultConfig()
+	cfg.Rotation.TimestampFormat = config.TimestampFormatUnix
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sa).Build()
+	clock := &MockClock{currentTime: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	reconciler := &ServiceAccountTokenReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        cfg,
+		EventRecorder: record.NewFakeRecorder(10),
+		Clock:         clock,
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: sa.Namespace, Name: sa.Name}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var secret corev1.Secret
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Namespace: sa.Namespace, Name: "legacy-client-token"}, &secret); err != nil {
+		t.Fatalf("expected token Secret to be created: %v", err)
+	}
+	if got := secret.Annotations[AnnotationGeneratedAt]; got != "1735689600" {
+		t.Errorf("expected generated-at in Unix format, got %q", got)
+	}
+
+	// The Unix timestamp is read back, so the token is not rotated before it is due
+	clock.currentTime = clock.currentTime.Add(29 * 24 * time.Hour)
+	result, err := reconciler.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.RequeueAfter != 24*time.Hour {
+		t.Errorf("expected requeue after 24h, got %v", result.RequeueAfter)
+	}
+}
+
+func TestServiceAccountTokenReconcileForeignSecret(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+		Name:        "legacy-client",
+		Namespace:   "apps",
+		Annotations: map[string]string{AnnotationTokenSecret: "app-config"},
+	}}
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "apps"},
+		Data:       map[string][]byte{"key": []byte("value")},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sa, existing).Build()
+	fakeRecorder := record.NewFakeRecorder(10)
+	reconciler := &ServiceAccountTokenReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Config:        config.NewDefaultConfig(),
+		EventRecorder: fakeRecorder,
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: sa.Namespace, Name: sa.Name}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var secret corev1.Secret
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Namespace: "apps", Name: "app-config"}, &secret); err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if secret.Type == corev1.SecretTypeServiceAccountToken || string(secret.Data["key"]) != "value" {
+		t.Errorf("expected foreign Secret to be left untouched, got %v", secret)
+	}
+	if event := <-fakeRecorder.Events; !strings.Contains(event, EventReasonTokenSecretFailed) {
+		t.Errorf("expected %s event, got %q", EventReasonTokenSecretFailed, event)
+	}
+}
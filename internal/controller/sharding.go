@@ -0,0 +1,101 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+// Shard restricts the controllers of an operator replica to the namespaces of one shard
+type Shard struct {
+	// Reader reads Namespaces for label-based sharding
+	Reader client.Reader
+	Config config.ShardingConfig
+	// Index is the shard owned by this replica (0 to Shards-1)
+	Index int
+}
+
+// ShardFor returns the shard of a namespace. In label mode, namespaces with a valid shard
+// label belong to that shard; all other namespaces are assigned by a hash of their name.
+func ShardFor(cfg config.ShardingConfig, namespace string, namespaceLabels map[string]string) int {
+	if !cfg.Enabled() {
+		return 0
+	}
+	if cfg.Mode == config.ShardingModeLabel {
+		if index, err := strconv.Atoi(namespaceLabels[cfg.LabelKey]); err == nil && index >= 0 && index < cfg.Shards {
+			return index
+		}
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(namespace))
+	return int(hash.Sum32() % uint32(cfg.Shards))
+}
+
+// Owns returns true if the namespace belongs to the shard. A nil Shard owns all namespaces.
+func (s *Shard) Owns(ctx context.Context, namespace string) (bool, error) {
+	if s == nil || !s.Config.Enabled() {
+		return true, nil
+	}
+
+	var namespaceLabels map[string]string
+	if s.Config.Mode == config.ShardingModeLabel {
+		var ns corev1.Namespace
+		if err := s.Reader.Get(ctx, types.NamespacedName{Name: namespace}, &ns); client.IgnoreNotFound(err) != nil {
+			return false, fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+		}
+		namespaceLabels = ns.Labels
+	}
+	return ShardFor(s.Config, namespace, namespaceLabels) == s.Index, nil
+}
+
+// wrap returns a Reconciler that skips requests for namespaces of other shards.
+// A nil Shard returns the reconciler unchanged.
+func (s *Shard) wrap(reconciler reconcile.Reconciler) reconcile.Reconciler {
+	if s == nil {
+		return reconciler
+	}
+	return reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		owned, err := s.Owns(ctx, req.Namespace)
+		if err != nil || !owned {
+			return ctrl.Result{}, err
+		}
+		return reconciler.Reconcile(ctx, req)
+	})
+}
+
+// ShardIndexFromHostname returns the shard index of a StatefulSet pod from the ordinal
+// suffix of its hostname (e.g. "secret-operator-2" is shard 2)
+func ShardIndexFromHostname(hostname string) (int, error) {
+	i := strings.LastIndex(hostname, "-")
+	index, err := strconv.Atoi(hostname[i+1:])
+	if i < 0 || err != nil || index < 0 {
+		return 0, fmt.Errorf("hostname %q has no StatefulSet ordinal suffix", hostname)
+	}
+	return index, nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+func TestShardFor(t *testing.T) {
+	cfg := config.ShardingConfig{Shards: 4, Mode: config.ShardingModeHash, LabelKey: config.DefaultShardingLabelKey}
+
+	counts := make(map[int]int)
+	for i := 0; i < 100; i++ {
+		namespace := fmt.Sprintf("team-%d", i)
+		shard := ShardFor(cfg, namespace, nil)
+		if shard < 0 || shard >= cfg.Shards {
+			t.Fatalf("shard %d of %s is out of range", shard, namespace)
+		}
+		if again := ShardFor(cfg, namespace, nil); again != shard {
+			t.Errorf("expected shard of %s to be deterministic, got %d and %d", namespace, shard, again)
+		}
+		counts[shard]++
+	}
+	if len(counts) != cfg.Shards {
+		t.Errorf("expected namespaces to be spread over all shards, got %v", counts)
+	}
+
+	// The label is ignored in hash mode
+	labels := map[string]string{config.DefaultShardingLabelKey: "3"}
+	if ShardFor(cfg, "team-0", labels) != ShardFor(cfg, "team-0", nil) {
+		t.Error("expected shard label to be ignored in hash mode")
+	}
+
+	cfg.Mode = config.ShardingModeLabel
+	if shard := ShardFor(cfg, "team-0", labels); shard != 3 {
+		t.Errorf("expected labeled namespace to be in shard 3, got %d", shard)
+	}
+	// Invalid labels fall back to the hash
+	for _, value := range []string{"4", "-1", "a"} {
+		labels[config.DefaultShardingLabelKey] = value
+		if ShardFor(cfg, "team-0", labels) != ShardFor(cfg, "team-0", nil) {
+			t.Errorf("expected shard label %q to fall back to the hash", value)
+		}
+	}
+}
+
+func TestShardWrap(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	namespaces := []*corev1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "a", Labels: map[string]string{config.DefaultShardingLabelKey: "0"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "b", Labels: map[string]string{config.DefaultShardingLabelKey: "1"}}},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(namespaces[0], namespaces[1]).Build()
+
+	shard := &Shard{
+		Reader: fakeClient,
+		Config: config.ShardingConfig{Shards: 2, Mode: config.ShardingModeLabel, LabelKey: config.DefaultShardingLabelKey},
+		Index:  1,
+	}
+	var reconciled []string
+	reconciler := shard.wrap(reconcile.Func(func(_ context.Context, req ctrl.Request) (ctrl.Result, error) {
+		reconciled = append(reconciled, req.Namespace)
+		return ctrl.Result{}, nil
+	}))
+
+	for _, namespace := range []string{"a", "b"} {
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "secret"}}
+		if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(reconciled) != 1 || reconciled[0] != "b" {
+		t.Errorf("expected only namespace b to be reconciled, got %v", reconciled)
+	}
+
+	// Without a Shard, all namespaces are owned
+	var none *Shard
+	if owned, err := none.Owns(context.Background(), "a"); err != nil || !owned {
+		t.Errorf("expected nil Shard to own all namespaces, got %v, %v", owned, err)
+	}
+}
+
+func TestShardIndexFromHostname(t *testing.T) {
+	if index, err := ShardIndexFromHostname("secret-operator-2"); err != nil || index != 2 {
+		t.Errorf("expected shard 2, got %d, %v", index, err)
+	}
+	for _, hostname := range []string{"secret-operator", "secret-operator-abc12"} {
+		if _, err := ShardIndexFromHostname(hostname); err == nil {
+			t.Errorf("expected error for hostname %q", hostname)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+const (
+	// MetricStartupSecrets is the name of the gauge with the state of the Secrets when the
+	// operator started, by state (managed, rotation_overdue, replica_outdated)
+	MetricStartupSecrets = "secret_operator_startup_secrets"
+
+	// MetricStartupResyncDuration is the name of the gauge with the time from the operator start
+	// until the reconcile requests of the initial resync were processed
+	MetricStartupResyncDuration = "secret_operator_startup_resync_duration_seconds"
+
+	// startupPollInterval is the interval at which the workqueues are checked during the initial resync
+	startupPollInterval = time.Second
+)
+
+var (
+	startupSecrets = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: MetricStartupSecrets,
+		Help: "State of the Secrets when the operator started (state: managed, rotation_overdue, replica_outdated)",
+	}, []string{"state"})
+	startupResyncDuration = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: MetricStartupResyncDuration,
+		Help: "Time from the operator start until all reconcile requests of the initial resync were processed",
+	})
+)
+
+// StartupSummary is the state of the Secrets when the operator started
+type StartupSummary struct {
+	// ManagedSecrets is the number of Secrets with generated values
+	ManagedSecrets int
+	// RotationsOverdue is the number of managed Secrets whose rotation became due while the
+	// operator was not running
+	RotationsOverdue int
+	// OutdatedReplicas is the number of replicated Secrets whose data no longer matches their
+	// sources, because a source changed or the replica drifted while the operator was not running
+	OutdatedReplicas int
+}
+
+// StartupReport logs and exports a one-time summary of the managed Secrets after the initial
+// cache sync, and the time until the initial resync was processed, so that the recovery after
+// restarts and upgrades can be quantified. Like the controllers, it only runs on the leader.
+type StartupReport struct {
+	Reader client.Reader
+	Config *config.Config
+	// Started is the time the operator was started. If zero, the time the report starts is used.
+	Started time.Time
+	// Gatherer provides the workqueue metrics. If nil, the controller-runtime registry is used.
+	Gatherer prometheus.Gatherer
+	// Checkpoints are the resync checkpoints of the controllers. Secrets they deferred are not in
+	// the workqueues yet, so the initial resync is not complete before they became due.
+	Checkpoints []*ResyncCheckpoint
+	// Clock is used to get the current time. If nil, time.Now() is used.
+	Clock Clock
+}
+
+// SetupWithManager registers the metrics and adds the report to the Manager
+func (r *StartupReport) SetupWithManager(mgr ctrl.Manager) error {
+	for _, collector := range []prometheus.Collector{startupSecrets, startupResyncDuration} {
+		if err := registerCollector(collector); err != nil {
+			return err
+		}
+	}
+	return mgr.Add(r)
+}
+
+// Start implements manager.Runnable. The Manager starts it after the caches were synced.
+func (r *StartupReport) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx)
+	if r.Started.IsZero() {
+		r.Started = r.now()
+	}
+
+	summary, err := r.summarize(ctx)
+	if err != nil {
+		logger.Error(err, "Failed to summarize Secrets at startup")
+	} else {
+		startupSecrets.WithLabelValues("managed").Set(float64(summary.ManagedSecrets))
+		startupSecrets.WithLabelValues("rotation_overdue").Set(float64(summary.RotationsOverdue))
+		startupSecrets.WithLabelValues("replica_outdated").Set(float64(summary.OutdatedReplicas))
+		logger.Info("Startup reconciliation report",
+			"managedSecrets", summary.ManagedSecrets,
+			"rotationsOverdue", summary.RotationsOverdue,
+			"outdatedReplicas", summary.OutdatedReplicas)
+	}
+
+	ticker := time.NewTicker(startupPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		drained, err := r.drained()
+		if err != nil {
+			logger.Error(err, "Failed to read workqueue metrics, startup resync duration is not reported")
+			return nil
+		}
+		if drained {
+			duration := r.now().Sub(r.Started)
+			startupResyncDuration.Set(duration.Seconds())
+			logger.Info("Startup resync completed", "duration", duration.Round(time.Millisecond))
+			return nil
+		}
+	}
+}
+
+// now returns the current time
+func (r *StartupReport) now() time.Time {
+	if r.Clock != nil {
+		return r.Clock.Now()
+	}
+	return time.Now()
+}
+
+// drained returns true if the controllers started and none of them has pending, deferred or
+// running reconcile requests
+func (r *StartupReport) drained() (bool, error) {
+	families, err := gatherWorkqueueMetrics(r.Gatherer)
+	if err != nil {
+		return false, err
+	}
+	// Without active workers series, the controllers did not start yet
+	if len(families[activeWorkersMetric]) == 0 {
+		return false, nil
+	}
+	for _, name := range []string{workqueueDepthMetric, workqueueUnfinishedWorkMetric, activeWorkersMetric} {
+		for _, value := range families[name] {
+			if value > 0 {
+				return false, nil
+			}
+		}
+	}
+
+	now := r.now()
+	for _, checkpoint := range r.Checkpoints {
+		if now.Before(checkpoint.pendingDeferral()) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// summarize collects the state of the Secrets
+func (r *StartupReport) summarize(ctx context.Context) (StartupSummary, error) {
+	var secrets corev1.SecretList
+	if err := r.Reader.List(ctx, &secrets); err != nil {
+		return StartupSummary{}, err
+	}
+
+	byRef := make(map[string]*corev1.Secret, len(secrets.Items))
+	for i := range secrets.Items {
+		byRef[secrets.Items[i].Namespace+"/"+secrets.Items[i].Name] = &secrets.Items[i]
+	}
+
+	now := r.now()
+	rotation := &SecretReconciler{Config: r.Config}
+	var summary StartupSummary
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if isReplicaOutdated(secret, byRef) {
+			summary.OutdatedReplicas++
+		}
+
+		value, ok := secret.Annotations[AnnotationAutogenerate]
+		if !ok || r.Config.IsSecretTypeIgnored(string(secret.Type)) {
+			continue
+		}
+		summary.ManagedSecrets++
+		if next := nextRotationTime(rotation, secret.Annotations, parseFields(value)); next != nil && !next.After(now) {
+			summary.RotationsOverdue++
+		}
+	}
+	return summary, nil
+}
+
+// isReplicaOutdated returns true if a replicated Secret drifted or no longer holds the data of its
+// sources. Stale replicas are snapshots that are not updated anymore, and replicas of individual
+// keys are not checked.
+func isReplicaOutdated(target *corev1.Secret, secrets map[string]*corev1.Secret) bool {
+	replicatedFrom := replicator.GetReplicatedFromAnnotation(target)
+	if replicatedFrom == "" || replicator.IsStale(target) {
+		return false
+	}
+	if replicator.HasDrifted(target) {
+		return true
+	}
+	if replicator.HasKeyReferences(target) {
+		return false
+	}
+
+	refs, err := replicator.ParseSourceReferences(replicatedFrom)
+	if err != nil {
+		return false
+	}
+	// Sources are merged in order, so the last source wins for duplicate keys
+	expected := make(map[string][]byte)
+	for _, ref := range refs {
+		source, ok := secrets[ref]
+		if !ok {
+			// The source was deleted, the replica is marked stale when it is reconciled
+			return true
+		}
+		for key, value := range source.Data {
+			expected[key] = value
+		}
+	}
+	for key, value := range expected {
+		if !bytes.Equal(target.Data[key], value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+func replicaSecret(name, replicatedFrom string, data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: "default",
+			Annotations: map[string]string{replicator.AnnotationReplicatedFrom: replicatedFrom},
+		},
+		Data: data,
+	}
+}
+
+func TestStartupReport(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	generatedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := generatedAt.Add(2 * time.Hour)
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "production"},
+		Data:       map[string][]byte{"password": []byte("new")},
+	}
+	drifted := replicaSecret("drifted", "production/db", map[string][]byte{"password": []byte("new")})
+	drifted.Annotations[replicator.AnnotationReplicatedChecksum] = "outdated"
+	stale := replicaSecret("stale", "production/gone", nil)
+	stale.Annotations[replicator.AnnotationSourceDeletedAt] = generatedAt.Format(time.RFC3339)
+	objs := []client.Object{
+		source,
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: "overdue", Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate: "password",
+				AnnotationRotate:       "1h",
+				AnnotationGeneratedAt:  generatedAt.Format(time.RFC3339),
+			},
+		}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: "pending", Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate: "password",
+				AnnotationRotate:       "7d",
+				AnnotationGeneratedAt:  generatedAt.Format(time.RFC3339),
+			},
+		}},
+		// In sync, with a key of its own
+		replicaSecret("current", "production/db", map[string][]byte{"password": []byte("new"), "own": []byte("x")}),
+		// The source changed while the operator was not running
+		replicaSecret("outdated", "production/db", map[string][]byte{"password": []byte("old")}),
+		// The source was deleted while the operator was not running
+		replicaSecret("orphaned", "production/gone", map[string][]byte{"password": []byte("old")}),
+		drifted,
+		stale,
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+	registry := prometheus.NewRegistry()
+	depth := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: workqueueDepthMetric},
+		[]string{"name", "controller", "priority"})
+	activeWorkers := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: activeWorkersMetric}, []string{"controller"})
+	registry.MustRegister(depth, activeWorkers)
+	depth.WithLabelValues("secret", "secret", "").Set(0)
+	activeWorkers.WithLabelValues("secret").Set(0)
+
+	report := &StartupReport{
+		Reader:   fakeClient,
+		Config:   config.NewDefaultConfig(),
+		Started:  now.Add(-30 * time.Second),
+		Gatherer: registry,
+		Clock:    &MockClock{currentTime: now},
+	}
+
+	summary, err := report.summarize(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := StartupSummary{ManagedSecrets: 2, RotationsOverdue: 1, OutdatedReplicas: 3}
+	if summary != expected {
+		t.Errorf("expected %+v, got %+v", expected, summary)
+	}
+
+	if err := report.Start(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := gaugeValue(t, startupSecrets, "replica_outdated"); got != 3 {
+		t.Errorf("expected 3 outdated replicas, got %v", got)
+	}
+	var metric dto.Metric
+	if err := startupResyncDuration.Write(&metric); err != nil {
+		t.Fatalf("failed to write metric: %v", err)
+	}
+	if got := metric.GetGauge().GetValue(); got != 30 {
+		t.Errorf("expected startup resync duration of 30s, got %v", got)
+	}
+}
+
+func TestStartupReportWaitsForWorkqueues(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	depth := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: workqueueDepthMetric},
+		[]string{"name", "controller", "priority"})
+	unfinished := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: workqueueUnfinishedWorkMetric},
+		[]string{"name", "controller"})
+	activeWorkers := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: activeWorkersMetric}, []string{"controller"})
+	registry.MustRegister(depth, unfinished, activeWorkers)
+
+	now := time.Now()
+	checkpoint := &ResyncCheckpoint{}
+	report := &StartupReport{
+		Gatherer:    registry,
+		Checkpoints: []*ResyncCheckpoint{checkpoint, nil},
+		Clock:       &MockClock{currentTime: now},
+	}
+	if drained, err := report.drained(); err != nil || drained {
+		t.Errorf("expected controllers not to be started, got drained=%v err=%v", drained, err)
+	}
+
+	activeWorkers.WithLabelValues("secret").Set(0)
+	depth.WithLabelValues("secret", "secret", "").Set(5)
+	if drained, err := report.drained(); err != nil || drained {
+		t.Errorf("expected pending requests, got drained=%v err=%v", drained, err)
+	}
+
+	depth.WithLabelValues("secret", "secret", "").Set(0)
+	activeWorkers.WithLabelValues("secret").Set(1)
+	unfinished.WithLabelValues("secret", "secret").Set(2)
+	if drained, err := report.drained(); err != nil || drained {
+		t.Errorf("expected running reconciles, got drained=%v err=%v", drained, err)
+	}
+
+	activeWorkers.WithLabelValues("secret").Set(0)
+	unfinished.WithLabelValues("secret", "secret").Set(0)
+	checkpoint.deferredUntil = now.Add(time.Minute)
+	if drained, err := report.drained(); err != nil || drained {
+		t.Errorf("expected deferred requests, got drained=%v err=%v", drained, err)
+	}
+
+	checkpoint.deferredUntil = now
+	if drained, err := report.drained(); err != nil || !drained {
+		t.Errorf("expected workqueues to be drained, got drained=%v err=%v", drained, err)
+	}
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+const (
+	// DebugStatusPath is the path of the status endpoint on the metrics server
+	DebugStatusPath = "/debug/status"
+
+	// recentEventsWindow is the window of the Event counts reported by the status endpoint
+	recentEventsWindow = time.Hour
+	// recentEventsBucket is the resolution of the Event counts. Events are counted per bucket, so
+	// the memory does not grow with the number of Events.
+	recentEventsBucket = time.Minute
+)
+
+// OperatorStatus summarizes the state of all Secrets managed by the operator
+type OperatorStatus struct {
+	// ManagedSecrets is the number of Secrets with generated values
+	ManagedSecrets int `json:"managedSecrets"`
+	// RotationsOverdue is the number of managed Secrets whose rotation is overdue
+	RotationsOverdue int `json:"rotationsOverdue"`
+	// RotationPausedNamespaces is the number of namespaces with rotation paused
+	RotationPausedNamespaces int `json:"rotationPausedNamespaces"`
+	// ReplicatedSecrets is the number of Secrets replicated from another Secret
+	ReplicatedSecrets int `json:"replicatedSecrets"`
+	// StaleReplicas is the number of replicated Secrets whose source was deleted
+	StaleReplicas int `json:"staleReplicas"`
+	// DeniedReplicationsLastHour is the number of ReplicationDenied Events in the last hour
+	DeniedReplicationsLastHour int `json:"deniedReplicationsLastHour"`
+	// WorkqueueDepth is the number of pending reconcile requests per controller
+	WorkqueueDepth map[string]int `json:"workqueueDepth"`
+	// Features lists the optional features and whether they are enabled
+	Features map[string]bool `json:"features"`
+	// Settings lists the configuration values that affect all Secrets
+	Settings map[string]string `json:"settings"`
+}
+
+// StatusHandler serves a cluster-wide summary of the managed Secrets and the configuration in
+// effect. Like the DebugHandler, it is served on the metrics server.
+type StatusHandler struct {
+	Reader client.Reader
+	Config *config.Config
+	// Gatherer provides the workqueue metrics. If nil, the controller-runtime registry is used.
+	Gatherer prometheus.Gatherer
+	// Clock is used to get the current time. If nil, time.Now() is used.
+	Clock Clock
+}
+
+// ServeHTTP implements http.Handler
+func (h *StatusHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status, err := h.status(req)
+	if err != nil {
+		log.FromContext(req.Context()).Error(err, "Failed to collect status")
+		http.Error(w, "Failed to collect status", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(status)
+}
+
+// status collects the current status
+func (h *StatusHandler) status(req *http.Request) (*OperatorStatus, error) {
+	ctx := req.Context()
+
+	var secrets corev1.SecretList
+	if err := h.Reader.List(ctx, &secrets); err != nil {
+		return nil, err
+	}
+	var namespaces corev1.NamespaceList
+	if err := h.Reader.List(ctx, &namespaces); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if h.Clock != nil {
+		now = h.Clock.Now()
+	}
+
+	status := &OperatorStatus{
+		DeniedReplicationsLastHour: recentEvents.count(EventReasonReplicationDenied, now),
+		Features:                   statusFeatures(h.Config),
+		Settings:                   statusSettings(h.Config),
+	}
+	for i := range namespaces.Items {
+		if isRotationPaused(&namespaces.Items[i]) {
+			status.RotationPausedNamespaces++
+		}
+	}
+
+	rotation := &SecretReconciler{Config: h.Config}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if replicator.GetReplicatedFromAnnotation(secret) != "" {
+			status.ReplicatedSecrets++
+		}
+		if replicator.IsStale(secret) {
+			status.StaleReplicas++
+		}
+
+		value, ok := secret.Annotations[AnnotationAutogenerate]
+		if !ok || h.Config.IsSecretTypeIgnored(string(secret.Type)) {
+			continue
+		}
+		status.ManagedSecrets++
+		if next := nextRotationTime(rotation, secret.Annotations, parseFields(value)); next != nil && !next.After(now) {
+			status.RotationsOverdue++
+		}
+	}
+
+	depth, err := gatherWorkqueueDepth(h.Gatherer)
+	if err != nil {
+		return nil, err
+	}
+	status.WorkqueueDepth = depth
+
+	return status, nil
+}
+
+// statusFeatures returns the optional features and whether they are enabled
+func statusFeatures(cfg *config.Config) map[string]bool {
+	return map[string]bool{
+		"secretGenerator":      cfg.Features.SecretGenerator,
+		"secretReplicator":     cfg.Features.SecretReplicator,
+		"validatingWebhook":    cfg.Features.ValidatingWebhook,
+		"serviceAccountTokens": cfg.Features.ServiceAccountTokens,
+		"secretSources":        cfg.Features.SecretSources,
+		"secretDefaults":       cfg.Features.SecretDefaults,
+		"configMapIDs":         cfg.Features.ConfigMapIDs,
+		"reconcileRequests":    cfg.Features.ReconcileRequests,
+		"managedLabel":         cfg.ManagedLabel.Enabled,
+		"activityLog":          cfg.ActivityLog.Enabled,
+		"inventory":            cfg.Inventory.Enabled,
+		"encryptionCheck":      cfg.EncryptionCheck.Enabled,
+		"sharding":             cfg.Sharding.Enabled(),
+	}
+}
+
+// statusSettings returns the configuration values that affect all Secrets
+func statusSettings(cfg *config.Config) map[string]string {
+	return map[string]string{
+		"defaults.type":                cfg.Defaults.Type,
+		"defaults.length":              fmt.Sprint(cfg.Defaults.Length),
+		"rotation.minInterval":         cfg.Rotation.MinInterval.Duration().String(),
+		"rotation.defaultInterval":     cfg.Rotation.DefaultInterval.Duration().String(),
+		"replication.driftPolicy":      cfg.Replication.DriftPolicy,
+		"replication.deniedNamespaces": fmt.Sprint(len(cfg.Replication.DeniedNamespaces)),
+		"policies":                     fmt.Sprint(len(cfg.Policies)),
+	}
+}
+
+// WriteStatus writes the status as a human-readable summary
+func WriteStatus(w io.Writer, status *OperatorStatus) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Managed Secrets:\t%d\n", status.ManagedSecrets)
+	fmt.Fprintf(tw, "Rotations overdue:\t%d\n", status.RotationsOverdue)
+	fmt.Fprintf(tw, "Namespaces with rotation paused:\t%d\n", status.RotationPausedNamespaces)
+	fmt.Fprintf(tw, "Replicated Secrets:\t%d\n", status.ReplicatedSecrets)
+	fmt.Fprintf(tw, "Stale replicas:\t%d\n", status.StaleReplicas)
+	fmt.Fprintf(tw, "Denied replications (last hour):\t%d\n", status.DeniedReplicationsLastHour)
+
+	fmt.Fprintln(tw, "\nWorkqueue depth:")
+	for _, name := range slices.Sorted(maps.Keys(status.WorkqueueDepth)) {
+		fmt.Fprintf(tw, "  %s\t%d\n", name, status.WorkqueueDepth[name])
+	}
+
+	fmt.Fprintln(tw, "\nFeatures:")
+	for _, name := range slices.Sorted(maps.Keys(status.Features)) {
+		state := "disabled"
+		if status.Features[name] {
+			state = "enabled"
+		}
+		fmt.Fprintf(tw, "  %s\t%s\n", name, state)
+	}
+
+	fmt.Fprintln(tw, "\nSettings:")
+	for _, name := range slices.Sorted(maps.Keys(status.Settings)) {
+		fmt.Fprintf(tw, "  %s\t%s\n", name, status.Settings[name])
+	}
+	return tw.Flush()
+}
+
+// recentEvents records the Events emitted by the operator's controllers in the last hour
+var recentEvents = &eventHistory{events: make(map[string][]eventBucket)}
+
+// eventHistory counts Events by reason within recentEventsWindow, in buckets of recentEventsBucket
+type eventHistory struct {
+	mu     sync.Mutex
+	events map[string][]eventBucket
+}
+
+// eventBucket is the number of Events of a reason that started in one bucket
+type eventBucket struct {
+	start time.Time
+	count int
+}
+
+// record records an Event with the given reason
+func (h *eventHistory) record(reason string, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	buckets := pruneBuckets(h.events[reason], now)
+	start := now.Truncate(recentEventsBucket)
+	if n := len(buckets); n > 0 && buckets[n-1].start.Equal(start) {
+		buckets[n-1].count++
+	} else {
+		buckets = append(buckets, eventBucket{start: start, count: 1})
+	}
+	h.events[reason] = buckets
+}
+
+// count returns the number of Events with the given reason within the window
+func (h *eventHistory) count(reason string, now time.Time) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	buckets := pruneBuckets(h.events[reason], now)
+	if buckets == nil {
+		delete(h.events, reason)
+		return 0
+	}
+	h.events[reason] = buckets
+	total := 0
+	for _, bucket := range buckets {
+		total += bucket.count
+	}
+	return total
+}
+
+// pruneBuckets removes the buckets that ended before the window
+func pruneBuckets(buckets []eventBucket, now time.Time) []eventBucket {
+	cutoff := now.Add(-recentEventsWindow)
+	i := 0
+	for i < len(buckets) && !buckets[i].start.Add(recentEventsBucket).After(cutoff) {
+		i++
+	}
+	if i == len(buckets) {
+		return nil
+	}
+	return slices.Clone(buckets[i:])
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+func TestStatusHandler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	generatedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := generatedAt.Add(2 * time.Hour)
+	objs := []client.Object{
+		pausedNamespace("frozen"),
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: "due", Namespace: "frozen",
+			Annotations: map[string]string{
+				AnnotationAutogenerate: "password",
+				AnnotationRotate:       "1h",
+				AnnotationGeneratedAt:  generatedAt.Format(time.RFC3339),
+			},
+		}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: "pending", Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationAutogenerate: "password",
+				AnnotationRotate:       "7d",
+				AnnotationGeneratedAt:  generatedAt.Format(time.RFC3339),
+			},
+		}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: "replica", Namespace: "default",
+			Annotations: map[string]string{
+				replicator.AnnotationReplicatedFrom:  "production/db",
+				replicator.AnnotationSourceDeletedAt: generatedAt.Format(time.RFC3339),
+			},
+		}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "manual", Namespace: "default"}},
+	}
+
+	previous := recentEvents
+	recentEvents = &eventHistory{events: make(map[string][]eventBucket)}
+	t.Cleanup(func() { recentEvents = previous })
+	recentEvents.record(EventReasonReplicationDenied, now.Add(-2*time.Hour))
+	recentEvents.record(EventReasonReplicationDenied, now.Add(-30*time.Minute))
+	recentEvents.record(EventReasonReplicationDenied, now.Add(-time.Minute))
+	recentEvents.record(EventReasonReplicationSucceeded, now)
+
+	cfg := config.NewDefaultConfig()
+	cfg.Features.SecretReplicator = false
+	handler := &StatusHandler{
+		Reader:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		Config:   cfg,
+		Gatherer: prometheus.NewRegistry(),
+		Clock:    &MockClock{currentTime: now},
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, DebugStatusPath, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	var status OperatorStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	expected := map[string][2]int{
+		"managedSecrets":             {status.ManagedSecrets, 2},
+		"rotationsOverdue":           {status.RotationsOverdue, 1},
+		"rotationPausedNamespaces":   {status.RotationPausedNamespaces, 1},
+		"replicatedSecrets":          {status.ReplicatedSecrets, 1},
+		"staleReplicas":              {status.StaleReplicas, 1},
+		"deniedReplicationsLastHour": {status.DeniedReplicationsLastHour, 2},
+	}
+	for name, values := range expected {
+		if values[0] != values[1] {
+			t.Errorf("expected %s to be %d, got %d", name, values[1], values[0])
+		}
+	}
+	if !status.Features["secretGenerator"] || status.Features["secretReplicator"] {
+		t.Errorf("expected features of the configuration, got %v", status.Features)
+	}
+	if status.Settings["replication.driftPolicy"] != cfg.Replication.DriftPolicy {
+		t.Errorf("expected drift policy setting, got %v", status.Settings)
+	}
+
+	var out bytes.Buffer
+	if err := WriteStatus(&out, &status); err != nil {
+		t.Fatalf("failed to write status: %v", err)
+	}
+	for _, line := range []string{"Managed Secrets:", "Rotations overdue:", "Denied replications (last hour):", "secretReplicator", "disabled"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("expected output to contain %q, got:\n%s", line, out.String())
+		}
+	}
+}
+
+func TestStatusHandlerMethodNotAllowed(t *testing.T) {
+	handler := &StatusHandler{Config: config.NewDefaultConfig()}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, DebugStatusPath, nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", rec.Code)
+	}
+}
+
+func TestEventHistoryIsBounded(t *testing.T) {
+	history := &eventHistory{events: make(map[string][]eventBucket)}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 10000; i++ {
+		history.record(EventReasonReplicationDenied, now.Add(time.Duration(i)*time.Millisecond))
+	}
+	if buckets := len(history.events[EventReasonReplicationDenied]); buckets != 1 {
+		t.Errorf("expected Events of one minute to share a bucket, got %d buckets", buckets)
+	}
+	if got := history.count(EventReasonReplicationDenied, now.Add(30*time.Minute)); got != 10000 {
+		t.Errorf("expected 10000 Events within the window, got %d", got)
+	}
+	if got := history.count(EventReasonReplicationDenied, now.Add(2*time.Hour)); got != 0 {
+		t.Errorf("expected no Events after the window, got %d", got)
+	}
+	if _, ok := history.events[EventReasonReplicationDenied]; ok {
+		t.Error("expected the reason to be removed after the window")
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+const (
+	// MetricWorkqueueDepth is the name of the gauge with the pending reconcile requests per controller
+	MetricWorkqueueDepth = "secret_operator_workqueue_depth"
+
+	// MetricWorkqueueAddRate is the name of the gauge with the reconcile requests added per second
+	MetricWorkqueueAddRate = "secret_operator_workqueue_add_rate"
+
+	// MetricWorkqueueRetryRate is the name of the gauge with the reconcile requests retried per second
+	MetricWorkqueueRetryRate = "secret_operator_workqueue_retry_rate"
+
+	// MetricWorkqueueLongestQueued is the name of the gauge with the time since a controller's
+	// queue was last seen empty
+	MetricWorkqueueLongestQueued = "secret_operator_workqueue_longest_queued_seconds"
+
+	// workqueueAddsMetric is the controller-runtime counter of requests added per controller
+	workqueueAddsMetric = "workqueue_adds_total"
+
+	// workqueueRetriesMetric is the controller-runtime counter of requests retried per controller
+	workqueueRetriesMetric = "workqueue_retries_total"
+
+	// workqueueMonitorInterval is the interval at which the workqueue metrics are sampled
+	workqueueMonitorInterval = 15 * time.Second
+)
+
+var (
+	workqueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: MetricWorkqueueDepth,
+		Help: "Number of pending reconcile requests per controller",
+	}, []string{"controller"})
+	workqueueAddRate = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: MetricWorkqueueAddRate,
+		Help: "Reconcile requests added per second per controller, over the last sampling interval",
+	}, []string{"controller"})
+	workqueueRetryRate = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: MetricWorkqueueRetryRate,
+		Help: "Reconcile requests retried per second per controller, over the last sampling interval",
+	}, []string{"controller"})
+	workqueueLongestQueued = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: MetricWorkqueueLongestQueued,
+		Help: "Time since the queue of a controller was last seen empty, an upper bound of the time " +
+			"its oldest pending reconcile request has been queued",
+	}, []string{"controller"})
+)
+
+// workqueueSample is the state of a controller's queue at the previous sample
+type workqueueSample struct {
+	adds    float64
+	retries float64
+	// emptyAt is the last time the queue was seen empty
+	emptyAt time.Time
+	// backpressure is true while a backpressure warning is active
+	backpressure bool
+}
+
+// WorkqueueMonitor periodically samples the controller-runtime workqueue metrics, exports the
+// depth, add and retry rates and the queued age per controller, and logs a warning when a
+// controller falls behind, so that capacity issues show up before rotations start slipping
+type WorkqueueMonitor struct {
+	Config *config.Config
+	// Gatherer provides the workqueue metrics. If nil, the controller-runtime registry is used.
+	Gatherer prometheus.Gatherer
+	// Clock is used to get the current time. If nil, time.Now() is used.
+	Clock Clock
+
+	samples map[string]*workqueueSample
+	last    time.Time
+}
+
+// SetupWithManager registers the metrics and adds the monitor to the Manager
+func (m *WorkqueueMonitor) SetupWithManager(mgr ctrl.Manager) error {
+	for _, collector := range []prometheus.Collector{workqueueDepth, workqueueAddRate, workqueueRetryRate, workqueueLongestQueued} {
+		if err := registerCollector(collector); err != nil {
+			return err
+		}
+	}
+	return mgr.Add(m)
+}
+
+// NeedLeaderElection returns false, because every replica has its own workqueues
+func (m *WorkqueueMonitor) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements manager.Runnable
+func (m *WorkqueueMonitor) Start(ctx context.Context) error {
+	ticker := time.NewTicker(workqueueMonitorInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.sample(ctx); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to sample workqueue metrics")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// now returns the current time using the configured clock
+func (m *WorkqueueMonitor) now() time.Time {
+	if m.Clock == nil {
+		return time.Now()
+	}
+	return m.Clock.Now()
+}
+
+// sample updates the workqueue metrics of all controllers and logs backpressure changes
+func (m *WorkqueueMonitor) sample(ctx context.Context) error {
+	families, err := gatherWorkqueueMetrics(m.Gatherer)
+	if err != nil {
+		return err
+	}
+	logger := log.FromContext(ctx)
+
+	now := m.now()
+	elapsed := now.Sub(m.last).Seconds()
+	if m.samples == nil {
+		m.samples = make(map[string]*workqueueSample)
+		elapsed = 0
+	}
+	m.last = now
+
+	for name, depth := range families[workqueueDepthMetric] {
+		adds, retries := families[workqueueAddsMetric][name], families[workqueueRetriesMetric][name]
+		previous, ok := m.samples[name]
+		if !ok {
+			previous = &workqueueSample{adds: adds, retries: retries, emptyAt: now}
+			m.samples[name] = previous
+		}
+
+		workqueueDepth.WithLabelValues(name).Set(depth)
+		if elapsed > 0 {
+			workqueueAddRate.WithLabelValues(name).Set((adds - previous.adds) / elapsed)
+			workqueueRetryRate.WithLabelValues(name).Set((retries - previous.retries) / elapsed)
+		}
+		if depth == 0 {
+			previous.emptyAt = now
+		}
+		queued := now.Sub(previous.emptyAt)
+		workqueueLongestQueued.WithLabelValues(name).Set(queued.Seconds())
+		previous.adds, previous.retries = adds, retries
+
+		// Log once when a controller falls behind and once when it recovers
+		depthThreshold, ageThreshold := m.Config.Metrics.WorkqueueDepthThreshold, m.Config.Metrics.WorkqueueAgeThreshold.Duration()
+		backpressure := (depthThreshold > 0 && int(depth) > depthThreshold) || (ageThreshold > 0 && queued > ageThreshold)
+		switch {
+		case backpressure && !previous.backpressure:
+			logger.Info("Workqueue backpressure: controller is falling behind",
+				"controller", name, "depth", int(depth), "queuedFor", queued.Truncate(time.Second))
+		case !backpressure && previous.backpressure:
+			logger.Info("Workqueue backpressure resolved", "controller", name, "depth", int(depth))
+		}
+		previous.backpressure = backpressure
+	}
+	return nil
+}
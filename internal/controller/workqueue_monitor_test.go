@@ -0,0 +1,117 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+func gaugeValue(t *testing.T, gauge *prometheus.GaugeVec, controller string) float64 {
+	t.Helper()
+	var metric dto.Metric
+	if err := gauge.WithLabelValues(controller).Write(&metric); err != nil {
+		t.Fatalf("failed to write metric: %v", err)
+	}
+	return metric.GetGauge().GetValue()
+}
+
+func TestWorkqueueMonitor(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	depth := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: workqueueDepthMetric},
+		[]string{"name", "controller", "priority"})
+	adds := prometheus.NewCounterVec(prometheus.CounterOpts{Name: workqueueAddsMetric},
+		[]string{"name", "controller"})
+	retries := prometheus.NewCounterVec(prometheus.CounterOpts{Name: workqueueRetriesMetric},
+		[]string{"name", "controller"})
+	registry.MustRegister(depth, adds, retries)
+
+	cfg := config.NewDefaultConfig()
+	cfg.Metrics.WorkqueueDepthThreshold = 100
+	cfg.Metrics.WorkqueueAgeThreshold = config.Duration(time.Minute)
+
+	clock := &MockClock{currentTime: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	monitor := &WorkqueueMonitor{Config: cfg, Gatherer: registry, Clock: clock}
+	ctx := context.Background()
+	const name = "test-workqueue-monitor"
+
+	depth.WithLabelValues(name, name, "").Set(0)
+	if err := monitor.sample(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Requests are added faster than they are processed; the depth of all priorities is summed
+	clock.currentTime = clock.currentTime.Add(10 * time.Second)
+	depth.WithLabelValues(name, name, "").Set(30)
+	depth.WithLabelValues(name, name, "100").Set(10)
+	adds.WithLabelValues(name, name).Add(50)
+	retries.WithLabelValues(name, name).Add(5)
+	if err := monitor.sample(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := gaugeValue(t, workqueueDepth, name); got != 40 {
+		t.Errorf("expected depth 40, got %v", got)
+	}
+	if got := gaugeValue(t, workqueueAddRate, name); got != 5 {
+		t.Errorf("expected add rate 5/s, got %v", got)
+	}
+	if got := gaugeValue(t, workqueueRetryRate, name); got != 0.5 {
+		t.Errorf("expected retry rate 0.5/s, got %v", got)
+	}
+	if got := gaugeValue(t, workqueueLongestQueued, name); got != 10 {
+		t.Errorf("expected longest queued 10s, got %v", got)
+	}
+	if monitor.samples[name].backpressure {
+		t.Error("expected no backpressure below the thresholds")
+	}
+
+	// The queue does not drain for longer than the age threshold
+	clock.currentTime = clock.currentTime.Add(time.Minute)
+	if err := monitor.sample(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := gaugeValue(t, workqueueLongestQueued, name); got != 70 {
+		t.Errorf("expected longest queued 70s, got %v", got)
+	}
+	if got := gaugeValue(t, workqueueAddRate, name); got != 0 {
+		t.Errorf("expected add rate 0/s, got %v", got)
+	}
+	if !monitor.samples[name].backpressure {
+		t.Error("expected backpressure above the age threshold")
+	}
+
+	// An empty queue resets the age and resolves the backpressure
+	clock.currentTime = clock.currentTime.Add(10 * time.Second)
+	depth.WithLabelValues(name, name, "").Set(0)
+	depth.WithLabelValues(name, name, "100").Set(0)
+	if err := monitor.sample(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := gaugeValue(t, workqueueLongestQueued, name); got != 0 {
+		t.Errorf("expected longest queued 0s, got %v", got)
+	}
+	if monitor.samples[name].backpressure {
+		t.Error("expected backpressure to be resolved")
+	}
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsauth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClientCATLSOption(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := ClientCATLSOption(filepath.Join(dir, "missing.crt")); err == nil {
+		t.Error("expected error for missing CA file")
+	}
+
+	invalid := filepath.Join(dir, "invalid.crt")
+	if err := os.WriteFile(invalid, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if _, err := ClientCATLSOption(invalid); err == nil {
+		t.Error("expected error for CA file without certificates")
+	}
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package migrate translates the annotations of other Secret replicators
+// (kubernetes-replicator, kubed) to the annotations of this operator
+package migrate
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+const (
+	// MittwaldPrefix is the annotation prefix of kubernetes-replicator
+	MittwaldPrefix = "replicator.v1.mittwald.de/"
+	// KubedPrefix is the annotation prefix of kubed
+	KubedPrefix = "kubed.appscode.com/"
+
+	mittwaldReplicationAllowed           = MittwaldPrefix + "replication-allowed"
+	mittwaldReplicationAllowedNamespaces = MittwaldPrefix + "replication-allowed-namespaces"
+	mittwaldReplicateFrom                = MittwaldPrefix + "replicate-from"
+	mittwaldReplicateTo                  = MittwaldPrefix + "replicate-to"
+	kubedSync                            = KubedPrefix + "sync"
+)
+
+// translatedAnnotations lists the third-party annotations that have an equivalent
+var translatedAnnotations = []string{
+	mittwaldReplicationAllowed,
+	mittwaldReplicationAllowedNamespaces,
+	mittwaldReplicateFrom,
+	mittwaldReplicateTo,
+	kubedSync,
+}
+
+// Change describes the annotation changes for a single Secret
+type Change struct {
+	Namespace string
+	Name      string
+	// Removed holds the third-party annotations that are replaced
+	Removed map[string]string
+	// Added holds the annotations of this operator that replace them
+	Added map[string]string
+	// Warnings lists annotations that cannot be translated and are left untouched
+	Warnings []string
+
+	// origins holds the removed annotations each added annotation replaces
+	origins map[string][]string
+}
+
+// replace records that the given third-party annotations are replaced by an annotation of this operator
+func (c *Change) replace(key, value string, annotations map[string]string, replaced ...string) {
+	c.Added[key] = value
+	for _, old := range replaced {
+		c.Removed[old] = annotations[old]
+		c.origins[key] = append(c.origins[key], old)
+	}
+}
+
+// IsEmpty returns true if the change neither modifies annotations nor has warnings
+func (c *Change) IsEmpty() bool {
+	return len(c.Removed) == 0 && len(c.Added) == 0 && len(c.Warnings) == 0
+}
+
+// Apply returns the annotations with the change applied
+func (c *Change) Apply(annotations map[string]string) map[string]string {
+	result := maps.Clone(annotations)
+	if result == nil {
+		result = make(map[string]string)
+	}
+	for key := range c.Removed {
+		delete(result, key)
+	}
+	maps.Copy(result, c.Added)
+	return result
+}
+
+// Translate computes the changes that migrate the third-party replication annotations of a
+// Secret in the given namespace. Annotations without an equivalent are reported as warnings.
+func Translate(namespace, name string, annotations map[string]string) *Change {
+	change := &Change{
+		Namespace: namespace,
+		Name:      name,
+		Removed:   make(map[string]string),
+		Added:     make(map[string]string),
+		origins:   make(map[string][]string),
+	}
+
+	translateMittwald(change, annotations)
+	translateKubed(change, annotations)
+
+	// Translation failures of known annotations are already reported
+	for _, key := range slices.Sorted(maps.Keys(annotations)) {
+		if !strings.HasPrefix(key, MittwaldPrefix) && !strings.HasPrefix(key, KubedPrefix) {
+			continue
+		}
+		if slices.Contains(translatedAnnotations, key) || isStatusAnnotation(key) {
+			continue
+		}
+		change.Warnings = append(change.Warnings, fmt.Sprintf("%s has no equivalent and is left untouched", key))
+	}
+
+	// Never overwrite annotations of this operator that are already set to a different value;
+	// the third-party annotations are kept in that case
+	for _, key := range slices.Sorted(maps.Keys(change.Added)) {
+		if existing, ok := annotations[key]; ok && existing != change.Added[key] {
+			change.Warnings = append(change.Warnings,
+				fmt.Sprintf("%s is already set to %q, not replacing it with %q", key, existing, change.Added[key]))
+			delete(change.Added, key)
+			for _, old := range change.origins[key] {
+				delete(change.Removed, old)
+			}
+		}
+	}
+
+	return change
+}
+
+// translateMittwald translates kubernetes-replicator annotations. A source allows replication
+// with replication-allowed "true" (all namespaces) or an explicit replication-allowed-namespaces list.
+func translateMittwald(change *Change, annotations map[string]string) {
+	allowed, hasAllowed := annotations[mittwaldReplicationAllowed]
+	allowedNamespaces, hasAllowedNamespaces := annotations[mittwaldReplicationAllowedNamespaces]
+
+	switch {
+	case hasAllowedNamespaces:
+		if patterns, ok := translatePatterns(change, mittwaldReplicationAllowedNamespaces, allowedNamespaces); ok {
+			replaced := []string{mittwaldReplicationAllowedNamespaces}
+			if hasAllowed {
+				replaced = append(replaced, mittwaldReplicationAllowed)
+			}
+			change.replace(replicator.AnnotationReplicatableFromNamespaces, patterns, annotations, replaced...)
+		}
+	case hasAllowed && allowed == "true":
+		change.replace(replicator.AnnotationReplicatableFromNamespaces, "*", annotations, mittwaldReplicationAllowed)
+	case hasAllowed:
+		// Replication explicitly not allowed, which is the default of this operator
+		change.Removed[mittwaldReplicationAllowed] = allowed
+	}
+
+	if value, ok := annotations[mittwaldReplicateFrom]; ok {
+		// kubernetes-replicator allows omitting the namespace for sources in the same namespace
+		source := strings.TrimSpace(value)
+		if !strings.Contains(source, "/") {
+			source = change.Namespace + "/" + source
+		}
+		change.replace(replicator.AnnotationReplicateFrom, source, annotations, mittwaldReplicateFrom)
+	}
+
+	if value, ok := annotations[mittwaldReplicateTo]; ok {
+		if patterns, ok := translatePatterns(change, mittwaldReplicateTo, value); ok {
+			change.replace(replicator.AnnotationReplicateTo, patterns, annotations, mittwaldReplicateTo)
+		}
+	}
+}
+
+// translateKubed translates kubed annotations. An empty sync annotation pushes the Secret to all
+// namespaces; a namespace label selector has no equivalent.
+func translateKubed(change *Change, annotations map[string]string) {
+	value, ok := annotations[kubedSync]
+	if !ok {
+		return
+	}
+	if strings.TrimSpace(value) != "" {
+		change.Warnings = append(change.Warnings,
+			fmt.Sprintf("%s=%q selects namespaces by label, which push replication does not support", kubedSync, value))
+		return
+	}
+	change.replace(replicator.AnnotationReplicateTo, replicator.ReplicateToAllNamespaces, annotations, kubedSync)
+}
+
+// simpleRegexp matches regular expressions that are a namespace name prefix followed by ".*"
+var simpleRegexp = regexp.MustCompile(`^\^?([a-z0-9-]*)\.\*\$?$`)
+
+// translatePatterns translates a comma-separated list of namespace names or regular expressions
+// into glob patterns. Only names and "prefix.*" expressions can be translated.
+func translatePatterns(change *Change, key, value string) (string, bool) {
+	var patterns []string
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if len(validation.IsDNS1123Label(entry)) == 0 {
+			patterns = append(patterns, entry)
+			continue
+		}
+		if match := simpleRegexp.FindStringSubmatch(entry); match != nil {
+			patterns = append(patterns, match[1]+"*")
+			continue
+		}
+		change.Warnings = append(change.Warnings,
+			fmt.Sprintf("%s contains the regular expression %q, which cannot be translated to a glob pattern", key, entry))
+		return "", false
+	}
+	if len(patterns) == 0 {
+		return "", false
+	}
+	return strings.Join(patterns, ","), true
+}
+
+// isStatusAnnotation returns true for annotations the third-party replicators write on
+// replicated Secrets; they are obsolete after the migration and not reported
+func isStatusAnnotation(key string) bool {
+	switch strings.TrimPrefix(strings.TrimPrefix(key, MittwaldPrefix), KubedPrefix) {
+	case "replicated-at", "replicated-from-version", "replicated-keys", "origin":
+		return true
+	}
+	return false
+}
+
+// WriteDiff writes the change as a diff of the annotations
+func WriteDiff(w io.Writer, change *Change) {
+	fmt.Fprintf(w, "secret %s/%s\n", change.Namespace, change.Name)
+	for _, key := range slices.Sorted(maps.Keys(change.Removed)) {
+		fmt.Fprintf(w, "- %s: %q\n", key, change.Removed[key])
+	}
+	for _, key := range slices.Sorted(maps.Keys(change.Added)) {
+		fmt.Fprintf(w, "+ %s: %q\n", key, change.Added[key])
+	}
+	for _, warning := range change.Warnings {
+		fmt.Fprintf(w, "! %s\n", warning)
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrate
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name            string
+		annotations     map[string]string
+		expectedAdded   map[string]string
+		expectedRemoved []string
+		expectWarning   string
+	}{
+		{
+			name:        "no third-party annotations",
+			annotations: map[string]string{"other": "value"},
+		},
+		{
+			name: "mittwald allowed in all namespaces",
+			annotations: map[string]string{
+				mittwaldReplicationAllowed: "true",
+			},
+			expectedAdded:   map[string]string{replicator.AnnotationReplicatableFromNamespaces: "*"},
+			expectedRemoved: []string{mittwaldReplicationAllowed},
+		},
+		{
+			name: "mittwald allowed namespaces with regular expression",
+			annotations: map[string]string{
+				mittwaldReplicationAllowed:           "true",
+				mittwaldReplicationAllowedNamespaces: "staging, team-.*",
+			},
+			expectedAdded:   map[string]string{replicator.AnnotationReplicatableFromNamespaces: "staging,team-*"},
+			expectedRemoved: []string{mittwaldReplicationAllowed, mittwaldReplicationAllowedNamespaces},
+		},
+		{
+			name: "mittwald untranslatable regular expression",
+			annotations: map[string]string{
+				mittwaldReplicationAllowedNamespaces: "(dev|test)-[0-9]+",
+			},
+			expectWarning: "cannot be translated",
+		},
+		{
+			name: "mittwald replication not allowed",
+			annotations: map[string]string{
+				mittwaldReplicationAllowed: "false",
+			},
+			expectedRemoved: []string{mittwaldReplicationAllowed},
+		},
+		{
+			name: "mittwald pull from same namespace",
+			annotations: map[string]string{
+				mittwaldReplicateFrom: "db-credentials",
+			},
+			expectedAdded:   map[string]string{replicator.AnnotationReplicateFrom: "apps/db-credentials"},
+			expectedRemoved: []string{mittwaldReplicateFrom},
+		},
+		{
+			name: "mittwald push",
+			annotations: map[string]string{
+				mittwaldReplicateTo: "staging,dev",
+			},
+			expectedAdded:   map[string]string{replicator.AnnotationReplicateTo: "staging,dev"},
+			expectedRemoved: []string{mittwaldReplicateTo},
+		},
+		{
+			name: "mittwald push by label has no equivalent",
+			annotations: map[string]string{
+				MittwaldPrefix + "replicate-to-matching": "env=dev",
+			},
+			expectWarning: "replicate-to-matching has no equivalent",
+		},
+		{
+			name: "kubed sync to all namespaces",
+			annotations: map[string]string{
+				kubedSync: "",
+			},
+			expectedAdded:   map[string]string{replicator.AnnotationReplicateTo: "*"},
+			expectedRemoved: []string{kubedSync},
+		},
+		{
+			name: "kubed sync by label",
+			annotations: map[string]string{
+				kubedSync: "app=kubed",
+			},
+			expectWarning: "selects namespaces by label",
+		},
+		{
+			name: "existing annotation is not overwritten",
+			annotations: map[string]string{
+				mittwaldReplicateTo:              "staging",
+				replicator.AnnotationReplicateTo: "production",
+			},
+			expectWarning: "already set",
+		},
+		{
+			name: "status annotations are ignored",
+			annotations: map[string]string{
+				MittwaldPrefix + "replicated-at": "2025-01-01T00:00:00Z",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change := Translate("apps", "secret", tt.annotations)
+
+			expectedAdded := tt.expectedAdded
+			if expectedAdded == nil {
+				expectedAdded = map[string]string{}
+			}
+			if !reflect.DeepEqual(change.Added, expectedAdded) {
+				t.Errorf("expected added %v, got %v", expectedAdded, change.Added)
+			}
+			if len(change.Removed) != len(tt.expectedRemoved) {
+				t.Errorf("expected removed %v, got %v", tt.expectedRemoved, change.Removed)
+			}
+			for _, key := range tt.expectedRemoved {
+				if _, ok := change.Removed[key]; !ok {
+					t.Errorf("expected %s to be removed", key)
+				}
+			}
+
+			warnings := strings.Join(change.Warnings, "\n")
+			if tt.expectWarning == "" && warnings != "" {
+				t.Errorf("unexpected warnings: %s", warnings)
+			}
+			if tt.expectWarning != "" && !strings.Contains(warnings, tt.expectWarning) {
+				t.Errorf("expected warning containing %q, got %q", tt.expectWarning, warnings)
+			}
+		})
+	}
+}
+
+func TestChangeApplyAndDiff(t *testing.T) {
+	annotations := map[string]string{mittwaldReplicateTo: "staging", "other": "value"}
+	change := Translate("apps", "registry", annotations)
+
+	applied := change.Apply(annotations)
+	expected := map[string]string{replicator.AnnotationReplicateTo: "staging", "other": "value"}
+	if !reflect.DeepEqual(applied, expected) {
+		t.Errorf("expected %v, got %v", expected, applied)
+	}
+
+	var out bytes.Buffer
+	WriteDiff(&out, change)
+	expectedDiff := "secret apps/registry\n" +
+		"- replicator.v1.mittwald.de/replicate-to: \"staging\"\n" +
+		"+ iso.gtrfc.com/replicate-to: \"staging\"\n"
+	if out.String() != expectedDiff {
+		t.Errorf("expected diff:\n%s\ngot:\n%s", expectedDiff, out.String())
+	}
+}
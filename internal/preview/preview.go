@@ -0,0 +1,307 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package preview runs the reconcilers offline against a fake client to show how the operator
+// would change a set of Secrets, e.g. in a CI plan step
+package preview
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/guided-traffic/internal-secrets-operator/internal/controller"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+)
+
+const (
+	// StatusCreated marks Secrets that the operator would create (e.g. push targets)
+	StatusCreated = "created"
+	// StatusUpdated marks Secrets that the operator would modify
+	StatusUpdated = "updated"
+	// StatusUnchanged marks Secrets that the operator would leave untouched
+	StatusUnchanged = "unchanged"
+
+	// RedactedValue replaces data values that are not part of the input, i.e. generated values
+	RedactedValue = "<generated>"
+
+	// reconcilePasses is the number of times all Secrets are reconciled, so that replication
+	// picks up values generated in an earlier pass
+	reconcilePasses = 3
+)
+
+// SecretResult is a Secret as the operator would leave it
+type SecretResult struct {
+	Status string
+	Secret *corev1.Secret
+}
+
+// Event is an Event the operator would emit
+type Event struct {
+	Namespace string
+	Name      string
+	Type      string
+	Reason    string
+	Message   string
+}
+
+// Result is the outcome of a preview
+type Result struct {
+	Secrets []SecretResult
+	Events  []Event
+}
+
+// HasWarnings returns true if the operator would emit Warning Events
+func (r *Result) HasWarnings() bool {
+	for _, event := range r.Events {
+		if event.Type == corev1.EventTypeWarning {
+			return true
+		}
+	}
+	return false
+}
+
+// Decode reads Secrets and Namespaces from a multi-document YAML or JSON stream.
+// Other kinds are ignored.
+func Decode(r io.Reader) ([]client.Object, error) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	deserializer := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+
+	var objects []client.Object
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(r))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read document: %w", err)
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		obj, _, err := deserializer.Decode(doc, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode document: %w", err)
+		}
+		switch o := obj.(type) {
+		case *corev1.Secret:
+			if o.Namespace == "" {
+				o.Namespace = metav1.NamespaceDefault
+			}
+			// The API server merges stringData into data, the fake client does not
+			controller.NormalizeStringData(o)
+			objects = append(objects, o)
+		case *corev1.Namespace:
+			objects = append(objects, o)
+		}
+	}
+	return objects, nil
+}
+
+// Run reconciles the given Secrets and Namespaces with a fake client and returns all Secrets
+// as the operator would leave them. Namespaces of the Secrets are created implicitly.
+// Values that do not appear in the input are replaced by RedactedValue.
+func Run(ctx context.Context, cfg *config.Config, objects []client.Object) (*Result, error) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	inputs := make(map[types.NamespacedName]*corev1.Secret)
+	inputValues := make(map[string]bool)
+	namespaces := make(map[string]bool)
+	for _, obj := range objects {
+		switch o := obj.(type) {
+		case *corev1.Secret:
+			inputs[client.ObjectKeyFromObject(o)] = o.DeepCopy()
+			for _, value := range o.Data {
+				inputValues[string(value)] = true
+			}
+		case *corev1.Namespace:
+			namespaces[o.Name] = true
+		}
+	}
+
+	all := append([]client.Object{}, objects...)
+	for key := range inputs {
+		if !namespaces[key.Namespace] {
+			namespaces[key.Namespace] = true
+			all = append(all, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: key.Namespace}})
+		}
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(all...).Build()
+	recorder := &eventRecorder{}
+	reconcilers := []interface {
+		Reconcile(context.Context, ctrl.Request) (ctrl.Result, error)
+	}{
+		&controller.SecretReconciler{
+			Client:        fakeClient,
+			Scheme:        scheme,
+			Generator:     generator.NewSecretGeneratorWithCharset(cfg.Defaults.String.BuildCharset()),
+			Config:        cfg,
+			EventRecorder: recorder,
+		},
+		&controller.SecretReplicatorReconciler{
+			Client:        fakeClient,
+			Scheme:        scheme,
+			Config:        cfg,
+			EventRecorder: recorder,
+		},
+	}
+
+	for pass := 0; pass < reconcilePasses; pass++ {
+		secrets, err := listSecrets(ctx, fakeClient)
+		if err != nil {
+			return nil, err
+		}
+		for _, secret := range secrets {
+			req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&secret)}
+			for _, reconciler := range reconcilers {
+				if _, err := reconciler.Reconcile(ctx, req); err != nil {
+					return nil, fmt.Errorf("failed to reconcile Secret %s: %w", req.NamespacedName, err)
+				}
+			}
+		}
+	}
+
+	secrets, err := listSecrets(ctx, fakeClient)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{Events: recorder.events}
+	for i := range secrets {
+		secret := &secrets[i]
+		status := StatusCreated
+		if input, ok := inputs[client.ObjectKeyFromObject(secret)]; ok {
+			status = StatusUnchanged
+			if !reflect.DeepEqual(input.Annotations, secret.Annotations) ||
+				!reflect.DeepEqual(input.Data, secret.Data) ||
+				!reflect.DeepEqual(input.Finalizers, secret.Finalizers) {
+				status = StatusUpdated
+			}
+		}
+		result.Secrets = append(result.Secrets, SecretResult{Status: status, Secret: redact(secret, inputValues)})
+	}
+	return result, nil
+}
+
+// listSecrets returns all Secrets of the fake client sorted by namespace and name
+func listSecrets(ctx context.Context, c client.Client) ([]corev1.Secret, error) {
+	var list corev1.SecretList
+	if err := c.List(ctx, &list); err != nil {
+		return nil, fmt.Errorf("failed to list Secrets: %w", err)
+	}
+	for i := range list.Items {
+		controller.NormalizeStringData(&list.Items[i])
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		if list.Items[i].Namespace != list.Items[j].Namespace {
+			return list.Items[i].Namespace < list.Items[j].Namespace
+		}
+		return list.Items[i].Name < list.Items[j].Name
+	})
+	return list.Items, nil
+}
+
+// redact returns a copy of the Secret for output, with values that are not part of the input
+// replaced and server-populated metadata removed
+func redact(secret *corev1.Secret, inputValues map[string]bool) *corev1.Secret {
+	out := secret.DeepCopy()
+	out.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"}
+	out.ResourceVersion = ""
+	out.UID = ""
+	out.CreationTimestamp = metav1.Time{}
+	out.ManagedFields = nil
+	for key, value := range out.Data {
+		if !inputValues[string(value)] {
+			out.Data[key] = []byte(RedactedValue)
+		}
+	}
+	return out
+}
+
+// Write writes the Secrets of the result as a multi-document YAML stream, followed by the
+// Events as comments
+func Write(w io.Writer, result *Result) error {
+	for _, secret := range result.Secrets {
+		data, err := yaml.Marshal(secret.Secret)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "---\n# %s\n%s", secret.Status, data); err != nil {
+			return err
+		}
+	}
+	if len(result.Events) > 0 {
+		fmt.Fprintln(w, "---\n# Events:")
+		for _, event := range result.Events {
+			fmt.Fprintf(w, "#   %s %s %s/%s: %s\n", event.Type, event.Reason, event.Namespace, event.Name, event.Message)
+		}
+	}
+	return nil
+}
+
+// eventRecorder records Events for the preview result
+type eventRecorder struct {
+	events []Event
+}
+
+// Event implements record.EventRecorder
+func (r *eventRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	event := Event{Type: eventtype, Reason: reason, Message: message}
+	if obj, ok := object.(client.Object); ok {
+		event.Namespace, event.Name = obj.GetNamespace(), obj.GetName()
+	}
+	r.events = append(r.events, event)
+}
+
+// Eventf implements record.EventRecorder
+func (r *eventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// AnnotatedEventf implements record.EventRecorder
+func (r *eventRecorder) AnnotatedEventf(
+	object runtime.Object,
+	_ map[string]string,
+	eventtype, reason, messageFmt string,
+	args ...interface{},
+) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preview
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+const input = `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: db
+  namespace: production
+  annotations:
+    iso.gtrfc.com/autogenerate: password
+    iso.gtrfc.com/replicate-to: staging
+data:
+  username: YWRtaW4=
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: manual
+  namespace: production
+data:
+  token: c2VjcmV0
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ignored
+`
+
+func TestPreview(t *testing.T) {
+	objects, err := Decode(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("failed to decode input: %v", err)
+	}
+	if len(objects) != 2 {
+		t.Fatalf("expected 2 Secrets to be decoded, got %d", len(objects))
+	}
+
+	result, err := Run(context.Background(), config.NewDefaultConfig(), objects)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	statuses := make(map[string]string)
+	for _, secret := range result.Secrets {
+		statuses[secret.Secret.Namespace+"/"+secret.Secret.Name] = secret.Status
+	}
+	expected := map[string]string{
+		"production/db":     StatusUpdated,
+		"production/manual": StatusUnchanged,
+		"staging/db":        StatusCreated,
+	}
+	for key, status := range expected {
+		if statuses[key] != status {
+			t.Errorf("expected %s to be %s, got %q", key, status, statuses[key])
+		}
+	}
+
+	for _, secret := range result.Secrets {
+		if secret.Secret.Name != "db" {
+			continue
+		}
+		if string(secret.Secret.Data["password"]) != RedactedValue {
+			t.Errorf("expected generated password in %s to be redacted, got %q",
+				secret.Secret.Namespace, secret.Secret.Data["password"])
+		}
+		if string(secret.Secret.Data["username"]) != "admin" {
+			t.Errorf("expected input value in %s to be kept, got %q",
+				secret.Secret.Namespace, secret.Secret.Data["username"])
+		}
+	}
+
+	var out bytes.Buffer
+	if err := Write(&out, result); err != nil {
+		t.Fatalf("failed to write result: %v", err)
+	}
+	for _, expected := range []string{"# created\n", "# updated\n", "kind: Secret", "# Events:", "GenerationSucceeded"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, out.String())
+		}
+	}
+}
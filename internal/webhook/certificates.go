@@ -0,0 +1,318 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/clock"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+)
+
+const (
+	// DefaultCertificateDuration is the validity of self-managed webhook serving certificates
+	DefaultCertificateDuration = 365 * 24 * time.Hour
+
+	// certificateRenewalFraction is the fraction of the lifetime after which the certificate is renewed
+	certificateRenewalFraction = 2.0 / 3.0
+
+	// certificateCheckInterval is the longest time between two checks of the certificate Secret
+	// and the CA bundle, so that changes by other replicas or users are picked up
+	certificateCheckInterval = time.Hour
+
+	// certificateRetryInterval is the time between two attempts after a failed check
+	certificateRetryInterval = 10 * time.Second
+
+	// CertificateSecretLabel marks the certificate Secret. The webhook configuration excludes
+	// Secrets with this label, so that the Secret can be written while no replica serves the webhook.
+	CertificateSecretLabel = "iso.gtrfc.com/webhook-certificate"
+)
+
+// CertificateManager maintains a self-signed serving certificate for the webhook server, so that
+// the webhook does not depend on cert-manager. The certificate is stored in a Secret shared by all
+// replicas, written to the certificate directory of the webhook server and injected as CA bundle
+// into the ValidatingWebhookConfiguration. It is renewed after two thirds of its lifetime; the
+// previous certificate stays in the CA bundle until it expires, so that replicas that have not
+// reloaded the new certificate yet keep being trusted.
+type CertificateManager struct {
+	// Reader reads the Secret and the webhook configuration (uncached, so Ensure works before the manager starts)
+	Reader client.Reader
+	// Writer creates and updates the Secret and the webhook configuration
+	Writer client.Writer
+	// Namespace and SecretName locate the certificate Secret
+	Namespace  string
+	SecretName string
+	// ServiceName is the name of the webhook Service in Namespace; the certificate is valid for its DNS names
+	ServiceName string
+	// WebhookConfigurationName is the ValidatingWebhookConfiguration that receives the CA bundle
+	WebhookConfigurationName string
+	// CertDir is the certificate directory of the webhook server
+	CertDir string
+	// Duration is the validity of the certificate (default DefaultCertificateDuration)
+	Duration time.Duration
+	// Clock is used to get the current time. If nil, time.Now() is used.
+	Clock clock.Clock
+}
+
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,verbs=get;update
+
+// Start keeps the certificate up to date until the context is cancelled. It implements
+// manager.Runnable.
+func (m *CertificateManager) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("webhook-certificates")
+	for {
+		wait := certificateCheckInterval
+		renewAt, err := m.Ensure(ctx)
+		if err != nil {
+			logger.Error(err, "Failed to ensure webhook serving certificate")
+			wait = certificateRetryInterval
+		} else if untilRenewal := renewAt.Sub(clock.Now(m.Clock)); untilRenewal < wait {
+			wait = max(untilRenewal, certificateRetryInterval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+// WaitForCertificate calls Ensure until it succeeds or the context is done. The webhook server needs
+// the certificate files when it starts, but the first attempts may fail while the API server is not
+// reachable yet or another replica is creating the Secret.
+func (m *CertificateManager) WaitForCertificate(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("webhook-certificates")
+	for {
+		_, err := m.Ensure(ctx)
+		if err == nil {
+			return nil
+		}
+		logger.Error(err, "Failed to ensure webhook serving certificate, retrying")
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("webhook serving certificate not ready: %w", err)
+		case <-time.After(certificateRetryInterval):
+		}
+	}
+}
+
+// NeedLeaderElection returns false, because every replica serves the webhook and needs the certificate
+func (m *CertificateManager) NeedLeaderElection() bool {
+	return false
+}
+
+// dnsNames returns the DNS names of the webhook Service
+func (m *CertificateManager) dnsNames() []string {
+	return []string{
+		fmt.Sprintf("%s.%s.svc", m.ServiceName, m.Namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", m.ServiceName, m.Namespace),
+	}
+}
+
+// Ensure creates or renews the certificate Secret if needed, writes the certificate to the
+// certificate directory and injects the CA bundle. It returns the time the certificate is due for renewal.
+func (m *CertificateManager) Ensure(ctx context.Context) (time.Time, error) {
+	var secret *corev1.Secret
+	var renewAt time.Time
+	// Replicas starting at the same time race for creating and renewing the Secret;
+	// the losers use the certificate of the winner
+	err := retry.OnError(retry.DefaultRetry, func(err error) bool {
+		return apierrors.IsAlreadyExists(err) || apierrors.IsConflict(err)
+	}, func() error {
+		var err error
+		secret, renewAt, err = m.ensureSecret(ctx)
+		return err
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if err := m.writeCertDir(secret); err != nil {
+		return time.Time{}, err
+	}
+	if err := m.injectCABundle(ctx, secret.Data["ca.crt"]); err != nil {
+		return time.Time{}, err
+	}
+	return renewAt, nil
+}
+
+// ensureSecret creates the certificate Secret or renews its certificate if needed
+func (m *CertificateManager) ensureSecret(ctx context.Context) (*corev1.Secret, time.Time, error) {
+	var secret corev1.Secret
+	err := m.Reader.Get(ctx, types.NamespacedName{Namespace: m.Namespace, Name: m.SecretName}, &secret)
+	if apierrors.IsNotFound(err) {
+		secret = corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: m.Namespace,
+				Name:      m.SecretName,
+				Labels:    map[string]string{CertificateSecretLabel: "true"},
+			},
+			Type: corev1.SecretTypeTLS,
+		}
+		if err := m.issue(&secret); err != nil {
+			return nil, time.Time{}, err
+		}
+		if err := m.Writer.Create(ctx, &secret); err != nil {
+			return nil, time.Time{}, err
+		}
+		log.FromContext(ctx).Info("Created webhook serving certificate", "secret", m.SecretName)
+	} else if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to get webhook certificate Secret: %w", err)
+	}
+
+	renewAt, valid := m.renewalTime(&secret)
+	renew := !valid || !clock.Now(m.Clock).Before(renewAt)
+	// Secrets created by earlier versions are labeled on the next check
+	if !renew && secret.Labels[CertificateSecretLabel] == "true" {
+		return &secret, renewAt, nil
+	}
+	if renew {
+		if err := m.issue(&secret); err != nil {
+			return nil, time.Time{}, err
+		}
+	}
+	metav1.SetMetaDataLabel(&secret.ObjectMeta, CertificateSecretLabel, "true")
+	if err := m.Writer.Update(ctx, &secret); err != nil {
+		return nil, time.Time{}, err
+	}
+	if renew {
+		log.FromContext(ctx).Info("Renewed webhook serving certificate", "secret", m.SecretName)
+	}
+	renewAt, _ = m.renewalTime(&secret)
+	return &secret, renewAt, nil
+}
+
+// renewalTime returns the time the certificate of the Secret is due for renewal, and false if the
+// Secret holds no valid certificate for the webhook Service
+func (m *CertificateManager) renewalTime(secret *corev1.Secret) (time.Time, bool) {
+	certPEM, keyPEM := secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]
+	if generator.ValidateKeyPair(certPEM, keyPEM) != nil || len(secret.Data["ca.crt"]) == 0 {
+		return time.Time{}, false
+	}
+	cert, err := generator.ParseCertificatePEM(certPEM)
+	if err != nil {
+		return time.Time{}, false
+	}
+	for _, name := range m.dnsNames() {
+		if !slices.Contains(cert.DNSNames, name) {
+			return time.Time{}, false
+		}
+	}
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	return cert.NotBefore.Add(time.Duration(float64(lifetime) * certificateRenewalFraction)), true
+}
+
+// issue generates a new certificate into the Secret. The CA bundle holds the new certificate and,
+// until it expires, the previous one.
+func (m *CertificateManager) issue(secret *corev1.Secret) error {
+	duration := m.Duration
+	if duration <= 0 {
+		duration = DefaultCertificateDuration
+	}
+	now := clock.Now(m.Clock)
+	cert, err := generator.GenerateSelfSignedCertificate(generator.CertificateOptions{
+		CommonName: m.dnsNames()[0],
+		Duration:   duration,
+		// Tolerate clock skew between the operator and the API server
+		NotBefore: now.Add(-time.Hour),
+		DNSNames:  m.dnsNames(),
+		IsCA:      true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to generate webhook serving certificate: %w", err)
+	}
+
+	bundle := slices.Clone(cert.CertPEM)
+	if previous, err := generator.ParseCertificatePEM(secret.Data[corev1.TLSCertKey]); err == nil && now.Before(previous.NotAfter) {
+		bundle = append(bundle, secret.Data[corev1.TLSCertKey]...)
+	}
+
+	secret.Data = map[string][]byte{
+		corev1.TLSCertKey:       cert.CertPEM,
+		corev1.TLSPrivateKeyKey: cert.KeyPEM,
+		"ca.crt":                bundle,
+	}
+	return nil
+}
+
+// writeCertDir writes the certificate and key of the Secret to the certificate directory. The
+// webhook server watches the files and reloads them on change.
+func (m *CertificateManager) writeCertDir(secret *corev1.Secret) error {
+	if err := os.MkdirAll(m.CertDir, 0o700); err != nil {
+		return fmt.Errorf("failed to create certificate directory: %w", err)
+	}
+	// The key is written first, so the server never pairs a new certificate with an old key for long
+	for _, key := range []string{corev1.TLSPrivateKeyKey, corev1.TLSCertKey} {
+		path := filepath.Join(m.CertDir, key)
+		if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, secret.Data[key]) {
+			continue
+		}
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, secret.Data[key], 0o600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", key, err)
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			return fmt.Errorf("failed to write %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// injectCABundle sets the CA bundle of all webhooks of the ValidatingWebhookConfiguration that
+// call the webhook Service
+func (m *CertificateManager) injectCABundle(ctx context.Context, bundle []byte) error {
+	var configuration admissionregistrationv1.ValidatingWebhookConfiguration
+	if err := m.Reader.Get(ctx, types.NamespacedName{Name: m.WebhookConfigurationName}, &configuration); err != nil {
+		return fmt.Errorf("failed to get ValidatingWebhookConfiguration %s: %w", m.WebhookConfigurationName, err)
+	}
+
+	changed := false
+	for i := range configuration.Webhooks {
+		clientConfig := &configuration.Webhooks[i].ClientConfig
+		if clientConfig.Service == nil || clientConfig.Service.Name != m.ServiceName ||
+			clientConfig.Service.Namespace != m.Namespace || bytes.Equal(clientConfig.CABundle, bundle) {
+			continue
+		}
+		clientConfig.CABundle = bundle
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	if err := m.Writer.Update(ctx, &configuration); err != nil {
+		return fmt.Errorf("failed to inject CA bundle into %s: %w", m.WebhookConfigurationName, err)
+	}
+	log.FromContext(ctx).Info("Injected webhook CA bundle", "configuration", m.WebhookConfigurationName)
+	return nil
+}
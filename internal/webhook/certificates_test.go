@@ -0,0 +1,194 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/clock"
+)
+
+func TestCertificateManagerEnsure(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	configuration := &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "operator"},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+			Name: "vsecret.iso.gtrfc.com",
+			ClientConfig: admissionregistrationv1.WebhookClientConfig{
+				Service: &admissionregistrationv1.ServiceReference{Name: "operator-webhook", Namespace: "system"},
+			},
+		}},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configuration).Build()
+	fakeClock := clock.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	manager := &CertificateManager{
+		Reader:                   fakeClient,
+		Writer:                   fakeClient,
+		Namespace:                "system",
+		SecretName:               "operator-webhook-cert",
+		ServiceName:              "operator-webhook",
+		WebhookConfigurationName: "operator",
+		CertDir:                  t.TempDir(),
+		Duration:                 90 * 24 * time.Hour,
+		Clock:                    fakeClock,
+	}
+
+	renewAt, err := manager.Ensure(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Two thirds of the lifetime, which starts an hour early to tolerate clock skew
+	if expected := fakeClock.Now().Add(-time.Hour).Add(60 * 24 * time.Hour); !renewAt.Equal(expected) {
+		t.Errorf("expected renewal at %v, got %v", expected, renewAt)
+	}
+
+	secret := getCertificateSecret(t, manager)
+	if secret.Labels[CertificateSecretLabel] != "true" {
+		t.Errorf("expected the certificate Secret to be labeled, got labels %v", secret.Labels)
+	}
+	files, err := os.ReadFile(filepath.Join(manager.CertDir, corev1.TLSCertKey))
+	if err != nil || !bytes.Equal(files, secret.Data[corev1.TLSCertKey]) {
+		t.Errorf("expected tls.crt to be written to the certificate directory: %v", err)
+	}
+	if bundle := getCABundle(t, manager); !bytes.Equal(bundle, secret.Data[corev1.TLSCertKey]) {
+		t.Errorf("expected CA bundle to be the serving certificate, got %q", bundle)
+	}
+
+	// Not yet due: the certificate is kept
+	if _, err := manager.Ensure(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if unchanged := getCertificateSecret(t, manager); !bytes.Equal(unchanged.Data[corev1.TLSCertKey], secret.Data[corev1.TLSCertKey]) {
+		t.Error("expected certificate to be kept before renewal")
+	}
+
+	// Due: the certificate is renewed and the previous one stays in the CA bundle
+	fakeClock.Set(renewAt)
+	if _, err := manager.Ensure(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	renewed := getCertificateSecret(t, manager)
+	if bytes.Equal(renewed.Data[corev1.TLSCertKey], secret.Data[corev1.TLSCertKey]) {
+		t.Fatal("expected certificate to be renewed")
+	}
+	expectedBundle := append(bytes.Clone(renewed.Data[corev1.TLSCertKey]), secret.Data[corev1.TLSCertKey]...)
+	if bundle := getCABundle(t, manager); !bytes.Equal(bundle, expectedBundle) {
+		t.Error("expected CA bundle to hold the renewed and the previous certificate")
+	}
+}
+
+func TestCertificateManagerLabelsExistingSecret(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	configuration := &admissionregistrationv1.ValidatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "operator"}}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configuration).Build()
+	manager := &CertificateManager{
+		Reader:                   fakeClient,
+		Writer:                   fakeClient,
+		Namespace:                "system",
+		SecretName:               "operator-webhook-cert",
+		ServiceName:              "operator-webhook",
+		WebhookConfigurationName: "operator",
+		CertDir:                  t.TempDir(),
+	}
+	if _, err := manager.Ensure(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A Secret created by an earlier version has no label
+	secret := getCertificateSecret(t, manager)
+	secret.Labels = nil
+	if err := fakeClient.Update(context.Background(), secret); err != nil {
+		t.Fatalf("failed to update secret: %v", err)
+	}
+	if _, err := manager.Ensure(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	labeled := getCertificateSecret(t, manager)
+	if labeled.Labels[CertificateSecretLabel] != "true" {
+		t.Errorf("expected the certificate Secret to be labeled, got labels %v", labeled.Labels)
+	}
+	if !bytes.Equal(labeled.Data[corev1.TLSCertKey], secret.Data[corev1.TLSCertKey]) {
+		t.Error("expected the certificate to be kept")
+	}
+}
+
+func TestCertificateManagerWaitForCertificate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	// Without the webhook configuration, the CA bundle cannot be injected
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	manager := &CertificateManager{
+		Reader:                   fakeClient,
+		Writer:                   fakeClient,
+		Namespace:                "system",
+		SecretName:               "operator-webhook-cert",
+		ServiceName:              "operator-webhook",
+		WebhookConfigurationName: "operator",
+		CertDir:                  t.TempDir(),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := manager.WaitForCertificate(ctx); err == nil {
+		t.Error("expected an error once the context is done")
+	}
+
+	configuration := &admissionregistrationv1.ValidatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "operator"}}
+	if err := fakeClient.Create(context.Background(), configuration); err != nil {
+		t.Fatalf("failed to create webhook configuration: %v", err)
+	}
+	if err := manager.WaitForCertificate(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func getCertificateSecret(t *testing.T, manager *CertificateManager) *corev1.Secret {
+	t.Helper()
+	var secret corev1.Secret
+	key := types.NamespacedName{Namespace: manager.Namespace, Name: manager.SecretName}
+	if err := manager.Reader.Get(context.Background(), key, &secret); err != nil {
+		t.Fatalf("failed to get certificate Secret: %v", err)
+	}
+	return &secret
+}
+
+func getCABundle(t *testing.T, manager *CertificateManager) []byte {
+	t.Helper()
+	var configuration admissionregistrationv1.ValidatingWebhookConfiguration
+	key := types.NamespacedName{Name: manager.WebhookConfigurationName}
+	if err := manager.Reader.Get(context.Background(), key, &configuration); err != nil {
+		t.Fatalf("failed to get webhook configuration: %v", err)
+	}
+	return configuration.Webhooks[0].ClientConfig.CABundle
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhook contains the admission webhooks of the operator
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	isoannotations "github.com/guided-traffic/internal-secrets-operator/pkg/annotations"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/policy"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+// SecretValidator rejects Secrets whose replication annotations or policy violations would be
+// refused at reconcile time, so that errors surface when the Secret is applied
+type SecretValidator struct {
+	Config *config.Config
+	// Reader reads Namespaces for policies that select namespaces by label.
+	// If nil, such policies are not enforced by the webhook.
+	Reader client.Reader
+}
+
+var _ admission.CustomValidator = &SecretValidator{}
+
+// +kubebuilder:webhook:path=/validate--v1-secret,mutating=false,failurePolicy=ignore,sideEffects=None,groups="",resources=secrets,verbs=create;update,versions=v1,name=vsecret.iso.gtrfc.com,admissionReviewVersions=v1
+
+// SetupWithManager registers the validating webhook for Secrets with the Manager
+func (v *SecretValidator) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&corev1.Secret{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate validates a newly created Secret
+func (v *SecretValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return nil, fmt.Errorf("expected a Secret, got %T", obj)
+	}
+	return nil, v.validate(ctx, nil, secret)
+}
+
+// ValidateUpdate validates an updated Secret. Only what changed compared to the old Secret is
+// validated: a Secret that became invalid by a later denylist entry or policy must still accept
+// updates, in particular the operator's bookkeeping and finalizer removal. Updates of Secrets
+// that are being deleted are always allowed.
+func (v *SecretValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	secret, ok := newObj.(*corev1.Secret)
+	if !ok {
+		return nil, fmt.Errorf("expected a Secret, got %T", newObj)
+	}
+	if secret.DeletionTimestamp != nil {
+		return nil, nil
+	}
+	old, ok := oldObj.(*corev1.Secret)
+	if !ok {
+		return nil, fmt.Errorf("expected a Secret, got %T", oldObj)
+	}
+	return nil, v.validate(ctx, old, secret)
+}
+
+// ValidateDelete allows all deletions
+func (v *SecretValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks that the autogenerate fields of a Secret are valid data keys, that its
+// replication annotations do not reference namespaces on the replication denylist and that
+// the Secret does not violate a policy. On updates, old is the previous Secret and only the
+// annotations that changed are validated; problems the old Secret already had are not reported.
+func (v *SecretValidator) validate(ctx context.Context, old, secret *corev1.Secret) error {
+	if v.Config.IsSecretTypeIgnored(string(secret.Type)) {
+		return nil
+	}
+
+	if v.Config.LegacyAnnotationPrefix != "" {
+		secret = secret.DeepCopy()
+		isoannotations.TranslateLegacy(secret.Annotations, v.Config.LegacyAnnotationPrefix)
+		if old != nil {
+			old = old.DeepCopy()
+			isoannotations.TranslateLegacy(old.Annotations, v.Config.LegacyAnnotationPrefix)
+		}
+	}
+	changed := func(annotation string) bool {
+		return old == nil || old.Annotations[annotation] != secret.Annotations[annotation]
+	}
+
+	if changed(isoannotations.Autogenerate) {
+		if err := isoannotations.ValidateFields(isoannotations.Fields(secret.Annotations)); err != nil {
+			return err
+		}
+	}
+
+	if changed(replicator.AnnotationReplicatableUntil) {
+		if _, err := replicator.ConsentExpiry(secret); err != nil {
+			return err
+		}
+	}
+
+	var previous []string
+	if old != nil {
+		previous = replicator.ReferencedNamespaces(old)
+	}
+	var denied []string
+	for _, namespace := range replicator.ReferencedNamespaces(secret) {
+		if slices.Contains(previous, namespace) {
+			continue
+		}
+		if !replicator.DecideNamespace(namespace, v.Config.Replication.DeniedNamespaces).Allowed {
+			denied = append(denied, namespace)
+		}
+	}
+	if len(denied) > 0 {
+		return fmt.Errorf("replication annotations reference namespaces on the replication denylist: %s",
+			strings.Join(denied, ", "))
+	}
+
+	return v.validatePolicies(ctx, old, secret)
+}
+
+// validatePolicies rejects Secrets that use features denied by a policy. On updates, violations
+// the old Secret already had are allowed.
+func (v *SecretValidator) validatePolicies(ctx context.Context, old, secret *corev1.Secret) error {
+	if len(v.Config.Policies) == 0 {
+		return nil
+	}
+
+	var namespaceLabels map[string]string
+	if v.Reader != nil && policy.NeedsNamespaceLabels(v.Config.Policies) {
+		var namespace corev1.Namespace
+		if err := v.Reader.Get(ctx, types.NamespacedName{Name: secret.Namespace}, &namespace); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to get namespace %s: %w", secret.Namespace, err)
+		}
+		namespaceLabels = namespace.Labels
+		if namespaceLabels == nil {
+			namespaceLabels = map[string]string{}
+		}
+	}
+
+	violations := policy.Evaluate(v.Config.Policies, secret, namespaceLabels, config.PolicyFeatures)
+	if len(violations) == 0 {
+		return nil
+	}
+	var existing []policy.Violation
+	if old != nil {
+		existing = policy.Evaluate(v.Config.Policies, old, namespaceLabels, config.PolicyFeatures)
+	}
+	var messages []string
+	for _, violation := range violations {
+		if !slices.Contains(existing, violation) {
+			messages = append(messages, violation.String())
+		}
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return fmt.Errorf("policy violations: %s", strings.Join(messages, "; "))
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/annotations"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+func newSecret(secretType corev1.SecretType, annotations map[string]string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: annotations},
+		Type:       secretType,
+	}
+}
+
+func TestSecretValidatorDeniedNamespaces(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Replication.DeniedNamespaces = []string{"kube-*"}
+	validator := &SecretValidator{Config: cfg}
+
+	tests := []struct {
+		name        string
+		secret      *corev1.Secret
+		expectError string
+	}{
+		{
+			name:   "no replication annotations",
+			secret: newSecret(corev1.SecretTypeOpaque, nil),
+		},
+		{
+			name: "allowed push targets",
+			secret: newSecret(corev1.SecretTypeOpaque, map[string]string{
+				replicator.AnnotationReplicateTo: "staging,dev",
+			}),
+		},
+		{
+			name: "wildcard push",
+			secret: newSecret(corev1.SecretTypeOpaque, map[string]string{
+				replicator.AnnotationReplicateTo: "*",
+			}),
+		},
+		{
+			name: "denied push target",
+			secret: newSecret(corev1.SecretTypeOpaque, map[string]string{
+				replicator.AnnotationReplicateTo: "staging,kube-system",
+			}),
+			expectError: "kube-system",
+		},
+		{
+			name: "denied pull source",
+			secret: newSecret(corev1.SecretTypeOpaque, map[string]string{
+				replicator.AnnotationReplicateFrom: "production/db,kube-public/ca",
+			}),
+			expectError: "kube-public",
+		},
+		{
+			name: "denied per-key source",
+			secret: newSecret(corev1.SecretTypeOpaque, map[string]string{
+				replicator.AnnotationReplicateFromKeyPrefix + "token": "kube-system/token#value",
+			}),
+			expectError: "kube-system",
+		},
+		{
+			name: "ignored Secret type",
+			secret: newSecret("helm.sh/release.v1", map[string]string{
+				replicator.AnnotationReplicateTo: "kube-system",
+			}),
+		},
+		{
+			name: "invalid replicatable-until",
+			secret: newSecret(corev1.SecretTypeOpaque, map[string]string{
+				replicator.AnnotationReplicatableUntil: "2025-12-31",
+			}),
+			expectError: "RFC3339",
+		},
+		{
+			name: "invalid autogenerate field",
+			secret: newSecret(corev1.SecretTypeOpaque, map[string]string{
+				annotations.Autogenerate: "password,api key",
+			}),
+			expectError: `"api key"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, createErr := validator.ValidateCreate(context.Background(), tt.secret)
+			_, updateErr := validator.ValidateUpdate(context.Background(), newSecret(corev1.SecretTypeOpaque, nil), tt.secret)
+
+			for _, err := range []error{createErr, updateErr} {
+				if tt.expectError == "" {
+					if err != nil {
+						t.Errorf("unexpected error: %v", err)
+					}
+					continue
+				}
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("expected error mentioning %q, got %v", tt.expectError, err)
+				}
+			}
+		})
+	}
+}
+
+func TestSecretValidatorAllowsDelete(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Replication.DeniedNamespaces = []string{"kube-*"}
+	validator := &SecretValidator{Config: cfg}
+
+	secret := newSecret(corev1.SecretTypeOpaque, map[string]string{replicator.AnnotationReplicateTo: "kube-system"})
+	if _, err := validator.ValidateDelete(context.Background(), secret); err != nil {
+		t.Errorf("expected deletion to be allowed, got %v", err)
+	}
+}
+
+func TestSecretValidatorUpdateValidatesChanges(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Replication.DeniedNamespaces = []string{"kube-*"}
+	cfg.Policies = []config.PolicyRule{
+		{Name: "fast-rotation-requires-label", MinRotationInterval: config.Duration(24 * time.Hour)},
+	}
+	validator := &SecretValidator{Config: cfg}
+
+	// A Secret that became invalid after it was admitted, e.g. by a new denylist entry or policy
+	old := newSecret(corev1.SecretTypeOpaque, map[string]string{
+		annotations.Autogenerate:               "password,api key",
+		annotations.Rotate:                     "1h",
+		replicator.AnnotationReplicateTo:       "kube-system",
+		replicator.AnnotationReplicatableUntil: "2025-12-31",
+	})
+
+	bookkeeping := old.DeepCopy()
+	bookkeeping.Annotations[annotations.GeneratedAt] = "2025-01-01T00:00:00Z"
+	if _, err := validator.ValidateUpdate(context.Background(), old, bookkeeping); err != nil {
+		t.Errorf("expected unrelated changes to be allowed, got %v", err)
+	}
+
+	deleting := old.DeepCopy()
+	deleting.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	deleting.Annotations[replicator.AnnotationReplicateTo] = "kube-system,kube-public"
+	if _, err := validator.ValidateUpdate(context.Background(), old, deleting); err != nil {
+		t.Errorf("expected updates of a deleted Secret to be allowed, got %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		annotation  string
+		value       string
+		expectError string
+	}{
+		{name: "new denied namespace", annotation: replicator.AnnotationReplicateTo, value: "kube-system,kube-public", expectError: "kube-public"},
+		{name: "changed autogenerate", annotation: annotations.Autogenerate, value: "api key,token", expectError: `"api key"`},
+		{name: "changed replicatable-until", annotation: replicator.AnnotationReplicatableUntil, value: "2026-12-31", expectError: "RFC3339"},
+		{name: "new policy violation", annotation: annotations.RotatePrefix + "password", value: "30m", expectError: "fast-rotation-requires-label"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := old.DeepCopy()
+			updated.Annotations[tt.annotation] = tt.value
+			_, err := validator.ValidateUpdate(context.Background(), old, updated)
+			if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+				t.Errorf("expected error mentioning %q, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+func TestSecretValidatorLegacyAnnotationPrefix(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Replication.DeniedNamespaces = []string{"kube-*"}
+	cfg.LegacyAnnotationPrefix = "legacy.example.com/"
+	validator := &SecretValidator{Config: cfg}
+
+	secret := newSecret(corev1.SecretTypeOpaque, map[string]string{
+		"legacy.example.com/replicate-to": "kube-system",
+	})
+	_, err := validator.ValidateCreate(context.Background(), secret)
+	if err == nil || !strings.Contains(err.Error(), "kube-system") {
+		t.Errorf("expected legacy annotation to be validated, got %v", err)
+	}
+	if _, ok := secret.Annotations[replicator.AnnotationReplicateTo]; ok {
+		t.Error("expected the admitted Secret to be left unchanged")
+	}
+}
+
+func TestSecretValidatorRejectsNonSecrets(t *testing.T) {
+	validator := &SecretValidator{Config: config.NewDefaultConfig()}
+	if _, err := validator.ValidateCreate(context.Background(), &corev1.ConfigMap{}); err == nil {
+		t.Error("expected error for non-Secret object")
+	}
+}
+
+func TestSecretValidatorPolicies(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Policies = []config.PolicyRule{
+		{
+			Name:              "no-templates-for-tenants",
+			NamespaceSelector: "tenant=true",
+			DeniedFeatures:    []string{config.PolicyFeatureReplicateAs},
+		},
+		{
+			Name:                "fast-rotation-requires-label",
+			MinRotationInterval: config.Duration(24 * time.Hour),
+		},
+	}
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	tenant := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: map[string]string{"tenant": "true"}}}
+	validator := &SecretValidator{
+		Config: cfg,
+		Reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(tenant).Build(),
+	}
+
+	secret := newSecret(corev1.SecretTypeOpaque, map[string]string{
+		replicator.AnnotationReplicateTo: "staging",
+		replicator.AnnotationReplicateAs: "{{ .Namespace }}-app",
+	})
+	_, err := validator.ValidateCreate(context.Background(), secret)
+	if err == nil || !strings.Contains(err.Error(), "no-templates-for-tenants") {
+		t.Errorf("expected error mentioning the violated policy, got %v", err)
+	}
+
+	secret = newSecret(corev1.SecretTypeOpaque, map[string]string{annotations.Rotate: "1h"})
+	_, err = validator.ValidateCreate(context.Background(), secret)
+	if err == nil || !strings.Contains(err.Error(), "fast-rotation-requires-label") {
+		t.Errorf("expected error mentioning the violated policy, got %v", err)
+	}
+
+	secret = newSecret(corev1.SecretTypeOpaque, map[string]string{annotations.Rotate: "30d"})
+	if _, err := validator.ValidateCreate(context.Background(), secret); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,591 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package annotations defines the generation annotations of the operator and parses them
+// the same way the operator does. It is meant for admission tooling and tests that need
+// to interpret Secrets without re-declaring the annotation keys.
+package annotations
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+const (
+	// Prefix is the prefix for all secret operator annotations
+	Prefix = "iso.gtrfc.com/"
+
+	// Autogenerate specifies which fields to auto-generate
+	Autogenerate = Prefix + "autogenerate"
+
+	// AutogenerateID specifies which keys of a ConfigMap to fill with random, non-secret
+	// identifiers (requires features.configMapIDs)
+	AutogenerateID = Prefix + "autogenerate-id"
+
+	// Adopt lists existing fields that the operator takes over without changing their values.
+	// The operator moves them to autogenerate, so rotation applies from then on.
+	Adopt = Prefix + "adopt"
+
+	// Type specifies the default type of generated value (string, bytes)
+	Type = Prefix + "type"
+
+	// Length specifies the default length of the generated value
+	Length = Prefix + "length"
+
+	// TypePrefix is the prefix for field-specific type annotations (type.<field>)
+	TypePrefix = Prefix + "type."
+
+	// LengthPrefix is the prefix for field-specific length annotations (length.<field>)
+	LengthPrefix = Prefix + "length."
+
+	// Encoding specifies the default encoding of bytes values (raw, base64, base64url, base32, hex)
+	Encoding = Prefix + "encoding"
+
+	// EncodingPrefix is the prefix for field-specific encoding annotations (encoding.<field>)
+	EncodingPrefix = Prefix + "encoding."
+
+	// HashPrefix is the prefix for field-specific hash annotations (hash.<field>) that write a
+	// hash of the generated value to the companion key <field>-<algorithm>
+	HashPrefix = Prefix + "hash."
+
+	// GeneratedAt indicates when the value was generated
+	GeneratedAt = Prefix + "generated-at"
+
+	// GeneratedKeys lists the data keys the operator generated (set by the operator), so that
+	// values of fields removed from autogenerate can be told apart from user-provided values
+	GeneratedKeys = Prefix + "generated-keys"
+
+	// DataChecksum is the SHA-256 checksum of the data of the Secret (set by the operator whenever
+	// it writes data), e.g. to restart pods on changes via a pod template annotation
+	DataChecksum = Prefix + "data-checksum"
+
+	// Rotate specifies the default rotation interval for all fields
+	Rotate = Prefix + "rotate"
+
+	// RotatePrefix is the prefix for field-specific rotation annotations (rotate.<field>)
+	RotatePrefix = Prefix + "rotate."
+
+	// RotateExclude lists fields that are never rotated, e.g. identity-like fields of a Secret
+	// with a secret-wide rotate interval (comma-separated)
+	RotateExclude = Prefix + "rotate-exclude"
+
+	// RotateWith couples the rotation of a Secret to another Secret ("namespace/name" or "name" in
+	// the same namespace): whenever the other Secret is rotated, this Secret is rotated as well
+	RotateWith = Prefix + "rotate-with"
+
+	// RotationRequestedAt requests an immediate rotation of all fields.
+	// Rotation happens if the timestamp is not older than generated-at and was not handled yet.
+	RotationRequestedAt = Prefix + "rotation-requested-at"
+
+	// RotationRequestHandledAt records the rotation-requested-at value the operator acted on
+	// (set by the operator), so that a request is handled once
+	RotationRequestHandledAt = Prefix + "rotation-request-handled-at"
+
+	// RotationConfigError holds the error of invalid rotate annotations (set by the operator).
+	// It is removed once the annotations are fixed.
+	RotationConfigError = Prefix + "rotation-config-error"
+
+	// GenerationError holds the errors of fields that could not be generated due to their
+	// configuration (set by the operator). It is removed once all fields are generated.
+	GenerationError = Prefix + "generation-error"
+
+	// OriginPrefix is the prefix for field-specific annotations (origin.<field>) that record where
+	// the value of a field came from (see the Origin constants, set by the operator)
+	OriginPrefix = Prefix + "origin."
+
+	// RevisionPrefix is the prefix for field-specific annotations (revision.<field>) that count how
+	// often the value of a field was generated, rotated or renewed (set by the operator)
+	RevisionPrefix = Prefix + "revision."
+
+	// Ready is "true" once all fields listed in autogenerate and all rendered keys exist in the
+	// Secret, and "false" while any of them is missing (set by the operator)
+	Ready = Prefix + "ready"
+
+	// BackupOf names the Secret whose previous values a rotation backup Secret holds (set by the operator)
+	BackupOf = Prefix + "backup-of"
+
+	// BackupExpiresAt is the time after which a rotation backup Secret is deleted (set by the operator)
+	BackupExpiresAt = Prefix + "backup-expires-at"
+
+	// TLSDNSNames lists the DNS subject alternative names of generated certificates
+	TLSDNSNames = Prefix + "tls.dns-names"
+
+	// TLSIPAddresses lists the IP address subject alternative names of generated certificates
+	TLSIPAddresses = Prefix + "tls.ip-addresses"
+
+	// TLSKeyUsages lists the key usages of generated certificates (e.g. "server-auth,client-auth")
+	TLSKeyUsages = Prefix + "tls.key-usages"
+
+	// TLSDuration overrides the validity period of generated certificates
+	TLSDuration = Prefix + "tls.duration"
+
+	// TLSRenewBefore renews generated certificates this long before they expire, instead of
+	// after the configured fraction of their lifetime
+	TLSRenewBefore = Prefix + "tls.renew-before"
+
+	// TLSIssuerSecret references a CA Secret ("namespace/name" or "name" in the same namespace)
+	// whose tls.crt and tls.key sign the generated certificates instead of self-signing them
+	TLSIssuerSecret = Prefix + "tls.issuer-secret"
+
+	// TLSIssuerForNamespaces lists the namespaces (glob patterns) whose Secrets may use a CA Secret
+	// as issuer. Secrets in the namespace of the CA Secret may always use it.
+	TLSIssuerForNamespaces = Prefix + "tls.issuer-for-namespaces"
+
+	// TLSKeystorePasswordField names the data field holding the password of PKCS#12 keystores
+	// and truststores rendered alongside generated certificates
+	TLSKeystorePasswordField = Prefix + "tls.keystore-password-field"
+
+	// RenderEnv renders the data of the Secret as KEY=value lines into the .env key. "true" renders
+	// all keys, any other value is a comma-separated list of the keys to render.
+	RenderEnv = Prefix + "render-env"
+
+	// RenderJSON renders the data of the Secret as JSON object into the config.json key, with the
+	// same values as RenderEnv
+	RenderJSON = Prefix + "render-json"
+
+	// RenderYAML renders the data of the Secret as YAML mapping into the config.yaml key, with the
+	// same values as RenderEnv
+	RenderYAML = Prefix + "render-yaml"
+
+	// RenderMapping renames data keys in rendered keys, e.g. "db-password=DB_PASSWORD"
+	RenderMapping = Prefix + "render-mapping"
+
+	// SigningKeyPrefix is the prefix for annotations naming the data field whose value signs a
+	// signed-token field (signing-key.<field>)
+	SigningKeyPrefix = Prefix + "signing-key."
+
+	// TokenDurationPrefix is the prefix for annotations overriding the validity period of a
+	// signed-token field (token-duration.<field>)
+	TokenDurationPrefix = Prefix + "token-duration."
+
+	// OAuthClientIDFormat overrides the format of the client IDs of oauth-client fields
+	// (uuid, hex or alphanumeric)
+	OAuthClientIDFormat = Prefix + "oauth.client-id-format"
+
+	// TTL specifies a time-to-live after which the entire Secret is deleted
+	TTL = Prefix + "ttl"
+
+	// TTLWarnedFor records the expiry the TTLExpiring warning was emitted for (set by the
+	// operator), so that the warning is emitted once per expiry
+	TTLWarnedFor = Prefix + "ttl-warned-for"
+
+	// StringUppercase specifies whether to include uppercase letters
+	StringUppercase = Prefix + "string.uppercase"
+
+	// StringLowercase specifies whether to include lowercase letters
+	StringLowercase = Prefix + "string.lowercase"
+
+	// StringNumbers specifies whether to include numbers
+	StringNumbers = Prefix + "string.numbers"
+
+	// StringSpecialChars specifies whether to include special characters
+	StringSpecialChars = Prefix + "string.specialChars"
+
+	// StringAllowedSpecialChars specifies which special characters to use
+	StringAllowedSpecialChars = Prefix + "string.allowedSpecialChars"
+
+	// StringData specifies whether generated string values are written via stringData
+	// (overrides defaults.stringData)
+	StringData = Prefix + "string-data"
+
+	// UniqueValues specifies whether generated values must differ from the values of all other
+	// fields of the Secret (overrides defaults.uniqueValues)
+	UniqueValues = Prefix + "unique-values"
+)
+
+// Origins of field values, as recorded in the origin.<field> annotations
+const (
+	// OriginGenerated values were generated or rotated by the operator
+	OriginGenerated = "generated"
+
+	// OriginAdopted values existed before and were taken over with the adopt annotation
+	OriginAdopted = "adopted"
+
+	// OriginReplicated values were copied from a source Secret by the replicator
+	OriginReplicated = "replicated"
+
+	// OriginExternal values of autogenerate fields were set by someone else and are kept
+	OriginExternal = "external"
+)
+
+// FieldAnnotation returns the field-specific annotation prefix+key (e.g. origin.<key>). Data keys
+// may be longer than annotation names allow or end in a character that names cannot end in, so ok
+// is false if prefix+key is not a valid annotation name and must not be written.
+func FieldAnnotation(prefix, key string) (name string, ok bool) {
+	name = prefix + key
+	return name, len(validation.IsQualifiedName(name)) == 0
+}
+
+// SetOrigin records the origin of the value of a data key. Keys that cannot form a valid
+// annotation name are skipped. Returns true if annotations was modified.
+func SetOrigin(annotations map[string]string, key, origin string) bool {
+	name, ok := FieldAnnotation(OriginPrefix, key)
+	if !ok || annotations[name] == origin {
+		return false
+	}
+	annotations[name] = origin
+	return true
+}
+
+// TranslateLegacy copies the annotations carrying legacyPrefix to the same key with the
+// primary Prefix, so that Secrets annotated with a previous prefix are read like Secrets
+// annotated with the primary one. Primary annotations take precedence over legacy ones.
+// Returns the added annotations.
+func TranslateLegacy(annotations map[string]string, legacyPrefix string) []string {
+	if legacyPrefix == "" || legacyPrefix == Prefix {
+		return nil
+	}
+	var translated []string
+	for key, value := range annotations {
+		name, ok := strings.CutPrefix(key, legacyPrefix)
+		if !ok || name == "" {
+			continue
+		}
+		if _, exists := annotations[Prefix+name]; exists {
+			continue
+		}
+		annotations[Prefix+name] = value
+		translated = append(translated, Prefix+name)
+	}
+	return translated
+}
+
+// ParseFields parses a comma-separated list of field names
+func ParseFields(value string) []string {
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// Fields returns the fields listed in the autogenerate annotation.
+// Returns nil if the annotation is not present or empty.
+func Fields(annotations map[string]string) []string {
+	autogenerate, ok := annotations[Autogenerate]
+	if !ok || autogenerate == "" {
+		return nil
+	}
+	return ParseFields(autogenerate)
+}
+
+// ValidateFields checks that the fields are valid Secret data keys (alphanumeric, '-', '_' or '.'),
+// so that invalid fields are reported before the API server rejects the generated data
+func ValidateFields(fields []string) error {
+	for _, field := range fields {
+		if errs := validation.IsConfigMapKey(field); len(errs) > 0 {
+			return fmt.Errorf("invalid field %q in %s: %s", field, Autogenerate, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+// ParseRenderMapping parses the render-mapping annotation, a comma-separated list of
+// key=name pairs. Entries without a key or name are ignored.
+func ParseRenderMapping(annotations map[string]string) map[string]string {
+	mapping := make(map[string]string)
+	for _, entry := range ParseFields(annotations[RenderMapping]) {
+		key, name, _ := strings.Cut(entry, "=")
+		key, name = strings.TrimSpace(key), strings.TrimSpace(name)
+		if key != "" && name != "" {
+			mapping[key] = name
+		}
+	}
+	return mapping
+}
+
+// ParseBool parses a boolean annotation value.
+// Returns the parsed value and true if the annotation exists and is valid.
+// Valid values are "true", "false", "1", "0" (case-insensitive).
+func ParseBool(annotations map[string]string, key string) (bool, bool) {
+	value, ok := annotations[key]
+	if !ok {
+		return false, false
+	}
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "1":
+		return true, true
+	case "false", "0":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// ParseTimestamp parses a timestamp annotation in RFC 3339 or Unix epoch seconds format.
+// Returns nil if the annotation is not present or invalid.
+func ParseTimestamp(annotations map[string]string, key string) *time.Time {
+	value, ok := annotations[key]
+	if !ok || value == "" {
+		return nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds >= 0 {
+		t := time.Unix(seconds, 0).UTC()
+		return &t
+	}
+	return nil
+}
+
+// FormatTimestamp formats a timestamp annotation value in the given format
+// (config.TimestampFormatRFC3339 or config.TimestampFormatUnix)
+func FormatTimestamp(t time.Time, format string) string {
+	if format == config.TimestampFormatUnix {
+		return strconv.FormatInt(t.Unix(), 10)
+	}
+	return t.Format(time.RFC3339)
+}
+
+// FieldType returns the type for a specific field.
+// Priority: type.<field> annotation > type annotation > defaultType
+func FieldType(annotations map[string]string, field, defaultType string) string {
+	if value, ok := annotations[TypePrefix+field]; ok && value != "" {
+		return value
+	}
+	if value, ok := annotations[Type]; ok && value != "" {
+		return value
+	}
+	return defaultType
+}
+
+// FieldEncoding returns the encoding for a specific field, or "" (raw) if none is set.
+// Priority: encoding.<field> annotation > encoding annotation
+func FieldEncoding(annotations map[string]string, field string) string {
+	if value, ok := annotations[EncodingPrefix+field]; ok && value != "" {
+		return value
+	}
+	return annotations[Encoding]
+}
+
+// DefaultLength returns the length annotation, or defaultLength if it is missing or not a positive integer
+func DefaultLength(annotations map[string]string, defaultLength int) int {
+	if length, ok := parseLength(annotations, Length); ok {
+		return length
+	}
+	return defaultLength
+}
+
+// FieldLength returns the length for a specific field.
+// Priority: length.<field> annotation > length annotation > defaultLength
+func FieldLength(annotations map[string]string, field string, defaultLength int) int {
+	if length, ok := parseLength(annotations, LengthPrefix+field); ok {
+		return length
+	}
+	return DefaultLength(annotations, defaultLength)
+}
+
+// parseLength parses a positive integer annotation
+func parseLength(annotations map[string]string, key string) (int, bool) {
+	if value, ok := annotations[key]; ok && value != "" {
+		if length, err := strconv.Atoi(value); err == nil && length > 0 {
+			return length, true
+		}
+	}
+	return 0, false
+}
+
+// RotationExcluded returns true if the field is listed in the rotate-exclude annotation
+func RotationExcluded(annotations map[string]string, field string) bool {
+	return slices.Contains(ParseFields(annotations[RotateExclude]), field)
+}
+
+// FieldRotationInterval returns the rotation interval for a specific field.
+// Priority: rotate-exclude annotation > rotate.<field> annotation > rotate annotation > defaultInterval.
+// Invalid durations are ignored; an explicit "0" disables rotation regardless of the default.
+func FieldRotationInterval(annotations map[string]string, field string, defaultInterval time.Duration) time.Duration {
+	if RotationExcluded(annotations, field) {
+		return 0
+	}
+	for _, key := range []string{RotatePrefix + field, Rotate} {
+		if value, ok := annotations[key]; ok && value != "" {
+			if duration, err := config.ParseDuration(value); err == nil {
+				return duration
+			}
+		}
+	}
+	return defaultInterval
+}
+
+// ValidateRotation checks that the rotate annotation and the rotate.<field> annotations of the
+// given fields are valid durations. FieldRotationInterval ignores invalid values.
+func ValidateRotation(annotations map[string]string, fields []string) error {
+	keys := []string{Rotate}
+	for _, field := range fields {
+		keys = append(keys, RotatePrefix+field)
+	}
+	for _, key := range keys {
+		value, ok := annotations[key]
+		if !ok || value == "" {
+			continue
+		}
+		duration, err := config.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %w", key, value, err)
+		}
+		if duration < 0 {
+			return fmt.Errorf("invalid %s %q: must not be negative", key, value)
+		}
+	}
+	return nil
+}
+
+// Certificate holds the settings of generated certificates. Unset values are zero.
+type Certificate struct {
+	DNSNames    []string
+	IPAddresses []net.IP
+	KeyUsages   []string
+	Duration    time.Duration
+	RenewBefore time.Duration
+}
+
+// ParseCertificate parses and validates the tls.* certificate annotations. Key usage names are
+// validated when the certificate is generated.
+func ParseCertificate(annotations map[string]string) (Certificate, error) {
+	var cert Certificate
+	for _, name := range ParseFields(annotations[TLSDNSNames]) {
+		// Wildcard names are allowed for the leftmost label only
+		if errs := validation.IsDNS1123Subdomain(strings.TrimPrefix(name, "*.")); len(errs) > 0 {
+			return cert, fmt.Errorf("invalid DNS name %q in %s: %s", name, TLSDNSNames, strings.Join(errs, ", "))
+		}
+		cert.DNSNames = append(cert.DNSNames, name)
+	}
+	for _, value := range ParseFields(annotations[TLSIPAddresses]) {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return cert, fmt.Errorf("invalid IP address %q in %s", value, TLSIPAddresses)
+		}
+		cert.IPAddresses = append(cert.IPAddresses, ip)
+	}
+	cert.KeyUsages = ParseFields(annotations[TLSKeyUsages])
+
+	for _, d := range []struct {
+		key    string
+		target *time.Duration
+	}{{TLSDuration, &cert.Duration}, {TLSRenewBefore, &cert.RenewBefore}} {
+		key, value := d.key, annotations[d.key]
+		if value == "" {
+			continue
+		}
+		duration, err := config.ParseDuration(value)
+		if err != nil {
+			return cert, fmt.Errorf("invalid %s %q: %w", key, value, err)
+		}
+		if duration <= 0 {
+			return cert, fmt.Errorf("invalid %s %q: must be positive", key, value)
+		}
+		*d.target = duration
+	}
+	if cert.Duration > 0 && cert.RenewBefore >= cert.Duration {
+		return cert, fmt.Errorf("%s %s must be shorter than %s %s", TLSRenewBefore, cert.RenewBefore, TLSDuration, cert.Duration)
+	}
+	return cert, nil
+}
+
+// CharsetOptions holds the charset configuration of string fields
+type CharsetOptions struct {
+	Uppercase           bool
+	Lowercase           bool
+	Numbers             bool
+	SpecialChars        bool
+	AllowedSpecialChars string
+}
+
+// ResolveCharsetOptions resolves charset options from annotations and config defaults.
+// Priority: annotations > config defaults
+func ResolveCharsetOptions(annotations map[string]string, defaults config.StringOptions) CharsetOptions {
+	opts := CharsetOptions{
+		Uppercase:           defaults.Uppercase,
+		Lowercase:           defaults.Lowercase,
+		Numbers:             defaults.Numbers,
+		SpecialChars:        defaults.SpecialChars,
+		AllowedSpecialChars: defaults.AllowedSpecialChars,
+	}
+
+	if val, ok := ParseBool(annotations, StringUppercase); ok {
+		opts.Uppercase = val
+	}
+	if val, ok := ParseBool(annotations, StringLowercase); ok {
+		opts.Lowercase = val
+	}
+	if val, ok := ParseBool(annotations, StringNumbers); ok {
+		opts.Numbers = val
+	}
+	if val, ok := ParseBool(annotations, StringSpecialChars); ok {
+		opts.SpecialChars = val
+	}
+	// Note: We check for the annotation's existence, not just non-empty value
+	// This allows users to explicitly set it to empty if they want to override the config
+	if val, ok := annotations[StringAllowedSpecialChars]; ok {
+		opts.AllowedSpecialChars = val
+	}
+
+	return opts
+}
+
+// Validate validates the charset options
+func (o CharsetOptions) Validate() error {
+	if !o.Uppercase && !o.Lowercase && !o.Numbers && !o.SpecialChars {
+		return fmt.Errorf("at least one charset option must be enabled (uppercase, lowercase, numbers, or specialChars)")
+	}
+	if o.SpecialChars && o.AllowedSpecialChars == "" {
+		return fmt.Errorf("allowedSpecialChars must not be empty when specialChars is enabled")
+	}
+	if o.SpecialChars {
+		return config.ValidateSpecialChars(o.AllowedSpecialChars)
+	}
+	return nil
+}
+
+// Charset builds the charset string from the options
+func (o CharsetOptions) Charset() string {
+	var charset string
+	if o.Lowercase {
+		charset += "abcdefghijklmnopqrstuvwxyz"
+	}
+	if o.Uppercase {
+		charset += "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	}
+	if o.Numbers {
+		charset += "0123456789"
+	}
+	if o.SpecialChars {
+		charset += o.AllowedSpecialChars
+	}
+	return charset
+}
+
+// Charset resolves and validates the charset of string fields.
+// Priority: annotations > config defaults
+func Charset(annotations map[string]string, defaults config.StringOptions) (string, error) {
+	opts := ResolveCharsetOptions(annotations, defaults)
+	if err := opts.Validate(); err != nil {
+		return "", err
+	}
+	return opts.Charset(), nil
+}
@@ -0,0 +1,316 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package annotations
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+func TestFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    []string
+	}{
+		{name: "missing annotation", annotations: map[string]string{}, expected: nil},
+		{name: "empty annotation", annotations: map[string]string{Autogenerate: ""}, expected: nil},
+		{name: "single field", annotations: map[string]string{Autogenerate: "password"}, expected: []string{"password"}},
+		{
+			name:        "fields with whitespace and empty entries",
+			annotations: map[string]string{Autogenerate: " password, ,api-key ,"},
+			expected:    []string{"password", "api-key"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := Fields(tt.annotations); !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestTranslateLegacy(t *testing.T) {
+	annotations := map[string]string{
+		"legacy.example.com/autogenerate": "password,api-key",
+		"legacy.example.com/length":       "16",
+		Length:                            "32",
+		"legacy.example.com/":             "ignored",
+		"other.example.com/length":        "8",
+	}
+	if translated := TranslateLegacy(annotations, "legacy.example.com/"); !reflect.DeepEqual(translated, []string{Autogenerate}) {
+		t.Fatalf("expected autogenerate to be translated, got %v", translated)
+	}
+	expected := map[string]string{
+		"legacy.example.com/autogenerate": "password,api-key",
+		"legacy.example.com/length":       "16",
+		Autogenerate:                      "password,api-key",
+		Length:                            "32",
+		"legacy.example.com/":             "ignored",
+		"other.example.com/length":        "8",
+	}
+	if !reflect.DeepEqual(annotations, expected) {
+		t.Errorf("expected %v, got %v", expected, annotations)
+	}
+
+	if translated := TranslateLegacy(annotations, "legacy.example.com/"); len(translated) != 0 {
+		t.Error("expected no change when the primary annotations exist")
+	}
+	if translated := TranslateLegacy(map[string]string{Autogenerate: "password"}, ""); len(translated) != 0 {
+		t.Error("expected no change without a legacy prefix")
+	}
+}
+
+func TestSetOrigin(t *testing.T) {
+	annotations := map[string]string{}
+	if !SetOrigin(annotations, "password", OriginGenerated) {
+		t.Fatal("expected the origin to be recorded")
+	}
+	if annotations[OriginPrefix+"password"] != OriginGenerated {
+		t.Errorf("expected origin %q, got %v", OriginGenerated, annotations)
+	}
+	if SetOrigin(annotations, "password", OriginGenerated) {
+		t.Error("expected no change for the same origin")
+	}
+	if !SetOrigin(annotations, "password", OriginAdopted) || annotations[OriginPrefix+"password"] != OriginAdopted {
+		t.Errorf("expected the origin to be replaced, got %v", annotations)
+	}
+
+	// Keys that cannot form a valid annotation name are not recorded
+	for _, key := range []string{strings.Repeat("k", 60), "key_", "key."} {
+		if SetOrigin(annotations, key, OriginReplicated) {
+			t.Errorf("expected no origin for key %q", key)
+		}
+		if _, ok := annotations[OriginPrefix+key]; ok {
+			t.Errorf("expected no origin annotation for key %q", key)
+		}
+	}
+}
+
+func TestValidateFields(t *testing.T) {
+	if err := ValidateFields([]string{"password", "api-key", "tls.crt", "DB_PASSWORD"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, field := range []string{"db password", "api/key", "pass\u00e9", ".."} {
+		err := ValidateFields([]string{"password", field})
+		if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("%q", field)) {
+			t.Errorf("expected error mentioning %q, got %v", field, err)
+		}
+	}
+}
+
+func TestParseRenderMapping(t *testing.T) {
+	mapping := ParseRenderMapping(map[string]string{RenderMapping: "db-password = DB_PASSWORD, invalid, =name, key=, tls.crt=cert"})
+	expected := map[string]string{"db-password": "DB_PASSWORD", "tls.crt": "cert"}
+	if !reflect.DeepEqual(mapping, expected) {
+		t.Errorf("expected %v, got %v", expected, mapping)
+	}
+}
+
+func TestValidateRotation(t *testing.T) {
+	valid := map[string]string{Rotate: "7d", RotatePrefix + "pin": "0", RotatePrefix + "other": "invalid"}
+	if err := ValidateRotation(valid, []string{"password", "pin"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for key, value := range map[string]string{Rotate: "weekly", RotatePrefix + "pin": "-1h"} {
+		err := ValidateRotation(map[string]string{key: value}, []string{"password", "pin"})
+		if err == nil || !strings.Contains(err.Error(), key) {
+			t.Errorf("expected error mentioning %s for %q, got %v", key, value, err)
+		}
+	}
+}
+
+func TestParseCertificate(t *testing.T) {
+	cert, err := ParseCertificate(map[string]string{
+		TLSDNSNames:    "app.example.com, *.app.example.com",
+		TLSIPAddresses: "10.0.0.1,::1",
+		TLSKeyUsages:   "server-auth,client-auth",
+		TLSDuration:    "30d",
+		TLSRenewBefore: "7d",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(cert.DNSNames, []string{"app.example.com", "*.app.example.com"}) {
+		t.Errorf("unexpected DNS names %v", cert.DNSNames)
+	}
+	if len(cert.IPAddresses) != 2 || cert.IPAddresses[0].String() != "10.0.0.1" || cert.IPAddresses[1].String() != "::1" {
+		t.Errorf("unexpected IP addresses %v", cert.IPAddresses)
+	}
+	if !reflect.DeepEqual(cert.KeyUsages, []string{"server-auth", "client-auth"}) {
+		t.Errorf("unexpected key usages %v", cert.KeyUsages)
+	}
+	if cert.Duration != 30*24*time.Hour || cert.RenewBefore != 7*24*time.Hour {
+		t.Errorf("unexpected duration %s and renew-before %s", cert.Duration, cert.RenewBefore)
+	}
+
+	for name, annotations := range map[string]map[string]string{
+		TLSDNSNames:    {TLSDNSNames: "app_example.com"},
+		TLSIPAddresses: {TLSIPAddresses: "10.0.0.256"},
+		TLSDuration:    {TLSDuration: "0"},
+		TLSRenewBefore: {TLSDuration: "1d", TLSRenewBefore: "2d"},
+	} {
+		if _, err := ParseCertificate(annotations); err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("expected error mentioning %s for %v, got %v", name, annotations, err)
+		}
+	}
+}
+
+func TestFieldOverrides(t *testing.T) {
+	annotations := map[string]string{
+		Type:                      "bytes",
+		Length:                    "24",
+		TypePrefix + "pin":        "string",
+		LengthPrefix + "pin":      "6",
+		LengthPrefix + "key":      "invalid",
+		Encoding:                  "base64",
+		EncodingPrefix + "pin":    "hex",
+		Rotate:                    "7d",
+		RotatePrefix + "pin":      "1h",
+		RotatePrefix + "key":      "invalid",
+		GeneratedAt:               "2025-01-01T00:00:00Z",
+		RotationRequestedAt:       "not a timestamp",
+		StringUppercase:           "false",
+		StringSpecialChars:        "TRUE",
+		StringNumbers:             "maybe",
+		StringAllowedSpecialChars: "!",
+	}
+
+	if got := FieldType(annotations, "pin", config.DefaultType); got != "string" {
+		t.Errorf("expected field-specific type, got %q", got)
+	}
+	if got := FieldType(annotations, "key", config.DefaultType); got != "bytes" {
+		t.Errorf("expected type annotation, got %q", got)
+	}
+	if got := FieldType(map[string]string{}, "key", config.DefaultType); got != config.DefaultType {
+		t.Errorf("expected default type, got %q", got)
+	}
+
+	if got := FieldLength(annotations, "pin", 32); got != 6 {
+		t.Errorf("expected field-specific length, got %d", got)
+	}
+	if got := FieldLength(annotations, "key", 32); got != 24 {
+		t.Errorf("expected invalid field length to fall back to length annotation, got %d", got)
+	}
+	if got := FieldLength(map[string]string{Length: "-1"}, "key", 32); got != 32 {
+		t.Errorf("expected default length, got %d", got)
+	}
+
+	if got := FieldEncoding(annotations, "pin"); got != "hex" {
+		t.Errorf("expected field-specific encoding, got %q", got)
+	}
+	if got := FieldEncoding(annotations, "key"); got != "base64" {
+		t.Errorf("expected encoding annotation, got %q", got)
+	}
+	if got := FieldEncoding(map[string]string{}, "key"); got != "" {
+		t.Errorf("expected no encoding, got %q", got)
+	}
+
+	if got := FieldRotationInterval(annotations, "pin", 0); got != time.Hour {
+		t.Errorf("expected field-specific rotation interval, got %s", got)
+	}
+	if got := FieldRotationInterval(annotations, "key", 0); got != 7*24*time.Hour {
+		t.Errorf("expected invalid field interval to fall back to rotate annotation, got %s", got)
+	}
+	if got := FieldRotationInterval(map[string]string{}, "key", 0); got != 0 {
+		t.Errorf("expected no rotation, got %s", got)
+	}
+	if got := FieldRotationInterval(map[string]string{}, "key", 90*24*time.Hour); got != 90*24*time.Hour {
+		t.Errorf("expected default rotation interval, got %s", got)
+	}
+	if got := FieldRotationInterval(map[string]string{Rotate: "0"}, "key", 90*24*time.Hour); got != 0 {
+		t.Errorf("expected explicit opt-out to disable rotation, got %s", got)
+	}
+	excluded := map[string]string{Rotate: "24h", RotateExclude: "client_id, username"}
+	if got := FieldRotationInterval(excluded, "username", 0); got != 0 {
+		t.Errorf("expected excluded field not to be rotated, got %s", got)
+	}
+	if got := FieldRotationInterval(excluded, "password", 0); got != 24*time.Hour {
+		t.Errorf("expected rotate annotation for fields that are not excluded, got %s", got)
+	}
+
+	expectedTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	if got := ParseTimestamp(annotations, GeneratedAt); got == nil || !got.Equal(expectedTime) {
+		t.Errorf("expected %s, got %v", expectedTime, got)
+	}
+	if got := ParseTimestamp(annotations, RotationRequestedAt); got != nil {
+		t.Errorf("expected invalid timestamp to be ignored, got %v", got)
+	}
+	if got := ParseTimestamp(map[string]string{GeneratedAt: "1735689600"}, GeneratedAt); got == nil || !got.Equal(expectedTime) {
+		t.Errorf("expected Unix timestamp %s, got %v", expectedTime, got)
+	}
+	if got := FormatTimestamp(expectedTime, config.TimestampFormatUnix); got != "1735689600" {
+		t.Errorf("expected Unix timestamp, got %q", got)
+	}
+	if got := FormatTimestamp(expectedTime, config.TimestampFormatRFC3339); got != "2025-01-01T00:00:00Z" {
+		t.Errorf("expected RFC 3339 timestamp, got %q", got)
+	}
+
+	opts := ResolveCharsetOptions(annotations, config.NewDefaultConfig().Defaults.String)
+	expectedOpts := CharsetOptions{
+		Uppercase:           false,
+		Lowercase:           true,
+		Numbers:             true,
+		SpecialChars:        true,
+		AllowedSpecialChars: "!",
+	}
+	if opts != expectedOpts {
+		t.Errorf("expected %+v, got %+v", expectedOpts, opts)
+	}
+}
+
+func TestCharset(t *testing.T) {
+	defaults := config.NewDefaultConfig().Defaults.String
+
+	charset, err := Charset(map[string]string{StringUppercase: "0", StringLowercase: "0"}, defaults)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if charset != "0123456789" {
+		t.Errorf("expected numbers only, got %q", charset)
+	}
+
+	if _, err := Charset(map[string]string{
+		StringUppercase: "false",
+		StringLowercase: "false",
+		StringNumbers:   "false",
+	}, defaults); err == nil {
+		t.Error("expected error when all charset options are disabled")
+	}
+
+	if _, err := Charset(map[string]string{
+		StringSpecialChars:        "true",
+		StringAllowedSpecialChars: "",
+	}, defaults); err == nil {
+		t.Error("expected error when special characters are enabled without allowed characters")
+	}
+
+	if _, err := Charset(map[string]string{
+		StringSpecialChars:        "true",
+		StringAllowedSpecialChars: "!€",
+	}, defaults); err == nil {
+		t.Error("expected error for non-ASCII special characters")
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clock
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(start)
+
+	if !c.Now().Equal(start) {
+		t.Errorf("expected %s, got %s", start, c.Now())
+	}
+
+	c.Advance(time.Hour)
+	if expected := start.Add(time.Hour); !c.Now().Equal(expected) {
+		t.Errorf("expected %s after Advance, got %s", expected, c.Now())
+	}
+
+	c.Set(start)
+	if !c.Now().Equal(start) {
+		t.Errorf("expected %s after Set, got %s", start, c.Now())
+	}
+
+	// Concurrent use must be safe (run with -race)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			c.Advance(time.Second)
+		}()
+		go func() {
+			defer wg.Done()
+			_ = c.Now()
+		}()
+	}
+	wg.Wait()
+	if expected := start.Add(10 * time.Second); !c.Now().Equal(expected) {
+		t.Errorf("expected %s after concurrent Advance, got %s", expected, c.Now())
+	}
+}
+
+func TestNow(t *testing.T) {
+	fixed := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	if got := Now(NewFakeClock(fixed)); !got.Equal(fixed) {
+		t.Errorf("expected %s, got %s", fixed, got)
+	}
+
+	before := time.Now()
+	if got := Now(nil); got.Before(before) {
+		t.Errorf("expected real time for nil clock, got %s", got)
+	}
+}
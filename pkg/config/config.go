@@ -20,9 +20,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
+	"unicode"
 
 	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 const (
@@ -35,6 +40,29 @@ const (
 	// TypeBytes is the bytes generation type
 	TypeBytes = "bytes"
 
+	// TypeHex is the generation type for random bytes stored as lowercase hex, e.g. for HMAC keys
+	TypeHex = "hex"
+
+	// TypeUUID is the generation type for random (version 4) UUIDs, e.g. for instance identifiers
+	TypeUUID = "uuid"
+
+	// TypeTLS is the TLS certificate generation type
+	TypeTLS = "tls"
+
+	// TypeSignedToken is the generation type for time-limited tokens signed with another field
+	TypeSignedToken = "signed-token"
+
+	// TypeOAuthClient is the generation type for OAuth client credentials: a random client secret
+	// in the field and a stable client ID in the matching client ID field
+	TypeOAuthClient = "oauth-client"
+
+	// ClientIDFormatUUID generates OAuth client IDs as random (version 4) UUIDs
+	ClientIDFormatUUID = "uuid"
+	// ClientIDFormatHex generates OAuth client IDs as 32 lowercase hex characters
+	ClientIDFormatHex = "hex"
+	// ClientIDFormatAlphanumeric generates OAuth client IDs as 32 alphanumeric characters
+	ClientIDFormatAlphanumeric = "alphanumeric"
+
 	// DefaultLength is the default length for generated values
 	DefaultLength = 32
 
@@ -43,19 +71,502 @@ const (
 
 	// DefaultRotationMinInterval is the minimum allowed rotation interval
 	DefaultRotationMinInterval = 5 * time.Minute
+
+	// DefaultCertificateDuration is the default validity period of generated certificates
+	DefaultCertificateDuration = 90 * 24 * time.Hour
+
+	// DefaultCertificateRenewalFraction is the default fraction of a certificate's
+	// lifetime after which it is renewed
+	DefaultCertificateRenewalFraction = 2.0 / 3.0
+
+	// DefaultTokenDuration is the default validity period of generated signed tokens
+	DefaultTokenDuration = 24 * time.Hour
+
+	// DefaultTokenRenewalFraction is the default fraction of a signed token's lifetime after
+	// which it is refreshed
+	DefaultTokenRenewalFraction = 2.0 / 3.0
+
+	// DefaultSourceFanOutRate is the default number of targets of a single replication source
+	// that are enqueued per second
+	DefaultSourceFanOutRate = 20
+
+	// DefaultTTLWarningBefore is how long before TTL expiry a warning event is emitted
+	DefaultTTLWarningBefore = time.Hour
+
+	// DefaultManagedLabelKey is the default label key used for label-based opt-in
+	DefaultManagedLabelKey = "iso.gtrfc.com/managed"
+
+	// DefaultManagedLabelValue is the default label value used for label-based opt-in
+	DefaultManagedLabelValue = "true"
+
+	// DefaultMetricsMaxNamespaces is the default number of namespaces reported individually
+	// in per-namespace metrics
+	DefaultMetricsMaxNamespaces = 50
+
+	// DefaultRotationOverdueThreshold is the default time after which a due rotation counts as overdue
+	DefaultRotationOverdueThreshold = time.Hour
+
+	// DefaultWorkqueueDepthThreshold is the default number of pending reconcile requests of a
+	// controller above which a backpressure warning is logged
+	DefaultWorkqueueDepthThreshold = 1000
+
+	// DefaultWorkqueueAgeThreshold is the default time a controller's queue may stay non-empty
+	// before a backpressure warning is logged
+	DefaultWorkqueueAgeThreshold = 10 * time.Minute
+
+	// DefaultInventoryInterval is the default interval at which the inventory ConfigMaps are updated
+	DefaultInventoryInterval = 10 * time.Minute
+
+	// DefaultAnnotationHygieneInterval is the default interval at which the annotations of all
+	// Secrets are checked for problems
+	DefaultAnnotationHygieneInterval = time.Hour
+
+	// DefaultResyncCheckpointInterval is the default interval at which the leader records its
+	// resync checkpoint
+	DefaultResyncCheckpointInterval = 30 * time.Second
+
+	// DefaultResyncSpread is the default window over which the Secrets that did not change since
+	// the checkpoint of the previous leader are reconciled
+	DefaultResyncSpread = 10 * time.Minute
+
+	// DefaultRandomSource is the default random source for generated values
+	DefaultRandomSource = "crypto/rand"
+
+	// DefaultHealthStallTimeout is the default time a controller may have pending reconcile requests
+	// without a completed reconcile before the liveness check fails
+	DefaultHealthStallTimeout = 15 * time.Minute
+
+	// DefaultEncryptionCheckInterval is the default interval at which the encryption-at-rest
+	// posture check is repeated
+	DefaultEncryptionCheckInterval = time.Hour
+
+	// DefaultInventoryConfigMapName is the default name of the per-namespace inventory ConfigMap
+	DefaultInventoryConfigMapName = "secret-operator-inventory"
+
+	// DriftPolicyOverwrite overwrites manually modified replicated Secrets and emits a warning
+	DriftPolicyOverwrite = "overwrite"
+
+	// DriftPolicyWarn only emits a warning and leaves manually modified replicated Secrets untouched
+	DriftPolicyWarn = "warn"
+
+	// TimestampFormatRFC3339 writes timestamp annotations as RFC 3339 (e.g. "2025-01-01T00:00:00Z")
+	TimestampFormatRFC3339 = "rfc3339"
+
+	// TimestampFormatUnix writes timestamp annotations as Unix epoch seconds (e.g. "1735689600")
+	TimestampFormatUnix = "unix"
+
+	// ShardingModeHash assigns namespaces to shards by a hash of the namespace name
+	ShardingModeHash = "hash"
+
+	// ShardingModeLabel assigns namespaces to the shard in their shard label,
+	// falling back to the hash for namespaces without a valid label
+	ShardingModeLabel = "label"
+
+	// DefaultShardingLabelKey is the namespace label holding the shard index in label mode
+	DefaultShardingLabelKey = "iso.gtrfc.com/shard"
 )
 
+// Controllers whose resync period can be overridden
+const (
+	ControllerSecretGenerator  = "secret-generator"
+	ControllerSecretReplicator = "secret-replicator"
+)
+
+// ResyncControllers lists the controllers whose resync period can be overridden
+var ResyncControllers = []string{
+	ControllerSecretGenerator,
+	ControllerSecretReplicator,
+}
+
+// Features that policies can restrict
+const (
+	PolicyFeatureAutogenerate     = "autogenerate"
+	PolicyFeatureRotate           = "rotate"
+	PolicyFeatureTTL              = "ttl"
+	PolicyFeatureReplicateTo      = "replicate-to"
+	PolicyFeatureReplicateFrom    = "replicate-from"
+	PolicyFeatureReplicatableFrom = "replicatable-from"
+	PolicyFeatureReplicateAs      = "replicate-as"
+)
+
+// PolicyFeatures lists all features that policies can restrict
+var PolicyFeatures = []string{
+	PolicyFeatureAutogenerate,
+	PolicyFeatureRotate,
+	PolicyFeatureTTL,
+	PolicyFeatureReplicateTo,
+	PolicyFeatureReplicateFrom,
+	PolicyFeatureReplicatableFrom,
+	PolicyFeatureReplicateAs,
+}
+
 // Config holds the operator configuration
 type Config struct {
 	Defaults DefaultsConfig `yaml:"defaults"`
 	Rotation RotationConfig `yaml:"rotation"`
-	Features FeaturesConfig `yaml:"features"`
+	TTL      TTLConfig      `yaml:"ttl"`
+	// Cleanup controls what happens to generated values when fields are removed from autogenerate
+	Cleanup CleanupConfig `yaml:"cleanup"`
+	// Quota limits the generated Secrets and fields per namespace
+	Quota QuotaConfig `yaml:"quota"`
+	// Certificates holds the configuration for generated TLS certificates
+	Certificates CertificatesConfig `yaml:"certificates"`
+	// Tokens holds the configuration for generated signed tokens
+	Tokens TokensConfig `yaml:"tokens"`
+	// OAuthClients holds the configuration for generated OAuth client credentials
+	OAuthClients OAuthClientsConfig `yaml:"oauthClients"`
+	Features     FeaturesConfig     `yaml:"features"`
+	// ManagedLabel restricts the operator to Secrets carrying a specific label
+	ManagedLabel ManagedLabelConfig `yaml:"managedLabel"`
+	// Replication holds cluster-wide settings for secret replication
+	Replication ReplicationConfig `yaml:"replication"`
+	// IgnoredSecretTypes lists Secret types that are never processed, regardless of annotations
+	IgnoredSecretTypes []string `yaml:"ignoredSecretTypes"`
+	// LegacyAnnotationPrefix is an additional annotation prefix (e.g. "secret-operator.example.com/")
+	// that is read alongside the primary one while Secrets are migrated. Annotations are always
+	// written with the primary prefix. Empty disables legacy annotations.
+	LegacyAnnotationPrefix string `yaml:"legacyAnnotationPrefix"`
+	// Metrics holds the configuration of the operator's Prometheus metrics
+	Metrics MetricsConfig `yaml:"metrics"`
+	// ActivityLog holds the configuration of the structured activity stream
+	ActivityLog ActivityLogConfig `yaml:"activityLog"`
+	// Events limits the Kubernetes Events the operator emits
+	Events EventsConfig `yaml:"events"`
+	// Inventory holds the configuration of the per-namespace inventory ConfigMaps
+	Inventory InventoryConfig `yaml:"inventory"`
+	// AnnotationHygiene holds the configuration of the periodic check of the operator annotations
+	AnnotationHygiene AnnotationHygieneConfig `yaml:"annotationHygiene"`
+	// DifferentialResync holds the configuration of the initial resync after a leader failover
+	DifferentialResync DifferentialResyncConfig `yaml:"differentialResync"`
+	// Health holds the configuration of the liveness check
+	Health HealthConfig `yaml:"health"`
+	// Cache holds the configuration of the informer cache
+	Cache CacheConfig `yaml:"cache"`
+	// EncryptionCheck holds the configuration of the encryption-at-rest posture check
+	EncryptionCheck EncryptionCheckConfig `yaml:"encryptionCheck"`
+	// Randomness holds the configuration of the random source for generated values
+	Randomness RandomnessConfig `yaml:"randomness"`
+	// Policies restrict the features available to Secrets by namespace
+	Policies []PolicyRule `yaml:"policies"`
+	// Sharding splits the namespaces across operator replicas
+	Sharding ShardingConfig `yaml:"sharding"`
+	// Bootstrap lists Secrets the operator creates in new namespaces
+	Bootstrap []BootstrapSecret `yaml:"bootstrap"`
+}
+
+// BootstrapSecret is a Secret the operator creates in namespaces that list its name in the
+// bootstrap-secrets annotation or that match Namespaces or NamespaceSelector. Existing Secrets
+// are never modified.
+type BootstrapSecret struct {
+	// Name is the name of the created Secret
+	Name string `yaml:"name"`
+	// Annotations of the created Secret, e.g. iso.gtrfc.com/autogenerate
+	Annotations map[string]string `yaml:"annotations"`
+	// Labels of the created Secret
+	Labels map[string]string `yaml:"labels"`
+	// Type of the created Secret (default: Opaque)
+	Type string `yaml:"type"`
+	// Namespaces lists the namespaces (glob patterns) that receive the Secret without annotation
+	Namespaces []string `yaml:"namespaces"`
+	// NamespaceSelector is a label selector on the Namespace (e.g. "tenant=true") for namespaces
+	// that receive the Secret without annotation
+	NamespaceSelector string `yaml:"namespaceSelector"`
+}
+
+// ShardingConfig holds the configuration for splitting namespaces across operator replicas.
+// Each replica owns the namespaces of one shard and runs its own leader election.
+type ShardingConfig struct {
+	// Shards is the number of shards (0 or 1 disables sharding)
+	Shards int `yaml:"shards"`
+	// Mode selects how namespaces are assigned to shards ("hash" or "label")
+	Mode string `yaml:"mode"`
+	// LabelKey is the namespace label holding the shard index in label mode
+	LabelKey string `yaml:"labelKey"`
+}
+
+// Enabled returns true if namespaces are split across more than one shard
+func (s ShardingConfig) Enabled() bool {
+	return s.Shards > 1
+}
+
+// PolicyRule restricts the features available to Secrets in the namespaces it applies to.
+// A rule applies to a namespace that matches Namespaces (all if empty), does not match
+// ExcludeNamespaces and whose labels match NamespaceSelector (all if empty).
+type PolicyRule struct {
+	// Name identifies the rule in events and admission errors
+	Name string `yaml:"name"`
+	// Namespaces lists the namespaces (glob patterns) the rule applies to
+	Namespaces []string `yaml:"namespaces"`
+	// ExcludeNamespaces lists namespaces (glob patterns) the rule does not apply to
+	ExcludeNamespaces []string `yaml:"excludeNamespaces"`
+	// NamespaceSelector is a label selector on the Namespace (e.g. "tenant=true")
+	NamespaceSelector string `yaml:"namespaceSelector"`
+	// DeniedFeatures lists the features Secrets must not use (see PolicyFeatures)
+	DeniedFeatures []string `yaml:"deniedFeatures"`
+	// MinRotationInterval is the shortest rotation interval Secrets may use (0 means no limit)
+	MinRotationInterval Duration `yaml:"minRotationInterval"`
+	// ExemptLabel exempts Secrets carrying this label ("key" or "key=value") from the rule
+	ExemptLabel string `yaml:"exemptLabel"`
+}
+
+// RandomnessConfig holds the configuration of the random source for generated values
+type RandomnessConfig struct {
+	// Source is the name of the random source, "crypto/rand" or a source registered by the build
+	// (e.g. an HSM or KMS DRBG)
+	Source string `yaml:"source"`
+}
+
+// HealthConfig holds the configuration of the liveness check
+type HealthConfig struct {
+	// StallTimeout is how long a controller may have pending reconcile requests without a
+	// completed reconcile before the liveness check fails
+	StallTimeout Duration `yaml:"stallTimeout"`
+}
+
+// EncryptionCheckConfig holds the configuration of the check whether the API server encrypts
+// Secrets at rest. The check is best-effort: it reads the storage metrics of the API server, which
+// many clusters do not expose. It is disabled by default.
+type EncryptionCheckConfig struct {
+	// Enabled runs the check at startup and every Interval
+	Enabled bool `yaml:"enabled"`
+	// Interval is how often the check is repeated
+	Interval Duration `yaml:"interval"`
+}
+
+// CacheConfig holds the configuration of the informer cache. A resync reconciles every watched
+// object again, which heals state that was changed without the operator noticing.
+type CacheConfig struct {
+	// ResyncPeriod is how often the informers resync (0 = controller-runtime default of 10 hours).
+	// It applies to all controllers.
+	ResyncPeriod Duration `yaml:"resyncPeriod"`
+	// ControllerResyncPeriods resyncs individual controllers (see ResyncControllers) more often
+	// than ResyncPeriod by periodically enqueuing all of their Secrets
+	ControllerResyncPeriods map[string]Duration `yaml:"controllerResyncPeriods"`
+}
+
+// ResyncPeriodFor returns the resync period override of a controller (0 if not set)
+func (c CacheConfig) ResyncPeriodFor(controller string) time.Duration {
+	return c.ControllerResyncPeriods[controller].Duration()
+}
+
+// InventoryConfig holds the configuration of the per-namespace inventory ConfigMaps
+type InventoryConfig struct {
+	// Enabled maintains a ConfigMap per namespace summarizing the operator-managed Secrets
+	Enabled bool `yaml:"enabled"`
+	// Interval is how often the inventory ConfigMaps are updated
+	Interval Duration `yaml:"interval"`
+	// ConfigMapName is the name of the inventory ConfigMap in each namespace
+	ConfigMapName string `yaml:"configMapName"`
+}
+
+// AnnotationHygieneConfig holds the configuration of the periodic check of the operator
+// annotations of all Secrets. Unknown annotations are ignored by the controllers, so typos would
+// otherwise fail silently.
+type AnnotationHygieneConfig struct {
+	// Enabled reports deprecated, misspelled and conflicting annotations via a metric and a
+	// summary Event per namespace
+	Enabled bool `yaml:"enabled"`
+	// Interval is how often the annotations are checked
+	Interval Duration `yaml:"interval"`
+}
+
+// DifferentialResyncConfig holds the configuration of the initial resync after a leader failover
+// or restart. The leader records a checkpoint, and its successor reconciles the Secrets that
+// changed since then first and spreads the others over a window.
+type DifferentialResyncConfig struct {
+	// Enabled records the checkpoint and uses the checkpoint of the previous leader at startup
+	Enabled bool `yaml:"enabled"`
+	// CheckpointInterval is how often the leader records its checkpoint
+	CheckpointInterval Duration `yaml:"checkpointInterval"`
+	// Spread is the window over which the Secrets that did not change since the checkpoint are reconciled
+	Spread Duration `yaml:"spread"`
+}
+
+// ActivityLogConfig holds the configuration of the structured activity stream
+type ActivityLogConfig struct {
+	// Enabled writes every decision the operator reports as an Event as a JSON line to stdout,
+	// separate from the operator's logs on stderr
+	Enabled bool `yaml:"enabled"`
+}
+
+// EventsConfig limits the Kubernetes Events the operator emits, so that a single Secret that
+// fails on every reconcile cannot exhaust the Event budget of its namespace
+type EventsConfig struct {
+	// MaxPerSecretPerHour limits the Events emitted per Secret (or other object) per hour (0 = unlimited)
+	MaxPerSecretPerHour int `yaml:"maxPerSecretPerHour"`
+	// MaxPerHour limits the Events emitted per hour cluster-wide (0 = unlimited)
+	MaxPerHour int `yaml:"maxPerHour"`
+}
+
+// MetricsConfig holds the configuration of the operator's Prometheus metrics
+type MetricsConfig struct {
+	// PerNamespace adds a namespace label to the managed secrets, rotation and replication metrics
+	PerNamespace bool `yaml:"perNamespace"`
+	// MaxNamespaces limits the number of namespaces reported individually;
+	// all other namespaces are aggregated to keep the label cardinality bounded
+	MaxNamespaces int `yaml:"maxNamespaces"`
+	// RotationOverdueThreshold is the time after which a due rotation or certificate renewal
+	// that has not happened counts as overdue
+	RotationOverdueThreshold Duration `yaml:"rotationOverdueThreshold"`
+	// WorkqueueDepthThreshold is the number of pending reconcile requests of a controller above
+	// which a backpressure warning is logged (0 = disabled)
+	WorkqueueDepthThreshold int `yaml:"workqueueDepthThreshold"`
+	// WorkqueueAgeThreshold is the time a controller's queue may stay non-empty before a
+	// backpressure warning is logged (0 = disabled)
+	WorkqueueAgeThreshold Duration `yaml:"workqueueAgeThreshold"`
+}
+
+// DefaultIgnoredSecretTypes are Secret types owned by other components (Helm release state
+// and service account tokens) that must never be modified by the operator
+var DefaultIgnoredSecretTypes = []string{
+	"helm.sh/release.v1",
+	"kubernetes.io/service-account-token",
+}
+
+// IsSecretTypeIgnored returns true if Secrets of the given type must not be processed
+func (c *Config) IsSecretTypeIgnored(secretType string) bool {
+	return slices.Contains(c.IgnoredSecretTypes, secretType)
+}
+
+// ReplicationConfig holds the cluster-wide configuration for secret replication
+type ReplicationConfig struct {
+	// DeniedNamespaces lists namespaces (glob patterns) that Secrets are never pushed to or pulled from
+	DeniedNamespaces []string `yaml:"deniedNamespaces"`
+	// DriftPolicy defines how manual modifications of replicated Secrets are handled
+	// ("overwrite" or "warn")
+	DriftPolicy string `yaml:"driftPolicy"`
+	// Boundaries deny replication between namespaces selected by labels, in addition to
+	// the allowlists of the individual Secrets
+	Boundaries []ReplicationBoundary `yaml:"boundaries"`
+	// PushedLabels are set on all Secrets created or updated by push replication
+	PushedLabels map[string]string `yaml:"pushedLabels"`
+	// PushedType overrides the type of Secrets created by push replication from Opaque sources
+	PushedType string `yaml:"pushedType"`
+	// OwnerLabels are set on all replicated Secrets (pulled and pushed) and identify their source
+	OwnerLabels OwnerLabelsConfig `yaml:"ownerLabels"`
+	// SourceFanOutRate limits how many targets of a single source are enqueued per second when
+	// the source changes, so that a source with many targets does not delay the targets of other
+	// sources (0 = unlimited)
+	SourceFanOutRate int `yaml:"sourceFanOutRate"`
+	// MaxBytesPerSourceNamespace limits the total size of the data replicated from Secrets of one
+	// namespace, summed over all targets, so that a single tenant cannot grow etcd unnoticed
+	// (0 = unlimited)
+	MaxBytesPerSourceNamespace int64 `yaml:"maxBytesPerSourceNamespace"`
+}
+
+// OwnerLabelManagedBy is the value of the managed-by owner label
+const OwnerLabelManagedBy = "internal-secrets-operator"
+
+// OwnerLabelsConfig holds the keys of the labels that identify replicated Secrets, e.g. for
+// NetworkPolicy or admission policy selectors. Labels with an empty key are not set.
+type OwnerLabelsConfig struct {
+	// SourceNamespace is the key of the label with the namespace of the source
+	SourceNamespace string `yaml:"sourceNamespace"`
+	// SourceName is the key of the label with the name of the source
+	SourceName string `yaml:"sourceName"`
+	// ManagedBy is the key of the label with the value internal-secrets-operator
+	ManagedBy string `yaml:"managedBy"`
+}
+
+// Keys returns the configured label keys
+func (o OwnerLabelsConfig) Keys() []string {
+	var keys []string
+	for _, key := range []string{o.SourceNamespace, o.SourceName, o.ManagedBy} {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Labels returns the owner labels of a Secret replicated from the given sources
+// ("namespace/name"). The source namespace is only set if all sources share it, and the source
+// name only for a single source. Names that are not valid label values (longer than 63
+// characters) are left out.
+func (o OwnerLabelsConfig) Labels(sourceRefs []string) map[string]string {
+	result := make(map[string]string)
+	set := func(key, value string) {
+		if key != "" && value != "" && len(validation.IsValidLabelValue(value)) == 0 {
+			result[key] = value
+		}
+	}
+
+	namespace, name := "", ""
+	for i, ref := range sourceRefs {
+		ns, n, _ := strings.Cut(ref, "/")
+		if i == 0 {
+			namespace, name = ns, n
+			continue
+		}
+		if ns != namespace {
+			namespace = ""
+		}
+		name = ""
+	}
+	set(o.SourceNamespace, namespace)
+	set(o.SourceName, name)
+	set(o.ManagedBy, OwnerLabelManagedBy)
+	return result
+}
+
+// ReplicationBoundary denies replication from namespaces matching From into namespaces matching To
+type ReplicationBoundary struct {
+	// Name identifies the boundary in events
+	Name string `yaml:"name"`
+	// From is a label selector on the source namespace (e.g. "env=prod")
+	From string `yaml:"from"`
+	// To is a label selector on the target namespace (e.g. "env!=prod")
+	To string `yaml:"to"`
+}
+
+// ManagedLabelConfig holds the configuration for label-based opt-in.
+// When enabled, only Secrets carrying the label Key=Value are processed,
+// and the label is also used as a selector for the Secret informer.
+type ManagedLabelConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Key     string `yaml:"key"`
+	Value   string `yaml:"value"`
+}
+
+// Matches returns true if the given labels satisfy the opt-in label.
+// If label-based opt-in is disabled, all label sets match.
+func (m ManagedLabelConfig) Matches(objLabels map[string]string) bool {
+	if !m.Enabled {
+		return true
+	}
+	value, ok := objLabels[m.Key]
+	return ok && value == m.Value
+}
+
+// Selector returns a label selector for the opt-in label.
+// If label-based opt-in is disabled, it returns labels.Everything().
+func (m ManagedLabelConfig) Selector() labels.Selector {
+	if !m.Enabled {
+		return labels.Everything()
+	}
+	return labels.SelectorFromSet(labels.Set{m.Key: m.Value})
 }
 
 // FeaturesConfig holds feature toggle configuration
 type FeaturesConfig struct {
 	SecretGenerator  bool `yaml:"secretGenerator"`
 	SecretReplicator bool `yaml:"secretReplicator"`
+	// ValidatingWebhook rejects invalid replication annotations at admission time
+	ValidatingWebhook bool `yaml:"validatingWebhook"`
+	// ServiceAccountTokens creates and rotates long-lived token Secrets for annotated ServiceAccounts
+	ServiceAccountTokens bool `yaml:"serviceAccountTokens"`
+	// SecretSources resolves "SecretSource/<name>" references in replicate-from (requires the
+	// SecretSource CRD)
+	SecretSources bool `yaml:"secretSources"`
+	// SecretDefaults applies the annotations of SecretDefaults to matching Secrets (requires the
+	// SecretDefaults CRD)
+	SecretDefaults bool `yaml:"secretDefaults"`
+	// ConfigMapIDs generates non-secret random identifiers into ConfigMaps with the
+	// autogenerate-id annotation (caches all ConfigMaps of the cluster)
+	ConfigMapIDs bool `yaml:"configMapIDs"`
+	// ReconcileRequests reconciles Secrets on demand when a ReconcileRequest is created (requires
+	// the ReconcileRequest CRD)
+	ReconcileRequests bool `yaml:"reconcileRequests"`
 }
 
 // DefaultsConfig holds the default values for secret generation
@@ -63,12 +574,164 @@ type DefaultsConfig struct {
 	Type   string        `yaml:"type"`
 	Length int           `yaml:"length"`
 	String StringOptions `yaml:"string"`
+	// StringData writes generated string values via stringData instead of data, so that the
+	// API server encodes them
+	StringData bool `yaml:"stringData"`
+	// UniqueValues regenerates values that equal the value of another field in the same Secret
+	UniqueValues bool `yaml:"uniqueValues"`
+	// TypeProfiles override the length and charset of generated string values by Secret type.
+	// They take precedence over the built-in profiles.
+	TypeProfiles []TypeProfile `yaml:"typeProfiles"`
+	// BuiltinTypeProfiles enables the built-in profiles of the basic-auth and dockerconfigjson types
+	BuiltinTypeProfiles bool `yaml:"builtinTypeProfiles"`
+}
+
+// TypeProfile holds the generation defaults of Secrets with a specific type. Length and
+// charset annotations of a Secret take precedence over its profile.
+type TypeProfile struct {
+	// SecretType is the Secret type the profile applies to (e.g. kubernetes.io/basic-auth)
+	SecretType string `yaml:"secretType"`
+	// Fields restricts the profile to these fields (empty = all generated fields)
+	Fields []string `yaml:"fields"`
+	// Length of generated values (0 = defaults.length)
+	Length int `yaml:"length"`
+	// String replaces defaults.string for generated string values (nil = defaults.string)
+	String *StringOptions `yaml:"string"`
+}
+
+// builtinTypeProfiles are the profiles enabled by defaults.builtinTypeProfiles. They follow the
+// conventions of the well-known Secret types.
+var builtinTypeProfiles = []TypeProfile{
+	{
+		// Passwords of basic-auth Secrets are often entered by humans elsewhere,
+		// so they use all character classes
+		SecretType: "kubernetes.io/basic-auth",
+		Fields:     []string{"password"},
+		Length:     32,
+		String: &StringOptions{
+			Uppercase:           true,
+			Lowercase:           true,
+			Numbers:             true,
+			SpecialChars:        true,
+			AllowedSpecialChars: DefaultAllowedSpecialChars,
+		},
+	},
+	{
+		// Registry robot passwords end up in URLs and auth headers, so they are
+		// longer and alphanumeric
+		SecretType: "kubernetes.io/dockerconfigjson",
+		Length:     48,
+		String: &StringOptions{
+			Uppercase: true,
+			Lowercase: true,
+			Numbers:   true,
+		},
+	},
+}
+
+// FieldDefaults returns the length and charset options a field of a Secret with the given type
+// is generated with when it has no length or charset annotations. The first matching profile
+// applies, so field-specific profiles must precede a catch-all profile of the same type. The
+// built-in profiles (if enabled) are matched after the configured ones.
+func (d DefaultsConfig) FieldDefaults(secretType, field string) (int, StringOptions) {
+	length, stringOpts := d.Length, d.String
+	profiles := d.TypeProfiles
+	if d.BuiltinTypeProfiles {
+		profiles = append(slices.Clip(profiles), builtinTypeProfiles...)
+	}
+	for _, profile := range profiles {
+		if profile.SecretType != secretType {
+			continue
+		}
+		if len(profile.Fields) > 0 && !slices.Contains(profile.Fields, field) {
+			continue
+		}
+		if profile.Length > 0 {
+			length = profile.Length
+		}
+		if profile.String != nil {
+			stringOpts = *profile.String
+		}
+		break
+	}
+	return length, stringOpts
 }
 
 // RotationConfig holds the configuration for secret rotation
 type RotationConfig struct {
 	MinInterval  Duration `yaml:"minInterval"`
 	CreateEvents bool     `yaml:"createEvents"`
+	// RequireChange regenerates rotated values that equal the previous value, for consumers that
+	// treat an unchanged value as a failed rotation
+	RequireChange bool `yaml:"requireChange"`
+	// DefaultInterval rotates generated fields without a rotate annotation (0 = no rotation).
+	// Secrets opt out with a rotate annotation of "0".
+	DefaultInterval Duration `yaml:"defaultInterval"`
+	// ClockSkewTolerance is the clock skew between operator replicas and control planes that
+	// rotation checks tolerate: generated-at timestamps up to this far in the future are treated
+	// as now, and rotations become due this much before the interval has elapsed
+	ClockSkewTolerance Duration `yaml:"clockSkewTolerance"`
+	// TimestampFormat is the format of the generated-at annotation ("rfc3339" or "unix").
+	// Both formats are always accepted when parsing.
+	TimestampFormat string `yaml:"timestampFormat"`
+	// MaxConcurrent limits the number of rotations per minute cluster-wide (0 = unlimited)
+	MaxConcurrent int `yaml:"maxConcurrent"`
+	// MaxConcurrentPerNamespace limits the number of rotations per minute per namespace (0 = unlimited)
+	MaxConcurrentPerNamespace int `yaml:"maxConcurrentPerNamespace"`
+	// CatchUpWindow spreads the rotations that are overdue when the operator starts over this
+	// window, oldest first (0 = rotate all overdue Secrets immediately)
+	CatchUpWindow Duration `yaml:"catchUpWindow"`
+	// BackupRetention copies the previous values of rotated fields into a <name>-rotation-backup
+	// Secret that is deleted after this duration (0 = no backup)
+	BackupRetention Duration `yaml:"backupRetention"`
+}
+
+// CertificatesConfig holds the configuration for generated TLS certificates
+type CertificatesConfig struct {
+	// Duration is the validity period of generated certificates
+	Duration Duration `yaml:"duration"`
+	// RenewalFraction is the fraction of a certificate's lifetime after which it is renewed
+	RenewalFraction float64 `yaml:"renewalFraction"`
+}
+
+// TokensConfig holds the configuration for generated signed tokens
+type TokensConfig struct {
+	// Duration is the validity period of generated tokens
+	Duration Duration `yaml:"duration"`
+	// RenewalFraction is the fraction of a token's lifetime after which it is refreshed
+	RenewalFraction float64 `yaml:"renewalFraction"`
+}
+
+// OAuthClientsConfig holds the configuration for generated OAuth client credentials
+type OAuthClientsConfig struct {
+	// ClientIDFormat is the format of generated client IDs (uuid, hex or alphanumeric)
+	ClientIDFormat string `yaml:"clientIDFormat"`
+}
+
+// ClientIDFormats lists the valid formats of generated OAuth client IDs
+var ClientIDFormats = []string{ClientIDFormatUUID, ClientIDFormatHex, ClientIDFormatAlphanumeric}
+
+// TTLConfig holds the configuration for secret-wide TTL expiry
+type TTLConfig struct {
+	// WarningBefore is how long before expiry a Warning event is emitted on the Secret.
+	// 0 disables the warning.
+	WarningBefore Duration `yaml:"warningBefore"`
+}
+
+// CleanupConfig holds the configuration for fields removed from the autogenerate annotation
+type CleanupConfig struct {
+	// DeleteRemovedFields deletes the values the operator generated for fields that are no longer
+	// listed in the autogenerate annotation. By default, the values are kept.
+	DeleteRemovedFields bool `yaml:"deleteRemovedFields"`
+}
+
+// QuotaConfig holds the per-namespace limits for Secret generation. Secrets that would exceed
+// a limit are not generated; Secrets that are already generated keep being rotated.
+type QuotaConfig struct {
+	// MaxSecretsPerNamespace limits the number of Secrets with generated values per namespace (0 = unlimited)
+	MaxSecretsPerNamespace int `yaml:"maxSecretsPerNamespace"`
+	// MaxFieldsPerNamespace limits the number of generated fields per namespace (0 = unlimited)
+	MaxFieldsPerNamespace int `yaml:"maxFieldsPerNamespace"`
 }
 
 // StringOptions holds the character set options for string generation
@@ -80,6 +743,20 @@ type StringOptions struct {
 	AllowedSpecialChars string `yaml:"allowedSpecialChars"`
 }
 
+// Validate checks that the options produce a usable charset
+func (o StringOptions) Validate() error {
+	// At least one charset option must be enabled for string type
+	if !o.Uppercase && !o.Lowercase && !o.Numbers && !o.SpecialChars {
+		return fmt.Errorf("at least one charset option must be enabled (uppercase, lowercase, numbers, or specialChars)")
+	}
+
+	// If specialChars is enabled, allowedSpecialChars must not be empty
+	if o.SpecialChars && o.AllowedSpecialChars == "" {
+		return fmt.Errorf("allowedSpecialChars must not be empty when specialChars is enabled")
+	}
+	return ValidateSpecialChars(o.AllowedSpecialChars)
+}
+
 // Duration is a wrapper around time.Duration that supports YAML unmarshaling
 type Duration time.Duration
 
@@ -145,13 +822,80 @@ func NewDefaultConfig() *Config {
 			},
 		},
 		Rotation: RotationConfig{
-			MinInterval:  Duration(DefaultRotationMinInterval),
-			CreateEvents: false,
+			MinInterval:     Duration(DefaultRotationMinInterval),
+			CreateEvents:    false,
+			TimestampFormat: TimestampFormatRFC3339,
+		},
+		TTL: TTLConfig{
+			WarningBefore: Duration(DefaultTTLWarningBefore),
+		},
+		Certificates: CertificatesConfig{
+			Duration:        Duration(DefaultCertificateDuration),
+			RenewalFraction: DefaultCertificateRenewalFraction,
+		},
+		Tokens: TokensConfig{
+			Duration:        Duration(DefaultTokenDuration),
+			RenewalFraction: DefaultTokenRenewalFraction,
+		},
+		OAuthClients: OAuthClientsConfig{
+			ClientIDFormat: ClientIDFormatUUID,
 		},
 		Features: FeaturesConfig{
 			SecretGenerator:  true,
 			SecretReplicator: true,
 		},
+		ManagedLabel: ManagedLabelConfig{
+			Enabled: false,
+			Key:     DefaultManagedLabelKey,
+			Value:   DefaultManagedLabelValue,
+		},
+		Replication: ReplicationConfig{
+			DriftPolicy:      DriftPolicyOverwrite,
+			SourceFanOutRate: DefaultSourceFanOutRate,
+		},
+		IgnoredSecretTypes: slices.Clone(DefaultIgnoredSecretTypes),
+		Metrics: MetricsConfig{
+			PerNamespace:             false,
+			MaxNamespaces:            DefaultMetricsMaxNamespaces,
+			RotationOverdueThreshold: Duration(DefaultRotationOverdueThreshold),
+			WorkqueueDepthThreshold:  DefaultWorkqueueDepthThreshold,
+			WorkqueueAgeThreshold:    Duration(DefaultWorkqueueAgeThreshold),
+		},
+		ActivityLog: ActivityLogConfig{
+			Enabled: false,
+		},
+		Events: EventsConfig{
+			MaxPerSecretPerHour: 0,
+			MaxPerHour:          0,
+		},
+		Inventory: InventoryConfig{
+			Enabled:       false,
+			Interval:      Duration(DefaultInventoryInterval),
+			ConfigMapName: DefaultInventoryConfigMapName,
+		},
+		AnnotationHygiene: AnnotationHygieneConfig{
+			Enabled:  false,
+			Interval: Duration(DefaultAnnotationHygieneInterval),
+		},
+		DifferentialResync: DifferentialResyncConfig{
+			Enabled:            false,
+			CheckpointInterval: Duration(DefaultResyncCheckpointInterval),
+			Spread:             Duration(DefaultResyncSpread),
+		},
+		Health: HealthConfig{
+			StallTimeout: Duration(DefaultHealthStallTimeout),
+		},
+		EncryptionCheck: EncryptionCheckConfig{
+			Interval: Duration(DefaultEncryptionCheckInterval),
+		},
+		Randomness: RandomnessConfig{
+			Source: DefaultRandomSource,
+		},
+		Sharding: ShardingConfig{
+			Shards:   0,
+			Mode:     ShardingModeHash,
+			LabelKey: DefaultShardingLabelKey,
+		},
 	}
 }
 
@@ -191,6 +935,82 @@ func LoadConfig(path string) (*Config, error) {
 	if config.Rotation.MinInterval == 0 {
 		config.Rotation.MinInterval = Duration(DefaultRotationMinInterval)
 	}
+	if config.Rotation.TimestampFormat == "" {
+		config.Rotation.TimestampFormat = TimestampFormatRFC3339
+	}
+	// Apply defaults for certificates config
+	if config.Certificates.Duration == 0 {
+		config.Certificates.Duration = Duration(DefaultCertificateDuration)
+	}
+	if config.Certificates.RenewalFraction == 0 {
+		config.Certificates.RenewalFraction = DefaultCertificateRenewalFraction
+	}
+	// Apply defaults for tokens config
+	if config.Tokens.Duration == 0 {
+		config.Tokens.Duration = Duration(DefaultTokenDuration)
+	}
+	if config.Tokens.RenewalFraction == 0 {
+		config.Tokens.RenewalFraction = DefaultTokenRenewalFraction
+	}
+	// Apply defaults for OAuth clients config
+	if config.OAuthClients.ClientIDFormat == "" {
+		config.OAuthClients.ClientIDFormat = ClientIDFormatUUID
+	}
+	// Apply defaults for managed label config
+	if config.ManagedLabel.Key == "" {
+		config.ManagedLabel.Key = DefaultManagedLabelKey
+	}
+	if config.ManagedLabel.Value == "" {
+		config.ManagedLabel.Value = DefaultManagedLabelValue
+	}
+	// Apply defaults for replication config
+	if config.Replication.DriftPolicy == "" {
+		config.Replication.DriftPolicy = DriftPolicyOverwrite
+	}
+	// Apply defaults for metrics config
+	if config.Metrics.MaxNamespaces == 0 {
+		config.Metrics.MaxNamespaces = DefaultMetricsMaxNamespaces
+	}
+	if config.Metrics.RotationOverdueThreshold == 0 {
+		config.Metrics.RotationOverdueThreshold = Duration(DefaultRotationOverdueThreshold)
+	}
+	// Apply defaults for inventory config
+	if config.Inventory.Interval == 0 {
+		config.Inventory.Interval = Duration(DefaultInventoryInterval)
+	}
+	if config.Inventory.ConfigMapName == "" {
+		config.Inventory.ConfigMapName = DefaultInventoryConfigMapName
+	}
+	// Apply defaults for annotation hygiene config
+	if config.AnnotationHygiene.Interval == 0 {
+		config.AnnotationHygiene.Interval = Duration(DefaultAnnotationHygieneInterval)
+	}
+	// Apply defaults for differential resync config
+	if config.DifferentialResync.CheckpointInterval == 0 {
+		config.DifferentialResync.CheckpointInterval = Duration(DefaultResyncCheckpointInterval)
+	}
+	if config.DifferentialResync.Spread == 0 {
+		config.DifferentialResync.Spread = Duration(DefaultResyncSpread)
+	}
+	// Apply defaults for health config
+	if config.Health.StallTimeout == 0 {
+		config.Health.StallTimeout = Duration(DefaultHealthStallTimeout)
+	}
+	// Apply defaults for encryption check config
+	if config.EncryptionCheck.Interval == 0 {
+		config.EncryptionCheck.Interval = Duration(DefaultEncryptionCheckInterval)
+	}
+	// Apply defaults for randomness config
+	if config.Randomness.Source == "" {
+		config.Randomness.Source = DefaultRandomSource
+	}
+	// Apply defaults for sharding config
+	if config.Sharding.Mode == "" {
+		config.Sharding.Mode = ShardingModeHash
+	}
+	if config.Sharding.LabelKey == "" {
+		config.Sharding.LabelKey = DefaultShardingLabelKey
+	}
 
 	// Validate the configuration
 	if err := config.Validate(); err != nil {
@@ -215,15 +1035,23 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("default length must be positive, got %d", c.Defaults.Length)
 	}
 
-	// Validate that at least one charset option is enabled for string type
-	if !c.Defaults.String.Uppercase && !c.Defaults.String.Lowercase &&
-		!c.Defaults.String.Numbers && !c.Defaults.String.SpecialChars {
-		return fmt.Errorf("at least one charset option must be enabled (uppercase, lowercase, numbers, or specialChars)")
+	if err := c.Defaults.String.Validate(); err != nil {
+		return err
 	}
 
-	// Validate that if specialChars is enabled, allowedSpecialChars is not empty
-	if c.Defaults.String.SpecialChars && c.Defaults.String.AllowedSpecialChars == "" {
-		return fmt.Errorf("allowedSpecialChars must not be empty when specialChars is enabled")
+	// Validate type profiles
+	for i, profile := range c.Defaults.TypeProfiles {
+		if profile.SecretType == "" {
+			return fmt.Errorf("typeProfiles[%d]: secretType must not be empty", i)
+		}
+		if profile.Length < 0 {
+			return fmt.Errorf("typeProfiles[%d]: length must be non-negative, got %d", i, profile.Length)
+		}
+		if profile.String != nil {
+			if err := profile.String.Validate(); err != nil {
+				return fmt.Errorf("typeProfiles[%d]: %w", i, err)
+			}
+		}
 	}
 
 	// Validate rotation minInterval
@@ -231,6 +1059,324 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("rotation minInterval must be non-negative, got %s", c.Rotation.MinInterval.Duration())
 	}
 
+	// Validate rotation defaultInterval
+	if c.Rotation.DefaultInterval < 0 {
+		return fmt.Errorf("rotation defaultInterval must be non-negative, got %s", c.Rotation.DefaultInterval.Duration())
+	}
+	if c.Rotation.DefaultInterval > 0 && c.Rotation.DefaultInterval < c.Rotation.MinInterval {
+		return fmt.Errorf("rotation defaultInterval %s is below minInterval %s",
+			c.Rotation.DefaultInterval.Duration(), c.Rotation.MinInterval.Duration())
+	}
+
+	// Validate rotation clock skew tolerance and timestamp format
+	if c.Rotation.ClockSkewTolerance < 0 {
+		return fmt.Errorf("rotation clockSkewTolerance must be non-negative, got %s", c.Rotation.ClockSkewTolerance.Duration())
+	}
+	switch c.Rotation.TimestampFormat {
+	case "", TimestampFormatRFC3339, TimestampFormatUnix:
+		// valid formats (empty means rfc3339)
+	default:
+		return fmt.Errorf("invalid rotation timestampFormat: %s, must be 'rfc3339' or 'unix'", c.Rotation.TimestampFormat)
+	}
+
+	// Validate rotation limits
+	if c.Rotation.MaxConcurrent < 0 {
+		return fmt.Errorf("rotation maxConcurrent must be non-negative, got %d", c.Rotation.MaxConcurrent)
+	}
+	if c.Rotation.MaxConcurrentPerNamespace < 0 {
+		return fmt.Errorf("rotation maxConcurrentPerNamespace must be non-negative, got %d", c.Rotation.MaxConcurrentPerNamespace)
+	}
+	if c.Rotation.CatchUpWindow < 0 {
+		return fmt.Errorf("rotation catchUpWindow must be non-negative, got %s", c.Rotation.CatchUpWindow.Duration())
+	}
+	if c.Rotation.BackupRetention < 0 {
+		return fmt.Errorf("rotation backupRetention must be non-negative, got %s", c.Rotation.BackupRetention.Duration())
+	}
+
+	// Validate quotas
+	if c.Quota.MaxSecretsPerNamespace < 0 {
+		return fmt.Errorf("quota maxSecretsPerNamespace must be non-negative, got %d", c.Quota.MaxSecretsPerNamespace)
+	}
+	if c.Quota.MaxFieldsPerNamespace < 0 {
+		return fmt.Errorf("quota maxFieldsPerNamespace must be non-negative, got %d", c.Quota.MaxFieldsPerNamespace)
+	}
+
+	// Validate TTL warningBefore
+	if c.TTL.WarningBefore.Duration() < 0 {
+		return fmt.Errorf("ttl warningBefore must be non-negative, got %s", c.TTL.WarningBefore.Duration())
+	}
+
+	// Validate certificates config
+	if c.Certificates.Duration.Duration() < 0 {
+		return fmt.Errorf("certificates duration must be non-negative, got %s", c.Certificates.Duration.Duration())
+	}
+	if c.Certificates.RenewalFraction < 0 || c.Certificates.RenewalFraction > 1 {
+		return fmt.Errorf("certificates renewalFraction must be between 0 and 1, got %v", c.Certificates.RenewalFraction)
+	}
+
+	// Validate tokens config
+	if c.Tokens.Duration.Duration() < 0 {
+		return fmt.Errorf("tokens duration must be non-negative, got %s", c.Tokens.Duration.Duration())
+	}
+	if c.Tokens.RenewalFraction < 0 || c.Tokens.RenewalFraction > 1 {
+		return fmt.Errorf("tokens renewalFraction must be between 0 and 1, got %v", c.Tokens.RenewalFraction)
+	}
+
+	// Validate OAuth clients config
+	if c.OAuthClients.ClientIDFormat != "" && !slices.Contains(ClientIDFormats, c.OAuthClients.ClientIDFormat) {
+		return fmt.Errorf("oauthClients clientIDFormat must be one of %v, got %q",
+			ClientIDFormats, c.OAuthClients.ClientIDFormat)
+	}
+
+	// Validate replication denylist patterns
+	for _, pattern := range c.Replication.DeniedNamespaces {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid replication deniedNamespaces pattern %q: %w", pattern, err)
+		}
+	}
+
+	// Validate replication drift policy
+	switch c.Replication.DriftPolicy {
+	case "", DriftPolicyOverwrite, DriftPolicyWarn:
+		// valid policies (empty means overwrite)
+	default:
+		return fmt.Errorf("invalid replication driftPolicy: %s, must be 'overwrite' or 'warn'", c.Replication.DriftPolicy)
+	}
+
+	// Validate replication boundaries
+	for i, boundary := range c.Replication.Boundaries {
+		for _, selector := range []string{boundary.From, boundary.To} {
+			if selector == "" {
+				return fmt.Errorf("replication boundary %d (%s) must set both from and to", i, boundary.Name)
+			}
+			if _, err := labels.Parse(selector); err != nil {
+				return fmt.Errorf("invalid replication boundary %d (%s) selector %q: %w", i, boundary.Name, selector, err)
+			}
+		}
+	}
+
+	// Validate labels and type of pushed Secrets
+	for key, value := range c.Replication.PushedLabels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid replication pushedLabels key %q: %s", key, errs[0])
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("invalid replication pushedLabels value %q: %s", value, errs[0])
+		}
+	}
+	for _, key := range c.Replication.OwnerLabels.Keys() {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid replication ownerLabels key %q: %s", key, errs[0])
+		}
+	}
+	if c.IsSecretTypeIgnored(c.Replication.PushedType) {
+		return fmt.Errorf("replication pushedType %q is an ignored Secret type", c.Replication.PushedType)
+	}
+	if c.Replication.SourceFanOutRate < 0 {
+		return fmt.Errorf("replication sourceFanOutRate must be non-negative, got %d", c.Replication.SourceFanOutRate)
+	}
+	if c.Replication.MaxBytesPerSourceNamespace < 0 {
+		return fmt.Errorf("replication maxBytesPerSourceNamespace must be non-negative, got %d",
+			c.Replication.MaxBytesPerSourceNamespace)
+	}
+
+	// Validate event limits
+	if c.Events.MaxPerSecretPerHour < 0 {
+		return fmt.Errorf("events maxPerSecretPerHour must be non-negative, got %d", c.Events.MaxPerSecretPerHour)
+	}
+	if c.Events.MaxPerHour < 0 {
+		return fmt.Errorf("events maxPerHour must be non-negative, got %d", c.Events.MaxPerHour)
+	}
+
+	// Validate metrics config
+	if c.Metrics.MaxNamespaces < 0 {
+		return fmt.Errorf("metrics maxNamespaces must be non-negative, got %d", c.Metrics.MaxNamespaces)
+	}
+	if c.Metrics.RotationOverdueThreshold < 0 {
+		return fmt.Errorf("metrics rotationOverdueThreshold must be non-negative, got %s",
+			c.Metrics.RotationOverdueThreshold.Duration())
+	}
+	if c.Metrics.WorkqueueDepthThreshold < 0 {
+		return fmt.Errorf("metrics workqueueDepthThreshold must be non-negative, got %d", c.Metrics.WorkqueueDepthThreshold)
+	}
+	if c.Metrics.WorkqueueAgeThreshold < 0 {
+		return fmt.Errorf("metrics workqueueAgeThreshold must be non-negative, got %s",
+			c.Metrics.WorkqueueAgeThreshold.Duration())
+	}
+
+	// Validate inventory config
+	if c.Inventory.Interval < 0 {
+		return fmt.Errorf("inventory interval must be non-negative, got %v", time.Duration(c.Inventory.Interval))
+	}
+	if c.Inventory.ConfigMapName != "" {
+		if errs := validation.IsDNS1123Subdomain(c.Inventory.ConfigMapName); len(errs) > 0 {
+			return fmt.Errorf("invalid inventory configMapName %q: %s", c.Inventory.ConfigMapName, errs[0])
+		}
+	}
+
+	// Validate annotation hygiene config
+	if c.AnnotationHygiene.Interval < 0 {
+		return fmt.Errorf("annotationHygiene interval must be non-negative, got %v", time.Duration(c.AnnotationHygiene.Interval))
+	}
+
+	// Validate differential resync config
+	if c.DifferentialResync.CheckpointInterval < 0 {
+		return fmt.Errorf("differentialResync checkpointInterval must be non-negative, got %v",
+			time.Duration(c.DifferentialResync.CheckpointInterval))
+	}
+	if c.DifferentialResync.Spread < 0 {
+		return fmt.Errorf("differentialResync spread must be non-negative, got %v", time.Duration(c.DifferentialResync.Spread))
+	}
+
+	// Validate legacy annotation prefix
+	if c.LegacyAnnotationPrefix != "" {
+		domain, ok := strings.CutSuffix(c.LegacyAnnotationPrefix, "/")
+		if !ok {
+			return fmt.Errorf("legacyAnnotationPrefix %q must end with '/'", c.LegacyAnnotationPrefix)
+		}
+		if errs := validation.IsDNS1123Subdomain(domain); len(errs) > 0 {
+			return fmt.Errorf("invalid legacyAnnotationPrefix %q: %s", c.LegacyAnnotationPrefix, errs[0])
+		}
+	}
+
+	// Validate health config
+	if c.Health.StallTimeout < 0 {
+		return fmt.Errorf("health stallTimeout must be non-negative, got %v", time.Duration(c.Health.StallTimeout))
+	}
+
+	// Validate encryption check config
+	if c.EncryptionCheck.Interval < 0 {
+		return fmt.Errorf("encryptionCheck interval must be non-negative, got %s", c.EncryptionCheck.Interval.Duration())
+	}
+
+	// Validate cache config
+	if c.Cache.ResyncPeriod < 0 {
+		return fmt.Errorf("cache resyncPeriod must be non-negative, got %s", c.Cache.ResyncPeriod.Duration())
+	}
+	for name, period := range c.Cache.ControllerResyncPeriods {
+		if !slices.Contains(ResyncControllers, name) {
+			return fmt.Errorf("invalid cache controllerResyncPeriods controller: %s, must be one of %s",
+				name, strings.Join(ResyncControllers, ", "))
+		}
+		if period < 0 {
+			return fmt.Errorf("cache controllerResyncPeriods %s must be non-negative, got %s", name, period.Duration())
+		}
+	}
+
+	// Validate policies
+	for i, rule := range c.Policies {
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("invalid policy %d (%s): %w", i, rule.Name, err)
+		}
+	}
+
+	// Validate sharding config
+	if c.Sharding.Shards < 0 {
+		return fmt.Errorf("sharding shards must be non-negative, got %d", c.Sharding.Shards)
+	}
+	switch c.Sharding.Mode {
+	case "", ShardingModeHash:
+		// valid modes (empty means hash)
+	case ShardingModeLabel:
+		if errs := validation.IsQualifiedName(c.Sharding.LabelKey); len(errs) > 0 {
+			return fmt.Errorf("invalid sharding labelKey %q: %s", c.Sharding.LabelKey, errs[0])
+		}
+	default:
+		return fmt.Errorf("invalid sharding mode: %s, must be 'hash' or 'label'", c.Sharding.Mode)
+	}
+
+	// Validate bootstrap Secrets
+	names := make(map[string]bool)
+	for i, secret := range c.Bootstrap {
+		if err := secret.validate(); err != nil {
+			return fmt.Errorf("invalid bootstrap secret %d (%s): %w", i, secret.Name, err)
+		}
+		if names[secret.Name] {
+			return fmt.Errorf("duplicate bootstrap secret %s", secret.Name)
+		}
+		names[secret.Name] = true
+	}
+
+	// Validate managed label
+	if c.ManagedLabel.Enabled {
+		if errs := validation.IsQualifiedName(c.ManagedLabel.Key); len(errs) > 0 {
+			return fmt.Errorf("invalid managedLabel key %q: %s", c.ManagedLabel.Key, errs[0])
+		}
+		if errs := validation.IsValidLabelValue(c.ManagedLabel.Value); len(errs) > 0 {
+			return fmt.Errorf("invalid managedLabel value %q: %s", c.ManagedLabel.Value, errs[0])
+		}
+	}
+
+	return nil
+}
+
+// validate checks the patterns, selectors and features of a policy rule
+func (r PolicyRule) validate() error {
+	for _, pattern := range append(slices.Clone(r.Namespaces), r.ExcludeNamespaces...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid namespace pattern %q: %w", pattern, err)
+		}
+	}
+	if _, err := labels.Parse(r.NamespaceSelector); err != nil {
+		return fmt.Errorf("invalid namespaceSelector %q: %w", r.NamespaceSelector, err)
+	}
+	for _, feature := range r.DeniedFeatures {
+		if !slices.Contains(PolicyFeatures, feature) {
+			return fmt.Errorf("unknown feature %q, must be one of %s", feature, strings.Join(PolicyFeatures, ", "))
+		}
+	}
+	if r.MinRotationInterval < 0 {
+		return fmt.Errorf("minRotationInterval must be non-negative, got %s", r.MinRotationInterval.Duration())
+	}
+	if r.ExemptLabel != "" {
+		key, value, _ := strings.Cut(r.ExemptLabel, "=")
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid exemptLabel key %q: %s", key, errs[0])
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("invalid exemptLabel value %q: %s", value, errs[0])
+		}
+	}
+	return nil
+}
+
+// validate checks the name, metadata, patterns and selector of a bootstrap Secret
+func (s BootstrapSecret) validate() error {
+	if errs := validation.IsDNS1123Subdomain(s.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid name %q: %s", s.Name, errs[0])
+	}
+	for key := range s.Annotations {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid annotation key %q: %s", key, errs[0])
+		}
+	}
+	for key, value := range s.Labels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid label key %q: %s", key, errs[0])
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("invalid label value %q: %s", value, errs[0])
+		}
+	}
+	for _, pattern := range s.Namespaces {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid namespace pattern %q: %w", pattern, err)
+		}
+	}
+	if _, err := labels.Parse(s.NamespaceSelector); err != nil {
+		return fmt.Errorf("invalid namespaceSelector %q: %w", s.NamespaceSelector, err)
+	}
+	return nil
+}
+
+// ValidateSpecialChars checks that special characters are printable ASCII. Generated strings
+// pick single bytes of the charset, so multi-byte characters would produce invalid UTF-8.
+func ValidateSpecialChars(chars string) error {
+	for _, r := range chars {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			return fmt.Errorf("allowedSpecialChars must only contain printable ASCII characters, got %q", r)
+		}
+	}
 	return nil
 }
 
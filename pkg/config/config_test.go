@@ -17,6 +17,7 @@ limitations under the License.
 package config
 
 import (
+	"maps"
 	"os"
 	"path/filepath"
 	"strings"
@@ -274,6 +275,21 @@ func TestConfigValidate(t *testing.T) {
 			wantError: true,
 			errorMsg:  "allowedSpecialChars must not be empty",
 		},
+		{
+			name: "non-ASCII allowedSpecialChars",
+			config: &Config{
+				Defaults: DefaultsConfig{
+					Type:   "string",
+					Length: 32,
+					String: StringOptions{
+						SpecialChars:        true,
+						AllowedSpecialChars: "!§",
+					},
+				},
+			},
+			wantError: true,
+			errorMsg:  "printable ASCII",
+		},
 		{
 			name: "valid bytes type",
 			config: &Config{
@@ -973,3 +989,1167 @@ rotation:
 		t.Errorf("expected rotation minInterval %v, got %v", DefaultRotationMinInterval, cfg.Rotation.MinInterval.Duration())
 	}
 }
+
+func TestLoadConfigWithManagedLabel(t *testing.T) {
+	tests := []struct {
+		name          string
+		configContent string
+		expectError   bool
+		expectEnabled bool
+		expectKey     string
+		expectValue   string
+	}{
+		{
+			name: "managed label section omitted - should use defaults",
+			configContent: `
+defaults:
+  type: string
+`,
+			expectEnabled: false,
+			expectKey:     DefaultManagedLabelKey,
+			expectValue:   DefaultManagedLabelValue,
+		},
+		{
+			name: "managed label enabled with default key and value",
+			configContent: `
+managedLabel:
+  enabled: true
+`,
+			expectEnabled: true,
+			expectKey:     DefaultManagedLabelKey,
+			expectValue:   DefaultManagedLabelValue,
+		},
+		{
+			name: "managed label enabled with custom key and value",
+			configContent: `
+managedLabel:
+  enabled: true
+  key: example.com/secrets
+  value: enabled
+`,
+			expectEnabled: true,
+			expectKey:     "example.com/secrets",
+			expectValue:   "enabled",
+		},
+		{
+			name: "invalid managed label key",
+			configContent: `
+managedLabel:
+  enabled: true
+  key: "not a valid key"
+`,
+			expectError: true,
+		},
+		{
+			name: "invalid managed label value",
+			configContent: `
+managedLabel:
+  enabled: true
+  value: "not valid!"
+`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			configPath := filepath.Join(tmpDir, "config.yaml")
+
+			if err := os.WriteFile(configPath, []byte(tt.configContent), 0644); err != nil {
+				t.Fatalf("failed to write config file: %v", err)
+			}
+
+			cfg, err := LoadConfig(configPath)
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if cfg.ManagedLabel.Enabled != tt.expectEnabled {
+				t.Errorf("expected managedLabel.enabled %v, got %v", tt.expectEnabled, cfg.ManagedLabel.Enabled)
+			}
+			if cfg.ManagedLabel.Key != tt.expectKey {
+				t.Errorf("expected managedLabel.key %q, got %q", tt.expectKey, cfg.ManagedLabel.Key)
+			}
+			if cfg.ManagedLabel.Value != tt.expectValue {
+				t.Errorf("expected managedLabel.value %q, got %q", tt.expectValue, cfg.ManagedLabel.Value)
+			}
+		})
+	}
+}
+
+func TestManagedLabelConfigMatches(t *testing.T) {
+	enabled := ManagedLabelConfig{Enabled: true, Key: DefaultManagedLabelKey, Value: DefaultManagedLabelValue}
+	disabled := ManagedLabelConfig{Enabled: false, Key: DefaultManagedLabelKey, Value: DefaultManagedLabelValue}
+
+	tests := []struct {
+		name     string
+		cfg      ManagedLabelConfig
+		labels   map[string]string
+		expected bool
+	}{
+		{
+			name:     "disabled matches nil labels",
+			cfg:      disabled,
+			labels:   nil,
+			expected: true,
+		},
+		{
+			name:     "enabled with matching label",
+			cfg:      enabled,
+			labels:   map[string]string{DefaultManagedLabelKey: "true"},
+			expected: true,
+		},
+		{
+			name:     "enabled with wrong value",
+			cfg:      enabled,
+			labels:   map[string]string{DefaultManagedLabelKey: "false"},
+			expected: false,
+		},
+		{
+			name:     "enabled without label",
+			cfg:      enabled,
+			labels:   map[string]string{"app": "test"},
+			expected: false,
+		},
+		{
+			name:     "enabled with nil labels",
+			cfg:      enabled,
+			labels:   nil,
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.Matches(tt.labels); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestManagedLabelConfigSelector(t *testing.T) {
+	disabled := ManagedLabelConfig{}
+	if !disabled.Selector().Empty() {
+		t.Error("expected empty selector when managed label is disabled")
+	}
+
+	enabled := ManagedLabelConfig{Enabled: true, Key: DefaultManagedLabelKey, Value: DefaultManagedLabelValue}
+	expected := DefaultManagedLabelKey + "=" + DefaultManagedLabelValue
+	if got := enabled.Selector().String(); got != expected {
+		t.Errorf("expected selector %q, got %q", expected, got)
+	}
+}
+
+func TestLoadConfigTTL(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+ttl:
+  warningBefore: 2d
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.TTL.WarningBefore.Duration() != 48*time.Hour {
+		t.Errorf("expected ttl warningBefore %v, got %v", 48*time.Hour, cfg.TTL.WarningBefore.Duration())
+	}
+
+	// 0 disables the warning and is not replaced by the default
+	if err := os.WriteFile(configPath, []byte("ttl:\n  warningBefore: 0\n"), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	if cfg, err = LoadConfig(configPath); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.TTL.WarningBefore != 0 {
+		t.Errorf("expected disabled ttl warningBefore, got %v", cfg.TTL.WarningBefore.Duration())
+	}
+
+	// Omitted section falls back to default
+	cfg, err = LoadConfig(filepath.Join(tmpDir, "missing.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.TTL.WarningBefore.Duration() != DefaultTTLWarningBefore {
+		t.Errorf("expected default ttl warningBefore %v, got %v", DefaultTTLWarningBefore, cfg.TTL.WarningBefore.Duration())
+	}
+}
+
+func TestConfigValidateNegativeTTLWarningBefore(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.TTL.WarningBefore = Duration(-time.Minute)
+
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative ttl warningBefore")
+	}
+}
+
+func TestLoadConfigCertificates(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+certificates:
+  duration: 30d
+  renewalFraction: 0.5
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Certificates.Duration.Duration() != 30*24*time.Hour {
+		t.Errorf("expected certificates duration %v, got %v", 30*24*time.Hour, cfg.Certificates.Duration.Duration())
+	}
+	if cfg.Certificates.RenewalFraction != 0.5 {
+		t.Errorf("expected certificates renewalFraction 0.5, got %v", cfg.Certificates.RenewalFraction)
+	}
+}
+
+func TestConfigValidateCertificates(t *testing.T) {
+	tests := []struct {
+		name      string
+		duration  time.Duration
+		fraction  float64
+		wantError bool
+	}{
+		{name: "defaults", duration: DefaultCertificateDuration, fraction: DefaultCertificateRenewalFraction},
+		{name: "fraction of one", duration: time.Hour, fraction: 1},
+		{name: "negative duration", duration: -time.Hour, fraction: 0.5, wantError: true},
+		{name: "fraction above one", duration: time.Hour, fraction: 1.5, wantError: true},
+		{name: "negative fraction", duration: time.Hour, fraction: -0.1, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultConfig()
+			cfg.Certificates.Duration = Duration(tt.duration)
+			cfg.Certificates.RenewalFraction = tt.fraction
+
+			err := cfg.Validate()
+			if tt.wantError && err == nil {
+				t.Error("expected error, got nil")
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfigTokens(t *testing.T) {
+	cfg := NewDefaultConfig()
+	if cfg.Tokens.Duration.Duration() != DefaultTokenDuration || cfg.Tokens.RenewalFraction != DefaultTokenRenewalFraction {
+		t.Errorf("unexpected token defaults %+v", cfg.Tokens)
+	}
+
+	cfg.Tokens.Duration = Duration(-time.Hour)
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative tokens duration")
+	}
+	cfg = NewDefaultConfig()
+	cfg.Tokens.RenewalFraction = 1.5
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for tokens renewalFraction above one")
+	}
+}
+
+func TestConfigOAuthClients(t *testing.T) {
+	cfg := NewDefaultConfig()
+	if cfg.OAuthClients.ClientIDFormat != ClientIDFormatUUID {
+		t.Errorf("expected default client ID format %q, got %q", ClientIDFormatUUID, cfg.OAuthClients.ClientIDFormat)
+	}
+
+	cfg.OAuthClients.ClientIDFormat = "base32"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown client ID format")
+	}
+}
+
+func TestConfigLegacyAnnotationPrefix(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.LegacyAnnotationPrefix = "secret-operator.example.com/"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, prefix := range []string{"secret-operator.example.com", "Secret_Operator/"} {
+		cfg.LegacyAnnotationPrefix = prefix
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected error for legacyAnnotationPrefix %q", prefix)
+		}
+	}
+}
+
+func TestConfigTypeProfiles(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.Defaults.TypeProfiles = builtinTypeProfiles
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected built-in profiles to be valid: %v", err)
+	}
+	cfg.Defaults.TypeProfiles = nil
+
+	// The built-in profiles are opt-in
+	length, opts := cfg.Defaults.FieldDefaults("kubernetes.io/basic-auth", "password")
+	if length != cfg.Defaults.Length || opts != cfg.Defaults.String {
+		t.Errorf("expected defaults without built-in profiles, got length %d and %+v", length, opts)
+	}
+
+	cfg.Defaults.BuiltinTypeProfiles = true
+	length, opts = cfg.Defaults.FieldDefaults("kubernetes.io/basic-auth", "password")
+	if length != 32 || !opts.SpecialChars {
+		t.Errorf("expected basic-auth password profile, got length %d and %+v", length, opts)
+	}
+	length, opts = cfg.Defaults.FieldDefaults("kubernetes.io/basic-auth", "username")
+	if length != cfg.Defaults.Length || opts != cfg.Defaults.String {
+		t.Errorf("expected defaults for fields not listed in the profile, got length %d and %+v", length, opts)
+	}
+	if length, _ = cfg.Defaults.FieldDefaults("kubernetes.io/dockerconfigjson", "password"); length != 48 {
+		t.Errorf("expected dockerconfigjson length 48, got %d", length)
+	}
+
+	// A profile without length or charset keeps the defaults
+	cfg.Defaults.TypeProfiles = []TypeProfile{{SecretType: "Opaque"}}
+	length, opts = cfg.Defaults.FieldDefaults("Opaque", "password")
+	if length != cfg.Defaults.Length || opts != cfg.Defaults.String {
+		t.Errorf("expected defaults for empty profile, got length %d and %+v", length, opts)
+	}
+
+	cfg.Defaults.TypeProfiles = []TypeProfile{{Length: 16}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for profile without secretType")
+	}
+	cfg.Defaults.TypeProfiles = []TypeProfile{{SecretType: "Opaque", Length: -1}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative profile length")
+	}
+	cfg.Defaults.TypeProfiles = []TypeProfile{{SecretType: "Opaque", String: &StringOptions{}}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for profile without charset options")
+	}
+}
+
+func TestLoadConfigTypeProfiles(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `
+defaults:
+  builtinTypeProfiles: true
+  typeProfiles:
+    - secretType: kubernetes.io/dockerconfigjson
+      length: 64
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if length, _ := cfg.Defaults.FieldDefaults("kubernetes.io/dockerconfigjson", "password"); length != 64 {
+		t.Errorf("expected the configured profile to precede the built-in one, got length %d", length)
+	}
+	if length, _ := cfg.Defaults.FieldDefaults("kubernetes.io/basic-auth", "password"); length != 32 {
+		t.Errorf("expected the built-in basic-auth profile, got length %d", length)
+	}
+}
+
+func TestConfigValidateEventLimits(t *testing.T) {
+	cfg := NewDefaultConfig()
+	if cfg.Events.MaxPerSecretPerHour != 0 || cfg.Events.MaxPerHour != 0 {
+		t.Errorf("expected event limits to be disabled by default, got %+v", cfg.Events)
+	}
+
+	cfg.Events.MaxPerSecretPerHour = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative events maxPerSecretPerHour")
+	}
+
+	cfg = NewDefaultConfig()
+	cfg.Events.MaxPerHour = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative events maxPerHour")
+	}
+
+	cfg = NewDefaultConfig()
+	cfg.Events.MaxPerSecretPerHour = 20
+	cfg.Events.MaxPerHour = 1000
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestLoadConfigCacheResyncPeriods(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+cache:
+  resyncPeriod: 1h
+  controllerResyncPeriods:
+    secret-replicator: 10m
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Cache.ResyncPeriod.Duration() != time.Hour {
+		t.Errorf("expected resyncPeriod 1h, got %v", cfg.Cache.ResyncPeriod.Duration())
+	}
+	if period := cfg.Cache.ResyncPeriodFor(ControllerSecretReplicator); period != 10*time.Minute {
+		t.Errorf("expected secret-replicator resync period 10m, got %v", period)
+	}
+	if period := cfg.Cache.ResyncPeriodFor(ControllerSecretGenerator); period != 0 {
+		t.Errorf("expected no secret-generator resync period, got %v", period)
+	}
+}
+
+func TestConfigValidateCache(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.Cache.ResyncPeriod = Duration(-time.Hour)
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative cache resyncPeriod")
+	}
+
+	cfg = NewDefaultConfig()
+	cfg.Cache.ControllerResyncPeriods = map[string]Duration{"unknown": Duration(time.Hour)}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown controller in cache controllerResyncPeriods")
+	}
+
+	cfg = NewDefaultConfig()
+	cfg.Cache.ControllerResyncPeriods = map[string]Duration{ControllerSecretGenerator: Duration(-time.Hour)}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative controller resync period")
+	}
+}
+
+func TestLoadConfigEncryptionCheck(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+encryptionCheck:
+  enabled: true
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !cfg.EncryptionCheck.Enabled {
+		t.Error("expected encryption check to be enabled")
+	}
+	if cfg.EncryptionCheck.Interval.Duration() != DefaultEncryptionCheckInterval {
+		t.Errorf("expected default interval %v, got %v", DefaultEncryptionCheckInterval, cfg.EncryptionCheck.Interval.Duration())
+	}
+
+	cfg = NewDefaultConfig()
+	if cfg.EncryptionCheck.Enabled {
+		t.Error("expected encryption check to be disabled by default")
+	}
+	cfg.EncryptionCheck.Interval = Duration(-time.Hour)
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative encryptionCheck interval")
+	}
+}
+
+func TestConfigValidateRotationLimits(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.Rotation.MaxConcurrent = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative rotation maxConcurrent")
+	}
+
+	cfg = NewDefaultConfig()
+	cfg.Rotation.MaxConcurrentPerNamespace = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative rotation maxConcurrentPerNamespace")
+	}
+
+	cfg = NewDefaultConfig()
+	cfg.Rotation.CatchUpWindow = Duration(-time.Hour)
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative rotation catchUpWindow")
+	}
+
+	cfg = NewDefaultConfig()
+	cfg.Rotation.BackupRetention = Duration(-time.Hour)
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative rotation backupRetention")
+	}
+
+	cfg = NewDefaultConfig()
+	cfg.Rotation.MaxConcurrent = 10
+	cfg.Rotation.MaxConcurrentPerNamespace = 2
+	cfg.Rotation.CatchUpWindow = Duration(time.Hour)
+	cfg.Rotation.BackupRetention = Duration(7 * 24 * time.Hour)
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestConfigValidateQuota(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.Quota.MaxSecretsPerNamespace = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative quota maxSecretsPerNamespace")
+	}
+
+	cfg = NewDefaultConfig()
+	cfg.Quota.MaxFieldsPerNamespace = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative quota maxFieldsPerNamespace")
+	}
+
+	cfg = NewDefaultConfig()
+	cfg.Quota.MaxSecretsPerNamespace = 100
+	cfg.Quota.MaxFieldsPerNamespace = 500
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestLoadConfigReplicationDeniedNamespaces(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+replication:
+  deniedNamespaces:
+    - kube-system
+    - "openshift-*"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"kube-system", "openshift-*"}
+	if len(cfg.Replication.DeniedNamespaces) != len(expected) {
+		t.Fatalf("expected %d denied namespaces, got %v", len(expected), cfg.Replication.DeniedNamespaces)
+	}
+	for i, pattern := range expected {
+		if cfg.Replication.DeniedNamespaces[i] != pattern {
+			t.Errorf("expected denied namespace %q at index %d, got %q", pattern, i, cfg.Replication.DeniedNamespaces[i])
+		}
+	}
+}
+
+func TestConfigValidateReplicationDeniedNamespaces(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.Replication.DeniedNamespaces = []string{"kube-[system"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for invalid deniedNamespaces pattern")
+	}
+}
+
+func TestLoadConfigReplicationDriftPolicy(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{name: "default", content: "replication: {}\n", expected: DriftPolicyOverwrite},
+		{name: "warn", content: "replication:\n  driftPolicy: warn\n", expected: DriftPolicyWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(tmpDir, tt.name+".yaml")
+			if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to write config file: %v", err)
+			}
+
+			cfg, err := LoadConfig(configPath)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.Replication.DriftPolicy != tt.expected {
+				t.Errorf("expected drift policy %q, got %q", tt.expected, cfg.Replication.DriftPolicy)
+			}
+		})
+	}
+}
+
+func TestConfigValidateReplicationDriftPolicy(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.Replication.DriftPolicy = "ignore"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for invalid driftPolicy")
+	}
+}
+
+func TestIgnoredSecretTypes(t *testing.T) {
+	cfg := NewDefaultConfig()
+	if !cfg.IsSecretTypeIgnored("helm.sh/release.v1") {
+		t.Error("expected Helm release Secrets to be ignored by default")
+	}
+	if !cfg.IsSecretTypeIgnored("kubernetes.io/service-account-token") {
+		t.Error("expected service account token Secrets to be ignored by default")
+	}
+	if cfg.IsSecretTypeIgnored("Opaque") {
+		t.Error("expected Opaque Secrets not to be ignored")
+	}
+
+	tmpDir := t.TempDir()
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{name: "default", content: "defaults: {}\n", expected: DefaultIgnoredSecretTypes},
+		{name: "override", content: "ignoredSecretTypes:\n  - example.com/custom\n", expected: []string{"example.com/custom"}},
+		{name: "empty", content: "ignoredSecretTypes: []\n", expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(tmpDir, tt.name+".yaml")
+			if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to write config file: %v", err)
+			}
+
+			cfg, err := LoadConfig(configPath)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(cfg.IgnoredSecretTypes, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("expected ignored types %v, got %v", tt.expected, cfg.IgnoredSecretTypes)
+			}
+		})
+	}
+}
+
+func TestLoadConfigMetrics(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+metrics:
+  perNamespace: true
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.Metrics.PerNamespace {
+		t.Error("expected perNamespace to be enabled")
+	}
+	if cfg.Metrics.MaxNamespaces != DefaultMetricsMaxNamespaces {
+		t.Errorf("expected default maxNamespaces %d, got %d", DefaultMetricsMaxNamespaces, cfg.Metrics.MaxNamespaces)
+	}
+	if cfg.Metrics.RotationOverdueThreshold.Duration() != DefaultRotationOverdueThreshold {
+		t.Errorf("expected default rotationOverdueThreshold %s, got %s",
+			DefaultRotationOverdueThreshold, cfg.Metrics.RotationOverdueThreshold.Duration())
+	}
+	if cfg.Metrics.WorkqueueDepthThreshold != DefaultWorkqueueDepthThreshold ||
+		cfg.Metrics.WorkqueueAgeThreshold.Duration() != DefaultWorkqueueAgeThreshold {
+		t.Errorf("expected default workqueue thresholds, got %d and %s",
+			cfg.Metrics.WorkqueueDepthThreshold, cfg.Metrics.WorkqueueAgeThreshold.Duration())
+	}
+
+	cfg.Metrics.MaxNamespaces = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative maxNamespaces")
+	}
+
+	cfg.Metrics.MaxNamespaces = DefaultMetricsMaxNamespaces
+	cfg.Metrics.RotationOverdueThreshold = Duration(-time.Minute)
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative rotationOverdueThreshold")
+	}
+
+	cfg.Metrics.RotationOverdueThreshold = Duration(DefaultRotationOverdueThreshold)
+	cfg.Metrics.WorkqueueDepthThreshold = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative workqueueDepthThreshold")
+	}
+
+	cfg.Metrics.WorkqueueDepthThreshold = DefaultWorkqueueDepthThreshold
+	cfg.Metrics.WorkqueueAgeThreshold = Duration(-time.Minute)
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative workqueueAgeThreshold")
+	}
+
+	// 0 disables the backpressure warnings and is not replaced by the defaults
+	configContent = `
+metrics:
+  workqueueDepthThreshold: 0
+  workqueueAgeThreshold: 0
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	if cfg, err = LoadConfig(configPath); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Metrics.WorkqueueDepthThreshold != 0 || cfg.Metrics.WorkqueueAgeThreshold != 0 {
+		t.Errorf("expected disabled workqueue thresholds, got %d and %s",
+			cfg.Metrics.WorkqueueDepthThreshold, cfg.Metrics.WorkqueueAgeThreshold.Duration())
+	}
+}
+
+func TestLoadConfigInventory(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+inventory:
+  enabled: true
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.Inventory.Enabled {
+		t.Error("expected inventory to be enabled")
+	}
+	if time.Duration(cfg.Inventory.Interval) != DefaultInventoryInterval {
+		t.Errorf("expected default interval %v, got %v", DefaultInventoryInterval, time.Duration(cfg.Inventory.Interval))
+	}
+	if cfg.Inventory.ConfigMapName != DefaultInventoryConfigMapName {
+		t.Errorf("expected default ConfigMap name %q, got %q", DefaultInventoryConfigMapName, cfg.Inventory.ConfigMapName)
+	}
+
+	cfg.Inventory.ConfigMapName = "Invalid_Name"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for invalid ConfigMap name")
+	}
+}
+
+func TestLoadConfigAnnotationHygiene(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+annotationHygiene:
+  enabled: true
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.AnnotationHygiene.Enabled {
+		t.Error("expected annotation hygiene to be enabled")
+	}
+	if time.Duration(cfg.AnnotationHygiene.Interval) != DefaultAnnotationHygieneInterval {
+		t.Errorf("expected default interval %v, got %v", DefaultAnnotationHygieneInterval, time.Duration(cfg.AnnotationHygiene.Interval))
+	}
+
+	cfg.AnnotationHygiene.Interval = Duration(-time.Minute)
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative interval")
+	}
+}
+
+func TestLoadConfigDifferentialResync(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+differentialResync:
+  enabled: true
+  spread: 30m
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.DifferentialResync.Enabled {
+		t.Error("expected differential resync to be enabled")
+	}
+	if time.Duration(cfg.DifferentialResync.CheckpointInterval) != DefaultResyncCheckpointInterval {
+		t.Errorf("expected default checkpoint interval %v, got %v",
+			DefaultResyncCheckpointInterval, time.Duration(cfg.DifferentialResync.CheckpointInterval))
+	}
+	if time.Duration(cfg.DifferentialResync.Spread) != 30*time.Minute {
+		t.Errorf("expected spread 30m, got %v", time.Duration(cfg.DifferentialResync.Spread))
+	}
+
+	cfg.DifferentialResync.Spread = Duration(-time.Minute)
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative spread")
+	}
+}
+
+func TestLoadConfigRandomness(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	if err := os.WriteFile(configPath, []byte("randomness: {}\n"), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Randomness.Source != DefaultRandomSource {
+		t.Errorf("expected default random source %q, got %q", DefaultRandomSource, cfg.Randomness.Source)
+	}
+
+	if err := os.WriteFile(configPath, []byte("randomness:\n  source: pkcs11\n"), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	cfg, err = LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Randomness.Source != "pkcs11" {
+		t.Errorf("expected random source pkcs11, got %q", cfg.Randomness.Source)
+	}
+}
+
+func TestLoadConfigPolicies(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+policies:
+  - name: push-from-infra-only
+    excludeNamespaces: ["infra-*"]
+    deniedFeatures: [replicate-to]
+  - name: fast-rotation-requires-label
+    minRotationInterval: 24h
+    exemptLabel: iso.gtrfc.com/fast-rotation=approved
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Policies) != 2 {
+		t.Fatalf("expected 2 policies, got %d", len(cfg.Policies))
+	}
+	if cfg.Policies[0].DeniedFeatures[0] != PolicyFeatureReplicateTo {
+		t.Errorf("expected denied feature %q, got %v", PolicyFeatureReplicateTo, cfg.Policies[0].DeniedFeatures)
+	}
+	if cfg.Policies[1].MinRotationInterval.Duration() != 24*time.Hour {
+		t.Errorf("expected minRotationInterval 24h, got %s", cfg.Policies[1].MinRotationInterval.Duration())
+	}
+}
+
+func TestConfigValidatePolicies(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      PolicyRule
+		wantError bool
+	}{
+		{name: "valid", rule: PolicyRule{Namespaces: []string{"team-*"}, NamespaceSelector: "tenant=true", DeniedFeatures: []string{PolicyFeatureReplicateAs}}},
+		{name: "invalid pattern", rule: PolicyRule{ExcludeNamespaces: []string{"infra-["}}, wantError: true},
+		{name: "invalid selector", rule: PolicyRule{NamespaceSelector: "tenant in"}, wantError: true},
+		{name: "unknown feature", rule: PolicyRule{DeniedFeatures: []string{"templates"}}, wantError: true},
+		{name: "negative min rotation interval", rule: PolicyRule{MinRotationInterval: Duration(-time.Hour)}, wantError: true},
+		{name: "invalid exempt label", rule: PolicyRule{ExemptLabel: "not a label"}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultConfig()
+			cfg.Policies = []PolicyRule{tt.rule}
+
+			err := cfg.Validate()
+			if tt.wantError && err == nil {
+				t.Error("expected error, got nil")
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfigValidateReplicationBoundaries(t *testing.T) {
+	tests := []struct {
+		name      string
+		boundary  ReplicationBoundary
+		wantError bool
+	}{
+		{name: "valid", boundary: ReplicationBoundary{From: "env=prod", To: "env!=prod"}},
+		{name: "missing to", boundary: ReplicationBoundary{From: "env=prod"}, wantError: true},
+		{name: "invalid selector", boundary: ReplicationBoundary{From: "env in", To: "env=dev"}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultConfig()
+			cfg.Replication.Boundaries = []ReplicationBoundary{tt.boundary}
+
+			err := cfg.Validate()
+			if tt.wantError && err == nil {
+				t.Error("expected error, got nil")
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfigValidateRotationDefaultInterval(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.Rotation.DefaultInterval = Duration(90 * 24 * time.Hour)
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.Rotation.DefaultInterval = Duration(-time.Hour)
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative rotation defaultInterval")
+	}
+
+	cfg.Rotation.DefaultInterval = Duration(time.Minute)
+	cfg.Rotation.MinInterval = Duration(time.Hour)
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for rotation defaultInterval below minInterval")
+	}
+}
+
+func TestOwnerLabels(t *testing.T) {
+	owner := OwnerLabelsConfig{SourceNamespace: "source-namespace", SourceName: "source-name", ManagedBy: "managed-by"}
+	tests := []struct {
+		name       string
+		owner      OwnerLabelsConfig
+		sourceRefs []string
+		expected   map[string]string
+	}{
+		{
+			name:       "single source",
+			owner:      owner,
+			sourceRefs: []string{"production/db"},
+			expected:   map[string]string{"source-namespace": "production", "source-name": "db", "managed-by": OwnerLabelManagedBy},
+		},
+		{
+			name:       "sources in the same namespace",
+			owner:      owner,
+			sourceRefs: []string{"production/db", "production/cache"},
+			expected:   map[string]string{"source-namespace": "production", "managed-by": OwnerLabelManagedBy},
+		},
+		{
+			name:       "sources in different namespaces",
+			owner:      owner,
+			sourceRefs: []string{"production/db", "shared/cache"},
+			expected:   map[string]string{"managed-by": OwnerLabelManagedBy},
+		},
+		{
+			name:       "name is no valid label value",
+			owner:      owner,
+			sourceRefs: []string{"production/" + strings.Repeat("a", 64)},
+			expected:   map[string]string{"source-namespace": "production", "managed-by": OwnerLabelManagedBy},
+		},
+		{
+			name:       "only managed-by",
+			owner:      OwnerLabelsConfig{ManagedBy: "managed-by"},
+			sourceRefs: []string{"production/db"},
+			expected:   map[string]string{"managed-by": OwnerLabelManagedBy},
+		},
+		{
+			name:       "disabled",
+			sourceRefs: []string{"production/db"},
+			expected:   map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.owner.Labels(tt.sourceRefs); !maps.Equal(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestConfigValidateReplicationPushedMetadata(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.Replication.PushedLabels = map[string]string{"managed-by": "iso-operator"}
+	cfg.Replication.PushedType = "example.com/replicated"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg = NewDefaultConfig()
+	cfg.Replication.PushedLabels = map[string]string{"managed by": "iso-operator"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for invalid pushedLabels key")
+	}
+
+	cfg = NewDefaultConfig()
+	cfg.Replication.OwnerLabels.SourceName = "source name"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for invalid ownerLabels key")
+	}
+
+	cfg = NewDefaultConfig()
+	cfg.Replication.PushedType = "helm.sh/release.v1"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for ignored pushedType")
+	}
+}
+
+func TestConfigValidateReplicationSourceFanOutRate(t *testing.T) {
+	cfg := NewDefaultConfig()
+	if cfg.Replication.SourceFanOutRate != DefaultSourceFanOutRate {
+		t.Errorf("expected default sourceFanOutRate %d, got %d", DefaultSourceFanOutRate, cfg.Replication.SourceFanOutRate)
+	}
+
+	cfg.Replication.SourceFanOutRate = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative sourceFanOutRate")
+	}
+}
+
+func TestConfigValidateReplicationMaxBytesPerSourceNamespace(t *testing.T) {
+	cfg := NewDefaultConfig()
+	if cfg.Replication.MaxBytesPerSourceNamespace != 0 {
+		t.Errorf("expected replication bytes to be unlimited by default, got %d", cfg.Replication.MaxBytesPerSourceNamespace)
+	}
+
+	cfg.Replication.MaxBytesPerSourceNamespace = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative maxBytesPerSourceNamespace")
+	}
+}
+
+func TestLoadConfigRotationTimestamps(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	if err := os.WriteFile(configPath, []byte("rotation:\n  clockSkewTolerance: 30s\n"), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Rotation.ClockSkewTolerance.Duration() != 30*time.Second {
+		t.Errorf("expected clockSkewTolerance 30s, got %s", cfg.Rotation.ClockSkewTolerance.Duration())
+	}
+	if cfg.Rotation.TimestampFormat != TimestampFormatRFC3339 {
+		t.Errorf("expected default timestamp format %q, got %q", TimestampFormatRFC3339, cfg.Rotation.TimestampFormat)
+	}
+
+	cfg = NewDefaultConfig()
+	cfg.Rotation.TimestampFormat = "iso8601"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for invalid timestampFormat")
+	}
+
+	cfg = NewDefaultConfig()
+	cfg.Rotation.ClockSkewTolerance = Duration(-time.Second)
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative clockSkewTolerance")
+	}
+}
+
+func TestConfigValidateBootstrap(t *testing.T) {
+	tests := []struct {
+		name      string
+		secrets   []BootstrapSecret
+		wantError bool
+	}{
+		{name: "valid", secrets: []BootstrapSecret{
+			{Name: "app-db", Annotations: map[string]string{"iso.gtrfc.com/autogenerate": "password"}},
+			{Name: "app-cache", Namespaces: []string{"team-*"}, NamespaceSelector: "tenant=true"},
+		}},
+		{name: "invalid name", secrets: []BootstrapSecret{{Name: "App_DB"}}, wantError: true},
+		{name: "duplicate name", secrets: []BootstrapSecret{{Name: "app-db"}, {Name: "app-db"}}, wantError: true},
+		{name: "invalid annotation key", secrets: []BootstrapSecret{{Name: "app-db", Annotations: map[string]string{"not valid": ""}}}, wantError: true},
+		{name: "invalid label value", secrets: []BootstrapSecret{{Name: "app-db", Labels: map[string]string{"app": "not valid"}}}, wantError: true},
+		{name: "invalid pattern", secrets: []BootstrapSecret{{Name: "app-db", Namespaces: []string{"team-["}}}, wantError: true},
+		{name: "invalid selector", secrets: []BootstrapSecret{{Name: "app-db", NamespaceSelector: "tenant in"}}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultConfig()
+			cfg.Bootstrap = tt.secrets
+			err := cfg.Validate()
+			if tt.wantError && err == nil {
+				t.Error("expected error")
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfigValidateSharding(t *testing.T) {
+	cfg := NewDefaultConfig()
+	if cfg.Sharding.Enabled() {
+		t.Error("expected sharding to be disabled by default")
+	}
+	cfg.Sharding.Shards = 3
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.Sharding.Mode = "random"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for invalid sharding mode")
+	}
+
+	cfg.Sharding.Mode = ShardingModeLabel
+	cfg.Sharding.LabelKey = "not a label"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for invalid sharding labelKey")
+	}
+
+	cfg.Sharding.LabelKey = DefaultShardingLabelKey
+	cfg.Sharding.Shards = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative sharding shards")
+	}
+}
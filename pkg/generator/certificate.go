@@ -0,0 +1,271 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	// PEMTypeCertificate is the PEM block type for certificates
+	PEMTypeCertificate = "CERTIFICATE"
+
+	// PEMTypePrivateKey is the PEM block type for PKCS#8 private keys
+	PEMTypePrivateKey = "PRIVATE KEY"
+
+	// KeyUsageDigitalSignature allows the key to create digital signatures
+	KeyUsageDigitalSignature = "digital-signature"
+	// KeyUsageKeyEncipherment allows the key to encipher other keys
+	KeyUsageKeyEncipherment = "key-encipherment"
+	// KeyUsageServerAuth allows the certificate to authenticate TLS servers
+	KeyUsageServerAuth = "server-auth"
+	// KeyUsageClientAuth allows the certificate to authenticate TLS clients
+	KeyUsageClientAuth = "client-auth"
+)
+
+// DefaultKeyUsages are the key usages of certificates that do not specify any
+var DefaultKeyUsages = []string{KeyUsageDigitalSignature, KeyUsageKeyEncipherment, KeyUsageServerAuth}
+
+// CertificateOptions holds the options for generating a certificate
+type CertificateOptions struct {
+	// CommonName is the subject common name of the certificate
+	CommonName string
+	// Duration is the validity period of the certificate
+	Duration time.Duration
+	// NotBefore is the start of the validity period
+	NotBefore time.Time
+	// DNSNames are the DNS subject alternative names of the certificate
+	DNSNames []string
+	// IPAddresses are the IP address subject alternative names of the certificate
+	IPAddresses []net.IP
+	// KeyUsages are the key usages of the certificate (e.g. KeyUsageServerAuth).
+	// If empty, DefaultKeyUsages are used.
+	KeyUsages []string
+	// IsCA marks the certificate as a CA, so that it can be used as its own trust anchor
+	// (e.g. in the caBundle of a webhook configuration)
+	IsCA bool
+	// Issuer signs the certificate instead of its own key. The validity period is capped at the
+	// expiry of the issuer, and the issuer's certificate chain is appended to the certificate.
+	Issuer *CA
+}
+
+// CA is a certificate authority that signs generated certificates
+type CA struct {
+	// Certificate is the parsed CA certificate
+	Certificate *x509.Certificate
+	// ChainPEM is the PEM-encoded certificate chain of the CA, starting with the CA certificate
+	ChainPEM []byte
+	// Key is the private key of the CA
+	Key crypto.Signer
+}
+
+// ParseCA parses a PEM-encoded CA certificate chain and its private key. The first certificate
+// must be a CA certificate that is allowed to sign certificates.
+func ParseCA(certPEM, keyPEM []byte) (*CA, error) {
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA certificate and private key: %w", err)
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA certificate: %w", err)
+	}
+	if !cert.IsCA {
+		return nil, fmt.Errorf("certificate %q is not a CA certificate", cert.Subject.CommonName)
+	}
+	if cert.KeyUsage != 0 && cert.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return nil, fmt.Errorf("certificate %q is not allowed to sign certificates", cert.Subject.CommonName)
+	}
+	key, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported CA private key type %T", pair.PrivateKey)
+	}
+	return &CA{Certificate: cert, ChainPEM: certPEM, Key: key}, nil
+}
+
+// Certificate holds a generated PEM-encoded certificate and private key
+type Certificate struct {
+	CertPEM  []byte
+	KeyPEM   []byte
+	NotAfter time.Time
+}
+
+// GenerateSelfSignedCertificate generates an ECDSA P-256 certificate that is self-signed, or
+// signed by opts.Issuer if set
+func GenerateSelfSignedCertificate(opts CertificateOptions) (*Certificate, error) {
+	if opts.Duration <= 0 {
+		return nil, fmt.Errorf("certificate duration must be positive, got %s", opts.Duration)
+	}
+	if opts.CommonName == "" {
+		return nil, fmt.Errorf("certificate common name must not be empty")
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate private key: %w", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+
+	notBefore := opts.NotBefore
+	if notBefore.IsZero() {
+		notBefore = time.Now()
+	}
+	notAfter := notBefore.Add(opts.Duration)
+	if opts.Issuer != nil && notAfter.After(opts.Issuer.Certificate.NotAfter) {
+		notAfter = opts.Issuer.Certificate.NotAfter
+	}
+	if !notAfter.After(notBefore) {
+		return nil, fmt.Errorf("issuer certificate expired at %s", notAfter.UTC().Format(time.RFC3339))
+	}
+
+	keyUsage, extKeyUsage, err := parseKeyUsages(opts.KeyUsages)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: opts.CommonName},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              keyUsage,
+		ExtKeyUsage:           extKeyUsage,
+		BasicConstraintsValid: true,
+		DNSNames:              opts.DNSNames,
+		IPAddresses:           opts.IPAddresses,
+	}
+	if opts.IsCA {
+		template.IsCA = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	}
+
+	parent, signer := template, crypto.Signer(key)
+	if opts.Issuer != nil {
+		parent, signer = opts.Issuer.Certificate, opts.Issuer.Key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: PEMTypeCertificate, Bytes: der})
+	if opts.Issuer != nil {
+		certPEM = append(certPEM, opts.Issuer.ChainPEM...)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal private key: %w", err)
+	}
+
+	return &Certificate{
+		CertPEM:  certPEM,
+		KeyPEM:   pem.EncodeToMemory(&pem.Block{Type: PEMTypePrivateKey, Bytes: keyDER}),
+		NotAfter: notAfter,
+	}, nil
+}
+
+// parseKeyUsages converts key usage names to x509 key usages and extended key usages
+func parseKeyUsages(usages []string) (x509.KeyUsage, []x509.ExtKeyUsage, error) {
+	if len(usages) == 0 {
+		usages = DefaultKeyUsages
+	}
+	var keyUsage x509.KeyUsage
+	var extKeyUsage []x509.ExtKeyUsage
+	for _, usage := range usages {
+		switch usage {
+		case KeyUsageDigitalSignature:
+			keyUsage |= x509.KeyUsageDigitalSignature
+		case KeyUsageKeyEncipherment:
+			keyUsage |= x509.KeyUsageKeyEncipherment
+		case KeyUsageServerAuth:
+			extKeyUsage = append(extKeyUsage, x509.ExtKeyUsageServerAuth)
+		case KeyUsageClientAuth:
+			extKeyUsage = append(extKeyUsage, x509.ExtKeyUsageClientAuth)
+		default:
+			return 0, nil, fmt.Errorf("unknown key usage %q, must be one of %s", usage, strings.Join([]string{
+				KeyUsageDigitalSignature, KeyUsageKeyEncipherment, KeyUsageServerAuth, KeyUsageClientAuth,
+			}, ", "))
+		}
+	}
+	return keyUsage, extKeyUsage, nil
+}
+
+// ParseCertificatePEM parses the first certificate in PEM-encoded data
+func ParseCertificatePEM(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != PEMTypeCertificate {
+		return nil, fmt.Errorf("no PEM-encoded certificate found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	return cert, nil
+}
+
+// ValidateKeyPair checks that all certificates of a PEM-encoded chain parse and that the
+// PEM-encoded private key matches the first (leaf) certificate
+func ValidateKeyPair(certPEM, keyPEM []byte) error {
+	count := 0
+	for rest := certPEM; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != PEMTypeCertificate {
+			continue
+		}
+		count++
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return fmt.Errorf("failed to parse certificate %d of the chain: %w", count, err)
+		}
+	}
+	if count == 0 {
+		return fmt.Errorf("no PEM-encoded certificate found")
+	}
+
+	if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		return fmt.Errorf("certificate and private key do not match: %w", err)
+	}
+	return nil
+}
+
+// CertificateKeyField returns the data key used for the private key belonging to a
+// certificate field. For "tls.crt" this is "tls.key", otherwise "<field>.key".
+func CertificateKeyField(certField string) string {
+	if strings.HasSuffix(certField, ".crt") {
+		return strings.TrimSuffix(certField, ".crt") + ".key"
+	}
+	return certField + ".key"
+}
@@ -17,14 +17,19 @@ limitations under the License.
 package generator
 
 import (
-	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"unicode"
 
 	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
 )
 
-// Generator defines the interface for secret generation
+// Generator defines the interface for secret generation.
+// GenerateWithOptions is the entry point for new callers; the other methods are shorthands.
 type Generator interface {
+	// GenerateWithOptions generates a value with the given options
+	GenerateWithOptions(opts GenerateOptions) ([]byte, error)
 	// GenerateString generates a random string of the specified length
 	GenerateString(length int) (string, error)
 	// GenerateStringWithCharset generates a random string with a custom charset
@@ -37,10 +42,12 @@ type Generator interface {
 	GenerateWithCharset(genType string, length int, charset string) (string, error)
 }
 
-// SecretGenerator implements the Generator interface using crypto/rand
+// SecretGenerator implements the Generator interface using a RandomSource (crypto/rand by default)
 type SecretGenerator struct {
 	// defaultCharset is the default character set used for string generation
 	defaultCharset string
+	// source provides the random bytes
+	source RandomSource
 }
 
 // DefaultCharset is the default character set for generating random strings
@@ -53,6 +60,7 @@ const AlphanumericCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXY
 func NewSecretGenerator() *SecretGenerator {
 	return &SecretGenerator{
 		defaultCharset: AlphanumericCharset,
+		source:         cryptoSource{},
 	}
 }
 
@@ -60,9 +68,24 @@ func NewSecretGenerator() *SecretGenerator {
 func NewSecretGeneratorWithCharset(charset string) *SecretGenerator {
 	return &SecretGenerator{
 		defaultCharset: charset,
+		source:         cryptoSource{},
 	}
 }
 
+// NewSecretGeneratorWithSource creates a new SecretGenerator with a custom default charset
+// that reads its random bytes from the given source
+func NewSecretGeneratorWithSource(charset string, source RandomSource) *SecretGenerator {
+	return &SecretGenerator{
+		defaultCharset: charset,
+		source:         source,
+	}
+}
+
+// RandomSource returns the name of the random source of the generator
+func (g *SecretGenerator) RandomSource() string {
+	return g.source.Name()
+}
+
 // GenerateString generates a random string of the specified length using the default charset
 func (g *SecretGenerator) GenerateString(length int) (string, error) {
 	return g.GenerateStringWithCharset(length, g.defaultCharset)
@@ -76,13 +99,19 @@ func (g *SecretGenerator) GenerateStringWithCharset(length int, charset string)
 	if charset == "" {
 		return "", fmt.Errorf("charset must not be empty")
 	}
+	// Characters are picked byte by byte, so multi-byte characters would produce invalid UTF-8
+	for _, r := range charset {
+		if r > unicode.MaxASCII {
+			return "", fmt.Errorf("charset must only contain ASCII characters, got %q", r)
+		}
+	}
 
 	result := make([]byte, length)
 	charsetLen := len(charset)
 
 	// Generate random bytes
 	randomBytes := make([]byte, length)
-	if _, err := rand.Read(randomBytes); err != nil {
+	if _, err := io.ReadFull(g.source, randomBytes); err != nil {
 		return "", fmt.Errorf("failed to generate random bytes: %w", err)
 	}
 
@@ -101,7 +130,7 @@ func (g *SecretGenerator) GenerateBytes(length int) ([]byte, error) {
 	}
 
 	randomBytes := make([]byte, length)
-	if _, err := rand.Read(randomBytes); err != nil {
+	if _, err := io.ReadFull(g.source, randomBytes); err != nil {
 		return nil, fmt.Errorf("failed to generate random bytes: %w", err)
 	}
 
@@ -124,6 +153,14 @@ func (g *SecretGenerator) GenerateWithCharset(genType string, length int, charse
 			return "", err
 		}
 		return string(bytes), nil
+	case config.TypeHex:
+		bytes, err := g.GenerateBytes(length)
+		if err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(bytes), nil
+	case config.TypeUUID:
+		return GenerateUUID(g)
 	default:
 		return "", fmt.Errorf("unknown generation type: %s", genType)
 	}
@@ -156,6 +156,8 @@ func TestGenerate(t *testing.T) {
 		{"string type", "string", 32, false},
 		{"empty type defaults to string", "", 32, false},
 		{"bytes type", "bytes", 32, false},
+		{"hex type", "hex", 32, false},
+		{"uuid type", "uuid", 32, false},
 		{"unknown type", "unknown", 32, true},
 	}
 
@@ -210,6 +212,8 @@ func TestGenerateStringWithCharset(t *testing.T) {
 		{"empty charset", 16, "", true},
 		{"zero length", 0, "abc", true},
 		{"negative length", -1, "abc", true},
+		{"multi-byte charset", 16, "abc€", true},
+		{"invalid UTF-8 charset", 16, "abc\xff", true},
 	}
 
 	for _, tt := range tests {
@@ -259,6 +263,8 @@ func TestGenerateWithCharset(t *testing.T) {
 		{"string with empty charset", "string", 16, "", true},
 		{"zero length string", "string", 0, "abc", true},
 		{"zero length bytes", "bytes", 0, "abc", true},
+		{"hex type ignores charset", "hex", 16, "abc123", false},
+		{"zero length hex", "hex", 0, "abc", true},
 	}
 
 	for _, tt := range tests {
@@ -283,3 +289,67 @@ func TestGenerateWithCharset(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateWithOptions(t *testing.T) {
+	gen := NewSecretGenerator()
+
+	tests := []struct {
+		name         string
+		opts         GenerateOptions
+		wantError    bool
+		wantLength   int
+		allowedChars string
+		checkPolicy  bool
+	}{
+		{"string defaults", GenerateOptions{Length: 16}, false, 16, AlphanumericCharset, false},
+		{"custom charset", GenerateOptions{Type: "string", Length: 8, Charset: "ab"}, false, 8, "ab", false},
+		{"bytes hex", GenerateOptions{Type: "bytes", Length: 16, Encoding: EncodingHex}, false, 32, "0123456789abcdef", false},
+		{"bytes base64url", GenerateOptions{Type: "bytes", Length: 16, Encoding: EncodingBase64URL}, false, 22, AlphanumericCharset + "-_", false},
+		{"bytes base32", GenerateOptions{Type: "bytes", Length: 16, Encoding: EncodingBase32}, false, 32, "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567=", false},
+		{"string base64", GenerateOptions{Length: 3, Charset: "a", Encoding: EncodingBase64}, false, 4, "YWFh", false},
+		{
+			"policy satisfied",
+			GenerateOptions{Length: 8, Policy: &Policy{MinUppercase: 2, MinLowercase: 2, MinNumbers: 2}},
+			false, 8, AlphanumericCharset, true,
+		},
+		{"hex", GenerateOptions{Type: "hex", Length: 16}, false, 32, "0123456789abcdef", false},
+		{"uuid ignores length", GenerateOptions{Type: "uuid"}, false, UUIDLength, "0123456789abcdef-", false},
+		{"zero length", GenerateOptions{}, true, 0, "", false},
+		{"unknown type", GenerateOptions{Type: "invalid", Length: 8}, true, 0, "", false},
+		{"unknown encoding", GenerateOptions{Length: 8, Encoding: "rot13"}, true, 0, "", false},
+		{"policy on bytes", GenerateOptions{Type: "bytes", Length: 8, Policy: &Policy{}}, true, 0, "", false},
+		{"policy on hex", GenerateOptions{Type: "hex", Length: 8, Policy: &Policy{}}, true, 0, "", false},
+		{"policy on uuid", GenerateOptions{Type: "uuid", Policy: &Policy{}}, true, 0, "", false},
+		{"policy exceeds length", GenerateOptions{Length: 2, Policy: &Policy{MinUppercase: 2, MinNumbers: 1}}, true, 0, "", false},
+		{"policy class missing in charset", GenerateOptions{Length: 8, Charset: "abc", Policy: &Policy{MinSpecialChars: 1}}, true, 0, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := gen.GenerateWithOptions(tt.opts)
+			if tt.wantError {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(value) != tt.wantLength {
+				t.Errorf("expected length %d, got %d", tt.wantLength, len(value))
+			}
+			for _, c := range string(value) {
+				if !strings.ContainsRune(tt.allowedChars, c) {
+					t.Errorf("unexpected character %q in %q", c, value)
+				}
+			}
+			if tt.checkPolicy {
+				counts := countClasses(string(value))
+				if counts.uppercase < 2 || counts.lowercase < 2 || counts.numbers < 2 {
+					t.Errorf("value %q does not satisfy the policy", value)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import (
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// HashBcrypt is the hash algorithm of bcrypt companion fields, e.g. for htpasswd files
+	HashBcrypt = "bcrypt"
+
+	// BcryptCost is the cost of generated bcrypt hashes (2^10 rounds)
+	BcryptCost = 10
+)
+
+// HashField returns the name of the companion key holding the hash of a field (e.g. password-bcrypt)
+func HashField(field, algorithm string) string {
+	return field + "-" + algorithm
+}
+
+// ValidateHash returns an error if the hash algorithm is not supported
+func ValidateHash(algorithm string) error {
+	if algorithm != HashBcrypt {
+		return fmt.Errorf("unknown hash algorithm: %s", algorithm)
+	}
+	return nil
+}
+
+// GenerateHash returns the hash of the value with the given algorithm and a random salt.
+// bcrypt hashes at most 72 bytes; longer values are rejected instead of being truncated.
+func GenerateHash(algorithm string, value []byte) ([]byte, error) {
+	if err := ValidateHash(algorithm); err != nil {
+		return nil, err
+	}
+	return bcrypt.GenerateFromPassword(value, BcryptCost)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestGenerateHash(t *testing.T) {
+	pattern := regexp.MustCompile(`^\$2a\$10\$[./A-Za-z0-9]{53}$`)
+
+	hash, err := GenerateHash(HashBcrypt, []byte("password"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !pattern.Match(hash) {
+		t.Fatalf("%q is not a bcrypt hash", hash)
+	}
+	if err := bcrypt.CompareHashAndPassword(hash, []byte("password")); err != nil {
+		t.Errorf("expected the hash to match the value: %v", err)
+	}
+	other, err := GenerateHash(HashBcrypt, []byte("password"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(hash) == string(other) {
+		t.Error("expected different salts for each hash")
+	}
+
+	if _, err := GenerateHash(HashBcrypt, []byte(strings.Repeat("x", 73))); err == nil {
+		t.Error("expected error for values longer than 72 bytes")
+	}
+	if _, err := GenerateHash("md5", []byte("password")); err == nil {
+		t.Error("expected error for unknown hash algorithm")
+	}
+	if got := HashField("password", HashBcrypt); got != "password-bcrypt" {
+		t.Errorf("HashField() = %q, want %q", got, "password-bcrypt")
+	}
+}
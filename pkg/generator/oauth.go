@@ -0,0 +1,58 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+// clientIDLength is the number of characters of hex and alphanumeric client IDs
+const clientIDLength = 32
+
+// OAuthClientIDField returns the data field that holds the client ID of an oauth-client field:
+// "client_secret" becomes "client_id" (also with a prefix, e.g. "github_client_secret", and with
+// dashes), any other field gets a ".client_id" suffix
+func OAuthClientIDField(secretField string) string {
+	for _, suffix := range []string{"client_secret", "client-secret"} {
+		if strings.HasSuffix(secretField, suffix) {
+			return strings.TrimSuffix(secretField, "secret") + "id"
+		}
+	}
+	return secretField + ".client_id"
+}
+
+// GenerateClientID generates an OAuth client ID in the given format (uuid, hex or alphanumeric)
+func GenerateClientID(g Generator, format string) (string, error) {
+	switch format {
+	case config.ClientIDFormatUUID, "":
+		return GenerateUUID(g)
+	case config.ClientIDFormatHex:
+		b, err := g.GenerateBytes(clientIDLength / 2)
+		if err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(b), nil
+	case config.ClientIDFormatAlphanumeric:
+		return g.GenerateStringWithCharset(clientIDLength, AlphanumericCharset)
+	default:
+		return "", fmt.Errorf("unknown client ID format %q, must be one of %v", format, config.ClientIDFormats)
+	}
+}
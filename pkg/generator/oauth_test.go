@@ -0,0 +1,65 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+func TestOAuthClientIDField(t *testing.T) {
+	tests := map[string]string{
+		"client_secret":        "client_id",
+		"github_client_secret": "github_client_id",
+		"client-secret":        "client-id",
+		"oauth":                "oauth.client_id",
+	}
+	for field, expected := range tests {
+		if got := OAuthClientIDField(field); got != expected {
+			t.Errorf("OAuthClientIDField(%q) = %q, expected %q", field, got, expected)
+		}
+	}
+}
+
+func TestGenerateClientID(t *testing.T) {
+	g := NewSecretGenerator()
+	tests := []struct {
+		format  string
+		pattern string
+	}{
+		{config.ClientIDFormatUUID, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{config.ClientIDFormatHex, `^[0-9a-f]{32}$`},
+		{config.ClientIDFormatAlphanumeric, `^[a-zA-Z0-9]{32}$`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			id, err := GenerateClientID(g, tt.format)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !regexp.MustCompile(tt.pattern).MatchString(id) {
+				t.Errorf("client ID %q does not match %s", id, tt.pattern)
+			}
+		})
+	}
+
+	if _, err := GenerateClientID(g, "invalid"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+const (
+	// EncodingRaw stores the generated value as is (default)
+	EncodingRaw = "raw"
+	// EncodingBase64 encodes the generated value with standard base64
+	EncodingBase64 = "base64"
+	// EncodingBase64URL encodes the generated value with URL-safe base64 without padding (RFC 4648 §5)
+	EncodingBase64URL = "base64url"
+	// EncodingBase32 encodes the generated value with standard base32
+	EncodingBase32 = "base32"
+	// EncodingHex encodes the generated value as lowercase hex
+	EncodingHex = "hex"
+
+	// maxPolicyAttempts bounds the number of values generated to satisfy a Policy
+	maxPolicyAttempts = 1000
+)
+
+// GenerateOptions holds the options for generating a value
+type GenerateOptions struct {
+	// Type is the generation type (string, bytes, hex or uuid). Defaults to string.
+	Type string
+	// Length is the number of characters (string) or bytes (bytes, hex) before encoding. UUIDs
+	// always have UUIDLength characters, their length is ignored.
+	Length int
+	// Charset is the character set for string values. Defaults to the generator's charset.
+	Charset string
+	// Encoding is applied to the generated value (raw, base64, base64url, base32, hex). Defaults to raw.
+	Encoding string
+	// Policy requires minimum numbers of character classes in string values. Optional.
+	Policy *Policy
+}
+
+// Policy requires minimum numbers of character classes in generated strings
+type Policy struct {
+	MinUppercase    int
+	MinLowercase    int
+	MinNumbers      int
+	MinSpecialChars int
+}
+
+// Validate checks the options for errors that do not depend on the generator
+func (o GenerateOptions) Validate() error {
+	if o.Length <= 0 && o.Type != config.TypeUUID {
+		return fmt.Errorf("length must be positive, got %d", o.Length)
+	}
+	switch o.Type {
+	case config.DefaultType, "", config.TypeBytes, config.TypeHex, config.TypeUUID:
+	default:
+		return fmt.Errorf("unknown generation type: %s", o.Type)
+	}
+	if err := ValidateEncoding(o.Encoding); err != nil {
+		return err
+	}
+	if o.Policy == nil {
+		return nil
+	}
+	if o.Type != config.DefaultType && o.Type != "" {
+		return fmt.Errorf("a policy can only be applied to string values")
+	}
+	p := o.Policy
+	if p.MinUppercase < 0 || p.MinLowercase < 0 || p.MinNumbers < 0 || p.MinSpecialChars < 0 {
+		return fmt.Errorf("policy minimums must not be negative")
+	}
+	if required := p.MinUppercase + p.MinLowercase + p.MinNumbers + p.MinSpecialChars; required > o.Length {
+		return fmt.Errorf("policy requires %d characters, but length is %d", required, o.Length)
+	}
+	return nil
+}
+
+// ValidateEncoding returns an error if the encoding is not supported. An empty encoding is raw.
+func ValidateEncoding(encoding string) error {
+	switch encoding {
+	case EncodingRaw, "", EncodingBase64, EncodingBase64URL, EncodingBase32, EncodingHex:
+		return nil
+	default:
+		return fmt.Errorf("unknown encoding: %s", encoding)
+	}
+}
+
+// GenerateWithOptions generates a value with the given options
+func (g *SecretGenerator) GenerateWithOptions(opts GenerateOptions) ([]byte, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	var value []byte
+	switch opts.Type {
+	case config.TypeBytes:
+		bytes, err := g.GenerateBytes(opts.Length)
+		if err != nil {
+			return nil, err
+		}
+		value = bytes
+	case config.TypeHex:
+		bytes, err := g.GenerateBytes(opts.Length)
+		if err != nil {
+			return nil, err
+		}
+		value = []byte(hex.EncodeToString(bytes))
+	case config.TypeUUID:
+		uuid, err := GenerateUUID(g)
+		if err != nil {
+			return nil, err
+		}
+		value = []byte(uuid)
+	default:
+		charset := opts.Charset
+		if charset == "" {
+			charset = g.defaultCharset
+		}
+		str, err := g.generatePolicyString(opts.Length, charset, opts.Policy)
+		if err != nil {
+			return nil, err
+		}
+		value = []byte(str)
+	}
+
+	switch opts.Encoding {
+	case EncodingBase64:
+		return []byte(base64.StdEncoding.EncodeToString(value)), nil
+	case EncodingBase64URL:
+		return []byte(base64.RawURLEncoding.EncodeToString(value)), nil
+	case EncodingBase32:
+		return []byte(base32.StdEncoding.EncodeToString(value)), nil
+	case EncodingHex:
+		return []byte(hex.EncodeToString(value)), nil
+	default:
+		return value, nil
+	}
+}
+
+// generatePolicyString generates strings until one satisfies the policy. Rejecting values
+// instead of placing required characters keeps every character uniformly distributed.
+func (g *SecretGenerator) generatePolicyString(length int, charset string, policy *Policy) (string, error) {
+	if policy == nil {
+		return g.GenerateStringWithCharset(length, charset)
+	}
+
+	available := countClasses(charset)
+	if (policy.MinUppercase > 0 && available.uppercase == 0) ||
+		(policy.MinLowercase > 0 && available.lowercase == 0) ||
+		(policy.MinNumbers > 0 && available.numbers == 0) ||
+		(policy.MinSpecialChars > 0 && available.specialChars == 0) {
+		return "", fmt.Errorf("charset does not contain all character classes required by the policy")
+	}
+
+	for attempt := 0; attempt < maxPolicyAttempts; attempt++ {
+		value, err := g.GenerateStringWithCharset(length, charset)
+		if err != nil {
+			return "", err
+		}
+		counts := countClasses(value)
+		if counts.uppercase >= policy.MinUppercase && counts.lowercase >= policy.MinLowercase &&
+			counts.numbers >= policy.MinNumbers && counts.specialChars >= policy.MinSpecialChars {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("failed to satisfy the policy after %d attempts", maxPolicyAttempts)
+}
+
+// classCounts holds the number of characters per character class
+type classCounts struct {
+	uppercase    int
+	lowercase    int
+	numbers      int
+	specialChars int
+}
+
+// countClasses counts the characters of each class in s
+func countClasses(s string) classCounts {
+	var counts classCounts
+	for _, c := range s {
+		switch {
+		case strings.ContainsRune("ABCDEFGHIJKLMNOPQRSTUVWXYZ", c):
+			counts.uppercase++
+		case strings.ContainsRune("abcdefghijklmnopqrstuvwxyz", c):
+			counts.lowercase++
+		case strings.ContainsRune("0123456789", c):
+			counts.numbers++
+		default:
+			counts.specialChars++
+		}
+	}
+	return counts
+}
@@ -0,0 +1,411 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// PKCS#12 keystores are encrypted with PBES2 (PBKDF2 with HMAC-SHA256 and AES-256-CBC) and
+// protected by an HMAC-SHA256 MAC, the defaults of current Java and OpenSSL versions
+const (
+	pkcs12Iterations = 10000
+	pkcs12SaltLength = 16
+)
+
+var (
+	oidData                  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidPKCS8ShroudedKeyBag   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 2}
+	oidCertBag               = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 3}
+	oidCertTypeX509          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 22, 1}
+	oidFriendlyName          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 20}
+	oidLocalKeyID            = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 21}
+	oidPBES2                 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2                = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA256        = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidAES256CBC             = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidSHA256                = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidJavaTrustedKeyUsage   = asn1.ObjectIdentifier{2, 16, 840, 1, 113894, 746875, 1, 1}
+	oidAnyExtendedKeyUsage   = asn1.ObjectIdentifier{2, 5, 29, 37, 0}
+	asn1Null                 = asn1.RawValue{Tag: asn1.TagNull}
+	errEmptyKeystorePassword = fmt.Errorf("keystore password must not be empty")
+)
+
+type pfx struct {
+	Version  int
+	AuthSafe contentInfo
+	MacData  macData
+}
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue
+}
+
+type macData struct {
+	Mac        digestInfo
+	MacSalt    []byte
+	Iterations int
+}
+
+type digestInfo struct {
+	Algorithm algorithmIdentifier
+	Digest    []byte
+}
+
+type algorithmIdentifier struct {
+	Algorithm  asn1.ObjectIdentifier
+	Parameters asn1.RawValue `asn1:"optional"`
+}
+
+type safeBag struct {
+	ID         asn1.ObjectIdentifier
+	Value      asn1.RawValue
+	Attributes []pkcs12Attribute `asn1:"set,optional"`
+}
+
+type pkcs12Attribute struct {
+	ID    asn1.ObjectIdentifier
+	Value asn1.RawValue
+}
+
+type certBag struct {
+	ID   asn1.ObjectIdentifier
+	Data []byte `asn1:"explicit,tag:0"`
+}
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm     algorithmIdentifier
+	EncryptedData []byte
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc algorithmIdentifier
+	EncryptionScheme  algorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt       []byte
+	Iterations int
+	PRF        algorithmIdentifier
+}
+
+// KeystoreFields returns the data keys of the PKCS#12 keystore and truststore rendered for a
+// certificate field: "keystore.p12" and "truststore.p12" for "tls.crt", otherwise the field
+// name (without a ".crt" suffix) followed by ".keystore.p12" and ".truststore.p12"
+func KeystoreFields(certField string) (keystore, truststore string) {
+	if certField == "tls.crt" {
+		return "keystore.p12", "truststore.p12"
+	}
+	prefix := strings.TrimSuffix(certField, ".crt")
+	return prefix + ".keystore.p12", prefix + ".truststore.p12"
+}
+
+// EncodeKeystore encodes a PEM-encoded certificate chain and PKCS#8 private key as a
+// password-protected PKCS#12 keystore with a single key entry named alias
+func EncodeKeystore(certPEM, keyPEM []byte, alias, password string) ([]byte, error) {
+	if password == "" {
+		return nil, errEmptyKeystorePassword
+	}
+	certs, err := decodeCertificatesPEM(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(keyPEM)
+	if block == nil || block.Type != PEMTypePrivateKey {
+		return nil, fmt.Errorf("no PEM-encoded PKCS#8 private key found")
+	}
+
+	// The local key ID links the key to its certificate
+	localKeyID := sha256.Sum256(certs[0])
+	keyAttributes := []pkcs12Attribute{friendlyNameAttribute(alias), localKeyIDAttribute(localKeyID[:])}
+
+	encryptedKey, err := encryptPBES2(block.Bytes, password)
+	if err != nil {
+		return nil, err
+	}
+	keyBag, err := newSafeBag(oidPKCS8ShroudedKeyBag, encryptedKey, keyAttributes)
+	if err != nil {
+		return nil, err
+	}
+
+	bags := []safeBag{keyBag}
+	for i, der := range certs {
+		var attributes []pkcs12Attribute
+		if i == 0 {
+			attributes = keyAttributes
+		}
+		bag, err := newCertBag(der, attributes)
+		if err != nil {
+			return nil, err
+		}
+		bags = append(bags, bag)
+	}
+	return encodePFX(bags, password)
+}
+
+// EncodeTruststore encodes PEM-encoded certificates as a password-protected PKCS#12 truststore.
+// The first certificate is named alias, further ones alias-1, alias-2 and so on.
+func EncodeTruststore(certsPEM []byte, alias, password string) ([]byte, error) {
+	if password == "" {
+		return nil, errEmptyKeystorePassword
+	}
+	certs, err := decodeCertificatesPEM(certsPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	// Java only treats certificates without a key as trusted if they carry its trusted key usage
+	trusted, err := asn1.Marshal(oidAnyExtendedKeyUsage)
+	if err != nil {
+		return nil, err
+	}
+	bags := make([]safeBag, 0, len(certs))
+	for i, der := range certs {
+		name := alias
+		if i > 0 {
+			name = fmt.Sprintf("%s-%d", alias, i)
+		}
+		bag, err := newCertBag(der, []pkcs12Attribute{
+			friendlyNameAttribute(name),
+			{ID: oidJavaTrustedKeyUsage, Value: asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: trusted}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		bags = append(bags, bag)
+	}
+	return encodePFX(bags, password)
+}
+
+// decodeCertificatesPEM returns the DER-encoded certificates of a PEM-encoded chain
+func decodeCertificatesPEM(data []byte) ([][]byte, error) {
+	var certs [][]byte
+	for rest := data; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != PEMTypeCertificate {
+			continue
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		certs = append(certs, block.Bytes)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no PEM-encoded certificate found")
+	}
+	return certs, nil
+}
+
+// newSafeBag returns a safe bag holding the DER encoding of value
+func newSafeBag(id asn1.ObjectIdentifier, value any, attributes []pkcs12Attribute) (safeBag, error) {
+	der, err := asn1.Marshal(value)
+	if err != nil {
+		return safeBag{}, err
+	}
+	return safeBag{ID: id, Value: explicitValue(der), Attributes: attributes}, nil
+}
+
+// newCertBag returns a safe bag holding a DER-encoded X.509 certificate
+func newCertBag(der []byte, attributes []pkcs12Attribute) (safeBag, error) {
+	return newSafeBag(oidCertBag, certBag{ID: oidCertTypeX509, Data: der}, attributes)
+}
+
+// friendlyNameAttribute returns the attribute that names a keystore entry
+func friendlyNameAttribute(name string) pkcs12Attribute {
+	value := asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagBMPString, Bytes: bmpString(name)}
+	der, _ := asn1.Marshal(value)
+	return pkcs12Attribute{ID: oidFriendlyName, Value: asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: der}}
+}
+
+// localKeyIDAttribute returns the attribute that links a private key to its certificate
+func localKeyIDAttribute(id []byte) pkcs12Attribute {
+	der, _ := asn1.Marshal(id)
+	return pkcs12Attribute{ID: oidLocalKeyID, Value: asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: der}}
+}
+
+// encodePFX encodes the safe bags as a PKCS#12 PFX protected by a MAC
+func encodePFX(bags []safeBag, password string) ([]byte, error) {
+	safeContents, err := asn1.Marshal(bags)
+	if err != nil {
+		return nil, err
+	}
+	safeContentsData, err := dataContentInfo(safeContents)
+	if err != nil {
+		return nil, err
+	}
+	authSafe, err := asn1.Marshal([]contentInfo{safeContentsData})
+	if err != nil {
+		return nil, err
+	}
+	authSafeData, err := dataContentInfo(authSafe)
+	if err != nil {
+		return nil, err
+	}
+
+	salt, err := randomBytes(pkcs12SaltLength)
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, pkcs12MACKey(password, salt, pkcs12Iterations))
+	mac.Write(authSafe)
+
+	return asn1.Marshal(pfx{
+		Version:  3,
+		AuthSafe: authSafeData,
+		MacData: macData{
+			Mac:        digestInfo{Algorithm: algorithmIdentifier{Algorithm: oidSHA256, Parameters: asn1Null}, Digest: mac.Sum(nil)},
+			MacSalt:    salt,
+			Iterations: pkcs12Iterations,
+		},
+	})
+}
+
+// dataContentInfo wraps data in a content info of type data
+func dataContentInfo(data []byte) (contentInfo, error) {
+	octets, err := asn1.Marshal(data)
+	if err != nil {
+		return contentInfo{}, err
+	}
+	return contentInfo{ContentType: oidData, Content: explicitValue(octets)}, nil
+}
+
+// explicitValue wraps a DER encoding in an explicit [0] tag. Struct tags cannot be used for
+// this, since encoding/asn1 ignores them for pre-encoded raw values.
+func explicitValue(der []byte) asn1.RawValue {
+	return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: der}
+}
+
+// encryptPBES2 encrypts a DER-encoded PKCS#8 private key with PBES2
+func encryptPBES2(plaintext []byte, password string) (encryptedPrivateKeyInfo, error) {
+	salt, err := randomBytes(pkcs12SaltLength)
+	if err != nil {
+		return encryptedPrivateKeyInfo{}, err
+	}
+	iv, err := randomBytes(aes.BlockSize)
+	if err != nil {
+		return encryptedPrivateKeyInfo{}, err
+	}
+	key, err := pbkdf2.Key(sha256.New, password, salt, pkcs12Iterations, 32)
+	if err != nil {
+		return encryptedPrivateKeyInfo{}, fmt.Errorf("failed to derive keystore key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return encryptedPrivateKeyInfo{}, err
+	}
+
+	// PKCS#7 padding
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	padded := append([]byte{}, plaintext...)
+	for range padding {
+		padded = append(padded, byte(padding))
+	}
+	ciphertext := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, padded)
+
+	kdfParams, err := asn1.Marshal(pbkdf2Params{
+		Salt:       salt,
+		Iterations: pkcs12Iterations,
+		PRF:        algorithmIdentifier{Algorithm: oidHMACWithSHA256, Parameters: asn1Null},
+	})
+	if err != nil {
+		return encryptedPrivateKeyInfo{}, err
+	}
+	ivParam, err := asn1.Marshal(iv)
+	if err != nil {
+		return encryptedPrivateKeyInfo{}, err
+	}
+	params, err := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: algorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: kdfParams}},
+		EncryptionScheme:  algorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: ivParam}},
+	})
+	if err != nil {
+		return encryptedPrivateKeyInfo{}, err
+	}
+
+	return encryptedPrivateKeyInfo{
+		Algorithm:     algorithmIdentifier{Algorithm: oidPBES2, Parameters: asn1.RawValue{FullBytes: params}},
+		EncryptedData: ciphertext,
+	}, nil
+}
+
+// pkcs12MACKey derives the MAC key with the PKCS#12 key derivation function (RFC 7292,
+// appendix B.2) for SHA-256
+func pkcs12MACKey(password string, salt []byte, iterations int) []byte {
+	const u, v = sha256.Size, 64
+	const macKeyID = 3
+
+	// The password is a null-terminated BMPString
+	pass := append(bmpString(password), 0, 0)
+	fill := func(data []byte) []byte {
+		if len(data) == 0 {
+			return nil
+		}
+		out := make([]byte, v*((len(data)+v-1)/v))
+		for i := range out {
+			out[i] = data[i%len(data)]
+		}
+		return out
+	}
+	diversifier := make([]byte, v)
+	for i := range diversifier {
+		diversifier[i] = macKeyID
+	}
+	input := append(fill(salt), fill(pass)...)
+
+	// A single block of u bytes is enough for the HMAC-SHA256 key
+	hash := sha256.Sum256(append(diversifier, input...))
+	for i := 1; i < iterations; i++ {
+		hash = sha256.Sum256(hash[:])
+	}
+	return hash[:u]
+}
+
+// bmpString encodes a string as big-endian UTF-16
+func bmpString(s string) []byte {
+	encoded := utf16.Encode([]rune(s))
+	out := make([]byte, 0, 2*len(encoded))
+	for _, c := range encoded {
+		out = append(out, byte(c>>8), byte(c))
+	}
+	return out
+}
+
+// randomBytes returns n cryptographically random bytes
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/pem"
+	"testing"
+	"time"
+)
+
+// decodePFX verifies the MAC of a PKCS#12 keystore and returns its safe bags
+func decodePFX(t *testing.T, data []byte, password string) []safeBag {
+	t.Helper()
+	var p pfx
+	if _, err := asn1.Unmarshal(data, &p); err != nil {
+		t.Fatalf("failed to decode PFX: %v", err)
+	}
+	var authSafe []byte
+	if _, err := asn1.Unmarshal(p.AuthSafe.Content.Bytes, &authSafe); err != nil {
+		t.Fatalf("failed to decode authenticated safe: %v", err)
+	}
+	mac := hmac.New(sha256.New, pkcs12MACKey(password, p.MacData.MacSalt, p.MacData.Iterations))
+	mac.Write(authSafe)
+	if !hmac.Equal(mac.Sum(nil), p.MacData.Mac.Digest) {
+		t.Fatal("MAC does not match")
+	}
+
+	var contents []contentInfo
+	if _, err := asn1.Unmarshal(authSafe, &contents); err != nil {
+		t.Fatalf("failed to decode content infos: %v", err)
+	}
+	var bags []safeBag
+	for _, content := range contents {
+		var safeContents []byte
+		if _, err := asn1.Unmarshal(content.Content.Bytes, &safeContents); err != nil {
+			t.Fatalf("failed to decode safe contents: %v", err)
+		}
+		var contentBags []safeBag
+		if _, err := asn1.Unmarshal(safeContents, &contentBags); err != nil {
+			t.Fatalf("failed to decode safe bags: %v", err)
+		}
+		bags = append(bags, contentBags...)
+	}
+	return bags
+}
+
+// decryptKeyBag decrypts the PKCS#8 private key of a shrouded key bag
+func decryptKeyBag(t *testing.T, bag safeBag, password string) []byte {
+	t.Helper()
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(bag.Value.Bytes, &info); err != nil {
+		t.Fatalf("failed to decode encrypted private key: %v", err)
+	}
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		t.Fatalf("failed to decode PBES2 parameters: %v", err)
+	}
+	var kdf pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf); err != nil {
+		t.Fatalf("failed to decode PBKDF2 parameters: %v", err)
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		t.Fatalf("failed to decode IV: %v", err)
+	}
+
+	key, err := pbkdf2.Key(sha256.New, password, kdf.Salt, kdf.Iterations, 32)
+	if err != nil {
+		t.Fatalf("failed to derive key: %v", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatalf("failed to create cipher: %v", err)
+	}
+	plaintext := make([]byte, len(info.EncryptedData))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, info.EncryptedData)
+	return plaintext[:len(plaintext)-int(plaintext[len(plaintext)-1])]
+}
+
+func TestEncodeKeystore(t *testing.T) {
+	ca, err := GenerateSelfSignedCertificate(CertificateOptions{CommonName: "ca", Duration: time.Hour, IsCA: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	issuer, err := ParseCA(ca.CertPEM, ca.KeyPEM)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cert, err := GenerateSelfSignedCertificate(CertificateOptions{CommonName: "my-service", Duration: time.Hour, Issuer: issuer})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	keystore, err := EncodeKeystore(cert.CertPEM, cert.KeyPEM, "my-service", "changeit")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bags := decodePFX(t, keystore, "changeit")
+
+	// The key and the full chain are stored
+	if len(bags) != 3 || !bags[0].ID.Equal(oidPKCS8ShroudedKeyBag) ||
+		!bags[1].ID.Equal(oidCertBag) || !bags[2].ID.Equal(oidCertBag) {
+		t.Fatalf("expected a key bag and two certificate bags, got %v", bags)
+	}
+	keyBlock, _ := pem.Decode(cert.KeyPEM)
+	if !bytes.Equal(decryptKeyBag(t, bags[0], "changeit"), keyBlock.Bytes) {
+		t.Error("decrypted private key does not match")
+	}
+	if len(bags[0].Attributes) != 2 || !bytes.Equal(bags[0].Attributes[1].Value.Bytes, bags[1].Attributes[1].Value.Bytes) {
+		t.Error("expected the private key and the leaf certificate to share the local key ID")
+	}
+	var leaf certBag
+	if _, err := asn1.Unmarshal(bags[1].Value.Bytes, &leaf); err != nil {
+		t.Fatalf("failed to decode certificate bag: %v", err)
+	}
+	certBlock, _ := pem.Decode(cert.CertPEM)
+	if !bytes.Equal(leaf.Data, certBlock.Bytes) {
+		t.Error("expected the first certificate to be the leaf certificate")
+	}
+
+	truststore, err := EncodeTruststore(ca.CertPEM, "my-service", "changeit")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bags = decodePFX(t, truststore, "changeit")
+	if len(bags) != 1 || !bags[0].ID.Equal(oidCertBag) {
+		t.Fatalf("expected a single certificate bag, got %v", bags)
+	}
+	trusted := false
+	for _, attribute := range bags[0].Attributes {
+		trusted = trusted || attribute.ID.Equal(oidJavaTrustedKeyUsage)
+	}
+	if !trusted {
+		t.Error("expected the certificate to be marked as trusted")
+	}
+}
+
+func TestEncodeKeystoreErrors(t *testing.T) {
+	cert, err := GenerateSelfSignedCertificate(CertificateOptions{CommonName: "my-service", Duration: time.Hour})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := EncodeKeystore(cert.CertPEM, cert.KeyPEM, "my-service", ""); err == nil {
+		t.Error("expected error for empty password")
+	}
+	if _, err := EncodeKeystore(cert.CertPEM, cert.CertPEM, "my-service", "changeit"); err == nil {
+		t.Error("expected error for missing private key")
+	}
+	if _, err := EncodeTruststore([]byte("not a certificate"), "my-service", "changeit"); err == nil {
+		t.Error("expected error for missing certificate")
+	}
+}
+
+func TestKeystoreFields(t *testing.T) {
+	tests := []struct {
+		field      string
+		keystore   string
+		truststore string
+	}{
+		{"tls.crt", "keystore.p12", "truststore.p12"},
+		{"server.crt", "server.keystore.p12", "server.truststore.p12"},
+		{"cert", "cert.keystore.p12", "cert.truststore.p12"},
+	}
+	for _, tt := range tests {
+		keystore, truststore := KeystoreFields(tt.field)
+		if keystore != tt.keystore || truststore != tt.truststore {
+			t.Errorf("KeystoreFields(%q) = %q, %q, expected %q, %q", tt.field, keystore, truststore, tt.keystore, tt.truststore)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/bits"
+	"sort"
+	"sync"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+// DefaultRandomSource is the name of the random source backed by crypto/rand
+const DefaultRandomSource = config.DefaultRandomSource
+
+const (
+	// selfTestSize is the number of bytes read per sample in SelfTest
+	selfTestSize = 2048
+	// selfTestMaxBias is the maximum allowed deviation of the share of one bits from 50%
+	selfTestMaxBias = 0.05
+)
+
+// RandomSource provides the random bytes for generated values.
+// Implementations must be safe for concurrent use.
+type RandomSource interface {
+	io.Reader
+	// Name identifies the source, e.g. in metrics
+	Name() string
+}
+
+// RandomSourceFactory creates a RandomSource, e.g. by connecting to an HSM or KMS
+type RandomSourceFactory func() (RandomSource, error)
+
+var (
+	randomSourcesMu sync.RWMutex
+	randomSources   = map[string]RandomSourceFactory{
+		DefaultRandomSource: func() (RandomSource, error) { return cryptoSource{}, nil },
+	}
+)
+
+// RegisterRandomSource makes a random source available under the given name.
+// Builds that link an HSM or KMS DRBG call it from an init function; the source is then
+// selected with the randomness.source configuration option.
+func RegisterRandomSource(name string, factory RandomSourceFactory) {
+	randomSourcesMu.Lock()
+	defer randomSourcesMu.Unlock()
+	if factory == nil {
+		panic("generator: RegisterRandomSource factory is nil")
+	}
+	if _, exists := randomSources[name]; exists {
+		panic("generator: RegisterRandomSource called twice for " + name)
+	}
+	randomSources[name] = factory
+}
+
+// RandomSources returns the names of all registered random sources
+func RandomSources() []string {
+	randomSourcesMu.RLock()
+	defer randomSourcesMu.RUnlock()
+	names := make([]string, 0, len(randomSources))
+	for name := range randomSources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewRandomSource creates the registered random source with the given name
+func NewRandomSource(name string) (RandomSource, error) {
+	randomSourcesMu.RLock()
+	factory, ok := randomSources[name]
+	randomSourcesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown random source %q, available: %v", name, RandomSources())
+	}
+	source, err := factory()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create random source %q: %w", name, err)
+	}
+	return source, nil
+}
+
+// SelfTest checks that a random source is usable before it is used for generation.
+// It detects broken sources (short reads, constant or repeated output, strong bias),
+// not subtle statistical weaknesses.
+func SelfTest(source RandomSource) error {
+	first := make([]byte, selfTestSize)
+	second := make([]byte, selfTestSize)
+	if _, err := io.ReadFull(source, first); err != nil {
+		return fmt.Errorf("random source %q: read failed: %w", source.Name(), err)
+	}
+	if _, err := io.ReadFull(source, second); err != nil {
+		return fmt.Errorf("random source %q: read failed: %w", source.Name(), err)
+	}
+
+	if bytes.Equal(first, second) {
+		return fmt.Errorf("random source %q: repeated output", source.Name())
+	}
+	if bytes.Count(first, first[:1]) == len(first) {
+		return fmt.Errorf("random source %q: constant output", source.Name())
+	}
+
+	ones := 0
+	for _, b := range first {
+		ones += bits.OnesCount8(b)
+	}
+	share := float64(ones) / float64(len(first)*8)
+	if share < 0.5-selfTestMaxBias || share > 0.5+selfTestMaxBias {
+		return fmt.Errorf("random source %q: biased output (%.1f%% one bits)", source.Name(), share*100)
+	}
+	return nil
+}
+
+// cryptoSource is the default RandomSource backed by crypto/rand
+type cryptoSource struct{}
+
+// Read implements io.Reader
+func (cryptoSource) Read(p []byte) (int, error) {
+	return rand.Read(p)
+}
+
+// Name implements RandomSource
+func (cryptoSource) Name() string {
+	return DefaultRandomSource
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import (
+	"crypto/rand"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testSource is a RandomSource that returns bytes from a function of the read offset
+type testSource struct {
+	name   string
+	offset int
+	next   func(offset int) byte
+	err    error
+}
+
+func (s *testSource) Read(p []byte) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	for i := range p {
+		p[i] = s.next(s.offset)
+		s.offset++
+	}
+	return len(p), nil
+}
+
+func (s *testSource) Name() string {
+	return s.name
+}
+
+// cryptoTestSource reads from crypto/rand under a custom name
+type cryptoTestSource struct{}
+
+func (cryptoTestSource) Read(p []byte) (int, error) { return rand.Read(p) }
+func (cryptoTestSource) Name() string               { return "test-hsm" }
+
+func TestSelfTest(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    RandomSource
+		wantError string
+	}{
+		{name: "crypto/rand", source: cryptoSource{}},
+		{
+			name:      "read error",
+			source:    &testSource{name: "broken", err: errors.New("device not ready")},
+			wantError: "read failed",
+		},
+		{
+			name:      "constant output",
+			source:    &testSource{name: "constant", next: func(int) byte { return 0x42 }},
+			wantError: "repeated output",
+		},
+		{
+			name:      "repeated output",
+			source:    &testSource{name: "repeating", next: func(offset int) byte { return byte(offset % selfTestSize) }},
+			wantError: "repeated output",
+		},
+		{
+			name:      "biased output",
+			source:    &testSource{name: "biased", next: func(offset int) byte { return byte(offset/3) | 0xF0 }},
+			wantError: "biased output",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SelfTest(tt.source)
+			if tt.wantError == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("expected error containing %q, got %v", tt.wantError, err)
+			}
+		})
+	}
+}
+
+func TestRegisterRandomSource(t *testing.T) {
+	RegisterRandomSource("test-hsm", func() (RandomSource, error) { return cryptoTestSource{}, nil })
+	RegisterRandomSource("test-unavailable", func() (RandomSource, error) { return nil, errors.New("no connection") })
+
+	if names := RandomSources(); !slices.Contains(names, "test-hsm") || !slices.Contains(names, DefaultRandomSource) {
+		t.Errorf("expected registered sources, got %v", names)
+	}
+
+	source, err := NewRandomSource("test-hsm")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	gen := NewSecretGeneratorWithSource(AlphanumericCharset, source)
+	if gen.RandomSource() != "test-hsm" {
+		t.Errorf("expected generator to use test-hsm, got %q", gen.RandomSource())
+	}
+	if value, err := gen.GenerateString(16); err != nil || len(value) != 16 {
+		t.Errorf("expected a 16 character value, got %q (%v)", value, err)
+	}
+
+	if _, err := NewRandomSource("test-unavailable"); err == nil || !strings.Contains(err.Error(), "no connection") {
+		t.Errorf("expected factory error, got %v", err)
+	}
+	if _, err := NewRandomSource("unknown"); err == nil {
+		t.Error("expected error for unknown source")
+	}
+}
+
+func TestGeneratorUsesRandomSource(t *testing.T) {
+	source := &testSource{name: "sequence", next: func(offset int) byte { return byte(offset) }}
+	gen := NewSecretGeneratorWithSource("abcd", source)
+
+	value, err := gen.GenerateString(6)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value != "abcdab" {
+		t.Errorf("expected value derived from the source, got %q", value)
+	}
+
+	failing := NewSecretGeneratorWithSource("abcd", &testSource{name: "broken", err: errors.New("device not ready")})
+	if _, err := failing.GenerateBytes(8); err == nil {
+		t.Error("expected error from failing source")
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTokenSignature is returned by ParseSignedToken if the token was not signed with the key
+var ErrTokenSignature = errors.New("token signature does not match the signing key")
+
+// signedTokenHeader is the JOSE header of signed tokens
+var signedTokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// TokenClaims are the claims of a signed token
+type TokenClaims struct {
+	// ID is a random identifier, so that tokens issued at the same time differ
+	ID        string `json:"jti"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Lifetime returns the validity period of the token
+func (c TokenClaims) Lifetime() time.Duration {
+	return time.Duration(c.ExpiresAt-c.IssuedAt) * time.Second
+}
+
+// GenerateSignedToken generates a JSON Web Token signed with HMAC-SHA256 (HS256) that is valid
+// for duration from issuedAt
+func GenerateSignedToken(key []byte, issuedAt time.Time, duration time.Duration) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, fmt.Errorf("signing key must not be empty")
+	}
+	if duration < time.Second {
+		return nil, fmt.Errorf("token duration must be at least 1s, got %s", duration)
+	}
+	id, err := randomBytes(16)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := json.Marshal(TokenClaims{
+		ID:        hex.EncodeToString(id),
+		IssuedAt:  issuedAt.Unix(),
+		ExpiresAt: issuedAt.Add(duration).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	signingInput := signedTokenHeader + "." + base64.RawURLEncoding.EncodeToString(claims)
+	return []byte(signingInput + "." + base64.RawURLEncoding.EncodeToString(signToken(key, signingInput))), nil
+}
+
+// ParseSignedToken parses a token generated by GenerateSignedToken and verifies its signature.
+// The expiry is not checked. If the signature does not match the key, the claims are returned
+// together with ErrTokenSignature.
+func ParseSignedToken(token, key []byte) (TokenClaims, error) {
+	var claims TokenClaims
+	parts := bytes.Split(token, []byte("."))
+	if len(parts) != 3 || string(parts[0]) != signedTokenHeader {
+		return claims, fmt.Errorf("not an HS256 JSON Web Token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(string(parts[1]))
+	if err != nil {
+		return claims, fmt.Errorf("invalid token claims: %w", err)
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return claims, fmt.Errorf("invalid token claims: %w", err)
+	}
+	if claims.ExpiresAt <= claims.IssuedAt {
+		return claims, fmt.Errorf("token expires before it is issued")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(string(parts[2]))
+	if err != nil {
+		return claims, fmt.Errorf("invalid token signature: %w", err)
+	}
+	if !hmac.Equal(signature, signToken(key, string(parts[0])+"."+string(parts[1]))) {
+		return claims, ErrTokenSignature
+	}
+	return claims, nil
+}
+
+// signToken returns the HMAC-SHA256 signature of the signing input
+func signToken(key []byte, signingInput string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
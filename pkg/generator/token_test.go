@@ -0,0 +1,82 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateSignedToken(t *testing.T) {
+	issuedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	key := []byte("signing-key")
+
+	token, err := GenerateSignedToken(key, issuedAt, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if parts := strings.Split(string(token), "."); len(parts) != 3 || parts[0] != "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9" {
+		t.Errorf("expected an HS256 JSON Web Token, got %q", token)
+	}
+
+	claims, err := ParseSignedToken(token, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claims.IssuedAt != issuedAt.Unix() || claims.ExpiresAt != issuedAt.Add(time.Hour).Unix() {
+		t.Errorf("unexpected claims %+v", claims)
+	}
+	if claims.Lifetime() != time.Hour {
+		t.Errorf("expected lifetime 1h, got %s", claims.Lifetime())
+	}
+	if claims.ID == "" {
+		t.Error("expected a token ID")
+	}
+
+	other, err := GenerateSignedToken(key, issuedAt, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(other) == string(token) {
+		t.Error("expected tokens issued at the same time to differ")
+	}
+
+	// A different key keeps the claims readable but fails the signature check
+	claims, err = ParseSignedToken(token, []byte("other-key"))
+	if !errors.Is(err, ErrTokenSignature) {
+		t.Errorf("expected ErrTokenSignature, got %v", err)
+	}
+	if claims.ExpiresAt != issuedAt.Add(time.Hour).Unix() {
+		t.Errorf("expected claims despite signature mismatch, got %+v", claims)
+	}
+}
+
+func TestSignedTokenErrors(t *testing.T) {
+	if _, err := GenerateSignedToken(nil, time.Now(), time.Hour); err == nil {
+		t.Error("expected error for empty key")
+	}
+	if _, err := GenerateSignedToken([]byte("key"), time.Now(), 0); err == nil {
+		t.Error("expected error for zero duration")
+	}
+	for _, token := range []string{"", "a.b", "a.b.c", "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.!!.c"} {
+		if _, err := ParseSignedToken([]byte(token), []byte("key")); err == nil || errors.Is(err, ErrTokenSignature) {
+			t.Errorf("expected parse error for %q, got %v", token, err)
+		}
+	}
+}
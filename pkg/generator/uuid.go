@@ -0,0 +1,34 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import "fmt"
+
+// UUIDLength is the number of characters of a generated UUID
+const UUIDLength = 36
+
+// GenerateUUID generates a random (version 4) UUID as defined in RFC 4122
+func GenerateUUID(g Generator) (string, error) {
+	b, err := g.GenerateBytes(16)
+	if err != nil {
+		return "", err
+	}
+	// Version 4 (random) and RFC 4122 variant
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
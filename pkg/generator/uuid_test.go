@@ -0,0 +1,42 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestGenerateUUID(t *testing.T) {
+	g := NewSecretGenerator()
+	pattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		uuid, err := GenerateUUID(g)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(uuid) != UUIDLength || !pattern.MatchString(uuid) {
+			t.Fatalf("%q is not a version 4 UUID", uuid)
+		}
+		if seen[uuid] {
+			t.Fatalf("duplicate UUID %q", uuid)
+		}
+		seen[uuid] = true
+	}
+}
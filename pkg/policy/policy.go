@@ -0,0 +1,200 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy evaluates the cluster policies that restrict the features Secrets may use
+// by namespace. The same evaluation is used by the admission webhook and the reconcilers.
+package policy
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/annotations"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+var (
+	// GenerationFeatures are the features enforced by the generation controller
+	GenerationFeatures = []string{
+		config.PolicyFeatureAutogenerate,
+		config.PolicyFeatureRotate,
+		config.PolicyFeatureTTL,
+	}
+
+	// ReplicationFeatures are the features enforced by the replication controller
+	ReplicationFeatures = []string{
+		config.PolicyFeatureReplicateTo,
+		config.PolicyFeatureReplicateFrom,
+		config.PolicyFeatureReplicatableFrom,
+		config.PolicyFeatureReplicateAs,
+	}
+)
+
+// Violation is a feature a Secret uses although a policy rule denies it
+type Violation struct {
+	// Rule is the name of the violated rule
+	Rule string
+	// Feature is the restricted feature
+	Feature string
+	// Message explains the violation
+	Message string
+}
+
+// String returns the violation as a human-readable message
+func (v Violation) String() string {
+	if v.Rule == "" {
+		return v.Message
+	}
+	return fmt.Sprintf("policy %s: %s", v.Rule, v.Message)
+}
+
+// NeedsNamespaceLabels returns true if any rule selects namespaces by label
+func NeedsNamespaceLabels(rules []config.PolicyRule) bool {
+	for _, rule := range rules {
+		if rule.NamespaceSelector != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Features returns the features a Secret uses, in the order of config.PolicyFeatures
+func Features(secret *corev1.Secret) []string {
+	var features []string
+	has := func(key string) bool { return secret.Annotations[key] != "" }
+	hasPrefix := func(prefix string) bool {
+		for key, value := range secret.Annotations {
+			if strings.HasPrefix(key, prefix) && value != "" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if has(annotations.Autogenerate) || has(annotations.Adopt) {
+		features = append(features, config.PolicyFeatureAutogenerate)
+	}
+	if has(annotations.Rotate) || hasPrefix(annotations.RotatePrefix) {
+		features = append(features, config.PolicyFeatureRotate)
+	}
+	if has(annotations.TTL) {
+		features = append(features, config.PolicyFeatureTTL)
+	}
+	if has(replicator.AnnotationReplicateTo) {
+		features = append(features, config.PolicyFeatureReplicateTo)
+	}
+	if replicator.IsPullTarget(secret) {
+		features = append(features, config.PolicyFeatureReplicateFrom)
+	}
+	if replicator.AllowsReplication(secret) {
+		features = append(features, config.PolicyFeatureReplicatableFrom)
+	}
+	if has(replicator.AnnotationReplicateAs) {
+		features = append(features, config.PolicyFeatureReplicateAs)
+	}
+	return features
+}
+
+// Evaluate checks the Secret against all rules that apply to its namespace and returns the
+// violations of the given features. The namespace labels are only needed for rules with a
+// namespace selector; if they are nil, such rules do not apply.
+func Evaluate(rules []config.PolicyRule, secret *corev1.Secret, namespaceLabels map[string]string, features []string) []Violation {
+	used := Features(secret)
+	var violations []Violation
+	for _, rule := range rules {
+		if !Applies(rule, secret.Namespace, namespaceLabels) || isExempt(rule, secret.Labels) {
+			continue
+		}
+
+		for _, feature := range rule.DeniedFeatures {
+			if slices.Contains(features, feature) && slices.Contains(used, feature) {
+				violations = append(violations, Violation{
+					Rule:    rule.Name,
+					Feature: feature,
+					Message: fmt.Sprintf("feature %s is not allowed in namespace %s", feature, secret.Namespace),
+				})
+			}
+		}
+
+		minInterval := rule.MinRotationInterval.Duration()
+		if minInterval > 0 && slices.Contains(features, config.PolicyFeatureRotate) {
+			if key, interval, ok := shortestRotationInterval(secret.Annotations); ok && interval < minInterval {
+				violations = append(violations, Violation{
+					Rule:    rule.Name,
+					Feature: config.PolicyFeatureRotate,
+					Message: fmt.Sprintf("rotation interval %s of %s is below the minimum of %s in namespace %s",
+						interval, key, minInterval, secret.Namespace),
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// Applies returns true if the rule applies to the given namespace
+func Applies(rule config.PolicyRule, namespace string, namespaceLabels map[string]string) bool {
+	if len(rule.Namespaces) > 0 && !replicator.MatchesAnyNamespace(namespace, rule.Namespaces) {
+		return false
+	}
+	if replicator.MatchesAnyNamespace(namespace, rule.ExcludeNamespaces) {
+		return false
+	}
+	if rule.NamespaceSelector == "" {
+		return true
+	}
+	if namespaceLabels == nil {
+		return false
+	}
+	// The selector is validated when the configuration is loaded
+	selector, err := labels.Parse(rule.NamespaceSelector)
+	return err == nil && selector.Matches(labels.Set(namespaceLabels))
+}
+
+// isExempt returns true if the Secret labels carry the exempt label of the rule
+func isExempt(rule config.PolicyRule, secretLabels map[string]string) bool {
+	if rule.ExemptLabel == "" {
+		return false
+	}
+	key, value, hasValue := strings.Cut(rule.ExemptLabel, "=")
+	actual, ok := secretLabels[key]
+	return ok && (!hasValue || actual == value)
+}
+
+// shortestRotationInterval returns the shortest valid rotation interval of all rotate annotations.
+// Invalid intervals are reported by the generation controller and ignored here.
+func shortestRotationInterval(secretAnnotations map[string]string) (string, time.Duration, bool) {
+	var shortestKey string
+	var shortest time.Duration
+	for key, value := range secretAnnotations {
+		if key != annotations.Rotate && !strings.HasPrefix(key, annotations.RotatePrefix) {
+			continue
+		}
+		interval, err := config.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			continue
+		}
+		if shortestKey == "" || interval < shortest || (interval == shortest && key < shortestKey) {
+			shortestKey, shortest = key, interval
+		}
+	}
+	return shortestKey, shortest, shortestKey != ""
+}
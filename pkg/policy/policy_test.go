@@ -0,0 +1,173 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/annotations"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+)
+
+func newSecret(namespace string, secretLabels, secretAnnotations map[string]string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Namespace:   namespace,
+			Labels:      secretLabels,
+			Annotations: secretAnnotations,
+		},
+	}
+}
+
+func TestFeatures(t *testing.T) {
+	secret := newSecret("default", nil, map[string]string{
+		annotations.Autogenerate:                          "password",
+		annotations.RotatePrefix + "password":             "1h",
+		replicator.AnnotationReplicateTo:                  "staging",
+		replicator.AnnotationReplicateAs:                  "{{ .Namespace }}-app",
+		replicator.AnnotationReplicateFromKeyPrefix + "a": "production/db#password",
+	})
+
+	expected := []string{
+		config.PolicyFeatureAutogenerate,
+		config.PolicyFeatureRotate,
+		config.PolicyFeatureReplicateTo,
+		config.PolicyFeatureReplicateFrom,
+		config.PolicyFeatureReplicateAs,
+	}
+	if features := Features(secret); !reflect.DeepEqual(features, expected) {
+		t.Errorf("expected features %v, got %v", expected, features)
+	}
+
+	// Adopted fields are generated from then on
+	adopting := newSecret("default", nil, map[string]string{annotations.Adopt: "password"})
+	if features := Features(adopting); !reflect.DeepEqual(features, []string{config.PolicyFeatureAutogenerate}) {
+		t.Errorf("expected adopt to use the autogenerate feature, got %v", features)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	rules := []config.PolicyRule{
+		{
+			Name:              "push-from-infra-only",
+			ExcludeNamespaces: []string{"infra-*"},
+			DeniedFeatures:    []string{config.PolicyFeatureReplicateTo},
+		},
+		{
+			Name:                "fast-rotation-requires-label",
+			MinRotationInterval: config.Duration(24 * time.Hour),
+			ExemptLabel:         "fast-rotation=approved",
+		},
+		{
+			Name:              "no-templates-for-tenants",
+			NamespaceSelector: "tenant=true",
+			DeniedFeatures:    []string{config.PolicyFeatureReplicateAs},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		secret          *corev1.Secret
+		namespaceLabels map[string]string
+		features        []string
+		expectFeatures  []string
+		expectMessage   string
+	}{
+		{
+			name:   "push from infra namespace",
+			secret: newSecret("infra-certs", nil, map[string]string{replicator.AnnotationReplicateTo: "*"}),
+		},
+		{
+			name:           "push from other namespace",
+			secret:         newSecret("team-a", nil, map[string]string{replicator.AnnotationReplicateTo: "*"}),
+			expectFeatures: []string{config.PolicyFeatureReplicateTo},
+			expectMessage:  "policy push-from-infra-only: feature replicate-to is not allowed in namespace team-a",
+		},
+		{
+			name:     "push not checked by generation features",
+			secret:   newSecret("team-a", nil, map[string]string{replicator.AnnotationReplicateTo: "*"}),
+			features: GenerationFeatures,
+		},
+		{
+			name:           "short rotation interval",
+			secret:         newSecret("team-a", nil, map[string]string{annotations.Rotate: "7d", annotations.RotatePrefix + "token": "1h"}),
+			expectFeatures: []string{config.PolicyFeatureRotate},
+			expectMessage:  "rotation interval 1h0m0s of iso.gtrfc.com/rotate.token is below the minimum of 24h0m0s",
+		},
+		{
+			name:   "short rotation interval with exempt label",
+			secret: newSecret("team-a", map[string]string{"fast-rotation": "approved"}, map[string]string{annotations.Rotate: "1h"}),
+		},
+		{
+			name:           "short rotation interval with wrong exempt label value",
+			secret:         newSecret("team-a", map[string]string{"fast-rotation": "pending"}, map[string]string{annotations.Rotate: "1h"}),
+			expectFeatures: []string{config.PolicyFeatureRotate},
+		},
+		{
+			name:   "rotation interval at the minimum",
+			secret: newSecret("team-a", nil, map[string]string{annotations.Rotate: "24h"}),
+		},
+		{
+			name:            "template in tenant namespace",
+			secret:          newSecret("infra-a", nil, map[string]string{replicator.AnnotationReplicateAs: "{{ .Namespace }}-app"}),
+			namespaceLabels: map[string]string{"tenant": "true"},
+			expectFeatures:  []string{config.PolicyFeatureReplicateAs},
+		},
+		{
+			name:   "template without namespace labels",
+			secret: newSecret("infra-a", nil, map[string]string{replicator.AnnotationReplicateAs: "{{ .Namespace }}-app"}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			features := tt.features
+			if features == nil {
+				features = config.PolicyFeatures
+			}
+			violations := Evaluate(rules, tt.secret, tt.namespaceLabels, features)
+
+			var violated []string
+			for _, violation := range violations {
+				violated = append(violated, violation.Feature)
+			}
+			if !reflect.DeepEqual(violated, tt.expectFeatures) {
+				t.Fatalf("expected violations of %v, got %v", tt.expectFeatures, violations)
+			}
+			if tt.expectMessage != "" && !strings.Contains(violations[0].String(), tt.expectMessage) {
+				t.Errorf("expected message containing %q, got %q", tt.expectMessage, violations[0].String())
+			}
+		})
+	}
+}
+
+func TestNeedsNamespaceLabels(t *testing.T) {
+	if NeedsNamespaceLabels([]config.PolicyRule{{Namespaces: []string{"team-*"}}}) {
+		t.Error("expected rules without selector to not need namespace labels")
+	}
+	if !NeedsNamespaceLabels([]config.PolicyRule{{}, {NamespaceSelector: "tenant=true"}}) {
+		t.Error("expected rules with selector to need namespace labels")
+	}
+}
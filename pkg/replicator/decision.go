@@ -0,0 +1,166 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicator
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+// Decision is the outcome of a replication check
+type Decision struct {
+	// Allowed is true if the replication may proceed
+	Allowed bool
+	// Reason explains why the replication is denied; empty if it is allowed
+	Reason string
+}
+
+// Allow returns a Decision that allows the replication
+func Allow() Decision {
+	return Decision{Allowed: true}
+}
+
+// Deny returns a Decision that denies the replication with a formatted reason
+func Deny(format string, args ...interface{}) Decision {
+	return Decision{Reason: fmt.Sprintf(format, args...)}
+}
+
+// DecideNamespace checks a namespace against the cluster-wide replication denylist.
+// It applies to both pull sources and push targets.
+func DecideNamespace(namespace string, deniedNamespaces []string) Decision {
+	if MatchesAnyNamespace(namespace, deniedNamespaces) {
+		return Deny("namespace %s is on the replication denylist", namespace)
+	}
+	return Allow()
+}
+
+// DecidePullConsent checks if a source Secret allows replication into the target namespace,
+// either by its namespace allowlist or by its namespace label selector. The labels of the target
+// namespace are only needed for the selector; if they are nil, the selector does not match.
+func DecidePullConsent(source *corev1.Secret, targetNamespace string, targetNamespaceLabels map[string]string) Decision {
+	allowlist := source.Annotations[AnnotationReplicatableFromNamespaces]
+	selector := source.Annotations[AnnotationReplicatableFromSelector]
+
+	if selector == "" {
+		if allowed, err := ValidateReplication(source.Namespace, allowlist, targetNamespace); !allowed {
+			return Deny("%v", err)
+		}
+		return Allow()
+	}
+
+	// The name allowlist and the selector are alternatives, matching either one is sufficient
+	if allowlist != "" {
+		if allowed, _ := ValidateReplication(source.Namespace, allowlist, targetNamespace); allowed {
+			return Allow()
+		}
+	}
+
+	matched, err := MatchNamespaceSelector(selector, targetNamespaceLabels)
+	if err != nil {
+		return Deny("%v", err)
+	}
+	if !matched {
+		return Deny("target namespace %q does not match source selector %q", targetNamespace, selector)
+	}
+	return Allow()
+}
+
+// ConsentExpiry returns the end of the consent of a source Secret from its replicatable-until
+// annotation, or nil if the consent does not expire
+func ConsentExpiry(source *corev1.Secret) (*time.Time, error) {
+	value, ok := source.Annotations[AnnotationReplicatableUntil]
+	if !ok {
+		return nil, nil
+	}
+	until, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: must be an RFC3339 timestamp", AnnotationReplicatableUntil, value)
+	}
+	return &until, nil
+}
+
+// DecideConsentExpiry checks if the consent of a source Secret has expired. An invalid
+// replicatable-until annotation denies the replication, since the intended end is unknown.
+func DecideConsentExpiry(source *corev1.Secret, now time.Time) Decision {
+	until, err := ConsentExpiry(source)
+	if err != nil {
+		return Deny("%v", err)
+	}
+	if until != nil && !now.Before(*until) {
+		return Deny("consent of %s/%s expired at %s", source.Namespace, source.Name, until.UTC().Format(time.RFC3339))
+	}
+	return Allow()
+}
+
+// DecidePushTarget checks if a source Secret may be pushed to the target namespace.
+// Namespaces matching the replicate-exclude-namespaces annotation or the denylist are skipped.
+func DecidePushTarget(source *corev1.Secret, targetNamespace string, deniedNamespaces []string) Decision {
+	excluded := ParseTargetNamespaces(source.Annotations[AnnotationReplicateExcludeNamespaces])
+	if MatchesAnyNamespace(targetNamespace, excluded) {
+		return Deny("namespace %s is excluded by %s", targetNamespace, AnnotationReplicateExcludeNamespaces)
+	}
+	return DecideNamespace(targetNamespace, deniedNamespaces)
+}
+
+// DecideOverwrite checks if an existing target Secret may be overwritten by push replication
+// from sourceRef. Secrets with an ignored type and Secrets not replicated from the source are
+// owned by other components and never overwritten.
+func DecideOverwrite(target *corev1.Secret, sourceRef string, typeIgnored bool) Decision {
+	if typeIgnored {
+		return Deny("Secret %s/%s has ignored type %s and is not overwritten", target.Namespace, target.Name, target.Type)
+	}
+	if !IsOwnedByUs(target, sourceRef) {
+		return Deny("Secret %s/%s already exists and is not owned by this replication (no replicated-from annotation)",
+			target.Namespace, target.Name)
+	}
+	return Allow()
+}
+
+// DecideBoundary checks the cluster-wide replication boundaries between the source and target
+// namespaces. Missing labels are treated as empty, so selectors like "env!=prod" still match.
+func DecideBoundary(
+	sourceNamespace string,
+	sourceLabels map[string]string,
+	targetNamespace string,
+	targetLabels map[string]string,
+	boundaries []config.ReplicationBoundary,
+) Decision {
+	for _, boundary := range boundaries {
+		// The selectors are validated when the configuration is loaded
+		from, err := labels.Parse(boundary.From)
+		if err != nil {
+			return Deny("invalid replication boundary %s: %v", boundary.Name, err)
+		}
+		to, err := labels.Parse(boundary.To)
+		if err != nil {
+			return Deny("invalid replication boundary %s: %v", boundary.Name, err)
+		}
+		if from.Matches(labels.Set(sourceLabels)) && to.Matches(labels.Set(targetLabels)) {
+			name := boundary.Name
+			if name == "" {
+				name = fmt.Sprintf("%s -> %s", boundary.From, boundary.To)
+			}
+			return Deny("replication from namespace %s to %s crosses the boundary %s", sourceNamespace, targetNamespace, name)
+		}
+	}
+	return Allow()
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicator
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+)
+
+func sourceWithAnnotations(annotations map[string]string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "source", Namespace: "production", Annotations: annotations},
+	}
+}
+
+func TestDecidePullConsent(t *testing.T) {
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		labels        map[string]string
+		expectAllowed bool
+		expectReason  string
+	}{
+		{
+			name:         "no consent",
+			annotations:  map[string]string{},
+			expectReason: "does not have",
+		},
+		{
+			name:          "allowlist matches",
+			annotations:   map[string]string{AnnotationReplicatableFromNamespaces: "staging,dev-*"},
+			expectAllowed: true,
+		},
+		{
+			name:         "allowlist does not match",
+			annotations:  map[string]string{AnnotationReplicatableFromNamespaces: "dev-*"},
+			expectReason: "not in source allowlist",
+		},
+		{
+			name:          "selector matches",
+			annotations:   map[string]string{AnnotationReplicatableFromSelector: "env=staging"},
+			labels:        map[string]string{"env": "staging"},
+			expectAllowed: true,
+		},
+		{
+			name:         "selector without labels",
+			annotations:  map[string]string{AnnotationReplicatableFromSelector: "env=staging"},
+			expectReason: "does not match source selector",
+		},
+		{
+			name: "allowlist matches although selector does not",
+			annotations: map[string]string{
+				AnnotationReplicatableFromNamespaces: "staging",
+				AnnotationReplicatableFromSelector:   "env=prod",
+			},
+			expectAllowed: true,
+		},
+		{
+			name:         "invalid selector",
+			annotations:  map[string]string{AnnotationReplicatableFromSelector: "env in (("},
+			labels:       map[string]string{},
+			expectReason: "invalid namespace selector",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := DecidePullConsent(sourceWithAnnotations(tt.annotations), "staging", tt.labels)
+			if decision.Allowed != tt.expectAllowed {
+				t.Errorf("expected allowed=%v, got %+v", tt.expectAllowed, decision)
+			}
+			if !strings.Contains(decision.Reason, tt.expectReason) {
+				t.Errorf("expected reason containing %q, got %q", tt.expectReason, decision.Reason)
+			}
+		})
+	}
+}
+
+func TestDecidePushTarget(t *testing.T) {
+	source := sourceWithAnnotations(map[string]string{AnnotationReplicateExcludeNamespaces: "kube-*"})
+	denied := []string{"secure-*"}
+
+	if decision := DecidePushTarget(source, "staging", denied); !decision.Allowed {
+		t.Errorf("expected staging to be allowed, got %+v", decision)
+	}
+	if decision := DecidePushTarget(source, "kube-system", denied); decision.Allowed || !strings.Contains(decision.Reason, "excluded") {
+		t.Errorf("expected kube-system to be excluded, got %+v", decision)
+	}
+	if decision := DecidePushTarget(source, "secure-vault", denied); decision.Allowed || !strings.Contains(decision.Reason, "denylist") {
+		t.Errorf("expected secure-vault to be denied, got %+v", decision)
+	}
+}
+
+func TestDecideConsentExpiry(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	if decision := DecideConsentExpiry(sourceWithAnnotations(nil), now); !decision.Allowed {
+		t.Errorf("expected consent without replicatable-until not to expire, got %+v", decision)
+	}
+	source := sourceWithAnnotations(map[string]string{AnnotationReplicatableUntil: "2025-12-31T00:00:00Z"})
+	if decision := DecideConsentExpiry(source, now); !decision.Allowed {
+		t.Errorf("expected consent to be valid before replicatable-until, got %+v", decision)
+	}
+	if decision := DecideConsentExpiry(source, time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)); decision.Allowed ||
+		!strings.Contains(decision.Reason, "expired") {
+		t.Errorf("expected consent to expire at replicatable-until, got %+v", decision)
+	}
+	invalid := sourceWithAnnotations(map[string]string{AnnotationReplicatableUntil: "next year"})
+	if decision := DecideConsentExpiry(invalid, now); decision.Allowed || !strings.Contains(decision.Reason, "RFC3339") {
+		t.Errorf("expected invalid replicatable-until to deny replication, got %+v", decision)
+	}
+}
+
+func TestDecideOverwrite(t *testing.T) {
+	owned := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:        "target",
+		Namespace:   "staging",
+		Annotations: map[string]string{AnnotationReplicatedFrom: "production/source"},
+	}}
+	foreign := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "target", Namespace: "staging"}}
+
+	if decision := DecideOverwrite(owned, "production/source", false); !decision.Allowed {
+		t.Errorf("expected owned target to be overwritten, got %+v", decision)
+	}
+	if decision := DecideOverwrite(owned, "production/other", false); decision.Allowed {
+		t.Error("expected target replicated from another source not to be overwritten")
+	}
+	if decision := DecideOverwrite(foreign, "production/source", false); decision.Allowed || !strings.Contains(decision.Reason, "not owned") {
+		t.Errorf("expected foreign target not to be overwritten, got %+v", decision)
+	}
+	if decision := DecideOverwrite(owned, "production/source", true); decision.Allowed || !strings.Contains(decision.Reason, "ignored type") {
+		t.Errorf("expected target with ignored type not to be overwritten, got %+v", decision)
+	}
+}
+
+func TestDecideBoundary(t *testing.T) {
+	boundaries := []config.ReplicationBoundary{{Name: "prod-stays-prod", From: "env=prod", To: "env!=prod"}}
+	prod := map[string]string{"env": "prod"}
+	dev := map[string]string{"env": "dev"}
+
+	if decision := DecideBoundary("production", prod, "staging", prod, boundaries); !decision.Allowed {
+		t.Errorf("expected replication within prod to be allowed, got %+v", decision)
+	}
+	if decision := DecideBoundary("development", dev, "production", prod, boundaries); !decision.Allowed {
+		t.Errorf("expected replication from dev to prod to be allowed, got %+v", decision)
+	}
+	if decision := DecideBoundary("production", prod, "development", dev, boundaries); decision.Allowed || !strings.Contains(decision.Reason, "prod-stays-prod") {
+		t.Errorf("expected replication from prod to dev to be denied, got %+v", decision)
+	}
+	if decision := DecideBoundary("production", prod, "unlabeled", nil, boundaries); decision.Allowed {
+		t.Error("expected replication from prod to an unlabeled namespace to be denied")
+	}
+	if decision := DecideBoundary("production", prod, "development", dev, nil); !decision.Allowed {
+		t.Errorf("expected replication without boundaries to be allowed, got %+v", decision)
+	}
+}
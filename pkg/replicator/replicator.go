@@ -17,56 +17,233 @@ limitations under the License.
 package replicator
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"text/template"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/annotations"
 )
 
 const (
 	// AnnotationPrefix is the prefix for all replication annotations
-	AnnotationPrefix = "iso.gtrfc.com/"
+	AnnotationPrefix = annotations.Prefix
 
 	// AnnotationReplicatableFromNamespaces allowlist of namespaces that can replicate FROM this Secret
 	AnnotationReplicatableFromNamespaces = AnnotationPrefix + "replicatable-from-namespaces"
 
-	// AnnotationReplicateFrom source Secret to replicate data from (format: "namespace/secret-name")
+	// AnnotationReplicatableFromSelector label selector for namespaces that can replicate FROM this Secret
+	// (e.g. "env=staging"). It is an alternative to AnnotationReplicatableFromNamespaces.
+	AnnotationReplicatableFromSelector = AnnotationPrefix + "replicatable-from-selector"
+
+	// AnnotationReplicatableUntil ends the consent of a source Secret at an RFC3339 timestamp, for
+	// time-boxed sharing. Afterwards, new syncs are denied and existing targets are marked stale.
+	AnnotationReplicatableUntil = AnnotationPrefix + "replicatable-until"
+
+	// AnnotationReplicateFrom source Secret(s) to replicate data from
+	// (format: "namespace/secret-name", comma-separated for multiple sources)
 	AnnotationReplicateFrom = AnnotationPrefix + "replicate-from"
 
+	// AnnotationReplicateFromKeyPrefix pulls a single key from a source Secret.
+	// The annotation "replicate-from.<key>" has the format "namespace/secret-name#sourceKey";
+	// if "#sourceKey" is omitted, the source key with the same name is used.
+	AnnotationReplicateFromKeyPrefix = AnnotationReplicateFrom + "."
+
 	// AnnotationReplicateTo push this secret to specified namespaces (comma-separated)
 	AnnotationReplicateTo = AnnotationPrefix + "replicate-to"
 
+	// AnnotationReplicateExcludeNamespaces namespaces (comma-separated glob patterns) that are excluded
+	// from push replication, mainly used together with replicate-to "*"
+	AnnotationReplicateExcludeNamespaces = AnnotationPrefix + "replicate-exclude-namespaces"
+
+	// AnnotationReplicateAs name template for pushed Secrets (e.g. "{{ .Namespace }}-registry-creds").
+	// The template can use .Namespace (target namespace), .Name and .SourceNamespace (source Secret).
+	AnnotationReplicateAs = AnnotationPrefix + "replicate-as"
+
+	// ReplicateToAllNamespaces is the replicate-to value that pushes a Secret to all namespaces
+	ReplicateToAllNamespaces = "*"
+
 	// AnnotationReplicatedFrom indicates this Secret was replicated from another Secret
 	AnnotationReplicatedFrom = AnnotationPrefix + "replicated-from"
 
 	// AnnotationLastReplicatedAt timestamp of last replication
 	AnnotationLastReplicatedAt = AnnotationPrefix + "last-replicated-at"
 
+	// AnnotationSourceDeletedAt timestamp when a source of a pull target was deleted.
+	// The target keeps its last known data (snapshot) but no longer receives updates.
+	AnnotationSourceDeletedAt = AnnotationPrefix + "source-deleted-at"
+
+	// AnnotationConsentExpiredAt timestamp when the replicatable-until consent of a source expired.
+	// Like after a source deletion, the target keeps its last known data but no longer receives updates.
+	AnnotationConsentExpiredAt = AnnotationPrefix + "consent-expired-at"
+
+	// AnnotationReplicatedChecksum SHA-256 checksum of the target data as last written by replication.
+	// It is used to detect manual modifications of replicated data.
+	AnnotationReplicatedChecksum = AnnotationPrefix + "replicated-checksum"
+
 	// FinalizerReplicateToCleanup finalizer for cleaning up pushed Secrets
 	FinalizerReplicateToCleanup = AnnotationPrefix + "replicate-to-cleanup"
 )
 
-// ReplicateSecret copies data from source Secret to target Secret
-func ReplicateSecret(source, target *corev1.Secret) {
+// ReplicateSecret copies data from source Secret to target Secret.
+// now is recorded as the time of the replication.
+func ReplicateSecret(source, target *corev1.Secret, now time.Time) {
+	ReplicateSecrets([]*corev1.Secret{source}, target, now)
+}
+
+// ReplicateSecrets merges data from multiple source Secrets into the target Secret.
+// Sources are applied in order, so if several sources contain the same key, the last source wins.
+func ReplicateSecrets(sources []*corev1.Secret, target *corev1.Secret, now time.Time) {
 	// Initialize target data if nil
 	if target.Data == nil {
 		target.Data = make(map[string][]byte)
 	}
 
-	// Copy all data from source to target (overwrite existing)
-	for key, value := range source.Data {
-		target.Data[key] = value
+	if target.Annotations == nil {
+		target.Annotations = make(map[string]string)
+	}
+
+	// Copy all data from the sources to target (overwrite existing)
+	sourceRefs := make([]string, 0, len(sources))
+	for _, source := range sources {
+		for key, value := range source.Data {
+			target.Data[key] = value
+			annotations.SetOrigin(target.Annotations, key, annotations.OriginReplicated)
+		}
+		sourceRefs = append(sourceRefs, fmt.Sprintf("%s/%s", source.Namespace, source.Name))
 	}
 
 	// Add replication status annotations
+	target.Annotations[AnnotationReplicatedFrom] = strings.Join(sourceRefs, ",")
+	target.Annotations[AnnotationLastReplicatedAt] = now.Format(time.RFC3339)
+	RecordChecksums(target)
+
+	// The target receives updates again, so it is no longer stale
+	delete(target.Annotations, AnnotationSourceDeletedAt)
+	delete(target.Annotations, AnnotationConsentExpiredAt)
+}
+
+// ReplicateKeys copies individually referenced keys from the source Secrets into the target Secret.
+// sources maps source references ("namespace/secret-name") to the fetched source Secrets.
+// Sources that are not yet listed in the replicated-from annotation are appended to it.
+func ReplicateKeys(sources map[string]*corev1.Secret, keyRefs map[string]KeyReference, target *corev1.Secret, now time.Time) error {
+	if len(keyRefs) == 0 {
+		return nil
+	}
+
+	// Initialize target data and annotations if nil
+	if target.Data == nil {
+		target.Data = make(map[string][]byte)
+	}
 	if target.Annotations == nil {
 		target.Annotations = make(map[string]string)
 	}
-	target.Annotations[AnnotationReplicatedFrom] = fmt.Sprintf("%s/%s", source.Namespace, source.Name)
-	target.Annotations[AnnotationLastReplicatedAt] = time.Now().Format(time.RFC3339)
+
+	var sourceRefs []string
+	if replicatedFrom := target.Annotations[AnnotationReplicatedFrom]; replicatedFrom != "" {
+		sourceRefs = strings.Split(replicatedFrom, ",")
+	}
+
+	// Process keys in a stable order so errors and annotations are deterministic
+	targetKeys := make([]string, 0, len(keyRefs))
+	for targetKey := range keyRefs {
+		targetKeys = append(targetKeys, targetKey)
+	}
+	sort.Strings(targetKeys)
+
+	for _, targetKey := range targetKeys {
+		ref := keyRefs[targetKey]
+		source, ok := sources[ref.Source]
+		if !ok {
+			return fmt.Errorf("source Secret %s for key %q was not fetched", ref.Source, targetKey)
+		}
+		value, ok := source.Data[ref.Key]
+		if !ok {
+			return fmt.Errorf("source Secret %s has no key %q (referenced by key %q)", ref.Source, ref.Key, targetKey)
+		}
+		target.Data[targetKey] = value
+		annotations.SetOrigin(target.Annotations, targetKey, annotations.OriginReplicated)
+
+		if !slices.Contains(sourceRefs, ref.Source) {
+			sourceRefs = append(sourceRefs, ref.Source)
+		}
+	}
+
+	target.Annotations[AnnotationReplicatedFrom] = strings.Join(sourceRefs, ",")
+	target.Annotations[AnnotationLastReplicatedAt] = now.Format(time.RFC3339)
+	RecordChecksums(target)
+	return nil
+}
+
+// DataChecksum returns a hex-encoded SHA-256 checksum over the keys and values of Secret data
+func DataChecksum(data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	for _, key := range keys {
+		// Length prefixes keep the encoding unambiguous
+		_, _ = fmt.Fprintf(hash, "%d:%s%d:", len(key), key, len(data[key]))
+		_, _ = hash.Write(data[key])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// RecordChecksums records the checksum of the replicated data for drift detection and as
+// data-checksum for consumers
+func RecordChecksums(target *corev1.Secret) {
+	checksum := DataChecksum(target.Data)
+	target.Annotations[AnnotationReplicatedChecksum] = checksum
+	target.Annotations[annotations.DataChecksum] = checksum
+}
+
+// HasDrifted checks if the data of a replicated Secret was modified since it was last replicated.
+// Secrets without a recorded checksum (replicated by older versions) are never considered drifted.
+func HasDrifted(target *corev1.Secret) bool {
+	checksum := target.Annotations[AnnotationReplicatedChecksum]
+	return checksum != "" && checksum != DataChecksum(target.Data)
+}
+
+// typeRequiredKeys lists the data keys required by typed Secrets.
+// Each entry is a group of keys of which at least one must be present.
+var typeRequiredKeys = map[corev1.SecretType][][]string{
+	corev1.SecretTypeTLS:              {{corev1.TLSCertKey}, {corev1.TLSPrivateKeyKey}},
+	corev1.SecretTypeBasicAuth:        {{corev1.BasicAuthUsernameKey, corev1.BasicAuthPasswordKey}},
+	corev1.SecretTypeSSHAuth:          {{corev1.SSHAuthPrivateKey}},
+	corev1.SecretTypeDockerConfigJson: {{corev1.DockerConfigJsonKey}},
+	corev1.SecretTypeDockercfg:        {{corev1.DockerConfigKey}},
+}
+
+// ValidateTypeKeys checks that a Secret contains the (non-empty) keys required by its type.
+// This allows projecting data from a source into a target of a different type
+// (e.g. from an Opaque source into a kubernetes.io/tls target).
+func ValidateTypeKeys(secret *corev1.Secret) error {
+	for _, group := range typeRequiredKeys[secret.Type] {
+		found := false
+		for _, key := range group {
+			if len(secret.Data[key]) > 0 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("secret type %s requires key %s", secret.Type, strings.Join(group, " or "))
+		}
+	}
+	return nil
 }
 
 // ValidateReplication checks if replication is allowed (mutual consent)
@@ -97,6 +274,25 @@ func ValidateReplication(sourceNamespace string, sourceAllowlist string, targetN
 	return false, fmt.Errorf("target namespace %q is not in source allowlist %q", targetNamespace, sourceAllowlist)
 }
 
+// MatchNamespaceSelector checks if namespace labels match a label selector (e.g. "env=staging,tier!=db")
+func MatchNamespaceSelector(selector string, namespaceLabels map[string]string) (bool, error) {
+	parsed, err := labels.Parse(selector)
+	if err != nil {
+		return false, fmt.Errorf("invalid namespace selector %q: %w", selector, err)
+	}
+	if parsed.Empty() {
+		return false, fmt.Errorf("namespace selector %q must not be empty", selector)
+	}
+	return parsed.Matches(labels.Set(namespaceLabels)), nil
+}
+
+// AllowsReplication checks if a Secret can be used as pull source
+// (has a namespace allowlist or a namespace selector)
+func AllowsReplication(secret *corev1.Secret) bool {
+	return secret.Annotations[AnnotationReplicatableFromNamespaces] != "" ||
+		secret.Annotations[AnnotationReplicatableFromSelector] != ""
+}
+
 // MatchNamespace checks if a namespace matches a glob pattern
 // Supports glob patterns: *, ?, [abc], [a-z], [0-9]
 func MatchNamespace(namespace, pattern string) (bool, error) {
@@ -126,6 +322,210 @@ func ParseSourceReference(sourceRef string) (namespace, name string, err error)
 	return namespace, name, nil
 }
 
+// ParseSourceReferences parses a comma-separated list of "namespace/secret-name" references.
+// The references are returned in normalized form and in the given order. Duplicates are rejected.
+func ParseSourceReferences(sourceRefs string) ([]string, error) {
+	var result []string
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(sourceRefs, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		namespace, name, err := ParseSourceReference(part)
+		if err != nil {
+			return nil, err
+		}
+		ref := fmt.Sprintf("%s/%s", namespace, name)
+		if seen[ref] {
+			return nil, fmt.Errorf("duplicate source reference %q", ref)
+		}
+		seen[ref] = true
+		result = append(result, ref)
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("invalid source reference: no source specified")
+	}
+
+	return result, nil
+}
+
+// KeyReference references a single key of a source Secret
+type KeyReference struct {
+	// Source is the source Secret reference ("namespace/secret-name")
+	Source string
+	// Key is the key in the source Secret
+	Key string
+}
+
+// ParseKeyReference parses "namespace/secret-name#sourceKey" format.
+// If "#sourceKey" is omitted, targetKey is used as source key.
+func ParseKeyReference(value, targetKey string) (KeyReference, error) {
+	sourceRef, sourceKey, hasKey := strings.Cut(value, "#")
+	namespace, name, err := ParseSourceReference(sourceRef)
+	if err != nil {
+		return KeyReference{}, err
+	}
+
+	sourceKey = strings.TrimSpace(sourceKey)
+	if !hasKey {
+		sourceKey = targetKey
+	}
+	if sourceKey == "" {
+		return KeyReference{}, fmt.Errorf("invalid key reference %q: source key cannot be empty", value)
+	}
+
+	return KeyReference{Source: fmt.Sprintf("%s/%s", namespace, name), Key: sourceKey}, nil
+}
+
+// ParseKeyReferences parses all replicate-from.<key> annotations.
+// It returns a map of target keys to their key references.
+func ParseKeyReferences(annotations map[string]string) (map[string]KeyReference, error) {
+	keyRefs := make(map[string]KeyReference)
+	for annotation, value := range annotations {
+		targetKey, ok := strings.CutPrefix(annotation, AnnotationReplicateFromKeyPrefix)
+		if !ok {
+			continue
+		}
+		if targetKey == "" {
+			return nil, fmt.Errorf("annotation %s is missing the target key", annotation)
+		}
+		ref, err := ParseKeyReference(value, targetKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reference for key %q: %w", targetKey, err)
+		}
+		keyRefs[targetKey] = ref
+	}
+	return keyRefs, nil
+}
+
+// HasKeyReferences checks if a Secret has any replicate-from.<key> annotation
+func HasKeyReferences(secret *corev1.Secret) bool {
+	for annotation := range secret.Annotations {
+		if strings.HasPrefix(annotation, AnnotationReplicateFromKeyPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPullTarget checks if a Secret pulls data from other Secrets (whole Secrets or individual keys)
+func IsPullTarget(secret *corev1.Secret) bool {
+	return secret.Annotations[AnnotationReplicateFrom] != "" || HasKeyReferences(secret)
+}
+
+// PullsFrom checks if a pull target references the given source ("namespace/secret-name"),
+// either as a whole Secret or for individual keys
+func PullsFrom(target *corev1.Secret, sourceRef string) bool {
+	if ReferencesSource(target.Annotations[AnnotationReplicateFrom], sourceRef) {
+		return true
+	}
+	keyRefs, err := ParseKeyReferences(target.Annotations)
+	if err != nil {
+		return false
+	}
+	for _, ref := range keyRefs {
+		if ref.Source == sourceRef {
+			return true
+		}
+	}
+	return false
+}
+
+// ReferencesSource checks if a replicate-from value references the given source ("namespace/secret-name")
+func ReferencesSource(sourceRefs string, sourceRef string) bool {
+	refs, err := ParseSourceReferences(sourceRefs)
+	if err != nil {
+		return false
+	}
+	for _, ref := range refs {
+		if ref == sourceRef {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesAnyNamespace checks if a namespace matches any of the given glob patterns.
+// Invalid patterns never match.
+func MatchesAnyNamespace(namespace string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, err := MatchNamespace(namespace, pattern); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// TargetNameData is the data available in replicate-as name templates
+type TargetNameData struct {
+	// Namespace is the target namespace
+	Namespace string
+	// Name is the name of the source Secret
+	Name string
+	// SourceNamespace is the namespace of the source Secret
+	SourceNamespace string
+}
+
+// ReferencedNamespaces returns the namespaces a Secret references in its replication annotations:
+// the push targets of replicate-to (without the "*" wildcard) and the source namespaces of
+// replicate-from and replicate-from.<key>, sorted. Invalid references are ignored.
+func ReferencedNamespaces(secret *corev1.Secret) []string {
+	var namespaces []string
+	add := func(namespace string) {
+		if namespace != "" && !slices.Contains(namespaces, namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+
+	for _, namespace := range ParseTargetNamespaces(secret.Annotations[AnnotationReplicateTo]) {
+		if namespace != ReplicateToAllNamespaces {
+			add(namespace)
+		}
+	}
+
+	sourceRefs, _ := ParseSourceReferences(secret.Annotations[AnnotationReplicateFrom])
+	keyRefs, _ := ParseKeyReferences(secret.Annotations)
+	for _, ref := range keyRefs {
+		sourceRefs = append(sourceRefs, ref.Source)
+	}
+	for _, sourceRef := range sourceRefs {
+		if namespace, _, err := ParseSourceReference(sourceRef); err == nil {
+			add(namespace)
+		}
+	}
+
+	slices.Sort(namespaces)
+	return namespaces
+}
+
+// RenderTargetName returns the name of the pushed Secret in the target namespace.
+// Without replicate-as annotation, the source name is used.
+func RenderTargetName(source *corev1.Secret, targetNamespace string) (string, error) {
+	nameTemplate := source.Annotations[AnnotationReplicateAs]
+	if nameTemplate == "" {
+		return source.Name, nil
+	}
+
+	tmpl, err := template.New("replicate-as").Option("missingkey=error").Parse(nameTemplate)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s template: %w", AnnotationReplicateAs, err)
+	}
+
+	var name strings.Builder
+	data := TargetNameData{Namespace: targetNamespace, Name: source.Name, SourceNamespace: source.Namespace}
+	if err := tmpl.Execute(&name, data); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", AnnotationReplicateAs, err)
+	}
+
+	if errs := validation.IsDNS1123Subdomain(name.String()); len(errs) > 0 {
+		return "", fmt.Errorf("invalid target name %q: %s", name.String(), errs[0])
+	}
+
+	return name.String(), nil
+}
+
 // ParseTargetNamespaces parses comma-separated list of target namespaces
 func ParseTargetNamespaces(targetNS string) []string {
 	if targetNS == "" {
@@ -196,18 +596,41 @@ func GetReplicatedFromAnnotation(secret *corev1.Secret) string {
 	return secret.Annotations[AnnotationReplicatedFrom]
 }
 
-// HasConflictingAnnotations checks if autogenerate and replicate-from are both present
+// IsStale checks if a Secret is a snapshot whose source was deleted or whose consent expired
+func IsStale(secret *corev1.Secret) bool {
+	return secret.Annotations[AnnotationSourceDeletedAt] != "" || secret.Annotations[AnnotationConsentExpiredAt] != ""
+}
+
+// HasConflictingAnnotations checks if autogenerate and replicate-from (or replicate-from.<key>) are both present
 func HasConflictingAnnotations(secret *corev1.Secret) bool {
 	if secret.Annotations == nil {
 		return false
 	}
 	hasAutogenerate := secret.Annotations[AnnotationPrefix+"autogenerate"] != ""
-	hasReplicateFrom := secret.Annotations[AnnotationReplicateFrom] != ""
-	return hasAutogenerate && hasReplicateFrom
+	return hasAutogenerate && IsPullTarget(secret)
+}
+
+// ApplyPushedLabels sets the configured labels on a pushed Secret
+func ApplyPushedLabels(target *corev1.Secret, pushedLabels map[string]string) {
+	if len(pushedLabels) > 0 && target.Labels == nil {
+		target.Labels = make(map[string]string, len(pushedLabels))
+	}
+	for key, value := range pushedLabels {
+		target.Labels[key] = value
+	}
+}
+
+// PushedType returns the type of a Secret pushed from a source of the given type.
+// The override only applies to Opaque sources; other types are kept.
+func PushedType(sourceType corev1.SecretType, override string) corev1.SecretType {
+	if override != "" && (sourceType == "" || sourceType == corev1.SecretTypeOpaque) {
+		return corev1.SecretType(override)
+	}
+	return sourceType
 }
 
-// CreateReplicatedSecret creates a new Secret for replication
-func CreateReplicatedSecret(source *corev1.Secret, targetNamespace string) *corev1.Secret {
+// CreateReplicatedSecret creates a new Secret for replication at the given time
+func CreateReplicatedSecret(source *corev1.Secret, targetNamespace string, now time.Time) *corev1.Secret {
 	target := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      source.Name,
@@ -215,7 +638,7 @@ func CreateReplicatedSecret(source *corev1.Secret, targetNamespace string) *core
 			Labels:    make(map[string]string),
 			Annotations: map[string]string{
 				AnnotationReplicatedFrom:   fmt.Sprintf("%s/%s", source.Namespace, source.Name),
-				AnnotationLastReplicatedAt: time.Now().Format(time.RFC3339),
+				AnnotationLastReplicatedAt: now.Format(time.RFC3339),
 			},
 		},
 		Type: source.Type,
@@ -230,7 +653,9 @@ func CreateReplicatedSecret(source *corev1.Secret, targetNamespace string) *core
 	// Copy data
 	for key, value := range source.Data {
 		target.Data[key] = value
+		annotations.SetOrigin(target.Annotations, key, annotations.OriginReplicated)
 	}
+	RecordChecksums(target)
 
 	return target
 }
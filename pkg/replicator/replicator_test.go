@@ -17,11 +17,16 @@ limitations under the License.
 package replicator
 
 import (
+	"strings"
 	"testing"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/annotations"
 )
 
 func TestMatchNamespace(t *testing.T) {
@@ -293,6 +298,35 @@ func TestValidateReplication(t *testing.T) {
 	}
 }
 
+func TestMatchNamespaceSelector(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector string
+		labels   map[string]string
+		want     bool
+		wantErr  bool
+	}{
+		{name: "equality match", selector: "env=staging", labels: map[string]string{"env": "staging"}, want: true},
+		{name: "equality mismatch", selector: "env=staging", labels: map[string]string{"env": "production"}, want: false},
+		{name: "no labels", selector: "env=staging", labels: nil, want: false},
+		{name: "set-based match", selector: "env in (staging,dev),team", labels: map[string]string{"env": "dev", "team": "a"}, want: true},
+		{name: "empty selector", selector: " ", wantErr: true},
+		{name: "invalid selector", selector: "env==(", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MatchNamespaceSelector(tt.selector, tt.labels)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MatchNamespaceSelector() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("MatchNamespaceSelector() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestReplicateSecret(t *testing.T) {
 	source := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -341,7 +375,7 @@ func TestReplicateSecret(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ReplicateSecret(source, tt.target)
+			ReplicateSecret(source, tt.target, time.Now())
 
 			// Check data was copied
 			if len(tt.target.Data) < len(source.Data) {
@@ -373,6 +407,15 @@ func TestReplicateSecret(t *testing.T) {
 				t.Fatal("target annotations is nil")
 			}
 
+			for key := range source.Data {
+				if origin := tt.target.Annotations[annotations.OriginPrefix+key]; origin != annotations.OriginReplicated {
+					t.Errorf("origin of %q = %q, want %q", key, origin, annotations.OriginReplicated)
+				}
+			}
+			if _, ok := tt.target.Annotations[annotations.OriginPrefix+"oldkey"]; ok {
+				t.Error("expected no origin for a key that was not replicated")
+			}
+
 			expectedReplicatedFrom := "production/source-secret"
 			if tt.target.Annotations[AnnotationReplicatedFrom] != expectedReplicatedFrom {
 				t.Errorf("replicated-from = %q, want %q",
@@ -462,6 +505,400 @@ func TestParseSourceReference(t *testing.T) {
 	}
 }
 
+func TestParseSourceReferences(t *testing.T) {
+	tests := []struct {
+		name       string
+		sourceRefs string
+		want       []string
+		wantErr    bool
+	}{
+		{
+			name:       "single source",
+			sourceRefs: "infra/ca-bundle",
+			want:       []string{"infra/ca-bundle"},
+		},
+		{
+			name:       "multiple sources keep order",
+			sourceRefs: "infra/proxy-creds, infra/ca-bundle",
+			want:       []string{"infra/proxy-creds", "infra/ca-bundle"},
+		},
+		{
+			name:       "trailing comma",
+			sourceRefs: "infra/ca-bundle,",
+			want:       []string{"infra/ca-bundle"},
+		},
+		{
+			name:       "invalid entry",
+			sourceRefs: "infra/ca-bundle,proxy-creds",
+			wantErr:    true,
+		},
+		{
+			name:       "duplicate source",
+			sourceRefs: "infra/ca-bundle, infra/ca-bundle",
+			wantErr:    true,
+		},
+		{
+			name:       "empty",
+			sourceRefs: " , ",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSourceReferences(tt.sourceRefs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSourceReferences() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ParseSourceReferences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReferencesSource(t *testing.T) {
+	if !ReferencesSource("infra/ca-bundle, infra/proxy-creds", "infra/proxy-creds") {
+		t.Error("expected source in list to be referenced")
+	}
+	if ReferencesSource("infra/ca-bundle", "infra/proxy-creds") {
+		t.Error("expected other source not to be referenced")
+	}
+	if ReferencesSource("invalid", "invalid") {
+		t.Error("expected invalid references not to match")
+	}
+}
+
+func TestReplicateSecretsMerge(t *testing.T) {
+	first := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ca-bundle", Namespace: "infra"},
+		Data: map[string][]byte{
+			"ca.crt": []byte("ca"),
+			"shared": []byte("from-first"),
+		},
+	}
+	second := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "proxy-creds", Namespace: "infra"},
+		Data: map[string][]byte{
+			"proxy-password": []byte("secret"),
+			"shared":         []byte("from-second"),
+		},
+	}
+	target := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "combined", Namespace: "app"},
+	}
+
+	ReplicateSecrets([]*corev1.Secret{first, second}, target, time.Now())
+
+	expected := map[string]string{
+		"ca.crt":         "ca",
+		"proxy-password": "secret",
+		"shared":         "from-second",
+	}
+	if len(target.Data) != len(expected) {
+		t.Errorf("target data length = %d, want %d", len(target.Data), len(expected))
+	}
+	for key, value := range expected {
+		if string(target.Data[key]) != value {
+			t.Errorf("target[%q] = %q, want %q", key, target.Data[key], value)
+		}
+	}
+
+	if got := target.Annotations[AnnotationReplicatedFrom]; got != "infra/ca-bundle,infra/proxy-creds" {
+		t.Errorf("replicated-from = %q, want %q", got, "infra/ca-bundle,infra/proxy-creds")
+	}
+}
+
+func TestParseKeyReference(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		targetKey string
+		want      KeyReference
+		wantErr   bool
+	}{
+		{
+			name:      "explicit source key",
+			value:     "infra/db#password",
+			targetKey: "db-password",
+			want:      KeyReference{Source: "infra/db", Key: "password"},
+		},
+		{
+			name:      "source key defaults to target key",
+			value:     "infra/db",
+			targetKey: "password",
+			want:      KeyReference{Source: "infra/db", Key: "password"},
+		},
+		{
+			name:      "whitespace is trimmed",
+			value:     " infra / db # password ",
+			targetKey: "db-password",
+			want:      KeyReference{Source: "infra/db", Key: "password"},
+		},
+		{
+			name:      "empty source key",
+			value:     "infra/db#",
+			targetKey: "password",
+			wantErr:   true,
+		},
+		{
+			name:      "invalid source reference",
+			value:     "db#password",
+			targetKey: "password",
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseKeyReference(tt.value, tt.targetKey)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseKeyReference() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseKeyReference() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseKeyReferences(t *testing.T) {
+	annotations := map[string]string{
+		AnnotationReplicateFrom:                      "infra/ca-bundle",
+		AnnotationReplicateFromKeyPrefix + "db-pass": "infra/db#password",
+		AnnotationReplicateFromKeyPrefix + "api-key": "infra/api",
+		AnnotationReplicatedFrom:                     "infra/ca-bundle",
+	}
+
+	keyRefs, err := ParseKeyReferences(annotations)
+	if err != nil {
+		t.Fatalf("ParseKeyReferences() error = %v", err)
+	}
+	if len(keyRefs) != 2 {
+		t.Fatalf("ParseKeyReferences() returned %d references, want 2", len(keyRefs))
+	}
+	if keyRefs["db-pass"] != (KeyReference{Source: "infra/db", Key: "password"}) {
+		t.Errorf("keyRefs[db-pass] = %+v", keyRefs["db-pass"])
+	}
+	if keyRefs["api-key"] != (KeyReference{Source: "infra/api", Key: "api-key"}) {
+		t.Errorf("keyRefs[api-key] = %+v", keyRefs["api-key"])
+	}
+
+	if _, err := ParseKeyReferences(map[string]string{AnnotationReplicateFromKeyPrefix + "x": "invalid"}); err == nil {
+		t.Error("expected error for invalid key reference")
+	}
+}
+
+func TestReplicateKeys(t *testing.T) {
+	sources := map[string]*corev1.Secret{
+		"infra/db": {
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "infra"},
+			Data:       map[string][]byte{"password": []byte("dbpass")},
+		},
+		"infra/api": {
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "infra"},
+			Data:       map[string][]byte{"token": []byte("apitoken")},
+		},
+	}
+	target := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "app",
+			Annotations: map[string]string{AnnotationReplicatedFrom: "infra/api"},
+		},
+	}
+
+	keyRefs := map[string]KeyReference{
+		"db-password": {Source: "infra/db", Key: "password"},
+		"api-token":   {Source: "infra/api", Key: "token"},
+	}
+	if err := ReplicateKeys(sources, keyRefs, target, time.Now()); err != nil {
+		t.Fatalf("ReplicateKeys() error = %v", err)
+	}
+
+	if string(target.Data["db-password"]) != "dbpass" || string(target.Data["api-token"]) != "apitoken" {
+		t.Errorf("unexpected target data: %v", target.Data)
+	}
+	if got := target.Annotations[AnnotationReplicatedFrom]; got != "infra/api,infra/db" {
+		t.Errorf("replicated-from = %q, want %q", got, "infra/api,infra/db")
+	}
+
+	missing := map[string]KeyReference{"x": {Source: "infra/db", Key: "missing"}}
+	if err := ReplicateKeys(sources, missing, target, time.Now()); err == nil {
+		t.Error("expected error for missing source key")
+	}
+}
+
+func TestPullsFrom(t *testing.T) {
+	target := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				AnnotationReplicateFrom:                   "infra/ca-bundle",
+				AnnotationReplicateFromKeyPrefix + "pass": "infra/db#password",
+			},
+		},
+	}
+
+	if !PullsFrom(target, "infra/ca-bundle") {
+		t.Error("expected whole source to be referenced")
+	}
+	if !PullsFrom(target, "infra/db") {
+		t.Error("expected per-key source to be referenced")
+	}
+	if PullsFrom(target, "infra/other") {
+		t.Error("expected unrelated source not to be referenced")
+	}
+	if !IsPullTarget(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{AnnotationReplicateFromKeyPrefix + "pass": "infra/db"},
+	}}) {
+		t.Error("expected Secret with only per-key references to be a pull target")
+	}
+}
+
+func TestValidateTypeKeys(t *testing.T) {
+	tests := []struct {
+		name       string
+		secretType corev1.SecretType
+		data       map[string][]byte
+		wantErr    bool
+	}{
+		{
+			name:       "opaque has no required keys",
+			secretType: corev1.SecretTypeOpaque,
+		},
+		{
+			name:       "tls with certificate and key",
+			secretType: corev1.SecretTypeTLS,
+			data: map[string][]byte{
+				"tls.crt": []byte("cert"),
+				"tls.key": []byte("key"),
+				"ca.crt":  []byte("ca"),
+			},
+		},
+		{
+			name:       "tls without key",
+			secretType: corev1.SecretTypeTLS,
+			data:       map[string][]byte{"tls.crt": []byte("cert")},
+			wantErr:    true,
+		},
+		{
+			name:       "tls with empty placeholder key",
+			secretType: corev1.SecretTypeTLS,
+			data: map[string][]byte{
+				"tls.crt": []byte("cert"),
+				"tls.key": {},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "basic-auth with password only",
+			secretType: corev1.SecretTypeBasicAuth,
+			data:       map[string][]byte{"password": []byte("pass")},
+		},
+		{
+			name:       "basic-auth without credentials",
+			secretType: corev1.SecretTypeBasicAuth,
+			data:       map[string][]byte{"token": []byte("x")},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &corev1.Secret{Type: tt.secretType, Data: tt.data}
+			if err := ValidateTypeKeys(secret); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTypeKeys() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMatchesAnyNamespace(t *testing.T) {
+	patterns := []string{"kube-*", "monitoring", "bad-[pattern"}
+
+	if !MatchesAnyNamespace("kube-system", patterns) {
+		t.Error("expected kube-system to match kube-*")
+	}
+	if !MatchesAnyNamespace("monitoring", patterns) {
+		t.Error("expected exact match for monitoring")
+	}
+	if MatchesAnyNamespace("default", patterns) {
+		t.Error("expected default not to match")
+	}
+	if MatchesAnyNamespace("default", nil) {
+		t.Error("expected no match without patterns")
+	}
+}
+
+func TestReferencedNamespaces(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				AnnotationReplicateTo:                       "staging, *, dev",
+				AnnotationReplicateFrom:                     "production/db,staging/api",
+				AnnotationReplicateFromKeyPrefix + "ca.crt": "infra/ca",
+			},
+		},
+	}
+
+	got := ReferencedNamespaces(secret)
+	expected := []string{"dev", "infra", "production", "staging"}
+	if strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	if got := ReferencedNamespaces(&corev1.Secret{}); len(got) != 0 {
+		t.Errorf("expected no namespaces, got %v", got)
+	}
+}
+
+func TestRenderTargetName(t *testing.T) {
+	tests := []struct {
+		name        string
+		template    string
+		want        string
+		expectError bool
+	}{
+		{name: "no template", template: "", want: "registry"},
+		{name: "namespace prefix", template: "{{ .Namespace }}-registry-creds", want: "team-a-registry-creds"},
+		{name: "all fields", template: "{{ .SourceNamespace }}-{{ .Name }}-{{ .Namespace }}", want: "infra-registry-team-a"},
+		{name: "static name", template: "pull-secret", want: "pull-secret"},
+		{name: "parse error", template: "{{ .Namespace", expectError: true},
+		{name: "unknown field", template: "{{ .Cluster }}", expectError: true},
+		{name: "invalid name", template: "{{ .Namespace }}_creds", expectError: true},
+		{name: "empty result", template: "{{ if false }}x{{ end }}", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "registry",
+					Namespace:   "infra",
+					Annotations: map[string]string{},
+				},
+			}
+			if tt.template != "" {
+				source.Annotations[AnnotationReplicateAs] = tt.template
+			}
+
+			got, err := RenderTargetName(source, "team-a")
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error, got name %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
 func TestParseTargetNamespaces(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -735,6 +1172,51 @@ func TestHasConflictingAnnotations(t *testing.T) {
 	}
 }
 
+func TestDataChecksum(t *testing.T) {
+	data := map[string][]byte{"a": []byte("1"), "b": []byte("2")}
+
+	if DataChecksum(data) != DataChecksum(map[string][]byte{"b": []byte("2"), "a": []byte("1")}) {
+		t.Error("expected checksum to be independent of map order")
+	}
+	if DataChecksum(data) == DataChecksum(map[string][]byte{"a": []byte("1"), "b": []byte("3")}) {
+		t.Error("expected checksum to change when a value changes")
+	}
+	// Moving bytes between key and value must change the checksum
+	if DataChecksum(map[string][]byte{"ab": []byte("c")}) == DataChecksum(map[string][]byte{"a": []byte("bc")}) {
+		t.Error("expected checksum to distinguish key and value boundaries")
+	}
+}
+
+func TestHasDrifted(t *testing.T) {
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "src", Namespace: "production"},
+		Data:       map[string][]byte{"password": []byte("secret")},
+	}
+	target := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "dst", Namespace: "staging"}}
+
+	ReplicateSecret(source, target, time.Now())
+	if HasDrifted(target) {
+		t.Error("expected freshly replicated Secret not to be drifted")
+	}
+	if checksum := target.Annotations[annotations.DataChecksum]; checksum != DataChecksum(target.Data) {
+		t.Errorf("expected data checksum of the replicated data, got %q", checksum)
+	}
+
+	target.Data["password"] = []byte("changed")
+	if !HasDrifted(target) {
+		t.Error("expected modified Secret to be drifted")
+	}
+
+	delete(target.Annotations, AnnotationReplicatedChecksum)
+	if HasDrifted(target) {
+		t.Error("expected Secret without checksum not to be drifted")
+	}
+
+	if HasDrifted(CreateReplicatedSecret(source, "staging", time.Now())) {
+		t.Error("expected created Secret not to be drifted")
+	}
+}
+
 func TestCreateReplicatedSecret(t *testing.T) {
 	source := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -752,7 +1234,8 @@ func TestCreateReplicatedSecret(t *testing.T) {
 		},
 	}
 
-	target := CreateReplicatedSecret(source, "staging")
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	target := CreateReplicatedSecret(source, "staging", now)
 
 	// Check basic metadata
 	if target.Name != source.Name {
@@ -792,13 +1275,40 @@ func TestCreateReplicatedSecret(t *testing.T) {
 			target.Annotations[AnnotationReplicatedFrom], expectedReplicatedFrom)
 	}
 
-	timestamp := target.Annotations[AnnotationLastReplicatedAt]
-	if timestamp == "" {
-		t.Error("last-replicated-at annotation is empty")
+	if timestamp := target.Annotations[AnnotationLastReplicatedAt]; timestamp != "2025-01-01T12:00:00Z" {
+		t.Errorf("last-replicated-at = %q, want the given time", timestamp)
 	}
-	_, err := time.Parse(time.RFC3339, timestamp)
-	if err != nil {
-		t.Errorf("last-replicated-at is not valid RFC3339: %v", err)
+	for key := range source.Data {
+		if origin := target.Annotations[annotations.OriginPrefix+key]; origin != annotations.OriginReplicated {
+			t.Errorf("origin of %q = %q, want %q", key, origin, annotations.OriginReplicated)
+		}
+	}
+}
+
+func TestReplicateSecretLongKeys(t *testing.T) {
+	longKey := strings.Repeat("k", 60)
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "source", Namespace: "production"},
+		Data: map[string][]byte{
+			longKey:    []byte("long"),
+			"key_":     []byte("underscore"),
+			"password": []byte("secret"),
+		},
+	}
+	target := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "target", Namespace: "staging"}}
+
+	ReplicateSecret(source, target, time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	for key, value := range source.Data {
+		if string(target.Data[key]) != string(value) {
+			t.Errorf("expected %q to be replicated", key)
+		}
+	}
+	if errs := apivalidation.ValidateAnnotations(target.Annotations, field.NewPath("metadata", "annotations")); len(errs) > 0 {
+		t.Errorf("invalid target annotations: %v", errs.ToAggregate())
+	}
+	if origin := target.Annotations[annotations.OriginPrefix+"password"]; origin != annotations.OriginReplicated {
+		t.Errorf("origin of password = %q, want %q", origin, annotations.OriginReplicated)
 	}
 }
 
@@ -1066,3 +1576,42 @@ func TestCharacterClassPatterns(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyPushedLabels(t *testing.T) {
+	target := &corev1.Secret{}
+	ApplyPushedLabels(target, map[string]string{"managed-by": "iso-operator"})
+	if target.Labels["managed-by"] != "iso-operator" {
+		t.Errorf("expected pushed label to be set, got %v", target.Labels)
+	}
+
+	target = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "myapp", "managed-by": "helm"}}}
+	ApplyPushedLabels(target, map[string]string{"managed-by": "iso-operator"})
+	if target.Labels["app"] != "myapp" || target.Labels["managed-by"] != "iso-operator" {
+		t.Errorf("expected pushed label to override source labels, got %v", target.Labels)
+	}
+
+	target = &corev1.Secret{}
+	ApplyPushedLabels(target, nil)
+	if target.Labels != nil {
+		t.Errorf("expected no labels without pushed labels, got %v", target.Labels)
+	}
+}
+
+func TestPushedType(t *testing.T) {
+	tests := []struct {
+		sourceType corev1.SecretType
+		override   string
+		expected   corev1.SecretType
+	}{
+		{sourceType: corev1.SecretTypeOpaque, expected: corev1.SecretTypeOpaque},
+		{sourceType: corev1.SecretTypeOpaque, override: "example.com/replicated", expected: "example.com/replicated"},
+		{sourceType: "", override: "example.com/replicated", expected: "example.com/replicated"},
+		{sourceType: corev1.SecretTypeTLS, override: "example.com/replicated", expected: corev1.SecretTypeTLS},
+	}
+
+	for _, tt := range tests {
+		if got := PushedType(tt.sourceType, tt.override); got != tt.expected {
+			t.Errorf("PushedType(%q, %q) = %q, want %q", tt.sourceType, tt.override, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutil
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// errInjectedConflict is the cause of the conflicts returned by a FaultInjector
+var errInjectedConflict = errors.New("conflict injected by the test")
+
+// Faults configures the faults a FaultInjector injects into the controllers of a test manager,
+// so that tests cover the paths that only run when the API server misbehaves, e.g. retries
+// after conflicts or reconciles that are repeated after a partial failure. Rates are
+// probabilities between 0 and 1.
+type Faults struct {
+	// ConflictRate is the rate of updates, patches and applies that fail with a conflict instead
+	// of being sent to the API server
+	ConflictRate float64
+	// GetDelay is the maximum delay of a get. Each get is delayed by a random duration up to it.
+	GetDelay time.Duration
+	// DropEventRate is the rate of update events of the watches that are not delivered to the
+	// controllers. Add and delete events are always delivered, so that every object is
+	// reconciled at least once.
+	DropEventRate float64
+	// Seed seeds the random source of the faults. If 0, the current time is used.
+	Seed int64
+}
+
+// FaultInjector injects Faults into a client and a cache. It is safe for concurrent use.
+type FaultInjector struct {
+	faults Faults
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewFaultInjector returns a FaultInjector for the faults
+func NewFaultInjector(faults Faults) *FaultInjector {
+	seed := faults.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &FaultInjector{faults: faults, rnd: rand.New(rand.NewSource(seed))}
+}
+
+// chance reports whether a fault with the rate occurs
+func (f *FaultInjector) chance(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rnd.Float64() < rate
+}
+
+// getDelay returns a random delay up to the maximum get delay
+func (f *FaultInjector) getDelay() time.Duration {
+	if f.faults.GetDelay <= 0 {
+		return 0
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return time.Duration(f.rnd.Int63n(int64(f.faults.GetDelay)))
+}
+
+// conflict returns a conflict error for the object if a write is to fail
+func (f *FaultInjector) conflict(c client.Client, obj client.Object) error {
+	gvk, _ := c.GroupVersionKindFor(obj)
+	return f.conflictFor(gvk, obj.GetName())
+}
+
+// applyConflict returns a conflict error for the apply configuration if an apply is to fail
+func (f *FaultInjector) applyConflict(obj runtime.ApplyConfiguration) error {
+	var gvk schema.GroupVersionKind
+	if typed, ok := obj.(interface {
+		GetAPIVersion() *string
+		GetKind() *string
+	}); ok {
+		gvk = schema.FromAPIVersionAndKind(ptr.Deref(typed.GetAPIVersion(), ""), ptr.Deref(typed.GetKind(), ""))
+	}
+	var name string
+	if named, ok := obj.(interface{ GetName() *string }); ok {
+		name = ptr.Deref(named.GetName(), "")
+	}
+	return f.conflictFor(gvk, name)
+}
+
+// conflictFor returns a conflict error for the named object of the kind if a write is to fail
+func (f *FaultInjector) conflictFor(gvk schema.GroupVersionKind, name string) error {
+	if !f.chance(f.faults.ConflictRate) {
+		return nil
+	}
+	var resource schema.GroupResource
+	if gvk.Kind != "" {
+		plural, _ := meta.UnsafeGuessKindToResource(gvk)
+		resource = plural.GroupResource()
+	}
+	return apierrors.NewConflict(resource, name, errInjectedConflict)
+}
+
+// Client returns a client that injects the faults into the calls of c
+func (f *FaultInjector) Client(c client.Client) client.Client {
+	return &faultClient{Client: c, faults: f}
+}
+
+// Cache returns a cache whose informers drop events as configured by the faults
+func (f *FaultInjector) Cache(c cache.Cache) cache.Cache {
+	return &faultCache{Cache: c, faults: f}
+}
+
+// faultClient is a client that injects faults into gets and writes
+type faultClient struct {
+	client.Client
+	faults *FaultInjector
+}
+
+// Get gets the object after a random delay
+func (c *faultClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if delay := c.faults.getDelay(); delay > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+// Update updates the object unless a conflict is injected
+func (c *faultClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if err := c.faults.conflict(c.Client, obj); err != nil {
+		return err
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+// Patch patches the object unless a conflict is injected
+func (c *faultClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if err := c.faults.conflict(c.Client, obj); err != nil {
+		return err
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+// Apply applies the configuration unless a conflict is injected
+func (c *faultClient) Apply(ctx context.Context, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+	if err := c.faults.applyConflict(obj); err != nil {
+		return err
+	}
+	return c.Client.Apply(ctx, obj, opts...)
+}
+
+// Status returns a writer for the status subresource that injects conflicts into updates and patches
+func (c *faultClient) Status() client.SubResourceWriter {
+	return &faultStatusWriter{SubResourceWriter: c.Client.Status(), client: c.Client, faults: c.faults}
+}
+
+// faultStatusWriter is a status writer that injects conflicts into updates and patches
+type faultStatusWriter struct {
+	client.SubResourceWriter
+	client client.Client
+	faults *FaultInjector
+}
+
+// Update updates the status of the object unless a conflict is injected
+func (w *faultStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	if err := w.faults.conflict(w.client, obj); err != nil {
+		return err
+	}
+	return w.SubResourceWriter.Update(ctx, obj, opts...)
+}
+
+// Patch patches the status of the object unless a conflict is injected
+func (w *faultStatusWriter) Patch(
+	ctx context.Context,
+	obj client.Object,
+	patch client.Patch,
+	opts ...client.SubResourcePatchOption,
+) error {
+	if err := w.faults.conflict(w.client, obj); err != nil {
+		return err
+	}
+	return w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+}
+
+// faultCache is a cache whose informers drop update events
+type faultCache struct {
+	cache.Cache
+	faults *FaultInjector
+}
+
+// GetInformer returns the informer for the object with event dropping
+func (c *faultCache) GetInformer(ctx context.Context, obj client.Object, opts ...cache.InformerGetOption) (cache.Informer, error) {
+	informer, err := c.Cache.GetInformer(ctx, obj, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &faultInformer{Informer: informer, faults: c.faults}, nil
+}
+
+// GetInformerForKind returns the informer for the kind with event dropping
+func (c *faultCache) GetInformerForKind(ctx context.Context, gvk schema.GroupVersionKind, opts ...cache.InformerGetOption) (cache.Informer, error) {
+	informer, err := c.Cache.GetInformerForKind(ctx, gvk, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &faultInformer{Informer: informer, faults: c.faults}, nil
+}
+
+// faultInformer is an informer whose event handlers miss update events
+type faultInformer struct {
+	cache.Informer
+	faults *FaultInjector
+}
+
+// AddEventHandler adds the handler with event dropping
+func (i *faultInformer) AddEventHandler(handler toolscache.ResourceEventHandler) (toolscache.ResourceEventHandlerRegistration, error) {
+	return i.Informer.AddEventHandler(i.wrap(handler))
+}
+
+// AddEventHandlerWithResyncPeriod adds the handler with event dropping
+func (i *faultInformer) AddEventHandlerWithResyncPeriod(handler toolscache.ResourceEventHandler, resyncPeriod time.Duration) (toolscache.ResourceEventHandlerRegistration, error) {
+	return i.Informer.AddEventHandlerWithResyncPeriod(i.wrap(handler), resyncPeriod)
+}
+
+// AddEventHandlerWithOptions adds the handler with event dropping
+func (i *faultInformer) AddEventHandlerWithOptions(handler toolscache.ResourceEventHandler, options toolscache.HandlerOptions) (toolscache.ResourceEventHandlerRegistration, error) {
+	return i.Informer.AddEventHandlerWithOptions(i.wrap(handler), options)
+}
+
+// wrap returns a handler that drops update events before they reach the handler
+func (i *faultInformer) wrap(handler toolscache.ResourceEventHandler) toolscache.ResourceEventHandler {
+	return toolscache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: handler.OnAdd,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if i.faults.chance(i.faults.faults.DropEventRate) {
+				return
+			}
+			handler.OnUpdate(oldObj, newObj)
+		},
+		DeleteFunc: handler.OnDelete,
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestFaultInjectorConflicts(t *testing.T) {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "apps"}}
+	ctx := context.Background()
+	key := client.ObjectKeyFromObject(secret)
+
+	// Every update fails with a conflict without reaching the API server
+	c := NewFaultInjector(Faults{ConflictRate: 1, Seed: 1}).Client(fake.NewClientBuilder().WithObjects(secret).Build())
+	var got corev1.Secret
+	if err := c.Get(ctx, key, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got.Data = map[string][]byte{"password": []byte("secret")}
+	if err := c.Update(ctx, &got); !apierrors.IsConflict(err) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+	if err := c.Status().Update(ctx, &got); !apierrors.IsConflict(err) {
+		t.Errorf("expected a conflict for the status, got %v", err)
+	}
+	patch := client.MergeFrom(got.DeepCopy())
+	got.Labels = map[string]string{"patched": "true"}
+	if err := c.Patch(ctx, &got, patch); !apierrors.IsConflict(err) {
+		t.Errorf("expected a conflict for the patch, got %v", err)
+	}
+	if err := c.Status().Patch(ctx, &got, patch); !apierrors.IsConflict(err) {
+		t.Errorf("expected a conflict for the status patch, got %v", err)
+	}
+	apply := corev1ac.Secret("db", "apps").WithData(map[string][]byte{"password": []byte("secret")})
+	err := c.Apply(ctx, apply, client.FieldOwner("test"))
+	if !apierrors.IsConflict(err) {
+		t.Errorf("expected a conflict for the apply, got %v", err)
+	}
+	var status apierrors.APIStatus
+	if errors.As(err, &status) && status.Status().Details.Kind != "secrets" {
+		t.Errorf("expected the conflict to name the secrets resource, got %q", status.Status().Details.Kind)
+	}
+	if err := c.Get(ctx, key, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Data) != 0 {
+		t.Errorf("expected the Secret to be unchanged, got %v", got.Data)
+	}
+
+	// Without a conflict rate, updates pass through
+	c = NewFaultInjector(Faults{Seed: 1}).Client(fake.NewClientBuilder().WithObjects(secret).Build())
+	if err := c.Get(ctx, key, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got.Data = map[string][]byte{"password": []byte("secret")}
+	if err := c.Update(ctx, &got); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestFaultInjectorGetDelay(t *testing.T) {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "apps"}}
+	c := NewFaultInjector(Faults{GetDelay: time.Hour, Seed: 1}).Client(fake.NewClientBuilder().WithObjects(secret).Build())
+
+	// A delayed get returns when its context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := c.Get(ctx, client.ObjectKeyFromObject(secret), &corev1.Secret{}); err != context.DeadlineExceeded {
+		t.Errorf("expected the deadline to be exceeded, got %v", err)
+	}
+}
+
+func TestFaultInjectorDropsUpdateEvents(t *testing.T) {
+	var adds, updates, deletes int
+	handler := toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { adds++ },
+		UpdateFunc: func(interface{}, interface{}) { updates++ },
+		DeleteFunc: func(interface{}) { deletes++ },
+	}
+	secret := &corev1.Secret{}
+
+	wrapped := (&faultInformer{faults: NewFaultInjector(Faults{DropEventRate: 1, Seed: 1})}).wrap(handler)
+	wrapped.OnAdd(secret, false)
+	wrapped.OnUpdate(secret, secret)
+	wrapped.OnDelete(secret)
+	if adds != 1 || updates != 0 || deletes != 1 {
+		t.Errorf("expected only the update to be dropped, got %d adds, %d updates, %d deletes", adds, updates, deletes)
+	}
+
+	wrapped = (&faultInformer{faults: NewFaultInjector(Faults{Seed: 1})}).wrap(handler)
+	wrapped.OnUpdate(secret, secret)
+	if updates != 1 {
+		t.Errorf("expected the update to be delivered, got %d updates", updates)
+	}
+}
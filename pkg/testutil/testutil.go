@@ -0,0 +1,232 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testutil starts the operator's controllers against an envtest API server, so that
+// teams embedding the controllers or writing policies around them can test against the real
+// reconcilers without copying the bootstrap code
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/config"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	isov1alpha1 "github.com/guided-traffic/internal-secrets-operator/api/v1alpha1"
+	"github.com/guided-traffic/internal-secrets-operator/internal/controller"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/clock"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+)
+
+// cacheSyncTimeout is the time a started manager has to start its controllers and sync its cache
+const cacheSyncTimeout = 30 * time.Second
+
+// NewScheme returns a scheme with the Kubernetes types and the types of the operator
+func NewScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := isov1alpha1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	return scheme, nil
+}
+
+// StartEnvironment starts an envtest API server with the CRDs in the given directories
+// (e.g. the operator's config/crd/bases). The envtest binaries are located through
+// KUBEBUILDER_ASSETS. The caller stops the environment with Stop.
+func StartEnvironment(crdDirectoryPaths ...string) (*envtest.Environment, *rest.Config, error) {
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     crdDirectoryPaths,
+		ErrorIfCRDPathMissing: len(crdDirectoryPaths) > 0,
+	}
+	restConfig, err := env.Start()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to start test environment: %w", err)
+	}
+	return env, restConfig, nil
+}
+
+// Options selects the controllers a test manager runs and how they are configured
+type Options struct {
+	// Config is the operator configuration. If nil, config.NewDefaultConfig() is used.
+	Config *config.Config
+	// Clock is the time source of the controllers, e.g. a clock.FakeClock to test rotation
+	// without waiting. If nil, the real time is used.
+	Clock clock.Clock
+	// Scheme is the scheme of the manager. If nil, NewScheme() is used.
+	Scheme *runtime.Scheme
+	// Generator runs the secret generator controller
+	Generator bool
+	// Replicator runs the secret replicator controller
+	Replicator bool
+	// Faults are injected into the client and the watches of the controllers. The client of
+	// the Manager is not affected. If nil, no faults are injected.
+	Faults *Faults
+}
+
+// Manager is a running controller manager of a test
+type Manager struct {
+	// Client reads from the cache of the manager and writes to the API server
+	Client client.Client
+	// Config is the operator configuration the controllers run with
+	Config *config.Config
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// StartManager starts a manager with the selected controllers against the API server of
+// restConfig and waits for its caches to sync. The manager is stopped when the test ends.
+// Multiple managers may run in the same process, e.g. one per test.
+func StartManager(t testing.TB, restConfig *rest.Config, opts Options) *Manager {
+	t.Helper()
+
+	operatorConfig := opts.Config
+	if operatorConfig == nil {
+		operatorConfig = config.NewDefaultConfig()
+	}
+	scheme := opts.Scheme
+	if scheme == nil {
+		var err error
+		if scheme, err = NewScheme(); err != nil {
+			t.Fatalf("failed to create scheme: %v", err)
+		}
+	}
+
+	mgrOpts := ctrl.Options{
+		Scheme: scheme,
+		// Disable the metrics server to avoid port conflicts
+		Metrics: metricsserver.Options{BindAddress: "0"},
+		// Controllers of consecutive tests share their names
+		Controller: ctrlconfig.Controller{SkipNameValidation: ptr.To(true)},
+	}
+	var faults *FaultInjector
+	if opts.Faults != nil {
+		faults = NewFaultInjector(*opts.Faults)
+		mgrOpts.NewCache = func(config *rest.Config, cacheOpts cache.Options) (cache.Cache, error) {
+			c, err := cache.New(config, cacheOpts)
+			if err != nil {
+				return nil, err
+			}
+			return faults.Cache(c), nil
+		}
+	}
+
+	mgr, err := ctrl.NewManager(restConfig, mgrOpts)
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+
+	controllerClient := mgr.GetClient()
+	if faults != nil {
+		controllerClient = faults.Client(controllerClient)
+	}
+
+	if opts.Generator {
+		if err := (&controller.SecretReconciler{
+			Client:        controllerClient,
+			Scheme:        mgr.GetScheme(),
+			Generator:     generator.NewSecretGeneratorWithCharset(operatorConfig.Defaults.String.BuildCharset()),
+			Config:        operatorConfig,
+			EventRecorder: mgr.GetEventRecorderFor("secret-operator"),
+			Clock:         opts.Clock,
+		}).SetupWithManager(mgr); err != nil {
+			t.Fatalf("failed to set up generator controller: %v", err)
+		}
+	}
+	if opts.Replicator {
+		if err := (&controller.SecretReplicatorReconciler{
+			Client:        controllerClient,
+			Scheme:        mgr.GetScheme(),
+			Config:        operatorConfig,
+			EventRecorder: mgr.GetEventRecorderFor("secret-replicator"),
+			Clock:         opts.Clock,
+		}).SetupWithManager(mgr); err != nil {
+			t.Fatalf("failed to set up replicator controller: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Manager{
+		Client: mgr.GetClient(),
+		Config: operatorConfig,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(m.done)
+		if err := mgr.Start(ctx); err != nil {
+			t.Logf("manager stopped: %v", err)
+		}
+	}()
+	t.Cleanup(m.Stop)
+
+	syncCtx, syncCancel := context.WithTimeout(ctx, cacheSyncTimeout)
+	defer syncCancel()
+	// The controllers are started, and request the informers of their watches, once the manager
+	// is elected, so the cache is synced afterwards to include those informers
+	select {
+	case <-mgr.Elected():
+	case <-syncCtx.Done():
+		t.Fatalf("failed to start the controllers of the manager")
+	}
+	if !mgr.GetCache().WaitForCacheSync(syncCtx) {
+		t.Fatalf("failed to sync the cache of the manager")
+	}
+
+	return m
+}
+
+// Stop stops the manager and waits until it has shut down. It is safe to call Stop more than once.
+func (m *Manager) Stop() {
+	m.cancel()
+	<-m.done
+}
+
+// CreateNamespace creates a namespace with a generated name, which is deleted when the test ends
+func CreateNamespace(t testing.TB, c client.Client) *corev1.Namespace {
+	t.Helper()
+
+	ns := &corev1.Namespace{ObjectMeta: ctrl.ObjectMeta{GenerateName: "test-"}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.Create(ctx, ns); err != nil {
+		t.Fatalf("failed to create namespace: %v", err)
+	}
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = c.Delete(ctx, ns)
+	})
+	return ns
+}
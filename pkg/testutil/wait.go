@@ -0,0 +1,184 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultTimeout is a timeout for the wait helpers that suits a single reconcile
+	DefaultTimeout = 10 * time.Second
+
+	// PollInterval is the interval at which the wait helpers check their condition
+	PollInterval = 100 * time.Millisecond
+)
+
+// ErrTimeout is returned by the wait helpers if the condition was not met in time
+var ErrTimeout = errors.New("timed out waiting for condition")
+
+// ErrConditionChanged is returned by ConsistentlySecret if the condition stopped holding
+var ErrConditionChanged = errors.New("condition no longer holds")
+
+// WaitForSecret waits until the condition holds for the Secret. On timeout, it returns the last
+// state of the Secret together with ErrTimeout, or the error of getting it.
+func WaitForSecret(
+	ctx context.Context,
+	c client.Client,
+	key types.NamespacedName,
+	timeout time.Duration,
+	condition func(*corev1.Secret) bool,
+) (*corev1.Secret, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		var secret corev1.Secret
+		err := c.Get(ctx, key, &secret)
+		if err == nil && condition(&secret) {
+			return &secret, nil
+		}
+		if !time.Now().Before(deadline) {
+			if err != nil {
+				return nil, err
+			}
+			return &secret, fmt.Errorf("secret %s: %w", key, ErrTimeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(PollInterval):
+		}
+	}
+}
+
+// WaitForSecretField waits until the Secret has the data field
+func WaitForSecretField(ctx context.Context, c client.Client, key types.NamespacedName, field string, timeout time.Duration) (*corev1.Secret, error) {
+	return WaitForSecret(ctx, c, key, timeout, func(secret *corev1.Secret) bool {
+		_, ok := secret.Data[field]
+		return ok
+	})
+}
+
+// WaitForAnnotation waits until the Secret has the annotation
+func WaitForAnnotation(ctx context.Context, c client.Client, key types.NamespacedName, annotation string, timeout time.Duration) (*corev1.Secret, error) {
+	return WaitForSecret(ctx, c, key, timeout, func(secret *corev1.Secret) bool {
+		_, ok := secret.Annotations[annotation]
+		return ok
+	})
+}
+
+// WaitForSecretData waits until the Secret has all the expected data values, e.g. after replication
+func WaitForSecretData(ctx context.Context, c client.Client, key types.NamespacedName, expected map[string]string, timeout time.Duration) (*corev1.Secret, error) {
+	return WaitForSecret(ctx, c, key, timeout, func(secret *corev1.Secret) bool {
+		for field, value := range expected {
+			if actual, ok := secret.Data[field]; !ok || string(actual) != value {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// WaitForRotation waits until the data field of the Secret no longer has the old value
+func WaitForRotation(ctx context.Context, c client.Client, key types.NamespacedName, field, oldValue string, timeout time.Duration) (*corev1.Secret, error) {
+	return WaitForSecret(ctx, c, key, timeout, func(secret *corev1.Secret) bool {
+		value, ok := secret.Data[field]
+		return ok && string(value) != oldValue
+	})
+}
+
+// WaitForSecretDeletion waits until the Secret no longer exists
+func WaitForSecretDeletion(ctx context.Context, c client.Client, key types.NamespacedName, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		err := c.Get(ctx, key, &corev1.Secret{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return fmt.Errorf("secret %s still exists: %w", key, ErrTimeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(PollInterval):
+		}
+	}
+}
+
+// ConsistentlySecret checks that the condition holds for the Secret for the whole duration, e.g.
+// to check that the operator leaves it alone. It returns as soon as the condition does not hold,
+// with the state of the Secret and ErrConditionChanged, or the error of getting it.
+func ConsistentlySecret(
+	ctx context.Context,
+	c client.Client,
+	key types.NamespacedName,
+	duration time.Duration,
+	condition func(*corev1.Secret) bool,
+) (*corev1.Secret, error) {
+	deadline := time.Now().Add(duration)
+	for {
+		var secret corev1.Secret
+		if err := c.Get(ctx, key, &secret); err != nil {
+			return nil, err
+		}
+		if !condition(&secret) {
+			return &secret, fmt.Errorf("secret %s: %w", key, ErrConditionChanged)
+		}
+		if !time.Now().Before(deadline) {
+			return &secret, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(PollInterval):
+		}
+	}
+}
+
+// ConsistentlySecretEmpty returns true if the Secret does not exist or has no data for the whole
+// duration, e.g. to check that a denied replication does not happen
+func ConsistentlySecretEmpty(ctx context.Context, c client.Client, key types.NamespacedName, duration time.Duration) bool {
+	deadline := time.Now().Add(duration)
+	for time.Now().Before(deadline) {
+		var secret corev1.Secret
+		err := c.Get(ctx, key, &secret)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return false
+		}
+		if err == nil && len(secret.Data) > 0 {
+			return false
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(PollInterval):
+		}
+	}
+	return true
+}
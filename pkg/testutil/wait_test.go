@@ -0,0 +1,123 @@
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWaitForSecretData(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "apps"},
+		Data:       map[string][]byte{"username": []byte("admin")},
+	}
+	c := fake.NewClientBuilder().WithObjects(secret).Build()
+	ctx := context.Background()
+	key := types.NamespacedName{Name: "db", Namespace: "apps"}
+
+	// The value is updated while waiting
+	go func() {
+		time.Sleep(2 * PollInterval)
+		updated := secret.DeepCopy()
+		updated.Data["password"] = []byte("secret")
+		_ = c.Update(ctx, updated)
+	}()
+	got, err := WaitForSecretData(ctx, c, key, map[string]string{"password": "secret"}, DefaultTimeout)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(got.Data["username"]) != "admin" {
+		t.Errorf("expected the whole Secret to be returned, got %v", got.Data)
+	}
+
+	// On timeout, the last state of the Secret is returned
+	got, err = WaitForSecretField(ctx, c, key, "missing", PollInterval)
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected ErrTimeout, got %v", err)
+	}
+	if got == nil || string(got.Data["password"]) != "secret" {
+		t.Errorf("expected the last state of the Secret, got %v", got)
+	}
+
+	// A Secret that does not exist returns the error of getting it
+	if _, err := WaitForAnnotation(ctx, c, types.NamespacedName{Name: "other", Namespace: "apps"}, "a", PollInterval); err == nil ||
+		errors.Is(err, ErrTimeout) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func TestConsistentlySecret(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "apps"},
+		Data:       map[string][]byte{"password": []byte("old")},
+	}
+	c := fake.NewClientBuilder().WithObjects(secret).Build()
+	ctx := context.Background()
+	key := types.NamespacedName{Name: "db", Namespace: "apps"}
+	unchanged := func(secret *corev1.Secret) bool { return string(secret.Data["password"]) == "old" }
+
+	if _, err := ConsistentlySecret(ctx, c, key, 2*PollInterval, unchanged); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// A change is reported before the duration ends
+	updated := secret.DeepCopy()
+	updated.Data["password"] = []byte("new")
+	if err := c.Update(ctx, updated); err != nil {
+		t.Fatalf("failed to update Secret: %v", err)
+	}
+	start := time.Now()
+	got, err := ConsistentlySecret(ctx, c, key, DefaultTimeout, unchanged)
+	if !errors.Is(err, ErrConditionChanged) {
+		t.Errorf("expected ErrConditionChanged, got %v", err)
+	}
+	if got == nil || string(got.Data["password"]) != "new" {
+		t.Errorf("expected the changed Secret, got %v", got)
+	}
+	if time.Since(start) >= DefaultTimeout {
+		t.Error("expected the change to be reported immediately")
+	}
+}
+
+func TestWaitForSecretDeletion(t *testing.T) {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "apps"}}
+	c := fake.NewClientBuilder().WithObjects(secret).Build()
+	ctx := context.Background()
+	key := types.NamespacedName{Name: "db", Namespace: "apps"}
+
+	if err := WaitForSecretDeletion(ctx, c, key, PollInterval); !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected ErrTimeout, got %v", err)
+	}
+	if !ConsistentlySecretEmpty(ctx, c, key, 2*PollInterval) {
+		t.Error("expected Secret without data to be empty")
+	}
+
+	if err := c.Delete(ctx, secret); err != nil {
+		t.Fatalf("failed to delete Secret: %v", err)
+	}
+	if err := WaitForSecretDeletion(ctx, c, key, DefaultTimeout); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
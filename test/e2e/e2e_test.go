@@ -32,51 +32,11 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/annotations"
 )
 
 const (
-	// AnnotationPrefix is the prefix for all secret operator annotations
-	AnnotationPrefix = "iso.gtrfc.com/"
-
-	// AnnotationAutogenerate specifies which fields to auto-generate
-	AnnotationAutogenerate = AnnotationPrefix + "autogenerate"
-
-	// AnnotationType specifies the type of generated value
-	AnnotationType = AnnotationPrefix + "type"
-
-	// AnnotationLength specifies the length of the generated value
-	AnnotationLength = AnnotationPrefix + "length"
-
-	// AnnotationTypePrefix is the prefix for field-specific type annotations
-	AnnotationTypePrefix = AnnotationPrefix + "type."
-
-	// AnnotationLengthPrefix is the prefix for field-specific length annotations
-	AnnotationLengthPrefix = AnnotationPrefix + "length."
-
-	// AnnotationGeneratedAt indicates when the value was generated
-	AnnotationGeneratedAt = AnnotationPrefix + "generated-at"
-
-	// AnnotationRotate specifies the default rotation interval for all fields
-	AnnotationRotate = AnnotationPrefix + "rotate"
-
-	// AnnotationRotatePrefix is the prefix for field-specific rotation annotations
-	AnnotationRotatePrefix = AnnotationPrefix + "rotate."
-
-	// AnnotationStringUppercase specifies whether to include uppercase letters
-	AnnotationStringUppercase = AnnotationPrefix + "string.uppercase"
-
-	// AnnotationStringLowercase specifies whether to include lowercase letters
-	AnnotationStringLowercase = AnnotationPrefix + "string.lowercase"
-
-	// AnnotationStringNumbers specifies whether to include numbers
-	AnnotationStringNumbers = AnnotationPrefix + "string.numbers"
-
-	// AnnotationStringSpecialChars specifies whether to include special characters
-	AnnotationStringSpecialChars = AnnotationPrefix + "string.specialChars"
-
-	// AnnotationStringAllowedSpecialChars specifies which special characters to use
-	AnnotationStringAllowedSpecialChars = AnnotationPrefix + "string.allowedSpecialChars"
-
 	// testNamespace is the namespace used for E2E tests
 	testNamespace = "default"
 
@@ -167,9 +127,9 @@ func TestSecretAutoGeneration(t *testing.T) {
 			Name:      "test-autogenerate",
 			Namespace: testNamespace,
 			Annotations: map[string]string{
-				AnnotationAutogenerate: "password",
-				AnnotationType:         "string",
-				AnnotationLength:       "32",
+				annotations.Autogenerate: "password",
+				annotations.Type:         "string",
+				annotations.Length:       "32",
 			},
 		},
 		Type: corev1.SecretTypeOpaque,
@@ -194,7 +154,7 @@ func TestSecretAutoGeneration(t *testing.T) {
 		// Check if password field was generated
 		if _, ok := s.Data["password"]; ok {
 			// Check if generated-at annotation was set
-			if s.Annotations[AnnotationGeneratedAt] != "" {
+			if s.Annotations[annotations.GeneratedAt] != "" {
 				processedSecret = s
 				return true, nil
 			}
@@ -218,7 +178,7 @@ func TestSecretAutoGeneration(t *testing.T) {
 	}
 
 	// Verify generated-at annotation was set
-	if processedSecret.Annotations[AnnotationGeneratedAt] == "" {
+	if processedSecret.Annotations[annotations.GeneratedAt] == "" {
 		t.Error("Expected generated-at annotation to be set")
 	}
 
@@ -236,9 +196,9 @@ func TestSecretMultiFieldGeneration(t *testing.T) {
 			Name:      "test-multi-field",
 			Namespace: testNamespace,
 			Annotations: map[string]string{
-				AnnotationAutogenerate: "password,api-key,token",
-				AnnotationType:         "string",
-				AnnotationLength:       "24",
+				annotations.Autogenerate: "password,api-key,token",
+				annotations.Type:         "string",
+				annotations.Length:       "24",
 			},
 		},
 		Type: corev1.SecretTypeOpaque,
@@ -265,7 +225,7 @@ func TestSecretMultiFieldGeneration(t *testing.T) {
 		_, hasApiKey := s.Data["api-key"]
 		_, hasToken := s.Data["token"]
 
-		if hasPassword && hasApiKey && hasToken && s.Annotations[AnnotationGeneratedAt] != "" {
+		if hasPassword && hasApiKey && hasToken && s.Annotations[annotations.GeneratedAt] != "" {
 			processedSecret = s
 			return true, nil
 		}
@@ -304,9 +264,9 @@ func TestSecretBytesGeneration(t *testing.T) {
 			Name:      "test-bytes",
 			Namespace: testNamespace,
 			Annotations: map[string]string{
-				AnnotationAutogenerate: "encryption-key",
-				AnnotationType:         "bytes",
-				AnnotationLength:       "32",
+				annotations.Autogenerate: "encryption-key",
+				annotations.Type:         "bytes",
+				annotations.Length:       "32",
 			},
 		},
 		Type: corev1.SecretTypeOpaque,
@@ -327,7 +287,7 @@ func TestSecretBytesGeneration(t *testing.T) {
 		}
 
 		if _, ok := s.Data["encryption-key"]; ok {
-			if s.Annotations[AnnotationGeneratedAt] != "" {
+			if s.Annotations[annotations.GeneratedAt] != "" {
 				processedSecret = s
 				return true, nil
 			}
@@ -359,9 +319,9 @@ func TestSecretRegenerationByKeyDeletion(t *testing.T) {
 			Name:      "test-regenerate-by-deletion",
 			Namespace: testNamespace,
 			Annotations: map[string]string{
-				AnnotationAutogenerate: "password",
-				AnnotationType:         "string",
-				AnnotationLength:       "32",
+				annotations.Autogenerate: "password",
+				annotations.Type:         "string",
+				annotations.Length:       "32",
 			},
 		},
 		Type: corev1.SecretTypeOpaque,
@@ -382,7 +342,7 @@ func TestSecretRegenerationByKeyDeletion(t *testing.T) {
 		}
 
 		if pwd, ok := s.Data["password"]; ok {
-			if s.Annotations[AnnotationGeneratedAt] != "" {
+			if s.Annotations[annotations.GeneratedAt] != "" {
 				originalPassword = string(pwd)
 				return true, nil
 			}
@@ -470,7 +430,7 @@ func TestSecretWithoutAnnotationNotProcessed(t *testing.T) {
 	}
 
 	// Check that no operator annotations were added
-	if _, ok := s.Annotations[AnnotationGeneratedAt]; ok {
+	if _, ok := s.Annotations[annotations.GeneratedAt]; ok {
 		t.Error("Secret without autogenerate annotation should not be processed")
 	}
 
@@ -493,9 +453,9 @@ func TestSecretExistingValuePreserved(t *testing.T) {
 			Name:      "test-existing-value",
 			Namespace: testNamespace,
 			Annotations: map[string]string{
-				AnnotationAutogenerate: "password,new-field",
-				AnnotationType:         "string",
-				AnnotationLength:       "32",
+				annotations.Autogenerate: "password,new-field",
+				annotations.Type:         "string",
+				annotations.Length:       "32",
 			},
 		},
 		Type: corev1.SecretTypeOpaque,
@@ -519,7 +479,7 @@ func TestSecretExistingValuePreserved(t *testing.T) {
 
 		// Check if new-field was generated
 		if _, ok := s.Data["new-field"]; ok {
-			if s.Annotations[AnnotationGeneratedAt] != "" {
+			if s.Annotations[annotations.GeneratedAt] != "" {
 				processedSecret = s
 				return true, nil
 			}
@@ -555,11 +515,11 @@ func TestSecretFieldSpecificConfig(t *testing.T) {
 			Name:      "test-field-specific",
 			Namespace: testNamespace,
 			Annotations: map[string]string{
-				AnnotationAutogenerate:                    "password,encryption-key",
-				AnnotationType:                            "string",
-				AnnotationLength:                          "24",
-				AnnotationTypePrefix + "encryption-key":   "bytes",
-				AnnotationLengthPrefix + "encryption-key": "32",
+				annotations.Autogenerate:                    "password,encryption-key",
+				annotations.Type:                            "string",
+				annotations.Length:                          "24",
+				annotations.TypePrefix + "encryption-key":   "bytes",
+				annotations.LengthPrefix + "encryption-key": "32",
 			},
 		},
 		Type: corev1.SecretTypeOpaque,
@@ -582,7 +542,7 @@ func TestSecretFieldSpecificConfig(t *testing.T) {
 		_, hasPassword := s.Data["password"]
 		_, hasEncryptionKey := s.Data["encryption-key"]
 
-		if hasPassword && hasEncryptionKey && s.Annotations[AnnotationGeneratedAt] != "" {
+		if hasPassword && hasEncryptionKey && s.Annotations[annotations.GeneratedAt] != "" {
 			processedSecret = s
 			return true, nil
 		}
@@ -620,10 +580,10 @@ func TestSecretRotationBasic(t *testing.T) {
 			Name:      "test-rotation-basic",
 			Namespace: testNamespace,
 			Annotations: map[string]string{
-				AnnotationAutogenerate: "password",
-				AnnotationType:         "string",
-				AnnotationLength:       "32",
-				AnnotationRotate:       "10s",
+				annotations.Autogenerate: "password",
+				annotations.Type:         "string",
+				annotations.Length:       "32",
+				annotations.Rotate:       "10s",
 			},
 		},
 		Type: corev1.SecretTypeOpaque,
@@ -645,7 +605,7 @@ func TestSecretRotationBasic(t *testing.T) {
 		}
 
 		if pwd, ok := s.Data["password"]; ok {
-			if genAt := s.Annotations[AnnotationGeneratedAt]; genAt != "" {
+			if genAt := s.Annotations[annotations.GeneratedAt]; genAt != "" {
 				originalPassword = string(pwd)
 				originalGeneratedAt = genAt
 				return true, nil
@@ -673,7 +633,7 @@ func TestSecretRotationBasic(t *testing.T) {
 		}
 
 		pwd := string(s.Data["password"])
-		genAt := s.Annotations[AnnotationGeneratedAt]
+		genAt := s.Annotations[annotations.GeneratedAt]
 
 		// Check if password was rotated (different value and different timestamp)
 		if pwd != originalPassword && genAt != originalGeneratedAt {
@@ -704,10 +664,10 @@ func TestSecretRotationFieldSpecific(t *testing.T) {
 			Name:      "test-rotation-field-specific",
 			Namespace: testNamespace,
 			Annotations: map[string]string{
-				AnnotationAutogenerate:              "password,api-key",
-				AnnotationType:                      "string",
-				AnnotationLength:                    "24",
-				AnnotationRotatePrefix + "password": "10s",
+				annotations.Autogenerate:              "password,api-key",
+				annotations.Type:                      "string",
+				annotations.Length:                    "24",
+				annotations.RotatePrefix + "password": "10s",
 				// api-key has no rotation annotation, so it should not be rotated
 			},
 		},
@@ -731,7 +691,7 @@ func TestSecretRotationFieldSpecific(t *testing.T) {
 		pwd, hasPwd := s.Data["password"]
 		apiKey, hasApiKey := s.Data["api-key"]
 
-		if hasPwd && hasApiKey && s.Annotations[AnnotationGeneratedAt] != "" {
+		if hasPwd && hasApiKey && s.Annotations[annotations.GeneratedAt] != "" {
 			originalPassword = string(pwd)
 			originalApiKey = string(apiKey)
 			return true, nil
@@ -798,10 +758,10 @@ func TestSecretRotationMinIntervalValidation(t *testing.T) {
 			Name:      "test-rotation-min-interval",
 			Namespace: testNamespace,
 			Annotations: map[string]string{
-				AnnotationAutogenerate: "password",
-				AnnotationType:         "string",
-				AnnotationLength:       "32",
-				AnnotationRotate:       "1s", // Below minInterval of 5s
+				annotations.Autogenerate: "password",
+				annotations.Type:         "string",
+				annotations.Length:       "32",
+				annotations.Rotate:       "1s", // Below minInterval of 5s
 			},
 		},
 		Type: corev1.SecretTypeOpaque,
@@ -873,12 +833,12 @@ func TestSecretCharsetUppercaseOnly(t *testing.T) {
 			Name:      "test-charset-uppercase-only",
 			Namespace: testNamespace,
 			Annotations: map[string]string{
-				AnnotationAutogenerate:    "token",
-				AnnotationType:            "string",
-				AnnotationLength:          "32",
-				AnnotationStringUppercase: "true",
-				AnnotationStringLowercase: "false",
-				AnnotationStringNumbers:   "false",
+				annotations.Autogenerate:    "token",
+				annotations.Type:            "string",
+				annotations.Length:          "32",
+				annotations.StringUppercase: "true",
+				annotations.StringLowercase: "false",
+				annotations.StringNumbers:   "false",
 			},
 		},
 		Type: corev1.SecretTypeOpaque,
@@ -898,7 +858,7 @@ func TestSecretCharsetUppercaseOnly(t *testing.T) {
 			return false, err
 		}
 
-		if _, ok := s.Data["token"]; ok && s.Annotations[AnnotationGeneratedAt] != "" {
+		if _, ok := s.Data["token"]; ok && s.Annotations[annotations.GeneratedAt] != "" {
 			processedSecret = s
 			return true, nil
 		}
@@ -935,12 +895,12 @@ func TestSecretCharsetNumbersOnly(t *testing.T) {
 			Name:      "test-charset-numbers-only",
 			Namespace: testNamespace,
 			Annotations: map[string]string{
-				AnnotationAutogenerate:    "pin",
-				AnnotationType:            "string",
-				AnnotationLength:          "6",
-				AnnotationStringUppercase: "false",
-				AnnotationStringLowercase: "false",
-				AnnotationStringNumbers:   "true",
+				annotations.Autogenerate:    "pin",
+				annotations.Type:            "string",
+				annotations.Length:          "6",
+				annotations.StringUppercase: "false",
+				annotations.StringLowercase: "false",
+				annotations.StringNumbers:   "true",
 			},
 		},
 		Type: corev1.SecretTypeOpaque,
@@ -960,7 +920,7 @@ func TestSecretCharsetNumbersOnly(t *testing.T) {
 			return false, err
 		}
 
-		if _, ok := s.Data["pin"]; ok && s.Annotations[AnnotationGeneratedAt] != "" {
+		if _, ok := s.Data["pin"]; ok && s.Annotations[annotations.GeneratedAt] != "" {
 			processedSecret = s
 			return true, nil
 		}
@@ -997,13 +957,13 @@ func TestSecretCharsetWithSpecialChars(t *testing.T) {
 			Name:      "test-charset-special-chars",
 			Namespace: testNamespace,
 			Annotations: map[string]string{
-				AnnotationAutogenerate:       "password",
-				AnnotationType:               "string",
-				AnnotationLength:             "64", // Larger to ensure special chars appear
-				AnnotationStringUppercase:    "true",
-				AnnotationStringLowercase:    "true",
-				AnnotationStringNumbers:      "true",
-				AnnotationStringSpecialChars: "true",
+				annotations.Autogenerate:       "password",
+				annotations.Type:               "string",
+				annotations.Length:             "64", // Larger to ensure special chars appear
+				annotations.StringUppercase:    "true",
+				annotations.StringLowercase:    "true",
+				annotations.StringNumbers:      "true",
+				annotations.StringSpecialChars: "true",
 			},
 		},
 		Type: corev1.SecretTypeOpaque,
@@ -1023,7 +983,7 @@ func TestSecretCharsetWithSpecialChars(t *testing.T) {
 			return false, err
 		}
 
-		if _, ok := s.Data["password"]; ok && s.Annotations[AnnotationGeneratedAt] != "" {
+		if _, ok := s.Data["password"]; ok && s.Annotations[annotations.GeneratedAt] != "" {
 			processedSecret = s
 			return true, nil
 		}
@@ -1074,14 +1034,14 @@ func TestSecretCharsetCustomSpecialChars(t *testing.T) {
 			Name:      "test-charset-custom-special-chars",
 			Namespace: testNamespace,
 			Annotations: map[string]string{
-				AnnotationAutogenerate:              "password",
-				AnnotationType:                      "string",
-				AnnotationLength:                    "48",
-				AnnotationStringUppercase:           "true",
-				AnnotationStringLowercase:           "true",
-				AnnotationStringNumbers:             "true",
-				AnnotationStringSpecialChars:        "true",
-				AnnotationStringAllowedSpecialChars: "!@#", // Only these three
+				annotations.Autogenerate:              "password",
+				annotations.Type:                      "string",
+				annotations.Length:                    "48",
+				annotations.StringUppercase:           "true",
+				annotations.StringLowercase:           "true",
+				annotations.StringNumbers:             "true",
+				annotations.StringSpecialChars:        "true",
+				annotations.StringAllowedSpecialChars: "!@#", // Only these three
 			},
 		},
 		Type: corev1.SecretTypeOpaque,
@@ -1101,7 +1061,7 @@ func TestSecretCharsetCustomSpecialChars(t *testing.T) {
 			return false, err
 		}
 
-		if _, ok := s.Data["password"]; ok && s.Annotations[AnnotationGeneratedAt] != "" {
+		if _, ok := s.Data["password"]; ok && s.Annotations[annotations.GeneratedAt] != "" {
 			processedSecret = s
 			return true, nil
 		}
@@ -1148,12 +1108,12 @@ func TestSecretCharsetLowercaseAndNumbers(t *testing.T) {
 			Name:      "test-charset-lowercase-numbers",
 			Namespace: testNamespace,
 			Annotations: map[string]string{
-				AnnotationAutogenerate:    "token",
-				AnnotationType:            "string",
-				AnnotationLength:          "24",
-				AnnotationStringUppercase: "false",
-				AnnotationStringLowercase: "true",
-				AnnotationStringNumbers:   "true",
+				annotations.Autogenerate:    "token",
+				annotations.Type:            "string",
+				annotations.Length:          "24",
+				annotations.StringUppercase: "false",
+				annotations.StringLowercase: "true",
+				annotations.StringNumbers:   "true",
 			},
 		},
 		Type: corev1.SecretTypeOpaque,
@@ -1173,7 +1133,7 @@ func TestSecretCharsetLowercaseAndNumbers(t *testing.T) {
 			return false, err
 		}
 
-		if _, ok := s.Data["token"]; ok && s.Annotations[AnnotationGeneratedAt] != "" {
+		if _, ok := s.Data["token"]; ok && s.Annotations[annotations.GeneratedAt] != "" {
 			processedSecret = s
 			return true, nil
 		}
@@ -1212,12 +1172,12 @@ func TestSecretCharsetInvalidConfiguration(t *testing.T) {
 			Name:      "test-charset-invalid-empty",
 			Namespace: testNamespace,
 			Annotations: map[string]string{
-				AnnotationAutogenerate:    "password",
-				AnnotationType:            "string",
-				AnnotationLength:          "32",
-				AnnotationStringUppercase: "false",
-				AnnotationStringLowercase: "false",
-				AnnotationStringNumbers:   "false",
+				annotations.Autogenerate:    "password",
+				annotations.Type:            "string",
+				annotations.Length:          "32",
+				annotations.StringUppercase: "false",
+				annotations.StringLowercase: "false",
+				annotations.StringNumbers:   "false",
 			},
 		},
 		Type: corev1.SecretTypeOpaque,
@@ -0,0 +1,133 @@
+//go:build integration
+// +build integration
+
+/*
+Copyright 2025 Guided Traffic.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/testutil"
+)
+
+// chaosFaults are the faults injected into the controllers by the fault injection tests
+var chaosFaults = &testutil.Faults{
+	ConflictRate:  0.3,
+	GetDelay:      50 * time.Millisecond,
+	DropEventRate: 0.3,
+}
+
+// TestGenerationWithFaults tests that generation converges and stays stable when updates
+// conflict, gets are slow and watch events are lost
+func TestGenerationWithFaults(t *testing.T) {
+	mgr := testutil.StartManager(t, restConfig, testutil.Options{
+		Generator: true,
+		Faults:    chaosFaults,
+	})
+	ns := createNamespace(t, mgr.Client)
+	ctx := context.Background()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "chaos-generation",
+			Namespace: ns.Name,
+			Annotations: map[string]string{
+				AnnotationAutogenerate: "password,token",
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+	}
+	if err := mgr.Client.Create(ctx, secret); err != nil {
+		t.Fatalf("failed to create secret: %v", err)
+	}
+
+	key := types.NamespacedName{Name: secret.Name, Namespace: ns.Name}
+	generated, err := testutil.WaitForSecret(ctx, mgr.Client, key, replicationTimeout, func(s *corev1.Secret) bool {
+		return len(s.Data["password"]) > 0 && len(s.Data["token"]) > 0
+	})
+	if err != nil {
+		t.Fatalf("failed to wait for generation: %v", err)
+	}
+
+	// Retried reconciles must not generate the values again
+	if _, err := testutil.ConsistentlySecret(ctx, mgr.Client, key, consistentlyDuration, func(s *corev1.Secret) bool {
+		return string(s.Data["password"]) == string(generated.Data["password"]) &&
+			string(s.Data["token"]) == string(generated.Data["token"])
+	}); err != nil {
+		t.Errorf("expected the generated values to stay stable after retries: %v", err)
+	}
+}
+
+// TestReplicationWithFaults tests that pull and push replication converge when updates
+// conflict, gets are slow and watch events are lost. Changes of the source are not tested,
+// since a dropped update event is only recovered by the next resync.
+func TestReplicationWithFaults(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Features.SecretReplicator = true
+	mgr := testutil.StartManager(t, restConfig, testutil.Options{
+		Config:     cfg,
+		Replicator: true,
+		Faults:     chaosFaults,
+	})
+	sourceNS := createNamespace(t, mgr.Client)
+	targetNS := createNamespace(t, mgr.Client)
+	ctx := context.Background()
+
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "chaos-source",
+			Namespace: sourceNS.Name,
+			Annotations: map[string]string{
+				replicator.AnnotationReplicatableFromNamespaces: targetNS.Name,
+				replicator.AnnotationReplicateTo:                targetNS.Name,
+			},
+		},
+		Data: map[string][]byte{"password": []byte("v1")},
+	}
+	if err := mgr.Client.Create(ctx, source); err != nil {
+		t.Fatalf("failed to create source secret: %v", err)
+	}
+	pulled := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "chaos-pulled",
+			Namespace: targetNS.Name,
+			Annotations: map[string]string{
+				replicator.AnnotationReplicateFrom: sourceNS.Name + "/chaos-source",
+			},
+		},
+	}
+	if err := mgr.Client.Create(ctx, pulled); err != nil {
+		t.Fatalf("failed to create target secret: %v", err)
+	}
+
+	pushedKey := types.NamespacedName{Name: source.Name, Namespace: targetNS.Name}
+	pulledKey := types.NamespacedName{Name: pulled.Name, Namespace: targetNS.Name}
+	for _, key := range []types.NamespacedName{pushedKey, pulledKey} {
+		if _, err := testutil.WaitForSecretData(ctx, mgr.Client, key, map[string]string{"password": "v1"}, replicationTimeout); err != nil {
+			t.Fatalf("failed to wait for replication to %s: %v", key, err)
+		}
+	}
+}
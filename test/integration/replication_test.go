@@ -26,117 +26,38 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
 	"github.com/guided-traffic/internal-secrets-operator/pkg/replicator"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/testutil"
 )
 
 const (
 	// Test timeouts for replication tests
-	replicationTimeout  = 30 * time.Second
-	replicationInterval = 250 * time.Millisecond
+	replicationTimeout = 30 * time.Second
 )
 
 // waitForSecretReplication waits for a secret to have specific data replicated
 func waitForSecretReplication(ctx context.Context, c client.Client, key types.NamespacedName, expectedData map[string]string) (*corev1.Secret, error) {
-	var secret corev1.Secret
-	deadline := time.Now().Add(replicationTimeout)
-
-	for time.Now().Before(deadline) {
-		if err := c.Get(ctx, key, &secret); err != nil {
-			time.Sleep(replicationInterval)
-			continue
-		}
-
-		// Check if all expected data is present
-		allPresent := true
-		for field, expectedValue := range expectedData {
-			actualValue, ok := secret.Data[field]
-			if !ok || string(actualValue) != expectedValue {
-				allPresent = false
-				break
-			}
-		}
-
-		if allPresent {
-			return &secret, nil
-		}
-
-		time.Sleep(replicationInterval)
-	}
-
-	// Return whatever we have
-	if err := c.Get(ctx, key, &secret); err != nil {
-		return nil, err
-	}
-	return &secret, nil
+	return ignoreTimeout(testutil.WaitForSecretData(ctx, c, key, expectedData, replicationTimeout))
 }
 
 // waitForSecretDeletion waits for a secret to be deleted
 func waitForSecretDeletion(ctx context.Context, c client.Client, key types.NamespacedName) error {
-	deadline := time.Now().Add(replicationTimeout)
-
-	for time.Now().Before(deadline) {
-		secret := &corev1.Secret{}
-		err := c.Get(ctx, key, secret)
-		if apierrors.IsNotFound(err) {
-			return nil
-		}
-		time.Sleep(replicationInterval)
-	}
-
-	return fmt.Errorf("secret still exists after timeout")
+	return testutil.WaitForSecretDeletion(ctx, c, key, replicationTimeout)
 }
 
 // consistentlySecretEmpty checks that a secret remains empty for a duration
 func consistentlySecretEmpty(ctx context.Context, c client.Client, key types.NamespacedName, duration time.Duration) bool {
-	deadline := time.Now().Add(duration)
-
-	for time.Now().Before(deadline) {
-		secret := &corev1.Secret{}
-		err := c.Get(ctx, key, secret)
-		// Ignore NotFound errors (secret doesn't exist yet or was deleted)
-		if err != nil && !apierrors.IsNotFound(err) {
-			return false
-		}
-		// If secret exists and has data, it's not empty
-		if err == nil && len(secret.Data) > 0 {
-			return false
-		}
-		time.Sleep(replicationInterval)
-	}
-
-	return true
+	return testutil.ConsistentlySecretEmpty(ctx, c, key, duration)
 }
 
 // waitForSecretUpdate waits for a secret to have a specific field value
 func waitForSecretUpdate(ctx context.Context, c client.Client, key types.NamespacedName, field string, expectedValue string) (*corev1.Secret, error) {
-	var secret corev1.Secret
-	deadline := time.Now().Add(replicationTimeout)
-
-	for time.Now().Before(deadline) {
-		if err := c.Get(ctx, key, &secret); err != nil {
-			time.Sleep(replicationInterval)
-			continue
-		}
-
-		actualValue, ok := secret.Data[field]
-		if ok && string(actualValue) == expectedValue {
-			return &secret, nil
-		}
-
-		time.Sleep(replicationInterval)
-	}
-
-	// Return whatever we have
-	if err := c.Get(ctx, key, &secret); err != nil {
-		return nil, err
-	}
-	return &secret, fmt.Errorf("timeout waiting for secret update: expected %s=%s", field, expectedValue)
+	return testutil.WaitForSecretData(ctx, c, key, map[string]string{field: expectedValue}, replicationTimeout)
 }
 
 func TestSecretReplication(t *testing.T) {
@@ -390,21 +311,14 @@ func TestSecretReplication(t *testing.T) {
 			t.Fatalf("failed to delete source secret: %v", err)
 		}
 
-		// Wait a bit for any potential cleanup
-		time.Sleep(2 * time.Second)
-
 		// Verify target Secret still exists with snapshot data
-		var snapshotSecret corev1.Secret
-		if err := tc.client.Get(ctx, types.NamespacedName{
+		if _, err := testutil.ConsistentlySecret(ctx, tc.client, types.NamespacedName{
 			Namespace: targetNS.Name,
 			Name:      "snapshot-secret",
-		}, &snapshotSecret); err != nil {
-			t.Fatalf("target secret should still exist (snapshot behavior): %v", err)
-		}
-
-		// Verify data is preserved
-		if string(snapshotSecret.Data["key"]) != "snapshot-value" {
-			t.Errorf("expected snapshot data to be preserved, got %s", string(snapshotSecret.Data["key"]))
+		}, consistentlyDuration, func(s *corev1.Secret) bool {
+			return string(s.Data["key"]) == "snapshot-value"
+		}); err != nil {
+			t.Errorf("target secret should keep the snapshot data: %v", err)
 		}
 	})
 
@@ -790,9 +704,6 @@ func TestFeatureInteractions(t *testing.T) {
 	tcReplicator := setupTestManagerWithReplicator(t, cfg)
 	defer tcReplicator.cancel()
 
-	// Small delay to ensure controllers are ready
-	time.Sleep(500 * time.Millisecond)
-
 	ctx := context.Background()
 
 	t.Run("RejectConflictingAnnotations", func(t *testing.T) {
@@ -863,19 +774,13 @@ func TestFeatureInteractions(t *testing.T) {
 		// The replicator should not interfere with this Secret
 		// (autogenerate is handled by the generator controller, which is not active in this test)
 		// We just verify that the replicator doesn't touch it
-		time.Sleep(2 * time.Second)
-
-		// Fetch the secret and verify replicatable annotation is still there
-		var secret corev1.Secret
-		if err := tcReplicator.client.Get(ctx, types.NamespacedName{
+		if _, err := testutil.ConsistentlySecret(ctx, tcReplicator.client, types.NamespacedName{
 			Namespace: ns.Name,
 			Name:      "combined-secret",
-		}, &secret); err != nil {
-			t.Fatalf("failed to get secret: %v", err)
-		}
-
-		if secret.Annotations[replicator.AnnotationReplicatableFromNamespaces] != "*" {
-			t.Errorf("expected replicatable-from-namespaces annotation to be preserved")
+		}, consistentlyDuration, func(s *corev1.Secret) bool {
+			return s.Annotations[replicator.AnnotationReplicatableFromNamespaces] == "*"
+		}); err != nil {
+			t.Errorf("expected replicatable-from-namespaces annotation to be preserved: %v", err)
 		}
 	})
 }
@@ -1013,25 +918,16 @@ func TestCharacterClassPatternsIntegration(t *testing.T) {
 		}
 		defer tc.client.Delete(ctx, sourceSecret)
 
-		// Wait and verify target was NOT modified
-		time.Sleep(3 * time.Second)
-
-		var unmodifiedTarget corev1.Secret
-		if err := tc.client.Get(ctx, types.NamespacedName{
+		// Verify target was NOT modified: it keeps the original data and gets no
+		// replicated-from annotation
+		if _, err := testutil.ConsistentlySecret(ctx, tc.client, types.NamespacedName{
 			Namespace: targetNS.Name,
 			Name:      "existing-secret",
-		}, &unmodifiedTarget); err != nil {
-			t.Fatalf("failed to get target secret: %v", err)
-		}
-
-		// Should still have original data
-		if string(unmodifiedTarget.Data["original"]) != "original-value" {
-			t.Error("unowned target secret was modified")
-		}
-
-		// Should NOT have replicated-from annotation
-		if unmodifiedTarget.Annotations[replicator.AnnotationReplicatedFrom] != "" {
-			t.Error("unowned target secret should not have replicated-from annotation")
+		}, consistentlyDuration, func(s *corev1.Secret) bool {
+			return string(s.Data["original"]) == "original-value" &&
+				s.Annotations[replicator.AnnotationReplicatedFrom] == ""
+		}); err != nil {
+			t.Errorf("unowned target secret was modified: %v", err)
 		}
 	})
 
@@ -28,7 +28,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/guided-traffic/internal-secrets-operator/pkg/clock"
 	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/testutil"
 )
 
 const (
@@ -37,6 +41,11 @@ const (
 	AnnotationRotatePrefix = AnnotationPrefix + "rotate."
 )
 
+// waitForRotation waits until a field of a secret no longer has the given value
+func waitForRotation(ctx context.Context, c client.Client, key types.NamespacedName, field, oldValue string) (*corev1.Secret, error) {
+	return testutil.WaitForRotation(ctx, c, key, field, oldValue, timeout)
+}
+
 // TestRotationBasic tests basic secret rotation functionality
 func TestRotationBasic(t *testing.T) {
 	tc := setupTestManager(t, nil)
@@ -162,7 +171,8 @@ func TestRotationWithMockedTime(t *testing.T) {
 		},
 	}
 
-	tc := setupTestManagerWithClock(t, customConfig, nil)
+	fakeClock := clock.NewFakeClock(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	tc := setupTestManagerWithClock(t, customConfig, fakeClock)
 	ns := createNamespace(t, tc.client)
 	defer tc.cleanup(t, ns)
 
@@ -197,16 +207,16 @@ func TestRotationWithMockedTime(t *testing.T) {
 			t.Fatal("expected password to be generated")
 		}
 
-		// Verify generated-at timestamp
+		// Verify generated-at timestamp is taken from the clock
 		generatedAt := updatedSecret.Annotations[AnnotationGeneratedAt]
-		if generatedAt == "" {
-			t.Error("expected generated-at annotation to be set")
+		if generatedAt != fakeClock.Now().Format(time.RFC3339) {
+			t.Errorf("expected generated-at %s, got %q", fakeClock.Now().Format(time.RFC3339), generatedAt)
 		}
 	})
 
 	t.Run("RotationNotDueYet", func(t *testing.T) {
 		// Create a secret with a recent generated-at timestamp
-		recentTime := time.Now().Add(-2 * time.Minute) // 2 minutes ago
+		recentTime := fakeClock.Now().Add(-2 * time.Minute) // 2 minutes ago
 
 		secret := &corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
@@ -228,24 +238,18 @@ func TestRotationWithMockedTime(t *testing.T) {
 			t.Fatalf("failed to create secret: %v", err)
 		}
 
-		// Wait a bit for reconciliation
-		time.Sleep(2 * time.Second)
-
-		key := types.NamespacedName{Name: secret.Name, Namespace: ns.Name}
-		var updatedSecret corev1.Secret
-		if err := tc.client.Get(ctx, key, &updatedSecret); err != nil {
-			t.Fatalf("failed to get secret: %v", err)
-		}
-
 		// Password should NOT be rotated (not due yet)
-		if string(updatedSecret.Data["password"]) != "existing-password" {
-			t.Error("password should NOT be rotated as rotation is not due yet")
+		key := types.NamespacedName{Name: secret.Name, Namespace: ns.Name}
+		if _, err := testutil.ConsistentlySecret(ctx, tc.client, key, consistentlyDuration, func(s *corev1.Secret) bool {
+			return string(s.Data["password"]) == "existing-password"
+		}); err != nil {
+			t.Errorf("password should NOT be rotated as rotation is not due yet: %v", err)
 		}
 	})
 
 	t.Run("RotationDue", func(t *testing.T) {
 		// Create a secret with an old generated-at timestamp
-		oldTime := time.Now().Add(-2 * time.Hour) // 2 hours ago
+		oldTime := fakeClock.Now().Add(-2 * time.Hour) // 2 hours ago
 
 		secret := &corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
@@ -267,12 +271,9 @@ func TestRotationWithMockedTime(t *testing.T) {
 			t.Fatalf("failed to create secret: %v", err)
 		}
 
-		// Wait for reconciliation to process the rotation
-		time.Sleep(3 * time.Second)
-
 		key := types.NamespacedName{Name: secret.Name, Namespace: ns.Name}
-		var updatedSecret corev1.Secret
-		if err := tc.client.Get(ctx, key, &updatedSecret); err != nil {
+		updatedSecret, err := waitForRotation(ctx, tc.client, key, "password", "old-password")
+		if err != nil {
 			t.Fatalf("failed to get secret: %v", err)
 		}
 
@@ -290,7 +291,7 @@ func TestRotationWithMockedTime(t *testing.T) {
 
 	t.Run("FieldSpecificRotationDue", func(t *testing.T) {
 		// Create a secret where one field needs rotation and another doesn't
-		oldTime := time.Now().Add(-3 * time.Hour) // 3 hours ago
+		oldTime := fakeClock.Now().Add(-3 * time.Hour) // 3 hours ago
 
 		secret := &corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
@@ -315,12 +316,9 @@ func TestRotationWithMockedTime(t *testing.T) {
 			t.Fatalf("failed to create secret: %v", err)
 		}
 
-		// Wait for reconciliation
-		time.Sleep(3 * time.Second)
-
 		key := types.NamespacedName{Name: secret.Name, Namespace: ns.Name}
-		var updatedSecret corev1.Secret
-		if err := tc.client.Get(ctx, key, &updatedSecret); err != nil {
+		updatedSecret, err := waitForRotation(ctx, tc.client, key, "password", "old-password")
+		if err != nil {
 			t.Fatalf("failed to get secret: %v", err)
 		}
 
@@ -334,6 +332,49 @@ func TestRotationWithMockedTime(t *testing.T) {
 		// generated-at timestamp, so the api-key might be rotated as well in the current
 		// implementation. This test documents the expected behavior.
 	})
+
+	t.Run("RotationAfterClockAdvance", func(t *testing.T) {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-rotation-clock-advance",
+				Namespace: ns.Name,
+				Annotations: map[string]string{
+					AnnotationAutogenerate: "password",
+					AnnotationRotate:       "1h",
+				},
+			},
+			Type: corev1.SecretTypeOpaque,
+		}
+
+		if err := tc.client.Create(ctx, secret); err != nil {
+			t.Fatalf("failed to create secret: %v", err)
+		}
+
+		key := types.NamespacedName{Name: secret.Name, Namespace: ns.Name}
+		generated, err := waitForSecretField(ctx, tc.client, key, "password")
+		if err != nil {
+			t.Fatalf("failed to get secret: %v", err)
+		}
+		initialPassword := string(generated.Data["password"])
+
+		// Move past the rotation interval and trigger a reconciliation
+		fakeClock.Advance(2 * time.Hour)
+		generated.Labels = map[string]string{"touched": "true"}
+		if err := tc.client.Update(ctx, generated); err != nil {
+			t.Fatalf("failed to update secret: %v", err)
+		}
+
+		rotated, err := waitForRotation(ctx, tc.client, key, "password", initialPassword)
+		if err != nil {
+			t.Fatalf("failed to get secret: %v", err)
+		}
+		if string(rotated.Data["password"]) == initialPassword {
+			t.Error("password should be rotated after advancing the clock past the interval")
+		}
+		if rotated.Annotations[AnnotationGeneratedAt] != fakeClock.Now().Format(time.RFC3339) {
+			t.Errorf("expected generated-at to be the advanced clock time, got %q", rotated.Annotations[AnnotationGeneratedAt])
+		}
+	})
 }
 
 // TestRotationWithCustomConfig tests rotation with custom configuration
@@ -385,18 +426,9 @@ func TestRotationWithCustomConfig(t *testing.T) {
 			t.Fatalf("failed to create secret: %v", err)
 		}
 
-		// Wait for reconciliation
-		time.Sleep(3 * time.Second)
-
 		key := types.NamespacedName{Name: secret.Name, Namespace: ns.Name}
-		var updatedSecret corev1.Secret
-		if err := tc.client.Get(ctx, key, &updatedSecret); err != nil {
-			t.Fatalf("failed to get secret: %v", err)
-		}
-
-		// Password should be rotated
-		if string(updatedSecret.Data["password"]) == "old-password" {
-			t.Error("password should be rotated")
+		if _, err := waitForRotation(ctx, tc.client, key, "password", "old-password"); err != nil {
+			t.Fatalf("password should be rotated: %v", err)
 		}
 	})
 }
@@ -559,18 +591,10 @@ func TestRotationPreservesOtherData(t *testing.T) {
 			t.Fatalf("failed to create secret: %v", err)
 		}
 
-		// Wait for reconciliation
-		time.Sleep(3 * time.Second)
-
 		key := types.NamespacedName{Name: secret.Name, Namespace: ns.Name}
-		var updatedSecret corev1.Secret
-		if err := tc.client.Get(ctx, key, &updatedSecret); err != nil {
-			t.Fatalf("failed to get secret: %v", err)
-		}
-
-		// Password should be rotated
-		if string(updatedSecret.Data["password"]) == "old-password" {
-			t.Error("password should be rotated")
+		updatedSecret, err := waitForRotation(ctx, tc.client, key, "password", "old-password")
+		if err != nil {
+			t.Fatalf("password should be rotated: %v", err)
 		}
 
 		// User data should be preserved
@@ -31,6 +31,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/testutil"
 )
 
 const (
@@ -44,58 +45,19 @@ const (
 	AnnotationGeneratedAt  = AnnotationPrefix + "generated-at"
 
 	// Test timeouts
-	timeout  = 10 * time.Second
-	interval = 100 * time.Millisecond
+	timeout = 10 * time.Second
+	// consistentlyDuration is how long the tests check that the operator leaves a Secret alone
+	consistentlyDuration = 3 * time.Second
 )
 
 // waitForSecretField waits for a specific field to be populated in a secret
 func waitForSecretField(ctx context.Context, c client.Client, key types.NamespacedName, field string) (*corev1.Secret, error) {
-	var secret corev1.Secret
-	deadline := time.Now().Add(timeout)
-
-	for time.Now().Before(deadline) {
-		if err := c.Get(ctx, key, &secret); err != nil {
-			time.Sleep(interval)
-			continue
-		}
-
-		if _, ok := secret.Data[field]; ok {
-			return &secret, nil
-		}
-
-		time.Sleep(interval)
-	}
-
-	// Return whatever we have, even if incomplete
-	if err := c.Get(ctx, key, &secret); err != nil {
-		return nil, err
-	}
-	return &secret, nil
+	return ignoreTimeout(testutil.WaitForSecretField(ctx, c, key, field, timeout))
 }
 
 // waitForAnnotation waits for a specific annotation to be set on a secret
 func waitForAnnotation(ctx context.Context, c client.Client, key types.NamespacedName, annotation string) (*corev1.Secret, error) {
-	var secret corev1.Secret
-	deadline := time.Now().Add(timeout)
-
-	for time.Now().Before(deadline) {
-		if err := c.Get(ctx, key, &secret); err != nil {
-			time.Sleep(interval)
-			continue
-		}
-
-		if _, ok := secret.Annotations[annotation]; ok {
-			return &secret, nil
-		}
-
-		time.Sleep(interval)
-	}
-
-	// Return whatever we have
-	if err := c.Get(ctx, key, &secret); err != nil {
-		return nil, err
-	}
-	return &secret, nil
+	return ignoreTimeout(testutil.WaitForAnnotation(ctx, c, key, annotation, timeout))
 }
 
 // TestSecretController runs all secret controller integration tests
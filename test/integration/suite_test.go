@@ -20,36 +20,27 @@ limitations under the License.
 package integration
 
 import (
-	"context"
+	"errors"
 	"os"
 	"path/filepath"
-	"sync/atomic"
 	"testing"
-	"time"
 
 	"go.uber.org/zap/zapcore"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/record"
-	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
-	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	"github.com/guided-traffic/internal-secrets-operator/internal/controller"
 	"github.com/guided-traffic/internal-secrets-operator/pkg/config"
-	"github.com/guided-traffic/internal-secrets-operator/pkg/generator"
+	"github.com/guided-traffic/internal-secrets-operator/pkg/testutil"
 )
 
 var (
 	restConfig *rest.Config
 	testEnv    *envtest.Environment
-
-	// Counter for unique controller names
-	controllerCounter int64
 )
 
 func TestMain(m *testing.M) {
@@ -69,23 +60,13 @@ func TestMain(m *testing.M) {
 		os.Setenv("KUBEBUILDER_ASSETS", kubebuilderAssets)
 	}
 
-	testEnv = &envtest.Environment{
-		ErrorIfCRDPathMissing: false,
-	}
-
 	var err error
-	restConfig, err = testEnv.Start()
+	testEnv, restConfig, err = testutil.StartEnvironment(filepath.Join(getProjectRoot(), "config", "crd", "bases"))
 	if err != nil {
 		logf.Log.Error(err, "failed to start test environment")
 		os.Exit(1)
 	}
 
-	err = corev1.AddToScheme(scheme.Scheme)
-	if err != nil {
-		logf.Log.Error(err, "failed to add corev1 to scheme")
-		os.Exit(1)
-	}
-
 	// Run tests
 	code := m.Run()
 
@@ -107,192 +88,57 @@ func TestMain(m *testing.M) {
 // testContext holds test dependencies
 type testContext struct {
 	client client.Client
-	cancel context.CancelFunc
-}
-
-// MockClock is a mock implementation of Clock for testing
-type MockClock struct {
-	currentTime time.Time
-}
-
-// Now returns the mocked current time
-func (m *MockClock) Now() time.Time {
-	return m.currentTime
-}
-
-// SetTime sets the mocked current time
-func (m *MockClock) SetTime(t time.Time) {
-	m.currentTime = t
-}
-
-// Advance advances the mocked time by the given duration
-func (m *MockClock) Advance(d time.Duration) {
-	m.currentTime = m.currentTime.Add(d)
+	cancel func()
 }
 
-// setupTestManager creates a manager with unique controller name for test isolation
+// setupTestManager creates a manager running the secret generator
 func setupTestManager(t *testing.T, operatorConfig *config.Config) *testContext {
 	return setupTestManagerWithClock(t, operatorConfig, nil)
 }
 
-// setupTestManagerWithClock creates a manager with an optional mock clock
+// setupTestManagerWithClock creates a manager with an optional clock (e.g. a clock.FakeClock)
 func setupTestManagerWithClock(t *testing.T, operatorConfig *config.Config, clock controller.Clock) *testContext {
 	t.Helper()
 
-	// Disable metrics server to avoid port conflicts
-	metricsAddr := "0"
-
-	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
-		Scheme: scheme.Scheme,
-		Metrics: metricsserver.Options{
-			BindAddress: metricsAddr,
-		},
+	mgr := testutil.StartManager(t, restConfig, testutil.Options{
+		Config:    operatorConfig,
+		Clock:     clock,
+		Generator: true,
 	})
-	if err != nil {
-		t.Fatalf("failed to create manager: %v", err)
-	}
-
-	// Create event recorder
-	eventBroadcaster := record.NewBroadcaster()
-	eventRecorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "secret-operator"})
-
-	if operatorConfig == nil {
-		operatorConfig = config.NewDefaultConfig()
-	}
-
-	// Create generator with charset from config
-	charset := operatorConfig.Defaults.String.BuildCharset()
-	gen := generator.NewSecretGeneratorWithCharset(charset)
-
-	reconciler := &controller.SecretReconciler{
-		Client:        mgr.GetClient(),
-		Scheme:        mgr.GetScheme(),
-		Generator:     gen,
-		Config:        operatorConfig,
-		EventRecorder: eventRecorder,
-		Clock:         clock,
-	}
-
-	// Use unique controller name using atomic counter
-	counter := atomic.AddInt64(&controllerCounter, 1)
-	controllerName := "secret-controller-" + time.Now().Format("150405") + "-" + string(rune('a'+counter%26))
-
-	err = ctrl.NewControllerManagedBy(mgr).
-		Named(controllerName).
-		For(&corev1.Secret{}).
-		Complete(reconciler)
-	if err != nil {
-		t.Fatalf("failed to setup controller: %v", err)
-	}
-
-	ctx, cancel := context.WithCancel(context.Background())
-	go func() {
-		if err := mgr.Start(ctx); err != nil {
-			t.Logf("manager stopped: %v", err)
-		}
-	}()
-
-	// Wait for manager and cache to be ready
-	time.Sleep(500 * time.Millisecond)
-
-	return &testContext{
-		client: mgr.GetClient(),
-		cancel: cancel,
-	}
+	return &testContext{client: mgr.Client, cancel: mgr.Stop}
 }
 
 // cleanup stops the manager and removes namespace
 func (tc *testContext) cleanup(t *testing.T, ns *corev1.Namespace) {
 	t.Helper()
 
-	// Cancel context to stop manager
+	// Stop the manager; the namespace is deleted by testutil.CreateNamespace when the test ends
 	tc.cancel()
-
-	// Delete namespace
-	if ns != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		_ = tc.client.Delete(ctx, ns)
-	}
 }
 
 // createNamespace creates a unique namespace for test isolation
 func createNamespace(t *testing.T, c client.Client) *corev1.Namespace {
-	t.Helper()
-
-	ns := &corev1.Namespace{
-		ObjectMeta: ctrl.ObjectMeta{
-			GenerateName: "test-",
-		},
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	if err := c.Create(ctx, ns); err != nil {
-		t.Fatalf("failed to create namespace: %v", err)
-	}
-
-	return ns
+	return testutil.CreateNamespace(t, c)
 }
 
-// setupTestManagerWithReplicator creates a manager with SecretReplicatorReconciler
+// setupTestManagerWithReplicator creates a manager running the secret replicator
 func setupTestManagerWithReplicator(t *testing.T, operatorConfig *config.Config) *testContext {
 	t.Helper()
 
-	// Disable metrics server to avoid port conflicts
-	metricsAddr := "0"
-
-	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
-		Scheme: scheme.Scheme,
-		Metrics: metricsserver.Options{
-			BindAddress: metricsAddr,
-		},
+	mgr := testutil.StartManager(t, restConfig, testutil.Options{
+		Config:     operatorConfig,
+		Replicator: true,
 	})
-	if err != nil {
-		t.Fatalf("failed to create manager: %v", err)
-	}
-
-	// Create event recorder
-	eventBroadcaster := record.NewBroadcaster()
-	eventRecorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "secret-replicator"})
-
-	if operatorConfig == nil {
-		operatorConfig = config.NewDefaultConfig()
-	}
-
-	// Setup SecretReplicatorReconciler
-	replicatorReconciler := &controller.SecretReplicatorReconciler{
-		Client:        mgr.GetClient(),
-		Scheme:        mgr.GetScheme(),
-		Config:        operatorConfig,
-		EventRecorder: eventRecorder,
-	}
-
-	// Use unique controller name to avoid conflicts in tests
-	counter := atomic.AddInt64(&controllerCounter, 1)
-	controllerName := "secret-replicator-" + time.Now().Format("150405") + "-" + string(rune('a'+counter%26))
-
-	// Use the proper SetupWithManagerAndName to ensure all watches are configured correctly
-	err = replicatorReconciler.SetupWithManagerAndName(mgr, controllerName)
-	if err != nil {
-		t.Fatalf("failed to setup replicator controller: %v", err)
-	}
-
-	ctx, cancel := context.WithCancel(context.Background())
-	go func() {
-		if err := mgr.Start(ctx); err != nil {
-			t.Logf("manager stopped: %v", err)
-		}
-	}()
-
-	// Wait for manager and cache to be ready
-	time.Sleep(500 * time.Millisecond)
+	return &testContext{client: mgr.Client, cancel: mgr.Stop}
+}
 
-	return &testContext{
-		client: mgr.GetClient(),
-		cancel: cancel,
+// ignoreTimeout returns the last state of a Secret the wait helpers return on timeout without
+// an error, so that the tests report which values are missing
+func ignoreTimeout(secret *corev1.Secret, err error) (*corev1.Secret, error) {
+	if errors.Is(err, testutil.ErrTimeout) {
+		return secret, nil
 	}
+	return secret, err
 }
 
 // getProjectRoot returns the project root directory